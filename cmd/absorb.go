@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	cmdutil "github.com/orirawlings/gh-biome/internal/util/command"
+
+	"github.com/spf13/cobra"
+)
+
+var absorbShared bool
+
+func init() {
+	absorbCmd.Flags().BoolVar(&absorbShared, "shared", false, "Share the other biome's objects through git alternates rather than copying them. The biome then depends on the other biome's objects.")
+	rootCmd.AddCommand(absorbCmd)
+}
+
+var absorbCmd = &cobra.Command{
+	Use:   "absorb <other-biome-path>",
+	Short: "Merge another biome into this biome",
+	Long: `
+Import the owners, authenticated users, remotes, references, and objects of
+another biome into this biome, so separately grown biomes can be
+consolidated. Owners, authenticated users, and remotes that are in both
+biomes are kept as they are in this biome, and their references are
+refreshed by the next 'biome fetch'.
+
+Objects are copied from the other biome with a local fetch, which needs no
+network access. With --shared, the other biome's object store is added to
+this biome's git alternates instead, so no objects are copied, but the other
+biome must not be removed or pruned while this biome uses it.
+
+Remotes that were evicted from the other biome are not absorbed. Restore
+them in the other biome first with 'biome restore'.
+`,
+	Example: `biome absorb ~/biomes/kubernetes-sigs
+
+biome absorb --shared /mnt/shared/biome
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+		path := args[0]
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(directory, path)
+		}
+		absorption, err := b.Absorb(ctx, path, absorbShared)
+		if err != nil {
+			return err
+		}
+		for _, owner := range absorption.Owners {
+			cmdutil.Println(cmd, fmt.Sprintf("Absorbed owner %s", owner))
+		}
+		for _, viewer := range absorption.Viewers {
+			cmdutil.Println(cmd, fmt.Sprintf("Absorbed authenticated user of %s", viewer.Host()))
+		}
+		cmdutil.Println(cmd, fmt.Sprintf("Absorbed %d remotes (%d refs) from %s", len(absorption.Remotes), absorption.Refs, path))
+		return nil
+	},
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+)
+
+func init() {
+	absorbCmd.SetContext(context.Background())
+	pushInContext(absorbCmd)
+}
+
+func TestAbsorbCmd_Execute(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	t.Cleanup(resetDirectory)
+
+	// grow another biome separately
+	other := t.TempDir()
+	if _, err := biome.Init(context.Background(), other, biomeOptions...); err != nil {
+		t.Fatalf("unexpected error initializing another biome: %v", err)
+	}
+	rootCmd.SetArgs([]string{
+		"-C", other,
+		"add",
+		"--skip-fetch",
+		github_com_cli.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	resetDirectory()
+
+	rootCmd.SetArgs([]string{"absorb", other})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	b, err := biome.Load(context.Background(), ".")
+	if err != nil {
+		t.Fatalf("unexpected error loading biome: %v", err)
+	}
+	owners, err := b.Owners(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error listing owners: %v", err)
+	}
+	if !slices.Contains(owners, github_com_cli) {
+		t.Errorf("expected %s to be absorbed, owners were %v", github_com_cli, owners)
+	}
+
+	rootCmd.SetArgs([]string{"absorb", t.TempDir()})
+	if err := rootCmd.Execute(); err == nil {
+		t.Errorf("expected error absorbing a directory that is not a biome")
+	}
+}
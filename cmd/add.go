@@ -1,15 +1,26 @@
 package cmd
 
 import (
+	"errors"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+
 	"github.com/spf13/cobra"
 )
 
 var (
-	skipFetch bool
+	skipFetch    bool
+	mine         string
+	affiliations []string
+	createdAfter string
 )
 
 func init() {
 	addCmd.Flags().BoolVar(&skipFetch, "skip-fetch", false, "Do not automatically fetch git references and objects from the owners' repositories.")
+	addCmd.Flags().StringVar(&mine, "mine", "", "Add all repositories that the authenticated user can access on the given GitHub host.")
+	addCmd.Flags().Lookup("mine").NoOptDefVal = "github.com"
+	addCmd.Flags().StringSliceVar(&affiliations, "affiliation", nil, "Limit --mine to repositories with the given affiliations to the authenticated user: owner, collaborator, organization_member. (default all)")
+	addCmd.Flags().StringVar(&createdAfter, "created-after", "", "Only add the owners' repositories created on or after the given date, ex. 2023-01-01.")
 	rootCmd.AddCommand(addCmd)
 }
 
@@ -39,6 +50,24 @@ of the owner.
 
 	<host>/<owner-name>/<repo-name>
 
+With --mine, every repository that the authenticated user can access on the
+given GitHub host (default "github.com") is added instead, regardless of which
+owner it belongs to. Use --affiliation to limit this to repositories the user
+owns, collaborates on, or can access through organization membership. The
+authenticated user's repositories are rediscovered each time remotes are
+updated.
+
+With --created-after, only the owners' repositories created on or after the
+given date are added, ex. to scope the biome to the era relevant to an
+investigation. The date is recorded as each owner's createdAfter setting, so
+it applies each time remotes are updated, and repositories created before it
+are removed from the biome. Add an owner again with another date to change it,
+or unset the setting with 'biome config unset biome.owner.<owner>.createdAfter'.
+
+gh does not need to be logged in to add public owners. If it is not logged in
+to an owner's GitHub server, only the owner's public repositories are added,
+and they are fetched anonymously. --mine always requires gh to be logged in.
+
 Run 'git remote' to show a listing of all remotes added to the biome.
 `,
 	Example: `biome add orirawlings
@@ -48,11 +77,23 @@ biome add github.com/orirawlings
 biome add https://github.com/orirawlings
 
 biome add github.com/orirawlings github.com/git github.com/cli
+
+biome add --created-after 2023-01-01 github.com/kubernetes
+
+biome add --mine
+
+biome add --mine=my.github.biz --affiliation collaborator,organization_member
 `,
 	Args: cobra.MatchAll(
-		cobra.MinimumNArgs(1),
+		func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().Changed("mine") {
+				return nil
+			}
+			return cobra.MinimumNArgs(1)(cmd, args)
+		},
 		validOwnerRefs,
 	),
+	ValidArgsFunction: completeOwners,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
 		b, err := load(ctx)
@@ -64,23 +105,62 @@ biome add github.com/orirawlings github.com/git github.com/cli
 		if err != nil {
 			return err
 		}
+		if createdAfter != "" {
+			if len(owners) == 0 {
+				return errors.New("--created-after requires owners")
+			}
+			s, err := biome.LookupSetting(biome.OwnerSettingKey(owners[0], "createdAfter"))
+			if err != nil {
+				return err
+			}
+			if err := s.Validate(createdAfter); err != nil {
+				return err
+			}
+		}
 		for _, owner := range owners {
 			cmd.PrintErrf("Adding %s...\n", owner)
 		}
+		groups := remoteGroups(owners)
 
 		// record owners in git config if not already present
-		if err := b.AddOwners(ctx, owners); err != nil {
+		if len(owners) > 0 {
+			if err := b.AddOwners(ctx, owners); err != nil {
+				return err
+			}
+		}
+		if createdAfter != "" {
+			for _, owner := range owners {
+				if err := b.SetOwnerSetting(ctx, owner, "createdAfter", createdAfter); err != nil {
+					return err
+				}
+			}
+		}
+
+		// record the authenticated user's affiliations in git config
+		if cmd.Flags().Changed("mine") {
+			viewer, err := parseViewer(mine, affiliations)
+			if err != nil {
+				return err
+			}
+			cmd.PrintErrf("Adding repositories accessible to the authenticated user on %s...\n", viewer.Host())
+			if err := b.AddViewers(ctx, []biome.Viewer{viewer}); err != nil {
+				return err
+			}
+			groups = append(groups, viewer.RemoteGroup())
+		}
+
+		if err := warnAnonymous(ctx, cmd, b); err != nil {
 			return err
 		}
 
 		// update git remote configurations for all owners
-		if err := b.UpdateRemotes(ctx); err != nil {
+		if err := updateRemotes(cmd, b); err != nil {
 			return err
 		}
 
 		// fetch remotes
 		if !skipFetch {
-			if err := fetch(ctx, cmd, b, owners); err != nil {
+			if err := fetch(ctx, cmd, b, groups); err != nil {
 				return err
 			}
 		}
@@ -2,7 +2,13 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
+
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+
+	"gopkg.in/h2non/gock.v1"
 )
 
 func init() {
@@ -41,4 +47,59 @@ func TestAddCmd_Execute(t *testing.T) {
 			t.Fatalf("unexpected error executing command: %v", err)
 		}
 	})
+
+	t.Run("--mine", func(t *testing.T) {
+		initBiome(t)
+		stubGitHub(t)
+		t.Cleanup(func() {
+			addCmd.Flags().Lookup("mine").Changed = false
+			mine = ""
+		})
+		rootCmd.SetArgs([]string{
+			"add",
+			"--skip-fetch",
+			"--mine",
+		})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error executing command: %v", err)
+		}
+	})
+	t.Run("--created-after", func(t *testing.T) {
+		initBiome(t)
+		stubGitHub(t)
+		t.Cleanup(func() {
+			createdAfter = ""
+		})
+		gock.New("https://api.github.com").
+			Post("/graphql").
+			BodyString(fmt.Sprintf(`{"query":"query OwnerRepositories($createdAt:Boolean!$endCursor:String$owner:String!$parent:Boolean!$visibility:Boolean!){repositoryOwner(login: $owner){repositories(first: 100, after: $endCursor, affiliations: [OWNER]){nodes{isDisabled,isArchived,isLocked,url,defaultBranchRef{name,prefix},visibility @include(if: $visibility),parent @include(if: $parent){url},createdAt @include(if: $createdAt)},pageInfo{hasNextPage,endCursor}}}}","variables":{"createdAt":true,"endCursor":null,"owner":%q,"parent":true,"visibility":true}}`, github_com_cli.Name())).
+			Reply(200).
+			JSON(`{"data":{"repositoryOwner":{"repositories":{"nodes":[{"url":"https://github.com/cli/cli","defaultBranchRef":{"name":"trunk","prefix":"refs/heads/"},"createdAt":"2019-10-03T19:12:38Z"}],"pageInfo":{"hasNextPage":false}}}}}`)
+
+		rootCmd.SetArgs([]string{
+			"add",
+			"--skip-fetch",
+			"--created-after", "last year",
+			github_com_cli.String(),
+		})
+		if err := rootCmd.Execute(); err == nil {
+			t.Fatalf("expected error adding owner with an invalid date")
+		}
+
+		rootCmd.SetArgs([]string{
+			"add",
+			"--skip-fetch",
+			"--created-after", "2023-01-01",
+			github_com_cli.String(),
+		})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error executing command: %v", err)
+		}
+		if value := strings.TrimSpace(testutil.Execute(t, "git", "config", "get", "biome.owner.github.com/cli.createdAfter")); value != "2023-01-01" {
+			t.Errorf("expected createdAfter setting of 2023-01-01, was %q", value)
+		}
+		if remotes := strings.TrimSpace(testutil.Execute(t, "git", "remote")); remotes != "" {
+			t.Errorf("expected repositories created before 2023-01-01 not to be added, was %q", remotes)
+		}
+	})
 }
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+
+	"github.com/spf13/cobra"
+)
+
+var adoptRemote string
+
+func init() {
+	adoptCmd.Flags().StringVar(&adoptRemote, "remote", "origin", "The git remote the repository was cloned from, whose URL identifies the repository.")
+	rootCmd.AddCommand(adoptCmd)
+}
+
+var adoptCmd = &cobra.Command{
+	Use:   "adopt [<directory>]",
+	Short: "Convert an existing bare mirror or clone into a git biome",
+	Long: `
+Convert an existing bare repository, ex. one created with 'git clone --mirror'
+or 'git clone --bare', into a git biome, without fetching it again.
+
+The repository is identified by the URL of the remote it was cloned from,
+given by --remote. All of its references are moved into the remote's
+namespace in the biome, ex. refs/heads/main becomes
+refs/remotes/github.com/<owner>/<repo>/heads/main. The cloned-from remote is
+replaced by the biome's remote for the repository, and the repository's
+owner is added to the biome, so the owner's other repositories are added by
+the next 'biome fetch' or 'biome sync-config'.
+
+Non-bare repositories cannot be adopted, use 'biome init' to make them
+biomes instead. Without a directory, the repository in the -C directory, or
+in GH_BIOME_DIR if set, or else in the current directory is adopted.
+`,
+	Example: `git clone --mirror https://github.com/cli/cli.git
+biome adopt cli.git`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := biomeDirectory()
+		if len(args) > 0 {
+			path = args[0]
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(directory, path)
+			}
+		}
+		_, remote, err := biome.Adopt(cmd.Context(), path, adoptRemote, biomeOptions...)
+		if err != nil {
+			return fmt.Errorf("failed to adopt repository: %w", err)
+		}
+		_, err = fmt.Fprintf(cmd.OutOrStderr(), "adopted %s into git biome in %s\n", remote.Name, path)
+		return err
+	},
+}
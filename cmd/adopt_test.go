@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestAdoptCmd_Execute(t *testing.T) {
+	t.Cleanup(resetDirectory)
+
+	// override biome options
+	oldOptions := biomeOptions
+	biomeOptions = []biome.BiomeOption{
+		biome.EditorOptions(config.HelperCommand(fmt.Sprintf("%s config-edit-helper", biomeBuildPath))),
+	}
+	t.Cleanup(func() {
+		biomeOptions = oldOptions
+	})
+
+	dir := t.TempDir()
+	testutil.Execute(t, "git", "init", "--quiet", "--bare", filepath.Join(dir, "bar.git"))
+	testutil.Execute(t, "git", "-C", filepath.Join(dir, "bar.git"), "remote", "add", "upstream", "https://github.com/orirawlings/bar.git")
+
+	rootCmd.SetArgs([]string{
+		"-C", dir,
+		"adopt",
+		"bar.git",
+	})
+	if err := rootCmd.Execute(); err == nil {
+		t.Errorf("expected error adopting a repository without an origin remote")
+	}
+
+	t.Cleanup(func() {
+		adoptRemote = "origin"
+	})
+	rootCmd.SetArgs([]string{
+		"-C", dir,
+		"adopt",
+		"--remote", "upstream",
+		"bar.git",
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	if _, err := biome.Load(context.Background(), filepath.Join(dir, "bar.git")); err != nil {
+		t.Errorf("expected the repository to be adopted as a biome: %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"errors"
+
+	cmdutil "github.com/orirawlings/gh-biome/internal/util/command"
+	"github.com/spf13/cobra"
+)
+
+var annotateRemove bool
+
+func init() {
+	annotateCmd.Flags().BoolVar(&annotateRemove, "remove", false, "Remove the remote's note.")
+	rootCmd.AddCommand(annotateCmd)
+}
+
+var annotateCmd = &cobra.Command{
+	Use:   "annotate <remote> [<note>]",
+	Short: "Attach a freeform note to a remote of the git biome",
+	Long: `
+Attach a freeform note to a remote of the git biome, ex. the team that owns
+it, or why it is kept, so that tribal knowledge travels with the biome.
+Annotating a remote replaces its previous note. Without a note, print the
+remote's note. Use --remove to remove it.
+
+Notes are stored in the biome's git config, as the
+biome.remote.<remote>.note setting, and are kept when remotes are updated.
+They are shown by 'biome remotes --long' and 'biome remotes --json'.
+`,
+	Example: `biome annotate github.com/cli/cli "owned by platform team"
+
+biome annotate github.com/cli/cli
+
+biome annotate --remove github.com/cli/cli
+`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if annotateRemove && len(args) == 2 {
+			return errors.New("--remove cannot be combined with a note")
+		}
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+		remote := args[0]
+		switch {
+		case annotateRemove:
+			return b.Annotate(ctx, remote, "")
+		case len(args) == 2:
+			return b.Annotate(ctx, remote, args[1])
+		}
+
+		notes, err := b.RemoteNotes(ctx)
+		if err != nil {
+			return err
+		}
+		if note, ok := notes[remote]; ok {
+			cmdutil.Println(cmd, note)
+		}
+		return nil
+	},
+}
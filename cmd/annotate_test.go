@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func init() {
+	annotateCmd.SetContext(context.Background())
+	pushInContext(annotateCmd)
+}
+
+func TestAnnotateCmd_Execute(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_orirawlings.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	t.Cleanup(func() {
+		annotateRemove = false
+	})
+
+	rootCmd.SetArgs([]string{"annotate", "github.com/orirawlings/bar", "owned by platform team"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	rootCmd.SetArgs([]string{"annotate", "github.com/orirawlings/missing", "no such remote"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("expected error annotating a remote that is not in the biome")
+	}
+
+	for _, run := range []struct {
+		args     []string
+		expected []string
+	}{
+		{
+			args:     []string{"annotate", "github.com/orirawlings/bar"},
+			expected: []string{"owned by platform team"},
+		},
+		{
+			args: []string{"remotes", "--long"},
+			expected: []string{
+				"github.com/orirawlings/bar owned by platform team",
+				"github.com/orirawlings/headless",
+			},
+		},
+		{
+			args: []string{"remotes", "--long", "--format", "csv"},
+			expected: []string{
+				"remote,archived,disabled,locked,internal,evicted,upstream,note",
+				"github.com/orirawlings/bar,false,false,false,false,false,github.com/cli/cli,owned by platform team",
+				"github.com/orirawlings/headless,false,false,false,false,false,,",
+			},
+		},
+		{
+			args: []string{"remotes", "--json"},
+			expected: []string{
+				`[`,
+				`  {`,
+				`    "name": "github.com/orirawlings/bar",`,
+				`    "archived": false,`,
+				`    "disabled": false,`,
+				`    "locked": false,`,
+				`    "internal": false,`,
+				`    "evicted": false,`,
+				`    "upstream": "github.com/cli/cli",`,
+				`    "note": "owned by platform team"`,
+				`  },`,
+				`  {`,
+				`    "name": "github.com/orirawlings/headless",`,
+				`    "archived": false,`,
+				`    "disabled": false,`,
+				`    "locked": false,`,
+				`    "internal": false,`,
+				`    "evicted": false`,
+				`  }`,
+				`]`,
+			},
+		},
+	} {
+		t.Run(strings.Join(run.args, " "), func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			rootCmd.SetOut(buf)
+			t.Cleanup(func() {
+				rootCmd.SetOut(nil)
+				remotesLong = false
+				remotesJSON = false
+				remotesFormat = tableFormat
+			})
+			rootCmd.SetArgs(run.args)
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("unexpected error executing command: %v", err)
+			}
+			expected := strings.Join(run.expected, "\n") + "\n"
+			if buf.String() != expected {
+				t.Errorf("expected %q, got %q", expected, buf.String())
+			}
+		})
+	}
+
+	rootCmd.SetArgs([]string{"annotate", "--remove", "github.com/orirawlings/bar"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	buf := new(bytes.Buffer)
+	rootCmd.SetOut(buf)
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+	})
+	rootCmd.SetArgs([]string{"annotate", "github.com/orirawlings/bar"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	if buf.String() != "" {
+		t.Errorf("expected no note after removing it, got %q", buf.String())
+	}
+}
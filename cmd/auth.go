@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	cmdutil "github.com/orirawlings/gh-biome/internal/util/command"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	authCmd.AddCommand(authCheckCmd)
+	rootCmd.AddCommand(authCmd)
+}
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Inspect how the git biome authenticates to GitHub",
+	Long: `
+Inspect how the git biome authenticates to the GitHub servers of its owners
+and viewers. biome uses the tokens stored by gh, ex. by 'gh auth login'.
+`,
+}
+
+var authCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Verify that stored tokens can read the repositories of the biome's owners",
+	Long: `
+Verify, for each GitHub server referenced by the git biome, that the token
+stored by gh can list the biome's owners and read their repositories. Run it
+before a long operation, such as a fetch, so that it does not fail halfway
+through.
+
+Tokens that are missing OAuth scopes, ex. repo for private repositories or
+read:org for repositories of organizations, are reported as warnings. The
+scopes of tokens that do not report them, ex. fine-grained personal access
+tokens, are not checked.
+
+The command fails if any owner's repositories cannot be read.
+`,
+	Example: `biome auth check
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		reports, err := b.CheckAuth(ctx)
+		if err != nil {
+			return err
+		}
+		var failed int
+		for _, r := range reports {
+			switch {
+			case r.Login == "":
+				cmdutil.Println(cmd, fmt.Sprintf("%s: not authenticated", r.Host))
+			case r.Scopes == nil:
+				cmdutil.Println(cmd, fmt.Sprintf("%s: logged in as %s (scopes not reported)", r.Host, r.Login))
+			default:
+				cmdutil.Println(cmd, fmt.Sprintf("%s: logged in as %s (scopes: %s)", r.Host, r.Login, strings.Join(r.Scopes, ", ")))
+			}
+			for _, w := range r.Warnings {
+				cmdutil.Println(cmd, fmt.Sprintf("  warning: %s", w))
+			}
+			for _, e := range r.Errors {
+				cmdutil.Println(cmd, fmt.Sprintf("  error: %s", e))
+			}
+			if !r.OK() {
+				failed++
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("authentication check failed for %d of %d hosts", failed, len(reports))
+		}
+		return nil
+	},
+}
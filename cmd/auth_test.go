@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"gopkg.in/h2non/gock.v1"
+)
+
+func init() {
+	authCmd.SetContext(context.Background())
+	pushInContext(authCmd)
+}
+
+func TestAuthCheckCmd_Execute(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_cli.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+
+	gock.New("https://api.github.com").
+		Get("/user").
+		Persist().
+		Reply(200).
+		SetHeader("X-OAuth-Scopes", "read:org, repo").
+		JSON(`{"login":"user1"}`)
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(fmt.Sprintf(`{"query":"query OwnerAccess($owner:String!){repositoryOwner(login: $owner){__typename,repositories{totalCount}}}","variables":{"owner":%q}}`, github_com_cli.Name())).
+		Reply(200).
+		JSON(`{"data":{"repositoryOwner":{"__typename":"Organization","repositories":{"totalCount":1}}}}`)
+
+	buf := new(bytes.Buffer)
+	authCheckCmd.SetOut(buf)
+	t.Cleanup(func() {
+		authCheckCmd.SetOut(nil)
+	})
+	rootCmd.SetArgs([]string{"auth", "check"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	if expected := "github.com: logged in as user1 (scopes: read:org, repo)\n"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	authorsSince      string
+	authorsCommitters bool
+	authorsJSON       bool
+	authorsFormat     outputFormat
+	authorsOptions    = newRemoteCategoryOptions(true)
+)
+
+func init() {
+	authorsCmd.Flags().StringVar(&authorsSince, "since", "", "Only count commits committed since this git date, ex. 1.month.ago, or this long ago, ex. 90d, 12w, or 1y.")
+	authorsCmd.Flags().BoolVar(&authorsCommitters, "committers", false, "Count the committers of commits rather than their authors.")
+	authorsCmd.Flags().BoolVar(&authorsJSON, "json", false, "Print the census as a JSON array, with the number of commits on each remote.")
+	addFormatFlag(authorsCmd.Flags(), &authorsFormat)
+	authorsOptions.AddFlags(authorsCmd.Flags())
+	rootCmd.AddCommand(authorsCmd)
+}
+
+// sinceShorthand matches durations like 90d, which are not git dates.
+var sinceShorthand = regexp.MustCompile(`^([0-9]+)([hdwmy])$`)
+
+// gitDate converts a duration shorthand, ex. 90d, into the git date that is
+// that long ago, ex. 90.days.ago. Other values are assumed to be git dates.
+func gitDate(value string) string {
+	m := sinceShorthand.FindStringSubmatch(value)
+	if m == nil {
+		return value
+	}
+	unit := map[string]string{
+		"h": "hours",
+		"d": "days",
+		"w": "weeks",
+		"m": "months",
+		"y": "years",
+	}[m[2]]
+	return m[1] + "." + unit + ".ago"
+}
+
+// sinceTime returns the time of a git date, or of a duration shorthand, see
+// [gitDate], as git interprets it for the biome at path. The time must be in
+// the past. Git interprets dates it does not understand as the current time,
+// so they are rejected too.
+func sinceTime(ctx context.Context, path, value string) (time.Time, error) {
+	now := time.Now()
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "-C", path, "rev-parse", "--since="+gitDate(value))
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not %q: %w: %s", cmd, err, stderr.String())
+	}
+	seconds, ok := strings.CutPrefix(strings.TrimSpace(string(out)), "--max-age=")
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid --since: %s", value)
+	}
+	unix, err := strconv.ParseInt(seconds, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since: %s: %w", value, err)
+	}
+	if unix >= now.Unix() {
+		return time.Time{}, fmt.Errorf("invalid --since: %s: must be in the past", value)
+	}
+	return time.Unix(unix, 0), nil
+}
+
+var authorsCmd = &cobra.Command{
+	Use:   "authors",
+	Short: "List the distinct commit authors across the biome's remotes",
+	Long: `
+List the distinct authors of the commits on the default branch of each
+fetched remote, ex. for license, CLA, or staffing analyses. Each author is
+listed with the number of remotes they committed to and their number of
+commits, most first. Commits shared by remotes, ex. forks, count toward each
+remote. Authors are identified by email address, after git's mailmap is
+applied.
+
+With --committers, the committers of commits are listed rather than their
+authors. With --json, the number of each author's commits on each remote is
+printed too.
+
+By default, only active remotes are included. Use the category flags to
+select other remotes.
+`,
+	Example: `biome authors --since 90d
+
+biome authors --committers --since 2025-01-01 --json
+
+biome authors --since 1y --format csv
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		contributors, err := b.Contributors(ctx, gitDate(authorsSince), authorsCommitters, authorsOptions.Categories()...)
+		if err != nil {
+			return err
+		}
+
+		if authorsJSON {
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			if contributors == nil {
+				return enc.Encode([]any{})
+			}
+			return enc.Encode(contributors)
+		}
+
+		heading := "author"
+		if authorsCommitters {
+			heading = "committer"
+		}
+		if authorsFormat == csvFormat {
+			w := newReportWriter(cmd, authorsFormat, "name", "email", "remotes", "commits")
+			for _, c := range contributors {
+				w.Row(c.Name, c.Email, strconv.Itoa(len(c.Remotes)), strconv.Itoa(c.Commits()))
+			}
+			return w.Flush()
+		}
+		w := newReportWriter(cmd, authorsFormat, heading, "remotes", "commits")
+		for _, c := range contributors {
+			w.Row(fmt.Sprintf("%s <%s>", c.Name, c.Email), strconv.Itoa(len(c.Remotes)), strconv.Itoa(c.Commits()))
+		}
+		return w.Flush()
+	},
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+)
+
+func init() {
+	authorsCmd.SetContext(context.Background())
+	pushInContext(authorsCmd)
+}
+
+func TestGitDate(t *testing.T) {
+	for value, expected := range map[string]string{
+		"":            "",
+		"90d":         "90.days.ago",
+		"12w":         "12.weeks.ago",
+		"6m":          "6.months.ago",
+		"1y":          "1.years.ago",
+		"36h":         "36.hours.ago",
+		"1.month.ago": "1.month.ago",
+		"2025-01-01":  "2025-01-01",
+	} {
+		if date := gitDate(value); date != expected {
+			t.Errorf("unexpected git date for %q: wanted %q, was %q", value, expected, date)
+		}
+	}
+}
+
+func TestAuthorsCmd_Args(t *testing.T) {
+	t.Cleanup(authorsOptions.Reset)
+	rootCmd.SetArgs([]string{"authors", "github.com/cli/cli"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("expected error executing authors with arguments")
+	}
+}
@@ -2,13 +2,59 @@ package cmd
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
 
 	"github.com/orirawlings/gh-biome/internal/biome"
+	"github.com/orirawlings/gh-biome/internal/config"
 )
 
 // biomeOptions can be overridden during tests
-var biomeOptions []biome.BiomeOption
+var biomeOptions = defaultBiomeOptions()
 
+// defaultBiomeOptions configures the biome from the environment. Setting
+// GH_BIOME_CONFIG_EDITOR=direct edits the biome's git config file directly,
+// rather than through 'git config edit' and the config-edit-helper command.
+func defaultBiomeOptions() []biome.BiomeOption {
+	var opts []biome.BiomeOption
+	if os.Getenv("GH_BIOME_CONFIG_EDITOR") == "direct" {
+		opts = append(opts, biome.EditorOptions(config.Direct()))
+	}
+	return opts
+}
+
+// biomeDirectory returns the directory of the biome that commands run
+// against: the -C directory, if given, otherwise GH_BIOME_DIR, if set,
+// otherwise the current working directory.
+func biomeDirectory() string {
+	if rootCmd.PersistentFlags().Changed("directory") {
+		return directory
+	}
+	if dir := os.Getenv("GH_BIOME_DIR"); dir != "" {
+		return dir
+	}
+	return directory
+}
+
+// load discovers the biome that commands run against. A repository
+// configured by the deprecated add-remotes command is upgraded first if
+// --migrate was given, otherwise the error explains how to upgrade it.
 func load(ctx context.Context) (biome.Biome, error) {
-	return biome.Load(ctx, ".", biomeOptions...)
+	b, err := biome.Discover(ctx, biomeDirectory(), biomeOptions...)
+	var needsMigration *biome.NeedsMigrationError
+	if !errors.As(err, &needsMigration) {
+		return b, err
+	}
+	if !migrate {
+		return nil, fmt.Errorf("%w\nRun 'gh biome migrate-legacy %s' to upgrade it, or pass --migrate to upgrade it before running the command", err, needsMigration.Path)
+	}
+	_, migration, err := biome.MigrateLegacy(ctx, needsMigration.Path, biomeOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate legacy configuration: %w", err)
+	}
+	if cmd := commandFrom(ctx); cmd != nil {
+		cmd.PrintErrf("Migrated %d remotes to git biome in %s\n", len(migration.Remotes), needsMigration.Path)
+	}
+	return biome.Load(ctx, needsMigration.Path, biomeOptions...)
 }
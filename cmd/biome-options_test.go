@@ -18,5 +18,32 @@ func TestMain(m *testing.M) {
 		panic(err.Error())
 	}
 	defer os.Remove(biomeBuildPath)
+	credentialHelper = shellQuote(biomeBuildPath) + " credential-helper"
 	m.Run()
 }
+
+// resetDirectory forgets any -C flag given to earlier commands.
+func resetDirectory() {
+	directory = "."
+	rootCmd.PersistentFlags().Lookup("directory").Changed = false
+}
+
+func TestBiomeDirectory(t *testing.T) {
+	t.Cleanup(resetDirectory)
+	t.Setenv("GH_BIOME_DIR", "")
+	if dir := biomeDirectory(); dir != "." {
+		t.Errorf("expected the current directory by default, was %q", dir)
+	}
+
+	t.Setenv("GH_BIOME_DIR", "/var/lib/biome")
+	if dir := biomeDirectory(); dir != "/var/lib/biome" {
+		t.Errorf("expected GH_BIOME_DIR, was %q", dir)
+	}
+
+	if err := rootCmd.PersistentFlags().Set("directory", "/tmp/other"); err != nil {
+		t.Fatal(err)
+	}
+	if dir := biomeDirectory(); dir != "/tmp/other" {
+		t.Errorf("expected the -C directory to take precedence over GH_BIOME_DIR, was %q", dir)
+	}
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	"github.com/spf13/cobra"
+)
+
+var (
+	catSkipMissing bool
+)
+
+func init() {
+	catCmd.Flags().BoolVar(&catSkipMissing, "skip-missing", false, "Warn about, rather than fail on, files that do not exist in a remote.")
+	rootCmd.AddCommand(catCmd)
+}
+
+var catCmd = &cobra.Command{
+	Use:   "cat <remote>[@<ref>]:<path>...",
+	Short: "Print the content of files of remotes without checking them out",
+	Long: `
+Print the content of a file of a remote's repository at the tip of its default
+branch, read directly from the biome's object database, so that scripts can
+read specific files, ex. manifests or lockfiles, from many remotes without
+checking them out.
+
+Name a branch or tag after the remote's name to read the file at that
+reference instead. References are named relative to the remote's namespace,
+ex. "main", "heads/main", or "tags/v1.0".
+
+The content of each file given is printed in order. Use --skip-missing to
+print a warning, rather than fail, when a remote does not have the file, ex.
+when reading the same file across many remotes.
+
+Remotes are named with the following format.
+
+	<host>/<owner-name>/<repo-name>
+`,
+	Example: `biome cat github.com/cli/cli:go.mod
+
+biome cat github.com/cli/cli@tags/v2.0.0:go.mod
+
+biome remotes | sed 's/$/:go.mod/' | xargs biome cat --skip-missing
+`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var specs []catSpec
+		for _, arg := range args {
+			spec, err := parseCatSpec(arg)
+			if err != nil {
+				return err
+			}
+			specs = append(specs, spec)
+		}
+
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, spec := range specs {
+			err := b.Cat(ctx, cmd.OutOrStdout(), spec.remote, spec.ref, spec.path)
+			if catSkipMissing && errors.Is(err, biome.ErrPathNotFound) {
+				cmd.PrintErrf("Warning: %v\n", err)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// catSpec names a file of a remote, given as <remote>[@<ref>]:<path>.
+type catSpec struct {
+	remote string
+	ref    string
+	path   string
+}
+
+func parseCatSpec(s string) (catSpec, error) {
+	remote, path, ok := strings.Cut(s, ":")
+	if !ok || path == "" {
+		return catSpec{}, fmt.Errorf("invalid file %q, expected <remote>[@<ref>]:<path>", s)
+	}
+	// remote names cannot contain '@', while references can
+	remote, ref, _ := strings.Cut(remote, "@")
+	if remote == "" {
+		return catSpec{}, fmt.Errorf("invalid file %q, expected <remote>[@<ref>]:<path>", s)
+	}
+	return catSpec{remote: remote, ref: ref, path: path}, nil
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func init() {
+	catCmd.SetContext(context.Background())
+	pushInContext(catCmd)
+}
+
+func TestParseCatSpec(t *testing.T) {
+	for _, tc := range []struct {
+		arg      string
+		expected catSpec
+		invalid  bool
+	}{
+		{
+			arg:      "github.com/cli/cli:go.mod",
+			expected: catSpec{remote: "github.com/cli/cli", path: "go.mod"},
+		},
+		{
+			arg:      "github.com/cli/cli@tags/v2.0.0:docs/README.md",
+			expected: catSpec{remote: "github.com/cli/cli", ref: "tags/v2.0.0", path: "docs/README.md"},
+		},
+		{
+			arg:     "github.com/cli/cli",
+			invalid: true,
+		},
+		{
+			arg:     "github.com/cli/cli:",
+			invalid: true,
+		},
+		{
+			arg:     "@main:go.mod",
+			invalid: true,
+		},
+	} {
+		t.Run(tc.arg, func(t *testing.T) {
+			spec, err := parseCatSpec(tc.arg)
+			if tc.invalid {
+				if err == nil {
+					t.Errorf("expected error, got %+v", spec)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if spec != tc.expected {
+				t.Errorf("expected %+v, got %+v", tc.expected, spec)
+			}
+		})
+	}
+}
+
+func TestCatCmd_Execute(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_orirawlings.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+
+	buf := new(bytes.Buffer)
+	catCmd.SetOut(buf)
+	t.Cleanup(func() {
+		catCmd.SetOut(nil)
+		catSkipMissing = false
+	})
+
+	// remotes have not been fetched
+	rootCmd.SetArgs([]string{"cat", "--skip-missing", "github.com/orirawlings/bar:go.mod"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Errorf("expected error for remote that has not been fetched")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output, got %q", buf.String())
+	}
+}
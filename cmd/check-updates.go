@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// checkUpdatesCmd represents the check-updates command
+var checkUpdatesCmd = &cobra.Command{
+	Use:   "check-updates [<github-owner> ...]",
+	Short: "List remotes that are behind GitHub, without fetching",
+	Long: `
+Ask GitHub for the default branch of the repository of each remote in the git
+biome, and list the remotes whose HEAD reference does not match it, without
+fetching anything. This shows how stale the biome is, and which remotes the
+next fetch would update, at the cost of a single GraphQL query per 100
+repositories.
+
+For each remote, when GitHub last received a push to the repository is listed,
+along with the commit of the remote's default branch in the biome and in
+GitHub. Remotes whose default branch was never fetched are behind, and remotes
+without a default branch in GitHub are never behind. Remotes of GitHub servers
+that gh is not logged in to are not checked.
+
+If owners are specified as arguments, only the remotes of those owners are
+checked. Use --all to list every checked remote, including those that are up to
+date, and --format csv to print the remotes as comma separated values, with
+full commit IDs.
+
+<github-owner> is specified with the following format, where <host> is the GitHub
+server name and <owner-name> is the name of the GitHub user or organziation within
+the server. If <host> is omitted, "github.com" is assumed.
+
+	[https://][<host>/]<owner-name>
+`,
+	Example: `biome check-updates
+
+biome check-updates github.com/cli
+
+biome check-updates --all --format csv
+`,
+	Args: validOwnerRefs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		owners, err := parseOwners(args)
+		if err != nil {
+			return err
+		}
+		if err := validateOwnersPresent(ctx, b, owners); err != nil {
+			return err
+		}
+
+		checks, err := b.CheckUpdates(ctx, owners...)
+		if err != nil {
+			return err
+		}
+
+		objectName := shortObjectName
+		if checkUpdatesFormat == csvFormat {
+			objectName = func(name string) string { return name }
+		}
+		var behind int
+		w := newReportWriter(cmd, checkUpdatesFormat, "remote", "pushed", "local", "github")
+		for _, c := range checks {
+			if c.Behind() {
+				behind++
+			} else if !checkUpdatesAll {
+				continue
+			}
+			w.Row(c.Remote, c.PushedAt.UTC().Format(time.RFC3339), objectName(c.Local), objectName(c.Upstream))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		cmd.PrintErrf("%d of %d remotes are behind GitHub\n", behind, len(checks))
+		return nil
+	},
+}
+
+var (
+	checkUpdatesAll    bool
+	checkUpdatesFormat outputFormat
+)
+
+func init() {
+	rootCmd.AddCommand(checkUpdatesCmd)
+	checkUpdatesCmd.Flags().BoolVar(&checkUpdatesAll, "all", false, "List remotes that are up to date as well.")
+	addFormatFlag(checkUpdatesCmd.Flags(), &checkUpdatesFormat)
+}
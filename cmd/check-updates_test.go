@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"gopkg.in/h2non/gock.v1"
+)
+
+func init() {
+	checkUpdatesCmd.SetContext(context.Background())
+	pushInContext(checkUpdatesCmd)
+}
+
+func TestCheckUpdatesCmd_Execute(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_cli.String(),
+		github_com_orirawlings.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+
+	const oid = "0123456789abcdef0123456789abcdef01234567"
+	for owner, nodes := range map[string]string{
+		"cli":         `{"url":"https://github.com/cli/cli","pushedAt":"2024-01-02T03:04:05Z","defaultBranchRef":{"target":{"oid":"` + oid + `"}}}`,
+		"orirawlings": `{"url":"https://github.com/orirawlings/archived","pushedAt":"2023-01-02T03:04:05Z","defaultBranchRef":{"target":{"oid":"` + oid + `"}}},{"url":"https://github.com/orirawlings/headless","pushedAt":"2022-01-02T03:04:05Z","defaultBranchRef":null}`,
+	} {
+		gock.New("https://api.github.com").
+			Post("/graphql").
+			BodyString(`{"query":"query OwnerUpdates($endCursor:String$owner:String!){repositoryOwner(login: $owner){repositories(first: 100, after: $endCursor, affiliations: [OWNER]){nodes{url,pushedAt,defaultBranchRef{target{oid}}},pageInfo{hasNextPage,endCursor}}}}","variables":{"endCursor":null,"owner":"` + owner + `"}}`).
+			Persist().
+			Reply(200).
+			JSON(`{"data":{"repositoryOwner":{"repositories":{"nodes":[` + nodes + `],"pageInfo":{"hasNextPage":false,"endCursor":"abc"}}}}}`)
+	}
+
+	for _, run := range []struct {
+		args     []string
+		expected []string
+	}{
+		{
+			args: []string{"--format", "csv"},
+			expected: []string{
+				"remote,pushed,local,github",
+				"github.com/cli/cli,2024-01-02T03:04:05Z,," + oid,
+				"github.com/orirawlings/archived,2023-01-02T03:04:05Z,," + oid,
+			},
+		},
+		{
+			args: []string{"--format", "csv", "--all", github_com_orirawlings.String()},
+			expected: []string{
+				"remote,pushed,local,github",
+				"github.com/orirawlings/archived,2023-01-02T03:04:05Z,," + oid,
+				"github.com/orirawlings/headless,2022-01-02T03:04:05Z,,",
+			},
+		},
+		{
+			args: []string{"cli"},
+			expected: []string{
+				"REMOTE              PUSHED                LOCAL  GITHUB",
+				"github.com/cli/cli  2024-01-02T03:04:05Z  -      0123456",
+			},
+		},
+	} {
+		t.Run(strings.Join(run.args, " "), func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			checkUpdatesCmd.SetOut(buf)
+			t.Cleanup(func() {
+				checkUpdatesCmd.SetOut(nil)
+				checkUpdatesAll = false
+				checkUpdatesFormat = tableFormat
+			})
+			rootCmd.SetArgs(append([]string{"check-updates"}, run.args...))
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("unexpected error executing command: %v", err)
+			}
+			expected := strings.Join(run.expected, "\n") + "\n"
+			if buf.String() != expected {
+				t.Errorf("expected %q, got %q", expected, buf.String())
+			}
+		})
+	}
+
+	rootCmd.SetArgs([]string{"check-updates", "github.com/git"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Errorf("expected error checking the remotes of an owner that was not added")
+	}
+}
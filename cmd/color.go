@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/cli/go-gh/v2/pkg/x/color"
+	"github.com/orirawlings/gh-biome/internal/biome"
+	"github.com/spf13/cobra"
+)
+
+// noColor disables colorized output, given by the --no-color flag.
+var noColor bool
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Print output without color, even to a terminal.")
+}
+
+const (
+	ansiReset  = "\x1b[0m"
+	ansiRed    = "\x1b[31m"
+	ansiYellow = "\x1b[33m"
+	ansiBlue   = "\x1b[34m"
+
+	// ansiGray is the bright black of the terminal's own 16 color palette,
+	// which the terminal's theme keeps readable against its background.
+	ansiGray = "\x1b[90m"
+
+	// ansiGray256 is a fixed mid-gray from the 256 color palette, which is
+	// more subdued than ansiGray in most themes.
+	ansiGray256 = "\x1b[38;5;242m"
+)
+
+// colorScheme styles the text that listing commands print to a terminal.
+// Remote names are colored by their category, and metadata, ex. upstreams,
+// groups, and notes about defaults, is muted so that names stand out in large
+// listings. The zero value prints text unchanged.
+type colorScheme struct {
+	enabled bool
+
+	// accessible limits colors to the terminal's 16 color palette, so that
+	// they follow the terminal's theme, see
+	// [color.IsAccessibleColorsEnabled].
+	accessible bool
+
+	// is256 reports whether the terminal supports 256 colors.
+	is256 bool
+}
+
+// newColorScheme returns the color scheme for the command's output. Colors
+// are only enabled when the output is a terminal, unless forced by
+// CLICOLOR_FORCE, and are disabled by --no-color or NO_COLOR.
+func newColorScheme(cmd *cobra.Command) colorScheme {
+	f, ok := cmd.OutOrStdout().(*os.File)
+	if noColor || !ok {
+		return colorScheme{}
+	}
+	if !term.IsColorForced() && (term.IsColorDisabled() || !term.IsTerminal(f)) {
+		return colorScheme{}
+	}
+	return colorScheme{
+		enabled:    true,
+		accessible: color.IsAccessibleColorsEnabled(),
+		is256:      term.FromEnv().Is256ColorSupported(),
+	}
+}
+
+// style wraps the text in the given escape sequence, if colors are enabled.
+func (c colorScheme) style(code, text string) string {
+	if !c.enabled || code == "" || text == "" {
+		return text
+	}
+	return code + text + ansiReset
+}
+
+// Muted styles metadata that supports, but is less important than, the
+// names it is printed alongside.
+func (c colorScheme) Muted(text string) string {
+	if c.is256 && !c.accessible {
+		return c.style(ansiGray256, text)
+	}
+	return c.style(ansiGray, text)
+}
+
+// Remote styles text naming the remote, ex. its name or HEAD reference, by
+// the remote's category. Active remotes are left unstyled, since they are
+// usually the bulk of a listing.
+func (c colorScheme) Remote(r biome.Remote, text string) string {
+	if !c.enabled {
+		// skip checking whether the remote is supported, which runs git
+		return text
+	}
+	switch {
+	case r.Evicted:
+		return c.style(ansiBlue, text)
+	case r.Disabled, r.Locked:
+		return c.style(ansiRed, text)
+	case r.Archived:
+		return c.style(ansiYellow, text)
+	case !r.Supported():
+		return c.Muted(text)
+	}
+	return text
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	"github.com/spf13/cobra"
+)
+
+func TestNewColorScheme(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.SetOut(new(bytes.Buffer))
+	if cs := newColorScheme(cmd); cs.enabled {
+		t.Error("expected colors to be disabled when output is not a terminal")
+	}
+
+	cmd.SetOut(os.Stdout)
+	t.Setenv("CLICOLOR_FORCE", "1")
+	t.Setenv("GH_ACCESSIBLE_COLORS", "1")
+	if cs := newColorScheme(cmd); !cs.enabled || !cs.accessible {
+		t.Errorf("expected accessible colors to be forced, was %+v", cs)
+	}
+
+	noColor = true
+	t.Cleanup(func() {
+		noColor = false
+	})
+	if cs := newColorScheme(cmd); cs.enabled {
+		t.Error("expected colors to be disabled by --no-color")
+	}
+}
+
+func TestColorScheme(t *testing.T) {
+	active := biome.Remote{Name: "github.com/cli/cli"}
+	archived := biome.Remote{Name: "github.com/cli/old", Archived: true}
+	locked := biome.Remote{Name: "github.com/cli/moved", Locked: true}
+	for _, tc := range []struct {
+		name     string
+		actual   string
+		expected string
+	}{
+		{
+			name:     "disabled",
+			actual:   colorScheme{}.Remote(archived, archived.Name) + " " + colorScheme{}.Muted("upstream"),
+			expected: "github.com/cli/old upstream",
+		},
+		{
+			name:     "active",
+			actual:   colorScheme{enabled: true}.Remote(active, active.Name),
+			expected: "github.com/cli/cli",
+		},
+		{
+			name:     "archived",
+			actual:   colorScheme{enabled: true}.Remote(archived, archived.Name),
+			expected: "\x1b[33mgithub.com/cli/old\x1b[0m",
+		},
+		{
+			name:     "locked",
+			actual:   colorScheme{enabled: true}.Remote(locked, locked.Head()),
+			expected: "\x1b[31mrefs/remotes/github.com/cli/moved/HEAD\x1b[0m",
+		},
+		{
+			name:     "muted",
+			actual:   colorScheme{enabled: true, is256: true}.Muted("(default)"),
+			expected: "\x1b[38;5;242m(default)\x1b[0m",
+		},
+		{
+			name:     "muted accessible",
+			actual:   colorScheme{enabled: true, is256: true, accessible: true}.Muted("(default)"),
+			expected: "\x1b[90m(default)\x1b[0m",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.actual != tc.expected {
+				t.Errorf("expected %q, was %q", tc.expected, tc.actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// ownerCompletionLimit is the most owners suggested by a completion.
+	ownerCompletionLimit = 20
+
+	// ownerCompletionTimeout bounds how long a completion waits for GitHub,
+	// so that the shell does not hang.
+	ownerCompletionTimeout = 5 * time.Second
+)
+
+// splitOwnerCompletion splits a partially typed owner argument into the part
+// that is kept as typed, ex. `https://my.github.biz/`, the GitHub host that
+// is searched, and the prefix of the owner's login.
+func splitOwnerCompletion(toComplete string) (kept, host, prefix string) {
+	rest := toComplete
+	if after, ok := strings.CutPrefix(rest, "https://"); ok {
+		kept, rest = "https://", after
+	}
+	if h, p, ok := strings.Cut(rest, "/"); ok {
+		return kept + h + "/", h, p
+	}
+	return kept, "github.com", rest
+}
+
+// completeOwners completes owner arguments by searching the GitHub host, given
+// by the typed host segment or github.com, for users and organizations whose
+// logins begin with the typed prefix.
+func completeOwners(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	kept, host, prefix := splitOwnerCompletion(toComplete)
+	if prefix == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ownerCompletionTimeout)
+	defer cancel()
+	matches, err := biome.SearchOwners(ctx, host, prefix, ownerCompletionLimit)
+	if err != nil {
+		cobra.CompErrorln(err.Error())
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var completions []string
+	for _, m := range matches {
+		kind := "user"
+		if m.Organization {
+			kind = "organization"
+		}
+		completions = append(completions, kept+m.Login+"\t"+kind)
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
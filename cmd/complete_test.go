@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestSplitOwnerCompletion(t *testing.T) {
+	for _, tc := range []struct {
+		toComplete, kept, host, prefix string
+	}{
+		{"ori", "", "github.com", "ori"},
+		{"github.com/ori", "github.com/", "github.com", "ori"},
+		{"my.github.biz/foo", "my.github.biz/", "my.github.biz", "foo"},
+		{"https://my.github.biz/foo", "https://my.github.biz/", "my.github.biz", "foo"},
+		{"https://foo", "https://", "github.com", "foo"},
+		{"my.github.biz/", "my.github.biz/", "my.github.biz", ""},
+	} {
+		kept, host, prefix := splitOwnerCompletion(tc.toComplete)
+		if kept != tc.kept || host != tc.host || prefix != tc.prefix {
+			t.Errorf("unexpected split of %q: wanted %q, %q, %q, was %q, %q, %q", tc.toComplete, tc.kept, tc.host, tc.prefix, kept, host, prefix)
+		}
+	}
+}
+
+func TestCompleteOwners(t *testing.T) {
+	stubGitHub(t)
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_ENTERPRISE_TOKEN", "")
+	t.Setenv("GITHUB_ENTERPRISE_TOKEN", "")
+	gock.New("https://my.github.biz").
+		Get("/api/v3/search/users").
+		MatchParam("q", "^foo in:login$").
+		Reply(200).
+		JSON(`{"items":[{"login":"foobar","type":"Organization"},{"login":"foo","type":"User"}]}`)
+
+	completions, directive := completeOwners(addCmd, nil, "https://my.github.biz/foo")
+	expected := []string{
+		"https://my.github.biz/foobar\torganization",
+		"https://my.github.biz/foo\tuser",
+	}
+	if !slices.Equal(completions, expected) {
+		t.Errorf("unexpected completions: wanted %q, was %q", expected, completions)
+	}
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("unexpected directive: %v", directive)
+	}
+
+	if completions, _ := completeOwners(addCmd, nil, "github.com/"); len(completions) != 0 {
+		t.Errorf("expected no completions without a prefix, was %q", completions)
+	}
+}
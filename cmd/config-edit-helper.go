@@ -1,40 +1,85 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/orirawlings/gh-biome/internal/config"
 	pb "github.com/orirawlings/gh-biome/internal/config/protobuf"
 
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 )
 
+// configEditHelperConnectTimeout is how long the helper tries to connect to
+// biome's editor server before giving up, so that it does not linger if biome
+// went away.
+const configEditHelperConnectTimeout = 10 * time.Second
+
 func init() {
 	rootCmd.AddCommand(configEditHelperCmd)
 }
 
 var configEditHelperCmd = &cobra.Command{
-	Use:   "config-edit-helper <callback-unix-socket> <config-file>",
+	Use:   "config-edit-helper <callback-target> <token> <config-file>",
 	Short: "A GIT_EDITOR implementation to use with 'git config edit'.",
 	Long: `
 A GIT_EDITOR implementation to use with 'git config edit'.
 
-This command calls back to a edit server listening at the given unix socke
-with the name of the git config file that needs to be edited.
+This command calls back to a edit server listening at the given gRPC target
+with the name of the git config file that needs to be edited, authenticating
+the call with the given token. The target is a unix socket, ex. unix:/tmp/123,
+or a TCP loopback address on Windows versions without unix sockets. A bare
+path is assumed to be a unix socket.
+
+The command gives up if it cannot connect within 10s, and exits once biome is
+done editing, or goes away.
 `,
 	Hidden: true,
-	Args:   cobra.ExactArgs(2),
+	Args:   cobra.ExactArgs(3),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		conn, err := grpc.NewClient(fmt.Sprintf("unix:%s", args[0]), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		target := args[0]
+		if !strings.Contains(target, ":") {
+			target = fmt.Sprintf("unix:%s", target)
+		}
+		conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
 		if err != nil {
 			return err
 		}
 		defer conn.Close()
+		if err := awaitReady(cmd.Context(), conn, configEditHelperConnectTimeout); err != nil {
+			return fmt.Errorf("could not connect to biome's editor server at %s within %s: %w", target, configEditHelperConnectTimeout, err)
+		}
 		c := pb.NewEditorClient(conn)
-		_, err = c.Edit(cmd.Context(), &pb.EditRequest{
-			Path: args[1],
+		ctx := metadata.AppendToOutgoingContext(cmd.Context(), config.HelperTokenKey, args[1])
+		_, err = c.Edit(ctx, &pb.EditRequest{
+			Path: args[2],
 		})
-		return err
+		if err != nil {
+			return fmt.Errorf("could not call back to biome at %s: %w", target, err)
+		}
+		return nil
 	},
 }
+
+// awaitReady waits for the client connection to be ready, or for the timeout
+// to pass.
+func awaitReady(ctx context.Context, conn *grpc.ClientConn, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	conn.Connect()
+	for {
+		state := conn.GetState()
+		if state == connectivity.Ready {
+			return nil
+		}
+		if !conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("%w, connection was %s", ctx.Err(), state)
+		}
+	}
+}
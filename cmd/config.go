@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	cmdutil "github.com/orirawlings/gh-biome/internal/util/command"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	configListDescriptions bool
+)
+
+func init() {
+	configListCmd.Flags().BoolVar(&configListDescriptions, "describe", false, "Include a description of each setting.")
+	for _, s := range biome.OwnerSettings() {
+		configCmd.Long += fmt.Sprintf("\n\t%s\n\t\t%s\n", s.Key, s.Description)
+	}
+	configCmd.Long += `
+Some settings apply to a single remote. They are stored under
+biome.remote.<remote-name>.<option>, where <remote-name> uses the format
+<host>/<owner-name>/<repo-name>. The following per-remote settings are
+available:
+`
+	for _, s := range biome.RemoteSettings() {
+		configCmd.Long += fmt.Sprintf("\n\t%s\n\t\t%s\n", s.Key, s.Description)
+	}
+	configCmd.Long += `
+Some settings apply to all owners and viewers of a GitHub server. They are
+stored under biome.host.<host>.<option>, where <host> is the GitHub server
+name, ex. github.com. The following per-host settings are available:
+`
+	for _, s := range biome.HostSettings() {
+		configCmd.Long += fmt.Sprintf("\n\t%s\n\t\t%s\n", s.Key, s.Description)
+	}
+	configCmd.AddCommand(configGetCmd, configSetCmd, configUnsetCmd, configListCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Read and write git biome settings",
+	Long: `
+Read and write settings that control the behavior of the git biome. Settings
+are stored in the biome's git config, but are validated before they are
+stored, so prefer these commands over editing the git config by hand.
+
+Run 'biome config list' to show all available settings and their effective
+values.
+
+Some settings apply to a single owner. They are stored under
+biome.owner.<github-owner>.<option>, where <github-owner> uses the format
+<host>/<owner-name>. The following per-owner settings are available:
+`,
+	Example: `biome config set biome.owner.github.com/cli.exclude 'legacy-*,sandbox'
+
+biome config set biome.remote.github.com/orirawlings/cli.upstream github.com/cli/cli
+`,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the effective value of a git biome setting",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+		v, err := b.GetSetting(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		cmdutil.Println(cmd, v.Value)
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Validate and store the value of a git biome setting",
+	Example: `biome config set fetch.parallel 8
+`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+		return b.SetSetting(ctx, args[0], args[1])
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a git biome setting, restoring its default value",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+		return b.UnsetSetting(ctx, args[0])
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List all git biome settings and their effective values",
+	Aliases: []string{"ls"},
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+		values, err := b.ListSettings(ctx)
+		if err != nil {
+			return err
+		}
+		for _, v := range values {
+			printSettingValue(cmd, v)
+		}
+		return nil
+	},
+}
+
+// printSettingValue prints a setting as key=value, optionally followed by its
+// description.
+func printSettingValue(cmd *cobra.Command, v biome.SettingValue) {
+	line := fmt.Sprintf("%s=%s", v.Key, v.Value)
+	if configListDescriptions {
+		line = fmt.Sprintf("%s\t# %s", line, v.Description)
+	}
+	cmdutil.Println(cmd, line)
+}
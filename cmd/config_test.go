@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func init() {
+	configCmd.SetContext(context.Background())
+	pushInContext(configCmd)
+}
+
+func TestConfigCmd_Execute(t *testing.T) {
+	initBiome(t)
+
+	rootCmd.SetArgs([]string{"config", "set", "fetch.parallel", "8"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+
+	rootCmd.SetArgs([]string{"config", "set", "fetch.parallel", "lots"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Fatal("expected error setting an invalid value, but was nil")
+	}
+
+	buf := new(bytes.Buffer)
+	configGetCmd.SetOut(buf)
+	t.Cleanup(func() {
+		configGetCmd.SetOut(nil)
+	})
+	rootCmd.SetArgs([]string{"config", "get", "fetch.parallel"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	if expected := "8\n"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/auth"
+	"github.com/orirawlings/gh-biome/internal/biome"
+
+	"github.com/spf13/cobra"
+)
+
+// credentialHelper is the command that git runs as a credential helper
+// during fetches. It can be overridden during tests.
+var credentialHelper = fmt.Sprintf("%s credential-helper", shellQuote(filepath.ToSlash(os.Args[0])))
+
+func init() {
+	rootCmd.AddCommand(credentialHelperCmd)
+}
+
+var credentialHelperCmd = &cobra.Command{
+	Use:   "credential-helper <operation>",
+	Short: "A git credential helper that provides the tokens stored by gh.",
+	Long: `
+A git credential helper that provides the tokens stored by gh, ex. by
+'gh auth login'. See gitcredentials(7).
+
+Only the get operation is supported. Credentials are never stored or erased.
+`,
+	Hidden: true,
+	Args:   cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if args[0] != "get" {
+			return nil
+		}
+		attrs := make(map[string]string)
+		scanner := bufio.NewScanner(cmd.InOrStdin())
+		for scanner.Scan() {
+			line := scanner.Text()
+			if line == "" {
+				break
+			}
+			if k, v, ok := strings.Cut(line, "="); ok {
+				attrs[k] = v
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+		if attrs["protocol"] != "https" {
+			return nil
+		}
+		token, _ := auth.TokenForHost(attrs["host"])
+		if token == "" {
+			return nil
+		}
+		_, err := fmt.Fprintf(cmd.OutOrStdout(), "protocol=https\nhost=%s\nusername=x-access-token\npassword=%s\n", attrs["host"], token)
+		return err
+	},
+}
+
+// credentialArgs returns git options that make git authenticate to the
+// biome's hosts with the tokens stored by gh, unless the biome is configured
+// to use the user's own git credentials. The options only apply to the git
+// command they are given to, so nothing is persisted in any git config.
+func credentialArgs(ctx context.Context, b biome.Biome) ([]string, error) {
+	source, err := b.GetSetting(ctx, "biome.credentials")
+	if err != nil {
+		return nil, err
+	}
+	if biome.CredentialSource(source.Value) != biome.CredentialsGH {
+		return nil, nil
+	}
+	hosts, err := b.Hosts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var args []string
+	for _, host := range hosts {
+		if token, _ := auth.TokenForHost(host); token == "" {
+			// leave hosts that gh cannot authenticate to the user's own
+			// git credential configuration
+			continue
+		}
+		key := fmt.Sprintf("credential.https://%s.helper", host)
+		// an empty helper resets the list of helpers from any other config,
+		// so only biome's helper is consulted for the host
+		args = append(args, "-c", key+"=", "-c", fmt.Sprintf("%s=!%s", key, credentialHelper))
+	}
+	return args, nil
+}
+
+// shellQuote quotes a string for use as a single word in a POSIX shell
+// command.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func init() {
+	credentialHelperCmd.SetContext(context.Background())
+	pushInContext(credentialHelperCmd)
+}
+
+func TestCredentialHelperCmd_Execute(t *testing.T) {
+	stubGitHub(t)
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_ENTERPRISE_TOKEN", "")
+	t.Setenv("GITHUB_ENTERPRISE_TOKEN", "")
+
+	for _, tc := range []struct {
+		name      string
+		operation string
+		input     string
+		expected  string
+	}{
+		{
+			name:      "get",
+			operation: "get",
+			input:     "protocol=https\nhost=github.com\npath=cli/cli.git\n\n",
+			expected:  "protocol=https\nhost=github.com\nusername=x-access-token\npassword=abc123\n",
+		},
+		{
+			name:      "unknown host",
+			operation: "get",
+			input:     "protocol=https\nhost=example.com\n",
+			expected:  "",
+		},
+		{
+			name:      "not https",
+			operation: "get",
+			input:     "protocol=http\nhost=github.com\n",
+			expected:  "",
+		},
+		{
+			name:      "store",
+			operation: "store",
+			input:     "protocol=https\nhost=github.com\nusername=x\npassword=y\n",
+			expected:  "",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			credentialHelperCmd.SetIn(strings.NewReader(tc.input))
+			credentialHelperCmd.SetOut(buf)
+			t.Cleanup(func() {
+				credentialHelperCmd.SetIn(nil)
+				credentialHelperCmd.SetOut(nil)
+			})
+			rootCmd.SetArgs([]string{"credential-helper", tc.operation})
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("unexpected error executing command: %v", err)
+			}
+			if buf.String() != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestCredentialArgs(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_cli.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+
+	ctx := context.Background()
+	b, err := load(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error loading biome: %v", err)
+	}
+	args, err := credentialArgs(ctx, b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{
+		"-c", "credential.https://github.com.helper=",
+		"-c", "credential.https://github.com.helper=!" + credentialHelper,
+	}
+	if !slices.Equal(args, expected) {
+		t.Errorf("expected %q, got %q", expected, args)
+	}
+
+	// the user's own git credentials can be used instead
+	if err := b.SetSetting(ctx, "biome.credentials", "git"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	args, err = credentialArgs(ctx, b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if args != nil {
+		t.Errorf("expected no credential options, got %q", args)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if actual, expected := shellQuote("/tmp/it's here/biome"), `'/tmp/it'\''s here/biome'`; actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
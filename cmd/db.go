@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	cmdutil "github.com/orirawlings/gh-biome/internal/util/command"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	dbCmd.AddCommand(dbSyncCmd)
+	rootCmd.AddCommand(dbCmd)
+}
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Manage the git biome's optional SQLite metadata database",
+	Long: `
+Manage an optional SQLite database of the git biome's metadata, stored inside
+the biome as biome.db, so that it can be queried with SQL by external tools,
+ex. sqlite3, notebooks, or BI tools.
+`,
+}
+
+var dbSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Materialize the biome's metadata into the SQLite database",
+	Long: `
+Write the git biome's metadata into its SQLite database, replacing anything
+synced before. The database has the following tables:
+
+  meta            the schema version, the biome's path, and the time of the sync
+  owners          the biome's owners, and whether they are paused
+  remotes         the biome's remotes, their categories, and their upstreams
+  refs            the references of each remote, with their objects or symrefs
+  heads           the commit at the HEAD of each fetched remote, and its date
+  fetches         the fetches of each remote recorded in the journal
+  ref_counts      the number of references of each remote, counted after fetches
+  forced_updates  the branches of each remote that fetches force-updated
+
+The database is written to a temporary file that then replaces the previous
+database, so readers never observe a partial sync.
+`,
+	Example: `biome db sync
+
+sqlite3 .git/biome.db 'SELECT owner, count(*) FROM remotes GROUP BY owner'
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		path, err := b.SyncDatabase(ctx)
+		if err != nil {
+			return err
+		}
+		cmdutil.Println(cmd, "Synced metadata database to", path)
+		return nil
+	},
+}
@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+)
+
+func init() {
+	dbCmd.SetContext(context.Background())
+	pushInContext(dbCmd)
+}
+
+func TestDBSyncCmd_Args(t *testing.T) {
+	rootCmd.SetArgs([]string{"db", "sync", "github.com/cli/cli"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("expected error executing db sync with arguments")
+	}
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	dedupTop int
+)
+
+func init() {
+	dedupCmd.Flags().IntVar(&dedupTop, "top", 10, "number of remotes that share the least to show, or 0 for all")
+	rootCmd.AddCommand(dedupCmd)
+}
+
+var dedupCmd = &cobra.Command{
+	Use:   "dedup",
+	Short: "Estimate the disk saved by sharing git objects between the biome's remotes",
+	Long: `
+Estimate how much disk the git biome saves by storing the git objects that its
+remotes share, ex. forks and mirrors, only once, compared to an independent
+clone of each remote. Independent clones would be packed separately, so the
+estimate is only approximate.
+
+The remotes whose objects are shared with no other reference of the biome are
+listed, largest first. These benefit least from the biome, so they are the
+first candidates for exclusion.
+
+Every remote's objects are measured, which can take a long time for large
+biomes, unless the biome has reachability bitmaps, ex. written by
+'git repack -a -d --write-bitmap-index'.
+`,
+	Example: `biome dedup
+biome dedup --top 0
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dedupTop < 0 {
+			return fmt.Errorf("--top must not be negative: %d", dedupTop)
+		}
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		d, err := b.Deduplication(ctx)
+		if err != nil {
+			return err
+		}
+		var percent float64
+		if d.IndependentBytes > 0 {
+			percent = 100 * float64(d.SavedBytes()) / float64(d.IndependentBytes)
+		}
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+		fmt.Fprintf(w, "Independent clones:\t%s\n", formatBytes(d.IndependentBytes))
+		fmt.Fprintf(w, "Biome:\t%s\n", formatBytes(d.Bytes))
+		fmt.Fprintf(w, "Saved:\t%s (%.0f%%)\n", formatBytes(d.SavedBytes()), percent)
+
+		remotes := d.Remotes
+		if dedupTop > 0 && len(remotes) > dedupTop {
+			remotes = remotes[:dedupTop]
+		}
+		if len(remotes) > 0 {
+			fmt.Fprintln(w)
+			fmt.Fprintln(w, "REMOTE\tSIZE\tSHARED\tEXCLUSIVE")
+		}
+		for _, u := range remotes {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", u.Remote, formatBytes(u.Bytes), formatBytes(u.SharedBytes()), formatBytes(u.ExclusiveBytes))
+		}
+		return w.Flush()
+	},
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+)
+
+func init() {
+	dedupCmd.SetContext(context.Background())
+	pushInContext(dedupCmd)
+}
+
+func TestDedupCmd_Args(t *testing.T) {
+	t.Cleanup(func() {
+		dedupTop = 10
+	})
+	for _, args := range [][]string{
+		{"dedup", "github.com/orirawlings"},
+		{"dedup", "--top", "-1"},
+	} {
+		dedupTop = 10
+		rootCmd.SetArgs(args)
+		if err := rootCmd.Execute(); err == nil {
+			t.Errorf("expected error executing %q", args)
+		}
+	}
+}
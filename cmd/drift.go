@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	driftJSON bool
+)
+
+func init() {
+	driftCmd.Flags().BoolVar(&driftJSON, "json", false, "Print the report as a JSON array.")
+	rootCmd.AddCommand(driftCmd)
+}
+
+var driftCmd = &cobra.Command{
+	Use:   "drift",
+	Short: "Report how far forks have diverged from their upstream remotes",
+	Long: `
+Report how far each fork in the git biome has drifted from the repository it
+was forked from. For each fork whose upstream repository is also a remote in
+the biome, the number of commits that the fork's default branch is ahead of
+and behind the upstream's default branch is listed.
+
+Forks and upstreams that have not been fetched are not listed.
+`,
+	Example: `biome drift
+
+biome drift --json
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		report, err := b.ForkDivergence(ctx)
+		if err != nil {
+			return err
+		}
+
+		if driftJSON {
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			if report == nil {
+				return enc.Encode([]any{})
+			}
+			return enc.Encode(report)
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "FORK\tUPSTREAM\tAHEAD\tBEHIND")
+		for _, d := range report {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\n", d.Fork, d.Upstream, d.Ahead, d.Behind)
+		}
+		return w.Flush()
+	},
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func init() {
+	driftCmd.SetContext(context.Background())
+	pushInContext(driftCmd)
+}
+
+func TestDriftCmd_Execute(t *testing.T) {
+	initBiome(t)
+
+	for _, run := range []struct {
+		flags    []string
+		expected string
+	}{
+		{
+			expected: "FORK  UPSTREAM  AHEAD  BEHIND\n",
+		},
+		{
+			flags:    []string{"--json"},
+			expected: "[]\n",
+		},
+	} {
+		buf := new(bytes.Buffer)
+		driftCmd.SetOut(buf)
+		t.Cleanup(func() {
+			driftCmd.SetOut(nil)
+			driftJSON = false
+		})
+		rootCmd.SetArgs(append([]string{"drift"}, run.flags...))
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error executing command: %v", err)
+		}
+		if buf.String() != run.expected {
+			t.Errorf("expected %q, got %q", run.expected, buf.String())
+		}
+	}
+}
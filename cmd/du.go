@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	duFormat outputFormat
+)
+
+func init() {
+	addFormatFlag(duCmd.Flags(), &duFormat)
+	rootCmd.AddCommand(duCmd)
+}
+
+var duCmd = &cobra.Command{
+	Use:   "du",
+	Short: "Show how much of the git biome's disk each GitHub owner uses",
+	Long: `
+Attribute the git biome's object storage to each of its owners, largest first,
+to see which owners are responsible for most of the biome's disk usage.
+
+SIZE is the on-disk size of the git objects reachable from the references of
+the owner's remotes. Objects are often shared between owners, ex. by forks,
+so the sizes of all owners add up to more than the biome's size. EXCLUSIVE is
+the size of the objects that are reachable from the owner's remotes only,
+which is about how much disk would be reclaimed by removing the owner.
+
+Sizes are measured by walking the objects reachable from each owner's remotes,
+which can take a long time for large biomes, unless the biome has
+reachability bitmaps, ex. written by 'git repack -a -d --write-bitmap-index'.
+`,
+	Example: `biome du
+
+biome du --format csv
+`,
+	Aliases: []string{"disk-usage"},
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		usage, err := b.DiskUsage(ctx)
+		if err != nil {
+			return err
+		}
+		w := newReportWriter(cmd, duFormat, "owner", "remotes", "size", "exclusive")
+		for _, u := range usage {
+			w.Row(u.Owner.String(), strconv.Itoa(u.Remotes), w.Bytes(u.Bytes), w.Bytes(u.ExclusiveBytes))
+		}
+		return w.Flush()
+	},
+}
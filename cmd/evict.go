@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	cmdutil "github.com/orirawlings/gh-biome/internal/util/command"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	evictArchived bool
+	evictDir      string
+	restoreAll    bool
+)
+
+func init() {
+	evictCmd.Flags().BoolVar(&evictArchived, "archived", false, "Evict all remotes that are archived in GitHub.")
+	evictCmd.Flags().StringVar(&evictDir, "dir", "", "Directory to store the git bundles of evicted remotes in. Defaults to the biome-bundles directory within the biome's git directory.")
+	restoreCmd.Flags().BoolVar(&restoreAll, "all", false, "Restore all evicted remotes.")
+	rootCmd.AddCommand(evictCmd)
+	rootCmd.AddCommand(restoreCmd)
+}
+
+var evictCmd = &cobra.Command{
+	Use:   "evict <remote> [...]",
+	Short: "Move remotes into cold storage to reclaim disk",
+	Long: `
+Export the references of the given remotes to git bundles, then remove the
+remotes' references and git remote configurations, reclaiming disk from
+rarely needed repositories. The location of each bundle is recorded in the
+biome, so that 'biome restore' can bring the remote back later.
+
+Evicted remotes are not fetched until they are restored, and are listed by
+'biome remotes --evicted'. The disk used by git objects that are only
+reachable from evicted remotes is reclaimed by the next garbage collection,
+ex. 'git gc --prune=now'.
+`,
+	Example: `biome evict github.com/orirawlings/old-project
+
+biome evict --archived --dir /mnt/cold-storage/biome
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if evictArchived {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		remotes := args
+		if evictArchived {
+			archived, err := b.Remotes(ctx, biome.Archived)
+			if err != nil {
+				return err
+			}
+			for _, r := range archived {
+				remotes = append(remotes, r.Name)
+			}
+		}
+
+		evictions, err := b.Evict(ctx, evictDir, remotes...)
+		for _, e := range evictions {
+			cmdutil.Println(cmd, fmt.Sprintf("Evicted %s (%d refs) to %s", e.Remote, e.Refs, e.Bundle))
+		}
+		if err != nil {
+			return err
+		}
+		if len(evictions) > 0 {
+			cmd.PrintErrln("Run 'git gc --prune=now' to reclaim the disk used by the evicted remotes.")
+		}
+		return nil
+	},
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore <remote> [...]",
+	Short: "Bring evicted remotes back from cold storage",
+	Long: `
+Import the references of the given remotes, that were evicted with
+'biome evict', from their git bundles, and configure them as git remotes
+again, so they are fetched once more. The bundles are removed once their
+references are restored.
+`,
+	Example: `biome restore github.com/orirawlings/old-project
+
+biome restore --all
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if restoreAll {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		remotes := args
+		if restoreAll {
+			evicted, err := b.Remotes(ctx, biome.Evicted)
+			if err != nil {
+				return err
+			}
+			for _, r := range evicted {
+				remotes = append(remotes, r.Name)
+			}
+		}
+
+		restored, restoreErr := b.Restore(ctx, remotes...)
+		for _, e := range restored {
+			cmdutil.Println(cmd, fmt.Sprintf("Restored %s (%d refs) from %s", e.Remote, e.Refs, e.Bundle))
+		}
+		if len(restored) == 0 {
+			return restoreErr
+		}
+
+		// configure the restored remotes as git remotes again
+		cmd.PrintErrln("Updating git remote configurations...")
+		return errors.Join(restoreErr, updateRemotes(cmd, b))
+	},
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+)
+
+func init() {
+	evictCmd.SetContext(context.Background())
+	pushInContext(evictCmd)
+	restoreCmd.SetContext(context.Background())
+	pushInContext(restoreCmd)
+}
+
+func TestEvictCmd_Args(t *testing.T) {
+	t.Cleanup(func() {
+		evictArchived = false
+		restoreAll = false
+	})
+	for _, args := range [][]string{
+		{"evict"},
+		{"evict", "--archived", "github.com/orirawlings/bar"},
+		{"restore"},
+		{"restore", "--all", "github.com/orirawlings/bar"},
+	} {
+		evictArchived, restoreAll = false, false
+		rootCmd.SetArgs(args)
+		if err := rootCmd.Execute(); err == nil {
+			t.Errorf("expected error executing %q", args)
+		}
+	}
+}
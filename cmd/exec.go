@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	execOptions = newRemoteCategoryOptions(true)
+)
+
+func init() {
+	execOptions.AddFlags(execCmd.Flags())
+	rootCmd.AddCommand(execCmd)
+}
+
+var execCmd = &cobra.Command{
+	Use:   "exec [flags] -- <command> [<arg> ...]",
+	Short: "Run a command with the git biome's context in its environment",
+	Long: `
+Run an arbitrary command with the context of the git biome exported to its
+environment, so external analysis tools can operate on the biome without
+discovering its remotes and references themselves.
+
+The following environment variables are set for the command:
+
+	BIOME_PATH          path of the biome's git repository
+	BIOME_HEADS_FILE    path of a file listing the HEAD git reference of each
+	                    selected remote, one per line
+	BIOME_REMOTES_FILE  path of a file listing the name of each selected
+	                    remote, one per line
+
+Remotes are selected by category, like the heads command. The files are
+removed once the command exits, and biome exits with the command's exit
+status.
+
+If the biome.lfs.policy setting is skip, GIT_LFS_SKIP_SMUDGE=1 is also set so
+that Git LFS objects are not downloaded by the command.
+`,
+	Example: `biome exec -- sh -c 'xargs git -C "$BIOME_PATH" grep -i "search term" < "$BIOME_HEADS_FILE"'
+
+biome exec --all -- my-analysis-tool
+`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		policy, err := lfsPolicy(ctx, b, "")
+		if err != nil {
+			return err
+		}
+
+		remotes, err := b.Remotes(ctx, execOptions.Categories()...)
+		if err != nil {
+			return err
+		}
+		var heads, names []string
+		for _, remote := range remotes {
+			heads = append(heads, remote.Head())
+			names = append(names, remote.Name)
+		}
+
+		headsFile, err := writeLinesTemp("biome-heads-*", heads)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(headsFile)
+		remotesFile, err := writeLinesTemp("biome-remotes-*", names)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(remotesFile)
+
+		c := exec.CommandContext(ctx, args[0], args[1:]...)
+		c.Env = append(os.Environ(),
+			"BIOME_PATH="+b.Path(),
+			"BIOME_HEADS_FILE="+headsFile,
+			"BIOME_REMOTES_FILE="+remotesFile,
+		)
+		c.Env = append(c.Env, policy.Env()...)
+		c.Stdin = cmd.InOrStdin()
+		c.Stdout = cmd.OutOrStdout()
+		c.Stderr = cmd.ErrOrStderr()
+		if err := c.Run(); err != nil {
+			err = fmt.Errorf("could not %q: %w", c, err)
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+				return &exitCodeError{code: exitErr.ExitCode(), err: err}
+			}
+			return err
+		}
+		return nil
+	},
+}
+
+// writeLinesTemp writes the lines to a new temporary file, returning its path.
+func writeLinesTemp(pattern string, lines []string) (string, error) {
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", err
+	}
+	var content string
+	if len(lines) > 0 {
+		content = strings.Join(lines, "\n") + "\n"
+	}
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func init() {
+	execCmd.SetContext(context.Background())
+	pushInContext(execCmd)
+}
+
+func TestExecCmd_Execute(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_cli.String(),
+		github_com_orirawlings.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+
+	buf := new(bytes.Buffer)
+	execCmd.SetOut(buf)
+	t.Cleanup(func() {
+		execCmd.SetOut(nil)
+		execOptions.Reset()
+	})
+	rootCmd.SetArgs([]string{
+		"exec",
+		"--archived",
+		"--",
+		"sh", "-c", `test -d "$BIOME_PATH" && cat "$BIOME_REMOTES_FILE" "$BIOME_HEADS_FILE"`,
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	expected := strings.Join([]string{
+		"github.com/orirawlings/archived",
+		"refs/remotes/github.com/orirawlings/archived/HEAD",
+	}, "\n") + "\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+
+	// the command's failure is reported with its exit status
+	rootCmd.SetArgs([]string{"exec", "--", "sh", "-c", "exit 3"})
+	err := rootCmd.Execute()
+	var exitErr *exitCodeError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("expected exit code error executing failing command, got %v", err)
+	}
+	if exitErr.code != 3 {
+		t.Errorf("expected exit code 3, got %d", exitErr.code)
+	}
+}
+
+func TestWriteLinesTemp(t *testing.T) {
+	for _, lines := range [][]string{nil, {"a", "b"}} {
+		path, err := writeLinesTemp("biome-test-*", lines)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		t.Cleanup(func() {
+			_ = os.Remove(path)
+		})
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var expected string
+		if len(lines) > 0 {
+			expected = strings.Join(lines, "\n") + "\n"
+		}
+		if string(content) != expected {
+			t.Errorf("expected %q, got %q", expected, content)
+		}
+	}
+}
@@ -1,10 +1,45 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/spf13/cobra"
 )
 
+var (
+	fetchUI        bool
+	fetchLFS       string
+	fetchJobs      int
+	fetchWatch     bool
+	fetchInterval  time.Duration
+	fetchScheduled bool
+
+	fetchQuarantined bool
+	fetchScanned     bool
+
+	fetchAllowProtectedDeletions bool
+
+	fetchRefs      []string
+	fetchHeadsOnly bool
+	fetchTagsOnly  bool
+)
+
 func init() {
+	fetchCmd.Flags().BoolVar(&fetchUI, "ui", false, "Render a live dashboard of fetch progress, throughput, failures, and ETA. Plain git output is used when stderr is not a terminal.")
+	fetchCmd.Flags().StringVar(&fetchLFS, "lfs", "", "Override the biome.lfs.policy setting for this fetch: skip, pointers, or selected.")
+	fetchCmd.Flags().BoolVar(&fetchWatch, "watch", false, "Keep updating and fetching remotes, waiting --interval between fetches, until interrupted.")
+	fetchCmd.Flags().DurationVar(&fetchInterval, "interval", defaultWatchInterval, "How long to wait between fetches with --watch, ex. 15m or 1h.")
+	fetchCmd.Flags().BoolVar(&fetchScheduled, "scheduled", false, "Skip owners that are not yet due to be fetched by their fetchFrequency setting, as --watch does. Use for fetches run by a scheduler, ex. cron.")
+	fetchCmd.Flags().BoolVar(&fetchQuarantined, "quarantine", false, "Receive objects into a quarantine, and only update references once the received packs pass validation, as the biome.fetch.quarantine setting does.")
+	fetchCmd.Flags().BoolVar(&fetchScanned, "scan", false, "Scan the blobs received by the fetch for secrets, as the biome.fetch.scan setting does.")
+	fetchCmd.Flags().BoolVar(&fetchAllowProtectedDeletions, "allow-protected-deletions", false, "Let the fetch delete references matching the biome.fetch.protectedRefs setting that no longer exist upstream, rather than keeping them.")
+	fetchCmd.Flags().IntVar(&fetchJobs, "jobs", 0, "Fetch with a pool of this many concurrent git processes, each fetching a single remote, rather than a single git process limited by fetch.parallel.")
+	fetchCmd.Flags().StringSliceVar(&fetchRefs, "refs", nil, "Only fetch the remote references matching this pattern, relative to refs/, ex. 'heads/*', rather than the remotes' configured refspecs. Can be repeated.")
+	fetchCmd.Flags().BoolVar(&fetchHeadsOnly, "heads-only", false, "Only fetch the remotes' branches, as with --refs 'heads/*'.")
+	fetchCmd.Flags().BoolVar(&fetchTagsOnly, "tags-only", false, "Only fetch the remotes' tags, as with --refs 'tags/*'.")
 	rootCmd.AddCommand(fetchCmd)
 }
 
@@ -32,6 +67,106 @@ refs/remotes/<remote-name>/pull/
 of the owner.
 
 	<host>/<owner-name>/<repo-name>
+
+After fetching, the references of each remote are counted, and the counts
+are recorded in the biome's journal, see 'biome stats --growth'. Branches
+that the fetch updated without fast-forwarding, because their history was
+rewritten and force-pushed, are reported, and recorded in the journal too,
+see 'biome forced-updates'.
+
+Use --ui to follow a long running fetch on a live dashboard, showing how many
+remotes have been fetched, throughput, failures, and the estimated time
+remaining.
+
+If gh is not logged in to an owner's GitHub server, only the owner's public
+repositories are discovered, using GitHub's REST API, and fetched
+anonymously. No token is needed to mirror public owners.
+
+By default, a single git process fetches the remotes, fetching as many remotes
+in parallel as the fetch.parallel setting allows. For large biomes, use --jobs
+to have biome drive a pool of that many concurrent git processes instead,
+each fetching a single remote, even those of a single owner. The remotes that
+took longest to fetch previously are fetched first, so the pool finishes
+around the same time. The processes only receive objects, and biome applies
+the reference updates they report one remote at a time, since concurrent
+reference updates may fail, ex. with the reftable backend. Progress is
+reported as each remote starts fetching. --jobs requires git 2.41 or later.
+Biomes that store references in a reftable are always fetched this way, with
+as many processes as --jobs, or the fetch.parallel setting, allows.
+
+To stay under GitHub's secondary rate limits, or to avoid overwhelming a small
+GitHub Enterprise Server, limit how many of a host's remotes are fetched
+concurrently with 'biome config set biome.host.<host>.maxConcurrent <n>'. The
+remotes of such hosts are fetched by their own git processes, no more than
+the limit at a time, even with --jobs. The owners and viewers of different
+hosts are discovered concurrently, while those of the same host are
+discovered one at a time, or up to the host's maxConcurrent setting. Space
+out the discovery of the owners of a host with 'biome config set
+biome.host.<host>.pace <duration>'.
+
+The remotes of owners paused with 'biome pause' are neither updated nor
+fetched.
+
+Git LFS objects are handled according to the biome.lfs.policy setting, which
+can be overridden for a single fetch with --lfs. Under the selected policy, the
+LFS objects of each remote's default branch are fetched after the git objects,
+for remotes selected with 'biome config set biome.remote.<remote>.lfs true'.
+
+To protect a long-lived biome from corrupt or malicious packs sent by a
+compromised upstream, fetch into a quarantine with --quarantine, or 'biome
+config set biome.fetch.quarantine true'. Objects are then received into a
+temporary object directory, checked by git's fsck checks as they arrive,
+by one git process per remote. Once the remotes are fetched, each received
+pack is verified with 'git index-pack --verify --strict'. Only if every pack
+passes are the objects migrated into the biome, and the reference updates
+that the fetches reported applied, without fetching again. Otherwise, the
+received objects are discarded, and no references are updated.
+
+Since a biome concentrates an organization's entire history in one
+searchable place, scan the blobs received by each fetch for secrets, ex.
+access tokens and private keys, with --scan, or 'biome config set
+biome.fetch.scan true'. Possible secrets are reported for each remote, with
+the secrets themselves redacted. Blobs are scanned with built-in rules,
+skipping binary blobs and blobs larger than 1MiB, unless an external scanner
+is configured with 'biome config set biome.scan.command <command>'. See
+'biome config --help' for the scanner's input and output.
+
+To keep an accident upstream, ex. a deleted main branch, from propagating
+into the biome, protect the remotes' important references with 'biome config
+set biome.fetch.protectedRefs <patterns>', where <patterns> are comma
+separated glob patterns of references relative to each remote's namespace,
+ex. 'heads/main,heads/release-*'. When a fetch deletes a protected
+reference, ex. because fetch.prune is set, the reference is kept at its
+previous commit, with a warning. Once the deletion is confirmed to be
+intended, fetch with --allow-protected-deletions to let it through.
+
+Use --refs, --heads-only, or --tags-only to fetch only some of the remotes'
+references for a single run, ex. a quick refresh of every remote's branches
+before a deadline, rather than all references as the remotes' configured
+refspecs specify. Each remote is then fetched by its own git process, as many
+at a time as --jobs, or the fetch.parallel setting, allows. References that
+are not fetched are left as they are.
+
+Use --watch to keep the biome fresh without scheduling fetches otherwise.
+Remotes are updated and fetched again, and again, waiting --interval between
+fetches. Waits are jittered by up to 10%, so that biomes do not fetch in
+lockstep, and double after each consecutive failed fetch, up to 16 times the
+interval. Failures are reported without ending the watch. Interrupt the
+watch, ex. with Ctrl-C, to stop it, cancelling any fetch in progress.
+
+To keep high-value owners fresh without constantly fetching dormant ones,
+assign owners a fetch frequency with 'biome config set
+biome.owner.<owner>.fetchFrequency <frequency>', where <frequency> is a
+duration, ex. 15m, or one of hourly, daily, or weekly. Fetches with --watch,
+or with --scheduled when run by a scheduler such as cron, skip an owner
+until its frequency has elapsed since its remotes were last fetched, as
+recorded in the biome's journal. Owners without a frequency are fetched
+every time. An owner is fetched at most as often as the scheduled fetches
+run, so pick an --interval, or a cron schedule, no longer than the shortest
+frequency.
+
+Fetches with --watch or --scheduled also check the biome's objects for
+corruption once the biome.fsck.schedule setting is due, see 'biome fsck'.
 `,
 	Example: `biome fetch
 
@@ -42,9 +177,41 @@ biome fetch github.com/orirawlings
 biome fetch https://github.com/orirawlings
 
 biome fetch github.com/orirawlings github.com/git github.com/cli
+
+biome fetch --ui
+
+biome fetch --jobs 16 github.com/kubernetes
+
+biome fetch --lfs selected github.com/orirawlings
+
+biome fetch --watch --interval 15m
+
+biome fetch --scheduled
+
+biome fetch --heads-only
+
+biome fetch --quarantine github.com/kubernetes
+
+biome fetch --scan github.com/orirawlings
+
+biome fetch --allow-protected-deletions github.com/orirawlings
+
+biome fetch --refs 'heads/release-*' github.com/kubernetes
 `,
 	Args: validOwnerRefs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if fetchJobs < 0 {
+			return fmt.Errorf("invalid --jobs: %d: must not be negative", fetchJobs)
+		}
+		if fetchInterval <= 0 {
+			return fmt.Errorf("invalid --interval: %s: must be positive", fetchInterval)
+		}
+		if cmd.Flags().Changed("interval") && !fetchWatch {
+			return errors.New("--interval requires --watch")
+		}
+		if _, err := fetchRefPatterns(); err != nil {
+			return err
+		}
 		ctx := cmd.Context()
 		b, err := load(ctx)
 		if err != nil {
@@ -59,14 +226,76 @@ biome fetch github.com/orirawlings github.com/git github.com/cli
 			return err
 		}
 
-		cmd.PrintErrln("Updating git remote configurations...")
-
-		// update git remote configurations for all owners
-		if err := b.UpdateRemotes(ctx); err != nil {
+		if err := warnAnonymous(ctx, cmd, b); err != nil {
 			return err
 		}
+		fetchOnce := func(ctx context.Context) error {
+			cmd.PrintErrln("Updating git remote configurations...")
 
-		// fetch remotes
-		return fetch(ctx, cmd, b, owners)
+			// update git remote configurations for all owners
+			if err := updateRemotes(cmd, b); err != nil {
+				return err
+			}
+
+			// fetch remotes, except those of paused owners, and of owners
+			// that are not yet due when scheduled
+			groups, ok, err := fetchGroups(ctx, cmd, b, owners, fetchWatch || fetchScheduled)
+			if err != nil || !ok {
+				return err
+			}
+			scan, err := fetchScan(ctx, b)
+			if err != nil {
+				return err
+			}
+			var baseline []string
+			if scan {
+				if baseline, err = b.ScanBaseline(ctx); err != nil {
+					return err
+				}
+			}
+			tips, err := b.BranchTips(ctx)
+			if err != nil {
+				return err
+			}
+			protected, err := protectedRefs(ctx, b)
+			if err != nil {
+				return err
+			}
+			// keep protected references that the fetch deleted, even if
+			// some remotes failed to fetch, or the fetch was interrupted
+			err = fetchKeepingProtectedRefs(ctx, cmd, b, protected, func() error {
+				return fetch(ctx, cmd, b, groups)
+			})
+			if ctx.Err() != nil {
+				return err
+			}
+			if scan {
+				// scan what was received, even if some remotes failed
+				// to fetch
+				err = errors.Join(err, scanFetched(ctx, cmd, b, baseline))
+			}
+
+			// report rewritten history, and count the references of
+			// remotes to monitor their growth, even if some remotes
+			// failed to fetch
+			return errors.Join(err, recordForcedUpdates(ctx, cmd, b, tips), recordRefCounts(ctx, b))
+		}
+		if fetchWatch || fetchScheduled {
+			fetchAndCheck := func(ctx context.Context) error {
+				err := fetchOnce(ctx)
+				if ctx.Err() != nil {
+					return err
+				}
+
+				// check the biome's integrity when a scheduled check is
+				// due, even if some remotes failed to fetch
+				return errors.Join(err, scheduledFsck(ctx, cmd, b))
+			}
+			if fetchWatch {
+				return watch(cmd, fetchInterval, fetchAndCheck)
+			}
+			return fetchAndCheck(ctx)
+		}
+		return fetchOnce(ctx)
 	},
 }
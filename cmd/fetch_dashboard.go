@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// dashboardRefreshInterval is how often the dashboard is redrawn while
+	// git is quiet, so throughput and ETA stay current.
+	dashboardRefreshInterval = time.Second
+
+	// dashboardMaxFailures is the number of failed remotes listed by name.
+	dashboardMaxFailures = 5
+)
+
+// fetchDashboard renders live progress of a `git fetch` of many remotes to a
+// terminal. It follows the lines git prints as it fetches each remote, so it
+// is written to as git's output.
+type fetchDashboard struct {
+	out   io.Writer
+	plan  fetchPlan
+	start time.Time
+	now   func() time.Time
+
+	mu       sync.Mutex
+	partial  []byte
+	fetched  map[string]bool
+	current  string
+	failed   []string
+	rendered int
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// newFetchDashboard starts rendering a dashboard to out, for a fetch of the
+// planned remotes.
+func newFetchDashboard(out io.Writer, plan fetchPlan) *fetchDashboard {
+	d := &fetchDashboard{
+		out:     out,
+		plan:    plan,
+		start:   time.Now(),
+		now:     time.Now,
+		fetched: make(map[string]bool),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go d.refresh()
+	return d
+}
+
+func (d *fetchDashboard) refresh() {
+	defer close(d.done)
+	t := time.NewTicker(dashboardRefreshInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			d.mu.Lock()
+			d.render()
+			d.mu.Unlock()
+		case <-d.stop:
+			return
+		}
+	}
+}
+
+// Write consumes git's output, updating the dashboard for each complete line.
+func (d *fetchDashboard) Write(p []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.partial = append(d.partial, p...)
+	for {
+		i := strings.IndexAny(string(d.partial), "\r\n")
+		if i < 0 {
+			break
+		}
+		d.observe(string(d.partial[:i]))
+		d.partial = d.partial[i+1:]
+	}
+	d.render()
+	return len(p), nil
+}
+
+// Close stops refreshing the dashboard and replaces it with a summary of the
+// fetch.
+func (d *fetchDashboard) Close() error {
+	close(d.stop)
+	<-d.done
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.partial) > 0 {
+		d.observe(string(d.partial))
+		d.partial = nil
+	}
+	d.render()
+	summary := fmt.Sprintf("Fetched %d remotes in %s", len(d.fetched), d.now().Sub(d.start).Round(time.Second))
+	if d.plan.known() {
+		summary += fmt.Sprintf(" (estimated %s)", d.plan.total().Round(time.Second))
+	}
+	_, err := fmt.Fprintf(d.out, "%s, %d failed\n", summary, len(d.failed))
+	return err
+}
+
+// observe updates the fetch progress from a line of git's output.
+func (d *fetchDashboard) observe(line string) {
+	fetching, failed := parseFetchLine(line)
+	if fetching != "" {
+		d.fetched[fetching] = true
+		d.current = fetching
+	}
+	if failed != "" {
+		d.failed = append(d.failed, failed)
+	}
+}
+
+// render redraws the dashboard in place of the previously rendered one.
+func (d *fetchDashboard) render() {
+	if d.rendered > 0 {
+		fmt.Fprintf(d.out, "\x1b[%dA\x1b[J", d.rendered)
+	}
+	lines := d.view(d.now())
+	for _, line := range lines {
+		fmt.Fprintln(d.out, line)
+	}
+	d.rendered = len(lines)
+}
+
+// view returns the lines of the dashboard as of the given time.
+func (d *fetchDashboard) view(now time.Time) []string {
+	elapsed := now.Sub(d.start)
+	fetched, total := len(d.fetched), len(d.plan.remotes)
+	progress := fmt.Sprintf("Fetching remote %d", fetched)
+	if total > 0 {
+		progress = fmt.Sprintf("Fetching remote %d/%d (%d%%)", fetched, total, 100*fetched/total)
+	}
+	if fetched > 0 && elapsed > 0 {
+		rate := float64(fetched) / elapsed.Seconds()
+		progress += fmt.Sprintf("  %.1f remotes/s", rate)
+		if remaining := total - fetched; remaining > 0 {
+			// prefer estimating from how long the remaining remotes took
+			// to fetch previously, over assuming that all remotes take
+			// equally long
+			eta := time.Duration(float64(remaining) / rate * float64(time.Second))
+			if d.plan.known() {
+				eta = d.plan.remaining(d.fetched, elapsed)
+			}
+			progress += fmt.Sprintf("  ETA %s", eta.Round(time.Second))
+		}
+	}
+	lines := []string{
+		progress,
+		fmt.Sprintf("  elapsed: %s", elapsed.Round(time.Second)),
+	}
+	if d.current != "" {
+		lines = append(lines, fmt.Sprintf("  current: %s", d.current))
+	}
+	if len(d.failed) > 0 {
+		lines = append(lines, fmt.Sprintf("  failed:  %d", len(d.failed)))
+		for i, name := range d.failed {
+			if i == dashboardMaxFailures {
+				lines = append(lines, fmt.Sprintf("    ... and %d more", len(d.failed)-i))
+				break
+			}
+			lines = append(lines, fmt.Sprintf("    %s", name))
+		}
+	}
+	return lines
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestFetchDashboard(t *testing.T) {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	now := start.Add(10 * time.Second)
+	d := &fetchDashboard{
+		out:     io.Discard,
+		plan:    newFetchPlan([]string{"github.com/cli/cli", "github.com/git/git", "github.com/orirawlings/bar", "github.com/orirawlings/foo"}, nil),
+		start:   start,
+		fetched: make(map[string]bool),
+		now: func() time.Time {
+			return now
+		},
+	}
+
+	for _, chunk := range []string{
+		"Fetching github.com/cli/cli\nFetching github.com/orirawlings/bar\n",
+		"error: could not fetch 'github.com/orirawlings/bar' (exit code: 128)\nFetch",
+		"ing github.com/git/git\n",
+	} {
+		if _, err := d.Write([]byte(chunk)); err != nil {
+			t.Fatalf("unexpected error writing to dashboard: %v", err)
+		}
+	}
+
+	expected := []string{
+		"Fetching remote 3/4 (75%)  0.3 remotes/s  ETA 3s",
+		"  elapsed: 10s",
+		"  current: github.com/git/git",
+		"  failed:  1",
+		"    github.com/orirawlings/bar",
+	}
+	if view := d.view(now); !slices.Equal(view, expected) {
+		t.Errorf("unexpected dashboard:\nwanted %q\nwas    %q", expected, view)
+	}
+}
+
+func TestFetchDashboard_Close(t *testing.T) {
+	buf := new(bytes.Buffer)
+	d := newFetchDashboard(buf, fetchPlan{})
+	if _, err := d.Write([]byte("Fetching github.com/cli/cli\nerror: could not fetch github.com/cli/cli")); err != nil {
+		t.Fatalf("unexpected error writing to dashboard: %v", err)
+	}
+	if err := d.Close(); err != nil {
+		t.Fatalf("unexpected error closing dashboard: %v", err)
+	}
+	if expected := "Fetched 1 remotes in 0s, 1 failed\n"; !bytes.HasSuffix(buf.Bytes(), []byte(expected)) {
+		t.Errorf("expected summary %q, got %q", expected, buf.String())
+	}
+}
+
+func TestFetchDashboard_estimates(t *testing.T) {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	now := start.Add(10 * time.Second)
+	d := &fetchDashboard{
+		out: io.Discard,
+		plan: newFetchPlan([]string{"github.com/cli/cli", "github.com/git/git"}, map[string]time.Duration{
+			"github.com/cli/cli": 5 * time.Second,
+			"github.com/git/git": 60 * time.Second,
+		}),
+		start:   start,
+		fetched: make(map[string]bool),
+		now: func() time.Time {
+			return now
+		},
+	}
+	if _, err := d.Write([]byte("Fetching github.com/cli/cli\n")); err != nil {
+		t.Fatalf("unexpected error writing to dashboard: %v", err)
+	}
+
+	// cli/cli took twice as long as estimated, so git/git is expected to as
+	// well
+	expected := "Fetching remote 1/2 (50%)  0.1 remotes/s  ETA 2m0s"
+	if view := d.view(now); view[0] != expected {
+		t.Errorf("unexpected dashboard progress: wanted %q, was %q", expected, view[0])
+	}
+}
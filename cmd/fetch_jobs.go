@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+)
+
+// queue orders the remotes to be fetched longest estimate first, so that a
+// pool of git processes starts the longest fetches early, and the processes
+// finish around the same time, even when a single owner's remote group holds
+// most of the remotes.
+func (p fetchPlan) queue() []string {
+	remotes := slices.Clone(p.remotes)
+	slices.SortStableFunc(remotes, func(a, b string) int {
+		return cmp.Compare(p.estimate(b), p.estimate(a))
+	})
+	return remotes
+}
+
+// fetchBatch is a set of remotes fetched by a single git process.
+type fetchBatch struct {
+
+	// jobs is the number of remotes the git process fetches in parallel, or
+	// 0 to let the fetch.parallel setting decide.
+	jobs int
+
+	// remotes to be fetched.
+	remotes []string
+}
+
+// batches splits the remotes to be fetched between git processes, so that
+// no more of each host's remotes are fetched concurrently than the host's
+// limit, if it has one. The remotes of each limited host are fetched by
+// their own git process, as many at a time as the lower of parallel, the
+// fetch.parallel setting, and the host's limit, while the remotes of the
+// other hosts are fetched together.
+func (p fetchPlan) batches(parallel int, limits map[string]int) []fetchBatch {
+	byHost := make(map[string][]string)
+	var unlimited []string
+	for _, remote := range p.remotes {
+		host, _, _ := strings.Cut(remote, "/")
+		if limits[host] > 0 {
+			byHost[host] = append(byHost[host], remote)
+		} else {
+			unlimited = append(unlimited, remote)
+		}
+	}
+
+	var batches []fetchBatch
+	add := func(remotes []string, limit int) {
+		if len(remotes) == 0 {
+			return
+		}
+		if limit > 0 && parallel > 0 {
+			limit = min(parallel, limit)
+		}
+		batches = append(batches, fetchBatch{jobs: limit, remotes: remotes})
+	}
+	for _, host := range slices.Sorted(maps.Keys(byHost)) {
+		add(byHost[host], limits[host])
+	}
+	add(unlimited, 0)
+	return batches
+}
+
+// syncLineWriter writes only complete lines to out, so that the output of
+// concurrent git processes is not interleaved within a line. Writes of all
+// the writers sharing mu are serialized.
+type syncLineWriter struct {
+	mu      *sync.Mutex
+	out     io.Writer
+	partial []byte
+}
+
+func (w *syncLineWriter) Write(p []byte) (int, error) {
+	w.partial = append(w.partial, p...)
+	i := bytes.LastIndexAny(w.partial, "\r\n")
+	if i < 0 {
+		return len(p), nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err := w.out.Write(w.partial[:i+1])
+	w.partial = slices.Clone(w.partial[i+1:])
+	return len(p), err
+}
+
+// Flush writes any incomplete line that remains.
+func (w *syncLineWriter) Flush() error {
+	if len(w.partial) == 0 {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err := w.out.Write(w.partial)
+	w.partial = nil
+	return err
+}
+
+// fetchRun runs one or more git fetch processes concurrently, timing the
+// fetch of each remote from their output.
+type fetchRun struct {
+	cmds      []*exec.Cmd
+	recorders []*fetchRecorder
+
+	// remotes, if set, is the single remote fetched by each git process,
+	// which git does not report, so each remote is timed from the start of
+	// its process until it exits.
+	remotes []string
+
+	// slots, if set, bounds how many of the git processes run at once.
+	slots *fetchSlots
+
+	// apply, if set, applies the reference updates that the git process
+	// fetching each remote reports on its standard output, rather than
+	// copying the output, see [pooledFetchRun]. It is called concurrently
+	// for different remotes, so it must serialize the updates, see
+	// [refQueue].
+	apply func(remote string, out []byte) error
+}
+
+func newFetchRun(cmds []*exec.Cmd) *fetchRun {
+	r := &fetchRun{
+		cmds: cmds,
+	}
+	for range cmds {
+		r.recorders = append(r.recorders, newFetchRecorder())
+	}
+	return r
+}
+
+// run runs the git processes to completion, copying their output to stdout
+// and stderr.
+func (r *fetchRun) run(stdout, stderr io.Writer) error {
+	var mu sync.Mutex
+	errs := make([]error, len(r.cmds))
+	var wg sync.WaitGroup
+	for i, c := range r.cmds {
+		outLines, errLines := &syncLineWriter{mu: &mu, out: stdout}, &syncLineWriter{mu: &mu, out: stderr}
+		c.Stdout, c.Stderr = r.recorders[i].stream(outLines), r.recorders[i].stream(errLines)
+		var updates bytes.Buffer
+		if r.apply != nil {
+			c.Stdout = &updates
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if r.remotes != nil {
+				if r.slots != nil {
+					defer r.slots.acquire(r.remotes[i])()
+				}
+				r.recorders[i].begin(r.remotes[i])
+
+				// report progress, as a git process fetching many remotes
+				// does
+				fmt.Fprintf(errLines, "Fetching %s\n", r.remotes[i])
+			}
+			if runErr := c.Run(); runErr != nil {
+				errs[i] = fmt.Errorf("could not %q: %w", c, runErr)
+			} else if r.apply != nil {
+				errs[i] = r.apply(r.remotes[i], updates.Bytes())
+			}
+			if errs[i] != nil && r.remotes != nil {
+				r.recorders[i].fail(r.remotes[i])
+				fmt.Fprintf(errLines, "error: could not fetch %s\n", r.remotes[i])
+			}
+			errs[i] = errors.Join(errs[i], outLines.Flush(), errLines.Flush())
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// fetched returns the number of remotes that the git processes have started
+// fetching.
+func (r *fetchRun) fetched() int {
+	var n int
+	for _, recorder := range r.recorders {
+		n += recorder.fetched()
+	}
+	return n
+}
+
+// finish stops timing the fetch and returns a journal entry for each remote
+// that was fetched, see [fetchRecorder.finish].
+func (r *fetchRun) finish(interrupted bool) []biome.JournalEntry {
+	var entries []biome.JournalEntry
+	for _, recorder := range r.recorders {
+		entries = append(entries, recorder.finish(interrupted)...)
+	}
+	return entries
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os/exec"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFetchPlan_queue(t *testing.T) {
+	plan := newFetchPlan(
+		[]string{"a", "b", "c", "d", "e"},
+		map[string]time.Duration{
+			"a": 3 * time.Second,
+			"b": 8 * time.Second,
+			"c": 1 * time.Second,
+			"d": 4 * time.Second,
+		},
+	)
+
+	// e is assumed to take as long as the average, 4s
+	if queue, expected := plan.queue(), []string{"b", "d", "e", "a", "c"}; !slices.Equal(queue, expected) {
+		t.Errorf("expected queue %q, was %q", expected, queue)
+	}
+	if queue := newFetchPlan(nil, nil).queue(); len(queue) != 0 {
+		t.Errorf("expected empty queue without remotes, was %q", queue)
+	}
+}
+
+func TestFetchPlan_batches(t *testing.T) {
+	plan := newFetchPlan(
+		[]string{
+			"github.com/cli/cli",
+			"ghes.example.com/a/x",
+			"github.com/cli/go-gh",
+			"ghes.example.com/a/y",
+			"ghes.example.com/b/z",
+			"my.github.biz/foo/bar",
+		},
+		map[string]time.Duration{
+			"ghes.example.com/a/x": 3 * time.Second,
+			"ghes.example.com/a/y": 2 * time.Second,
+			"ghes.example.com/b/z": 1 * time.Second,
+		},
+	)
+	limits := map[string]int{
+		"ghes.example.com": 2,
+		"my.github.biz":    4,
+	}
+	for _, tc := range []struct {
+		name     string
+		parallel int
+		expected []fetchBatch
+	}{
+		{
+			name:     "parallel above limits",
+			parallel: 3,
+			expected: []fetchBatch{
+				{jobs: 2, remotes: []string{"ghes.example.com/a/x", "ghes.example.com/a/y", "ghes.example.com/b/z"}},
+				{jobs: 3, remotes: []string{"my.github.biz/foo/bar"}},
+				{remotes: []string{"github.com/cli/cli", "github.com/cli/go-gh"}},
+			},
+		},
+		{
+			name: "default parallel",
+			expected: []fetchBatch{
+				{jobs: 2, remotes: []string{"ghes.example.com/a/x", "ghes.example.com/a/y", "ghes.example.com/b/z"}},
+				{jobs: 4, remotes: []string{"my.github.biz/foo/bar"}},
+				{remotes: []string{"github.com/cli/cli", "github.com/cli/go-gh"}},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if batches := plan.batches(tc.parallel, limits); !reflect.DeepEqual(batches, tc.expected) {
+				t.Errorf("unexpected batches:\nwanted %+v\nwas    %+v", tc.expected, batches)
+			}
+		})
+	}
+}
+
+func TestSyncLineWriter(t *testing.T) {
+	var mu sync.Mutex
+	out := new(bytes.Buffer)
+	a, b := &syncLineWriter{mu: &mu, out: out}, &syncLineWriter{mu: &mu, out: out}
+
+	write := func(w io.Writer, s string) {
+		t.Helper()
+		if _, err := io.WriteString(w, s); err != nil {
+			t.Fatalf("unexpected error writing: %v", err)
+		}
+	}
+	write(a, "Fetching github.com/")
+	write(b, "Fetching github.com/git/git\n")
+	write(a, "cli/cli\nremote: Counting")
+	write(b, "progress 50%\r")
+	if err := a.Flush(); err != nil {
+		t.Fatalf("unexpected error flushing: %v", err)
+	}
+
+	expected := "Fetching github.com/git/git\nFetching github.com/cli/cli\nprogress 50%\rremote: Counting"
+	if out.String() != expected {
+		t.Errorf("expected %q, was %q", expected, out.String())
+	}
+}
+
+func TestFetchRun(t *testing.T) {
+	run := newFetchRun([]*exec.Cmd{
+		exec.Command("sh", "-c", "echo Fetching github.com/cli/cli; echo Fetching github.com/cli/go-gh"),
+		exec.Command("sh", "-c", "echo Fetching github.com/git/git; echo 'error: could not fetch github.com/git/git' >&2; exit 1"),
+	})
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	err := run.run(stdout, stderr)
+	if err == nil || !strings.Contains(err.Error(), "exit status 1") {
+		t.Errorf("expected the failed git process to be reported, was %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	slices.Sort(lines)
+	expected := []string{
+		"Fetching github.com/cli/cli",
+		"Fetching github.com/cli/go-gh",
+		"Fetching github.com/git/git",
+	}
+	if !slices.Equal(lines, expected) {
+		t.Errorf("expected output %q, was %q", expected, lines)
+	}
+	if n := run.fetched(); n != 3 {
+		t.Errorf("expected 3 remotes fetched, was %d", n)
+	}
+
+	var failed []string
+	for _, e := range run.finish(false) {
+		if e.Failed {
+			failed = append(failed, e.Remote)
+		}
+	}
+	if !slices.Equal(failed, []string{"github.com/git/git"}) {
+		t.Errorf("expected github.com/git/git to be recorded as failed, was %q", failed)
+	}
+}
@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+)
+
+// fetchErrorLines is the number of git's most recent error messages that are
+// recorded in the journal when a remote fails to fetch.
+const fetchErrorLines = 5
+
+// parseFetchLine recognizes the lines git prints as it fetches many remotes,
+// returning the name of the remote that git started fetching, or the name of
+// the remote that failed to fetch.
+func parseFetchLine(line string) (fetching, failed string) {
+	if name, ok := strings.CutPrefix(line, "Fetching "); ok {
+		return name, ""
+	}
+	if name, ok := strings.CutPrefix(line, "error: could not fetch "); ok {
+		// parallel fetches report ex. `could not fetch 'name' (exit code: 1)`
+		name, _, _ = strings.Cut(name, " (exit code")
+		return "", strings.Trim(name, "'")
+	}
+	return "", ""
+}
+
+// fetchRecorder times the fetch of each remote from git's output, so the
+// durations can be recorded in the biome's journal. A remote's duration is
+// the time from git reporting that it started fetching the remote until git
+// reports the next remote. When remotes are fetched in parallel, this
+// measures each remote's share of the overall fetch, rather than the time
+// spent fetching it alone.
+type fetchRecorder struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	current string
+	started time.Time
+	entries []biome.JournalEntry
+	failed  map[string]bool
+
+	// errorLines are git's error messages since the last remote failed or
+	// started fetching, and errors are the messages that preceded the
+	// failure of each remote.
+	errorLines []string
+	errors     map[string]string
+}
+
+func newFetchRecorder() *fetchRecorder {
+	return &fetchRecorder{
+		now:    time.Now,
+		failed: make(map[string]bool),
+		errors: make(map[string]string),
+	}
+}
+
+// stream returns a writer for one of git's output streams that copies the
+// output to out while timing the fetch.
+func (r *fetchRecorder) stream(out io.Writer) io.Writer {
+	return &fetchRecorderStream{
+		recorder: r,
+		out:      out,
+	}
+}
+
+func (r *fetchRecorder) observe(line string) {
+	fetching, failed := parseFetchLine(line)
+	if failed != "" {
+		r.markFailed(failed)
+		return
+	}
+	if fetching != "" {
+		r.finishCurrent()
+		r.current = fetching
+		r.started = r.now()
+		r.errorLines = nil
+		return
+	}
+	if strings.HasPrefix(line, "fatal: ") || strings.HasPrefix(line, "error: ") {
+		r.errorLines = append(r.errorLines, line)
+		if len(r.errorLines) > fetchErrorLines {
+			r.errorLines = r.errorLines[1:]
+		}
+	}
+}
+
+// markFailed records that the fetch of the remote failed, attributing git's
+// latest error messages to it.
+func (r *fetchRecorder) markFailed(remote string) {
+	r.failed[remote] = true
+	if len(r.errorLines) > 0 {
+		r.errors[remote] = strings.Join(r.errorLines, "\n")
+		r.errorLines = nil
+	}
+}
+
+func (r *fetchRecorder) finishCurrent() {
+	if r.current == "" {
+		return
+	}
+	r.entries = append(r.entries, biome.JournalEntry{
+		Time:     r.started,
+		Op:       biome.FetchOp,
+		Remote:   r.current,
+		Duration: r.now().Sub(r.started),
+	})
+	r.current = ""
+}
+
+// begin starts timing the fetch of a remote that git does not report, ex.
+// when a git process fetches a single remote.
+func (r *fetchRecorder) begin(remote string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.observe("Fetching " + remote)
+}
+
+// fail records that the fetch of a remote failed, ex. when the git process
+// fetching a single remote exits with an error.
+func (r *fetchRecorder) fail(remote string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.markFailed(remote)
+}
+
+// fetched returns the number of remotes that git has started fetching.
+func (r *fetchRecorder) fetched() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := len(r.entries)
+	if r.current != "" {
+		n++
+	}
+	return n
+}
+
+// finish stops timing the fetch and returns a journal entry for each remote
+// that was fetched. If interrupted, the remote being fetched when the fetch
+// was interrupted is recorded as failed.
+func (r *fetchRecorder) finish(interrupted bool) []biome.JournalEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if interrupted {
+		r.failed[r.current] = true
+	}
+	r.finishCurrent()
+	for i, e := range r.entries {
+		if !r.failed[e.Remote] {
+			continue
+		}
+		r.entries[i].Failed = true
+		if msg := r.errors[e.Remote]; msg != "" {
+			r.entries[i].Error = msg
+			r.entries[i].ErrorClass = biome.ClassifyFetchError(msg)
+		}
+	}
+	return r.entries
+}
+
+// fetchRecorderStream splits one of git's output streams into lines for a
+// [fetchRecorder].
+type fetchRecorderStream struct {
+	recorder *fetchRecorder
+	out      io.Writer
+	partial  []byte
+}
+
+func (s *fetchRecorderStream) Write(p []byte) (int, error) {
+	n, err := s.out.Write(p)
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	s.partial = append(s.partial, p[:n]...)
+	for {
+		i := strings.IndexAny(string(s.partial), "\r\n")
+		if i < 0 {
+			break
+		}
+		s.recorder.observe(string(s.partial[:i]))
+		s.partial = s.partial[i+1:]
+	}
+	return n, err
+}
+
+// fetchPlan estimates how long it will take to fetch remotes, from how long
+// each remote took to fetch previously.
+type fetchPlan struct {
+
+	// remotes to be fetched.
+	remotes []string
+
+	// estimates of how long each remote takes to fetch, see
+	// [biome.FetchEstimates].
+	estimates map[string]time.Duration
+
+	// average of the estimates, assumed for remotes without an estimate.
+	average time.Duration
+}
+
+func newFetchPlan(remotes []string, estimates map[string]time.Duration) fetchPlan {
+	p := fetchPlan{
+		remotes:   remotes,
+		estimates: estimates,
+	}
+	if len(estimates) > 0 {
+		var total time.Duration
+		for _, d := range estimates {
+			total += d
+		}
+		p.average = total / time.Duration(len(estimates))
+	}
+	return p
+}
+
+// known returns true if any remote to be fetched has been fetched before.
+func (p fetchPlan) known() bool {
+	for _, remote := range p.remotes {
+		if _, ok := p.estimates[remote]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// estimate returns the estimated duration of fetching the remote. Remotes
+// that have not been fetched before are assumed to take as long as the
+// average remote that has.
+func (p fetchPlan) estimate(remote string) time.Duration {
+	if d, ok := p.estimates[remote]; ok {
+		return d
+	}
+	return p.average
+}
+
+// total returns the estimated duration of the entire fetch.
+func (p fetchPlan) total() time.Duration {
+	var total time.Duration
+	for _, remote := range p.remotes {
+		total += p.estimate(remote)
+	}
+	return total
+}
+
+// remaining returns the estimated time remaining, given the remotes that
+// have been fetched so far and the time elapsed. The estimate is scaled by
+// how much faster or slower the fetch is running than estimated.
+func (p fetchPlan) remaining(fetched map[string]bool, elapsed time.Duration) time.Duration {
+	var done, left time.Duration
+	for _, remote := range p.remotes {
+		if fetched[remote] {
+			done += p.estimate(remote)
+		} else {
+			left += p.estimate(remote)
+		}
+	}
+	if done > 0 && elapsed > 0 {
+		left = time.Duration(float64(left) * float64(elapsed) / float64(done))
+	}
+	return left
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+)
+
+func TestParseFetchLine(t *testing.T) {
+	for _, tc := range []struct {
+		line     string
+		fetching string
+		failed   string
+	}{
+		{"Fetching github.com/cli/cli", "github.com/cli/cli", ""},
+		{"error: could not fetch github.com/cli/cli", "", "github.com/cli/cli"},
+		{"error: could not fetch 'github.com/cli/cli' (exit code: 128)", "", "github.com/cli/cli"},
+		{"From https://github.com/cli/cli", "", ""},
+	} {
+		t.Run(tc.line, func(t *testing.T) {
+			fetching, failed := parseFetchLine(tc.line)
+			if fetching != tc.fetching || failed != tc.failed {
+				t.Errorf("unexpected parse: wanted (%q, %q), was (%q, %q)", tc.fetching, tc.failed, fetching, failed)
+			}
+		})
+	}
+}
+
+func TestFetchRecorder(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	r := newFetchRecorder()
+	r.now = func() time.Time {
+		return now
+	}
+	stdout, stderr := r.stream(io.Discard), r.stream(io.Discard)
+
+	write := func(w io.Writer, s string) {
+		t.Helper()
+		if _, err := io.WriteString(w, s); err != nil {
+			t.Fatalf("unexpected error writing: %v", err)
+		}
+	}
+	write(stdout, "Fetching github.com/cli/cli\n")
+	now = now.Add(2 * time.Second)
+	write(stdout, "Fetching github.com/git/")
+	write(stderr, "error: could not fetch github.com/cli/cli\n")
+	write(stdout, "git\n")
+	now = now.Add(3 * time.Second)
+
+	if n := r.fetched(); n != 2 {
+		t.Errorf("expected 2 remotes fetched, was %d", n)
+	}
+	expected := []biome.JournalEntry{
+		{
+			Time:     now.Add(-5 * time.Second),
+			Op:       biome.FetchOp,
+			Remote:   "github.com/cli/cli",
+			Duration: 2 * time.Second,
+			Failed:   true,
+		},
+		{
+			Time:     now.Add(-3 * time.Second),
+			Op:       biome.FetchOp,
+			Remote:   "github.com/git/git",
+			Duration: 3 * time.Second,
+			Failed:   true,
+		},
+	}
+	if entries := r.finish(true); !slices.Equal(entries, expected) {
+		t.Errorf("unexpected journal entries:\nwanted %v\nwas    %v", expected, entries)
+	}
+}
+
+func TestFetchRecorder_errors(t *testing.T) {
+	r := newFetchRecorder()
+	stderr := r.stream(io.Discard)
+	for _, line := range []string{
+		"Fetching github.com/cli/gone",
+		"remote: Repository not found.",
+		"fatal: repository 'https://github.com/cli/gone.git/' not found",
+		"error: could not fetch github.com/cli/gone",
+		"Fetching github.com/cli/cli",
+		"Fetching github.com/cli/slow",
+		"error: RPC failed; curl 28 Operation too slow",
+		"fatal: expected flush after ref listing",
+	} {
+		if _, err := io.WriteString(stderr, line+"\n"); err != nil {
+			t.Fatalf("unexpected error writing: %v", err)
+		}
+	}
+	r.fail("github.com/cli/slow")
+
+	var failures []biome.JournalEntry
+	for _, e := range r.finish(false) {
+		if e.Failed {
+			failures = append(failures, biome.JournalEntry{Remote: e.Remote, Error: e.Error, ErrorClass: e.ErrorClass})
+		}
+	}
+	expected := []biome.JournalEntry{
+		{
+			Remote:     "github.com/cli/gone",
+			Error:      "fatal: repository 'https://github.com/cli/gone.git/' not found",
+			ErrorClass: biome.NotFoundError,
+		},
+		{
+			Remote:     "github.com/cli/slow",
+			Error:      "error: RPC failed; curl 28 Operation too slow\nfatal: expected flush after ref listing",
+			ErrorClass: biome.TimeoutError,
+		},
+	}
+	if !slices.Equal(failures, expected) {
+		t.Errorf("unexpected failures:\nwanted %+v\nwas    %+v", expected, failures)
+	}
+}
+
+func TestFetchPlan(t *testing.T) {
+	p := newFetchPlan([]string{"a", "b", "c"}, map[string]time.Duration{
+		"a": 10 * time.Second,
+		"b": 30 * time.Second,
+		"z": 50 * time.Second,
+	})
+	if !p.known() {
+		t.Errorf("expected plan to be known")
+	}
+
+	// c has not been fetched before, so it is assumed to take as long as
+	// the average remote
+	if total, expected := p.total(), 70*time.Second; total != expected {
+		t.Errorf("unexpected total: wanted %s, was %s", expected, total)
+	}
+
+	// a took half as long as estimated, so the rest are expected to as well
+	if remaining, expected := p.remaining(map[string]bool{"a": true}, 5*time.Second), 30*time.Second; remaining != expected {
+		t.Errorf("unexpected remaining: wanted %s, was %s", expected, remaining)
+	}
+
+	if newFetchPlan([]string{"a"}, nil).known() {
+		t.Errorf("expected plan without estimates to be unknown")
+	}
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// porcelainUpdate is a reference update reported by `git fetch --porcelain`,
+// see git-fetch(1).
+type porcelainUpdate struct {
+
+	// flag is the kind of update, ex. '*' for a new reference, '+' for a
+	// forced update, or '-' for a pruned reference.
+	flag byte
+
+	// old and new are the object IDs the reference pointed to before and
+	// after the update. The zero object ID stands for a reference that does
+	// not exist.
+	old, new string
+
+	// ref is the full name of the local reference.
+	ref string
+}
+
+// parsePorcelain parses the reference updates that `git fetch --porcelain`
+// prints, one per line: `<flag> <old-object-id> <new-object-id> <local-ref>`.
+func parsePorcelain(out []byte) ([]porcelainUpdate, error) {
+	var updates []porcelainUpdate
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line[1:])
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("could not parse fetch output: %q", line)
+		}
+		updates = append(updates, porcelainUpdate{
+			flag: line[0],
+			old:  fields[0],
+			new:  fields[1],
+			ref:  fields[2],
+		})
+	}
+	return updates, scanner.Err()
+}
+
+// refUpdateCommands returns the `git update-ref --stdin` commands that apply
+// the reference updates, verifying that each reference still points to its
+// old object ID. Rejected updates, and references that are up to date, are
+// left as they are.
+func refUpdateCommands(updates []porcelainUpdate) string {
+	var b strings.Builder
+	for _, u := range updates {
+		switch u.flag {
+		case ' ', '+', '*', 't':
+			fmt.Fprintf(&b, "update %s %s %s\n", u.ref, u.new, u.old)
+		case '-':
+			fmt.Fprintf(&b, "delete %s %s\n", u.ref, u.old)
+		}
+	}
+	return b.String()
+}
+
+// pooledFetchRun returns a run of one git process per remote, as many at a
+// time as n, and no more of each host's remotes than the host's limit, if it
+// has one. Remotes are started longest estimate first, so that the processes
+// finish around the same time. Each process only receives the remote's
+// objects, reporting the reference updates it would make, which are then
+// applied one remote at a time through the queue, since concurrent reference
+// updates may fail, ex. when two processes both need to rewrite packed-refs,
+// or with the reftable backend. If updateRefs is false, ex. while objects are
+// received into a quarantine, the updates are held in the queue until they
+// are released.
+func pooledFetchRun(ctx context.Context, refs *refQueue, plan fetchPlan, n int, limits map[string]int, updateRefs bool, gitFetch func(...string) *exec.Cmd) *fetchRun {
+	remotes := plan.queue()
+	var cmds []*exec.Cmd
+	for _, remote := range remotes {
+		cmds = append(cmds, gitFetch("--porcelain", "--dry-run", "--no-write-fetch-head", remote))
+	}
+	run := newFetchRun(cmds)
+	run.remotes = remotes
+	run.slots = newFetchSlots(n, limits)
+	run.apply = refs.applier(ctx, updateRefs)
+	return run
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestParsePorcelain(t *testing.T) {
+	const (
+		zero = "0000000000000000000000000000000000000000"
+		a    = "1111111111111111111111111111111111111111"
+		b    = "2222222222222222222222222222222222222222"
+	)
+	out := strings.Join([]string{
+		"* " + zero + " " + a + " refs/remotes/github.com/cli/cli/heads/new",
+		"  " + a + " " + b + " refs/remotes/github.com/cli/cli/heads/main",
+		"+ " + b + " " + a + " refs/remotes/github.com/cli/cli/heads/rewritten",
+		"t " + a + " " + b + " refs/remotes/github.com/cli/cli/tags/v1",
+		"- " + a + " " + zero + " refs/remotes/github.com/cli/cli/heads/gone",
+		"= " + a + " " + a + " refs/remotes/github.com/cli/cli/heads/same",
+		"! " + a + " " + b + " refs/remotes/github.com/cli/cli/heads/rejected",
+		"",
+	}, "\n")
+	updates, err := parsePorcelain([]byte(out))
+	testutil.Check(t, err)
+	if len(updates) != 7 {
+		t.Fatalf("expected 7 updates, was %+v", updates)
+	}
+	if expected := (porcelainUpdate{flag: ' ', old: a, new: b, ref: "refs/remotes/github.com/cli/cli/heads/main"}); !reflect.DeepEqual(updates[1], expected) {
+		t.Errorf("expected %+v, was %+v", expected, updates[1])
+	}
+
+	expected := strings.Join([]string{
+		"update refs/remotes/github.com/cli/cli/heads/new " + a + " " + zero,
+		"update refs/remotes/github.com/cli/cli/heads/main " + b + " " + a,
+		"update refs/remotes/github.com/cli/cli/heads/rewritten " + a + " " + b,
+		"update refs/remotes/github.com/cli/cli/tags/v1 " + b + " " + a,
+		"delete refs/remotes/github.com/cli/cli/heads/gone " + a,
+		"",
+	}, "\n")
+	if commands := refUpdateCommands(updates); commands != expected {
+		t.Errorf("expected commands:\n%s\nwas:\n%s", expected, commands)
+	}
+
+	if _, err := parsePorcelain([]byte("From https://github.com/cli/cli\n")); err == nil {
+		t.Errorf("expected error for output that is not porcelain")
+	}
+}
+
+func TestPooledFetchRun(t *testing.T) {
+	initBiome(t)
+	ctx := context.Background()
+	b, err := load(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error loading biome: %v", err)
+	}
+	tree := strings.TrimSpace(testutil.Execute(t, "git", "write-tree"))
+	commit := strings.TrimSpace(testutil.Execute(t, "git", "-c", "user.name=A", "-c", "user.email=a@example.com", "commit-tree", "-m", "base", tree))
+	const zero = "0000000000000000000000000000000000000000"
+
+	// each pretend fetch reports the creation of the remote's main branch,
+	// except for the remote that fails to fetch
+	plan := newFetchPlan([]string{"github.com/cli/cli", "github.com/git/git", "github.com/cli/broken"}, nil)
+	gitFetch := func(args ...string) *exec.Cmd {
+		remote := args[len(args)-1]
+		if strings.HasSuffix(remote, "broken") {
+			return exec.Command("sh", "-c", "echo 'fatal: repository not found' >&2; exit 128")
+		}
+		return exec.Command("echo", fmt.Sprintf("* %s %s refs/remotes/%s/heads/main", zero, commit, remote))
+	}
+
+	for _, updateRefs := range []bool{false, true} {
+		run := pooledFetchRun(ctx, newRefQueue(b.Path()), plan, 2, nil, updateRefs, gitFetch)
+		stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+		if err := run.run(stdout, stderr); err == nil || !strings.Contains(err.Error(), "exit status 128") {
+			t.Errorf("expected the failed git process to be reported, was %v", err)
+		}
+		if stdout.Len() > 0 {
+			t.Errorf("expected reference updates not to be printed, was %q", stdout.String())
+		}
+		for _, expected := range []string{
+			"Fetching github.com/cli/cli\n",
+			"fatal: repository not found\n",
+			"error: could not fetch github.com/cli/broken\n",
+		} {
+			if !strings.Contains(stderr.String(), expected) {
+				t.Errorf("expected stderr to contain %q, was %q", expected, stderr.String())
+			}
+		}
+
+		refs := strings.TrimSpace(testutil.Execute(t, "git", "for-each-ref", "--format=%(refname) %(objectname)", "refs/remotes/"))
+		expected := ""
+		if updateRefs {
+			expected = fmt.Sprintf("refs/remotes/github.com/cli/cli/heads/main %s\nrefs/remotes/github.com/git/git/heads/main %s", commit, commit)
+		}
+		if refs != expected {
+			t.Errorf("expected references %q with updateRefs %t, was %q", expected, updateRefs, refs)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	"github.com/spf13/cobra"
+)
+
+// protectedRefs returns the protected references of the biome's remotes,
+// matching the biome.fetch.protectedRefs setting, to restore after a fetch.
+// It returns nothing with --allow-protected-deletions, so that the fetch
+// may delete them.
+func protectedRefs(ctx context.Context, b biome.Biome) (map[string]string, error) {
+	if fetchAllowProtectedDeletions {
+		return nil, nil
+	}
+	return b.ProtectedRefs(ctx)
+}
+
+// restoreProtectedRefs restores the protected references deleted by a
+// fetch, and warns about each, since their deletion upstream may be an
+// accident.
+func restoreProtectedRefs(ctx context.Context, cmd *cobra.Command, b biome.Biome, protected map[string]string) error {
+	restored, err := b.RestoreProtectedRefs(ctx, protected)
+	for _, r := range restored {
+		cmd.PrintErrf("Warning: kept %s %s at %s, which no longer exists upstream. Fetch with --allow-protected-deletions to delete it.\n", r.Remote, r.Ref, r.Commit)
+	}
+	if err != nil {
+		return fmt.Errorf("could not restore protected references: %w", err)
+	}
+	return nil
+}
+
+// fetchKeepingProtectedRefs runs fetch, and then restores the protected
+// references it deleted, even if some remotes failed to fetch. They are
+// restored even if ctx is cancelled, ex. by an interrupt, since the remotes
+// fetched before the interruption may have pruned theirs.
+func fetchKeepingProtectedRefs(ctx context.Context, cmd *cobra.Command, b biome.Biome, protected map[string]string, fetch func() error) error {
+	err := fetch()
+	return errors.Join(err, restoreProtectedRefs(context.WithoutCancel(ctx), cmd, b, protected))
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+	"github.com/spf13/cobra"
+)
+
+func TestRestoreProtectedRefs(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_orirawlings.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	tree := strings.TrimSpace(testutil.Execute(t, "git", "write-tree"))
+	commit := strings.TrimSpace(testutil.Execute(t, "git", "-c", "user.name=A", "-c", "user.email=a@example.com", "commit-tree", "-m", "main", tree))
+	testutil.Execute(t, "git", "update-ref", "refs/remotes/github.com/orirawlings/bar/heads/main", commit)
+
+	ctx := context.Background()
+	b, err := load(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error loading biome: %v", err)
+	}
+	if err := b.SetSetting(ctx, "biome.fetch.protectedRefs", "heads/main"); err != nil {
+		t.Fatalf("unexpected error setting protected references: %v", err)
+	}
+
+	// deletions are let through with --allow-protected-deletions
+	fetchAllowProtectedDeletions = true
+	t.Cleanup(func() {
+		fetchAllowProtectedDeletions = false
+	})
+	if protected, err := protectedRefs(ctx, b); err != nil || len(protected) != 0 {
+		t.Errorf("expected no protected references with --allow-protected-deletions, was %v, %v", protected, err)
+	}
+
+	fetchAllowProtectedDeletions = false
+	protected, err := protectedRefs(ctx, b)
+	testutil.Check(t, err)
+	testutil.Execute(t, "git", "update-ref", "-d", "refs/remotes/github.com/orirawlings/bar/heads/main")
+
+	cmd := &cobra.Command{}
+	buf := new(bytes.Buffer)
+	cmd.SetErr(buf)
+	testutil.Check(t, restoreProtectedRefs(ctx, cmd, b, protected))
+	if expected := "Warning: kept github.com/orirawlings/bar heads/main at " + commit + ", which no longer exists upstream. Fetch with --allow-protected-deletions to delete it.\n"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+	if actual := strings.TrimSpace(testutil.Execute(t, "git", "rev-parse", "refs/remotes/github.com/orirawlings/bar/heads/main")); actual != commit {
+		t.Errorf("expected heads/main to be restored at %s, was %s", commit, actual)
+	}
+
+	// protected references are restored after an interrupted fetch
+	fetchCtx, cancel := context.WithCancel(ctx)
+	interrupted := errors.New("pretend the fetch was interrupted")
+	buf.Reset()
+	err = fetchKeepingProtectedRefs(fetchCtx, cmd, b, protected, func() error {
+		testutil.Execute(t, "git", "update-ref", "-d", "refs/remotes/github.com/orirawlings/bar/heads/main")
+		cancel()
+		return interrupted
+	})
+	if !errors.Is(err, interrupted) {
+		t.Errorf("expected %v, was %v", interrupted, err)
+	}
+	if actual := strings.TrimSpace(testutil.Execute(t, "git", "rev-parse", "refs/remotes/github.com/orirawlings/bar/heads/main")); actual != commit {
+		t.Errorf("expected heads/main to be restored at %s after an interrupted fetch, was %s", commit, actual)
+	}
+}
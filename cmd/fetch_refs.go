@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+)
+
+// fetchRefPatterns returns the patterns of the remote references to fetch,
+// relative to refs/, ex. heads/*, given with --refs, --heads-only, or
+// --tags-only. If none were given, all references are fetched, as the remotes'
+// configured refspecs specify.
+func fetchRefPatterns() ([]string, error) {
+	var patterns []string
+	for _, pattern := range fetchRefs {
+		pattern = strings.TrimPrefix(pattern, "refs/")
+		if pattern == "" || strings.Count(pattern, "*") > 1 {
+			return nil, fmt.Errorf("invalid --refs: %q: must be a reference pattern with at most one *, ex. heads/*", pattern)
+		}
+		patterns = append(patterns, pattern)
+	}
+	if fetchHeadsOnly {
+		patterns = append(patterns, "heads/*")
+	}
+	if fetchTagsOnly {
+		patterns = append(patterns, "tags/*")
+	}
+	slices.Sort(patterns)
+	return slices.Compact(patterns), nil
+}
+
+// narrowedRefspecs returns the refspecs that fetch only the remote references
+// matching the given patterns into a remote's reference namespace.
+func narrowedRefspecs(namespace string, patterns []string) []string {
+	var refspecs []string
+	for _, pattern := range patterns {
+		refspecs = append(refspecs, fmt.Sprintf("+refs/%s:%s%s", pattern, namespace, pattern))
+	}
+	return refspecs
+}
+
+// narrowedFetchRun returns a run of one git process per remote, fetching
+// only the remote references matching the given patterns, since git only
+// accepts refspecs on the command line when fetching a single remote. As many
+// processes run at once as [fetchProcesses] allows, and no more of each host's
+// remotes than the host's limit, if it has one. If refs is set, ex. with the
+// reftable backend or a quarantine, the processes only receive objects, and
+// their reference updates are applied, or held, through the queue, see
+// [pooledFetchRun].
+func narrowedFetchRun(ctx context.Context, b biome.Biome, plan fetchPlan, patterns []string, limits map[string]int, refs *refQueue, updateRefs bool, gitFetch func(...string) *exec.Cmd) (*fetchRun, error) {
+	remotes, err := b.Remotes(ctx, biome.FetchableRemoteCategories...)
+	if err != nil {
+		return nil, err
+	}
+	namespaces := make(map[string]string)
+	for _, r := range remotes {
+		namespaces[r.Name] = r.RefNamespace()
+	}
+	n, err := fetchProcesses(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+
+	var cmds []*exec.Cmd
+	var fetched []string
+	for _, remote := range plan.remotes {
+		namespace, ok := namespaces[remote]
+		if !ok {
+			continue
+		}
+		args := []string{"--no-write-fetch-head"}
+		if refs != nil {
+			args = append(args, "--porcelain", "--dry-run")
+		}
+		args = append(append(args, remote), narrowedRefspecs(namespace, patterns)...)
+		cmds = append(cmds, gitFetch(args...))
+		fetched = append(fetched, remote)
+	}
+	run := newFetchRun(cmds)
+	run.remotes = fetched
+	run.slots = newFetchSlots(n, limits)
+	if refs != nil {
+		run.apply = refs.applier(ctx, updateRefs)
+	}
+	return run, nil
+}
+
+// fetchProcesses returns how many git processes, each fetching a single
+// remote, run at once: --jobs, or else the fetch.parallel setting, or else
+// the number of CPUs.
+func fetchProcesses(ctx context.Context, b biome.Biome) (int, error) {
+	if fetchJobs > 0 {
+		return fetchJobs, nil
+	}
+	parallel, err := b.GetSetting(ctx, "fetch.parallel")
+	if err != nil {
+		return 0, err
+	}
+	if n, _ := strconv.Atoi(parallel.Value); n > 0 {
+		return n, nil
+	}
+	return runtime.NumCPU(), nil
+}
+
+// fetchSlots bounds how many git processes run at once, overall and for each
+// host's remotes.
+type fetchSlots struct {
+	all   chan struct{}
+	hosts map[string]chan struct{}
+}
+
+// newFetchSlots returns slots for n git processes at once, and no more than a
+// host's limit of the host's remotes.
+func newFetchSlots(n int, limits map[string]int) *fetchSlots {
+	s := &fetchSlots{
+		all:   make(chan struct{}, n),
+		hosts: make(map[string]chan struct{}),
+	}
+	for host, limit := range limits {
+		s.hosts[host] = make(chan struct{}, limit)
+	}
+	return s
+}
+
+// acquire waits for a slot to fetch the remote, returning a function that
+// releases the slot.
+func (s *fetchSlots) acquire(remote string) func() {
+	host, _, _ := strings.Cut(remote, "/")
+	hostSlots := s.hosts[host]
+	if hostSlots != nil {
+		hostSlots <- struct{}{}
+	}
+	s.all <- struct{}{}
+	return func() {
+		<-s.all
+		if hostSlots != nil {
+			<-hostSlots
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestFetchRefPatterns(t *testing.T) {
+	t.Cleanup(func() {
+		fetchRefs = nil
+		fetchHeadsOnly = false
+		fetchTagsOnly = false
+	})
+	for _, tc := range []struct {
+		refs      []string
+		headsOnly bool
+		tagsOnly  bool
+		expected  []string
+		invalid   bool
+	}{
+		{},
+		{headsOnly: true, expected: []string{"heads/*"}},
+		{headsOnly: true, tagsOnly: true, expected: []string{"heads/*", "tags/*"}},
+		{refs: []string{"refs/heads/release-*", "heads/*"}, headsOnly: true, expected: []string{"heads/*", "heads/release-*"}},
+		{refs: []string{"heads/*/*"}, invalid: true},
+		{refs: []string{"refs/"}, invalid: true},
+	} {
+		t.Run(fmt.Sprint(tc.refs, tc.headsOnly, tc.tagsOnly), func(t *testing.T) {
+			fetchRefs, fetchHeadsOnly, fetchTagsOnly = tc.refs, tc.headsOnly, tc.tagsOnly
+			patterns, err := fetchRefPatterns()
+			if tc.invalid {
+				if err == nil {
+					t.Errorf("expected an error for %q", tc.refs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(patterns, tc.expected) {
+				t.Errorf("expected patterns %q, was %q", tc.expected, patterns)
+			}
+		})
+	}
+}
+
+func TestNarrowedRefspecs(t *testing.T) {
+	refspecs := narrowedRefspecs("refs/remotes/github.com/cli/cli/", []string{"heads/*", "tags/v1.*"})
+	expected := []string{
+		"+refs/heads/*:refs/remotes/github.com/cli/cli/heads/*",
+		"+refs/tags/v1.*:refs/remotes/github.com/cli/cli/tags/v1.*",
+	}
+	if !slices.Equal(refspecs, expected) {
+		t.Errorf("expected refspecs %q, was %q", expected, refspecs)
+	}
+}
+
+func TestFetchRun_slots(t *testing.T) {
+	// each process fails if another process holds the lock of its host
+	dir := t.TempDir()
+	lock := func(host string) *exec.Cmd {
+		path := filepath.Join(dir, host)
+		return exec.Command("sh", "-c", fmt.Sprintf("mkdir %q && sleep 0.1 && rmdir %q", path, path))
+	}
+	run := newFetchRun([]*exec.Cmd{
+		lock("github.com"),
+		lock("github.com"),
+		lock("my.github.biz"),
+		exec.Command("false"),
+	})
+	run.remotes = []string{
+		"github.com/cli/cli",
+		"github.com/git/git",
+		"my.github.biz/foobar/bazbiz",
+		"my.github.biz/foobar/broken",
+	}
+	run.slots = newFetchSlots(2, map[string]int{"github.com": 1})
+	if err := run.run(new(bytes.Buffer), new(bytes.Buffer)); err == nil {
+		t.Errorf("expected the failed git process to be reported")
+	}
+	if entries, err := os.ReadDir(dir); err != nil || len(entries) > 0 {
+		t.Errorf("expected every lock to be released, was %v: %v", entries, err)
+	}
+
+	var fetched, failed []string
+	for _, e := range run.finish(false) {
+		fetched = append(fetched, e.Remote)
+		if e.Failed {
+			failed = append(failed, e.Remote)
+		}
+	}
+	slices.Sort(fetched)
+	if !slices.Equal(fetched, run.remotes) {
+		t.Errorf("expected remotes %q to be recorded, was %q", run.remotes, fetched)
+	}
+	if !slices.Equal(failed, []string{"my.github.biz/foobar/broken"}) {
+		t.Errorf("expected my.github.biz/foobar/broken to be recorded as failed, was %q", failed)
+	}
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// refQueue applies the reference updates of concurrent git fetch processes
+// to the biome one transaction at a time, in the order they are queued. The
+// reftable backend allows a single writer at a time, and a git process that
+// finds the reftable locked fails rather than waiting, so fetches that write
+// references concurrently would collide. Fetches in the same biome process
+// share a queue, so that their writes never contend with each other.
+type refQueue struct {
+	path string
+	mu   sync.Mutex
+
+	// held are the `git update-ref --stdin` commands of remotes fetched
+	// into a quarantine, keyed by remote, until they are released.
+	held map[string]string
+}
+
+// newRefQueue returns a queue of reference transactions for the repository
+// at path.
+func newRefQueue(path string) *refQueue {
+	return &refQueue{
+		path: path,
+	}
+}
+
+// update waits for the transactions queued before it, and then applies the
+// `git update-ref --stdin` commands as a single transaction, recording msg in
+// the reflogs. Either all the commands are applied, or none are.
+func (q *refQueue) update(ctx context.Context, msg, commands string) error {
+	if commands == "" {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var stderr bytes.Buffer
+	c := exec.CommandContext(ctx, "git", "-C", q.path, "update-ref", "-m", msg, "--stdin")
+	c.Stdin = strings.NewReader(commands)
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("could not %q: %w: %s", c, err, stderr.String())
+	}
+	return nil
+}
+
+// applier returns a [fetchRun] apply function that queues the reference
+// updates each remote's `git fetch --porcelain` process reports, see
+// [pooledFetchRun]. If updateRefs is false, ex. while objects are received
+// into a quarantine, the updates are held until [refQueue.release].
+func (q *refQueue) applier(ctx context.Context, updateRefs bool) func(remote string, out []byte) error {
+	return func(remote string, out []byte) error {
+		updates, err := parsePorcelain(out)
+		if err != nil {
+			return err
+		}
+		if !updateRefs {
+			q.hold(remote, refUpdateCommands(updates))
+			return nil
+		}
+		if err := q.update(ctx, "fetch: "+remote, refUpdateCommands(updates)); err != nil {
+			return fmt.Errorf("could not update references of %s: %w", remote, err)
+		}
+		return nil
+	}
+}
+
+// hold keeps the `git update-ref --stdin` commands of the remote until
+// [refQueue.release].
+func (q *refQueue) hold(remote, commands string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.held == nil {
+		q.held = make(map[string]string)
+	}
+	q.held[remote] = commands
+}
+
+// release applies the held reference updates, one transaction per remote,
+// ordered by remote, and forgets them. The updates of every remote are
+// attempted, even if those of some remotes fail, ex. because their
+// references moved in the meantime.
+func (q *refQueue) release(ctx context.Context) error {
+	q.mu.Lock()
+	held := q.held
+	q.held = nil
+	q.mu.Unlock()
+
+	var errs []error
+	for _, remote := range slices.Sorted(maps.Keys(held)) {
+		if err := q.update(ctx, "fetch: "+remote, held[remote]); err != nil {
+			errs = append(errs, fmt.Errorf("could not update references of %s: %w", remote, err))
+		}
+	}
+	return errors.Join(errs...)
+}
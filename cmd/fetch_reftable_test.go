@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestRefQueue(t *testing.T) {
+	initBiome(t)
+	ctx := context.Background()
+	b, err := load(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error loading biome: %v", err)
+	}
+	tree := strings.TrimSpace(testutil.Execute(t, "git", "write-tree"))
+	commit := strings.TrimSpace(testutil.Execute(t, "git", "-c", "user.name=A", "-c", "user.email=a@example.com", "commit-tree", "-m", "base", tree))
+	const zero = "0000000000000000000000000000000000000000"
+
+	// concurrent transactions are applied one at a time
+	refs := newRefQueue(b.Path())
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			apply := refs.applier(ctx, true)
+			errs[i] = apply(fmt.Sprintf("github.com/cli/cli%d", i), []byte(fmt.Sprintf("* %s %s refs/remotes/github.com/cli/cli%d/heads/main\n", zero, commit, i)))
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		testutil.Check(t, err)
+	}
+	if refs := strings.Fields(testutil.Execute(t, "git", "for-each-ref", "--format=%(refname)", "refs/remotes/")); len(refs) != len(errs) {
+		t.Errorf("expected %d references, was %q", len(errs), refs)
+	}
+
+	// a transaction whose references moved in the meantime is not applied
+	err = refs.update(ctx, "fetch: github.com/cli/cli0", fmt.Sprintf("delete refs/remotes/github.com/cli/cli0/heads/main %s\ncreate refs/remotes/github.com/cli/cli0/heads/other %s\n", tree, commit))
+	testutil.ExpectError(t, err)
+	if out := testutil.Execute(t, "git", "for-each-ref", "--format=%(refname)", "refs/remotes/github.com/cli/cli0/"); out != "refs/remotes/github.com/cli/cli0/heads/main\n" {
+		t.Errorf("expected the transaction not to be applied, references were %q", out)
+	}
+
+	// updates received into a quarantine are held until they are released
+	apply := refs.applier(ctx, false)
+	testutil.Check(t, apply("github.com/git/git", []byte(fmt.Sprintf("* %s %s refs/remotes/github.com/git/git/heads/main\n", zero, commit))))
+	if out := testutil.Execute(t, "git", "for-each-ref", "refs/remotes/github.com/git/git/"); out != "" {
+		t.Errorf("expected no references to be updated, was %q", out)
+	}
+	testutil.Check(t, refs.release(ctx))
+	if out := testutil.Execute(t, "git", "for-each-ref", "--format=%(objectname) %(refname)", "refs/remotes/github.com/git/git/"); out != commit+" refs/remotes/github.com/git/git/heads/main\n" {
+		t.Errorf("expected the held update to be applied, references were %q", out)
+	}
+
+	// released updates are forgotten
+	testutil.Execute(t, "git", "update-ref", "-d", "refs/remotes/github.com/git/git/heads/main")
+	testutil.Check(t, refs.release(ctx))
+	if out := testutil.Execute(t, "git", "for-each-ref", "refs/remotes/github.com/git/git/"); out != "" {
+		t.Errorf("expected released updates not to be applied again, was %q", out)
+	}
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	"github.com/spf13/cobra"
+)
+
+// fetchScan reports whether the blobs received by fetches are scanned for
+// secrets, given by the --scan flag, or the biome.fetch.scan setting.
+func fetchScan(ctx context.Context, b biome.Biome) (bool, error) {
+	if fetchScanned {
+		return true, nil
+	}
+	v, err := b.GetSetting(ctx, "biome.fetch.scan")
+	if err != nil {
+		return false, err
+	}
+	scan, _ := biome.ParseBool(v.Value)
+	return scan, nil
+}
+
+// scanFetched scans the blobs fetched since the baseline was taken, and
+// reports the possible secrets found in each remote.
+func scanFetched(ctx context.Context, cmd *cobra.Command, b biome.Biome, baseline []string) error {
+	cmd.PrintErrln("Scanning fetched blobs for secrets...")
+	findings, err := b.Scan(ctx, baseline)
+	reportFindings(cmd, findings)
+	if err != nil {
+		return fmt.Errorf("could not scan fetched blobs: %w", err)
+	}
+	return nil
+}
+
+// reportFindings prints the possible secrets found by a scan, grouped by
+// remote. Secrets are printed redacted.
+func reportFindings(cmd *cobra.Command, findings []biome.Finding) {
+	if len(findings) == 0 {
+		cmd.PrintErrln("No possible secrets found")
+		return
+	}
+	for i, f := range findings {
+		if i == 0 || findings[i-1].Remote != f.Remote {
+			cmd.PrintErrf("Warning: possible secrets in %s:\n", f.Remote)
+		}
+		location := f.Path
+		if f.Line > 0 {
+			location += ":" + strconv.Itoa(f.Line)
+		}
+		line := fmt.Sprintf("  %s (blob %s) %s", location, f.Blob, f.Rule)
+		if f.Match != "" {
+			line += " " + f.Match
+		}
+		cmd.PrintErrln(line)
+	}
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	"github.com/spf13/cobra"
+)
+
+func TestFetchScan(t *testing.T) {
+	initBiome(t)
+	ctx := context.Background()
+	b, err := load(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error loading biome: %v", err)
+	}
+	if scan, err := fetchScan(ctx, b); err != nil || scan {
+		t.Errorf("expected no scan by default, was %t, %v", scan, err)
+	}
+
+	fetchScanned = true
+	t.Cleanup(func() {
+		fetchScanned = false
+	})
+	if scan, err := fetchScan(ctx, b); err != nil || !scan {
+		t.Errorf("expected scan with --scan, was %t, %v", scan, err)
+	}
+
+	fetchScanned = false
+	if err := b.SetSetting(ctx, "biome.fetch.scan", "true"); err != nil {
+		t.Fatalf("unexpected error setting scan: %v", err)
+	}
+	if scan, err := fetchScan(ctx, b); err != nil || !scan {
+		t.Errorf("expected scan with biome.fetch.scan, was %t, %v", scan, err)
+	}
+}
+
+func TestReportFindings(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		findings []biome.Finding
+		expected string
+	}{
+		{
+			name:     "none",
+			expected: "No possible secrets found\n",
+		},
+		{
+			name: "grouped by remote",
+			findings: []biome.Finding{
+				{Remote: "github.com/cli/cli", Blob: "abc123", Path: "config.ini", Line: 2, Rule: "github-token", Match: "ghp_****"},
+				{Remote: "github.com/cli/cli", Blob: "def456", Path: "id_rsa", Line: 1, Rule: "private-key", Match: "----****"},
+				{Remote: "github.com/cli/go-gh", Blob: "789abc", Path: ".env", Rule: "custom rule"},
+			},
+			expected: `Warning: possible secrets in github.com/cli/cli:
+  config.ini:2 (blob abc123) github-token ghp_****
+  id_rsa:1 (blob def456) private-key ----****
+Warning: possible secrets in github.com/cli/go-gh:
+  .env (blob 789abc) custom rule
+`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			buf := new(bytes.Buffer)
+			cmd.SetErr(buf)
+			reportFindings(cmd, tc.findings)
+			if buf.String() != tc.expected {
+				t.Errorf("unexpected report:\nwanted:\n%s\nwas:\n%s", tc.expected, buf.String())
+			}
+		})
+	}
+}
@@ -1,8 +1,14 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"slices"
 	"testing"
+	"time"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	"github.com/spf13/cobra"
 )
 
 func init() {
@@ -52,3 +58,86 @@ func TestFetchCmd_Execute(t *testing.T) {
 		}
 	})
 }
+
+func TestFetchGroups_scheduled(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_cli.String(),
+		github_com_orirawlings.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	ctx := context.Background()
+	b, err := load(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error loading biome: %v", err)
+	}
+	if err := b.SetOwnerSetting(ctx, github_com_cli, "fetchFrequency", "daily"); err != nil {
+		t.Fatalf("unexpected error setting fetch frequency: %v", err)
+	}
+	if err := b.SetOwnerSetting(ctx, github_com_orirawlings, "fetchFrequency", "15m"); err != nil {
+		t.Fatalf("unexpected error setting fetch frequency: %v", err)
+	}
+	if err := b.Record(ctx,
+		biome.JournalEntry{Time: time.Now().Add(-time.Hour), Op: biome.FetchOp, Remote: "github.com/cli/cli"},
+		biome.JournalEntry{Time: time.Now().Add(-time.Hour), Op: biome.FetchOp, Remote: "github.com/orirawlings/bar"},
+	); err != nil {
+		t.Fatalf("unexpected error recording fetches: %v", err)
+	}
+
+	cmd := &cobra.Command{}
+	stderr := new(bytes.Buffer)
+	cmd.SetErr(stderr)
+	groups, ok, err := fetchGroups(ctx, cmd, b, nil, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{github_com_orirawlings.RemoteGroup()}; !ok || !slices.Equal(groups, expected) {
+		t.Errorf("unexpected groups: wanted %v, was %v", expected, groups)
+	}
+	if expected := "Skipping 1 owners not yet due by their fetch frequency\n"; stderr.String() != expected {
+		t.Errorf("unexpected stderr: wanted %q, was %q", expected, stderr.String())
+	}
+
+	groups, ok, err = fetchGroups(ctx, cmd, b, []biome.Owner{github_com_cli}, true)
+	if err != nil || ok || len(groups) > 0 {
+		t.Errorf("expected nothing to fetch for owner that is not due, was %v, %t, %v", groups, ok, err)
+	}
+
+	groups, ok, err = fetchGroups(ctx, cmd, b, nil, false)
+	if err != nil || !ok || groups != nil {
+		t.Errorf("expected all remotes to be fetched when not scheduled, was %v, %t, %v", groups, ok, err)
+	}
+}
+
+func TestFetchQuarantine(t *testing.T) {
+	initBiome(t)
+	ctx := context.Background()
+	b, err := load(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error loading biome: %v", err)
+	}
+	if quarantine, err := fetchQuarantine(ctx, b); err != nil || quarantine {
+		t.Errorf("expected no quarantine by default, was %t, %v", quarantine, err)
+	}
+
+	fetchQuarantined = true
+	t.Cleanup(func() {
+		fetchQuarantined = false
+	})
+	if quarantine, err := fetchQuarantine(ctx, b); err != nil || !quarantine {
+		t.Errorf("expected quarantine with --quarantine, was %t, %v", quarantine, err)
+	}
+
+	fetchQuarantined = false
+	if err := b.SetSetting(ctx, "biome.fetch.quarantine", "true"); err != nil {
+		t.Fatalf("unexpected error setting quarantine: %v", err)
+	}
+	if quarantine, err := fetchQuarantine(ctx, b); err != nil || !quarantine {
+		t.Errorf("expected quarantine with biome.fetch.quarantine, was %t, %v", quarantine, err)
+	}
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"math/rand/v2"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// defaultWatchInterval is how long fetch --watch waits between fetches,
+	// unless --interval is given.
+	defaultWatchInterval = 15 * time.Minute
+
+	// watchJitter is the largest fraction of the interval by which each wait
+	// of fetch --watch is lengthened or shortened at random, so that many
+	// biomes watching the same GitHub server do not fetch in lockstep.
+	watchJitter = 0.1
+
+	// maxWatchBackoff is the largest multiple of the interval that fetch
+	// --watch waits after repeated failures.
+	maxWatchBackoff = 16
+)
+
+// watchDelay returns how long fetch --watch waits before the next fetch,
+// given the interval, the number of consecutive fetches that failed, and a
+// random number in [0, 1). The interval doubles with each consecutive
+// failure, up to maxWatchBackoff times the interval, so that a biome does
+// not hammer a GitHub server that is down, and is jittered by up to
+// watchJitter of the delay either way.
+func watchDelay(interval time.Duration, failures int, random float64) time.Duration {
+	delay := interval
+	for i := 0; i < failures && delay < interval*maxWatchBackoff; i++ {
+		delay *= 2
+	}
+	return delay + time.Duration((2*random-1)*watchJitter*float64(delay))
+}
+
+// watch runs fetchOnce repeatedly, waiting the interval between runs, until
+// the user interrupts it, ex. with Ctrl-C. Failed runs are reported, and
+// delay the next run further, rather than ending the watch. An interrupt
+// during a run cancels the run, and ends the watch without an error.
+func watch(cmd *cobra.Command, interval time.Duration, fetchOnce func(context.Context) error) error {
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	cmd.SetContext(ctx)
+
+	var failures int
+	for {
+		err := fetchOnce(ctx)
+		if ctx.Err() != nil {
+			cmd.PrintErrln("Interrupted, stopped watching")
+			return nil
+		}
+		if err != nil {
+			failures++
+			cmd.PrintErrf("Fetch failed (%d in a row): %v\n", failures, err)
+		} else {
+			failures = 0
+		}
+
+		delay := watchDelay(interval, failures, rand.Float64())
+		cmd.PrintErrf("Next fetch at %s\n", time.Now().Add(delay).Format(time.Kitchen))
+		select {
+		case <-ctx.Done():
+			cmd.PrintErrln("Interrupted, stopped watching")
+			return nil
+		case <-time.After(delay):
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWatchDelay(t *testing.T) {
+	for _, tc := range []struct {
+		failures int
+		random   float64
+		expected time.Duration
+	}{
+		{0, 0.5, 15 * time.Minute},
+		{0, 0, 13*time.Minute + 30*time.Second},
+		{0, 1, 16*time.Minute + 30*time.Second},
+		{1, 0.5, 30 * time.Minute},
+		{3, 0.5, 2 * time.Hour},
+		{4, 0.5, 4 * time.Hour},
+		{10, 0.5, 4 * time.Hour},
+		{10, 0, 3*time.Hour + 36*time.Minute},
+	} {
+		if delay := watchDelay(15*time.Minute, tc.failures, tc.random); delay != tc.expected {
+			t.Errorf("unexpected delay after %d failures with random %v: wanted %s, was %s", tc.failures, tc.random, tc.expected, delay)
+		}
+	}
+}
+
+func TestFetchCmd_interval(t *testing.T) {
+	t.Cleanup(func() {
+		fetchWatch = false
+		fetchInterval = defaultWatchInterval
+		fetchCmd.Flags().Lookup("watch").Changed = false
+		fetchCmd.Flags().Lookup("interval").Changed = false
+	})
+	for _, args := range [][]string{
+		{"fetch", "--interval", "1h"},
+		{"fetch", "--watch", "--interval", "0s"},
+		{"fetch", "--watch", "--interval", "-5m"},
+	} {
+		rootCmd.SetArgs(args)
+		if err := rootCmd.Execute(); err == nil {
+			t.Errorf("expected error executing %q", args)
+		}
+		fetchWatch = false
+		fetchInterval = defaultWatchInterval
+	}
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// defaultForcedUpdatesPeriod is how far back forced-updates lists
+	// forced updates, unless --since is given.
+	defaultForcedUpdatesPeriod = "7d"
+)
+
+var (
+	forcedUpdatesFormat outputFormat
+	forcedUpdatesSince  string
+)
+
+func init() {
+	addFormatFlag(forcedUpdatesCmd.Flags(), &forcedUpdatesFormat)
+	forcedUpdatesCmd.Flags().StringVar(&forcedUpdatesSince, "since", defaultForcedUpdatesPeriod, "List forced updates since this git date, ex. 1.month.ago, or this long ago, ex. 30d, 12w, or 1y.")
+	rootCmd.AddCommand(forcedUpdatesCmd)
+}
+
+var forcedUpdatesCmd = &cobra.Command{
+	Use:   "forced-updates [<github-owner> ...]",
+	Short: "List the branches of the git biome's remotes whose history was rewritten",
+	Long: `
+List the branches of the git biome's remotes that fetches updated without
+fast-forwarding since the --since date, 7 days ago by default, most recent
+first. If owners are specified as arguments, only list their remotes.
+
+A branch is force-updated when its previous commit is no longer an ancestor
+of its new commit, because the branch's history was rewritten and
+force-pushed. Rewritten history of long-lived branches is a security
+relevant signal, ex. of a compromised account erasing its tracks, worth
+surfacing across every repository of an organization.
+
+Each fetch compares the remotes' branches before and after fetching, reports
+the forced updates, and records them in the biome's journal. FROM is the
+branch's commit before the fetch, and TO its commit after.
+`,
+	Example: `biome forced-updates
+
+biome forced-updates --since 30d github.com/cli
+
+biome forced-updates --format csv
+`,
+	Args: validOwnerRefs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+		since, err := sinceTime(ctx, b.Path(), forcedUpdatesSince)
+		if err != nil {
+			return err
+		}
+
+		owners, err := parseOwners(args)
+		if err != nil {
+			return err
+		}
+		if err := validateOwnersPresent(ctx, b, owners); err != nil {
+			return err
+		}
+
+		entries, err := b.Journal(ctx)
+		if err != nil {
+			return err
+		}
+		w := newReportWriter(cmd, forcedUpdatesFormat, "time", "remote", "branch", "from", "to")
+		for _, u := range biome.ForcedUpdatesSince(entries, since) {
+			if len(owners) > 0 && !slices.ContainsFunc(owners, func(owner biome.Owner) bool {
+				return owner.String() == path.Dir(u.Remote)
+			}) {
+				continue
+			}
+			w.Row(u.Time.Local().Format(time.DateTime), u.Remote, u.Branch, u.From, u.To)
+		}
+		return w.Flush()
+	},
+}
+
+// recordForcedUpdates finds the branches of the biome's remotes that were
+// updated without fast-forwarding since the tips were taken, before a fetch.
+// They are reported, and recorded in the biome's journal, so that
+// forced-updates can list them later.
+func recordForcedUpdates(ctx context.Context, cmd *cobra.Command, b biome.Biome, tips map[string]string) error {
+	updates, err := b.ForcedUpdates(ctx, tips)
+	if err != nil {
+		return fmt.Errorf("could not find forced updates: %w", err)
+	}
+	for _, u := range updates {
+		cmd.PrintErrf("Warning: %s %s was force-updated from %s to %s\n", u.Remote, u.Branch, u.From, u.To)
+	}
+	return b.Record(ctx, biome.ForcedUpdateEntries(updates, time.Now())...)
+}
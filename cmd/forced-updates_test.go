@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	forcedUpdatesCmd.SetContext(context.Background())
+	pushInContext(forcedUpdatesCmd)
+}
+
+func TestForcedUpdatesCmd_Execute(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_orirawlings.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+
+	tree := strings.TrimSpace(testutil.Execute(t, "git", "write-tree"))
+	commit := func(message string, parents ...string) string {
+		args := []string{"git", "-c", "user.name=A", "-c", "user.email=a@example.com", "commit-tree", "-m", message}
+		for _, parent := range parents {
+			args = append(args, "-p", parent)
+		}
+		return strings.TrimSpace(testutil.Execute(t, append(args, tree)...))
+	}
+	base := commit("base")
+	next := commit("next", base)
+	rewritten := commit("rewritten", base)
+	testutil.Execute(t, "git", "update-ref", "refs/remotes/github.com/orirawlings/bar/heads/main", next)
+
+	ctx := context.Background()
+	b, err := load(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error loading biome: %v", err)
+	}
+	tips, err := b.BranchTips(ctx)
+	testutil.Check(t, err)
+	testutil.Execute(t, "git", "update-ref", "refs/remotes/github.com/orirawlings/bar/heads/main", rewritten)
+
+	cmd := &cobra.Command{}
+	buf := new(bytes.Buffer)
+	cmd.SetErr(buf)
+	testutil.Check(t, recordForcedUpdates(ctx, cmd, b, tips))
+	if expected := "Warning: github.com/orirawlings/bar main was force-updated from " + next + " to " + rewritten + "\n"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+
+	longAgo := time.Now().Add(-30 * 24 * time.Hour)
+	if err := b.Record(ctx,
+		biome.JournalEntry{Time: longAgo, Op: biome.ForcedUpdateOp, Remote: "github.com/orirawlings/bar", Branch: "old", From: base, To: next},
+	); err != nil {
+		t.Fatalf("unexpected error recording journal: %v", err)
+	}
+
+	// times are left out, since the latest update was recorded just now
+	for _, run := range []struct {
+		args     []string
+		expected []string
+	}{
+		{
+			args: []string{"--format", "csv"},
+			expected: []string{
+				"remote,branch,from,to",
+				"github.com/orirawlings/bar,main," + next + "," + rewritten,
+			},
+		},
+		{
+			args: []string{"--format", "csv", "--since", "60d", github_com_orirawlings.String()},
+			expected: []string{
+				"remote,branch,from,to",
+				"github.com/orirawlings/bar,main," + next + "," + rewritten,
+				"github.com/orirawlings/bar,old," + base + "," + next,
+			},
+		},
+	} {
+		t.Run(strings.Join(run.args, " "), func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			forcedUpdatesCmd.SetOut(buf)
+			t.Cleanup(func() {
+				forcedUpdatesCmd.SetOut(nil)
+				forcedUpdatesFormat = tableFormat
+				forcedUpdatesSince = defaultForcedUpdatesPeriod
+			})
+			rootCmd.SetArgs(append([]string{"forced-updates"}, run.args...))
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("unexpected error executing command: %v", err)
+			}
+			var actual []string
+			for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+				_, rest, _ := strings.Cut(line, ",")
+				actual = append(actual, rest)
+			}
+			if !slices.Equal(actual, run.expected) {
+				t.Errorf("expected %q, got %q", run.expected, actual)
+			}
+		})
+	}
+
+	// dates that git does not understand, or that are not in the past, fail
+	for _, since := range []string{"lots", "2099-01-01"} {
+		t.Cleanup(func() {
+			forcedUpdatesSince = defaultForcedUpdatesPeriod
+		})
+		rootCmd.SetArgs([]string{"forced-updates", "--since", since})
+		if err := rootCmd.Execute(); err == nil {
+			t.Errorf("expected error listing forced updates since %q", since)
+		}
+	}
+}
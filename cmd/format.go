@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// outputFormat selects how a report is printed.
+type outputFormat string
+
+const (
+	// tableFormat prints a report for people to read, as aligned columns.
+	tableFormat outputFormat = "table"
+
+	// csvFormat prints a report as comma separated values with a header
+	// row, ex. for spreadsheets. Columns are in a stable order, and sizes are
+	// printed in bytes.
+	csvFormat outputFormat = "csv"
+)
+
+var outputFormats = []outputFormat{tableFormat, csvFormat}
+
+func (f *outputFormat) String() string {
+	return string(*f)
+}
+
+func (f *outputFormat) Set(s string) error {
+	for _, format := range outputFormats {
+		if string(format) == s {
+			*f = format
+			return nil
+		}
+	}
+	return fmt.Errorf("must be one of %s", strings.Join(formatNames(), ", "))
+}
+
+func (f *outputFormat) Type() string {
+	return "format"
+}
+
+func formatNames() []string {
+	var names []string
+	for _, format := range outputFormats {
+		names = append(names, string(format))
+	}
+	return names
+}
+
+// addFormatFlag adds a --format flag that selects how a report is printed.
+func addFormatFlag(fs *pflag.FlagSet, f *outputFormat) {
+	*f = tableFormat
+	fs.Var(f, "format", fmt.Sprintf("Print the report in this format: %s.", strings.Join(formatNames(), ", ")))
+}
+
+// reportWriter prints the rows of a report in an output format.
+type reportWriter struct {
+	format outputFormat
+	table  *tabwriter.Writer
+	csv    *csv.Writer
+}
+
+// newReportWriter returns a writer of a report with the given columns to the
+// command's output. The header row is printed in upper case for tables, and
+// in lower case for CSV.
+func newReportWriter(cmd *cobra.Command, format outputFormat, columns ...string) *reportWriter {
+	w := &reportWriter{
+		format: format,
+	}
+	if format == csvFormat {
+		w.csv = csv.NewWriter(cmd.OutOrStdout())
+		var header []string
+		for _, c := range columns {
+			header = append(header, strings.ToLower(c))
+		}
+		w.Row(header...)
+	} else {
+		w.table = tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+		var header []string
+		for _, c := range columns {
+			header = append(header, strings.ToUpper(c))
+		}
+		w.Row(header...)
+	}
+	return w
+}
+
+// Row prints a row of the report.
+func (w *reportWriter) Row(values ...string) {
+	if w.csv != nil {
+		_ = w.csv.Write(values)
+		return
+	}
+	fmt.Fprintln(w.table, strings.Join(values, "\t"))
+}
+
+// Bytes renders a size for the report, in human readable units for tables,
+// or in bytes for CSV.
+func (w *reportWriter) Bytes(n int64) string {
+	if w.format == csvFormat {
+		return strconv.FormatInt(n, 10)
+	}
+	return formatBytes(n)
+}
+
+// Flush prints any buffered rows, returning the first error writing the
+// report.
+func (w *reportWriter) Flush() error {
+	if w.csv != nil {
+		w.csv.Flush()
+		return w.csv.Error()
+	}
+	return w.table.Flush()
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestReportWriter(t *testing.T) {
+	for _, tc := range []struct {
+		format   outputFormat
+		expected string
+	}{
+		{
+			format:   tableFormat,
+			expected: "OWNER           SIZE\ngithub.com/cli  1.5 KiB\n",
+		},
+		{
+			format:   csvFormat,
+			expected: "owner,size\ngithub.com/cli,1536\n",
+		},
+	} {
+		buf := new(bytes.Buffer)
+		cmd := &cobra.Command{}
+		cmd.SetOut(buf)
+		w := newReportWriter(cmd, tc.format, "owner", "size")
+		w.Row("github.com/cli", w.Bytes(1536))
+		if err := w.Flush(); err != nil {
+			t.Fatalf("unexpected error flushing %s report: %v", tc.format, err)
+		}
+		if buf.String() != tc.expected {
+			t.Errorf("unexpected %s report: wanted %q, was %q", tc.format, tc.expected, buf.String())
+		}
+	}
+}
+
+func TestOutputFormat_Set(t *testing.T) {
+	var f outputFormat
+	if err := f.Set("csv"); err != nil || f != csvFormat {
+		t.Errorf("expected csv format, was %q: %v", f, err)
+	}
+	if err := f.Set("xml"); err == nil {
+		t.Error("expected error setting unknown format")
+	}
+}
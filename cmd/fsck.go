@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	cmdutil "github.com/orirawlings/gh-biome/internal/util/command"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// fsckScheduleOff is the --schedule value that disables scheduled
+	// integrity checks.
+	fsckScheduleOff = "off"
+)
+
+var (
+	fsckSchedule  string
+	fsckScheduled bool
+)
+
+func init() {
+	fsckCmd.Flags().StringVar(&fsckSchedule, "schedule", "", "Schedule integrity checks to run this often, ex. 12h, or one of hourly, daily, or weekly, rather than checking now. Use 'off' to stop scheduled checks.")
+	fsckCmd.Flags().BoolVar(&fsckScheduled, "scheduled", false, "Only check if a scheduled integrity check is due. Use for checks run by a scheduler, ex. cron.")
+	fsckCmd.MarkFlagsMutuallyExclusive("schedule", "scheduled")
+	rootCmd.AddCommand(fsckCmd)
+}
+
+var fsckCmd = &cobra.Command{
+	Use:   "fsck",
+	Short: "Check the git biome's objects for corruption",
+	Long: `
+Check that every object reachable from the git biome's references is present
+and intact, with a connectivity-only git fsck. Checking connectivity, rather
+than the contents of every object, keeps the check affordable for biomes of
+hundreds of gigabytes, while still catching missing or unreadable objects,
+ex. from a failing disk or an interrupted repack. The command fails if
+corruption is found.
+
+Each check is recorded in the biome's journal, and the result of the last
+check is shown by 'biome status'.
+
+Since silent corruption of a large biome is otherwise discovered far too
+late, ex. when a repository needs to be restored from it, schedule checks to
+run in the background with --schedule <frequency>, where <frequency> is a
+duration, ex. 12h, or one of hourly, daily, or weekly. This sets the
+biome.fsck.schedule setting. Scheduled checks are run after fetches with
+--watch or --scheduled, once the frequency has elapsed since the last check.
+Biomes that are not fetched that way can run 'biome fsck --scheduled' from a
+scheduler such as cron, which only checks when a check is due. Stop
+scheduled checks with --schedule off.
+`,
+	Example: `biome fsck
+
+biome fsck --schedule daily
+
+biome fsck --schedule off
+
+biome fsck --scheduled
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		if cmd.Flags().Changed("schedule") {
+			if fsckSchedule == fsckScheduleOff {
+				if err := b.UnsetSetting(ctx, "biome.fsck.schedule"); err != nil {
+					return err
+				}
+				cmdutil.Println(cmd, "Stopped scheduled integrity checks")
+				return nil
+			}
+			if err := b.SetSetting(ctx, "biome.fsck.schedule", fsckSchedule); err != nil {
+				return fmt.Errorf("invalid --schedule: %w", err)
+			}
+			cmdutil.Println(cmd, fmt.Sprintf("Scheduled integrity checks %s", fsckScheduleDescription(fsckSchedule)))
+			return nil
+		}
+
+		if fsckScheduled {
+			return scheduledFsck(ctx, cmd, b)
+		}
+		return runFsck(ctx, cmd, b)
+	},
+}
+
+// fsckScheduleDescription describes how often checks are scheduled to run.
+func fsckScheduleDescription(schedule string) string {
+	switch schedule {
+	case "hourly", "daily", "weekly":
+		return schedule
+	}
+	return "every " + schedule
+}
+
+// scheduledFsck checks the biome's objects for corruption if a scheduled
+// integrity check is due, by the biome.fsck.schedule setting and the last
+// check recorded in the biome's journal.
+func scheduledFsck(ctx context.Context, cmd *cobra.Command, b biome.Biome) error {
+	schedule, err := b.FsckSchedule(ctx)
+	if err != nil {
+		return err
+	}
+	entries, err := b.Journal(ctx)
+	if err != nil {
+		return err
+	}
+	if !biome.FsckDue(entries, schedule, time.Now()) {
+		return nil
+	}
+	return runFsck(ctx, cmd, b)
+}
+
+// runFsck checks the biome's objects for corruption, failing with git's
+// messages if corruption is found.
+func runFsck(ctx context.Context, cmd *cobra.Command, b biome.Biome) error {
+	cmd.PrintErrln("Checking the connectivity of the biome's objects...")
+	entry, err := b.Fsck(ctx)
+	if err != nil {
+		return err
+	}
+	if entry.Failed {
+		return errors.New("integrity check found corruption:\n" + entry.Error)
+	}
+	cmd.PrintErrf("No corruption found in %s\n", entry.Duration.Round(time.Second))
+	return nil
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+)
+
+func init() {
+	fsckCmd.SetContext(context.Background())
+	pushInContext(fsckCmd)
+}
+
+func TestFsckCmd_Execute(t *testing.T) {
+	initBiome(t)
+
+	buf := new(bytes.Buffer)
+	fsckCmd.SetOut(buf)
+	statusCmd.SetOut(buf)
+	reset := func() {
+		fsckSchedule = ""
+		fsckScheduled = false
+		fsckCmd.Flags().Lookup("schedule").Changed = false
+		fsckCmd.Flags().Lookup("scheduled").Changed = false
+	}
+	t.Cleanup(func() {
+		fsckCmd.SetOut(nil)
+		statusCmd.SetOut(nil)
+		reset()
+	})
+	run := func(t *testing.T, args ...string) string {
+		t.Helper()
+		defer reset()
+		buf.Reset()
+		rootCmd.SetArgs(args)
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error executing command: %v", err)
+		}
+		return buf.String()
+	}
+
+	if out, expected := run(t, "status"), "fsck: never checked\n"; !strings.Contains(out, expected) {
+		t.Errorf("expected status to contain %q, got %q", expected, out)
+	}
+
+	// scheduled checks are not due until scheduled
+	run(t, "fsck", "--scheduled")
+	ctx := context.Background()
+	b, err := load(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error loading biome: %v", err)
+	}
+	if entries, err := b.Journal(ctx); err != nil || len(entries) != 0 {
+		t.Errorf("expected no journal entries, was %+v: %v", entries, err)
+	}
+
+	if out, expected := run(t, "fsck", "--schedule", "daily"), "Scheduled integrity checks daily\n"; out != expected {
+		t.Errorf("expected %q, got %q", expected, out)
+	}
+	if out, expected := run(t, "status"), "fsck.schedule: daily\n"; !strings.Contains(out, expected) {
+		t.Errorf("expected status to contain %q, got %q", expected, out)
+	}
+
+	// the first scheduled check is due, but the next is not
+	run(t, "fsck", "--scheduled")
+	run(t, "fsck", "--scheduled")
+	entries, err := b.Journal(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error reading journal: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Op != biome.FsckOp || entries[0].Failed {
+		t.Errorf("expected a single successful integrity check, was %+v", entries)
+	}
+	expected := "fsck: ok at " + entries[0].Time.Local().Format(time.DateTime) + "\n"
+	if out := run(t, "status"); !strings.Contains(out, expected) {
+		t.Errorf("expected status to contain %q, got %q", expected, out)
+	}
+
+	if out, expected := run(t, "fsck", "--schedule", "off"), "Stopped scheduled integrity checks\n"; out != expected {
+		t.Errorf("expected %q, got %q", expected, out)
+	}
+	if out := run(t, "status"); strings.Contains(out, "fsck.schedule") {
+		t.Errorf("expected status without a schedule, got %q", out)
+	}
+
+	rootCmd.SetArgs([]string{"fsck", "--schedule", "sometimes"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Errorf("expected error for invalid schedule")
+	}
+}
@@ -38,6 +38,9 @@ var (
 			Name:   "main",
 			Prefix: "refs/heads/",
 		},
+		Parent: &parentRepository{
+			URL: "https://github.com/cli/cli",
+		},
 	}
 
 	github_com_orirawlings_archived = repository{
@@ -93,6 +96,7 @@ var (
 			Name:   "main",
 			Prefix: "refs/heads/",
 		},
+		Visibility: "INTERNAL",
 	}
 
 	repositories = map[string][]repository{
@@ -126,6 +130,12 @@ type repository struct {
 	IsLocked         bool
 	URL              string `graphql:"url" json:"url"`
 	DefaultBranchRef *ref
+	Visibility       string            `json:"visibility,omitempty"`
+	Parent           *parentRepository `json:"parent"`
+}
+
+type parentRepository struct {
+	URL string `json:"url"`
 }
 
 func stubGitHub(t testing.TB) {
@@ -171,12 +181,32 @@ hosts:
 		repositoriesStubs[o.String()] = gock.New(fmt.Sprintf("https://%s", host)).
 			Post("/graphql").
 			HeaderPresent("Authorization").
-			BodyString(fmt.Sprintf(`{"query":"query OwnerRepositories($endCursor:String$owner:String!){repositoryOwner(login: $owner){repositories(first: 100, after: $endCursor, affiliations: [OWNER]){nodes{isDisabled,isArchived,isLocked,url,defaultBranchRef{name,prefix}},pageInfo{hasNextPage,endCursor}}}}","variables":{"endCursor":null,"owner":%q}}`, o.Name())).
+			BodyString(fmt.Sprintf(`{"query":"query OwnerRepositories($createdAt:Boolean!$endCursor:String$owner:String!$parent:Boolean!$visibility:Boolean!){repositoryOwner(login: $owner){repositories(first: 100, after: $endCursor, affiliations: [OWNER]){nodes{isDisabled,isArchived,isLocked,url,defaultBranchRef{name,prefix},visibility @include(if: $visibility),parent @include(if: $parent){url},createdAt @include(if: $createdAt)},pageInfo{hasNextPage,endCursor}}}}","variables":{"createdAt":false,"endCursor":null,"owner":%q,"parent":true,"visibility":true}}`, o.Name())).
 			Persist().
 			Reply(200)
 
 		updateStubbedGitHubRepositories(t, o, repositories[o.String()])
 	}
+
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		HeaderPresent("Authorization").
+		BodyString(`{"query":"query Viewer{viewer{login}}"}`).
+		Persist().
+		Reply(200).
+		JSON(`{"data":{"viewer":{"login":"user1"}}}`)
+
+	marshalled, err := json.Marshal([]repository{github_com_cli_cli, github_com_orirawlings_bar})
+	if err != nil {
+		t.Fatalf("could not marshal viewer repositories in stubs: %v", err)
+	}
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		HeaderPresent("Authorization").
+		BodyString(`{"query":"query ViewerRepositories($affiliations:[RepositoryAffiliation!]!$createdAt:Boolean!$endCursor:String$parent:Boolean!$visibility:Boolean!){viewer{repositories(first: 100, after: $endCursor, affiliations: $affiliations){nodes{isDisabled,isArchived,isLocked,url,defaultBranchRef{name,prefix},visibility @include(if: $visibility),parent @include(if: $parent){url},createdAt @include(if: $createdAt)},pageInfo{hasNextPage,endCursor}}}}","variables":{"affiliations":["COLLABORATOR","ORGANIZATION_MEMBER","OWNER"],"createdAt":false,"endCursor":null,"parent":true,"visibility":true}}`).
+		Persist().
+		Reply(200).
+		JSON(fmt.Sprintf(`{"data":{"viewer":{"repositories":{"nodes":%s,"pageInfo":{"hasNextPage":false}}}}}`, marshalled))
 }
 
 func updateStubbedGitHubRepositories(t testing.TB, owner biome.Owner, repos []repository) {
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	"github.com/spf13/cobra"
+)
+
+// graphFormat selects how the biome's topology is printed.
+type graphFormat string
+
+const (
+	// dotFormat prints the topology in the Graphviz DOT language.
+	dotFormat graphFormat = "dot"
+
+	// jsonFormat prints the topology as a JSON object of nodes and edges.
+	jsonFormat graphFormat = "json"
+)
+
+func (f *graphFormat) String() string {
+	return string(*f)
+}
+
+func (f *graphFormat) Set(s string) error {
+	switch graphFormat(s) {
+	case dotFormat, jsonFormat:
+		*f = graphFormat(s)
+		return nil
+	}
+	return fmt.Errorf("must be one of %s, %s", dotFormat, jsonFormat)
+}
+
+func (f *graphFormat) Type() string {
+	return "format"
+}
+
+var (
+	graphOutputFormat = dotFormat
+	graphSizes        bool
+)
+
+func init() {
+	graphCmd.Flags().Var(&graphOutputFormat, "format", fmt.Sprintf("Print the graph in this format: %s, %s.", dotFormat, jsonFormat))
+	graphCmd.Flags().BoolVar(&graphSizes, "sizes", false, "Measure the disk usage of each remote, which can take a long time for large biomes.")
+	rootCmd.AddCommand(graphCmd)
+}
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Export the topology of the git biome for visualization",
+	Long: `
+Print the topology of the git biome as a graph: each owner is related to the
+remotes that it owns, and each fork is related to the upstream remote that it
+was forked from. Upstream remotes that are not in the biome are included, so
+that forks of the same repository are connected.
+
+Remote nodes are annotated with their categories, ex. active or archived. Use
+--sizes to also annotate each remote with the on-disk size of the objects
+reachable from its references, as measured by 'biome du'.
+
+By default, the graph is printed in the Graphviz DOT language, for rendering
+with Graphviz. Use --format json to print an object of nodes and edges for
+loading into other graph tools.
+`,
+	Example: `biome graph | dot -Tsvg > biome.svg
+
+biome graph --sizes --format json
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		g, err := b.Graph(ctx, graphSizes)
+		if err != nil {
+			return err
+		}
+		if graphOutputFormat == jsonFormat {
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			return enc.Encode(g)
+		}
+		return writeDot(cmd.OutOrStdout(), g)
+	},
+}
+
+// writeDot prints the graph in the Graphviz DOT language. Owners are drawn as
+// boxes, upstream remotes that are not in the biome are dashed, and fork
+// edges are dashed.
+func writeDot(w io.Writer, g biome.Graph) error {
+	var sb strings.Builder
+	sb.WriteString("digraph biome {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	for _, n := range g.Nodes {
+		var attrs []string
+		switch {
+		case n.Kind == biome.OwnerNode:
+			attrs = append(attrs, "shape=box")
+		case len(n.Categories) == 0:
+			attrs = append(attrs, "style=dashed")
+		default:
+			var categories []string
+			for _, c := range n.Categories {
+				categories = append(categories, string(c))
+			}
+			attrs = append(attrs, fmt.Sprintf("category=%q", strings.Join(categories, " ")))
+		}
+		if n.Bytes > 0 {
+			attrs = append(attrs, fmt.Sprintf("bytes=%d", n.Bytes))
+		}
+		fmt.Fprintf(&sb, "  %q [%s];\n", n.ID, strings.Join(attrs, ", "))
+	}
+	for _, e := range g.Edges {
+		if e.Kind == biome.ForkOfEdge {
+			fmt.Fprintf(&sb, "  %q -> %q [label=\"fork of\", style=dashed];\n", e.From, e.To)
+			continue
+		}
+		fmt.Fprintf(&sb, "  %q -> %q;\n", e.From, e.To)
+	}
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+)
+
+func init() {
+	graphCmd.SetContext(context.Background())
+	pushInContext(graphCmd)
+}
+
+func TestWriteDot(t *testing.T) {
+	g := biome.Graph{
+		Nodes: []biome.GraphNode{
+			{ID: "github.com/cli/cli", Kind: biome.RemoteNode},
+			{ID: "github.com/orirawlings", Kind: biome.OwnerNode},
+			{ID: "github.com/orirawlings/cli", Kind: biome.RemoteNode, Categories: []biome.RemoteCategory{biome.Archived}, Bytes: 1536},
+		},
+		Edges: []biome.GraphEdge{
+			{From: "github.com/orirawlings", To: "github.com/orirawlings/cli", Kind: biome.OwnsEdge},
+			{From: "github.com/orirawlings/cli", To: "github.com/cli/cli", Kind: biome.ForkOfEdge},
+		},
+	}
+	buf := new(bytes.Buffer)
+	if err := writeDot(buf, g); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := strings.Join([]string{
+		`digraph biome {`,
+		`  rankdir=LR;`,
+		`  "github.com/cli/cli" [style=dashed];`,
+		`  "github.com/orirawlings" [shape=box];`,
+		`  "github.com/orirawlings/cli" [category="archived", bytes=1536];`,
+		`  "github.com/orirawlings" -> "github.com/orirawlings/cli";`,
+		`  "github.com/orirawlings/cli" -> "github.com/cli/cli" [label="fork of", style=dashed];`,
+		`}`,
+	}, "\n") + "\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestGraphCmd_Execute(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_orirawlings.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+
+	buf := new(bytes.Buffer)
+	graphCmd.SetOut(buf)
+	t.Cleanup(func() {
+		graphCmd.SetOut(nil)
+		graphOutputFormat = dotFormat
+	})
+	rootCmd.SetArgs([]string{"graph", "--format", "json"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"id": "github.com/orirawlings/bar"`) {
+		t.Errorf("expected remote node in graph, got %q", buf.String())
+	}
+}
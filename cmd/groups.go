@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	cmdutil "github.com/orirawlings/gh-biome/internal/util/command"
+	"github.com/spf13/cobra"
+)
+
+// groupsCmd represents the groups command
+var groupsCmd = &cobra.Command{
+	Use:   "groups [<github-owner> | <group> ...]",
+	Short: "List the git remote groups of the git biome",
+	Long: `
+List the git remote groups of the git biome, along with the owner or viewer whose
+remotes are members of each group, and the number of members.
+
+Each owner and viewer of the biome has a git remote group, listing its remotes
+in the remotes.<group> git config, so that they can be fetched together, ex.
+with 'git fetch <group>'. Groups are named g-<sha1>, after a hash of the owner,
+ex. github.com/cli, or viewer, ex. viewer@github.com, so that any owner name can
+be used in git config. Groups that are not of any current owner or viewer, ex.
+left behind by manual edits of the git config, are listed with no owner.
+
+If owners, viewers, or group names are given as arguments, the members of their
+groups are printed instead, one remote per line.
+
+Use --format csv to print the groups as comma separated values.
+`,
+	Example: `biome groups
+
+biome groups github.com/cli
+
+biome groups viewer@github.com
+
+biome groups --format csv
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		groups, err := b.RemoteGroups(ctx)
+		if err != nil {
+			return err
+		}
+
+		if len(args) > 0 {
+			var members []string
+			for _, arg := range args {
+				group, err := findRemoteGroup(groups, arg)
+				if err != nil {
+					return err
+				}
+				members = append(members, group.Members...)
+			}
+			slices.Sort(members)
+			for _, member := range slices.Compact(members) {
+				cmdutil.Println(cmd, member)
+			}
+			return nil
+		}
+
+		w := newReportWriter(cmd, groupsFormat, "group", "owner", "remotes")
+		for _, group := range groups {
+			w.Row(group.Name, group.Source, strconv.Itoa(len(group.Members)))
+		}
+		return w.Flush()
+	},
+}
+
+// findRemoteGroup returns the remote group with the given name, or of the
+// given owner or viewer.
+func findRemoteGroup(groups []biome.RemoteGroup, arg string) (biome.RemoteGroup, error) {
+	source := arg
+	if owner, err := biome.ParseOwner(arg); err == nil {
+		source = owner.String()
+	}
+	for _, group := range groups {
+		if group.Name == arg || group.Source != "" && (group.Source == arg || group.Source == source) {
+			return group, nil
+		}
+	}
+	return biome.RemoteGroup{}, fmt.Errorf("no remote group of %q in the biome", arg)
+}
+
+var groupsFormat outputFormat
+
+func init() {
+	rootCmd.AddCommand(groupsCmd)
+	addFormatFlag(groupsCmd.Flags(), &groupsFormat)
+}
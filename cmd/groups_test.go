@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func init() {
+	groupsCmd.SetContext(context.Background())
+	pushInContext(groupsCmd)
+}
+
+func TestGroupsCmd_Execute(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_cli.String(),
+		github_com_orirawlings.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+
+	for _, run := range []struct {
+		args     []string
+		expected []string
+	}{
+		{
+			args: []string{"--format", "csv"},
+			expected: []string{
+				"group,owner,remotes",
+				fmt.Sprintf("%s,github.com/cli,1", github_com_cli.RemoteGroup()),
+				fmt.Sprintf("%s,github.com/orirawlings,3", github_com_orirawlings.RemoteGroup()),
+			},
+		},
+		{
+			args: []string{"orirawlings"},
+			expected: []string{
+				"github.com/orirawlings/archived",
+				"github.com/orirawlings/bar",
+				"github.com/orirawlings/headless",
+			},
+		},
+		{
+			args: []string{github_com_cli.RemoteGroup()},
+			expected: []string{
+				"github.com/cli/cli",
+			},
+		},
+	} {
+		t.Run(strings.Join(run.args, " "), func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			groupsCmd.SetOut(buf)
+			t.Cleanup(func() {
+				groupsCmd.SetOut(nil)
+				groupsFormat = tableFormat
+			})
+			rootCmd.SetArgs(append([]string{"groups"}, run.args...))
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("unexpected error executing command: %v", err)
+			}
+			expected := strings.Join(run.expected, "\n") + "\n"
+			if buf.String() != expected {
+				t.Errorf("expected %q, got %q", expected, buf.String())
+			}
+		})
+	}
+
+	rootCmd.SetArgs([]string{"groups", "github.com/git"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Errorf("expected error listing the members of an unknown group")
+	}
+}
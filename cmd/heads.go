@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"fmt"
+
 	cmdutil "github.com/orirawlings/gh-biome/internal/util/command"
 	"github.com/spf13/cobra"
 )
@@ -23,6 +25,17 @@ var headsCmd = &cobra.Command{
 		
 		gh biome heads | xargs git grep -i "search term"
 
+	A warning is printed for each remote whose HEAD reference is missing or does not resolve to a
+	default branch, ex. for empty repositories or remotes that have not been fetched yet, since
+	such references are silently skipped by most git commands. With --strict, the command also
+	fails, for analyses that assume every remote is covered.
+
+	Repositories with branches but no default branch can be given a synthesized HEAD reference,
+	pointing at their main or master branch, or their only branch, by setting biome.heads.synthesize.
+
+	When printing to a terminal, references are colored by the category of their remote, as with
+	'gh biome remotes'. Colors are never printed when the output is piped to another command.
+
 	`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
@@ -36,8 +49,20 @@ var headsCmd = &cobra.Command{
 			return err
 		}
 
+		unresolved, err := b.UnresolvedHeads(ctx, remotes)
+		if err != nil {
+			return err
+		}
+
+		cs := newColorScheme(cmd)
 		for _, remote := range remotes {
-			cmdutil.Println(cmd, remote.Head())
+			cmdutil.Println(cmd, cs.Remote(remote, remote.Head()))
+		}
+		for _, remote := range unresolved {
+			cmd.PrintErrf("Warning: %s does not resolve to a default branch of %s\n", remote.Head(), remote.Name)
+		}
+		if headsStrict && len(unresolved) > 0 {
+			return fmt.Errorf("%d of %d remotes have a missing or unresolvable HEAD reference", len(unresolved), len(remotes))
 		}
 		return nil
 	},
@@ -45,9 +70,11 @@ var headsCmd = &cobra.Command{
 
 var (
 	headsOptions = newRemoteCategoryOptions(true)
+	headsStrict  bool
 )
 
 func init() {
 	rootCmd.AddCommand(headsCmd)
 	headsOptions.AddFlags(headsCmd.Flags())
+	headsCmd.Flags().BoolVar(&headsStrict, "strict", false, "Fail if any remote's HEAD reference is missing or does not resolve to a default branch.")
 }
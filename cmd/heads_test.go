@@ -101,3 +101,41 @@ func TestHeadsCmd_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestHeadsCmd_Execute_strict(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_cli.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+
+	// nothing was fetched, so no HEAD resolves
+	out, errOut := new(bytes.Buffer), new(bytes.Buffer)
+	headsCmd.SetOut(out)
+	headsCmd.SetErr(errOut)
+	t.Cleanup(func() {
+		headsCmd.SetOut(nil)
+		headsCmd.SetErr(nil)
+		headsStrict = false
+	})
+	rootCmd.SetArgs([]string{"heads"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	if expected := "Warning: refs/remotes/github.com/cli/cli/HEAD does not resolve to a default branch of github.com/cli/cli\n"; errOut.String() != expected {
+		t.Errorf("expected %q, got %q", expected, errOut.String())
+	}
+
+	rootCmd.SetArgs([]string{"heads", "--strict"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Errorf("expected error listing unresolvable HEADs with --strict")
+	}
+	if expected := "refs/remotes/github.com/cli/cli/HEAD\n"; !strings.HasPrefix(out.String(), expected) {
+		t.Errorf("expected HEADs to be listed, got %q", out.String())
+	}
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+
+	cmdutil "github.com/orirawlings/gh-biome/internal/util/command"
+	"github.com/spf13/cobra"
+)
+
+var (
+	healthMinScore int
+)
+
+func init() {
+	healthCmd.Flags().IntVar(&healthMinScore, "min-score", 0, "Fail if the health score is below this value, ex. to gate a CI job.")
+	rootCmd.AddCommand(healthCmd)
+}
+
+var healthCmd = &cobra.Command{
+	Use:   "health",
+	Short: "Score the health of the git biome",
+	Long: `
+Score the health of the git biome from 0 to 100, and list the problems that
+were found along with steps to fix them, most important first.
+
+The following are checked:
+
+	unfetched remotes      remotes without any references, usually because fetching them failed
+	unresolved HEADs       remotes whose HEAD reference points to a missing default branch
+	stale fetch            remotes that have not been fetched in the past week
+	overdue maintenance    no commit-graph has been written by git maintenance in the past month
+	pack fragmentation     too many packfiles or loose objects in the object database
+
+Use --min-score to exit with an error when the score is too low, ex. in CI.
+`,
+	Example: `biome health
+
+biome health --min-score 80
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		report, err := b.Health(ctx)
+		if err != nil {
+			return err
+		}
+
+		cmdutil.Println(cmd, fmt.Sprintf("score: %d/100", report.Score))
+		for _, check := range report.Checks {
+			cmdutil.Println(cmd, fmt.Sprintf("-%d %s: %s", check.Penalty, check.Name, check.Detail))
+			cmdutil.Println(cmd, fmt.Sprintf("\tfix: %s", check.Remediation))
+		}
+
+		if report.Score < healthMinScore {
+			return fmt.Errorf("health score %d is below the minimum %d", report.Score, healthMinScore)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func init() {
+	healthCmd.SetContext(context.Background())
+	pushInContext(healthCmd)
+}
+
+func TestHealthCmd_Execute(t *testing.T) {
+	initBiome(t)
+
+	buf := new(bytes.Buffer)
+	healthCmd.SetOut(buf)
+	t.Cleanup(func() {
+		healthCmd.SetOut(nil)
+		healthMinScore = 0
+		healthCmd.Flags().Lookup("min-score").Changed = false
+	})
+
+	rootCmd.SetArgs([]string{"health", "--min-score", "100"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	if expected := "score: 100/100\n"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
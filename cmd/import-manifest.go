@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"cmp"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	cmdutil "github.com/orirawlings/gh-biome/internal/util/command"
+	"github.com/spf13/cobra"
+)
+
+// commitPattern matches the full object name of a commit, in SHA-1 or
+// SHA-256, that a manifest pins a project to.
+var commitPattern = regexp.MustCompile(`^[0-9a-f]{40}([0-9a-f]{24})?$`)
+
+var (
+	importManifestFetch    bool
+	importManifestSnapshot string
+)
+
+func init() {
+	importManifestCmd.Flags().BoolVar(&importManifestFetch, "fetch", false, "Fetch the remotes of the manifest's owners once they are added.")
+	importManifestCmd.Flags().StringVar(&importManifestSnapshot, "snapshot", "", "Record a snapshot with this name, in which the default branch of each remote is at the commit pinned by the manifest.")
+	rootCmd.AddCommand(importManifestCmd)
+}
+
+var importManifestCmd = &cobra.Command{
+	Use:   "import-manifest <file>",
+	Short: "Add the repositories of a multi-repository manifest to the git biome",
+	Long: `
+Add the GitHub repositories listed by a multi-repository manifest, in the
+format of Google's repo tool or of Fuchsia's jiri tool, as remotes of the git
+biome, so that existing multi-repository workflows can be brought into the
+biome. Use - to read the manifest from standard input.
+
+The owners of the manifest's repositories are added to the biome, limited to
+the listed repositories by each owner's include setting. Owners that are
+already in the biome without an include setting keep all their repositories.
+Otherwise, the listed repositories are added to the owner's include setting.
+
+Use --fetch to fetch the owners' remotes once they are added. Use --snapshot
+to then record a snapshot in which the default branch of each remote is at
+the commit that the manifest pins the repository to, so that the manifest's
+exact state can be restored later with 'biome snapshot restore'. Restoring
+such a snapshot resets the references of the biome's other remotes too.
+Repositories that the manifest tracks by branch, rather than pinning to a
+commit, are not recorded by the snapshot.
+`,
+	Example: `biome import-manifest default.xml
+
+biome import-manifest --fetch --snapshot release-1.0 .repo/manifests/default.xml
+
+biome manifest --owner github.com/cli | biome import-manifest -
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		projects, err := readManifest(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		if importManifestSnapshot != "" {
+			snapshots, err := b.Snapshots(ctx)
+			if err != nil {
+				return err
+			}
+			if slices.ContainsFunc(snapshots, func(s biome.Snapshot) bool { return s.Name == importManifestSnapshot }) {
+				return fmt.Errorf("snapshot already exists: %q", importManifestSnapshot)
+			}
+		}
+
+		// the listed repositories of each owner
+		repos := make(map[biome.Owner][]string)
+		for _, p := range projects {
+			owner, err := biome.ParseOwner(path.Dir(p.Remote))
+			if err != nil {
+				return fmt.Errorf("could not import %s: %w", p.Remote, err)
+			}
+			repos[owner] = append(repos[owner], path.Base(p.Remote))
+		}
+		owners := slices.SortedFunc(maps.Keys(repos), func(a, b biome.Owner) int {
+			return strings.Compare(a.String(), b.String())
+		})
+		existing, err := b.Owners(ctx)
+		if err != nil {
+			return err
+		}
+		var added []biome.Owner
+		for _, owner := range owners {
+			if !slices.Contains(existing, owner) {
+				cmd.PrintErrf("Adding %s...\n", owner)
+				added = append(added, owner)
+			}
+		}
+		if len(added) > 0 {
+			if err := b.AddOwners(ctx, added); err != nil {
+				return err
+			}
+		}
+		for _, owner := range owners {
+			include, err := b.GetOwnerSetting(ctx, owner, "include")
+			if err != nil {
+				return err
+			}
+			if include.IsDefault && slices.Contains(existing, owner) {
+				// all of the owner's repositories are already included
+				continue
+			}
+			patterns := slices.Clone(repos[owner])
+			for _, p := range strings.Split(include.Value, ",") {
+				if p = strings.TrimSpace(p); p != "" {
+					patterns = append(patterns, p)
+				}
+			}
+			slices.Sort(patterns)
+			if err := b.SetOwnerSetting(ctx, owner, "include", strings.Join(slices.Compact(patterns), ",")); err != nil {
+				return err
+			}
+		}
+
+		if err := warnAnonymous(ctx, cmd, b); err != nil {
+			return err
+		}
+		if err := updateRemotes(cmd, b, owners...); err != nil {
+			return err
+		}
+		remotes, err := b.Remotes(ctx, biome.FetchableRemoteCategories...)
+		if err != nil {
+			return err
+		}
+		commits := make(map[string]string)
+		for _, p := range projects {
+			if !slices.ContainsFunc(remotes, func(r biome.Remote) bool { return r.Name == p.Remote }) {
+				cmd.PrintErrf("Warning: %s is not a fetchable repository of its owner, skipping it\n", p.Remote)
+				continue
+			}
+			if p.Commit != "" {
+				commits[p.Remote] = p.Commit
+			}
+		}
+		cmd.PrintErrf("Imported %d repositories of %d owners\n", len(projects), len(owners))
+
+		if importManifestFetch {
+			groups, ok, err := fetchGroups(ctx, cmd, b, owners, false)
+			if err != nil {
+				return err
+			}
+			if ok {
+				if err := fetch(ctx, cmd, b, groups); err != nil {
+					return err
+				}
+			}
+		}
+		if importManifestSnapshot != "" {
+			s, err := b.CreateSnapshotAt(ctx, importManifestSnapshot, commits)
+			if err != nil {
+				return err
+			}
+			cmdutil.Println(cmd, "Recorded", len(commits), "pinned remotes in", s.Ref())
+		}
+		return nil
+	},
+}
+
+// readManifest reads the projects of the manifest in the named file, or in
+// standard input if the name is -.
+func readManifest(cmd *cobra.Command, name string) ([]biome.ManifestProject, error) {
+	var r io.Reader = cmd.InOrStdin()
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, fmt.Errorf("could not read manifest: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+	projects, err := parseManifest(r)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse manifest %s: %w", name, err)
+	}
+	return projects, nil
+}
+
+// manifestXML is a manifest of either Google's repo tool or Fuchsia's jiri
+// tool, see [writeManifest].
+type manifestXML struct {
+	XMLName xml.Name `xml:"manifest"`
+	Remotes []struct {
+		Name  string `xml:"name,attr"`
+		Fetch string `xml:"fetch,attr"`
+	} `xml:"remote"`
+	Default struct {
+		Remote   string `xml:"remote,attr"`
+		Revision string `xml:"revision,attr"`
+	} `xml:"default"`
+	Projects     []manifestProjectXML `xml:"project"`
+	JiriProjects []manifestProjectXML `xml:"projects>project"`
+}
+
+type manifestProjectXML struct {
+	Name         string `xml:"name,attr"`
+	Remote       string `xml:"remote,attr"`
+	Revision     string `xml:"revision,attr"`
+	Upstream     string `xml:"upstream,attr"`
+	RemoteBranch string `xml:"remotebranch,attr"`
+}
+
+// parseManifest returns the GitHub repositories of a repo or jiri manifest,
+// sorted by remote name. A project's revision pins it to a commit if it is a
+// full object name, or names its branch otherwise.
+func parseManifest(r io.Reader) ([]biome.ManifestProject, error) {
+	var m manifestXML
+	if err := xml.NewDecoder(r).Decode(&m); err != nil {
+		return nil, err
+	}
+	fetchURLs := make(map[string]string)
+	for _, remote := range m.Remotes {
+		fetchURLs[remote.Name] = remote.Fetch
+	}
+
+	var projects []biome.ManifestProject
+	for _, p := range m.Projects {
+		remote := cmp.Or(p.Remote, m.Default.Remote)
+		fetch, ok := fetchURLs[remote]
+		if !ok {
+			return nil, fmt.Errorf("unknown remote %q of project %s", remote, p.Name)
+		}
+		project, err := manifestProject(strings.TrimSuffix(fetch, "/")+"/"+p.Name, cmp.Or(p.Revision, m.Default.Revision), p.Upstream)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, project)
+	}
+	for _, p := range m.JiriProjects {
+		project, err := manifestProject(p.Remote, p.Revision, p.RemoteBranch)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, project)
+	}
+	slices.SortFunc(projects, func(a, b biome.ManifestProject) int {
+		return strings.Compare(a.Remote, b.Remote)
+	})
+	return slices.CompactFunc(projects, func(a, b biome.ManifestProject) bool {
+		return a.Remote == b.Remote
+	}), nil
+}
+
+// manifestProject returns the project of a manifest fetched from the given
+// URL, at the given revision, tracking the given branch.
+func manifestProject(rawURL, revision, branch string) (biome.ManifestProject, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return biome.ManifestProject{}, fmt.Errorf("project URL must be absolute: %q", rawURL)
+	}
+	repo := strings.Trim(strings.TrimSuffix(u.Path, ".git"), "/")
+	if strings.Count(repo, "/") != 1 {
+		return biome.ManifestProject{}, fmt.Errorf("project URL must name a GitHub repository, ex. https://github.com/<owner>/<repo>: %q", rawURL)
+	}
+	p := biome.ManifestProject{
+		Remote: path.Join(u.Host, repo),
+		URL:    rawURL,
+		Branch: branch,
+	}
+	if commitPattern.MatchString(revision) {
+		p.Commit = revision
+	} else if p.Branch == "" {
+		p.Branch = strings.TrimPrefix(revision, "refs/heads/")
+	}
+	return p, nil
+}
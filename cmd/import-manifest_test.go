@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func init() {
+	importManifestCmd.SetContext(context.Background())
+	pushInContext(importManifestCmd)
+}
+
+func TestParseManifest(t *testing.T) {
+	commit := strings.Repeat("a", 40)
+	projects := []biome.ManifestProject{
+		{
+			Remote: "github.com/cli/cli",
+			URL:    "https://github.com/cli/cli.git",
+			Branch: "trunk",
+			Commit: commit,
+		},
+		{
+			Remote: "my.github.biz/foobar/baz",
+			URL:    "https://my.github.biz/foobar/baz.git",
+			Branch: "main",
+		},
+	}
+	for _, format := range []manifestFormat{repoManifest, jiriManifest} {
+		t.Run(string(format), func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			testutil.Check(t, writeManifest(buf, format, projects))
+			actual, err := parseManifest(buf)
+			testutil.Check(t, err)
+			expected := []biome.ManifestProject{
+				{Remote: "github.com/cli/cli", Branch: "trunk", Commit: commit},
+				{Remote: "my.github.biz/foobar/baz", Branch: "main"},
+			}
+			for i := range actual {
+				actual[i].URL = ""
+			}
+			if !reflect.DeepEqual(actual, expected) {
+				t.Errorf("unexpected projects: wanted %+v, was %+v", expected, actual)
+			}
+		})
+	}
+
+	// repo manifests may give defaults, and revisions naming branches
+	actual, err := parseManifest(strings.NewReader(`<manifest>
+  <remote name="origin" fetch="https://github.com/cli"/>
+  <default remote="origin" revision="refs/heads/trunk"/>
+  <project name="cli.git"/>
+  <project name="go-gh" revision="` + commit + `"/>
+</manifest>`))
+	testutil.Check(t, err)
+	expected := []biome.ManifestProject{
+		{Remote: "github.com/cli/cli", URL: "https://github.com/cli/cli.git", Branch: "trunk"},
+		{Remote: "github.com/cli/go-gh", URL: "https://github.com/cli/go-gh", Commit: commit},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected projects: wanted %+v, was %+v", expected, actual)
+	}
+
+	for _, manifest := range []string{
+		`<manifest><project name="cli/cli" remote="missing"/></manifest>`,
+		`<manifest><projects><project remote="cli/cli"/></projects></manifest>`,
+		`<manifest><projects><project remote="https://github.com/cli"/></projects></manifest>`,
+		`not xml`,
+	} {
+		if _, err := parseManifest(strings.NewReader(manifest)); err == nil {
+			t.Errorf("expected error parsing manifest %q", manifest)
+		}
+	}
+}
+
+func TestImportManifestCmd_Execute(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	t.Cleanup(func() {
+		importManifestFetch = false
+		importManifestSnapshot = ""
+	})
+
+	tree := strings.TrimSpace(testutil.Execute(t, "git", "write-tree"))
+	pinned := strings.TrimSpace(testutil.Execute(t, "git", "-c", "user.name=A", "-c", "user.email=a@example.com", "commit-tree", "-m", "pinned", tree))
+	commit := strings.TrimSpace(testutil.Execute(t, "git", "-c", "user.name=A", "-c", "user.email=a@example.com", "commit-tree", "-m", "main", "-p", pinned, tree))
+	testutil.Execute(t, "git", "update-ref", "refs/remotes/github.com/orirawlings/bar/heads/main", commit)
+	testutil.Execute(t, "git", "symbolic-ref", "refs/remotes/github.com/orirawlings/bar/HEAD", "refs/remotes/github.com/orirawlings/bar/heads/main")
+
+	manifest := filepath.Join(t.TempDir(), "default.xml")
+	testutil.Check(t, os.WriteFile(manifest, []byte(`<manifest>
+  <remote name="github" fetch="https://github.com/"/>
+  <default remote="github" revision="main"/>
+  <project name="orirawlings/bar" revision="`+pinned+`"/>
+  <project name="orirawlings/missing"/>
+</manifest>
+`), 0o644))
+	buf := new(bytes.Buffer)
+	rootCmd.SetOut(buf)
+	rootCmd.SetErr(buf)
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+	})
+	rootCmd.SetArgs([]string{"import-manifest", "--snapshot", "release", manifest})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	for _, expected := range []string{
+		"Adding github.com/orirawlings...",
+		"Warning: github.com/orirawlings/missing is not a fetchable repository of its owner, skipping it",
+		"Recorded 1 pinned remotes in refs/biome/snapshots/release",
+	} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("expected output to contain %q, was:\n%s", expected, buf.String())
+		}
+	}
+	if actual := strings.TrimSpace(testutil.Execute(t, "git", "rev-parse", "refs/biome/snapshots/release^{tree}")); actual == "" {
+		t.Error("expected snapshot to be recorded")
+	}
+
+	ctx := context.Background()
+	b, err := load(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error loading biome: %v", err)
+	}
+	remotes, err := b.Remotes(ctx, biome.FetchableRemoteCategories...)
+	testutil.Check(t, err)
+	var names []string
+	for _, r := range remotes {
+		names = append(names, r.Name)
+	}
+	if expected := []string{"github.com/orirawlings/bar"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected only the manifest's repositories to be remotes, wanted %v, was %v", expected, names)
+	}
+
+	// importing again adds to the owner's included repositories
+	testutil.Check(t, os.WriteFile(manifest, []byte(`<manifest>
+  <projects>
+    <project name="headless" remote="https://github.com/orirawlings/headless.git"/>
+  </projects>
+</manifest>
+`), 0o644))
+	rootCmd.SetArgs([]string{"import-manifest", "--snapshot", "", manifest})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	v, err := b.GetOwnerSetting(ctx, github_com_orirawlings, "include")
+	testutil.Check(t, err)
+	if expected := "bar,headless,missing"; v.Value != expected {
+		t.Errorf("expected include setting %q, was %q", expected, v.Value)
+	}
+
+	rootCmd.SetArgs([]string{"import-manifest", "--snapshot", "release", manifest})
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("expected error recording a snapshot that already exists")
+	}
+}
@@ -2,13 +2,28 @@ package cmd
 
 import (
 	"fmt"
+	"path/filepath"
+	"slices"
 
 	"github.com/orirawlings/gh-biome/internal/biome"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	initFetchParallel int
+	initLFS           string
+	initMaintenance   string
+	initObjectFormat  string
+	initRefFormat     string
+)
+
 func init() {
+	initCmd.Flags().IntVar(&initFetchParallel, "fetch-parallel", 0, "Maximum number of remotes to fetch in parallel, recorded as the fetch.parallel setting. A value of 0 lets git choose a reasonable default. If not given, the setting is left unset.")
+	initCmd.Flags().StringVar(&initLFS, "lfs", "", "How Git LFS objects are handled, recorded as the biome.lfs.policy setting: skip, pointers, or selected.")
+	initCmd.Flags().StringVar(&initMaintenance, "maintenance", string(biome.MaintenanceOff), "How the biome is registered with git maintenance: off, minimal, or full.")
+	initCmd.Flags().StringVar(&initObjectFormat, "object-format", "", "The hash algorithm that names the biome's objects: sha1 or sha256. Defaults to git's default object format.")
+	initCmd.Flags().StringVar(&initRefFormat, "ref-format", "", "The backend that stores the biome's references: files or reftable, recorded as the biome.refFormat setting. Defaults to git's default ref format.")
 	rootCmd.AddCommand(initCmd)
 }
 
@@ -16,20 +31,111 @@ var initCmd = &cobra.Command{
 	Use:   "init [<directory>]",
 	Short: "Initialize a new git biome in the given directory",
 	Long: `
-Initialize a new git biome in the given directory.
+Initialize a new git biome in the given directory, with configuration
+settings tuned for git biome support.
+
+Whether the biome is a bare or a non-bare repository depends on the
+directory, rather than on a flag. If the directory is an existing non-bare
+repository, either its working tree or its git directory, the repository
+becomes the biome. The repository's branches, working tree, and other
+remotes are kept. Shallow repositories are refused. Otherwise, a bare git
+repo is initialized in the directory and becomes the biome.
+
+A relative directory is relative to the -C directory, if given. Without a
+directory, the biome is initialized in the -C directory, or in GH_BIOME_DIR
+if set, or else in the current directory.
+
+With --fetch-parallel, the number of remotes fetched in parallel is recorded in the biome as the fetch.parallel
+setting. Otherwise the setting is left unset, so git's default applies. It can be changed later with
+'biome config set fetch.parallel <n>'.
 
-This will initialize a new, bare git repo in the directory with configuration settings tuned for git biome support.
+How Git LFS objects are handled is recorded as the biome.lfs.policy setting.
+With skip, LFS objects are never downloaded, not even when a tool checks out
+files from the biome. With pointers, the default, only the LFS pointer files
+are fetched. With selected, the LFS objects of each remote's default branch are
+also fetched for remotes selected with 'biome config set
+biome.remote.<remote>.lfs true'.
+
+With --maintenance, the biome is registered with 'git maintenance' in the
+user's global git config, so the schedule installed by 'git maintenance
+start' also maintains the biome. With minimal, only the commit-graph is
+written hourly and loose objects are packed daily. With full, git's
+incremental strategy is used, except that remotes are not prefetched, since
+'biome fetch' fetches them. With off, the default, the biome is not
+registered, ex. for shared or CI environments that must not run background
+maintenance.
+
+With --object-format=sha256, the biome names objects with SHA-256 rather
+than SHA-1, if the installed git supports it. git does not convert objects
+between formats when fetching, so only remotes that also use SHA-256 can be
+fetched into such a biome. The object format cannot be changed after the
+biome is initialized.
+
+With --ref-format=reftable, references are stored in reftable files, which
+are much faster to read in bulk, if the installed git supports it (git 2.45
+or later). A reftable allows a single writer at a time, so fetches of such a
+biome only receive objects in parallel, and biome applies the reference
+updates of one remote at a time, as with 'biome fetch --jobs'. The choice is
+recorded as the biome.refFormat setting.
+`,
+	Example: `biome init
+
+biome init --fetch-parallel 8 --lfs skip my-biome
+
+biome init --maintenance minimal my-biome
+
+biome init --ref-format reftable my-biome
+
+biome -C ~/biomes init kubernetes
 `,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		path := "."
+		path := biomeDirectory()
 		if len(args) > 0 {
 			path = args[0]
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(directory, path)
+			}
+		}
+		if initFetchParallel < 0 {
+			return fmt.Errorf("invalid value for --fetch-parallel: %d: must not be negative", initFetchParallel)
+		}
+		// fetch.parallel is only recorded when chosen, so that git's own
+		// default applies otherwise
+		opts := slices.Clone(biomeOptions)
+		if cmd.Flags().Changed("fetch-parallel") {
+			opts = append(opts, biome.FetchParallel(initFetchParallel))
+		}
+		if initLFS != "" {
+			policy, err := biome.ParseLFSPolicy(initLFS)
+			if err != nil {
+				return err
+			}
+			opts = append(opts, biome.LFS(policy))
+		}
+		mode, err := biome.ParseMaintenanceMode(initMaintenance)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, biome.Maintenance(mode))
+		if initObjectFormat != "" {
+			format, err := biome.ParseObjectFormat(initObjectFormat)
+			if err != nil {
+				return err
+			}
+			opts = append(opts, biome.UseObjectFormat(format))
+		}
+		if initRefFormat != "" {
+			format, err := biome.ParseRefFormat(initRefFormat)
+			if err != nil {
+				return err
+			}
+			opts = append(opts, biome.UseRefFormat(format))
 		}
-		if _, err := biome.Init(cmd.Context(), path, biomeOptions...); err != nil {
+		if _, err := biome.Init(cmd.Context(), path, opts...); err != nil {
 			return fmt.Errorf("failed to initialize biome: %w", err)
 		}
-		_, err := fmt.Fprintf(cmd.OutOrStderr(), "git biome initialized in %s\n", path)
+		_, err = fmt.Fprintf(cmd.OutOrStderr(), "git biome initialized in %s\n", path)
 		return err
 	},
 }
@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/orirawlings/gh-biome/internal/biome"
@@ -17,6 +20,35 @@ func init() {
 
 func TestInitCmd_Execute(t *testing.T) {
 	initBiome(t)
+
+	// fetch.parallel is left to git's default unless chosen
+	if out, err := exec.Command("git", "config", "get", "--local", "fetch.parallel").CombinedOutput(); err == nil {
+		t.Errorf("expected fetch.parallel to be unset, was %q", out)
+	}
+}
+
+func TestInitCmd_Execute_fetchParallel(t *testing.T) {
+	oldOptions := biomeOptions
+	biomeOptions = []biome.BiomeOption{
+		biome.EditorOptions(config.HelperCommand(fmt.Sprintf("%s config-edit-helper", biomeBuildPath))),
+	}
+	t.Cleanup(func() {
+		biomeOptions = oldOptions
+		initFetchParallel = 0
+		initCmd.Flags().Lookup("fetch-parallel").Changed = false
+	})
+	path := t.TempDir()
+	rootCmd.SetArgs([]string{
+		"init",
+		"--fetch-parallel", "0",
+		path,
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	if out, err := exec.Command("git", "-C", path, "config", "get", "--local", "fetch.parallel").Output(); err != nil || strings.TrimSpace(string(out)) != "0" {
+		t.Errorf("expected fetch.parallel to be 0, was %q: %v", out, err)
+	}
 }
 
 func initBiome(t *testing.T) {
@@ -54,3 +86,87 @@ func initBiome(t *testing.T) {
 		os.Chdir(oldWD)
 	})
 }
+
+func TestInitCmd_Execute_lfs(t *testing.T) {
+	t.Cleanup(func() {
+		initLFS = ""
+	})
+	rootCmd.SetArgs([]string{
+		"init",
+		"--lfs", "everything",
+		t.TempDir(),
+	})
+	if err := rootCmd.Execute(); err == nil {
+		t.Errorf("expected error initializing biome with an invalid lfs policy")
+	}
+}
+
+func TestInitCmd_Execute_directory(t *testing.T) {
+	initBiome(t)
+	t.Cleanup(resetDirectory)
+	dir := t.TempDir()
+	rootCmd.SetArgs([]string{
+		"-C", dir,
+		"init",
+		"nested",
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	if _, err := biome.Load(context.Background(), filepath.Join(dir, "nested")); err != nil {
+		t.Errorf("expected a biome in the -C directory: %v", err)
+	}
+}
+
+func TestRootCmd_directory(t *testing.T) {
+	t.Cleanup(resetDirectory)
+	rootCmd.SetArgs([]string{
+		"-C", t.TempDir(),
+		"remotes",
+	})
+	if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "not a git repository") {
+		t.Errorf("expected error loading a biome from a -C directory that is not a git repository, was %v", err)
+	}
+}
+
+func TestInitCmd_Execute_maintenance(t *testing.T) {
+	t.Cleanup(func() {
+		initMaintenance = string(biome.MaintenanceOff)
+	})
+	rootCmd.SetArgs([]string{
+		"init",
+		"--maintenance", "sometimes",
+		t.TempDir(),
+	})
+	if err := rootCmd.Execute(); err == nil {
+		t.Errorf("expected error initializing biome with an invalid maintenance mode")
+	}
+}
+
+func TestInitCmd_Execute_objectFormat(t *testing.T) {
+	t.Cleanup(func() {
+		initObjectFormat = ""
+	})
+	rootCmd.SetArgs([]string{
+		"init",
+		"--object-format", "md5",
+		t.TempDir(),
+	})
+	if err := rootCmd.Execute(); err == nil {
+		t.Errorf("expected error initializing biome with an invalid object format")
+	}
+}
+
+func TestInitCmd_Execute_refFormat(t *testing.T) {
+	t.Cleanup(func() {
+		initRefFormat = ""
+	})
+	rootCmd.SetArgs([]string{
+		"init",
+		"--ref-format", "packed",
+		t.TempDir(),
+	})
+	if err := rootCmd.Execute(); err == nil {
+		t.Errorf("expected error initializing biome with an invalid ref format")
+	}
+}
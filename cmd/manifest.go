@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	"github.com/spf13/cobra"
+)
+
+// manifestFormat selects the multi-repository tool that the manifest is
+// written for.
+type manifestFormat string
+
+const (
+	// repoManifest is the manifest format of Google's repo tool.
+	repoManifest manifestFormat = "repo"
+
+	// jiriManifest is the manifest format of Fuchsia's jiri tool.
+	jiriManifest manifestFormat = "jiri"
+)
+
+func (f *manifestFormat) String() string {
+	return string(*f)
+}
+
+func (f *manifestFormat) Set(s string) error {
+	switch manifestFormat(s) {
+	case repoManifest, jiriManifest:
+		*f = manifestFormat(s)
+		return nil
+	}
+	return fmt.Errorf("must be one of %s, %s", repoManifest, jiriManifest)
+}
+
+func (f *manifestFormat) Type() string {
+	return "format"
+}
+
+var (
+	manifestOwners       []string
+	manifestOutputFormat = repoManifest
+)
+
+func init() {
+	manifestCmd.Flags().StringSliceVar(&manifestOwners, "owner", nil, "Only include the remotes of this owner. Can be repeated.")
+	manifestCmd.Flags().Var(&manifestOutputFormat, "format", fmt.Sprintf("Write the manifest in the format of this tool: %s, %s.", repoManifest, jiriManifest))
+	rootCmd.AddCommand(manifestCmd)
+}
+
+var manifestCmd = &cobra.Command{
+	Use:   "manifest",
+	Short: "Export the HEADs of the git biome's remotes as a multi-repository manifest",
+	Long: `
+Print a manifest of the git biome's fetchable remotes, pinning each remote's
+repository to the commit at its HEAD, as last fetched into the biome. Other
+machines can check out exactly the same state of every repository from
+GitHub with a multi-repository tool, without access to the biome itself.
+Use --owner to only include the remotes of some owners.
+
+Each project of the manifest is named after its remote, checked out at a
+path matching the remote's name, fetched from the remote's URL, and pinned
+to the commit at the remote's HEAD, tracking the remote's default branch.
+Remotes whose default branch has not been fetched are left out, with a
+warning.
+
+By default, the manifest is written in the format of Google's repo tool, with
+a <remote> for each GitHub host. Use --format jiri for Fuchsia's jiri tool.
+`,
+	Example: `biome manifest > default.xml
+
+biome manifest --owner github.com/cli --owner github.com/orirawlings
+
+biome manifest --format jiri > manifest
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := validOwnerRefs(cmd, manifestOwners); err != nil {
+			return err
+		}
+		owners, err := parseOwners(manifestOwners)
+		if err != nil {
+			return err
+		}
+		if err := validateOwnersPresent(ctx, b, owners); err != nil {
+			return err
+		}
+
+		projects, err := b.Manifest(ctx, owners...)
+		if err != nil {
+			return err
+		}
+		var pinned []biome.ManifestProject
+		for _, p := range projects {
+			if p.Commit == "" {
+				cmd.PrintErrf("Skipping %s, its default branch has not been fetched\n", p.Remote)
+				continue
+			}
+			pinned = append(pinned, p)
+		}
+		return writeManifest(cmd.OutOrStdout(), manifestOutputFormat, pinned)
+	},
+}
+
+// repoManifestXML is a manifest of Google's repo tool, see
+// https://gerrit.googlesource.com/git-repo/+/HEAD/docs/manifest-format.md
+type repoManifestXML struct {
+	XMLName  xml.Name `xml:"manifest"`
+	Remotes  []repoRemoteXML
+	Projects []repoProjectXML
+}
+
+type repoRemoteXML struct {
+	XMLName xml.Name `xml:"remote"`
+	Name    string   `xml:"name,attr"`
+	Fetch   string   `xml:"fetch,attr"`
+}
+
+type repoProjectXML struct {
+	XMLName  xml.Name `xml:"project"`
+	Name     string   `xml:"name,attr"`
+	Path     string   `xml:"path,attr"`
+	Remote   string   `xml:"remote,attr"`
+	Revision string   `xml:"revision,attr"`
+	Upstream string   `xml:"upstream,attr,omitempty"`
+}
+
+// jiriManifestXML is a manifest of Fuchsia's jiri tool, see
+// https://fuchsia.googlesource.com/jiri/+/HEAD/manifest.md
+type jiriManifestXML struct {
+	XMLName  xml.Name         `xml:"manifest"`
+	Projects []jiriProjectXML `xml:"projects>project"`
+}
+
+type jiriProjectXML struct {
+	Name         string `xml:"name,attr"`
+	Path         string `xml:"path,attr"`
+	Remote       string `xml:"remote,attr"`
+	RemoteBranch string `xml:"remotebranch,attr,omitempty"`
+	Revision     string `xml:"revision,attr"`
+}
+
+// writeManifest writes the projects as a manifest in the given format.
+func writeManifest(w io.Writer, format manifestFormat, projects []biome.ManifestProject) error {
+	var manifest any
+	switch format {
+	case jiriManifest:
+		m := jiriManifestXML{}
+		for _, p := range projects {
+			m.Projects = append(m.Projects, jiriProjectXML{
+				Name:         p.Remote,
+				Path:         p.Remote,
+				Remote:       p.URL,
+				RemoteBranch: p.Branch,
+				Revision:     p.Commit,
+			})
+		}
+		manifest = m
+	default:
+		m := repoManifestXML{}
+		hosts := make(map[string]bool)
+		for _, p := range projects {
+			host, name, _ := strings.Cut(p.Remote, "/")
+			if !hosts[host] {
+				hosts[host] = true
+				m.Remotes = append(m.Remotes, repoRemoteXML{
+					Name:  host,
+					Fetch: "https://" + host + "/",
+				})
+			}
+			m.Projects = append(m.Projects, repoProjectXML{
+				Name:     name,
+				Path:     p.Remote,
+				Remote:   host,
+				Revision: p.Commit,
+				Upstream: p.Branch,
+			})
+		}
+		manifest = m
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(manifest); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
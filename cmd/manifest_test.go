@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func init() {
+	manifestCmd.SetContext(context.Background())
+	pushInContext(manifestCmd)
+}
+
+func TestManifestCmd_Execute(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_orirawlings.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+
+	tree := strings.TrimSpace(testutil.Execute(t, "git", "write-tree"))
+	commit := strings.TrimSpace(testutil.Execute(t, "git", "-c", "user.name=A", "-c", "user.email=a@example.com", "commit-tree", "-m", "main", tree))
+	testutil.Execute(t, "git", "update-ref", "refs/remotes/github.com/orirawlings/bar/heads/main", commit)
+	testutil.Execute(t, "git", "symbolic-ref", "refs/remotes/github.com/orirawlings/bar/HEAD", "refs/remotes/github.com/orirawlings/bar/heads/main")
+
+	for _, run := range []struct {
+		args     []string
+		expected []string
+	}{
+		{
+			expected: []string{
+				`<?xml version="1.0" encoding="UTF-8"?>`,
+				`<manifest>`,
+				`  <remote name="github.com" fetch="https://github.com/"></remote>`,
+				`  <project name="orirawlings/bar" path="github.com/orirawlings/bar" remote="github.com" revision="` + commit + `" upstream="main"></project>`,
+				`</manifest>`,
+			},
+		},
+		{
+			args: []string{"--format", "jiri", "--owner", github_com_orirawlings.String()},
+			expected: []string{
+				`<?xml version="1.0" encoding="UTF-8"?>`,
+				`<manifest>`,
+				`  <projects>`,
+				`    <project name="github.com/orirawlings/bar" path="github.com/orirawlings/bar" remote="https://github.com/orirawlings/bar.git" remotebranch="main" revision="` + commit + `"></project>`,
+				`  </projects>`,
+				`</manifest>`,
+			},
+		},
+	} {
+		t.Run(strings.Join(run.args, " "), func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			manifestCmd.SetOut(buf)
+			t.Cleanup(func() {
+				manifestCmd.SetOut(nil)
+				manifestOwners = nil
+				manifestOutputFormat = repoManifest
+			})
+			rootCmd.SetArgs(append([]string{"manifest"}, run.args...))
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("unexpected error executing command: %v", err)
+			}
+			expected := strings.Join(run.expected, "\n") + "\n"
+			if buf.String() != expected {
+				t.Errorf("expected %q, got %q", expected, buf.String())
+			}
+		})
+	}
+
+	rootCmd.SetArgs([]string{"manifest", "--owner", github_com_cli.String()})
+	t.Cleanup(func() {
+		manifestOwners = nil
+	})
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("expected error for owner that is not in the biome")
+	}
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	"github.com/spf13/cobra"
+)
+
+var (
+	manifestsCollectOwners []string
+	manifestsCollectFiles  []string
+	manifestsCollectDir    string
+)
+
+func init() {
+	manifestsCollectCmd.Flags().StringSliceVar(&manifestsCollectOwners, "owner", nil, "Only collect from the remotes of this owner. Can be repeated.")
+	manifestsCollectCmd.Flags().StringSliceVar(&manifestsCollectFiles, "file", biome.DependencyFiles, "Collect files with this name. Can be repeated.")
+	manifestsCollectCmd.Flags().StringVarP(&manifestsCollectDir, "output-dir", "o", "", "Write the files into this directory, at <remote>/<path>, rather than printing them as a stream of JSON objects.")
+	manifestsCmd.AddCommand(manifestsCollectCmd)
+	rootCmd.AddCommand(manifestsCmd)
+}
+
+var manifestsCmd = &cobra.Command{
+	Use:   "manifests",
+	Short: "Analyze the dependency manifests of the git biome's remotes",
+	Long: `
+Dependency manifests, ex. go.mod or package.json, declare the dependencies of
+a repository. Collecting them from every remote of the git biome enables
+organization-wide dependency analysis, ex. finding every repository that
+depends on a vulnerable module, without checking any repository out.
+
+Not to be confused with 'biome manifest', which exports the HEADs of the
+biome's remotes as a multi-repository manifest.
+`,
+	Example: `biome manifests collect -o deps
+`,
+}
+
+var manifestsCollectCmd = &cobra.Command{
+	Use:   "collect",
+	Short: "Extract the dependency manifests at the HEAD of every remote",
+	Long: `
+Extract the dependency manifests at the HEAD of each of the git biome's
+fetchable remotes, read directly from the biome's object database. Use
+--owner to only collect from the remotes of some owners.
+
+Files named go.mod, package.json, requirements.txt, or pom.xml are collected
+from anywhere in each remote's tree, or only files with the names given by
+--file. Files within directories of vendored dependencies, ex. vendor/ and
+node_modules/, are skipped. Remotes whose default branch has not been
+fetched are skipped too.
+
+By default, each file is printed as a JSON object on its own line, with the
+remote, the commit at its HEAD, the file's path, and its content, so that
+the stream can be processed with tools like jq. Use --output-dir to write
+the files into a directory instead, at <remote>/<path>, ex.
+deps/github.com/cli/cli/go.mod.
+`,
+	Example: `biome manifests collect -o deps
+
+biome manifests collect --owner github.com/cli --file go.mod | jq -r .path
+
+biome manifests collect --file Cargo.toml --file Gemfile
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := validOwnerRefs(cmd, manifestsCollectOwners); err != nil {
+			return err
+		}
+		owners, err := parseOwners(manifestsCollectOwners)
+		if err != nil {
+			return err
+		}
+		if err := validateOwnersPresent(ctx, b, owners); err != nil {
+			return err
+		}
+
+		manifests, err := b.DependencyManifests(ctx, manifestsCollectFiles, owners...)
+		if err != nil {
+			return err
+		}
+		if manifestsCollectDir == "" {
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			for _, m := range manifests {
+				if err := enc.Encode(m); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		var collected int
+		remotes := make(map[string]bool)
+		for _, m := range manifests {
+			if !filepath.IsLocal(filepath.FromSlash(m.Path)) {
+				// a crafted tree must not write outside the directory
+				cmd.PrintErrf("Skipping %s of %s, its path is not local\n", m.Path, m.Remote)
+				continue
+			}
+			path := filepath.Join(manifestsCollectDir, filepath.FromSlash(m.Remote), filepath.FromSlash(m.Path))
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return fmt.Errorf("could not create directory for %s: %w", m.Path, err)
+			}
+			if err := os.WriteFile(path, []byte(m.Content), 0o644); err != nil {
+				return fmt.Errorf("could not write %s of %s: %w", m.Path, m.Remote, err)
+			}
+			collected++
+			remotes[m.Remote] = true
+		}
+		cmd.PrintErrf("Collected %d dependency manifests of %d remotes into %s\n", collected, len(remotes), manifestsCollectDir)
+		return nil
+	},
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func init() {
+	manifestsCollectCmd.SetContext(context.Background())
+	pushInContext(manifestsCollectCmd)
+}
+
+func TestManifestsCollectCmd_Execute(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_orirawlings.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	t.Cleanup(func() {
+		manifestsCollectDir = ""
+		manifestsCollectOwners = nil
+	})
+
+	file := filepath.Join(t.TempDir(), "go.mod")
+	if err := os.WriteFile(file, []byte("module github.com/orirawlings/bar\n"), 0o644); err != nil {
+		t.Fatalf("unexpected error writing file: %v", err)
+	}
+	blob := strings.TrimSpace(testutil.Execute(t, "git", "hash-object", "-w", file))
+	tree := strings.TrimSpace(testutil.Execute(t, "sh", "-c", fmt.Sprintf(`printf '100644 blob %s\tgo.mod\n' | git mktree`, blob)))
+	commit := strings.TrimSpace(testutil.Execute(t, "git", "-c", "user.name=A", "-c", "user.email=a@example.com", "commit-tree", "-m", "main", tree))
+	testutil.Execute(t, "git", "update-ref", "refs/remotes/github.com/orirawlings/bar/heads/main", commit)
+	testutil.Execute(t, "git", "symbolic-ref", "refs/remotes/github.com/orirawlings/bar/HEAD", "refs/remotes/github.com/orirawlings/bar/heads/main")
+
+	buf := new(bytes.Buffer)
+	rootCmd.SetOut(buf)
+	rootCmd.SetErr(buf)
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+	})
+	rootCmd.SetArgs([]string{"manifests", "collect"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	expected := fmt.Sprintf(`{"remote":"github.com/orirawlings/bar","commit":"%s","path":"go.mod","content":"module github.com/orirawlings/bar\n"}`+"\n", commit)
+	if buf.String() != expected {
+		t.Errorf("unexpected output: wanted %q, was %q", expected, buf.String())
+	}
+
+	dir := t.TempDir()
+	buf.Reset()
+	rootCmd.SetArgs([]string{"manifests", "collect", "-o", dir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	if expected := fmt.Sprintf("Collected 1 dependency manifests of 1 remotes into %s\n", dir); buf.String() != expected {
+		t.Errorf("unexpected output: wanted %q, was %q", expected, buf.String())
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "github.com", "orirawlings", "bar", "go.mod"))
+	testutil.Check(t, err)
+	if string(content) != "module github.com/orirawlings/bar\n" {
+		t.Errorf("unexpected content of collected go.mod: %q", content)
+	}
+
+	rootCmd.SetArgs([]string{"manifests", "collect", "--owner", my_github_biz_foobar.String()})
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("expected error collecting from an owner that is not in the biome")
+	}
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	cmdutil "github.com/orirawlings/gh-biome/internal/util/command"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mergeBaseAllForks bool
+)
+
+func init() {
+	mergeBaseCmd.Flags().BoolVar(&mergeBaseAllForks, "all-forks", false, "Compare the remote with every other fetched remote, listing the common ancestor with each remote that shares its history.")
+	rootCmd.AddCommand(mergeBaseCmd)
+}
+
+var mergeBaseCmd = &cobra.Command{
+	Use:   "merge-base <remote> <other-remote>",
+	Short: "Find common ancestors of remotes' default branches, such as forks",
+	Long: `
+Find the best common ancestor of the default branches of two remotes, ex.
+a repository and one of its forks. Because every remote shares the biome's
+object database, nothing needs to be fetched to compare them.
+
+With --all-forks, only one remote is given. Its default branch is compared
+with the default branch of every other fetched remote, printing the common
+ancestor and name of each remote that shares its history.
+
+Remotes are named with the following format.
+
+	<host>/<owner-name>/<repo-name>
+`,
+	Example: `biome merge-base github.com/cli/cli github.com/orirawlings/cli
+
+biome merge-base --all-forks github.com/cli/cli
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if mergeBaseAllForks {
+			return cobra.ExactArgs(1)(cmd, args)
+		}
+		return cobra.ExactArgs(2)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		if mergeBaseAllForks {
+			bases, err := b.ForkMergeBases(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			for _, base := range bases {
+				cmdutil.Println(cmd, fmt.Sprintf("%s %s", base.Commit, base.Remote))
+			}
+			return nil
+		}
+
+		commit, err := b.MergeBase(ctx, args[0], args[1])
+		if err != nil {
+			return err
+		}
+		if commit == "" {
+			return errors.New("remotes do not share any history")
+		}
+		cmdutil.Println(cmd, commit)
+		return nil
+	},
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func init() {
+	mergeBaseCmd.SetContext(context.Background())
+	pushInContext(mergeBaseCmd)
+}
+
+func TestMergeBaseCmd_Execute(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_cli.String(),
+		github_com_orirawlings.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+
+	buf := new(bytes.Buffer)
+	mergeBaseCmd.SetOut(buf)
+	t.Cleanup(func() {
+		mergeBaseCmd.SetOut(nil)
+		mergeBaseAllForks = false
+		mergeBaseCmd.Flags().Lookup("all-forks").Changed = false
+	})
+
+	// remotes have not been fetched
+	rootCmd.SetArgs([]string{"merge-base", "github.com/cli/cli", "github.com/orirawlings/bar"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Errorf("expected error for remotes that have not been fetched")
+	}
+
+	rootCmd.SetArgs([]string{"merge-base", "--all-forks", "github.com/cli/cli", "github.com/orirawlings/bar"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Errorf("expected error for extra arguments with --all-forks")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output, got %q", buf.String())
+	}
+}
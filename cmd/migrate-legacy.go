@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	cmdutil "github.com/orirawlings/gh-biome/internal/util/command"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(migrateLegacyCmd)
+}
+
+var migrateLegacyCmd = &cobra.Command{
+	Use:   "migrate-legacy [<directory>]",
+	Short: "Upgrade a repository configured by the legacy add-remotes command to a git biome",
+	Long: `
+Upgrade a repository that was configured by the deprecated add-remotes
+command, whose remotes record whether they are archived with
+remote.<remote>.archived, to the current git biome configuration.
+
+The owners of the biome are inferred from the names of the remotes, ex.
+github.com/cli for the remote github.com/cli/cli, and each remote is recorded
+as active or archived. Remotes that are not named <host>/<owner>/<repo> are
+left alone. The migration is non-destructive: remote URLs, fetch refspecs,
+and references are kept, and the next 'biome fetch' or 'biome sync-config'
+reconciles the remotes with GitHub.
+
+Without a directory, the repository in the -C directory, or in GH_BIOME_DIR
+if set, or else in the current directory is upgraded.
+
+Other commands refuse to run against a repository that needs this upgrade,
+unless --migrate is given to upgrade it before running the command.
+`,
+	Example: `biome migrate-legacy
+
+biome migrate-legacy ~/old-biome
+`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := biomeDirectory()
+		if len(args) > 0 {
+			path = args[0]
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(directory, path)
+			}
+		}
+		_, migration, err := biome.MigrateLegacy(cmd.Context(), path, biomeOptions...)
+		if err != nil {
+			return fmt.Errorf("failed to migrate legacy configuration: %w", err)
+		}
+		for _, owner := range migration.Owners {
+			cmdutil.Println(cmd, fmt.Sprintf("Inferred owner %s", owner))
+		}
+		for _, name := range migration.Skipped {
+			cmd.PrintErrf("Warning: skipped remote %s, its owner could not be inferred from its name\n", name)
+		}
+		cmdutil.Println(cmd, fmt.Sprintf("Migrated %d remotes to git biome in %s", len(migration.Remotes), path))
+		return nil
+	},
+}
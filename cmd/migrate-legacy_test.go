@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	"github.com/orirawlings/gh-biome/internal/config"
+)
+
+func TestMigrateLegacyCmd_Execute(t *testing.T) {
+	t.Cleanup(resetDirectory)
+
+	// override biome options
+	oldOptions := biomeOptions
+	biomeOptions = []biome.BiomeOption{
+		biome.EditorOptions(config.HelperCommand(fmt.Sprintf("%s config-edit-helper", biomeBuildPath))),
+	}
+	t.Cleanup(func() {
+		biomeOptions = oldOptions
+	})
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "legacy")
+	for _, args := range [][]string{
+		{"init", "--quiet", "--bare", path},
+		{"-C", path, "config", "remote.github.com/cli/cli.url", "https://github.com/cli/cli.git"},
+		{"-C", path, "config", "remote.github.com/cli/cli.archived", "false"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("unexpected error exec'ing git %q: %v: %s", args, err, out)
+		}
+	}
+
+	rootCmd.SetArgs([]string{
+		"-C", path,
+		"remotes",
+	})
+	if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "migrate-legacy") {
+		t.Errorf("expected error explaining how to migrate a legacy repository, was %v", err)
+	}
+
+	rootCmd.SetArgs([]string{
+		"-C", dir,
+		"migrate-legacy",
+		"legacy",
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+
+	// the repository is now a biome
+	rootCmd.SetArgs([]string{
+		"-C", dir,
+		"migrate-legacy",
+		"legacy",
+	})
+	if err := rootCmd.Execute(); err == nil {
+		t.Errorf("expected error migrating a biome")
+	}
+}
+
+func TestRootCmd_migrate(t *testing.T) {
+	t.Cleanup(resetDirectory)
+	t.Cleanup(func() {
+		migrate = false
+		remotesOptions.Reset()
+	})
+
+	// override biome options
+	oldOptions := biomeOptions
+	biomeOptions = []biome.BiomeOption{
+		biome.EditorOptions(config.HelperCommand(fmt.Sprintf("%s config-edit-helper", biomeBuildPath))),
+	}
+	t.Cleanup(func() {
+		biomeOptions = oldOptions
+	})
+
+	path := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--quiet", "--bare", path},
+		{"-C", path, "config", "remote.github.com/cli/cli.url", "https://github.com/cli/cli.git"},
+		{"-C", path, "config", "remote.github.com/cli/cli.archived", "true"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("unexpected error exec'ing git %q: %v: %s", args, err, out)
+		}
+	}
+
+	rootCmd.SetArgs([]string{
+		"-C", path,
+		"--migrate",
+		"remotes",
+		"--archived",
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	if _, err := biome.Load(context.Background(), path); err != nil {
+		t.Errorf("expected the legacy repository to be migrated: %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"github.com/cli/go-gh/v2/pkg/browser"
+	"github.com/spf13/cobra"
+)
+
+var openCommit bool
+
+// browse opens the URL in the user's web browser, as configured for gh.
+// Tests replace it to avoid launching a browser.
+var browse = func(cmd *cobra.Command, u string) error {
+	return browser.New("", cmd.OutOrStdout(), cmd.ErrOrStderr()).Browse(u)
+}
+
+func init() {
+	openCmd.Flags().BoolVar(&openCommit, "commit", false, "Open the page of the commit at the remote's HEAD, or at the given reference, rather than the repository's home page.")
+	rootCmd.AddCommand(openCmd)
+}
+
+var openCmd = &cobra.Command{
+	Use:   "open <ref-or-remote>[:<path>[:<line>]]",
+	Short: "Open the GitHub page of a remote in the web browser",
+	Long: `
+Open the page of a remote's repository on its GitHub server in the web
+browser, translating the biome's remote name back to the repository's web
+URL. The browser is chosen as gh chooses it, from the GH_BROWSER environment
+variable, gh's browser setting, or the BROWSER environment variable.
+
+Name a path after the remote's name to open the page of the file or
+directory at that path, at the tip of the remote's default branch. Use
+--commit to open the page of the commit at the tip of the remote's default
+branch instead of the repository's home page.
+
+Name a branch or tag after the remote's name to open the page of its commit,
+or of a path at its commit. References are named relative to the remote's
+namespace, as with 'biome cat', ex. "main", "heads/main", or "tags/v1.0".
+References and commits can also be named as with 'biome url', ex. by the
+full reference names that git commands print.
+
+Pages of commits and paths name the commit by its object ID, as fetched in
+the biome, so they show the same content as the biome even after the
+remote's branches move on.
+
+Remotes are named with the following format.
+
+	<host>/<owner-name>/<repo-name>
+`,
+	Example: `biome open github.com/cli/cli
+
+biome open --commit github.com/cli/cli
+
+biome open github.com/cli/cli:go.mod
+
+biome open github.com/cli/cli@tags/v2.0.0:pkg/cmd
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spec, err := parseWebSpec(args[0])
+		if err != nil {
+			return err
+		}
+		if openCommit && spec.rev == "" && spec.ref == "" {
+			spec.ref = "HEAD"
+		}
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+		u, err := spec.url(ctx, b)
+		if err != nil {
+			return err
+		}
+		cmd.PrintErrf("Opening %s in your browser.\n", u)
+		return browse(cmd, u)
+	},
+}
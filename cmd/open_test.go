@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	openCmd.SetContext(context.Background())
+	pushInContext(openCmd)
+}
+
+func TestOpenCmd_Execute(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_orirawlings.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	tree := strings.TrimSpace(testutil.Execute(t, "git", "write-tree"))
+	commit := strings.TrimSpace(testutil.Execute(t, "git", "-c", "user.name=A", "-c", "user.email=a@example.com", "commit-tree", "-m", "main", tree))
+	testutil.Execute(t, "git", "update-ref", "refs/remotes/github.com/orirawlings/bar/heads/main", commit)
+	testutil.Execute(t, "git", "symbolic-ref", "refs/remotes/github.com/orirawlings/bar/HEAD", "refs/remotes/github.com/orirawlings/bar/heads/main")
+
+	var opened []string
+	original := browse
+	browse = func(cmd *cobra.Command, u string) error {
+		opened = append(opened, u)
+		return nil
+	}
+	t.Cleanup(func() {
+		browse = original
+		openCommit = false
+	})
+
+	for _, args := range [][]string{
+		{"open", "github.com/orirawlings/bar"},
+		{"open", "--commit", "github.com/orirawlings/bar"},
+		{"open", "github.com/orirawlings/bar@main"},
+	} {
+		rootCmd.SetArgs(args)
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error executing command: %v", err)
+		}
+	}
+	expected := []string{
+		"https://github.com/orirawlings/bar",
+		"https://github.com/orirawlings/bar/commit/" + commit,
+		"https://github.com/orirawlings/bar/commit/" + commit,
+	}
+	if !slices.Equal(opened, expected) {
+		t.Errorf("expected to open %q, opened %q", expected, opened)
+	}
+
+	rootCmd.SetArgs([]string{"open", "github.com/orirawlings/bar:missing.md"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("expected error opening a missing path")
+	}
+}
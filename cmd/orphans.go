@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// orphansCmd represents the orphans command
+var orphansCmd = &cobra.Command{
+	Use:   "orphans",
+	Short: "List remotes that are missing from every remote category",
+	Long: `
+List the orphaned remotes of the git biome: git remotes named like the biome's
+remotes, <host>/<owner>/<repo>, and references in the namespace of such a
+remote, that are not recorded in any of the biome's remote categories, ex.
+because they were left over by a crash or by manual edits of the git config.
+Orphaned remotes are not fetched by the biome, and are not listed by 'biome
+remotes', but their references are still found by git commands.
+
+For each orphaned remote, whether it is configured as a git remote, the number
+of its references, and the reference namespaces holding them are listed. Other
+git remotes, ex. origin in a non-bare repository, and snapshots are never
+orphans.
+
+Use --reconcile to update the git remote configurations first, as 'biome
+sync-config' does, so that orphaned git remotes whose repositories are still
+owned by the biome's owners are recorded again, and the others are removed
+along with their references. Use --remove to remove the git remote
+configurations and references of the remaining orphans.
+
+Use --format csv to print the orphans as comma separated values.
+`,
+	Example: `biome orphans
+
+biome orphans --reconcile
+
+biome orphans --reconcile --remove
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		if orphansReconcile {
+			if err := warnAnonymous(ctx, cmd, b); err != nil {
+				return err
+			}
+			cmd.PrintErrln("Updating git remote configurations...")
+			if err := updateRemotes(cmd, b); err != nil {
+				return err
+			}
+		}
+
+		orphans, err := b.Orphans(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := newReportWriter(cmd, orphansFormat, "remote", "configured", "refs", "namespaces")
+		for _, o := range orphans {
+			w.Row(o.Remote, strconv.FormatBool(o.Configured), strconv.Itoa(o.References), strings.Join(o.Namespaces, " "))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		if orphansRemove && len(orphans) > 0 {
+			if err := b.RemoveOrphans(ctx, orphans); err != nil {
+				return err
+			}
+			cmd.PrintErrf("Removed %d orphaned remotes\n", len(orphans))
+		}
+		return nil
+	},
+}
+
+var (
+	orphansReconcile bool
+	orphansRemove    bool
+	orphansFormat    outputFormat
+)
+
+func init() {
+	rootCmd.AddCommand(orphansCmd)
+	orphansCmd.Flags().BoolVar(&orphansReconcile, "reconcile", false, "Update the git remote configurations before looking for orphans.")
+	orphansCmd.Flags().BoolVar(&orphansRemove, "remove", false, "Remove the git remote configurations and references of orphaned remotes.")
+	addFormatFlag(orphansCmd.Flags(), &orphansFormat)
+}
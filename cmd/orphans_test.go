@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func init() {
+	orphansCmd.SetContext(context.Background())
+	pushInContext(orphansCmd)
+}
+
+func TestOrphansCmd_Execute(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_orirawlings.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+
+	// a remote left over by manual edits of the git config
+	tree := strings.TrimSpace(testutil.Execute(t, "git", "write-tree"))
+	commit := strings.TrimSpace(testutil.Execute(t, "git", "-c", "user.name=A", "-c", "user.email=a@example.com", "commit-tree", "-m", "gone", tree))
+	testutil.Execute(t, "git", "config", "set", "remote.github.com/orirawlings/gone.url", "https://github.com/orirawlings/gone.git")
+	testutil.Execute(t, "git", "update-ref", "refs/remotes/github.com/orirawlings/gone/heads/main", commit)
+
+	for _, run := range []struct {
+		args     []string
+		expected []string
+	}{
+		{
+			args: []string{"--format", "csv"},
+			expected: []string{
+				"remote,configured,refs,namespaces",
+				"github.com/orirawlings/gone,true,1,refs/remotes/github.com/orirawlings/gone/",
+			},
+		},
+		{
+			args: []string{"--remove"},
+			expected: []string{
+				"REMOTE                       CONFIGURED  REFS  NAMESPACES",
+				"github.com/orirawlings/gone  true        1     refs/remotes/github.com/orirawlings/gone/",
+			},
+		},
+		{
+			args: []string{"--format", "csv"},
+			expected: []string{
+				"remote,configured,refs,namespaces",
+			},
+		},
+	} {
+		t.Run(strings.Join(run.args, " "), func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			orphansCmd.SetOut(buf)
+			t.Cleanup(func() {
+				orphansCmd.SetOut(nil)
+				orphansReconcile = false
+				orphansRemove = false
+				orphansFormat = tableFormat
+			})
+			rootCmd.SetArgs(append([]string{"orphans"}, run.args...))
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("unexpected error executing command: %v", err)
+			}
+			expected := strings.Join(run.expected, "\n") + "\n"
+			if buf.String() != expected {
+				t.Errorf("expected %q, got %q", expected, buf.String())
+			}
+		})
+	}
+
+	if remotes := strings.TrimSpace(testutil.Execute(t, "git", "remote")); strings.Contains(remotes, "gone") {
+		t.Errorf("expected orphaned remote to be removed, was %q", remotes)
+	}
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(pauseCmd)
+	rootCmd.AddCommand(resumeCmd)
+}
+
+var pauseCmd = &cobra.Command{
+	Use:   "pause <github-owner> [...]",
+	Short: "Pause GitHub user(s) or organization(s) in the git biome without removing them",
+	Long: `
+Pause GitHub repository owners that were previously added to the git biome. An
+owner is a GitHub user or organization.
+
+The remotes of a paused owner stay configured and their references remain
+queryable, but the owner's repositories are not discovered when remotes are
+updated, and its remotes are not fetched, until the owner is resumed. This is
+useful during upstream incidents or audits.
+
+<github-owner> is specified with the following format, where <host> is the GitHub
+server name and <owner-name> is the name of the GitHub user or organziation within
+the server. If <host> is omitted, "github.com" is assumed.
+
+	[https://][<host>/]<owner-name>
+`,
+	Example: `biome pause github.com/orirawlings
+`,
+	Args: cobra.MatchAll(
+		cobra.MinimumNArgs(1),
+		validOwnerRefs,
+	),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		owners, err := parseOwners(args)
+		if err != nil {
+			return err
+		}
+		if err := validateOwnersPresent(ctx, b, owners); err != nil {
+			return err
+		}
+		for _, owner := range owners {
+			cmd.PrintErrf("Pausing %s...\n", owner)
+			if err := b.SetOwnerSetting(ctx, owner, "paused", "true"); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var resumeCmd = &cobra.Command{
+	Use:   "resume <github-owner> [...]",
+	Short: "Resume paused GitHub user(s) or organization(s) in the git biome",
+	Long: `
+Resume GitHub repository owners that were paused with 'biome pause'. The
+owners' repositories are discovered and fetched again by the next fetch.
+
+<github-owner> is specified with the following format, where <host> is the GitHub
+server name and <owner-name> is the name of the GitHub user or organziation within
+the server. If <host> is omitted, "github.com" is assumed.
+
+	[https://][<host>/]<owner-name>
+`,
+	Example: `biome resume github.com/orirawlings
+`,
+	Args: cobra.MatchAll(
+		cobra.MinimumNArgs(1),
+		validOwnerRefs,
+	),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		owners, err := parseOwners(args)
+		if err != nil {
+			return err
+		}
+		if err := validateOwnersPresent(ctx, b, owners); err != nil {
+			return err
+		}
+		for _, owner := range owners {
+			cmd.PrintErrf("Resuming %s...\n", owner)
+			if err := b.UnsetOwnerSetting(ctx, owner, "paused"); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
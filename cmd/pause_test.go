@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+)
+
+func init() {
+	pauseCmd.SetContext(context.Background())
+	pushInContext(pauseCmd)
+	resumeCmd.SetContext(context.Background())
+	pushInContext(resumeCmd)
+}
+
+func TestPauseCmd_Execute(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_cli.String(),
+		github_com_orirawlings.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+
+	rootCmd.SetArgs([]string{"pause", github_com_orirawlings.String()})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	rootCmd.SetArgs([]string{"sync-config"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	rootCmd.SetArgs([]string{"resume", github_com_orirawlings.String()})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+
+	// owners must have been added to the biome
+	rootCmd.SetArgs([]string{"pause", my_github_biz_foobar.String()})
+	if err := rootCmd.Execute(); err == nil {
+		t.Fatalf("expected error pausing an owner that is not in the biome")
+	}
+	rootCmd.SetArgs([]string{"resume"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Fatalf("expected error resuming without owners")
+	}
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+
+	cmdutil "github.com/orirawlings/gh-biome/internal/util/command"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	pruneExpire string
+	pruneDryRun bool
+)
+
+func init() {
+	pruneObjectsCmd.Flags().StringVar(&pruneExpire, "expire", "2.weeks.ago", "Prune unreachable objects older than this git date. Must be at least one day ago.")
+	pruneObjectsCmd.Flags().BoolVarP(&pruneDryRun, "dry-run", "n", false, "Do not prune anything. Show an estimate of the disk space that could be reclaimed.")
+	rootCmd.AddCommand(pruneObjectsCmd)
+}
+
+var pruneObjectsCmd = &cobra.Command{
+	Use:   "prune-objects",
+	Short: "Reclaim the disk used by unreachable git objects in the git biome",
+	Long: `
+Prune the git objects that are no longer reachable from any reference, ex. the
+objects of removed or evicted remotes, reclaiming their disk space. Reflog
+entries and entries of the biome's journal are expired according to the
+biome.retention.reflog and biome.retention.journal settings.
+
+Only unreachable objects older than --expire, a git date, are pruned. Fetches write objects before they update the references that make
+the objects reachable, so the expiry must be at least one day ago, to keep the
+objects of fetches that are in progress.
+
+Use --dry-run to see how much disk space could be reclaimed, without changing
+anything. The estimate counts every unreachable object, so it is an upper
+bound.
+`,
+	Example: `biome prune-objects
+
+biome prune-objects --dry-run
+
+biome prune-objects --expire 3.days.ago
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		if pruneDryRun {
+			plan, err := b.PlanPruneObjects(ctx, pruneExpire)
+			if err != nil {
+				return err
+			}
+			cmdutil.Println(cmd, fmt.Sprintf("Would prune unreachable objects older than %s, up to approximately %s of %s reclaimable", plan.Expire.Format("2006-01-02 15:04:05"), formatBytes(plan.ReclaimableBytes), formatBytes(plan.Bytes)))
+			return nil
+		}
+
+		cmd.PrintErrf("Pruning unreachable objects older than %s...\n", pruneExpire)
+		reclaimed, err := b.PruneObjects(ctx, pruneExpire)
+		if err != nil {
+			return err
+		}
+		cmdutil.Println(cmd, fmt.Sprintf("Reclaimed %s", formatBytes(reclaimed)))
+		return nil
+	},
+}
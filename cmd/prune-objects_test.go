@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+)
+
+func init() {
+	pruneObjectsCmd.SetContext(context.Background())
+	pushInContext(pruneObjectsCmd)
+}
+
+func TestPruneObjectsCmd_Args(t *testing.T) {
+	rootCmd.SetArgs([]string{"prune-objects", "github.com/orirawlings"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("expected error executing prune-objects with arguments")
+	}
+}
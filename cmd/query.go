@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	"github.com/spf13/cobra"
+)
+
+var (
+	querySync   bool
+	queryJSON   bool
+	queryFormat outputFormat
+)
+
+func init() {
+	queryCmd.Flags().BoolVar(&querySync, "sync", false, "Sync the metadata database before querying it, as 'db sync' does.")
+	queryCmd.Flags().BoolVar(&queryJSON, "json", false, "Print the selected rows as a JSON array of objects.")
+	addFormatFlag(queryCmd.Flags(), &queryFormat)
+	rootCmd.AddCommand(queryCmd)
+}
+
+var queryCmd = &cobra.Command{
+	Use:   "query <sql>",
+	Short: "Query the biome's metadata database with read-only SQL",
+	Long: `
+Execute read-only SQL against the git biome's SQLite metadata database, and
+print the rows selected by its last statement, turning the biome into a
+queryable inventory. See 'db sync' for the database's tables. The database is
+queried as of its last sync, unless --sync is given.
+
+Statements that would modify the database, or attach any other database,
+fail.
+`,
+	Example: `biome query --sync "SELECT owner, count(*) AS remotes FROM remotes GROUP BY owner"
+
+biome query "SELECT remote FROM remotes JOIN heads USING (remote) WHERE NOT archived AND julianday('now') - julianday(committed_at) > 180"
+
+biome query --format csv "SELECT * FROM fetches WHERE failed" > failed-fetches.csv
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		if querySync {
+			if _, err := b.SyncDatabase(ctx); err != nil {
+				return err
+			}
+		}
+		result, err := b.Query(ctx, args[0])
+		if errors.Is(err, biome.ErrDatabaseNotSynced) {
+			return fmt.Errorf("%w, run 'gh biome db sync' or use --sync", err)
+		}
+		if err != nil {
+			return err
+		}
+
+		if queryJSON {
+			rows := make([]queryRow, 0, len(result.Rows))
+			for _, values := range result.Rows {
+				rows = append(rows, queryRow{
+					columns: result.Columns,
+					values:  values,
+				})
+			}
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			return enc.Encode(rows)
+		}
+		if len(result.Columns) == 0 {
+			return nil
+		}
+		w := newReportWriter(cmd, queryFormat, result.Columns...)
+		for _, values := range result.Rows {
+			row := make([]string, 0, len(values))
+			for _, v := range values {
+				row = append(row, queryValue(v))
+			}
+			w.Row(row...)
+		}
+		return w.Flush()
+	},
+}
+
+// queryRow is a row selected by a query, encoded as a JSON object with its
+// keys in the order of the selected columns.
+type queryRow struct {
+	columns []string
+	values  []any
+}
+
+func (r queryRow) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, column := range r.columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(column)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(r.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// queryValue renders a value selected by a query for a report. NULL is
+// rendered as an empty string.
+func queryValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func init() {
+	queryCmd.SetContext(context.Background())
+	pushInContext(queryCmd)
+}
+
+func TestQueryCmd_Args(t *testing.T) {
+	rootCmd.SetArgs([]string{"query"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("expected error executing query without SQL")
+	}
+}
+
+func TestQueryRow_MarshalJSON(t *testing.T) {
+	row := queryRow{
+		columns: []string{"remote", "archived", "upstream"},
+		values:  []any{"github.com/cli/cli", json.Number("0"), nil},
+	}
+	out, err := json.Marshal(row)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `{"remote":"github.com/cli/cli","archived":0,"upstream":null}`; string(out) != expected {
+		t.Errorf("unexpected JSON:\nwanted %s\nwas    %s", expected, out)
+	}
+}
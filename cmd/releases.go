@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	releasesFormat  outputFormat
+	releasesPattern string
+)
+
+func init() {
+	addFormatFlag(releasesCmd.Flags(), &releasesFormat)
+	releasesCmd.Flags().StringVar(&releasesPattern, "pattern", "", "Only consider tags whose names match this glob pattern, ex. 'v*'.")
+	rootCmd.AddCommand(releasesCmd)
+}
+
+var releasesCmd = &cobra.Command{
+	Use:   "releases [<github-owner> ...]",
+	Short: "List the latest tag of each remote of the git biome, with its date and signer",
+	Long: `
+List the latest tag of each remote of the git biome, for an audit of how
+recently, and how verifiably, every repository of an organization was
+released. If owners are specified as arguments, only list their remotes.
+
+The latest tag of a remote is the tag whose commit was committed most
+recently. Use --pattern to only consider tags whose names match a glob
+pattern, ex. 'v*', rather than every tag. Remotes without any such tag are
+not listed.
+
+DATE is the committer date of the tagged commit. When the tag itself is
+signed, VERIFIED is tag, and SIGNATURE and SIGNER describe the tag's
+signature. Otherwise, VERIFIED is commit, and they describe the signature of
+the tagged commit. SIGNATURE is good, bad, unverified, ex. when the signing
+key is not known, or unsigned. Signatures are verified with the user's gpg
+and gpg.ssh configuration. Configure gpg.ssh.allowedSignersFile to name the
+principal that made each SSH signature, rather than its key's fingerprint.
+`,
+	Example: `biome releases
+
+biome releases --pattern 'v*' github.com/cli
+
+biome releases --format csv
+`,
+	Args: validOwnerRefs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		owners, err := parseOwners(args)
+		if err != nil {
+			return err
+		}
+		if err := validateOwnersPresent(ctx, b, owners); err != nil {
+			return err
+		}
+
+		releases, err := b.Releases(ctx, releasesPattern, owners...)
+		if err != nil {
+			return err
+		}
+		w := newReportWriter(cmd, releasesFormat, "remote", "tag", "date", "verified", "signature", "signer")
+		for _, r := range releases {
+			verified := "commit"
+			if r.SignedTag {
+				verified = "tag"
+			}
+			w.Row(r.Remote, r.Tag, r.Date.Local().Format(time.DateOnly), verified, string(r.Signature), r.Signer)
+		}
+		return w.Flush()
+	},
+}
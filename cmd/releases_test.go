@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func init() {
+	releasesCmd.SetContext(context.Background())
+	pushInContext(releasesCmd)
+}
+
+func TestReleasesCmd_Execute(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_orirawlings.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	tree := strings.TrimSpace(testutil.Execute(t, "git", "write-tree"))
+	commit := func(message, date string) string {
+		return strings.TrimSpace(testutil.Execute(t, "env", "GIT_COMMITTER_DATE="+date, "git", "-c", "user.name=A", "-c", "user.email=a@example.com", "commit-tree", "-m", message, tree))
+	}
+	testutil.Execute(t, "git", "update-ref", "refs/remotes/github.com/orirawlings/bar/tags/v1.0", commit("v1.0", "2024-06-01T12:00:00Z"))
+	testutil.Execute(t, "git", "update-ref", "refs/remotes/github.com/orirawlings/bar/tags/v1.1", commit("v1.1", "2025-06-01T12:00:00Z"))
+	testutil.Execute(t, "git", "update-ref", "refs/remotes/github.com/orirawlings/bar/tags/nightly", commit("nightly", "2025-07-01T12:00:00Z"))
+
+	buf := new(bytes.Buffer)
+	releasesCmd.SetOut(buf)
+	t.Cleanup(func() {
+		releasesCmd.SetOut(nil)
+		releasesFormat = tableFormat
+		releasesPattern = ""
+	})
+	rootCmd.SetArgs([]string{"releases", "--format", "csv", "--pattern", "v*", github_com_orirawlings.String()})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	expected := strings.Join([]string{
+		"remote,tag,date,verified,signature,signer",
+		"github.com/orirawlings/bar,v1.1,2025-06-01,commit,unsigned,",
+	}, "\n") + "\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
@@ -1,6 +1,15 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
 	cmdutil "github.com/orirawlings/gh-biome/internal/util/command"
 	"github.com/spf13/cobra"
 )
@@ -15,9 +24,39 @@ var remotesCmd = &cobra.Command{
 	Not all discovered remotes are eligible for fetching and/or pushing git data, so not all are
 	configured as actual git remotes. But this command can list them, regardless.
 	
-	Use flag options to filter which categories of remotes to list.`,
+	Use flag options to filter which categories of remotes to list. Use --internal to further
+	limit the listing to repositories with internal visibility, which are visible to all members
+	of the owning enterprise but are neither public nor private.
+	
+	Use --with-upstream to print each remote that is a fork alongside its upstream remote, the
+	repository it was forked from. Upstreams are discovered from GitHub, or recorded with the
+	biome.remote.<remote>.upstream setting.
+	
+	Use --groups to print each remote alongside the git remote groups it is a member of, ex. to
+	fetch them with 'git fetch <group>'. See 'gh biome groups' for the owner of each group.
+	
+	Use --long to print each remote alongside its note, freeform knowledge about the remote, ex.
+	the team that owns it, attached with 'gh biome annotate'.
+	
+	When printing to a terminal, remote names are colored by category: archived remotes in
+	yellow, disabled and locked remotes in red, evicted remotes in blue, and unsupported remotes
+	in gray. Upstreams, groups, and notes are muted. Use --no-color or set NO_COLOR to disable colors.
+	
+	Use --failed to list only the remotes whose most recent fetch failed, as recorded in the
+	biome's journal.
+	
+	Use --format csv to print each remote's name, status in GitHub, and upstream as comma
+	separated values, ex. for spreadsheets, including each remote's note with --long. Use
+	--json to print each remote as a JSON object, including its note, and the error and class of error, ex. auth, notFound, timeout, or pack, of its most
+	recent fetch if it failed, to triage failed fetches.`,
 	Args: cobra.NoArgs, // TODO (orirawlings): add support for filtering remotes by owners listed as positional arguments
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if remotesGroups && withUpstream {
+			return errors.New("--groups cannot be combined with --with-upstream")
+		}
+		if remotesJSON && remotesFormat == csvFormat {
+			return errors.New("--json cannot be combined with --format csv")
+		}
 		ctx := cmd.Context()
 		b, err := load(ctx)
 		if err != nil {
@@ -29,18 +68,172 @@ var remotesCmd = &cobra.Command{
 			return err
 		}
 
+		// the remote groups of each remote
+		memberOf := make(map[string][]string)
+		if remotesGroups {
+			groups, err := b.RemoteGroups(ctx)
+			if err != nil {
+				return err
+			}
+			for _, group := range groups {
+				for _, member := range group.Members {
+					memberOf[member] = append(memberOf[member], group.Name)
+				}
+			}
+		}
+
+		// the note of each remote
+		var notes map[string]string
+		if remotesLong || remotesJSON {
+			if notes, err = b.RemoteNotes(ctx); err != nil {
+				return err
+			}
+		}
+
+		// the most recent fetch of each remote, if it failed
+		failures := make(map[string]biome.JournalEntry)
+		if remotesFailed || remotesJSON {
+			entries, err := b.Journal(ctx)
+			if err != nil {
+				return err
+			}
+			for _, e := range biome.FetchFailures(entries) {
+				failures[e.Remote] = e
+			}
+		}
+		if remotesFailed {
+			remotes = slices.DeleteFunc(remotes, func(r biome.Remote) bool {
+				_, ok := failures[r.Name]
+				return !ok
+			})
+		}
+
+		if remotesJSON {
+			result := []remoteJSON{}
+			for _, remote := range remotes {
+				if internalOnly && !remote.Internal {
+					continue
+				}
+				r := remoteJSON{
+					Name:     remote.Name,
+					Archived: remote.Archived,
+					Disabled: remote.Disabled,
+					Locked:   remote.Locked,
+					Internal: remote.Internal,
+					Evicted:  remote.Evicted,
+					Upstream: remote.Upstream,
+					Groups:   memberOf[remote.Name],
+					Note:     notes[remote.Name],
+				}
+				if failure, ok := failures[remote.Name]; ok {
+					r.FetchFailure = &remoteFetchFailure{
+						Time:       failure.Time,
+						Error:      failure.Error,
+						ErrorClass: failure.ErrorClass,
+					}
+				}
+				result = append(result, r)
+			}
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			return enc.Encode(result)
+		}
+
+		if remotesFormat == csvFormat {
+			columns := []string{"remote", "archived", "disabled", "locked", "internal", "evicted", "upstream"}
+			if remotesGroups {
+				columns = append(columns, "groups")
+			}
+			if remotesLong {
+				columns = append(columns, "note")
+			}
+			w := newReportWriter(cmd, remotesFormat, columns...)
+			for _, remote := range remotes {
+				if internalOnly && !remote.Internal {
+					continue
+				}
+				row := []string{
+					remote.Name,
+					strconv.FormatBool(remote.Archived),
+					strconv.FormatBool(remote.Disabled),
+					strconv.FormatBool(remote.Locked),
+					strconv.FormatBool(remote.Internal),
+					strconv.FormatBool(remote.Evicted),
+					remote.Upstream,
+				}
+				if remotesGroups {
+					row = append(row, strings.Join(memberOf[remote.Name], " "))
+				}
+				if remotesLong {
+					row = append(row, notes[remote.Name])
+				}
+				w.Row(row...)
+			}
+			return w.Flush()
+		}
+
+		cs := newColorScheme(cmd)
 		for _, remote := range remotes {
-			cmdutil.Println(cmd, remote)
+			if internalOnly && !remote.Internal {
+				continue
+			}
+			line := cs.Remote(remote, remote.Name)
+			if remotesGroups {
+				if groups := memberOf[remote.Name]; len(groups) > 0 {
+					line += " " + cs.Muted(strings.Join(groups, " "))
+				}
+			} else if withUpstream && remote.Upstream != "" {
+				line = fmt.Sprintf("%s %s", line, cs.Muted(remote.Upstream))
+			}
+			if note := notes[remote.Name]; remotesLong && note != "" {
+				line += " " + cs.Muted(note)
+			}
+			cmdutil.Println(cmd, line)
 		}
 		return nil
 	},
 }
 
+// remoteJSON is a remote as printed by 'remotes --json'.
+type remoteJSON struct {
+	Name         string              `json:"name"`
+	Archived     bool                `json:"archived"`
+	Disabled     bool                `json:"disabled"`
+	Locked       bool                `json:"locked"`
+	Internal     bool                `json:"internal"`
+	Evicted      bool                `json:"evicted"`
+	Upstream     string              `json:"upstream,omitempty"`
+	Groups       []string            `json:"groups,omitempty"`
+	Note         string              `json:"note,omitempty"`
+	FetchFailure *remoteFetchFailure `json:"fetchFailure,omitempty"`
+}
+
+// remoteFetchFailure describes the failure of a remote's most recent fetch.
+type remoteFetchFailure struct {
+	Time       time.Time             `json:"time"`
+	Error      string                `json:"error,omitempty"`
+	ErrorClass biome.FetchErrorClass `json:"errorClass,omitempty"`
+}
+
 var (
 	remotesOptions = newRemoteCategoryOptions(false)
+	internalOnly   bool
+	withUpstream   bool
+	remotesGroups  bool
+	remotesFailed  bool
+	remotesLong    bool
+	remotesJSON    bool
+	remotesFormat  outputFormat
 )
 
 func init() {
 	rootCmd.AddCommand(remotesCmd)
 	remotesOptions.AddFlags(remotesCmd.Flags())
+	remotesCmd.Flags().BoolVar(&internalOnly, "internal", false, "Only include remotes with internal visibility in GitHub, visible to all members of the owning enterprise. https://docs.github.com/en/repositories/creating-and-managing-repositories/about-repositories#about-internal-repositories")
+	remotesCmd.Flags().BoolVar(&withUpstream, "with-upstream", false, "Print the upstream remote that each fork was forked from after the fork's name.")
+	remotesCmd.Flags().BoolVar(&remotesGroups, "groups", false, "Print the git remote groups that each remote is a member of after the remote's name.")
+	remotesCmd.Flags().BoolVar(&remotesLong, "long", false, "Print the note attached to each remote with 'biome annotate' after the remote's name.")
+	remotesCmd.Flags().BoolVar(&remotesFailed, "failed", false, "Only include remotes whose most recent fetch failed.")
+	remotesCmd.Flags().BoolVar(&remotesJSON, "json", false, "Print the remotes as a JSON array, including the details of failed fetches.")
+	addFormatFlag(remotesCmd.Flags(), &remotesFormat)
 }
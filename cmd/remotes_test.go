@@ -5,6 +5,9 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
 )
 
 func init() {
@@ -197,6 +200,61 @@ func TestRemotesCmd_Execute(t *testing.T) {
 				"my.github.biz/foobar/bazbiz",
 			},
 		},
+		{
+			flags: []string{
+				"--internal",
+			},
+			expected: []string{
+				"my.github.biz/foobar/bazbiz",
+			},
+		},
+		{
+			flags: []string{
+				"--with-upstream",
+			},
+			expected: []string{
+				"github.com/cli/cli",
+				"github.com/orirawlings/bar github.com/cli/cli",
+				"github.com/orirawlings/headless",
+				"my.github.biz/foobar/bazbiz",
+			},
+		},
+		{
+			flags: []string{
+				"--groups",
+			},
+			expected: []string{
+				"github.com/cli/cli " + github_com_cli.RemoteGroup(),
+				"github.com/orirawlings/bar " + github_com_orirawlings.RemoteGroup(),
+				"github.com/orirawlings/headless " + github_com_orirawlings.RemoteGroup(),
+				"my.github.biz/foobar/bazbiz " + my_github_biz_foobar.RemoteGroup(),
+			},
+		},
+		{
+			flags: []string{
+				"--all",
+				"--internal",
+			},
+			expected: []string{
+				"my.github.biz/foobar/bazbiz",
+			},
+		},
+		{
+			flags: []string{
+				"--archived",
+				"--active",
+				"--format",
+				"csv",
+			},
+			expected: []string{
+				"remote,archived,disabled,locked,internal,evicted,upstream",
+				"github.com/cli/cli,false,false,false,false,false,",
+				"github.com/orirawlings/archived,true,false,false,false,false,",
+				"github.com/orirawlings/bar,false,false,false,false,false,github.com/cli/cli",
+				"github.com/orirawlings/headless,false,false,false,false,false,",
+				"my.github.biz/foobar/bazbiz,false,false,false,true,false,",
+			},
+		},
 	} {
 		t.Run(strings.Join(run.flags, " "), func(t *testing.T) {
 			buf := new(bytes.Buffer)
@@ -204,6 +262,94 @@ func TestRemotesCmd_Execute(t *testing.T) {
 			t.Cleanup(func() {
 				remotesCmd.SetOut(nil)
 				remotesOptions.Reset()
+				internalOnly = false
+				withUpstream = false
+				remotesGroups = false
+				remotesFormat = tableFormat
+			})
+			rootCmd.SetArgs(append([]string{"remotes"}, run.flags...))
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("unexpected error executing command: %v", err)
+			}
+			expected := strings.Join(run.expected, "\n") + "\n"
+			if buf.String() != expected {
+				t.Errorf("expected %q, got %q", expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestRemotesCmd_failed(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_orirawlings.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+
+	ctx := context.Background()
+	b, err := load(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error loading biome: %v", err)
+	}
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	if err := b.Record(ctx,
+		biome.JournalEntry{Time: start, Op: biome.FetchOp, Remote: "github.com/orirawlings/bar", Failed: true},
+		biome.JournalEntry{Time: start.Add(time.Hour), Op: biome.FetchOp, Remote: "github.com/orirawlings/bar"},
+		biome.JournalEntry{
+			Time:       start.Add(time.Hour),
+			Op:         biome.FetchOp,
+			Remote:     "github.com/orirawlings/headless",
+			Failed:     true,
+			Error:      "fatal: Authentication failed",
+			ErrorClass: biome.AuthError,
+		},
+	); err != nil {
+		t.Fatalf("unexpected error recording journal: %v", err)
+	}
+
+	for _, run := range []struct {
+		flags    []string
+		expected []string
+	}{
+		{
+			flags: []string{"--failed"},
+			expected: []string{
+				"github.com/orirawlings/headless",
+			},
+		},
+		{
+			flags: []string{"--failed", "--json"},
+			expected: []string{
+				`[`,
+				`  {`,
+				`    "name": "github.com/orirawlings/headless",`,
+				`    "archived": false,`,
+				`    "disabled": false,`,
+				`    "locked": false,`,
+				`    "internal": false,`,
+				`    "evicted": false,`,
+				`    "fetchFailure": {`,
+				`      "time": "2025-06-01T01:00:00Z",`,
+				`      "error": "fatal: Authentication failed",`,
+				`      "errorClass": "auth"`,
+				`    }`,
+				`  }`,
+				`]`,
+			},
+		},
+	} {
+		t.Run(strings.Join(run.flags, " "), func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			remotesCmd.SetOut(buf)
+			t.Cleanup(func() {
+				remotesCmd.SetOut(nil)
+				remotesFailed = false
+				remotesJSON = false
 			})
 			rootCmd.SetArgs(append([]string{"remotes"}, run.flags...))
 			if err := rootCmd.Execute(); err != nil {
@@ -1,10 +1,25 @@
 package cmd
 
 import (
+	"fmt"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	cmdutil "github.com/orirawlings/gh-biome/internal/util/command"
+
 	"github.com/spf13/cobra"
 )
 
+var (
+	removeMine    string
+	removeDryRun  bool
+	removeVerbose bool
+)
+
 func init() {
+	removeCmd.Flags().StringVar(&removeMine, "mine", "", "Remove the repositories that were added for the authenticated user on the given GitHub host.")
+	removeCmd.Flags().Lookup("mine").NoOptDefVal = "github.com"
+	removeCmd.Flags().BoolVarP(&removeDryRun, "dry-run", "n", false, "Do not remove anything. List the remotes and references that would be removed, with an estimate of the disk space that could be reclaimed.")
+	removeCmd.Flags().BoolVarP(&removeVerbose, "verbose", "v", false, "List the remotes and references that will be removed before removing them.")
 	rootCmd.AddCommand(removeCmd)
 }
 
@@ -23,7 +38,14 @@ the server. If <host> is omitted, "github.com" is assumed.
 
 	[https://][<host>/]<owner-name>
 
-Each of the owners' repositories will be removed from the git remotes.
+Each of the owners' repositories will be removed from the git remotes. Use
+--dry-run to preview which remotes and how many references would be removed,
+along with an estimate of how much disk space could be reclaimed, without
+changing anything.
+
+With --mine, the repositories that were added with 'add --mine' for the given
+GitHub host (default "github.com") are removed instead, unless they are also
+owned by a remaining owner.
 `,
 	Example: `biome remove orirawlings
 
@@ -32,10 +54,19 @@ biome remove github.com/orirawlings
 biome remove https://github.com/orirawlings
 
 biome remove github.com/orirawlings github.com/git github.com/cli
+
+biome remove --dry-run github.com/orirawlings
+
+biome remove --mine
 `,
 	Aliases: []string{"rm"},
 	Args: cobra.MatchAll(
-		cobra.MinimumNArgs(1),
+		func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().Changed("mine") {
+				return nil
+			}
+			return cobra.MinimumNArgs(1)(cmd, args)
+		},
 		validOwnerRefs,
 	),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -49,20 +80,60 @@ biome remove github.com/orirawlings github.com/git github.com/cli
 		if err != nil {
 			return err
 		}
+		var viewers []biome.Viewer
+		if cmd.Flags().Changed("mine") {
+			viewers = append(viewers, biome.NewViewer(removeMine))
+		}
+		if removeDryRun || removeVerbose {
+			plan, err := b.PlanRemoveOwners(ctx, owners, viewers)
+			if err != nil {
+				return err
+			}
+			printRemovalPlan(cmd, plan, removeDryRun)
+			if removeDryRun {
+				return nil
+			}
+		}
+
 		for _, owner := range owners {
 			cmd.PrintErrf("Removing %s...\n", owner)
 		}
 
 		// remove owners/users in git config if already present
-		if err := b.RemoveOwners(ctx, owners); err != nil {
-			return err
+		if len(owners) > 0 {
+			if err := b.RemoveOwners(ctx, owners); err != nil {
+				return err
+			}
+		}
+
+		// remove the authenticated user's repositories in git config
+		if len(viewers) > 0 {
+			for _, viewer := range viewers {
+				cmd.PrintErrf("Removing repositories accessible to the authenticated user on %s...\n", viewer.Host())
+			}
+			if err := b.RemoveViewers(ctx, viewers); err != nil {
+				return err
+			}
 		}
 
 		// update git remote configurations for all owners
-		if err := b.UpdateRemotes(ctx); err != nil {
+		if err := updateRemotes(cmd, b); err != nil {
 			return err
 		}
 
 		return nil
 	},
 }
+
+// printRemovalPlan describes the remotes and references that are affected by
+// removing owners from the biome.
+func printRemovalPlan(cmd *cobra.Command, plan biome.RemovalPlan, dryRun bool) {
+	verb := "Removing"
+	if dryRun {
+		verb = "Would remove"
+	}
+	for _, r := range plan.Remotes {
+		cmdutil.Println(cmd, fmt.Sprintf("%s %s (%d refs under %s)", verb, r.Remote, r.Refs, r.Namespace))
+	}
+	cmdutil.Println(cmd, fmt.Sprintf("%s %d remotes and %d refs, approximately %s reclaimable after garbage collection", verb, len(plan.Remotes), plan.Refs(), formatBytes(plan.ReclaimableBytes)))
+}
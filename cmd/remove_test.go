@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"strings"
 	"testing"
 )
 
@@ -43,4 +45,77 @@ func TestRemoveCmd_Execute(t *testing.T) {
 			t.Fatalf("unexpected error executing command: %v", err)
 		}
 	})
+
+	t.Run("--dry-run", func(t *testing.T) {
+		setup(t)
+		buf := new(bytes.Buffer)
+		removeCmd.SetOut(buf)
+		t.Cleanup(func() {
+			removeCmd.SetOut(nil)
+			removeCmd.Flags().Lookup("dry-run").Changed = false
+			removeDryRun = false
+			removeCmd.Flags().Lookup("verbose").Changed = false
+			removeVerbose = false
+		})
+		rootCmd.SetArgs([]string{"remove", "--dry-run", github_com_orirawlings.String()})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error executing command: %v", err)
+		}
+		expected := `Would remove github.com/orirawlings/archived (0 refs under refs/remotes/github.com/orirawlings/archived/)
+Would remove github.com/orirawlings/bar (0 refs under refs/remotes/github.com/orirawlings/bar/)
+Would remove github.com/orirawlings/headless (0 refs under refs/remotes/github.com/orirawlings/headless/)
+Would remove 3 remotes and 0 refs, approximately 0 B reclaimable after garbage collection
+`
+		if buf.String() != expected {
+			t.Errorf("expected %q, got %q", expected, buf.String())
+		}
+
+		// nothing should have been removed
+		buf.Reset()
+		listCmd.SetOut(buf)
+		t.Cleanup(func() {
+			listCmd.SetOut(nil)
+		})
+		rootCmd.SetArgs([]string{"list"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error executing command: %v", err)
+		}
+		if !strings.Contains(buf.String(), github_com_orirawlings.String()) {
+			t.Errorf("expected %s to remain in the biome, but was removed", github_com_orirawlings)
+		}
+	})
+
+	t.Run("--mine --dry-run", func(t *testing.T) {
+		initBiome(t)
+		stubGitHub(t)
+		rootCmd.SetArgs([]string{"add", "--skip-fetch", "--mine"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error executing command: %v", err)
+		}
+		addCmd.Flags().Lookup("mine").Changed = false
+		mine = ""
+
+		buf := new(bytes.Buffer)
+		removeCmd.SetOut(buf)
+		t.Cleanup(func() {
+			removeCmd.SetOut(nil)
+			removeCmd.Flags().Lookup("mine").Changed = false
+			removeMine = ""
+			removeCmd.Flags().Lookup("dry-run").Changed = false
+			removeDryRun = false
+			removeCmd.Flags().Lookup("verbose").Changed = false
+			removeVerbose = false
+		})
+		rootCmd.SetArgs([]string{"remove", "--mine", "--dry-run"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error executing command: %v", err)
+		}
+		expected := `Would remove github.com/cli/cli (0 refs under refs/remotes/github.com/cli/cli/)
+Would remove github.com/orirawlings/bar (0 refs under refs/remotes/github.com/orirawlings/bar/)
+Would remove 2 remotes and 0 refs, approximately 0 B reclaimable after garbage collection
+`
+		if buf.String() != expected {
+			t.Errorf("expected %q, got %q", expected, buf.String())
+		}
+	})
 }
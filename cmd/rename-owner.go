@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"github.com/orirawlings/gh-biome/internal/biome"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(renameOwnerCmd)
+}
+
+var renameOwnerCmd = &cobra.Command{
+	Use:   "rename-owner <old-github-owner> <new-github-owner>",
+	Short: "Rename a GitHub user or organization in the git biome",
+	Long: `
+Rename a GitHub repository owner that was previously added to the git biome,
+typically after the GitHub user or organization was renamed. An owner is a
+GitHub user or organization.
+
+The git remotes for each of the owner's repositories are renamed to use the new
+owner name. All git references under refs/remotes/<old-remote-name>/ are moved
+to refs/remotes/<new-remote-name>/ in a single transaction, so the remotes'
+git objects and references do not need to be fetched again.
+
+<github-owner> is specified with the following format, where <host> is the GitHub
+server name and <owner-name> is the name of the GitHub user or organziation within
+the server. If <host> is omitted, "github.com" is assumed.
+
+	[https://][<host>/]<owner-name>
+`,
+	Example: `biome rename-owner github.com/old-org github.com/new-org
+`,
+	Args: cobra.MatchAll(
+		cobra.ExactArgs(2),
+		validOwnerRefs,
+	),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		owners, err := parseOwners(args)
+		if err != nil {
+			return err
+		}
+		from, to := owners[0], owners[1]
+		if err := validateOwnersPresent(ctx, b, []biome.Owner{from}); err != nil {
+			return err
+		}
+
+		cmd.PrintErrf("Renaming %s to %s...\n", from, to)
+		return b.RenameOwner(ctx, from, to)
+	},
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func init() {
+	renameOwnerCmd.SetContext(context.Background())
+	pushInContext(renameOwnerCmd)
+}
+
+func TestRenameOwnerCmd_Execute(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_orirawlings.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+
+	rootCmd.SetArgs([]string{"rename-owner", github_com_orirawlings.String(), github_com_cli.String()})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+
+	buf := new(bytes.Buffer)
+	listCmd.SetOut(buf)
+	t.Cleanup(func() {
+		listCmd.SetOut(nil)
+	})
+	rootCmd.SetArgs([]string{"list"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	expected := github_com_cli.String() + "\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"path"
+	"slices"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	cmdutil "github.com/orirawlings/gh-biome/internal/util/command"
+	"github.com/spf13/cobra"
+)
+
+var (
+	retryUnsupportedDryRun bool
+)
+
+func init() {
+	retryUnsupportedCmd.Flags().BoolVar(&retryUnsupportedDryRun, "dry-run", false, "List the unsupported remotes whose fetch refspec is now valid, without updating any remotes.")
+	rootCmd.AddCommand(retryUnsupportedCmd)
+}
+
+var retryUnsupportedCmd = &cobra.Command{
+	Use:   "retry-unsupported",
+	Short: "Promote unsupported remotes whose fetch refspec is now valid",
+	Long: `
+Re-check the remotes that are categorized as unsupported, because git did not
+accept the fetch refspec of their reference namespace, ex. for repositories
+whose names begin with a dot. After a change of biome.refspecTemplate, or an
+upgrade of git, some of them may now be supported.
+
+The git remote configurations of the owners of such remotes are updated, as
+'biome sync-config <owner>' does, so that they are promoted to active or
+archived, according to their state in GitHub, without waiting for a full
+update of every owner. If a remote was only discovered through a viewer, the
+remotes of every owner and viewer are updated. Each promoted remote is
+printed. They are fetched by the next 'biome fetch'.
+
+Use --dry-run to only list the unsupported remotes whose fetch refspec is now
+valid.
+`,
+	Example: `biome config set biome.refspecTemplate 'refs/remotes/<host>/<owner>/repo-<repo>/*'
+biome retry-unsupported
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		unsupported, err := b.Remotes(ctx, biome.Unsupported)
+		if err != nil {
+			return err
+		}
+		var candidates []biome.Remote
+		for _, r := range unsupported {
+			if r.Supported() {
+				candidates = append(candidates, r)
+			}
+		}
+		if retryUnsupportedDryRun || len(candidates) == 0 {
+			for _, r := range candidates {
+				cmdutil.Println(cmd, r.Name)
+			}
+			cmd.PrintErrf("%d of %d unsupported remotes have a valid fetch refspec\n", len(candidates), len(unsupported))
+			return nil
+		}
+
+		// update only the owners of the candidates, unless one was not
+		// discovered through an owner of the biome
+		biomeOwners, err := b.Owners(ctx)
+		if err != nil {
+			return err
+		}
+		var owners []biome.Owner
+		for _, r := range candidates {
+			owner, err := biome.ParseOwner(path.Dir(r.Name))
+			if err != nil || !slices.Contains(biomeOwners, owner) {
+				owners = nil
+				break
+			}
+			if !slices.Contains(owners, owner) {
+				owners = append(owners, owner)
+			}
+		}
+
+		if err := warnAnonymous(ctx, cmd, b); err != nil {
+			return err
+		}
+		cmd.PrintErrln("Updating git remote configurations...")
+		if err := updateRemotes(cmd, b, owners...); err != nil {
+			return err
+		}
+
+		promoted, err := b.Remotes(ctx, biome.FetchableRemoteCategories...)
+		if err != nil {
+			return err
+		}
+		var n int
+		for _, r := range candidates {
+			if slices.ContainsFunc(promoted, func(p biome.Remote) bool { return p.Name == r.Name }) {
+				cmdutil.Println(cmd, r.Name)
+				n++
+			}
+		}
+		cmd.PrintErrf("Promoted %d of %d unsupported remotes\n", n, len(unsupported))
+		return nil
+	},
+}
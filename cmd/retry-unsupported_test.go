@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func init() {
+	retryUnsupportedCmd.SetContext(context.Background())
+	pushInContext(retryUnsupportedCmd)
+}
+
+func TestRetryUnsupportedCmd_Execute(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	for _, args := range [][]string{
+		{"add", "--skip-fetch", github_com_orirawlings.String()},
+		// repository names may begin with a dot once they are prefixed
+		{"config", "set", "biome.refspecTemplate", "refs/remotes/<host>/<owner>/repo-<repo>/*"},
+	} {
+		rootCmd.SetArgs(args)
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error executing %v: %v", args, err)
+		}
+	}
+
+	for _, run := range []struct {
+		args     []string
+		expected []string
+	}{
+		{
+			args:     []string{"--dry-run"},
+			expected: []string{"github.com/orirawlings/.github"},
+		},
+		{
+			expected: []string{"github.com/orirawlings/.github"},
+		},
+		{
+			// nothing is left to promote
+		},
+	} {
+		t.Run(strings.Join(run.args, " "), func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			retryUnsupportedCmd.SetOut(buf)
+			t.Cleanup(func() {
+				retryUnsupportedCmd.SetOut(nil)
+				retryUnsupportedDryRun = false
+			})
+			rootCmd.SetArgs(append([]string{"retry-unsupported"}, run.args...))
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("unexpected error executing command: %v", err)
+			}
+			var expected string
+			if len(run.expected) > 0 {
+				expected = strings.Join(run.expected, "\n") + "\n"
+			}
+			if buf.String() != expected {
+				t.Errorf("expected %q, got %q", expected, buf.String())
+			}
+		})
+	}
+}
@@ -2,12 +2,28 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	// directory is the directory that biome commands run in, ex. the biome's
+	// path, given by the -C flag.
+	directory string
+
+	// migrate upgrades a repository configured by the deprecated add-remotes
+	// command before running the command, given by the --migrate flag.
+	migrate bool
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVarP(&directory, "directory", "C", ".", "Run as if biome was started in this directory instead of the current working directory.")
+	rootCmd.PersistentFlags().BoolVar(&migrate, "migrate", false, "Upgrade a repository configured by the legacy add-remotes command to a git biome before running the command.")
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "biome",
 	Short: "Store many git repos fetched from independent remotes in a single local git repo.",
@@ -18,7 +34,11 @@ many repos in a common database, we enable fast bulk analysis and querying
 across all repos.
 
 This tool helps manage the initialization, configuration, and maintenance of
-the local git biome repo.`,
+the local git biome repo.
+
+Commands run against the biome in the current directory, or the nearest one
+enclosing it. Set GH_BIOME_DIR to run commands against a default biome
+elsewhere, or pass -C to choose a biome for a single command.`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		pushInContext(cmd)
 	},
@@ -27,10 +47,29 @@ the local git biome repo.`,
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
+		var exitErr *exitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		os.Exit(1)
 	}
 }
 
+// exitCodeError is an error that biome should exit with the given status
+// for, ex. the exit status of a command that biome ran.
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitCodeError) Unwrap() error {
+	return e.err
+}
+
 type cmdValueKey struct{}
 
 func pushInContext(cmd *cobra.Command) {
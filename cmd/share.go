@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/orirawlings/gh-biome/internal/biome"
+	cmdutil "github.com/orirawlings/gh-biome/internal/util/command"
+	"github.com/spf13/cobra"
+)
+
+var (
+	shareOutput string
+	shareListen string
+)
+
+func init() {
+	shareCmd.Flags().StringVarP(&shareOutput, "output", "o", "-", "Write the bundle to this file, or to standard output if -.")
+	shareCmd.Flags().StringVar(&shareListen, "listen", "", "Serve the remote over HTTP at this address, ex. localhost:8080, instead of writing a bundle.")
+	shareCmd.MarkFlagsMutuallyExclusive("output", "listen")
+	rootCmd.AddCommand(shareCmd)
+}
+
+var shareCmd = &cobra.Command{
+	Use:   "share <remote>",
+	Short: "Share a single remote's repository for others to clone",
+	Long: `
+Share the branches and tags of a single remote with a teammate, without
+exposing the rest of the biome, so that they can clone the repository from
+the biome rather than from GitHub.
+
+By default, a git bundle of the remote's references, and only the objects
+reachable from them, is written to standard output, or to the file given by
+--output. The bundle can be cloned like a repository, ex.
+'git clone cli.bundle'.
+
+Use --listen to serve the remote over HTTP instead, until interrupted, ex.
+with Ctrl-C. The remote can then be cloned and fetched from
+http://<address>/<remote>.git. Only git's smart HTTP protocol is served, for
+fetches only, so that no other files of the biome are exposed. Nothing is
+encrypted or authenticated, so only listen on trusted networks.
+
+In both cases, the remote's branches and tags are shared as branches and
+tags, and the remote's HEAD is shared as HEAD, as in the repository on
+GitHub. Remotes are named with the following format.
+
+	<host>/<owner-name>/<repo-name>
+`,
+	Example: `biome share -o cli.bundle github.com/cli/cli
+
+biome share github.com/cli/cli | ssh teammate 'cat > cli.bundle'
+
+biome share --listen :8080 github.com/cli/cli
+git clone http://biome.example.com:8080/github.com/cli/cli.git
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		var l net.Listener
+		if shareListen != "" {
+			if l, err = net.Listen("tcp", shareListen); err != nil {
+				return fmt.Errorf("could not listen: %w", err)
+			}
+			defer l.Close()
+		} else if f, ok := cmd.OutOrStdout().(*os.File); ok && shareOutput == "-" && term.IsTerminal(f) {
+			return errors.New("refusing to write a bundle to a terminal, use --output or redirect standard output")
+		}
+
+		s, err := b.Share(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		defer s.Close()
+
+		if l != nil {
+			ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			cmdutil.Println(cmd, fmt.Sprintf("Serving %s (%d refs) at http://%s/%s.git", s.Remote, s.Refs, l.Addr(), s.Remote))
+			return serveShare(ctx, cmd, s, l)
+		}
+		if shareOutput == "-" {
+			return s.WriteBundle(ctx, cmd.OutOrStdout())
+		}
+		f, err := os.Create(shareOutput)
+		if err != nil {
+			return fmt.Errorf("could not create bundle: %w", err)
+		}
+		if err := s.WriteBundle(ctx, f); err != nil {
+			return errors.Join(err, f.Close())
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("could not write bundle: %w", err)
+		}
+		cmd.PrintErrf("Shared %s (%d refs) to %s\n", s.Remote, s.Refs, shareOutput)
+		return nil
+	},
+}
+
+// serveShare serves the share over HTTP on the listener until the context is
+// done, ex. by an interrupt, which ends serving without an error.
+func serveShare(ctx context.Context, cmd *cobra.Command, s *biome.Share, l net.Listener) error {
+	server := &http.Server{Handler: s.Handler()}
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Serve(l)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		cmd.PrintErrln("Interrupted, stopped serving")
+		return server.Close()
+	}
+}
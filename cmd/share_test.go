@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	shareCmd.SetContext(context.Background())
+	pushInContext(shareCmd)
+}
+
+func TestShareCmd_Execute(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_orirawlings.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	t.Cleanup(func() {
+		shareOutput = "-"
+		shareListen = ""
+	})
+
+	tree := strings.TrimSpace(testutil.Execute(t, "git", "write-tree"))
+	commit := strings.TrimSpace(testutil.Execute(t, "git", "-c", "user.name=A", "-c", "user.email=a@example.com", "commit-tree", "-m", "main", tree))
+	testutil.Execute(t, "git", "update-ref", "refs/remotes/github.com/orirawlings/bar/heads/main", commit)
+	testutil.Execute(t, "git", "symbolic-ref", "refs/remotes/github.com/orirawlings/bar/HEAD", "refs/remotes/github.com/orirawlings/bar/heads/main")
+
+	bundle := filepath.Join(t.TempDir(), "bar.bundle")
+	buf := new(bytes.Buffer)
+	rootCmd.SetErr(buf)
+	t.Cleanup(func() {
+		rootCmd.SetErr(nil)
+	})
+	rootCmd.SetArgs([]string{"share", "-o", bundle, "github.com/orirawlings/bar"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	if expected := fmt.Sprintf("Shared github.com/orirawlings/bar (1 refs) to %s\n", bundle); buf.String() != expected {
+		t.Errorf("expected output %q, was %q", expected, buf.String())
+	}
+	heads := testutil.Execute(t, "git", "bundle", "list-heads", bundle)
+	if expected := fmt.Sprintf("%s refs/heads/main\n%s HEAD\n", commit, commit); heads != expected {
+		t.Errorf("unexpected bundle heads: wanted %q, was %q", expected, heads)
+	}
+
+	rootCmd.SetArgs([]string{"share", "-o", bundle, "github.com/orirawlings/headless"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("expected error sharing a remote that was not fetched")
+	}
+}
+
+func TestServeShare(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_orirawlings.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	tree := strings.TrimSpace(testutil.Execute(t, "git", "write-tree"))
+	commit := strings.TrimSpace(testutil.Execute(t, "git", "-c", "user.name=A", "-c", "user.email=a@example.com", "commit-tree", "-m", "main", tree))
+	testutil.Execute(t, "git", "update-ref", "refs/remotes/github.com/orirawlings/bar/heads/main", commit)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b, err := load(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error loading biome: %v", err)
+	}
+	s, err := b.Share(ctx, "github.com/orirawlings/bar")
+	testutil.Check(t, err)
+	t.Cleanup(func() {
+		s.Close()
+	})
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	testutil.Check(t, err)
+
+	cmd := &cobra.Command{}
+	buf := new(bytes.Buffer)
+	cmd.SetErr(buf)
+	done := make(chan error, 1)
+	go func() {
+		done <- serveShare(ctx, cmd, s, l)
+	}()
+	refs := testutil.Execute(t, "git", "ls-remote", fmt.Sprintf("http://%s/github.com/orirawlings/bar.git", l.Addr()))
+	if expected := fmt.Sprintf("%s\trefs/heads/main\n", commit); refs != expected {
+		t.Errorf("unexpected served refs: wanted %q, was %q", expected, refs)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error serving share: %v", err)
+	}
+	if expected := "Interrupted, stopped serving\n"; buf.String() != expected {
+		t.Errorf("expected output %q, was %q", expected, buf.String())
+	}
+}
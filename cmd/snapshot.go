@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	cmdutil "github.com/orirawlings/gh-biome/internal/util/command"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	snapshotDiffJSON   bool
+	snapshotDiffFormat outputFormat
+)
+
+func init() {
+	snapshotDiffCmd.Flags().BoolVar(&snapshotDiffJSON, "json", false, "Print the changes as a JSON array.")
+	addFormatFlag(snapshotDiffCmd.Flags(), &snapshotDiffFormat)
+	snapshotCmd.AddCommand(snapshotCreateCmd, snapshotListCmd, snapshotDiffCmd, snapshotRestoreCmd, snapshotDeleteCmd)
+	rootCmd.AddCommand(snapshotCmd)
+}
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Record and manage snapshots of the state of the git biome's references",
+	Long: `
+Snapshots record the object name of every reference of the git biome's
+remotes at a point in time, so that analyses can be pinned to an exact state
+of the biome and run again reproducibly later, even after remotes are
+fetched.
+
+A snapshot is a commit on refs/biome/snapshots/<name>. Its tree has a file,
+refs, that lists the snapshot's references in the format of
+'git ls-remote --symref', and a file, remotes, that lists the biome's remotes
+and their reference namespaces. The commits of those references are ancestors of
+the snapshot's commit, so they are kept, and are not pruned, for as long as
+the snapshot exists.
+`,
+	Example: `biome snapshot create 2025-q3-audit
+
+git show refs/biome/snapshots/2025-q3-audit:refs
+`,
+}
+
+var snapshotCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Record the current object name of every reference of the biome's remotes",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		s, err := b.CreateSnapshot(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		cmdutil.Println(cmd, "Recorded", s.References, "references in", s.Ref())
+		return nil
+	},
+}
+
+var snapshotListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List the biome's snapshots",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		snapshots, err := b.Snapshots(ctx)
+		if err != nil {
+			return err
+		}
+		w := newReportWriter(cmd, tableFormat, "name", "created", "references", "commit")
+		for _, s := range snapshots {
+			w.Row(s.Name, s.Time.UTC().Format(time.RFC3339), strconv.Itoa(s.References), s.Commit)
+		}
+		return w.Flush()
+	},
+}
+
+var snapshotDiffCmd = &cobra.Command{
+	Use:   "diff <from> <to>",
+	Short: "Report how the biome's remotes changed between two snapshots",
+	Long: `
+Report, for each remote whose references changed between an earlier snapshot,
+<from>, and a later snapshot, <to>, how many references it gained, lost, and
+updated, and how its HEAD moved. This provides an org-wide digest of changes
+between two points in time.
+
+AHEAD counts the commits of the later HEAD that were not in the earlier HEAD,
+and BEHIND counts the commits of the earlier HEAD that are no longer in the
+later HEAD. A remote whose HEAD is behind was force-pushed, or its default
+branch changed. Remotes that were added or removed between the snapshots are
+reported as such.
+`,
+	Example: `biome snapshot diff 2025-q2-audit 2025-q3-audit
+`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		diffs, err := b.DiffSnapshots(ctx, args[0], args[1])
+		if err != nil {
+			return err
+		}
+		if snapshotDiffJSON {
+			type diff struct {
+				Remote      string `json:"remote"`
+				Status      string `json:"status"`
+				RefsAdded   int    `json:"refsAdded"`
+				RefsRemoved int    `json:"refsRemoved"`
+				RefsUpdated int    `json:"refsUpdated"`
+				OldHead     string `json:"oldHead,omitempty"`
+				NewHead     string `json:"newHead,omitempty"`
+				Ahead       int    `json:"ahead"`
+				Behind      int    `json:"behind"`
+			}
+			result := []diff{}
+			for _, d := range diffs {
+				result = append(result, diff{
+					Remote:      d.Remote,
+					Status:      snapshotDiffStatus(d),
+					RefsAdded:   d.RefsAdded,
+					RefsRemoved: d.RefsRemoved,
+					RefsUpdated: d.RefsUpdated,
+					OldHead:     d.OldHead,
+					NewHead:     d.NewHead,
+					Ahead:       d.Ahead,
+					Behind:      d.Behind,
+				})
+			}
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			return enc.Encode(result)
+		}
+		w := newReportWriter(cmd, snapshotDiffFormat, "remote", "status", "added", "removed", "updated", "head", "ahead", "behind")
+		for _, d := range diffs {
+			head := ""
+			if d.OldHead != d.NewHead && snapshotDiffFormat == csvFormat {
+				head = fmt.Sprintf("%s..%s", d.OldHead, d.NewHead)
+			} else if d.OldHead != d.NewHead {
+				head = fmt.Sprintf("%s..%s", shortObjectName(d.OldHead), shortObjectName(d.NewHead))
+			}
+			w.Row(d.Remote, snapshotDiffStatus(d), strconv.Itoa(d.RefsAdded), strconv.Itoa(d.RefsRemoved), strconv.Itoa(d.RefsUpdated), head, strconv.Itoa(d.Ahead), strconv.Itoa(d.Behind))
+		}
+		return w.Flush()
+	},
+}
+
+// snapshotDiffStatus summarizes how a remote changed between two snapshots.
+func snapshotDiffStatus(d biome.SnapshotDiff) string {
+	switch {
+	case d.Added:
+		return "added"
+	case d.Removed:
+		return "removed"
+	case d.ForcePushed():
+		return "force-pushed"
+	default:
+		return "changed"
+	}
+}
+
+// shortObjectName abbreviates an object name for reports, or returns "-" if
+// there is no object.
+func shortObjectName(name string) string {
+	if name == "" {
+		return "-"
+	}
+	if len(name) > 7 {
+		return name[:7]
+	}
+	return name
+}
+
+var snapshotRestoreCmd = &cobra.Command{
+	Use:   "restore <name>",
+	Short: "Reset the references of the biome's remotes to a snapshot",
+	Long: `
+Reset the references of the git biome's remotes to the values recorded by a
+snapshot, in a single reference transaction, to reproduce a past analysis.
+References of remotes that the snapshot did not record, ex. branches created
+since, or the references of remotes added since, are deleted. References
+outside of the remotes' namespaces are not changed.
+
+Nothing is restored if any object recorded by the snapshot is no longer
+present in the biome. Commits are kept by the snapshot, but objects of tags
+that do not point to commits may have been pruned.
+
+The next fetch moves the remotes' references on again. Create a snapshot
+before restoring another to be able to return to the current state without
+fetching.
+`,
+	Example: `biome snapshot create now && biome snapshot restore 2025-q3-audit
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		changed, err := b.RestoreSnapshot(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		cmdutil.Println(cmd, "Restored", changed, "references from snapshot", args[0])
+		return nil
+	},
+}
+
+var snapshotDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a snapshot, so that commits only it kept may be pruned",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+		return b.DeleteSnapshot(ctx, args[0])
+	},
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func init() {
+	snapshotCmd.SetContext(context.Background())
+	pushInContext(snapshotCmd)
+}
+
+func TestSnapshotCmd_Execute(t *testing.T) {
+	initBiome(t)
+
+	rootCmd.SetArgs([]string{"snapshot", "create", "empty"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	rootCmd.SetArgs([]string{"snapshot", "create", "empty"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Fatal("expected error creating a snapshot that exists, but was nil")
+	}
+
+	buf := new(bytes.Buffer)
+	snapshotListCmd.SetOut(buf)
+	t.Cleanup(func() {
+		snapshotListCmd.SetOut(nil)
+	})
+	rootCmd.SetArgs([]string{"snapshot", "list"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != 2 || !strings.HasPrefix(lines[1], "empty ") {
+		t.Errorf("unexpected snapshot list:\n%s", buf.String())
+	}
+
+	rootCmd.SetArgs([]string{"snapshot", "delete", "empty"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	rootCmd.SetArgs([]string{"snapshot", "delete", "empty"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Fatal("expected error deleting a snapshot that does not exist, but was nil")
+	}
+}
+
+func TestSnapshotDiffCmd_Args(t *testing.T) {
+	rootCmd.SetArgs([]string{"snapshot", "diff", "a"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("expected error executing snapshot diff with one snapshot")
+	}
+}
+
+func TestShortObjectName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"":    "-",
+		"abc": "abc",
+		"0123456789abcdef0123456789abcdef01234567": "0123456",
+	} {
+		if short := shortObjectName(name); short != expected {
+			t.Errorf("unexpected short object name of %q: wanted %q, was %q", name, expected, short)
+		}
+	}
+}
+
+func TestSnapshotRestoreCmd_Args(t *testing.T) {
+	rootCmd.SetArgs([]string{"snapshot", "restore"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("expected error executing snapshot restore without a snapshot")
+	}
+}
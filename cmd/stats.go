@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// defaultGrowthPeriod is how far back stats --growth compares the
+	// references of remotes, unless --since is given.
+	defaultGrowthPeriod = "30d"
+)
+
+var (
+	statsFormat outputFormat
+	statsGrowth bool
+	statsSince  string
+)
+
+func init() {
+	addFormatFlag(statsCmd.Flags(), &statsFormat)
+	statsCmd.Flags().BoolVar(&statsGrowth, "growth", false, "Show how much the references of each remote grew since the --since date, fastest growing first.")
+	statsCmd.Flags().StringVar(&statsSince, "since", defaultGrowthPeriod, "Compare the references of remotes with --growth since this git date, ex. 1.month.ago, or this long ago, ex. 7d, 12w, or 1y.")
+	rootCmd.AddCommand(statsCmd)
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats [<github-owner> ...]",
+	Short: "Show the number of references of the git biome's remotes, and how fast it grows",
+	Long: `
+Show how many references each fetchable remote of the git biome has fetched,
+most first. If owners are specified as arguments, only show their remotes.
+Symbolic references, ex. the remote's HEAD, are not counted.
+
+The references of every remote are counted after each fetch, and recorded in
+the biome's journal. Use --growth to compare the current counts with the
+earliest counts recorded since the --since date, 30 days ago by default, to
+find the remotes whose references are exploding, usually because pull
+request references pile up. Such remotes are candidates for a narrower
+refspec, see the biome.refspecTemplate setting. SINCE is when the remote's
+references were first counted since the --since date, and is empty for
+remotes that were not counted before.
+`,
+	Example: `biome stats
+
+biome stats github.com/kubernetes
+
+biome stats --growth
+
+biome stats --growth --since 7d --format csv
+`,
+	Args: validOwnerRefs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cmd.Flags().Changed("since") && !statsGrowth {
+			return errors.New("--since requires --growth")
+		}
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+		var growthSince time.Time
+		if statsGrowth {
+			if growthSince, err = sinceTime(ctx, b.Path(), statsSince); err != nil {
+				return err
+			}
+		}
+
+		owners, err := parseOwners(args)
+		if err != nil {
+			return err
+		}
+		if err := validateOwnersPresent(ctx, b, owners); err != nil {
+			return err
+		}
+
+		counts, err := b.RefCounts(ctx)
+		if err != nil {
+			return err
+		}
+		if len(owners) > 0 {
+			maps.DeleteFunc(counts, func(remote string, _ int) bool {
+				return !slices.ContainsFunc(owners, func(owner biome.Owner) bool {
+					return owner.String() == path.Dir(remote)
+				})
+			})
+		}
+
+		if !statsGrowth {
+			w := newReportWriter(cmd, statsFormat, "remote", "refs")
+			for _, remote := range slices.SortedFunc(maps.Keys(counts), func(a, b string) int {
+				return cmp.Or(
+					cmp.Compare(counts[b], counts[a]),
+					cmp.Compare(a, b),
+				)
+			}) {
+				w.Row(remote, strconv.Itoa(counts[remote]))
+			}
+			return w.Flush()
+		}
+
+		entries, err := b.Journal(ctx)
+		if err != nil {
+			return err
+		}
+		w := newReportWriter(cmd, statsFormat, "remote", "since", "from", "to", "growth")
+		for _, g := range biome.RefCountGrowth(entries, counts, growthSince) {
+			var since string
+			if !g.Since.IsZero() {
+				since = g.Since.Local().Format(time.DateOnly)
+			}
+			w.Row(g.Remote, since, strconv.Itoa(g.From), strconv.Itoa(g.To), strconv.Itoa(g.Growth()))
+		}
+		return w.Flush()
+	},
+}
+
+// recordRefCounts counts the references of each of the biome's remotes, and
+// records the counts in the biome's journal, so that stats --growth can find
+// the remotes whose references grow fastest.
+func recordRefCounts(ctx context.Context, b biome.Biome) error {
+	counts, err := b.RefCounts(ctx)
+	if err != nil {
+		return fmt.Errorf("could not count references of remotes: %w", err)
+	}
+	return b.Record(ctx, biome.RefCountEntries(counts, time.Now())...)
+}
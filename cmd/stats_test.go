@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func init() {
+	statsCmd.SetContext(context.Background())
+	pushInContext(statsCmd)
+}
+
+func TestStatsCmd_Execute(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_orirawlings.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+
+	tree := strings.TrimSpace(testutil.Execute(t, "git", "write-tree"))
+	commit := strings.TrimSpace(testutil.Execute(t, "git", "-c", "user.name=A", "-c", "user.email=a@example.com", "commit-tree", "-m", "refs", tree))
+	for _, ref := range []string{"heads/main", "pull/1/head", "pull/2/head"} {
+		testutil.Execute(t, "git", "update-ref", "refs/remotes/github.com/orirawlings/bar/"+ref, commit)
+	}
+
+	ctx := context.Background()
+	b, err := load(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error loading biome: %v", err)
+	}
+	longAgo := time.Now().Add(-60 * 24 * time.Hour)
+	recently := time.Now().Add(-48 * time.Hour)
+	if err := b.Record(ctx,
+		biome.JournalEntry{Time: longAgo, Op: biome.RefsOp, Remote: "github.com/orirawlings/bar", Refs: 0},
+		biome.JournalEntry{Time: recently, Op: biome.RefsOp, Remote: "github.com/orirawlings/bar", Refs: 1},
+	); err != nil {
+		t.Fatalf("unexpected error recording journal: %v", err)
+	}
+
+	for _, run := range []struct {
+		args     []string
+		expected []string
+	}{
+		{
+			args: []string{"--format", "csv"},
+			expected: []string{
+				"remote,refs",
+				"github.com/orirawlings/bar,3",
+				"github.com/orirawlings/archived,0",
+				"github.com/orirawlings/headless,0",
+			},
+		},
+		{
+			args: []string{"--growth", "--format", "csv"},
+			expected: []string{
+				"remote,since,from,to,growth",
+				"github.com/orirawlings/bar," + recently.Format(time.DateOnly) + ",1,3,2",
+				"github.com/orirawlings/archived,,0,0,0",
+				"github.com/orirawlings/headless,,0,0,0",
+			},
+		},
+		{
+			args: []string{"--growth", "--since", "90d"},
+			expected: []string{
+				"REMOTE                           SINCE       FROM  TO  GROWTH",
+				"github.com/orirawlings/bar       " + longAgo.Format(time.DateOnly) + "  0     3   3",
+				"github.com/orirawlings/archived              0     0   0",
+				"github.com/orirawlings/headless              0     0   0",
+			},
+		},
+	} {
+		t.Run(strings.Join(run.args, " "), func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			statsCmd.SetOut(buf)
+			t.Cleanup(func() {
+				statsCmd.SetOut(nil)
+				statsFormat = tableFormat
+				statsGrowth = false
+				statsSince = defaultGrowthPeriod
+			})
+			rootCmd.SetArgs(append([]string{"stats"}, run.args...))
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("unexpected error executing command: %v", err)
+			}
+			expected := strings.Join(run.expected, "\n") + "\n"
+			if buf.String() != expected {
+				t.Errorf("expected %q, got %q", expected, buf.String())
+			}
+		})
+	}
+}
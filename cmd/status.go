@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	cmdutil "github.com/orirawlings/gh-biome/internal/util/command"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statusFormat outputFormat
+)
+
+func init() {
+	addFormatFlag(statusCmd.Flags(), &statusFormat)
+	rootCmd.AddCommand(statusCmd)
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Summarize the state of the git biome",
+	Long: `
+Summarize the state of the git biome, including the number of owners and viewers that have been
+added, the number of remotes discovered in each category, and the settings that control how
+remotes are fetched. The result of the last integrity check of the biome's objects, see
+'biome fsck', is shown too, with the schedule of integrity checks, if any.
+
+Use --format csv to print each statistic as a row of comma separated values, ex. for spreadsheets.
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		owners, err := b.Owners(ctx)
+		if err != nil {
+			return err
+		}
+		viewers, err := b.Viewers(ctx)
+		if err != nil {
+			return err
+		}
+		stats := [][2]string{
+			{"biome", b.Path()},
+			{"owners", strconv.Itoa(len(owners))},
+			{"viewers", strconv.Itoa(len(viewers))},
+		}
+		for _, category := range biome.AllRemoteCategories {
+			remotes, err := b.Remotes(ctx, category)
+			if err != nil {
+				return err
+			}
+			stats = append(stats, [2]string{fmt.Sprintf("remotes.%s", category), strconv.Itoa(len(remotes))})
+		}
+
+		entries, err := b.Journal(ctx)
+		if err != nil {
+			return err
+		}
+		stats = append(stats, [2]string{"fsck", fsckStatus(entries)})
+		if schedule, err := b.GetSetting(ctx, "biome.fsck.schedule"); err != nil {
+			return err
+		} else if schedule.Value != "" {
+			stats = append(stats, [2]string{"fsck.schedule", schedule.Value})
+		}
+
+		parallel, err := b.GetSetting(ctx, "fetch.parallel")
+		if err != nil {
+			return err
+		}
+
+		if statusFormat == csvFormat {
+			w := newReportWriter(cmd, statusFormat, "name", "value")
+			for _, stat := range stats {
+				w.Row(stat[0], stat[1])
+			}
+			w.Row(parallel.Key, parallel.Value)
+			return w.Flush()
+		}
+		cs := newColorScheme(cmd)
+		for _, stat := range stats {
+			cmdutil.Println(cmd, fmt.Sprintf("%s %s", cs.Muted(stat[0]+":"), stat[1]))
+		}
+		printStatusSetting(cmd, cs, parallel)
+		return nil
+	},
+}
+
+// printStatusSetting prints the effective value of a setting, noting when the
+// value is the setting's default.
+func printStatusSetting(cmd *cobra.Command, cs colorScheme, v biome.SettingValue) {
+	if v.IsDefault {
+		cmdutil.Println(cmd, fmt.Sprintf("%s %s %s", cs.Muted(v.Key+":"), v.Value, cs.Muted("(default)")))
+	} else {
+		cmdutil.Println(cmd, fmt.Sprintf("%s %s", cs.Muted(v.Key+":"), v.Value))
+	}
+}
+
+// fsckStatus describes the result of the last integrity check recorded in the
+// journal, ex. "ok at 2025-06-01 12:00:00".
+func fsckStatus(entries []biome.JournalEntry) string {
+	last, ok := biome.LastFsck(entries)
+	if !ok {
+		return "never checked"
+	}
+	result := "ok"
+	if last.Failed {
+		result = "corrupt"
+	}
+	return fmt.Sprintf("%s at %s", result, last.Time.Local().Format(time.DateTime))
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func init() {
+	statusCmd.SetContext(context.Background())
+	pushInContext(statusCmd)
+}
+
+func TestStatusCmd_Execute(t *testing.T) {
+	initBiome(t)
+
+	buf := new(bytes.Buffer)
+	statusCmd.SetOut(buf)
+	// init only records fetch.parallel when --fetch-parallel is given
+	rootCmd.SetArgs([]string{"config", "set", "fetch.parallel", "0"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	t.Cleanup(func() {
+		statusCmd.SetOut(nil)
+	})
+
+	status := func(t *testing.T) string {
+		t.Helper()
+		buf.Reset()
+		rootCmd.SetArgs([]string{"status"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error executing command: %v", err)
+		}
+		return buf.String()
+	}
+
+	for _, expected := range []string{
+		"owners: 0\n",
+		"viewers: 0\n",
+		"remotes.active: 0\n",
+		"fetch.parallel: 0\n",
+	} {
+		if out := status(t); !strings.Contains(out, expected) {
+			t.Errorf("expected status to contain %q, got %q", expected, out)
+		}
+	}
+
+	rootCmd.SetArgs([]string{"config", "unset", "fetch.parallel"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	if out, expected := status(t), "fetch.parallel: 1 (default)\n"; !strings.Contains(out, expected) {
+		t.Errorf("expected status to contain %q, got %q", expected, out)
+	}
+
+	buf.Reset()
+	t.Cleanup(func() {
+		statusFormat = tableFormat
+	})
+	rootCmd.SetArgs([]string{"status", "--format", "csv"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	for _, expected := range []string{
+		"name,value\n",
+		"\nowners,0\n",
+		"\nremotes.active,0\n",
+		"\nfetch.parallel,1\n",
+	} {
+		if out := buf.String(); !strings.Contains(out, expected) {
+			t.Errorf("expected CSV status to contain %q, got %q", expected, out)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(syncConfigCmd)
+}
+
+var syncConfigCmd = &cobra.Command{
+	Use:   "sync-config [<github-owner> ...]",
+	Short: "Update git remote configurations without fetching",
+	Long: `
+Discover the repositories of all owners and viewers previously added to the
+biome, and update the git remote configurations and the HEAD references of
+the remotes, without fetching any remote. If owners are specified as
+arguments, only discover the repositories of those owners. All other owners
+and viewers keep their previously configured remotes.
+
+This is useful when fetching is managed outside of biome, ex. by a scheduler
+that runs 'git fetch' for selected remote groups.
+
+<github-owner> is specified with the following format, where <host> is the GitHub
+server name and <owner-name> is the name of the GitHub user or organziation within
+the server. If <host> is omitted, "github.com" is assumed.
+
+	[https://][<host>/]<owner-name>
+`,
+	Example: `biome sync-config
+
+biome sync-config github.com/orirawlings github.com/cli
+`,
+	Args: validOwnerRefs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		owners, err := parseOwners(args)
+		if err != nil {
+			return err
+		}
+		if err := validateOwnersPresent(ctx, b, owners); err != nil {
+			return err
+		}
+
+		if err := warnAnonymous(ctx, cmd, b); err != nil {
+			return err
+		}
+		cmd.PrintErrln("Updating git remote configurations...")
+		return updateRemotes(cmd, b, owners...)
+	},
+}
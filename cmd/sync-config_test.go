@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+)
+
+func init() {
+	syncConfigCmd.SetContext(context.Background())
+	pushInContext(syncConfigCmd)
+}
+
+func TestSyncConfigCmd_Execute(t *testing.T) {
+
+	setup := func(t *testing.T) {
+		t.Helper()
+		initBiome(t)
+		stubGitHub(t)
+		rootCmd.SetArgs([]string{
+			"add",
+			"--skip-fetch",
+			github_com_cli.String(),
+			github_com_orirawlings.String(),
+		})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error executing command: %v", err)
+		}
+	}
+
+	t.Run("no arguments", func(t *testing.T) {
+		setup(t)
+		rootCmd.SetArgs([]string{"sync-config"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error executing command: %v", err)
+		}
+	})
+
+	t.Run("with arguments", func(t *testing.T) {
+		setup(t)
+		rootCmd.SetArgs([]string{"sync-config", github_com_orirawlings.String()})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error executing command: %v", err)
+		}
+	})
+
+	t.Run("owner not in biome", func(t *testing.T) {
+		setup(t)
+		rootCmd.SetArgs([]string{"sync-config", my_github_biz_foobar.String()})
+		if err := rootCmd.Execute(); err == nil {
+			t.Fatalf("expected error executing command for an owner that is not in the biome")
+		}
+	})
+}
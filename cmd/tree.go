@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+
+	cmdutil "github.com/orirawlings/gh-biome/internal/util/command"
+	"github.com/spf13/cobra"
+)
+
+var (
+	treeRef       string
+	treeRecursive bool
+	treeNameOnly  bool
+)
+
+func init() {
+	treeCmd.Flags().StringVar(&treeRef, "ref", "", "List the tree at this reference of the remote, ex. a branch or tag, instead of its HEAD.")
+	treeCmd.Flags().BoolVarP(&treeRecursive, "recursive", "r", false, "List the files of subdirectories in place of the subdirectories.")
+	treeCmd.Flags().BoolVar(&treeNameOnly, "name-only", false, "Print only the path of each entry.")
+	rootCmd.AddCommand(treeCmd)
+}
+
+var treeCmd = &cobra.Command{
+	Use:     "tree <remote> [<path>]",
+	Aliases: []string{"ls-tree"},
+	Short:   "List the files of a remote's repository without checking it out",
+	Long: `
+List the files and directories of a remote's repository at the tip of its
+default branch, read directly from the biome's object database, so that a
+repository's contents can be explored without checking it out.
+
+Use --ref to list the tree at another reference of the remote instead, ex. a
+branch or tag. References are named relative to the remote's namespace, ex.
+"main", "heads/main", or "tags/v1.0".
+
+As with 'git ls-tree', a path lists the matching entry, while a path ending
+with a slash lists the entries of the directory. Use -r to list the files of
+subdirectories recursively.
+
+Each entry is printed as its mode, object type, and object ID, followed by a
+tab and its path, as by 'git ls-tree'. Use --name-only to print only paths.
+
+Remotes are named with the following format.
+
+	<host>/<owner-name>/<repo-name>
+`,
+	Example: `biome tree github.com/cli/cli
+
+biome tree github.com/cli/cli docs/
+
+biome tree -r --name-only --ref tags/v2.0.0 github.com/cli/cli pkg/
+`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		var path string
+		if len(args) > 1 {
+			path = args[1]
+		}
+		entries, err := b.Tree(ctx, args[0], treeRef, path, treeRecursive)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if treeNameOnly {
+				cmdutil.Println(cmd, entry.Path)
+				continue
+			}
+			cmdutil.Println(cmd, fmt.Sprintf("%s %s %s\t%s", entry.Mode, entry.Type, entry.Object, entry.Path))
+		}
+		return nil
+	},
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func init() {
+	treeCmd.SetContext(context.Background())
+	pushInContext(treeCmd)
+}
+
+func TestTreeCmd_Execute(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_orirawlings.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+
+	buf := new(bytes.Buffer)
+	treeCmd.SetOut(buf)
+	t.Cleanup(func() {
+		treeCmd.SetOut(nil)
+		treeRef = ""
+		treeRecursive = false
+	})
+
+	// remotes have not been fetched
+	rootCmd.SetArgs([]string{"tree", "github.com/orirawlings/bar"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Errorf("expected error for remote that has not been fetched")
+	}
+
+	rootCmd.SetArgs([]string{"ls-tree", "-r", "--ref", "main", "github.com/orirawlings/bar", "docs/"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Errorf("expected error for missing reference")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output, got %q", buf.String())
+	}
+}
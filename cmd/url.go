@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	cmdutil "github.com/orirawlings/gh-biome/internal/util/command"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(urlCmd)
+}
+
+var urlCmd = &cobra.Command{
+	Use:   "url <ref-or-remote>[:<path>[:<line>]]...",
+	Short: "Print the GitHub URLs of remotes, references, commits, or files of the git biome",
+	Long: `
+Print the canonical URL on GitHub of a remote, a remote's reference, a
+commit, or a file or directory of one of them, so that results of git
+commands run in the biome, ex. git grep or git log, can be turned into
+shareable links. A URL is printed for each argument, in order.
+
+A remote is named by its name, and prints the repository's home page. A
+reference of a remote is named by its full name, as printed by git, ex.
+refs/remotes/github.com/cli/cli/heads/main, by its name relative to the
+remote's namespace after the remote's name, ex. github.com/cli/cli/heads/main,
+or after an '@', as with 'biome cat', ex. github.com/cli/cli@tags/v2.0.0. A
+commit is named by its object ID, and is attributed to a remote whose
+references reach it, preferring remotes that are not forks.
+
+References and commits print the page of their commit, unless a path is
+named after a ':', which prints the page of the file or directory at that
+path of the commit. A remote with a path prints the page of the path at the
+tip of the remote's default branch. A line number after the path of a file
+links to that line.
+
+Pages of commits and paths name the commit by its object ID, as fetched in
+the biome, so links keep showing the same content after the remote's
+branches move on.
+`,
+	Example: `biome url github.com/cli/cli
+
+biome url refs/remotes/github.com/cli/cli/heads/trunk:go.mod
+
+biome url github.com/cli/cli@tags/v2.0.0:pkg/cmd
+
+biome url 4a3f2b1
+
+git grep -n --no-color TODO refs/remotes/github.com/cli/cli/HEAD | cut -d: -f1-3 | xargs biome url
+`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var specs []webSpec
+		for _, arg := range args {
+			spec, err := parseWebSpec(arg)
+			if err != nil {
+				return err
+			}
+			specs = append(specs, spec)
+		}
+
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+		for _, spec := range specs {
+			u, err := spec.url(ctx, b)
+			if err != nil {
+				return err
+			}
+			cmdutil.Println(cmd, u)
+		}
+		return nil
+	},
+}
+
+// webSpec names a page of a remote on GitHub, given as
+// <ref-or-remote>[:<path>[:<line>]].
+type webSpec struct {
+
+	// remote is the name of the remote, unless rev is set.
+	remote string
+
+	// ref is the remote's reference, relative to its namespace.
+	ref string
+
+	// rev is a full reference name, or a commit's object ID, that names
+	// both the remote and its commit.
+	rev string
+
+	path string
+	line int
+}
+
+func parseWebSpec(s string) (webSpec, error) {
+	invalid := fmt.Errorf("invalid remote or reference %q, expected <ref-or-remote>[:<path>[:<line>]]", s)
+	name, path, _ := strings.Cut(s, ":")
+	var spec webSpec
+	if p, l, ok := strings.Cut(path, ":"); ok {
+		line, err := strconv.Atoi(l)
+		if err != nil || line <= 0 {
+			return webSpec{}, invalid
+		}
+		path, spec.line = p, line
+	}
+	spec.path = path
+	if name == "" {
+		return webSpec{}, invalid
+	}
+	if strings.HasPrefix(name, "refs/") || isHex(name) {
+		spec.rev = name
+		return spec, nil
+	}
+
+	// remote names cannot contain '@', while references can
+	remote, ref, _ := strings.Cut(name, "@")
+	if parts := strings.SplitN(remote, "/", 4); len(parts) == 4 {
+		if ref != "" {
+			return webSpec{}, invalid
+		}
+		remote, ref = strings.Join(parts[:3], "/"), parts[3]
+	}
+	if remote == "" {
+		return webSpec{}, invalid
+	}
+	spec.remote, spec.ref = remote, ref
+	return spec, nil
+}
+
+// url returns the URL of the named page on GitHub.
+func (s webSpec) url(ctx context.Context, b biome.Biome) (string, error) {
+	var u string
+	var err error
+	if s.rev != "" {
+		u, err = b.RefWebURL(ctx, s.rev, s.path)
+	} else {
+		u, err = b.WebURL(ctx, s.remote, s.ref, s.path)
+	}
+	if err != nil {
+		return "", err
+	}
+	if s.line > 0 && strings.Contains(u, "/blob/") {
+		u += "#L" + strconv.Itoa(s.line)
+	}
+	return u, nil
+}
+
+// isHex reports whether s is an abbreviated or full object ID.
+func isHex(s string) bool {
+	if len(s) < 4 {
+		return false
+	}
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
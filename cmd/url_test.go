@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func init() {
+	urlCmd.SetContext(context.Background())
+	pushInContext(urlCmd)
+}
+
+func TestURLCmd_Execute(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_orirawlings.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	file := filepath.Join(t.TempDir(), "go.mod")
+	if err := os.WriteFile(file, []byte("module github.com/orirawlings/bar\n"), 0o644); err != nil {
+		t.Fatalf("unexpected error writing file: %v", err)
+	}
+	blob := strings.TrimSpace(testutil.Execute(t, "git", "hash-object", "-w", file))
+	tree := strings.TrimSpace(testutil.Execute(t, "sh", "-c", fmt.Sprintf(`printf '100644 blob %s\tgo.mod\n' | git mktree`, blob)))
+	commit := strings.TrimSpace(testutil.Execute(t, "git", "-c", "user.name=A", "-c", "user.email=a@example.com", "commit-tree", "-m", "main", tree))
+	testutil.Execute(t, "git", "update-ref", "refs/remotes/github.com/orirawlings/bar/heads/main", commit)
+	testutil.Execute(t, "git", "symbolic-ref", "refs/remotes/github.com/orirawlings/bar/HEAD", "refs/remotes/github.com/orirawlings/bar/heads/main")
+
+	buf := new(bytes.Buffer)
+	rootCmd.SetOut(buf)
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+	})
+	rootCmd.SetArgs([]string{
+		"url",
+		"github.com/orirawlings/bar",
+		"github.com/orirawlings/bar:go.mod",
+		"github.com/orirawlings/bar/heads/main",
+		"refs/remotes/github.com/orirawlings/bar/heads/main:go.mod:1",
+		commit[:7],
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	expected := strings.Join([]string{
+		"https://github.com/orirawlings/bar",
+		"https://github.com/orirawlings/bar/blob/" + commit + "/go.mod",
+		"https://github.com/orirawlings/bar/commit/" + commit,
+		"https://github.com/orirawlings/bar/blob/" + commit + "/go.mod#L1",
+		"https://github.com/orirawlings/bar/commit/" + commit,
+	}, "\n") + "\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+
+	rootCmd.SetArgs([]string{"url", "refs/remotes/github.com/orirawlings/missing/heads/main"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("expected error for a reference of a remote that is not in the biome")
+	}
+}
+
+func TestParseWebSpec(t *testing.T) {
+	for _, tc := range []struct {
+		spec     string
+		expected webSpec
+	}{
+		{
+			spec:     "github.com/cli/cli",
+			expected: webSpec{remote: "github.com/cli/cli"},
+		},
+		{
+			spec:     "github.com/cli/cli:go.mod",
+			expected: webSpec{remote: "github.com/cli/cli", path: "go.mod"},
+		},
+		{
+			spec:     "github.com/cli/cli@tags/v2.0.0",
+			expected: webSpec{remote: "github.com/cli/cli", ref: "tags/v2.0.0"},
+		},
+		{
+			spec:     "github.com/cli/cli/heads/feature/x:pkg/cmd",
+			expected: webSpec{remote: "github.com/cli/cli", ref: "heads/feature/x", path: "pkg/cmd"},
+		},
+		{
+			spec:     "refs/remotes/github.com/cli/cli/heads/trunk:main.go:12",
+			expected: webSpec{rev: "refs/remotes/github.com/cli/cli/heads/trunk", path: "main.go", line: 12},
+		},
+		{
+			spec:     "4a3f2b1",
+			expected: webSpec{rev: "4a3f2b1"},
+		},
+	} {
+		t.Run(tc.spec, func(t *testing.T) {
+			spec, err := parseWebSpec(tc.spec)
+			testutil.Check(t, err)
+			if !reflect.DeepEqual(spec, tc.expected) {
+				t.Errorf("expected %+v, got %+v", tc.expected, spec)
+			}
+		})
+	}
+	for _, spec := range []string{
+		"",
+		"@main:go.mod",
+		"github.com/cli/cli/heads/main@tags/v1",
+		"github.com/cli/cli:main.go:line",
+	} {
+		if _, err := parseWebSpec(spec); err == nil {
+			t.Errorf("expected error parsing %q", spec)
+		}
+	}
+}
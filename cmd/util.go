@@ -4,8 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"slices"
+	"strconv"
+	"time"
 
+	"github.com/cli/go-gh/v2/pkg/term"
 	"github.com/orirawlings/gh-biome/internal/biome"
 	"github.com/spf13/cobra"
 )
@@ -55,23 +60,433 @@ func parseOwners(args []string) ([]biome.Owner, error) {
 	return owners, errors.Join(errs...)
 }
 
-// fetch git remotes for the given owners (or all remotes if no owners given)
-// in the git repo in the current directory.
-func fetch(ctx context.Context, cmd *cobra.Command, b biome.Biome, owners []biome.Owner) error {
-	fetchArgs := []string{"-C", b.Path(), "fetch"}
-	if len(owners) == 0 {
-		fetchArgs = append(fetchArgs, "--all")
+// parseViewer from command line arguments.
+func parseViewer(host string, affiliations []string) (biome.Viewer, error) {
+	var parsed []biome.RepositoryAffiliation
+	var errs []error
+	for _, affiliation := range affiliations {
+		a, err := biome.ParseAffiliation(affiliation)
+		parsed = append(parsed, a)
+		errs = append(errs, err)
+	}
+	return biome.NewViewer(host, parsed...), errors.Join(errs...)
+}
+
+// formatBytes renders a byte count in human readable, binary units.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// updateRemotes updates the biome's remote configurations. If the biome's API
+// request budget was spent, a warning is printed rather than failing, since
+// the owners and viewers that were not discovered keep their remotes. If
+// owners are given, only their remotes are updated.
+func updateRemotes(cmd *cobra.Command, b biome.Biome, owners ...biome.Owner) error {
+	err := b.UpdateRemotes(cmd.Context(), owners...)
+	if errors.Is(err, biome.ErrAPIBudgetExhausted) {
+		cmd.PrintErrf("Warning: %v\n", err)
+		return nil
+	}
+	return err
+}
+
+// warnAnonymous warns about the degradations of discovering and fetching
+// remotes from each of the biome's GitHub hosts that gh is not logged in to.
+func warnAnonymous(ctx context.Context, cmd *cobra.Command, b biome.Biome) error {
+	hosts, err := b.Hosts(ctx)
+	if err != nil {
+		return err
+	}
+	for _, host := range hosts {
+		if biome.Anonymous(host) {
+			cmd.PrintErrf("Not logged in to %s, so only its public repositories are discovered and fetched, without the upstreams of forks, and with a lower API rate limit. Run 'gh auth login --hostname %s' to include private repositories.\n", host, host)
+		}
+	}
+	return nil
+}
+
+// remoteGroups returns the git remote group names for the given owners.
+func remoteGroups(owners []biome.Owner) []string {
+	var groups []string
+	for _, owner := range owners {
+		groups = append(groups, owner.RemoteGroup())
+	}
+	return groups
+}
+
+// fetchGroups returns the git remote groups to fetch for the given owners, or
+// for all of the biome's owners and viewers if no owners are given, leaving
+// out paused owners. If scheduled is true, owners that are not yet due to be
+// fetched by their fetchFrequency setting are left out too. If no owners are
+// given and none are left out, no groups are returned, so that all remotes
+// are fetched. false is returned if there is nothing to fetch, because all
+// the owners are left out.
+func fetchGroups(ctx context.Context, cmd *cobra.Command, b biome.Biome, owners []biome.Owner, scheduled bool) ([]string, bool, error) {
+	all := len(owners) == 0
+	if all {
+		var err error
+		if owners, err = b.Owners(ctx); err != nil {
+			return nil, false, err
+		}
+	}
+	var due func(biome.Owner) (bool, error)
+	if scheduled {
+		var err error
+		if due, err = fetchSchedule(ctx, b, time.Now()); err != nil {
+			return nil, false, err
+		}
+	}
+	var active []biome.Owner
+	var notDue int
+	for _, owner := range owners {
+		v, err := b.GetOwnerSetting(ctx, owner, "paused")
+		if err != nil {
+			return nil, false, err
+		}
+		if paused, _ := biome.ParseBool(v.Value); paused {
+			cmd.PrintErrf("Skipping paused owner %s\n", owner)
+			continue
+		}
+		if due != nil {
+			ok, err := due(owner)
+			if err != nil {
+				return nil, false, err
+			}
+			if !ok {
+				notDue++
+				continue
+			}
+		}
+		active = append(active, owner)
+	}
+	if notDue > 0 {
+		cmd.PrintErrf("Skipping %d owners not yet due by their fetch frequency\n", notDue)
+	}
+	if all && len(active) == len(owners) {
+		return nil, true, nil
+	}
+	groups := remoteGroups(active)
+	if all {
+		viewers, err := b.Viewers(ctx)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, viewer := range viewers {
+			groups = append(groups, viewer.RemoteGroup())
+		}
+	}
+	return groups, len(groups) > 0, nil
+}
+
+// fetchSchedule returns a function that reports whether an owner is due to
+// be fetched at the given time, because its fetchFrequency setting has
+// elapsed since its remotes were last fetched, according to the biome's
+// journal. Owners without a frequency, or that have never been fetched, are
+// always due.
+func fetchSchedule(ctx context.Context, b biome.Biome, now time.Time) (func(biome.Owner) (bool, error), error) {
+	entries, err := b.Journal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fetched := biome.OwnerFetchTimes(entries)
+	return func(owner biome.Owner) (bool, error) {
+		v, err := b.GetOwnerSetting(ctx, owner, "fetchFrequency")
+		if err != nil {
+			return false, err
+		}
+		frequency, err := biome.ParseFetchFrequency(v.Value)
+		if err != nil {
+			return false, fmt.Errorf("invalid fetch frequency of %s: %q: %w", owner, v.Value, err)
+		}
+		last, ok := fetched[owner.String()]
+		return frequency == 0 || !ok || !now.Before(last.Add(frequency)), nil
+	}, nil
+}
+
+// fetch git remotes for the given remote groups (or all remotes if no groups
+// given) in the git repo in the current directory. How long each remote takes
+// to fetch is recorded in the biome's journal, to estimate the duration of
+// future fetches. git authenticates with the tokens stored by gh, unless the
+// biome is configured otherwise. Git LFS objects are handled according to the
+// biome's LFS policy. If --jobs is given, or the biome uses the reftable
+// backend, a pool of git processes fetch one remote each at a time, see
+// [pooledFetchRun].
+func fetch(ctx context.Context, cmd *cobra.Command, b biome.Biome, groups []string) error {
+	policy, err := lfsPolicy(ctx, b, fetchLFS)
+	if err != nil {
+		return err
+	}
+
+	credentials, err := credentialArgs(ctx, b)
+	if err != nil {
+		return err
+	}
+	plan, err := planFetch(ctx, b, groups)
+	if err != nil {
+		return err
+	}
+
+	quarantine, err := fetchQuarantine(ctx, b)
+	if err != nil {
+		return err
+	}
+	var q *biome.Quarantine
+	if quarantine {
+		if q, err = b.NewQuarantine(ctx); err != nil {
+			return err
+		}
+		defer q.Discard()
+	}
+
+	// gitFetch returns a git fetch process. With a quarantine, the process
+	// only receives objects into the quarantine, checking them, without
+	// updating references.
+	gitFetch := func(args ...string) *exec.Cmd {
+		fetchArgs := append([]string{"-C", b.Path()}, credentials...)
+		if q != nil {
+			fetchArgs = append(fetchArgs, q.ConfigArgs()...)
+		}
+		fetchArgs = append(fetchArgs, "fetch")
+		if q != nil {
+			fetchArgs = append(fetchArgs, "--dry-run")
+		}
+		c := exec.CommandContext(ctx, "git", append(fetchArgs, args...)...)
+		c.Env = append(os.Environ(), policy.Env()...)
+		if q != nil {
+			c.Env = append(c.Env, q.Env()...)
+		}
+		return c
+	}
+	limits, err := hostLimits(ctx, b)
+	if err != nil {
+		return err
+	}
+	patterns, err := fetchRefPatterns()
+	if err != nil {
+		return err
+	}
+	// reftable allows a single writer at a time, so with the reftable
+	// backend, fetches only receive objects, and their reference updates
+	// are queued, rather than written by concurrent git processes. With a
+	// quarantine, the reference updates are likewise held, and only applied
+	// once the received objects pass validation.
+	format, err := b.RefFormat(ctx)
+	if err != nil {
+		return err
+	}
+	refs := newRefQueue(b.Path())
+	serialized := format == biome.RefFormatReftable || q != nil
+	newRun := func() (*fetchRun, error) {
+		var cmds []*exec.Cmd
+		switch {
+		case len(patterns) > 0:
+			var queue *refQueue
+			if serialized {
+				queue = refs
+			}
+			return narrowedFetchRun(ctx, b, plan, patterns, limits, queue, q == nil, gitFetch)
+		case fetchJobs > 0 || serialized:
+			// drive a pool of git processes, one per remote, applying
+			// their reference updates one remote at a time, rather than
+			// relying on git's own parallelism within one process
+			n, err := fetchProcesses(ctx, b)
+			if err != nil {
+				return nil, err
+			}
+			return pooledFetchRun(ctx, refs, plan, n, limits, q == nil, gitFetch), nil
+		case len(limits) > 0:
+			// fetch the remotes of hosts with a concurrency limit in their
+			// own git processes, so that the limit holds across processes
+			parallel, err := b.GetSetting(ctx, "fetch.parallel")
+			if err != nil {
+				return nil, err
+			}
+			n, _ := strconv.Atoi(parallel.Value)
+			for _, batch := range plan.batches(n, limits) {
+				args := []string{"--multiple"}
+				if batch.jobs > 0 {
+					args = append(args, fmt.Sprintf("--jobs=%d", batch.jobs))
+				}
+				cmds = append(cmds, gitFetch(append(args, batch.remotes...)...))
+			}
+		case len(groups) == 0:
+			cmds = append(cmds, gitFetch("--all"))
+		default:
+			cmds = append(cmds, gitFetch(append([]string{"--multiple"}, groups...)...))
+		}
+		return newFetchRun(cmds), nil
+	}
+	run, err := newRun()
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+
+	// render a live dashboard instead of git's output, but only when a
+	// terminal can display it
+	var runErr error
+	if f, ok := cmd.ErrOrStderr().(*os.File); ok && fetchUI && term.IsTerminal(f) {
+		d := newFetchDashboard(f, plan)
+		runErr = run.run(d, d)
+		if err := d.Close(); runErr == nil {
+			runErr = err
+		}
 	} else {
-		fetchArgs = append(fetchArgs, "--multiple")
-		for _, owner := range owners {
-			fetchArgs = append(fetchArgs, owner.RemoteGroup())
+		if plan.known() {
+			cmd.PrintErrf("Fetching %d remotes, estimated to take %s\n", len(plan.remotes), plan.total().Round(time.Second))
 		}
+		runErr = run.run(cmd.OutOrStdout(), cmd.ErrOrStderr())
+		summary := fmt.Sprintf("Fetched %d remotes in %s", run.fetched(), time.Since(start).Round(time.Second))
+		if plan.known() {
+			summary += fmt.Sprintf(" (estimated %s)", plan.total().Round(time.Second))
+		}
+		cmd.PrintErrln(summary)
+	}
+	runErr = errors.Join(runErr, b.Record(ctx, run.finish(ctx.Err() != nil)...))
+	if q != nil && ctx.Err() == nil {
+		// update references from the received objects only once they pass
+		// validation, even if some remotes failed to fetch
+		runErr = errors.Join(runErr, releaseQuarantine(ctx, cmd, q, refs))
+	}
+	if runErr != nil {
+		return runErr
+	}
+
+	// set the HEAD references of remotes whose default branches were just
+	// fetched for the first time. Newly fetched branches may also tell which
+	// branch serves as the default branch of remotes without one.
+	if _, err := b.ResolveHeads(ctx); err != nil {
+		return err
+	}
+	if _, err := b.SynthesizeHeads(ctx); err != nil {
+		return err
 	}
-	c := exec.CommandContext(ctx, "git", fetchArgs...)
-	c.Stdout = cmd.OutOrStdout()
-	c.Stderr = cmd.ErrOrStderr()
-	if err := c.Run(); err != nil {
-		return fmt.Errorf("could not %q: %w", c, err)
+	if policy == biome.LFSSelected {
+		return fetchLFSObjects(ctx, cmd, b, credentials, plan.remotes)
 	}
 	return nil
 }
+
+// fetchQuarantine reports whether fetches receive objects into a quarantine,
+// given by the --quarantine flag, or the biome.fetch.quarantine setting.
+func fetchQuarantine(ctx context.Context, b biome.Biome) (bool, error) {
+	if fetchQuarantined {
+		return true, nil
+	}
+	v, err := b.GetSetting(ctx, "biome.fetch.quarantine")
+	if err != nil {
+		return false, err
+	}
+	quarantine, _ := biome.ParseBool(v.Value)
+	return quarantine, nil
+}
+
+// releaseQuarantine validates the objects received into the quarantine, and
+// migrates them into the biome if they pass. The reference updates that the
+// quarantined fetches reported, and the queue held, are then applied, so the
+// references point to exactly the objects that were validated. If validation
+// fails, the quarantine is discarded, and no references are updated.
+func releaseQuarantine(ctx context.Context, cmd *cobra.Command, q *biome.Quarantine, refs *refQueue) error {
+	if err := q.Validate(ctx); err != nil {
+		return fmt.Errorf("could not release quarantine, discarding received objects: %w", err)
+	}
+	if err := q.Migrate(); err != nil {
+		return err
+	}
+	cmd.PrintErrln("Received objects passed validation, updating references...")
+	return refs.release(ctx)
+}
+
+// hostLimits returns the limit on the number of each GitHub host's remotes
+// that are fetched concurrently, for the biome's hosts that have one.
+func hostLimits(ctx context.Context, b biome.Biome) (map[string]int, error) {
+	hosts, err := b.Hosts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	limits := make(map[string]int)
+	for _, host := range hosts {
+		v, err := b.GetSetting(ctx, biome.HostSettingKey(host, "maxConcurrent"))
+		if err != nil {
+			return nil, err
+		}
+		if limit, _ := strconv.Atoi(v.Value); limit > 0 {
+			limits[host] = limit
+		}
+	}
+	return limits, nil
+}
+
+// lfsPolicy returns the given override LFS policy, ex. from a command line
+// flag, or the policy recorded in the biome if there is no override.
+func lfsPolicy(ctx context.Context, b biome.Biome, override string) (biome.LFSPolicy, error) {
+	if override != "" {
+		return biome.ParseLFSPolicy(override)
+	}
+	v, err := b.GetSetting(ctx, "biome.lfs.policy")
+	if err != nil {
+		return "", err
+	}
+	return biome.ParseLFSPolicy(v.Value)
+}
+
+// fetchLFSObjects fetches the Git LFS objects of the default branch of each
+// of the given remotes that has been selected for LFS fetches, using the git
+// credential options given by [credentialArgs].
+func fetchLFSObjects(ctx context.Context, cmd *cobra.Command, b biome.Biome, credentials, remotes []string) error {
+	selected, err := b.LFSRemotes(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, r := range selected {
+		if !slices.Contains(remotes, r.Name) {
+			continue
+		}
+		cmd.PrintErrf("Fetching LFS objects for %s...\n", r.Name)
+		args := append([]string{"-C", b.Path()}, credentials...)
+		args = append(args, "lfs", "fetch", r.Name, r.Head())
+		c := exec.CommandContext(ctx, "git", args...)
+		c.Stdout, c.Stderr = cmd.OutOrStdout(), cmd.ErrOrStderr()
+		if err := c.Run(); err != nil {
+			errs = append(errs, fmt.Errorf("could not %q: %w", c, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// planFetch lists the remotes that will be fetched for the given remote
+// groups (or all remotes if no groups given), with estimates of how long
+// each takes to fetch from the biome's journal.
+func planFetch(ctx context.Context, b biome.Biome, groups []string) (fetchPlan, error) {
+	var remotes []string
+	if len(groups) == 0 {
+		all, err := b.Remotes(ctx, biome.FetchableRemoteCategories...)
+		if err != nil {
+			return fetchPlan{}, err
+		}
+		for _, r := range all {
+			remotes = append(remotes, r.Name)
+		}
+	} else {
+		members, err := b.RemoteGroupMembers(ctx, groups...)
+		if err != nil {
+			return fetchPlan{}, err
+		}
+		remotes = members
+	}
+	entries, err := b.Journal(ctx)
+	if err != nil {
+		return fetchPlan{}, err
+	}
+	return newFetchPlan(remotes, biome.FetchEstimates(entries)), nil
+}
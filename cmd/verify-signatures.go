@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/orirawlings/gh-biome/internal/biome"
+	"github.com/spf13/cobra"
+)
+
+var (
+	verifySignaturesSince   string
+	verifySignaturesOptions = newRemoteCategoryOptions(true)
+)
+
+func init() {
+	verifySignaturesCmd.Flags().StringVar(&verifySignaturesSince, "since", "", "Verify every commit of each remote's default branch committed since this git date, ex. 1.month.ago, or this long ago, ex. 90d, 12w, or 1y, rather than only the commit at HEAD.")
+	verifySignaturesOptions.AddFlags(verifySignaturesCmd.Flags())
+	rootCmd.AddCommand(verifySignaturesCmd)
+}
+
+var verifySignaturesCmd = &cobra.Command{
+	Use:   "verify-signatures",
+	Short: "Summarize the GPG and SSH signatures of commits across the biome's remotes",
+	Long: `
+Verify the GPG or SSH signatures of the commit at the HEAD of each fetched
+remote, counting good, bad, unverified, and unsigned commits per remote, ex.
+for compliance audits. With --since, every commit of each remote's default
+branch that was committed since the given git date, or duration ago, ex. 90d,
+is verified instead.
+
+Signatures are verified by git, with the user's gpg configuration. Signatures
+by keys that are not in the user's GPG keyring are unverified. SSH signatures
+are verified against gpg.ssh.allowedSignersFile. If it is not configured,
+intact SSH signatures are counted as good, though the signer is unknown.
+
+By default, only active remotes are verified. Use the category flags to
+select other remotes.
+`,
+	Example: `biome verify-signatures
+
+biome verify-signatures --since 1.month.ago --all
+
+biome verify-signatures --since 90d
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		reports, err := b.VerifySignatures(ctx, gitDate(verifySignaturesSince), verifySignaturesOptions.Categories()...)
+		if err != nil {
+			return err
+		}
+		var total biome.SignatureReport
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "REMOTE\tCOMMITS\tGOOD\tBAD\tUNVERIFIED\tUNSIGNED")
+		for _, r := range reports {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\n", r.Remote, r.Commits(), r.Good, r.Bad, r.Unverified, r.Unsigned)
+			total.Good += r.Good
+			total.Bad += r.Bad
+			total.Unverified += r.Unverified
+			total.Unsigned += r.Unsigned
+		}
+		fmt.Fprintf(w, "TOTAL\t%d\t%d\t%d\t%d\t%d\n", total.Commits(), total.Good, total.Bad, total.Unverified, total.Unsigned)
+		return w.Flush()
+	},
+}
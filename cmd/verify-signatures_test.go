@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+)
+
+func init() {
+	verifySignaturesCmd.SetContext(context.Background())
+	pushInContext(verifySignaturesCmd)
+}
+
+func TestVerifySignaturesCmd_Args(t *testing.T) {
+	t.Cleanup(verifySignaturesOptions.Reset)
+	rootCmd.SetArgs([]string{"verify-signatures", "github.com/cli/cli"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("expected error executing verify-signatures with arguments")
+	}
+}
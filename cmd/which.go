@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var whichFormat outputFormat
+
+func init() {
+	addFormatFlag(whichCmd.Flags(), &whichFormat)
+	rootCmd.AddCommand(whichCmd)
+}
+
+var whichCmd = &cobra.Command{
+	Use:   "which <commit>",
+	Short: "List the remotes of the git biome whose references reach a commit",
+	Long: `
+List the remotes of the git biome whose references reach a commit, to
+answer where a commit came from, and whether any other repository has it,
+across every remote of the biome at once, ex. to trace a vulnerable commit
+through forks and vendored copies of a repository.
+
+HEAD is true for remotes whose default branch reaches the commit. REFS lists
+the remote's references that reach it, relative to the remote's namespace,
+ex. heads/main or tags/v1.0.
+
+The commit is given by its object ID, full or abbreviated, or any other name
+that git resolves to a commit in the biome.
+`,
+	Example: `biome which 4a3f2b1
+
+biome which --format csv 4a3f2b1c8e2d9f0a7b6c5d4e3f2a1b0c9d8e7f6a
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		remotes, err := b.CommitRemotes(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		if len(remotes) == 0 {
+			return fmt.Errorf("commit %s is not reachable from any remote", args[0])
+		}
+		w := newReportWriter(cmd, whichFormat, "remote", "head", "refs")
+		for _, r := range remotes {
+			w.Row(r.Remote, strconv.FormatBool(r.Head), strings.Join(r.Refs, " "))
+		}
+		return w.Flush()
+	},
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func init() {
+	whichCmd.SetContext(context.Background())
+	pushInContext(whichCmd)
+}
+
+func TestWhichCmd_Execute(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_orirawlings.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	tree := strings.TrimSpace(testutil.Execute(t, "git", "write-tree"))
+	commit := strings.TrimSpace(testutil.Execute(t, "git", "-c", "user.name=A", "-c", "user.email=a@example.com", "commit-tree", "-m", "main", tree))
+	testutil.Execute(t, "git", "update-ref", "refs/remotes/github.com/orirawlings/bar/heads/main", commit)
+	testutil.Execute(t, "git", "update-ref", "refs/remotes/github.com/orirawlings/headless/tags/v1", commit)
+	testutil.Execute(t, "git", "symbolic-ref", "refs/remotes/github.com/orirawlings/bar/HEAD", "refs/remotes/github.com/orirawlings/bar/heads/main")
+
+	buf := new(bytes.Buffer)
+	whichCmd.SetOut(buf)
+	t.Cleanup(func() {
+		whichCmd.SetOut(nil)
+		whichFormat = tableFormat
+	})
+	rootCmd.SetArgs([]string{"which", "--format", "csv", commit[:7]})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	expected := strings.Join([]string{
+		"remote,head,refs",
+		"github.com/orirawlings/bar,true,heads/main",
+		"github.com/orirawlings/headless,false,tags/v1",
+	}, "\n") + "\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+
+	unreachable := strings.TrimSpace(testutil.Execute(t, "git", "-c", "user.name=A", "-c", "user.email=a@example.com", "commit-tree", "-m", "unreachable", tree))
+	rootCmd.SetArgs([]string{"which", unreachable})
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("expected error for a commit that no remote reaches")
+	}
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(whoamiCmd)
+}
+
+var whoamiCmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Show which account the git biome authenticates as on each GitHub host",
+	Long: `
+Show, for each GitHub server referenced by the git biome, which account biome
+authenticates as, the type of token, and where the token was found, ex. the gh
+config file, gh's secure storage, or an environment variable such as GH_TOKEN
+or GH_ENTERPRISE_TOKEN.
+
+GitHub App installation tokens do not act on behalf of a user, so no account
+is shown for them.
+`,
+	Example: `biome whoami
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		b, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		ids, err := b.Whoami(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "HOST\tACCOUNT\tTOKEN\tSOURCE")
+		for _, id := range ids {
+			account, kind, source := id.Login, id.Kind, id.Source
+			switch {
+			case source == "":
+				account, kind, source = "-", "-", "not logged in"
+			case id.Err != nil:
+				account = "?"
+				cmd.PrintErrf("%s: %v\n", id.Host, id.Err)
+			case account == "":
+				account = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", id.Host, account, kind, source)
+		}
+		return w.Flush()
+	},
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"gopkg.in/h2non/gock.v1"
+)
+
+func init() {
+	whoamiCmd.SetContext(context.Background())
+	pushInContext(whoamiCmd)
+}
+
+func TestWhoamiCmd_Execute(t *testing.T) {
+	initBiome(t)
+	stubGitHub(t)
+	t.Setenv("GITHUB_TOKEN", "")
+	rootCmd.SetArgs([]string{
+		"add",
+		"--skip-fetch",
+		github_com_cli.String(),
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+
+	t.Setenv("GH_TOKEN", "gho_abc123")
+	gock.New("https://api.github.com").
+		Get("/user").
+		Reply(200).
+		JSON(`{"login":"user1"}`)
+
+	buf := new(bytes.Buffer)
+	whoamiCmd.SetOut(buf)
+	t.Cleanup(func() {
+		whoamiCmd.SetOut(nil)
+	})
+	rootCmd.SetArgs([]string{"whoami"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error executing command: %v", err)
+	}
+	expected := "HOST        ACCOUNT  TOKEN        SOURCE\n" +
+		"github.com  user1    OAuth token  GH_TOKEN environment variable\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
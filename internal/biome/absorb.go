@@ -0,0 +1,287 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	slicesutil "github.com/orirawlings/gh-biome/internal/util/slices"
+)
+
+var (
+	// errAbsorbSelf indicates that a biome cannot absorb itself.
+	errAbsorbSelf = errors.New("a biome cannot absorb itself")
+
+	// errObjectFormatMismatch indicates that a biome cannot absorb another
+	// biome that names its objects with a different hash algorithm.
+	errObjectFormatMismatch = errors.New("biomes use different object formats")
+)
+
+// Absorption describes what a biome imported from another biome.
+type Absorption struct {
+
+	// Owners lists the other biome's owners that joined the biome.
+	Owners []Owner
+
+	// Viewers lists the other biome's authenticated users whose accessible
+	// repositories joined the biome.
+	Viewers []Viewer
+
+	// Remotes lists the names of the other biome's remotes that were added
+	// to the biome.
+	Remotes []string
+
+	// Refs is the number of references imported from the other biome.
+	Refs int
+}
+
+// Absorb imports the owners, viewers, remotes, references, and objects of
+// the biome at the given path, so separately grown biomes can be
+// consolidated. Owners, viewers, and remotes that are already in this biome
+// are kept as they are, along with their references, which are refreshed by
+// the next fetch. Remotes that were evicted from the other biome are not
+// absorbed, they must be restored in the other biome first.
+//
+// Objects are copied from the other biome with a local fetch. If shared is
+// true, the other biome's object store is instead added to the biome's git
+// alternates, so no objects are copied, but the biome depends on the other
+// biome's objects from then on.
+func (b *biome) Absorb(ctx context.Context, path string, shared bool) (Absorption, error) {
+	var absorption Absorption
+	other := &biome{
+		path:          path,
+		editorOptions: b.editorOptions,
+	}
+	if err := other.validate(ctx); err != nil {
+		return absorption, err
+	}
+	gitDir, err := b.gitDir(ctx)
+	if err != nil {
+		return absorption, err
+	}
+	otherGitDir, err := other.gitDir(ctx)
+	if err != nil {
+		return absorption, err
+	}
+	if gitDir == otherGitDir {
+		return absorption, fmt.Errorf("%w: %s", errAbsorbSelf, path)
+	}
+	format, err := b.git(ctx, "rev-parse", "--show-object-format")
+	if err != nil {
+		return absorption, err
+	}
+	otherFormat, err := other.git(ctx, "rev-parse", "--show-object-format")
+	if err != nil {
+		return absorption, err
+	}
+	if string(format) != string(otherFormat) {
+		return absorption, fmt.Errorf("%w: %s and %s", errObjectFormatMismatch, strings.TrimSpace(string(format)), strings.TrimSpace(string(otherFormat)))
+	}
+
+	otherCfg, err := config.Read(ctx, other.path)
+	if err != nil {
+		return absorption, err
+	}
+	var known map[string]string
+	if err := b.readConfig(ctx, func(ctx context.Context, cfg *config.Config) error {
+		known = knownRemotes(cfg)
+		return nil
+	}); err != nil {
+		return absorption, err
+	}
+
+	// the other biome's remotes that are new to this biome, and their
+	// reference namespaces
+	var namespaces []string
+	for key, name := range knownRemotes(otherCfg) {
+		if _, ok := known[key]; ok || evictedBundle(otherCfg, name) != "" {
+			continue
+		}
+		absorption.Remotes = append(absorption.Remotes, name)
+		if !otherCfg.Section("remote").HasSubsection(name) {
+			// disabled, locked, or unsupported remotes have no references
+			continue
+		}
+		namespace, ok := refNamespaceOf(otherCfg.Section("remote").Subsection(name).Options.Get("fetch"))
+		if !ok {
+			namespace = Remote{Name: name}.RefNamespace()
+		}
+		namespaces = append(namespaces, namespace)
+	}
+	slices.Sort(absorption.Remotes)
+	slices.Sort(namespaces)
+
+	if shared {
+		if err := b.addAlternate(gitDir, filepath.Join(otherGitDir, "objects")); err != nil {
+			return absorption, err
+		}
+	}
+	if absorption.Refs, err = b.fetchNamespaces(ctx, other, otherGitDir, namespaces); err != nil {
+		return absorption, fmt.Errorf("could not fetch references from %s: %w", path, err)
+	}
+
+	err = b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		// the edit is retried if the config changes concurrently
+		absorption.Owners, absorption.Viewers = nil, nil
+		owners, err := b.getOwners(cfg)
+		if err != nil {
+			return false, fmt.Errorf("could not load repository owners: %w", err)
+		}
+		otherOwners, err := other.getOwners(otherCfg)
+		if err != nil {
+			return false, fmt.Errorf("could not load repository owners of %s: %w", path, err)
+		}
+		biomeSection := cfg.Section(section)
+		ownerRefs := biomeSection.OptionAll(ownersOpt)
+		for _, owner := range otherOwners {
+			if slices.ContainsFunc(owners, func(o Owner) bool {
+				return strings.EqualFold(o.String(), owner.String())
+			}) {
+				continue
+			}
+			absorption.Owners = append(absorption.Owners, owner)
+			ownerRefs = append(ownerRefs, owner.String())
+			copySubsection(otherCfg, cfg, section, ownerSubsectionPrefix+owner.String())
+		}
+
+		// store all owners, sorted as by AddOwners
+		biomeSection.RemoveOption(ownersOpt)
+		for _, ownerRef := range slicesutil.SortedUnique(ownerRefs) {
+			biomeSection.AddOption(ownersOpt, ownerRef)
+		}
+
+		viewers, err := other.getViewers(otherCfg)
+		if err != nil {
+			return false, fmt.Errorf("could not load authenticated users of %s: %w", path, err)
+		}
+		for _, viewer := range viewers {
+			if biomeSection.HasSubsection(viewerSubsectionPrefix + viewer.Host()) {
+				continue
+			}
+			absorption.Viewers = append(absorption.Viewers, viewer)
+			copySubsection(otherCfg, cfg, section, viewerSubsectionPrefix+viewer.Host())
+		}
+
+		absorbed := make(map[string]struct{})
+		for _, name := range absorption.Remotes {
+			absorbed[name] = struct{}{}
+			copySubsection(otherCfg, cfg, "remote", name)
+			copySubsection(otherCfg, cfg, section, remoteSubsectionPrefix+name)
+		}
+		for _, opt := range otherCfg.Section("remotes").Options {
+			if _, ok := absorbed[opt.Value]; ok && remoteGroupPattern.MatchString(opt.Key) {
+				cfg.Section("remotes").AddOption(opt.Key, opt.Value)
+			}
+		}
+		metadata := cfg.Section(section).Subsection(remotesSubsection)
+		for _, opt := range otherCfg.Section(section).Subsection(remotesSubsection).Options {
+			name, _, _ := strings.Cut(opt.Value, " ")
+			if _, ok := absorbed[name]; ok {
+				metadata.AddOption(opt.Key, opt.Value)
+			}
+		}
+		return true, nil
+	})
+	return absorption, err
+}
+
+// knownRemotes returns the names of the remotes recorded in the biome's
+// remote metadata, or configured as members of the biome's remote groups,
+// keyed by their lower case name, since GitHub treats repository names
+// case-insensitively.
+func knownRemotes(cfg *config.Config) map[string]string {
+	names := make(map[string]string)
+	add := func(name string) {
+		if _, ok := names[strings.ToLower(name)]; !ok {
+			names[strings.ToLower(name)] = name
+		}
+	}
+	for _, opt := range cfg.Section(section).Subsection(remotesSubsection).Options {
+		name, _, _ := strings.Cut(opt.Value, " ")
+		add(name)
+	}
+	for _, opt := range cfg.Section("remotes").Options {
+		if remoteGroupPattern.MatchString(opt.Key) {
+			add(opt.Value)
+		}
+	}
+	return names
+}
+
+// copySubsection copies the options of a git config subsection from one
+// config to another, if the subsection exists.
+func copySubsection(from, to *config.Config, section, subsection string) {
+	if !from.Section(section).HasSubsection(subsection) {
+		return
+	}
+	ss := to.Section(section).Subsection(subsection)
+	for _, opt := range from.Section(section).Subsection(subsection).Options {
+		ss.AddOption(opt.Key, opt.Value)
+	}
+}
+
+// addAlternate adds an object directory to the biome's git alternates, unless
+// it is already listed.
+func (b *biome) addAlternate(gitDir, objects string) error {
+	path := filepath.Join(gitDir, "objects", "info", "alternates")
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if slices.Contains(strings.Split(string(data), "\n"), objects) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(f, objects); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// fetchNamespaces copies the references of the given namespaces, and the
+// objects they reach, from the other biome with a local fetch. HEAD
+// references are fetched as the object they point to, so they are made
+// symbolic references again afterwards. It returns the number of references
+// copied.
+func (b *biome) fetchNamespaces(ctx context.Context, other *biome, otherGitDir string, namespaces []string) (int, error) {
+	if len(namespaces) == 0 {
+		return 0, nil
+	}
+	refs, err := other.listRefs(ctx, namespaces)
+	if err != nil {
+		return 0, err
+	}
+	var refspecs strings.Builder
+	for _, namespace := range namespaces {
+		fmt.Fprintf(&refspecs, "+%s*:%s*\n", namespace, namespace)
+	}
+	if _, err := b.gitStdin(ctx, strings.NewReader(refspecs.String()), "fetch", "--quiet", "--no-tags", "--no-write-fetch-head", "--stdin", otherGitDir); err != nil {
+		return 0, err
+	}
+
+	w, err := b.updateRefs(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, ref := range refs {
+		if ref.Symref == "" {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "option no-deref\nsymref-update %s %s\n", ref.Name, ref.Symref); err != nil {
+			return 0, fmt.Errorf("could not update %s: %w", ref.Name, err)
+		}
+	}
+	return len(refs), w.Close()
+}
@@ -0,0 +1,122 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+// absorbable initializes a biome with the given owners, and configures an
+// active remote of the first owner with a commit on its default branch for
+// each of the given remotes.
+func absorbable(t *testing.T, ctx context.Context, owners []Owner, remotes ...remoteConfig) (*biome, map[string]string) {
+	t.Helper()
+	path := t.TempDir()
+	b := initBiome(t, ctx, path, true).(*biome)
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		for _, owner := range owners {
+			cfg.Section(section).AddOption(ownersOpt, owner.String())
+		}
+		for _, r := range remotes {
+			refspec, err := r.Remote.FetchRefspec()
+			if err != nil {
+				return false, err
+			}
+			cfg.Section("remote").Subsection(r.Remote.Name).SetOption("url", r.Remote.FetchURL())
+			cfg.Section("remote").Subsection(r.Remote.Name).SetOption("fetch", refspec)
+			cfg.Section("remotes").AddOption(owners[0].RemoteGroup(), r.Remote.Name)
+			cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, r.Remote.Name)
+		}
+		return true, nil
+	}))
+	commits := make(map[string]string)
+	for _, r := range remotes {
+		commits[r.Remote.Name] = commitTree(t, path, "initial commit of "+r.Remote.Name)
+		testutil.Execute(t, "git", "-C", path, "update-ref", r.Head, commits[r.Remote.Name])
+		testutil.Execute(t, "git", "-C", path, "symbolic-ref", r.Remote.Head(), r.Head)
+	}
+	return b, commits
+}
+
+func TestBiome_Absorb(t *testing.T) {
+	ctx := context.Background()
+	cliCfg := remoteConfig{
+		Remote: githubCLICLIRemote,
+		Head:   "refs/remotes/github.com/cli/cli/heads/trunk",
+	}
+	b, commits := absorbable(t, ctx, []Owner{github_com_orirawlings}, barRemoteCfg)
+	other, otherCommits := absorbable(t, ctx, []Owner{github_com_cli, github_com_orirawlings}, cliCfg, barRemoteCfg)
+
+	t.Run("self", func(t *testing.T) {
+		if _, err := b.Absorb(ctx, b.path, false); !errors.Is(err, errAbsorbSelf) {
+			t.Errorf("expected %v, was %v", errAbsorbSelf, err)
+		}
+	})
+
+	t.Run("not a biome", func(t *testing.T) {
+		_, err := b.Absorb(ctx, testutil.TempRepo(t), false)
+		testutil.ExpectError(t, err)
+	})
+
+	absorption, err := b.Absorb(ctx, other.path, false)
+	testutil.Check(t, err)
+	if !slices.Equal(absorption.Owners, []Owner{github_com_cli}) {
+		t.Errorf("expected absorbed owners %v, was %v", []Owner{github_com_cli}, absorption.Owners)
+	}
+	if !slices.Equal(absorption.Remotes, []string{githubCLICLIRemote.Name}) {
+		t.Errorf("expected absorbed remotes %v, was %v", []string{githubCLICLIRemote.Name}, absorption.Remotes)
+	}
+	if absorption.Refs != 2 {
+		t.Errorf("expected 2 absorbed refs, was %d", absorption.Refs)
+	}
+
+	// overlapping remotes keep their references
+	expectRefs(t, ctx, b.path, []string{
+		fmt.Sprintf(`%s commit %s %s`, otherCommits[githubCLICLIRemote.Name], githubCLICLIRemote.Head(), cliCfg.Head),
+		fmt.Sprintf(`%s commit %s `, otherCommits[githubCLICLIRemote.Name], cliCfg.Head),
+		fmt.Sprintf(`%s commit %s %s`, commits[barRemote.Name], barRemote.Head(), barRemoteCfg.Head),
+		fmt.Sprintf(`%s commit %s `, commits[barRemote.Name], barRemoteCfg.Head),
+	})
+
+	owners, err := b.Owners(ctx)
+	testutil.Check(t, err)
+	if expected := []Owner{github_com_cli, github_com_orirawlings}; !slices.Equal(owners, expected) {
+		t.Errorf("expected owners %v, was %v", expected, owners)
+	}
+	remotes, err := b.Remotes(ctx, Active)
+	testutil.Check(t, err)
+	if expected := []Remote{githubCLICLIRemote, barRemote}; !slices.Equal(remotes, expected) {
+		t.Errorf("expected remotes %v, was %v", expected, remotes)
+	}
+	assertGitConfig(t, b.path, "remote.github.com/cli/cli.url", githubCLICLIRemote.FetchURL())
+
+	// absorbing again adds nothing
+	absorption, err = b.Absorb(ctx, other.path, false)
+	testutil.Check(t, err)
+	if len(absorption.Owners) > 0 || len(absorption.Remotes) > 0 || absorption.Refs > 0 {
+		t.Errorf("expected nothing to be absorbed again, was %+v", absorption)
+	}
+}
+
+func TestBiome_Absorb_shared(t *testing.T) {
+	ctx := context.Background()
+	b, _ := absorbable(t, ctx, []Owner{github_com_cli})
+	other, _ := absorbable(t, ctx, []Owner{github_com_orirawlings}, barRemoteCfg)
+
+	_, err := b.Absorb(ctx, other.path, true)
+	testutil.Check(t, err)
+	alternates, err := os.ReadFile(filepath.Join(b.path, "objects", "info", "alternates"))
+	testutil.Check(t, err)
+	if expected := filepath.Join(other.path, "objects"); strings.TrimSpace(string(alternates)) != expected {
+		t.Errorf("expected alternates %q, was %q", expected, alternates)
+	}
+	testutil.Execute(t, "git", "-C", b.path, "rev-parse", "--verify", barRemoteCfg.Head)
+}
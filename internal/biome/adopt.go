@@ -0,0 +1,183 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+)
+
+var (
+	// errAlreadyBiome indicates that a repository cannot be adopted, because
+	// it is already a biome.
+	errAlreadyBiome = errors.New("repository is already a biome")
+
+	// errAdoptNonBare indicates that a non-bare repository cannot be
+	// adopted, since its branches would be moved out from under its working
+	// tree.
+	errAdoptNonBare = errors.New("only bare repositories can be adopted")
+
+	// errRemoteURLMissing indicates that the repository to adopt has no URL
+	// for the remote it was cloned from.
+	errRemoteURLMissing = errors.New("remote URL not configured")
+
+	// errRemoteURLInvalid indicates that a remote URL does not name a GitHub
+	// repository as <host>/<owner>/<repo>.
+	errRemoteURLInvalid = errors.New("remote URL does not name a repository as <host>/<owner>/<repo>")
+)
+
+// Adopt converts an existing bare repository, ex. one created with
+// `git clone --mirror` or `git clone --bare`, into a biome at the given
+// filesystem directory path. The repository is identified by the URL of the
+// named git remote it was cloned from, ex. "origin". All references of the
+// repository are moved into the reference namespace of the repository's
+// remote in the biome, ex. refs/heads/main becomes
+// refs/remotes/github.com/<owner>/<repo>/heads/main, and the remote's HEAD
+// is pointed at the branch of the repository's HEAD. The cloned-from remote
+// is replaced by the biome's remote, and the repository's owner is added to
+// the biome, so the owner's other repositories are added the next time
+// remotes are updated.
+func Adopt(ctx context.Context, path, remote string, opts ...BiomeOption) (Biome, Remote, error) {
+	b := &biome{
+		path: path,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	switch err := b.validate(ctx); err {
+	case nil:
+		return nil, Remote{}, fmt.Errorf("%w: %s", errAlreadyBiome, path)
+	case errVersionNotSet:
+	default:
+		return nil, Remote{}, err
+	}
+	if b.nonBare(ctx) {
+		return nil, Remote{}, fmt.Errorf("%w: %s", errAdoptNonBare, path)
+	}
+	if err := b.checkNotShallow(ctx); err != nil {
+		return nil, Remote{}, err
+	}
+
+	var remoteURL string
+	if err := b.readConfig(ctx, func(ctx context.Context, cfg *config.Config) error {
+		if cfg.Section("remote").HasSubsection(remote) {
+			remoteURL = cfg.Section("remote").Subsection(remote).Options.Get("url")
+		}
+		return nil
+	}); err != nil {
+		return nil, Remote{}, err
+	}
+	if remoteURL == "" {
+		return nil, Remote{}, fmt.Errorf("%w: remote.%s.url", errRemoteURLMissing, remote)
+	}
+	r, owner, err := remoteOfURL(remoteURL)
+	if err != nil {
+		return nil, Remote{}, err
+	}
+	refspec, err := r.FetchRefspec()
+	if err != nil {
+		return nil, Remote{}, err
+	}
+
+	if err := b.adoptRefs(ctx, r); err != nil {
+		return nil, Remote{}, fmt.Errorf("could not move references into %s: %w", r.RefNamespace(), err)
+	}
+
+	return b, r, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		cfg.Section("remote").RemoveSubsection(remote)
+		ss := cfg.Section("remote").Subsection(r.Name)
+		ss.SetOption("url", r.FetchURL())
+		ss.SetOption("fetch", refspec)
+		cfg.Section("remotes").AddOption(owner.RemoteGroup(), r.Name)
+
+		biomeSection := cfg.Section(section)
+		if !slices.Contains(biomeSection.OptionAll(ownersOpt), owner.String()) {
+			biomeSection.AddOption(ownersOpt, owner.String())
+		}
+		biomeSection.Subsection(remotesSubsection).AddOption(activeOpt, r.Name)
+		biomeSection.SetOption(versionOpt, v1)
+		return true, nil
+	})
+}
+
+// adoptRefs moves every reference of the repository, other than those
+// already in the remote's reference namespace, into the namespace in one
+// transaction, and points the remote's HEAD at the branch of the
+// repository's HEAD, if the branch exists.
+func (b *biome) adoptRefs(ctx context.Context, r Remote) error {
+	namespace := r.RefNamespace()
+	refs, err := b.listRefs(ctx, []string{"refs/"})
+	if err != nil {
+		return err
+	}
+	adopted := func(refname string) string {
+		return namespace + strings.TrimPrefix(refname, "refs/")
+	}
+
+	// the repository's HEAD names the remote's default branch, ex. for a
+	// mirror of the remote
+	var head string
+	if out, err := exec.CommandContext(ctx, "git", "-C", b.path, "symbolic-ref", "--quiet", "HEAD").Output(); err == nil {
+		head = strings.TrimSpace(string(out))
+	}
+
+	w, err := b.updateRefs(ctx)
+	if err != nil {
+		return err
+	}
+	var headExists bool
+	for _, ref := range refs {
+		if strings.HasPrefix(ref.Name, namespace) {
+			continue
+		}
+		if ref.Name == head {
+			headExists = true
+		}
+		if ref.Symref != "" {
+			_, err = fmt.Fprintf(w, "option no-deref\nsymref-create %s %s\noption no-deref\nsymref-delete %s\n", adopted(ref.Name), adopted(ref.Symref), ref.Name)
+		} else {
+			_, err = fmt.Fprintf(w, "create %s %s\ndelete %s %s\n", adopted(ref.Name), ref.ObjectName, ref.Name, ref.ObjectName)
+		}
+		if err != nil {
+			return fmt.Errorf("could not move %s: %w", ref.Name, err)
+		}
+	}
+	if headExists {
+		if _, err := fmt.Fprintf(w, "option no-deref\nsymref-update %s %s\n", r.Head(), adopted(head)); err != nil {
+			return fmt.Errorf("could not update HEAD ref for %s: %w", r.Name, err)
+		}
+	}
+	return w.Close()
+}
+
+// remoteOfURL identifies the biome remote, and its owner, of a GitHub
+// repository's URL, ex. https://github.com/cli/cli.git,
+// git@github.com:cli/cli.git, or ssh://git@github.com/cli/cli.
+func remoteOfURL(remoteURL string) (Remote, Owner, error) {
+	var host, repoPath string
+	if u, err := url.Parse(remoteURL); err == nil && u.Scheme != "" && u.Host != "" {
+		host, repoPath = u.Hostname(), u.Path
+	} else if userHost, p, ok := strings.Cut(remoteURL, ":"); ok && !strings.Contains(userHost, "/") {
+		// scp-like syntax, ex. git@github.com:cli/cli.git
+		_, host, ok = strings.Cut(userHost, "@")
+		if !ok {
+			host = userHost
+		}
+		repoPath = p
+	}
+	repoPath = strings.TrimSuffix(strings.Trim(repoPath, "/"), ".git")
+	ownerName, repo, ok := strings.Cut(repoPath, "/")
+	if !ok || host == "" || ownerName == "" || repo == "" || strings.Contains(repo, "/") {
+		return Remote{}, Owner{}, fmt.Errorf("%w: %q", errRemoteURLInvalid, remoteURL)
+	}
+	owner, err := ParseOwner(host + "/" + ownerName)
+	if err != nil {
+		return Remote{}, Owner{}, fmt.Errorf("%w: %q", errRemoteURLInvalid, remoteURL)
+	}
+	return Remote{Name: owner.String() + "/" + repo}, owner, nil
+}
@@ -0,0 +1,129 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+// tempMirror creates a mirror clone of a workspace whose origin remote is
+// github.com/orirawlings/bar, and returns the mirror's path and the commit of
+// its main branch.
+func tempMirror(t *testing.T) (string, string) {
+	t.Helper()
+	origin := tempWorkspace(t)
+	testutil.Execute(t, "git", "-C", origin, "tag", "v1.0.0", "main")
+	commit := strings.TrimSpace(testutil.Execute(t, "git", "-C", origin, "rev-parse", "main"))
+	path := filepath.Join(t.TempDir(), "bar.git")
+	testutil.Execute(t, "git", "clone", "--quiet", "--mirror", "file://"+origin, path)
+	testutil.Execute(t, "git", "-C", path, "remote", "set-url", "origin", "https://github.com/orirawlings/bar.git")
+	return path, commit
+}
+
+func TestAdopt(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("mirror", func(t *testing.T) {
+		path, commit := tempMirror(t)
+		stubGitHub(t)
+		b, r, err := Adopt(ctx, path, "origin", biomeOptions()...)
+		testutil.Check(t, err)
+		if r != barRemote {
+			t.Errorf("expected remote %v, was %v", barRemote, r)
+		}
+
+		refs := strings.Fields(testutil.Execute(t, "git", "-C", path, "for-each-ref", "--format=%(refname)"))
+		expectedRefs := []string{
+			barRemote.Head(),
+			"refs/remotes/github.com/orirawlings/bar/heads/main",
+			"refs/remotes/github.com/orirawlings/bar/remotes/origin/main",
+			"refs/remotes/github.com/orirawlings/bar/tags/v1.0.0",
+		}
+		if !slices.Equal(refs, expectedRefs) {
+			t.Errorf("expected refs %q, was %q", expectedRefs, refs)
+		}
+		if moved := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "rev-parse", barRemoteCfg.Head)); moved != commit {
+			t.Errorf("expected %s to be %s, was %s", barRemoteCfg.Head, commit, moved)
+		}
+		if head := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "symbolic-ref", barRemote.Head())); head != barRemoteCfg.Head {
+			t.Errorf("expected %s to refer to %s, was %s", barRemote.Head(), barRemoteCfg.Head, head)
+		}
+
+		assertGitConfig(t, path, "biome.version", v1)
+		assertGitConfig(t, path, "biome.owners", "github.com/orirawlings")
+		assertGitConfig(t, path, "remote.github.com/orirawlings/bar.url", barRemote.FetchURL())
+		assertConfigNotSet(t, path, "remote.origin.url")
+
+		remotes, err := b.Remotes(ctx, Active)
+		testutil.Check(t, err)
+		if !slices.Equal(remotes, []Remote{barRemote}) {
+			t.Errorf("expected remotes %v, was %v", []Remote{barRemote}, remotes)
+		}
+		load(t, ctx, path, true)
+	})
+
+	t.Run("already a biome", func(t *testing.T) {
+		path, _ := tempMirror(t)
+		stubGitHub(t)
+		_, _, err := Adopt(ctx, path, "origin", biomeOptions()...)
+		testutil.Check(t, err)
+		if _, _, err := Adopt(ctx, path, "origin", biomeOptions()...); !errors.Is(err, errAlreadyBiome) {
+			t.Errorf("expected %v, was %v", errAlreadyBiome, err)
+		}
+	})
+
+	t.Run("non-bare repository", func(t *testing.T) {
+		path := tempWorkspace(t)
+		if _, _, err := Adopt(ctx, path, "origin", biomeOptions()...); !errors.Is(err, errAdoptNonBare) {
+			t.Errorf("expected %v, was %v", errAdoptNonBare, err)
+		}
+	})
+
+	t.Run("missing remote", func(t *testing.T) {
+		path, _ := tempMirror(t)
+		if _, _, err := Adopt(ctx, path, "upstream", biomeOptions()...); !errors.Is(err, errRemoteURLMissing) {
+			t.Errorf("expected %v, was %v", errRemoteURLMissing, err)
+		}
+	})
+}
+
+func TestRemoteOfURL(t *testing.T) {
+	for _, tc := range []struct {
+		url      string
+		expected string
+	}{
+		{"https://github.com/cli/cli.git", "github.com/cli/cli"},
+		{"https://github.com/cli/cli", "github.com/cli/cli"},
+		{"https://GitHub.com/cli/cli/", "github.com/cli/cli"},
+		{"ssh://git@github.com/cli/cli.git", "github.com/cli/cli"},
+		{"ssh://git@github.com:22/cli/cli.git", "github.com/cli/cli"},
+		{"git@github.com:cli/cli.git", "github.com/cli/cli"},
+		{"git@my.github.biz:foobar/bazbiz", "my.github.biz/foobar/bazbiz"},
+		{"https://github.com/cli", ""},
+		{"https://github.com/cli/cli/tree/trunk", ""},
+		{"/srv/git/cli.git", ""},
+		{"", ""},
+	} {
+		t.Run(tc.url, func(t *testing.T) {
+			r, owner, err := remoteOfURL(tc.url)
+			if tc.expected == "" {
+				if !errors.Is(err, errRemoteURLInvalid) {
+					t.Errorf("expected %v, was %v", errRemoteURLInvalid, err)
+				}
+				return
+			}
+			testutil.Check(t, err)
+			if r.Name != tc.expected {
+				t.Errorf("expected remote %q, was %q", tc.expected, r.Name)
+			}
+			if !strings.HasPrefix(r.Name, owner.String()+"/") {
+				t.Errorf("expected owner of %q, was %q", r.Name, owner)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package biome
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/auth"
+)
+
+// anonymousPageSize is the number of repositories requested per page when
+// listing an owner's repositories anonymously.
+const anonymousPageSize = 100
+
+// Anonymous reports whether gh has no token for the GitHub host, so the
+// biome reads the host's public repositories without authenticating. GitHub's
+// GraphQL API cannot be used anonymously, so the REST API is used instead,
+// with the following degradations:
+//
+//   - only public repositories are discovered
+//   - upstreams of forks are not discovered
+//   - locked repositories are not recognized
+//   - viewers cannot be added for the host
+//   - GitHub allows far fewer anonymous API requests per hour
+//
+// Remotes are fetched anonymously over HTTPS.
+func Anonymous(host string) bool {
+	token, _ := auth.TokenForHost(host)
+	return token == ""
+}
+
+// anonymousTransport sends requests without credentials.
+type anonymousTransport struct {
+	rt http.RoundTripper
+}
+
+func (t anonymousTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Del("Authorization")
+	return t.rt.RoundTrip(req)
+}
+
+// anonymousRESTClient creates a REST API client for the host that does not
+// authenticate.
+func anonymousRESTClient(host string) (*api.RESTClient, error) {
+	client, err := api.NewRESTClient(api.ClientOptions{
+		Host: host,
+		// a placeholder, so that the client does not look up a token
+		AuthToken: "anonymous",
+		Transport: anonymousTransport{rt: http.DefaultTransport},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create API client: %s: %w", host, err)
+	}
+	return client, nil
+}
+
+// validateOwnerAnonymously ensures that the owner exists, without
+// authenticating.
+func validateOwnerAnonymously(ctx context.Context, owner Owner) error {
+	client, err := anonymousRESTClient(owner.Host())
+	if err != nil {
+		return err
+	}
+	var user struct {
+		Login string
+	}
+	return client.DoWithContext(ctx, "GET", "users/"+url.PathEscape(owner.name), nil, &user)
+}
+
+// restRepository is a repository as listed by GitHub's REST API.
+type restRepository struct {
+	HTMLURL       string    `json:"html_url"`
+	Archived      bool      `json:"archived"`
+	Disabled      bool      `json:"disabled"`
+	DefaultBranch string    `json:"default_branch"`
+	Visibility    string    `json:"visibility"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// repo converts the REST representation of the repository to the
+// representation that providers list.
+func (r restRepository) repo() Repo {
+	return Repo{
+		URL:           r.HTMLURL,
+		Archived:      r.Archived,
+		Disabled:      r.Disabled,
+		DefaultBranch: r.DefaultBranch,
+		Internal:      strings.ToUpper(r.Visibility) == "INTERNAL",
+		CreatedAt:     r.CreatedAt,
+	}
+}
+
+// restProvider lists the public repositories of owners with GitHub's REST
+// API, without authenticating.
+type restProvider struct {
+	budget *apiBudget
+}
+
+func (p restProvider) ListRepositories(ctx context.Context, owner Owner) ([]Repo, error) {
+	client, err := anonymousRESTClient(owner.Host())
+	if err != nil {
+		return nil, err
+	}
+	var repos []Repo
+	for page := 1; ; page++ {
+		if err := p.budget.spend(); err != nil {
+			return repos, err
+		}
+		var listed []restRepository
+		path := fmt.Sprintf("users/%s/repos?type=owner&per_page=%d&page=%d", url.PathEscape(owner.name), anonymousPageSize, page)
+		if err := client.DoWithContext(ctx, "GET", path, nil, &listed); err != nil {
+			return repos, fmt.Errorf("could not list public repos for %s: %w", owner, err)
+		}
+		for _, repo := range listed {
+			repos = append(repos, repo.repo())
+		}
+		if len(listed) < anonymousPageSize {
+			break
+		}
+	}
+	return repos, nil
+}
@@ -0,0 +1,78 @@
+package biome
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// stubAnonymous makes gh appear not to be logged in to any GitHub host.
+func stubAnonymous(t *testing.T) {
+	t.Helper()
+	testutil.StubGHConfig(t, "hosts: {}\n")
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_PATH", "/nonexistent/gh")
+}
+
+func TestAnonymous(t *testing.T) {
+	stubGitHub(t)
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	if Anonymous("github.com") {
+		t.Errorf("expected github.com to be authenticated")
+	}
+	stubAnonymous(t)
+	if !Anonymous("github.com") {
+		t.Errorf("expected github.com to be anonymous")
+	}
+}
+
+func TestBuildRemoteConfigsAnonymously(t *testing.T) {
+	ctx := context.Background()
+	stubGitHub(t)
+	stubAnonymous(t)
+
+	unauthenticated := func(req *http.Request, _ *gock.Request) (bool, error) {
+		return req.Header.Get("Authorization") == "", nil
+	}
+	gock.New("https://api.github.com").
+		Get("/users/cli").
+		AddMatcher(unauthenticated).
+		Reply(200).
+		JSON(`{"login":"cli"}`)
+	gock.New("https://api.github.com").
+		Get("/users/cli/repos").
+		MatchParam("page", "1").
+		AddMatcher(unauthenticated).
+		Reply(200).
+		JSON(`[
+			{"html_url":"https://github.com/cli/go-gh","archived":false,"disabled":false,"default_branch":"trunk","visibility":"public"},
+			{"html_url":"https://github.com/cli/cli","archived":true,"disabled":false,"default_branch":"trunk","visibility":"public"}
+		]`)
+
+	b := &biome{}
+	testutil.Check(t, b.validateOwner(ctx, github_com_cli))
+	remoteCfgs, err := b.buildRemoteConfigs(ctx, github_com_cli, nil, repositoryFields{})
+	testutil.Check(t, err)
+	expected := []remoteConfig{
+		{
+			Remote: Remote{Name: "github.com/cli/cli", Archived: true},
+			Head:   "refs/remotes/github.com/cli/cli/heads/trunk",
+		},
+		{
+			Remote: Remote{Name: "github.com/cli/go-gh"},
+			Head:   "refs/remotes/github.com/cli/go-gh/heads/trunk",
+		},
+	}
+	if !slices.Equal(remoteCfgs, expected) {
+		t.Errorf("unexpected remote configs: wanted %v, was %v", expected, remoteCfgs)
+	}
+
+	// viewers require a token
+	testutil.ExpectError(t, b.validateViewer(ctx, github_com_viewer))
+}
@@ -0,0 +1,87 @@
+package biome
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+)
+
+const (
+	// atticKey is the git config key of the setting that moves the
+	// references of archived and locked remotes into the attic.
+	atticKey = "biome.attic"
+
+	// atticRefPrefix is the reference prefix of the attic, where the
+	// references of archived and locked remotes are kept out of the biome's
+	// reference namespace, ex. refs/remotes/.
+	atticRefPrefix = "refs/attic/"
+
+	// atticRefTemplate is the reference namespace template of remotes in the
+	// attic.
+	atticRefTemplate = atticRefPrefix + "<name>/*"
+)
+
+// atticEnabled reports whether the references of archived and locked remotes
+// are moved into the attic, from a loaded config.
+func atticEnabled(cfg *config.Config) bool {
+	value, _ := getConfigValue(cfg, atticKey)
+	enabled, _ := ParseBool(value)
+	return enabled
+}
+
+// remoteRefTemplate returns the reference namespace template for a remote,
+// given the biome's template. Archived and locked remotes are kept in the
+// attic if it is enabled, since their references no longer change, so that
+// every fetch and every walk of the biome's references has fewer references
+// to consider.
+func remoteRefTemplate(template string, attic bool, r Remote) string {
+	if attic && (r.Archived || r.Locked) {
+		return atticRefTemplate
+	}
+	return template
+}
+
+// configuredRefTemplate returns the reference namespace template that the
+// named git remote is configured with, from a loaded config. This is the
+// attic's template for remotes whose references were moved to the attic, or
+// the biome's template otherwise.
+func configuredRefTemplate(cfg *config.Config, name string) string {
+	if cfg.Section("remote").HasSubsection(name) {
+		namespace, ok := refNamespaceOf(cfg.Section("remote").Subsection(name).Options.Get("fetch"))
+		if ok && strings.HasPrefix(namespace, atticRefPrefix) {
+			return atticRefTemplate
+		}
+	}
+	return refTemplate(cfg)
+}
+
+// restoreFromAttic returns the reference namespaces in the attic that hold
+// references of the given remotes, mapped to the remotes' reference
+// namespaces, so that the references can be moved out of the attic.
+func (b *biome) restoreFromAttic(ctx context.Context, remotes []Remote) (map[string]string, error) {
+	restored := make(map[string]string)
+	if len(remotes) == 0 {
+		return restored, nil
+	}
+	refs, err := b.listRefs(ctx, []string{atticRefPrefix})
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		names = append(names, ref.Name)
+	}
+	slices.Sort(names)
+	for _, r := range remotes {
+		namespace := Remote{Name: r.Name, refTemplate: atticRefTemplate}.RefNamespace()
+		if namespace == r.RefNamespace() {
+			continue
+		}
+		if i, _ := slices.BinarySearch(names, namespace); i < len(names) && strings.HasPrefix(names[i], namespace) {
+			restored[namespace] = r.RefNamespace()
+		}
+	}
+	return restored, nil
+}
@@ -0,0 +1,84 @@
+package biome
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestRemoteRefTemplate(t *testing.T) {
+	template := "refs/biome/<name>/*"
+	for _, tc := range []struct {
+		attic    bool
+		remote   Remote
+		expected string
+	}{
+		{false, Remote{Name: "github.com/cli/cli"}, template},
+		{false, Remote{Name: "github.com/cli/cli", Archived: true}, template},
+		{true, Remote{Name: "github.com/cli/cli"}, template},
+		{true, Remote{Name: "github.com/cli/cli", Archived: true}, atticRefTemplate},
+		{true, Remote{Name: "github.com/cli/cli", Locked: true}, atticRefTemplate},
+	} {
+		if actual := remoteRefTemplate(template, tc.attic, tc.remote); actual != tc.expected {
+			t.Errorf("unexpected template for %+v with attic %t: wanted %q, was %q", tc.remote, tc.attic, tc.expected, actual)
+		}
+	}
+}
+
+func TestBiome_UpdateRemotes_attic(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+	b := initBiome(t, ctx, path, true)
+
+	commitID := createCommitFor(t, ctx, path, []string{
+		archivedRemoteCfg.Head,
+	})
+	addOwners(t, ctx, b, github_com_orirawlings)
+	testutil.Check(t, b.UpdateRemotes(ctx))
+	testutil.ExpectError(t, b.SetSetting(ctx, atticKey, "sometimes"))
+	testutil.Check(t, b.SetSetting(ctx, atticKey, "true"))
+
+	// references of archived remotes are moved into the attic
+	testutil.Check(t, b.UpdateRemotes(ctx))
+	assertGitConfig(t, path, "remote."+archivedRemote.Name+".fetch", "+refs/*:refs/attic/github.com/orirawlings/archived/*")
+	assertGitConfig(t, path, "remote."+barRemote.Name+".fetch", "+refs/*:refs/remotes/github.com/orirawlings/bar/*")
+	expectRefs(t, ctx, path, []string{
+		fmt.Sprintf(`%s commit refs/attic/github.com/orirawlings/archived/HEAD refs/attic/github.com/orirawlings/archived/heads/master`, commitID),
+		fmt.Sprintf(`%s commit refs/attic/github.com/orirawlings/archived/heads/master `, commitID),
+	})
+	remotes, err := b.Remotes(ctx, Archived)
+	testutil.Check(t, err)
+	if len(remotes) != 1 || remotes[0].Head() != "refs/attic/github.com/orirawlings/archived/HEAD" {
+		t.Errorf("expected HEAD of archived remote in the attic, was %v", remotes)
+	}
+
+	// references of locked remotes stay in the attic, without a remote
+	locked := github_com_orirawlings_archived
+	locked.IsLocked = true
+	updateStubbedGitHubRepositories(t, github_com_orirawlings, []repository{
+		github_com_orirawlings_bar,
+		locked,
+	})
+	testutil.Check(t, b.UpdateRemotes(ctx))
+	assertConfigNotSet(t, path, "remote."+archivedRemote.Name+".fetch")
+	expectRefs(t, ctx, path, []string{
+		fmt.Sprintf(`%s commit refs/attic/github.com/orirawlings/archived/HEAD refs/attic/github.com/orirawlings/archived/heads/master`, commitID),
+		fmt.Sprintf(`%s commit refs/attic/github.com/orirawlings/archived/heads/master `, commitID),
+	})
+
+	// references are restored from the attic when the repository is active
+	// again
+	unarchived := github_com_orirawlings_archived
+	unarchived.IsArchived = false
+	updateStubbedGitHubRepositories(t, github_com_orirawlings, []repository{
+		github_com_orirawlings_bar,
+		unarchived,
+	})
+	testutil.Check(t, b.UpdateRemotes(ctx))
+	expectRefs(t, ctx, path, []string{
+		fmt.Sprintf(`%s commit refs/remotes/github.com/orirawlings/archived/HEAD refs/remotes/github.com/orirawlings/archived/heads/master`, commitID),
+		fmt.Sprintf(`%s commit refs/remotes/github.com/orirawlings/archived/heads/master `, commitID),
+	})
+}
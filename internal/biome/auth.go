@@ -0,0 +1,271 @@
+package biome
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/auth"
+	graphql "github.com/cli/shurcooL-graphql"
+)
+
+var (
+	// errNotLoggedIn indicates that gh has no token stored for a GitHub host.
+	errNotLoggedIn = errors.New("not logged in")
+)
+
+// AuthReport describes whether the token stored for a GitHub host can read
+// the repositories of the biome's owners and viewers on that host.
+type AuthReport struct {
+
+	// Host is the GitHub server name.
+	Host string
+
+	// Login is the user that the token authenticates as, or empty if the
+	// token could not be used.
+	Login string
+
+	// Scopes lists the OAuth scopes granted to the token, sorted, or nil if
+	// the token does not report its scopes, ex. fine-grained personal access
+	// tokens and GitHub App tokens.
+	Scopes []string
+
+	// Warnings describe missing permissions that may hide some of the
+	// repositories from the biome, without failing outright.
+	Warnings []string
+
+	// Errors describe owners or viewers whose repositories cannot be read.
+	Errors []error
+}
+
+// OK reports whether the token can read the repositories of all the biome's
+// owners and viewers on the host.
+func (r AuthReport) OK() bool {
+	return len(r.Errors) == 0
+}
+
+// CheckAuth verifies, for each GitHub host of the biome, that the token
+// stored by gh can list the biome's owners and read their repositories, so
+// that problems surface before a long operation fails halfway through.
+// Reports are sorted by host.
+func (b *biome) CheckAuth(ctx context.Context) ([]AuthReport, error) {
+	owners, err := b.Owners(ctx)
+	if err != nil {
+		return nil, err
+	}
+	viewers, err := b.Viewers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hosts, err := b.Hosts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var reports []AuthReport
+	for _, host := range hosts {
+		report := AuthReport{Host: host}
+		if Anonymous(host) {
+			report.Warnings = append(report.Warnings, "not logged in, so only public repositories can be read, with a lower API rate limit")
+			for _, owner := range owners {
+				if owner.Host() != host {
+					continue
+				}
+				if err := validateOwnerAnonymously(ctx, owner); err != nil {
+					report.Errors = append(report.Errors, fmt.Errorf("could not read repositories of %s: %w", owner, err))
+				}
+			}
+			for _, viewer := range viewers {
+				if viewer.Host() == host {
+					report.Errors = append(report.Errors, fmt.Errorf("%w to %s, run 'gh auth login --hostname %s' to add repositories accessible to the authenticated user", errNotLoggedIn, host, host))
+				}
+			}
+			reports = append(reports, report)
+			continue
+		}
+		report.Login, report.Scopes, err = currentUser(ctx, host)
+		if err != nil {
+			report.Errors = append(report.Errors, err)
+			reports = append(reports, report)
+			continue
+		}
+
+		var needsOrgScope bool
+		for _, owner := range owners {
+			if owner.Host() != host {
+				continue
+			}
+			isOrg, err := checkOwnerAccess(ctx, owner)
+			if err != nil {
+				report.Errors = append(report.Errors, fmt.Errorf("could not read repositories of %s: %w", owner, err))
+			}
+			needsOrgScope = needsOrgScope || isOrg
+		}
+		for _, viewer := range viewers {
+			if viewer.Host() == host && slices.Contains(viewer.Affiliations(), OrganizationMemberAffiliation) {
+				needsOrgScope = true
+			}
+		}
+
+		if report.Scopes != nil {
+			if !slices.Contains(report.Scopes, "repo") {
+				report.Warnings = append(report.Warnings, "token is missing the repo scope, so private repositories cannot be read")
+			}
+			if needsOrgScope && !slices.ContainsFunc(report.Scopes, func(s string) bool {
+				return s == "read:org" || s == "write:org" || s == "admin:org"
+			}) {
+				report.Warnings = append(report.Warnings, "token is missing the read:org scope, so repositories of organizations may be missing")
+			}
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// currentUser returns the login of the user that the host's token
+// authenticates as, and the OAuth scopes that the token was granted, sorted,
+// or nil if the token does not report its scopes.
+func currentUser(ctx context.Context, host string) (string, []string, error) {
+	client, err := api.NewRESTClient(api.ClientOptions{
+		Host: host,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("could not create API client: %s: %w", host, err)
+	}
+	resp, err := client.RequestWithContext(ctx, "GET", "user", nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("could not authenticate to %s: %w", host, err)
+	}
+	defer resp.Body.Close()
+	var user struct {
+		Login string
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return "", nil, fmt.Errorf("could not decode authenticated user: %s: %w", host, err)
+	}
+	var scopes []string
+	if header, ok := resp.Header["X-Oauth-Scopes"]; ok {
+		scopes = []string{}
+		for _, scope := range strings.Split(strings.Join(header, ","), ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				scopes = append(scopes, scope)
+			}
+		}
+		slices.Sort(scopes)
+	}
+	return user.Login, scopes, nil
+}
+
+// checkOwnerAccess ensures that the owner's repositories can be listed,
+// reporting whether the owner is an organization.
+func checkOwnerAccess(ctx context.Context, owner Owner) (bool, error) {
+	client, err := api.NewGraphQLClient(api.ClientOptions{
+		Host: owner.Host(),
+	})
+	if err != nil {
+		return false, fmt.Errorf("could not create API client: %s: %w", owner.Host(), err)
+	}
+	var query struct {
+		RepositoryOwner *struct {
+			Typename     string `graphql:"__typename"`
+			Repositories struct {
+				TotalCount int
+			}
+		} `graphql:"repositoryOwner(login: $owner)"`
+	}
+	variables := map[string]interface{}{
+		"owner": graphql.String(owner.name),
+	}
+	if err := client.QueryWithContext(ctx, "OwnerAccess", &query, variables); err != nil {
+		return false, err
+	}
+	if query.RepositoryOwner == nil {
+		return false, errors.New("owner not found")
+	}
+	return query.RepositoryOwner.Typename == "Organization", nil
+}
+
+// Identity describes the account that the biome authenticates as on a
+// GitHub host, and how.
+type Identity struct {
+
+	// Host is the GitHub server name.
+	Host string
+
+	// Login is the user that the token authenticates as, or empty if it is
+	// unknown, ex. for GitHub App installation tokens.
+	Login string
+
+	// Source describes where the token was found, ex. the gh config file or
+	// an environment variable. Source is empty if there is no token for the
+	// host.
+	Source string
+
+	// Kind describes the type of token, ex. a GitHub App installation token.
+	Kind string
+
+	// Err is set if the token could not be used to identify the user.
+	Err error
+}
+
+// Whoami identifies, for each GitHub host of the biome, the account that the
+// biome authenticates as and how the token was found, sorted by host. Hosts
+// that gh is not logged in to are included with an empty Source.
+func (b *biome) Whoami(ctx context.Context) ([]Identity, error) {
+	hosts, err := b.Hosts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result []Identity
+	for _, host := range hosts {
+		id := Identity{Host: host}
+		token, source := auth.TokenForHost(host)
+		if token == "" {
+			result = append(result, id)
+			continue
+		}
+		id.Source, id.Kind = tokenSource(source), tokenKind(token)
+		// GitHub App installation tokens do not act on behalf of a user
+		if !strings.HasPrefix(token, "ghs_") {
+			id.Login, _, id.Err = currentUser(ctx, host)
+		}
+		result = append(result, id)
+	}
+	return result, nil
+}
+
+// tokenSource describes a token source reported by gh's auth package.
+func tokenSource(source string) string {
+	switch source {
+	case "oauth_token":
+		return "gh config file"
+	case "gh":
+		return "gh secure storage"
+	default:
+		return source + " environment variable"
+	}
+}
+
+// tokenKinds maps the prefixes of GitHub tokens to the type of token.
+//
+// https://github.blog/engineering/platform-security/behind-githubs-new-authentication-token-formats/
+var tokenKinds = [][2]string{
+	{"github_pat_", "fine-grained personal access token"},
+	{"ghp_", "personal access token"},
+	{"gho_", "OAuth token"},
+	{"ghu_", "GitHub App user token"},
+	{"ghs_", "GitHub App installation token"},
+}
+
+// tokenKind describes the type of a token from its prefix.
+func tokenKind(token string) string {
+	for _, k := range tokenKinds {
+		if strings.HasPrefix(token, k[0]) {
+			return k[1]
+		}
+	}
+	return "token"
+}
@@ -0,0 +1,159 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestBiome_CheckAuth(t *testing.T) {
+	ctx := context.Background()
+	stubGitHub(t)
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_ENTERPRISE_TOKEN", "")
+	t.Setenv("GITHUB_ENTERPRISE_TOKEN", "")
+
+	gock.New("https://api.github.com").
+		Get("/user").
+		Reply(200).
+		SetHeader("X-OAuth-Scopes", "repo, gist").
+		JSON(`{"login":"user1"}`)
+	gock.New("https://my.github.biz").
+		Get("/api/v3/user").
+		Reply(200).
+		JSON(`{"login":"bizuser1"}`)
+	stubOwnerAccess := func(owner Owner, response string) {
+		host := owner.Host()
+		if host == "github.com" {
+			host = "api.github.com"
+		}
+		gock.New(fmt.Sprintf("https://%s", host)).
+			Post("/graphql").
+			BodyString(fmt.Sprintf(`{"query":"query OwnerAccess($owner:String!){repositoryOwner(login: $owner){__typename,repositories{totalCount}}}","variables":{"owner":%q}}`, owner.Name())).
+			Reply(200).
+			JSON(response)
+	}
+	stubOwnerAccess(github_com_cli, `{"data":{"repositoryOwner":{"__typename":"Organization","repositories":{"totalCount":2}}}}`)
+	stubOwnerAccess(github_com_orirawlings, `{"data":{"repositoryOwner":null}}`)
+
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		cfg.Section(section).AddOption(ownersOpt, github_com_cli.String())
+		cfg.Section(section).AddOption(ownersOpt, github_com_orirawlings.String())
+		cfg.Section(section).Subsection(viewerSubsectionPrefix+my_github_biz_viewer.Host()).AddOption(affiliationOpt, string(CollaboratorAffiliation))
+		return true, nil
+	}))
+
+	reports, err := b.CheckAuth(ctx)
+	testutil.Check(t, err)
+	if len(reports) != 2 {
+		t.Fatalf("expected a report for each host, was %v", reports)
+	}
+
+	github := reports[0]
+	if github.Host != "github.com" || github.Login != "user1" {
+		t.Errorf("unexpected report for github.com: %v", github)
+	}
+	if expected := []string{"gist", "repo"}; !slices.Equal(github.Scopes, expected) {
+		t.Errorf("unexpected scopes: wanted %v, was %v", expected, github.Scopes)
+	}
+	if len(github.Warnings) != 1 {
+		t.Errorf("expected a warning about the missing read:org scope, was %v", github.Warnings)
+	}
+	if github.OK() || len(github.Errors) != 1 {
+		t.Errorf("expected an error for the missing owner, was %v", github.Errors)
+	}
+
+	biz := reports[1]
+	if biz.Host != "my.github.biz" || biz.Login != "bizuser1" || !biz.OK() {
+		t.Errorf("unexpected report for my.github.biz: %v", biz)
+	}
+	if biz.Scopes != nil || len(biz.Warnings) != 0 {
+		t.Errorf("expected no scopes to be checked for my.github.biz: %v", biz)
+	}
+}
+
+func TestBiome_CheckAuth_notLoggedIn(t *testing.T) {
+	ctx := context.Background()
+	stubGitHub(t)
+	stubAnonymous(t)
+
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		cfg.Section(section).AddOption(ownersOpt, github_com_cli.String())
+		return true, nil
+	}))
+	gock.New("https://api.github.com").
+		Get("/users/cli").
+		Persist().
+		Reply(200).
+		JSON(`{"login":"cli"}`)
+
+	// public owners can be read anonymously
+	reports, err := b.CheckAuth(ctx)
+	testutil.Check(t, err)
+	if len(reports) != 1 || !reports[0].OK() || len(reports[0].Warnings) != 1 {
+		t.Errorf("expected github.com to be read anonymously, was %v", reports)
+	}
+
+	// viewers cannot be read anonymously
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		cfg.Section(section).Subsection(viewerSubsectionPrefix+github_com_viewer.Host()).AddOption(affiliationOpt, string(OwnerAffiliation))
+		return true, nil
+	}))
+	reports, err = b.CheckAuth(ctx)
+	testutil.Check(t, err)
+	if len(reports) != 1 || reports[0].OK() || !errors.Is(reports[0].Errors[0], errNotLoggedIn) {
+		t.Errorf("expected github.com to not be logged in, was %v", reports)
+	}
+}
+
+func TestBiome_Whoami(t *testing.T) {
+	ctx := context.Background()
+	stubGitHub(t)
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_ENTERPRISE_TOKEN", "ghs_def456")
+	t.Setenv("GITHUB_ENTERPRISE_TOKEN", "")
+
+	gock.New("https://api.github.com").
+		Get("/user").
+		Reply(200).
+		JSON(`{"login":"user1"}`)
+
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		cfg.Section(section).AddOption(ownersOpt, github_com_cli.String())
+		cfg.Section(section).AddOption(ownersOpt, my_github_biz_foobar.String())
+		return true, nil
+	}))
+
+	ids, err := b.Whoami(ctx)
+	testutil.Check(t, err)
+	expected := []Identity{
+		{Host: "github.com", Login: "user1", Source: "gh config file", Kind: "token"},
+		{Host: "my.github.biz", Source: "GH_ENTERPRISE_TOKEN environment variable", Kind: "GitHub App installation token"},
+	}
+	if !slices.Equal(ids, expected) {
+		t.Errorf("unexpected identities: wanted %v, was %v", expected, ids)
+	}
+}
@@ -2,16 +2,22 @@ package biome
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"os"
 	"os/exec"
-	"path"
+	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/orirawlings/gh-biome/internal/config"
+	"github.com/orirawlings/gh-biome/internal/util/retry"
 	slicesutil "github.com/orirawlings/gh-biome/internal/util/slices"
 
 	"github.com/cli/go-gh/v2/pkg/api"
@@ -70,6 +76,46 @@ const (
 	// unsupportedOpt is a git config option key which lists GitHub remote
 	// repositories that are not currently supported by biome.
 	unsupportedOpt = string(Unsupported)
+
+	// internalOpt is a git config option key which lists GitHub remote
+	// repositories that have internal visibility, meaning they are visible
+	// to all members of the owning enterprise. This is orthogonal to the
+	// remote categories.
+	internalOpt = "internal"
+
+	// upstreamOpt is a git config option key which lists GitHub remote
+	// repositories that are forks, along with the repository they were forked
+	// from, as `<fork> <upstream>` pairs of remote names. This is orthogonal
+	// to the remote categories.
+	upstreamOpt = "upstream"
+
+	// pendingHeadOpt is a git config option key which lists GitHub remote
+	// repositories whose default branch has not been fetched yet, along with
+	// the reference of the default branch, as `<remote> <reference>` pairs.
+	// Their HEAD references are set once the default branch is fetched, see
+	// [biome.ResolveHeads]. This is orthogonal to the remote categories.
+	pendingHeadOpt = "pendingHead"
+
+	// ownerSubsectionPrefix prefixes git config subsections that store
+	// per-owner settings, ex. `biome.owner.github.com/cli`.
+	ownerSubsectionPrefix = "owner."
+
+	// remoteSubsectionPrefix prefixes git config subsections that store
+	// per-remote settings, ex. `biome.remote.github.com/cli/cli`.
+	remoteSubsectionPrefix = "remote."
+
+	// viewerSubsectionPrefix prefixes git config subsections that store
+	// settings for the authenticated user of a GitHub server whose accessible
+	// repositories have been added to the biome, ex. `biome.viewer.github.com`.
+	viewerSubsectionPrefix = "viewer."
+
+	// hostSubsectionPrefix prefixes git config subsections that store
+	// per-host settings, ex. `biome.host.github.com`.
+	hostSubsectionPrefix = "host."
+
+	// affiliationOpt is a git config option key within a viewer subsection
+	// that lists the repository affiliations to aggregate for the viewer.
+	affiliationOpt = "affiliation"
 )
 
 var (
@@ -105,6 +151,49 @@ type Biome interface {
 	// biome.
 	Owners(context.Context) ([]Owner, error)
 
+	// Absorb imports the owners, viewers, remotes, references, and objects
+	// of the biome at the given path, keeping any that are already in the
+	// biome. If shared is true, the other biome's objects are shared through
+	// git alternates rather than copied.
+	Absorb(ctx context.Context, path string, shared bool) (Absorption, error)
+
+	// RenameOwner replaces an owner of the biome with another, typically
+	// after the GitHub user or organization was renamed. Remote
+	// configurations and references of the owner's repositories are moved to
+	// the new owner name without fetching them again.
+	RenameOwner(ctx context.Context, from, to Owner) error
+
+	// AddViewers records that the repositories accessible to the given
+	// authenticated users have joined the git biome. If a viewer for the same
+	// GitHub server was previously added, its affiliations are replaced.
+	AddViewers(context.Context, []Viewer) error
+
+	// RemoveViewers removes the authenticated users of the given viewers'
+	// GitHub servers from the records on the git biome. Remotes that were
+	// only accessible through a removed viewer will be removed in the next
+	// [UpdateRemotes] invocation.
+	RemoveViewers(context.Context, []Viewer) error
+
+	// Viewers lists the authenticated users whose accessible repositories are
+	// currently within the biome.
+	Viewers(context.Context) ([]Viewer, error)
+
+	// Hosts lists the GitHub servers of the biome's owners and viewers.
+	Hosts(context.Context) ([]string, error)
+
+	// CheckAuth verifies, for each GitHub host of the biome, that the token
+	// stored by gh can list the biome's owners and read their repositories.
+	CheckAuth(context.Context) ([]AuthReport, error)
+
+	// Whoami identifies, for each GitHub host of the biome, the account that
+	// the biome authenticates as and how the token was found.
+	Whoami(context.Context) ([]Identity, error)
+
+	// PlanRemoveOwners determines which remotes and references would be
+	// removed if the given owners and viewers were removed from the biome and
+	// remotes were updated, without changing anything.
+	PlanRemoveOwners(context.Context, []Owner, []Viewer) (RemovalPlan, error)
+
 	// Remotes returns all remotes currently discovered by the biome. Only
 	// discovered remotes that are categorized into at least one of the given
 	// categories will be returned. Not all remote categories are eligible to
@@ -112,16 +201,310 @@ type Biome interface {
 	// that cannot be fetched or updated on Github.
 	Remotes(context.Context, ...RemoteCategory) ([]Remote, error)
 
+	// UnresolvedHeads returns the given remotes whose HEAD reference is
+	// missing, or points to a default branch that does not exist.
+	UnresolvedHeads(ctx context.Context, remotes []Remote) ([]Remote, error)
+
+	// ResolveHeads sets the HEAD reference of each remote whose default
+	// branch was not fetched yet when remotes were updated, and has been
+	// fetched since. The names of the remotes whose HEAD reference was set
+	// are returned.
+	ResolveHeads(ctx context.Context) ([]string, error)
+
+	// SynthesizeHeads points the HEAD reference of each remote without a
+	// default branch at its most likely default branch, if the
+	// biome.heads.synthesize setting is enabled. The remotes whose HEAD
+	// reference was synthesized are returned.
+	SynthesizeHeads(ctx context.Context) ([]Remote, error)
+
+	// RemoteGroupMembers lists the names of the git remotes in the given
+	// git remote groups, ex. an [Owner.RemoteGroup]. Remotes that are members
+	// of more than one of the groups are listed once.
+	RemoteGroupMembers(ctx context.Context, groups ...string) ([]string, error)
+
+	// RemoteGroups returns the biome's git remote groups, each with the owner
+	// or viewer whose remotes are its members.
+	RemoteGroups(ctx context.Context) ([]RemoteGroup, error)
+
+	// Health scores the biome's health, checking for remotes that failed to
+	// fetch, HEAD references that do not resolve, stale fetches, overdue
+	// maintenance, and a fragmented object database.
+	Health(context.Context) (HealthReport, error)
+
+	// Orphans finds git remotes named like the biome's remotes, and
+	// references in their namespaces, that are not recorded in any remote
+	// category, ex. left over by a crash or by manual edits of the git config.
+	Orphans(context.Context) ([]Orphan, error)
+
+	// RemoveOrphans removes the git remote configurations and references of
+	// the given orphaned remotes.
+	RemoveOrphans(ctx context.Context, orphans []Orphan) error
+
+	// MergeBase returns the best common ancestor of the default branches of
+	// the two named remotes, or an empty string if they share no history.
+	MergeBase(ctx context.Context, remote, other string) (string, error)
+
+	// ForkMergeBases returns the best common ancestor of the named remote's
+	// default branch with the default branch of every other remote that
+	// shares history with it, such as its forks.
+	ForkMergeBases(ctx context.Context, remote string) ([]MergeBase, error)
+
+	// Tree lists the entries of the tree at the named remote's HEAD, or at
+	// the given reference of the remote, optionally limited to a path and
+	// recursing into subdirectories.
+	Tree(ctx context.Context, remote, ref, path string, recursive bool) ([]TreeEntry, error)
+
+	// Cat writes the content of the file at the given path in the tree at
+	// the named remote's HEAD, or at the given reference of the remote.
+	Cat(ctx context.Context, w io.Writer, remote, ref, path string) error
+
+	// VerifySignatures counts the commits at the HEAD of each fetched remote
+	// in the given categories, or committed since the given git date, by the
+	// status of their signatures.
+	VerifySignatures(ctx context.Context, since string, categories ...RemoteCategory) ([]SignatureReport, error)
+
+	// Contributors returns the distinct authors, or committers, of the
+	// commits of the default branch of each fetched remote in the given
+	// categories, committed since the given git date.
+	Contributors(ctx context.Context, since string, committers bool, categories ...RemoteCategory) ([]Contributor, error)
+
+	// SyncDatabase materializes the biome's owners, remotes, references,
+	// remote HEADs, and fetch history into a SQLite database inside the
+	// biome, returning the database's path.
+	SyncDatabase(context.Context) (string, error)
+
+	// Query executes read-only SQL against the metadata database, returning
+	// the rows selected by the query's last statement.
+	Query(ctx context.Context, query string) (QueryResult, error)
+
+	// CreateSnapshot records the current object name of every reference of
+	// the biome's remotes as a new, named snapshot.
+	CreateSnapshot(ctx context.Context, name string) (Snapshot, error)
+
+	// CreateSnapshotAt records a new, named snapshot in which the default
+	// branch of each of the named remotes is at the given commit.
+	CreateSnapshotAt(ctx context.Context, name string, commits map[string]string) (Snapshot, error)
+
+	// Snapshots returns the biome's snapshots, sorted by name.
+	Snapshots(context.Context) ([]Snapshot, error)
+
+	// DeleteSnapshot deletes the named snapshot.
+	DeleteSnapshot(ctx context.Context, name string) error
+
+	// DiffSnapshots reports how the references and HEAD of each remote
+	// changed between two snapshots.
+	DiffSnapshots(ctx context.Context, from, to string) ([]SnapshotDiff, error)
+
+	// RestoreSnapshot resets the references of the biome's remotes to the
+	// values recorded by the named snapshot.
+	RestoreSnapshot(ctx context.Context, name string) (int, error)
+
+	// ForkDivergence reports how far the default branch of each fetched fork
+	// has diverged from the default branch of its upstream remote.
+	ForkDivergence(context.Context) ([]Divergence, error)
+
+	// CheckUpdates compares the default branch of each fetchable remote's
+	// GitHub repository with the remote's HEAD reference, without fetching,
+	// so that remotes which are behind GitHub can be found. If owners are
+	// given, only their remotes are checked.
+	CheckUpdates(ctx context.Context, owners ...Owner) ([]UpdateCheck, error)
+
+	// DiskUsage attributes the biome's object storage to each of its owners,
+	// largest first.
+	DiskUsage(context.Context) ([]OwnerDiskUsage, error)
+
+	// PlanPruneObjects estimates how much disk would be reclaimed by pruning
+	// unreachable objects older than expire, without changing anything.
+	PlanPruneObjects(ctx context.Context, expire string) (PrunePlan, error)
+
+	// PruneObjects prunes unreachable objects older than expire, and expires
+	// reflog and journal entries according to the biome's retention
+	// settings, returning how much disk was reclaimed.
+	PruneObjects(ctx context.Context, expire string) (int64, error)
+
+	// Deduplication estimates how much storage the biome saves by sharing
+	// objects between its remotes, compared to independent clones.
+	Deduplication(context.Context) (Deduplication, error)
+
+	// Graph returns the topology of the biome, relating each owner to its
+	// remotes and each fork to its upstream remote, optionally measuring the
+	// disk usage of each remote.
+	Graph(ctx context.Context, sizes bool) (Graph, error)
+
+	// Evict exports the references of each of the named remotes to a git
+	// bundle in dir, or in the biome's git directory if dir is empty, and
+	// removes the remotes' references, so the remotes are no longer fetched.
+	Evict(ctx context.Context, dir string, remotes ...string) ([]Eviction, error)
+
+	// Restore imports the references of each of the named evicted remotes
+	// from their git bundles, so they are fetched again.
+	Restore(ctx context.Context, remotes ...string) ([]Eviction, error)
+
+	// LFSRemotes lists the fetchable remotes whose Git LFS objects are
+	// fetched under the [LFSSelected] policy.
+	LFSRemotes(context.Context) ([]Remote, error)
+
+	// Record appends the given entries to the biome's journal.
+	Record(context.Context, ...JournalEntry) error
+
+	// Journal returns the entries of the biome's journal, oldest first.
+	Journal(context.Context) ([]JournalEntry, error)
+
+	// DependencyManifests returns the dependency manifests with the given
+	// file names, ex. go.mod, at the HEAD of each of the biome's fetchable
+	// remotes, or only of the remotes of the given owners.
+	DependencyManifests(ctx context.Context, names []string, owners ...Owner) ([]DependencyManifest, error)
+
+	// BranchTips returns the object IDs that the branches of the biome's
+	// fetchable remotes point to, keyed by reference name.
+	BranchTips(context.Context) (map[string]string, error)
+
+	// ForcedUpdates returns the branches of the biome's fetchable remotes
+	// that were updated without fast-forwarding since the given tips were
+	// taken with BranchTips.
+	ForcedUpdates(ctx context.Context, before map[string]string) ([]ForcedUpdate, error)
+
+	// WebURL returns the URL of a page of the named remote's repository on
+	// its GitHub server: its home page, the page of the commit that the
+	// given reference resolves to, or the page of a path in that commit.
+	WebURL(ctx context.Context, remote, ref, path string) (string, error)
+
+	// RefWebURL returns the URL of the page of the commit, or of a path in
+	// the commit, that a full reference name in a remote's namespace, or a
+	// commit's object ID, resolves to, on the GitHub server of the remote it
+	// belongs to.
+	RefWebURL(ctx context.Context, ref, path string) (string, error)
+
+	// CommitRemotes returns the biome's fetchable remotes whose references
+	// reach the given commit, sorted by name.
+	CommitRemotes(ctx context.Context, commit string) ([]CommitRemote, error)
+
+	// Releases returns the latest tag of each of the biome's fetchable
+	// remotes, or only of the remotes of the given owners, whose name
+	// matches the glob pattern, if given, with the signature of the tag, or
+	// of its commit.
+	Releases(ctx context.Context, pattern string, owners ...Owner) ([]Release, error)
+
+	// Annotate attaches a freeform note to the named remote, replacing any
+	// previous note. An empty note removes the remote's note.
+	Annotate(ctx context.Context, remote, note string) error
+
+	// RemoteNotes returns the notes attached to remotes, keyed by remote
+	// name.
+	RemoteNotes(context.Context) (map[string]string, error)
+
+	// ProtectedRefs returns the object IDs that the references of the
+	// biome's fetchable remotes matching the biome.fetch.protectedRefs
+	// setting point to, keyed by reference name.
+	ProtectedRefs(context.Context) (map[string]string, error)
+
+	// RestoreProtectedRefs recreates the protected references, taken with
+	// ProtectedRefs, that were deleted since, ex. by a pruning fetch.
+	RestoreProtectedRefs(ctx context.Context, before map[string]string) ([]ProtectedRef, error)
+
+	// Fsck checks the connectivity of the biome's objects, and records the
+	// result in the biome's journal.
+	Fsck(context.Context) (JournalEntry, error)
+
+	// FsckSchedule returns how often the biome's objects are checked by
+	// scheduled integrity checks, or 0 if they are not scheduled.
+	FsckSchedule(context.Context) (time.Duration, error)
+
+	// RefFormat returns the reference backend of the biome's repository.
+	// Fetches serialize their reference updates with the reftable backend.
+	RefFormat(context.Context) (RefFormat, error)
+
+	// Manifest returns a project pinned to the commit at its HEAD for each
+	// of the biome's fetchable remotes, or only for the remotes of the
+	// given owners.
+	Manifest(ctx context.Context, owners ...Owner) ([]ManifestProject, error)
+
+	// NewQuarantine creates an empty quarantine object directory in the
+	// biome, that fetches receive objects into, so that the objects can be
+	// validated before they are migrated into the biome.
+	NewQuarantine(context.Context) (*Quarantine, error)
+
+	// RefCounts returns the number of references of each fetchable remote,
+	// keyed by the remote's name.
+	RefCounts(context.Context) (map[string]int, error)
+
+	// ScanBaseline returns the object IDs that the references of the
+	// biome's remotes point to, to scan only the blobs fetched afterwards.
+	ScanBaseline(context.Context) ([]string, error)
+
+	// Scan finds possible secrets in the blobs that the references of the
+	// biome's fetchable remotes reach, but that the baseline does not.
+	Scan(ctx context.Context, baseline []string) ([]Finding, error)
+
+	// Share creates a temporary bare repository that exposes only the
+	// references of the named remote, to bundle or serve to others.
+	Share(ctx context.Context, remote string) (*Share, error)
+
+	// GetSetting returns the effective value of the biome setting with the
+	// given git config key.
+	GetSetting(ctx context.Context, key string) (SettingValue, error)
+
+	// SetSetting validates and stores a value for the biome setting with the
+	// given git config key.
+	SetSetting(ctx context.Context, key, value string) error
+
+	// UnsetSetting removes the biome setting with the given git config key,
+	// so the setting's default is in effect.
+	UnsetSetting(ctx context.Context, key string) error
+
+	// ListSettings returns the effective values of all biome settings,
+	// including per-owner settings that have been set.
+	ListSettings(context.Context) ([]SettingValue, error)
+
+	// GetOwnerSetting returns the effective value of the per-owner setting
+	// option for the given owner.
+	GetOwnerSetting(ctx context.Context, owner Owner, option string) (SettingValue, error)
+
+	// SetOwnerSetting validates and stores a value for the per-owner setting
+	// option for the given owner.
+	SetOwnerSetting(ctx context.Context, owner Owner, option, value string) error
+
+	// UnsetOwnerSetting removes the per-owner setting option for the given
+	// owner, so the setting's default is in effect.
+	UnsetOwnerSetting(ctx context.Context, owner Owner, option string) error
+
 	// UpdateRemotes syncs the git remote configurations. All repositories
-	// owned by the biome's owners will be configured as remotes. Any other
-	// remotes will be dropped. HEAD references for each remote will be updated
-	// as well.
-	UpdateRemotes(context.Context) error
+	// owned by the biome's owners, or accessible to the biome's viewers, will
+	// be configured as remotes. Any other remotes will be dropped. HEAD
+	// references for each remote will be updated as well. If owners are
+	// given, only their remotes are discovered, and all other owners and
+	// viewers keep their previously configured remotes. Paused owners keep
+	// their previously configured remotes as well.
+	UpdateRemotes(ctx context.Context, owners ...Owner) error
 }
 
 type biome struct {
 	path          string
 	editorOptions []config.EditorOption
+
+	// fetchParallel is the fetch.parallel setting to record when the biome
+	// is initialized, or nil to leave it unset.
+	fetchParallel *int
+
+	// lfsPolicy is the biome.lfs.policy setting to record when the biome is
+	// initialized, or empty to leave it unset.
+	lfsPolicy LFSPolicy
+
+	// maintenance is how the biome is registered with git maintenance when it
+	// is initialized, or empty to leave it unregistered.
+	maintenance MaintenanceMode
+
+	// objectFormat is the object format of the biome's repository when it is
+	// initialized, or empty to use git's default.
+	objectFormat ObjectFormat
+
+	// refFormat is the reference backend of the biome's repository when it
+	// is initialized, or empty to use git's default.
+	refFormat RefFormat
+
+	// providers are the providers registered with [UseProvider], keyed by
+	// host.
+	providers map[string]Provider
 }
 
 // Path returns the filesystem path to the biome's git repository.
@@ -130,6 +513,10 @@ func (b *biome) Path() string {
 }
 
 // Init initializes a new git biome at the given filesystem directory path.
+// A new bare repository is created, unless the path is an existing non-bare
+// repository, either its working tree or its git directory, which then
+// becomes the biome. The repository's other remotes, branches, and working
+// tree are left alone.
 func Init(ctx context.Context, path string, opts ...BiomeOption) (Biome, error) {
 	b := &biome{
 		path: path,
@@ -138,18 +525,46 @@ func Init(ctx context.Context, path string, opts ...BiomeOption) (Biome, error)
 		opt(b)
 	}
 
-	// TODO (orirawlings): Explore using reftable and fail gracefully if reftable is not available
-	// in the user's version of git. reftable would likely be much faster for bulk and concurrent
-	// reads of references, but it does not support concurrent writes. `git fetch --multiple` and
-	// `git fetch --all` perform potentially concurrent writes and does not appear to busy-spin
-	// with backoff when making ref updates. This is a blocker for parallel fetching for biomes.
+	// reftable is much faster for bulk reads of references, but it does not
+	// support concurrent writes. `git fetch --multiple` and `git fetch --all`
+	// perform potentially concurrent writes and do not appear to busy-spin
+	// with backoff when making ref updates, so biome fetches reftable biomes
+	// with a pool of processes that only receive objects, and applies their
+	// reference updates one transaction at a time. Since that is slower for
+	// biomes that fetch few remotes, reftable is only used when chosen
+	// explicitly.
 	//
 	// See https://git-scm.com/docs/reftable#_update_transactions
-	//
-	// cmd := exec.CommandContext(ctx, "git", "init", "--bare", "--ref-format=reftable", b.path)
-	cmd := exec.CommandContext(ctx, "git", "init", "--bare", b.path)
-	if out, err := cmd.CombinedOutput(); err != nil {
-		return nil, fmt.Errorf("could not %q: %w\n%s", cmd, err, out)
+	nonBare := b.nonBare(ctx)
+	if nonBare {
+		if b.objectFormat != "" || b.refFormat != "" {
+			return nil, errFormatOfExisting
+		}
+		// biome only writes references under the namespaces of its remotes,
+		// and keeps the repository's other remotes, so the working tree,
+		// branches, and tags are left alone
+		if err := b.checkNotShallow(ctx); err != nil {
+			return nil, err
+		}
+	}
+	args := []string{"init", "--bare"}
+	if b.objectFormat != "" {
+		if err := b.objectFormat.checkSupported(ctx); err != nil {
+			return nil, err
+		}
+		args = append(args, "--object-format="+string(b.objectFormat))
+	}
+	if b.refFormat != "" {
+		if err := b.refFormat.checkSupported(ctx); err != nil {
+			return nil, err
+		}
+		args = append(args, "--ref-format="+string(b.refFormat))
+	}
+	if !nonBare {
+		cmd := exec.CommandContext(ctx, "git", append(args, b.path)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("could not %q: %w\n%s", cmd, err, out)
+		}
 	}
 
 	switch err := b.validate(ctx); err {
@@ -163,7 +578,7 @@ func Init(ctx context.Context, path string, opts ...BiomeOption) (Biome, error)
 		return nil, err
 	}
 
-	return b, b.editConfig(ctx, func(ctx context.Context, c *config.Config) (bool, error) {
+	err := b.editConfig(ctx, func(ctx context.Context, c *config.Config) (bool, error) {
 		c.SetOption(section, "", versionOpt, v1)
 
 		// fetch.parallel Specifies the maximal number of fetch operations to
@@ -171,10 +586,44 @@ func Init(ctx context.Context, path string, opts ...BiomeOption) (Biome, error)
 		// --multiple option of git-fetch(1) is in effect).
 		// A value of 0 will give some reasonable default. If unset, it
 		// defaults to 1.
-		c.SetOption("fetch", "", "parallel", "0")
+		if b.fetchParallel != nil {
+			c.SetOption("fetch", "", "parallel", strconv.Itoa(*b.fetchParallel))
+		}
+		if b.lfsPolicy != "" {
+			setConfigValue(c, lfsPolicyKey, string(b.lfsPolicy))
+		}
+		if b.refFormat != "" {
+			setConfigValue(c, refFormatKey, string(b.refFormat))
+		}
+		b.maintenance.configure(c)
 
 		return true, nil
 	})
+	if err != nil {
+		return b, err
+	}
+	if b.maintenance != "" && b.maintenance != MaintenanceOff {
+		if err := b.registerMaintenance(ctx); err != nil {
+			return b, err
+		}
+	}
+	return b, nil
+}
+
+// tryInit ensures that the installed git can initialize a repository with
+// the given `git init` options, by initializing a throwaway repository.
+func tryInit(ctx context.Context, opts ...string) error {
+	dir, err := os.MkdirTemp("", "gh-biome-init-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	args := append([]string{"init", "--bare", "--quiet"}, opts...)
+	cmd := exec.CommandContext(ctx, "git", append(args, filepath.Join(dir, "repo"))...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("could not %q: %w\n%s", cmd, err, out)
+	}
+	return nil
 }
 
 // Load an existing git biome at the given filesystem directory path.
@@ -188,8 +637,47 @@ func Load(ctx context.Context, path string, opts ...BiomeOption) (Biome, error)
 	return b, b.validate(ctx)
 }
 
+// Discover loads the git biome at the given filesystem directory path, or,
+// like git discovers repositories, the nearest biome enclosing it, ex. when
+// the path is a subdirectory of the biome's bare repository, or of the
+// working tree of a non-bare biome. A git repository that is not a biome
+// does not stop the search. When GIT_DIR is set, git always uses that
+// repository, so the path is loaded as is.
+func Discover(ctx context.Context, path string, opts ...BiomeOption) (Biome, error) {
+	if os.Getenv("GIT_DIR") != "" {
+		return Load(ctx, path, opts...)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	for dir := abs; ; dir = filepath.Dir(dir) {
+		if isGitDir(ctx, dir) || isGitDir(ctx, filepath.Join(dir, ".git")) {
+			found := dir
+			if dir == abs {
+				found = path
+			}
+			b, err := Load(ctx, found, opts...)
+			if !errors.Is(err, errVersionNotSet) {
+				return b, err
+			}
+		}
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+	return Load(ctx, path, opts...)
+}
+
+// isGitDir reports whether the directory is itself a git repository's git
+// directory, ex. a bare repository, rather than only being within one.
+func isGitDir(ctx context.Context, dir string) bool {
+	return exec.CommandContext(ctx, "git", "--git-dir="+dir, "rev-parse", "--git-dir").Run() == nil
+}
+
 // validate that the biome is a valid git repository and is using the expected
-// biome configuration schema version.
+// biome configuration schema version. Repositories configured by the
+// deprecated `add-remotes` command fail with a [NeedsMigrationError].
 func (b *biome) validate(ctx context.Context) error {
 	cmd := exec.CommandContext(ctx, "git", "-C", b.path, "rev-parse")
 	if err := cmd.Run(); err != nil {
@@ -203,6 +691,13 @@ func (b *biome) validate(ctx context.Context) error {
 		return fmt.Errorf("could not assert biome config version: %w", err)
 	}
 	if version == "" {
+		cfg, err := config.Read(ctx, b.path)
+		if err != nil {
+			return err
+		}
+		if isLegacy(cfg) {
+			return &NeedsMigrationError{Path: b.path}
+		}
 		return errVersionNotSet
 	}
 	if version != v1 {
@@ -250,6 +745,14 @@ func (b *biome) AddOwners(ctx context.Context, owners []Owner) error {
 // removed in the next [UpdateRemotes] invocation.
 func (b *biome) RemoveOwners(ctx context.Context, owners []Owner) error {
 	return b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		groups := make(map[string]struct{})
+		for _, owner := range owners {
+			groups[owner.RemoteGroup()] = struct{}{}
+		}
+		if err := b.runHook(ctx, cfg, preRemoveHookKey, removeChanges(remotesOnlyIn(cfg, groups))); err != nil {
+			return false, err
+		}
+
 		biomeSection := cfg.Section(section)
 
 		var ownerRefs []string
@@ -267,6 +770,11 @@ func (b *biome) RemoveOwners(ctx context.Context, owners []Owner) error {
 			biomeSection.AddOption(ownersOpt, ownerRef)
 		}
 
+		// clear settings of removed owners
+		for _, owner := range owners {
+			biomeSection.RemoveSubsection(ownerSubsectionPrefix + owner.String())
+		}
+
 		return true, nil
 	})
 }
@@ -274,13 +782,11 @@ func (b *biome) RemoveOwners(ctx context.Context, owners []Owner) error {
 // Owners lists the GitHub repository owners that are currently within the
 // biome.
 func (b *biome) Owners(ctx context.Context) ([]Owner, error) {
-	var owners []Owner
-	err := b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
-		var err error
-		owners, err = b.getOwners(cfg)
-		return false, err
-	})
-	return owners, err
+	idx, err := b.remotesIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return parseOwners(idx.Owners)
 }
 
 func (b *biome) validateOwners(ctx context.Context, owners []Owner) error {
@@ -294,6 +800,14 @@ func (b *biome) validateOwners(ctx context.Context, owners []Owner) error {
 }
 
 func (b *biome) validateOwner(ctx context.Context, owner Owner) error {
+	if _, ok := b.providers[owner.Host()]; ok {
+		// the provider reports unknown owners when listing their
+		// repositories
+		return nil
+	}
+	if Anonymous(owner.Host()) {
+		return validateOwnerAnonymously(ctx, owner)
+	}
 	client, err := api.NewGraphQLClient(api.ClientOptions{
 		Host: owner.Host(),
 	})
@@ -312,9 +826,15 @@ func (b *biome) validateOwner(ctx context.Context, owner Owner) error {
 }
 
 func (b *biome) getOwners(cfg *config.Config) ([]Owner, error) {
+	return parseOwners(cfg.Section(section).OptionAll(ownersOpt))
+}
+
+// parseOwners parses the owners recorded by the biome.owners option, skipping
+// and reporting any that are invalid.
+func parseOwners(ownerRefs []string) ([]Owner, error) {
 	var owners []Owner
 	var errs error
-	for _, ownerRef := range cfg.Section(section).OptionAll(ownersOpt) {
+	for _, ownerRef := range ownerRefs {
 		owner, err := ParseOwner(ownerRef)
 		if err != nil {
 			errs = errors.Join(errs, err)
@@ -325,6 +845,116 @@ func (b *biome) getOwners(cfg *config.Config) ([]Owner, error) {
 	return owners, errs
 }
 
+// AddViewers records that the repositories accessible to the given
+// authenticated users have joined the git biome. If a viewer for the same
+// GitHub server was previously added, its affiliations are replaced.
+func (b *biome) AddViewers(ctx context.Context, viewers []Viewer) error {
+	if err := b.validateViewers(ctx, viewers); err != nil {
+		return err
+	}
+	return b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		biomeSection := cfg.Section(section)
+		for _, viewer := range viewers {
+			ss := biomeSection.Subsection(viewerSubsectionPrefix + viewer.Host())
+			ss.RemoveOption(affiliationOpt)
+			for _, affiliation := range viewer.Affiliations() {
+				ss.AddOption(affiliationOpt, string(affiliation))
+			}
+		}
+		return true, nil
+	})
+}
+
+// RemoveViewers removes the authenticated users of the given viewers' GitHub
+// servers from the records on the git biome. Remotes that were only
+// accessible through a removed viewer will be removed in the next
+// [UpdateRemotes] invocation.
+func (b *biome) RemoveViewers(ctx context.Context, viewers []Viewer) error {
+	return b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		groups := make(map[string]struct{})
+		for _, viewer := range viewers {
+			groups[viewer.RemoteGroup()] = struct{}{}
+		}
+		if err := b.runHook(ctx, cfg, preRemoveHookKey, removeChanges(remotesOnlyIn(cfg, groups))); err != nil {
+			return false, err
+		}
+
+		biomeSection := cfg.Section(section)
+		for _, viewer := range viewers {
+			biomeSection.RemoveSubsection(viewerSubsectionPrefix + viewer.Host())
+		}
+		return true, nil
+	})
+}
+
+// Viewers lists the authenticated users whose accessible repositories are
+// currently within the biome.
+func (b *biome) Viewers(ctx context.Context) ([]Viewer, error) {
+	var viewers []Viewer
+	err := b.readConfig(ctx, func(ctx context.Context, cfg *config.Config) error {
+		var err error
+		viewers, err = b.getViewers(cfg)
+		return err
+	})
+	return viewers, err
+}
+
+func (b *biome) validateViewers(ctx context.Context, viewers []Viewer) error {
+	var errs []error
+	for _, viewer := range viewers {
+		if err := b.validateViewer(ctx, viewer); err != nil {
+			errs = append(errs, fmt.Errorf("could not validate authenticated user: %s: %w", viewer.Host(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (b *biome) validateViewer(ctx context.Context, viewer Viewer) error {
+	if _, ok := b.providers[viewer.Host()]; ok {
+		return fmt.Errorf("%w: %s", errProviderViewer, viewer.Host())
+	}
+	if Anonymous(viewer.Host()) {
+		return fmt.Errorf("%w to %s, run 'gh auth login --hostname %s'", errNotLoggedIn, viewer.Host(), viewer.Host())
+	}
+	client, err := api.NewGraphQLClient(api.ClientOptions{
+		Host: viewer.Host(),
+	})
+	if err != nil {
+		return fmt.Errorf("could not create API client: %s: %w", viewer.Host(), err)
+	}
+	var query struct {
+		Viewer struct {
+			Login string
+		}
+	}
+	return client.QueryWithContext(ctx, "Viewer", &query, nil)
+}
+
+func (b *biome) getViewers(cfg *config.Config) ([]Viewer, error) {
+	var viewers []Viewer
+	var errs error
+	for _, ss := range cfg.Section(section).Subsections {
+		host, ok := strings.CutPrefix(ss.Name, viewerSubsectionPrefix)
+		if !ok {
+			continue
+		}
+		var affiliations []RepositoryAffiliation
+		for _, v := range ss.OptionAll(affiliationOpt) {
+			affiliation, err := ParseAffiliation(v)
+			if err != nil {
+				errs = errors.Join(errs, err)
+				continue
+			}
+			affiliations = append(affiliations, affiliation)
+		}
+		viewers = append(viewers, NewViewer(host, affiliations...))
+	}
+	slices.SortFunc(viewers, func(a, b Viewer) int {
+		return strings.Compare(a.Host(), b.Host())
+	})
+	return viewers, errs
+}
+
 // Remotes returns all remotes currently discovered by the biome. Only
 // discovered remotes that are categorized into at least one of the given
 // categories will be returned. Not all remote categories are eligible to
@@ -339,34 +969,54 @@ func (b *biome) Remotes(ctx context.Context, categories ...RemoteCategory) ([]Re
 		remote  Remote
 	}
 	byName := make(map[string]*result)
-	err := b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
-		biomeRemotesSubsection := cfg.Section(section).Subsection(remotesSubsection)
-		for _, opt := range biomeRemotesSubsection.Options {
-			name := opt.Value
-			if _, ok := byName[name]; !ok {
-				byName[name] = &result{
-					matches: false,
-					remote: Remote{
-						Name: name,
-					},
-				}
+	upstreams := make(map[string]string)
+	idx, err := b.remotesIndex(ctx)
+	for _, opt := range idx.Remotes {
+		key, name := opt[0], opt[1]
+		if key == upstreamOpt {
+			if fork, upstream, ok := strings.Cut(name, " "); ok {
+				upstreams[fork] = upstream
 			}
-			switch opt.Key {
-			case activeOpt:
-			case archivedOpt:
-				byName[name].remote.Archived = true
-			case disabledOpt:
-				byName[name].remote.Disabled = true
-			case lockedOpt:
-				byName[name].remote.Locked = true
+			continue
+		}
+		if key == pendingHeadOpt {
+			continue
+		}
+		if _, ok := byName[name]; !ok {
+			byName[name] = &result{
+				matches: false,
+				remote: Remote{
+					Name:        name,
+					refTemplate: idx.RefTemplate,
+				},
 			}
-			byName[name].matches = byName[name].matches || slices.Contains(categories, RemoteCategory(opt.Key))
 		}
-		return false, nil
-	})
+		switch key {
+		case activeOpt:
+		case archivedOpt:
+			byName[name].remote.Archived = true
+		case disabledOpt:
+			byName[name].remote.Disabled = true
+		case lockedOpt:
+			byName[name].remote.Locked = true
+		case internalOpt:
+			byName[name].remote.Internal = true
+		case evictedOpt:
+			byName[name].remote.Evicted = true
+			continue
+		}
+		byName[name].matches = byName[name].matches || slices.Contains(categories, RemoteCategory(key))
+	}
 	var remotes []Remote
-	for _, r := range byName {
+	for name, r := range byName {
+		// evicted remotes are only in the evicted category, since they have
+		// no references until they are restored
+		if r.remote.Evicted {
+			r.matches = slices.Contains(categories, Evicted)
+		}
 		if r.matches {
+			r.remote.Upstream = upstreams[name]
+			r.remote.refTemplate = remoteRefTemplate(idx.RefTemplate, idx.Attic, r.remote)
 			remotes = append(remotes, r.remote)
 		}
 	}
@@ -376,56 +1026,269 @@ func (b *biome) Remotes(ctx context.Context, categories ...RemoteCategory) ([]Re
 	return remotes, err
 }
 
+// RemoteGroupMembers lists the names of the git remotes in the given git
+// remote groups, sorted by name.
+func (b *biome) RemoteGroupMembers(ctx context.Context, groups ...string) ([]string, error) {
+	var members []string
+	err := b.readConfig(ctx, func(ctx context.Context, cfg *config.Config) error {
+		for _, opt := range cfg.Section("remotes").Options {
+			if slices.Contains(groups, opt.Key) {
+				members = append(members, opt.Value)
+			}
+		}
+		return nil
+	})
+	slices.Sort(members)
+	return slices.Compact(members), err
+}
+
 // UpdateRemotes syncs the git remote configurations. All repositories
 // owned by the biome's owners will be configured as remotes. Any other
 // remotes will be dropped. HEAD references for each remote will be updated
 // as well.
-func (b *biome) UpdateRemotes(ctx context.Context) error {
+//
+// If the biome's API request budget is spent, the remaining owners and
+// viewers keep their previously configured remotes, and are discovered first
+// by the next update. The update is otherwise completed, returning
+// [ErrAPIBudgetExhausted].
+//
+// If owners are given, only their remotes are discovered. All other owners
+// and viewers keep their previously configured remotes. Likewise, paused
+// owners are never discovered, keeping their remotes until they are resumed.
+func (b *biome) UpdateRemotes(ctx context.Context, selected ...Owner) error {
 	remotesToCleanUp := make(map[string]struct{})
 	var addedRemoteCfgs []remoteConfig
 
+	// deferred lists the remote groups whose discovery was deferred to the
+	// next update, because the API request budget was spent.
+	var deferred []string
+
+	// atticked lists the locked remotes whose references are moved into the
+	// attic, rather than removed.
+	var atticked []Remote
+
+	// skipped lists the remote groups that were not selected for discovery,
+	// or whose owners are paused.
+	var skipped []string
+
+	// previousNamespaces records the reference namespace of each remote that
+	// was configured before the update.
+	previousNamespaces := make(map[string]string)
+
 	if err := b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		// the edit is retried if the config changes concurrently, so start
+		// over on each attempt
+		clear(remotesToCleanUp)
+		clear(previousNamespaces)
+		addedRemoteCfgs, deferred, atticked, skipped = nil, nil, nil, nil
+
 		owners, err := b.getOwners(cfg)
 		if err != nil {
 			return false, fmt.Errorf("could not load repository owners: %w", err)
 		}
+		viewers, err := b.getViewers(cfg)
+		if err != nil {
+			return false, fmt.Errorf("could not load authenticated users: %w", err)
+		}
 
 		gitRemoteSection := cfg.Section("remote")
 		gitRemotesSection := cfg.Section("remotes")
+		biomeRemotesSubsection := cfg.Section(section).Subsection(remotesSubsection)
 
-		// clear all remote groups
-		gitRemotesSection.Options = nil
+		// remember the previous configuration, to keep the remotes of
+		// owners and viewers whose discovery is deferred
+		previousSubsections := gitRemoteSection.Subsections
+		previousGroups := gitRemotesSection.Options
+		previousMetadata := slices.Clone(biomeRemotesSubsection.Options)
+		previouslyDeferred := cfg.Section(section).OptionAll(deferredOpt)
+		cfg.Section(section).RemoveOption(deferredOpt)
+		budget := newAPIBudget(cfg)
+		fields := newRepositoryFields(cfg)
+
+		// clear all of the biome's remote groups, keeping any other groups
+		foreign := foreignRemotes(cfg)
+		otherGroups := previousGroups[:0:0]
+		for _, opt := range previousGroups {
+			if !remoteGroupPattern.MatchString(opt.Key) {
+				otherGroups = append(otherGroups, opt)
+			}
+		}
+		gitRemotesSection.Options = otherGroups
 
+		template := refTemplate(cfg)
+		attic := atticEnabled(cfg)
+		applyReflogRetention(cfg)
+		foreignSubsections := previousSubsections[:0:0]
 		for _, ss := range gitRemoteSection.Subsections {
+			if _, ok := foreign[ss.Name]; ok {
+				foreignSubsections = append(foreignSubsections, ss)
+				continue
+			}
 			remotesToCleanUp[ss.Name] = struct{}{}
+			namespace, ok := refNamespaceOf(ss.Options.Get("fetch"))
+			if !ok {
+				namespace = Remote{Name: ss.Name}.RefNamespace()
+			}
+			previousNamespaces[ss.Name] = namespace
 		}
 
-		// clear existing remote declarations
-		gitRemoteSection.Subsections = nil
+		// clear existing remote declarations, keeping foreign remotes
+		gitRemoteSection.Subsections = foreignSubsections
 
 		// clear metadata about remotes
-		biomeRemotesSubsection := cfg.Section(section).Subsection(remotesSubsection)
 		biomeRemotesSubsection.
 			RemoveOption(activeOpt).
 			RemoveOption(archivedOpt).
 			RemoveOption(disabledOpt).
 			RemoveOption(lockedOpt).
-			RemoveOption(unsupportedOpt)
-
+			RemoveOption(unsupportedOpt).
+			RemoveOption(internalOpt).
+			RemoveOption(upstreamOpt).
+			RemoveOption(synthesizedOpt).
+			RemoveOption(pendingHeadOpt)
+
+		type source struct {
+			remoteGroup string
+			host        string
+			tagOpt      string
+			paused      bool
+			build       func(context.Context, *apiBudget) ([]remoteConfig, error)
+		}
+		var sources []source
 		for _, owner := range owners {
-			remoteGroup := owner.RemoteGroup()
+			includes := splitList(ownerSetting(cfg, owner, includeOpt))
+			excludes := splitList(ownerSetting(cfg, owner, "exclude"))
+			createdAfter, _ := time.Parse(time.DateOnly, ownerSetting(cfg, owner, createdAfterOpt))
+			ownerFields := fields
+			ownerFields.createdAt = !createdAfter.IsZero()
+			paused, _ := ParseBool(ownerSetting(cfg, owner, "paused"))
+			sources = append(sources, source{
+				remoteGroup: owner.RemoteGroup(),
+				host:        owner.Host(),
+				tagOpt:      tagOpts[ownerSetting(cfg, owner, "tags")],
+				paused:      paused,
+				build: func(ctx context.Context, budget *apiBudget) ([]remoteConfig, error) {
+					remoteCfgs, err := b.buildRemoteConfigs(ctx, owner, budget, ownerFields)
+					return slices.DeleteFunc(remoteCfgs, func(r remoteConfig) bool {
+						return (len(includes) > 0 && !r.Remote.matchesAny(includes)) || r.Remote.matchesAny(excludes) || r.CreatedAt.Before(createdAfter)
+					}), err
+				},
+			})
+		}
+		for _, viewer := range viewers {
+			sources = append(sources, source{
+				remoteGroup: viewer.RemoteGroup(),
+				host:        viewer.Host(),
+				tagOpt:      tagOpts["none"],
+				build: func(ctx context.Context, budget *apiBudget) ([]remoteConfig, error) {
+					return b.buildViewerRemoteConfigs(ctx, viewer, budget, fields)
+				},
+			})
+		}
 
-			remoteCfgs, err := b.buildRemoteConfigs(ctx, owner)
-			if err != nil {
-				return false, err
+		// remotes may be discovered through more than one source, ex. an
+		// owner's repository that is also accessible to a viewer. GitHub
+		// treats repository names case-insensitively, so remotes are keyed by
+		// their lower case name, keeping the first discovered name.
+		discovered := make(map[string]string)
+
+		// discover the sources that were deferred by the last update first,
+		// so that every source is eventually discovered
+		slices.SortStableFunc(sources, func(a, b source) int {
+			aDeferred, bDeferred := slices.Contains(previouslyDeferred, a.remoteGroup), slices.Contains(previouslyDeferred, b.remoteGroup)
+			switch {
+			case aDeferred && !bDeferred:
+				return -1
+			case !aDeferred && bDeferred:
+				return 1
 			}
-			for _, r := range remoteCfgs {
+			return 0
+		})
+
+		// discover the sources of each host concurrently with other hosts,
+		// then apply the discovered remotes in the order of the sources
+		type discovery struct {
+			skipped    bool
+			remoteCfgs []remoteConfig
+			err        error
+		}
+		discoveries := make([]discovery, len(sources))
+		var jobs []hostJob
+		for i, src := range sources {
+			if src.paused || len(selected) > 0 && !slices.ContainsFunc(selected, func(o Owner) bool {
+				return o.RemoteGroup() == src.remoteGroup
+			}) {
+				discoveries[i].skipped = true
+				continue
+			}
+			jobs = append(jobs, hostJob{
+				host: src.host,
+				run: func(ctx context.Context) error {
+					remoteCfgs, err := src.build(ctx, budget)
+					if err != nil && !errors.Is(err, ErrAPIBudgetExhausted) {
+						return err
+					}
+					discoveries[i] = discovery{remoteCfgs: remoteCfgs, err: err}
+					return nil
+				},
+			})
+		}
+		if err := runByHost(ctx, cfg, jobs); err != nil {
+			return false, err
+		}
+
+		// remotes whose names changed only in letter case keep their
+		// settings, under the name they are discovered with first
+		renamed := make(map[string]string)
+		for _, d := range discoveries {
+			if d.err != nil {
+				continue
+			}
+			for _, r := range d.remoteCfgs {
+				if _, ok := renamed[strings.ToLower(r.Remote.Name)]; !ok {
+					renamed[strings.ToLower(r.Remote.Name)] = r.Remote.Name
+				}
+			}
+		}
+		renameRemoteSettings(cfg, func(name string) (string, bool) {
+			name, ok := renamed[strings.ToLower(name)]
+			return name, ok
+		})
+
+		for i, src := range sources {
+			remoteGroup := src.remoteGroup
+			if discoveries[i].skipped {
+				skipped = append(skipped, remoteGroup)
+				continue
+			}
+			if errors.Is(discoveries[i].err, ErrAPIBudgetExhausted) {
+				deferred = append(deferred, remoteGroup)
+				continue
+			}
+			for _, r := range discoveries[i].remoteCfgs {
+				r = r.withRefTemplate(remoteRefTemplate(template, attic, r.Remote))
+				if name, ok := discovered[strings.ToLower(r.Remote.Name)]; ok {
+					if gitRemoteSection.HasSubsection(name) {
+						gitRemotesSection.AddOption(remoteGroup, name)
+					}
+					continue
+				}
+				discovered[strings.ToLower(r.Remote.Name)] = r.Remote.Name
+				if r.Remote.Internal {
+					biomeRemotesSubsection.AddOption(internalOpt, r.Remote.Name)
+				}
+				if r.Remote.Upstream != "" {
+					biomeRemotesSubsection.AddOption(upstreamOpt, r.Remote.Name+" "+r.Remote.Upstream)
+				}
 				if r.Remote.Disabled {
 					biomeRemotesSubsection.AddOption(disabledOpt, r.Remote.Name)
 					continue
 				}
 				if r.Remote.Locked {
 					biomeRemotesSubsection.AddOption(lockedOpt, r.Remote.Name)
+					if attic {
+						atticked = append(atticked, r.Remote)
+					}
 					continue
 				}
 				refspec, err := r.Remote.FetchRefspec()
@@ -441,92 +1304,288 @@ func (b *biome) UpdateRemotes(ctx context.Context) error {
 					biomeRemotesSubsection.AddOption(activeOpt, r.Remote.Name)
 				}
 
+				// evicted remotes are not fetched until they are restored
+				if evictedBundle(cfg, r.Remote.Name) != "" {
+					continue
+				}
+
 				// Add remote
 				delete(remotesToCleanUp, r.Remote.Name)
 				addedRemoteCfgs = append(addedRemoteCfgs, r)
 				gitRemoteSection.Subsection(r.Remote.Name).SetOption("url", r.Remote.FetchURL())
 				gitRemoteSection.Subsection(r.Remote.Name).SetOption("fetch", refspec)
-				gitRemoteSection.Subsection(r.Remote.Name).SetOption("tagOpt", "--no-tags")
+				if src.tagOpt != "" {
+					gitRemoteSection.Subsection(r.Remote.Name).SetOption("tagOpt", src.tagOpt)
+				}
 				gitRemotesSection.AddOption(remoteGroup, r.Remote.Name)
 			}
 		}
+
+		// keep the previous remotes of deferred and skipped sources, unless
+		// they were discovered through another source. Skipped sources that
+		// were deferred before remain deferred.
+		kept := maps.Clone(previousNamespaces)
+		for _, remoteGroup := range slices.Concat(deferred, skipped) {
+			if slices.Contains(deferred, remoteGroup) || slices.Contains(previouslyDeferred, remoteGroup) {
+				cfg.Section(section).AddOption(deferredOpt, remoteGroup)
+			}
+			for _, opt := range previousGroups {
+				if opt.Key != remoteGroup {
+					continue
+				}
+				name := opt.Value
+				if _, ok := discovered[strings.ToLower(name)]; !ok {
+					var restored bool
+					for _, ss := range previousSubsections {
+						if ss.Name == name {
+							gitRemoteSection.Subsections = append(gitRemoteSection.Subsections, ss)
+							restored = true
+						}
+					}
+					if !restored {
+						continue
+					}
+					discovered[strings.ToLower(name)] = name
+					for _, meta := range previousMetadata {
+						if meta.Value == name || strings.HasPrefix(meta.Value, name+" ") {
+							biomeRemotesSubsection.AddOption(meta.Key, meta.Value)
+						}
+					}
+					delete(remotesToCleanUp, name)
+					delete(kept, name)
+				}
+				if gitRemoteSection.HasSubsection(discovered[strings.ToLower(name)]) {
+					gitRemotesSection.AddOption(remoteGroup, discovered[strings.ToLower(name)])
+				}
+			}
+		}
+
+		// remotes are discovered in the order of their sources, which puts
+		// deferred sources first, so list the metadata of remotes by name,
+		// independent of the order they were discovered in
+		slices.SortStableFunc(biomeRemotesSubsection.Options, func(a, b *config.Option) int {
+			aName, _, _ := strings.Cut(a.Value, " ")
+			bName, _, _ := strings.Cut(b.Value, " ")
+			return cmp.Compare(aName, bName)
+		})
+
+		// let the preUpdate hook veto the update before it is written
+		if err := b.runHook(ctx, cfg, preUpdateHookKey, updateChanges(kept, addedRemoteCfgs)); err != nil {
+			return false, err
+		}
 		return true, nil
 	}); err != nil {
 		return fmt.Errorf("could not update remote configurations: %w", err)
 	}
 
+	// Repositories whose names changed only in letter case are the same
+	// repository to GitHub. Likewise, the reference namespace template may
+	// have changed since remotes were last updated. Migrate references to the
+	// new namespace rather than dropping them and fetching everything again.
+	previousByLowerName := make(map[string]string)
+	for name := range previousNamespaces {
+		previousByLowerName[strings.ToLower(name)] = name
+	}
+	moved := make(map[string]string)
+	var unconfigured []Remote
+	for _, r := range addedRemoteCfgs {
+		old, ok := previousByLowerName[strings.ToLower(r.Remote.Name)]
+		if !ok {
+			unconfigured = append(unconfigured, r.Remote)
+			continue
+		}
+		delete(remotesToCleanUp, old)
+		if namespace := r.Remote.RefNamespace(); previousNamespaces[old] != namespace {
+			moved[previousNamespaces[old]] = namespace
+		}
+	}
+
+	// keep the references of locked remotes in the attic, rather than
+	// removing them with the remote
+	for _, r := range atticked {
+		old, ok := previousByLowerName[strings.ToLower(r.Name)]
+		if !ok {
+			continue
+		}
+		delete(remotesToCleanUp, old)
+		if namespace := r.RefNamespace(); previousNamespaces[old] != namespace {
+			moved[previousNamespaces[old]] = namespace
+		}
+	}
+
+	// restore references from the attic for remotes that are configured
+	// again, ex. after their repositories were unlocked
+	restored, err := b.restoreFromAttic(ctx, unconfigured)
+	if err != nil {
+		return fmt.Errorf("could not restore references from the attic: %w", err)
+	}
+	maps.Copy(moved, restored)
+	if err := b.moveRefs(ctx, moved); err != nil {
+		return fmt.Errorf("could not migrate references for renamed remotes: %w", err)
+	}
+
+	if _, err := b.remotesIndex(ctx); err != nil {
+		return fmt.Errorf("could not rebuild remotes index: %w", err)
+	}
+
 	if err := b.setHeads(ctx, addedRemoteCfgs); err != nil {
 		return fmt.Errorf("could not set HEAD references for remotes: %w", err)
 	}
+	if _, err := b.SynthesizeHeads(ctx); err != nil {
+		return fmt.Errorf("could not synthesize HEAD references for remotes: %w", err)
+	}
 
-	if err := b.cleanUpRemotes(ctx, remotesToCleanUp); err != nil {
+	var namespacesToCleanUp []string
+	for name := range remotesToCleanUp {
+		namespacesToCleanUp = append(namespacesToCleanUp, previousNamespaces[name])
+	}
+	if err := b.cleanUpRefs(ctx, namespacesToCleanUp); err != nil {
 		return fmt.Errorf("could not clean up old remotes: %w", err)
 	}
 
+	if len(deferred) > 0 {
+		return fmt.Errorf("%w: discovery of %s deferred to the next update", ErrAPIBudgetExhausted, strings.Join(deferred, ", "))
+	}
 	return nil
 }
 
+// setHeads points the HEAD reference of each remote at its default branch,
+// or deletes it for remotes without a default branch. HEAD references are only
+// set once their default branch exists, ex. after the remote's first fetch, so
+// that they never dangle. Until then, any previous HEAD reference is kept, and
+// the default branch is recorded as pending, see [biome.ResolveHeads].
 func (b *biome) setHeads(ctx context.Context, remoteCfgs []remoteConfig) error {
-	w, err := b.updateRefs(ctx)
+	var targets []string
+	for _, r := range remoteCfgs {
+		if r.Head != "" {
+			targets = append(targets, r.Head)
+		}
+	}
+	refs, err := b.listRefs(ctx, targets)
 	if err != nil {
 		return err
 	}
+	existing := make(map[string]bool)
+	for _, ref := range refs {
+		existing[ref.Name] = ref.ObjectName != ""
+	}
 
-	for _, r := range remoteCfgs {
-		head := r.Remote.Head()
-		if r.Head == "" {
-			if _, err := fmt.Fprintf(w, "option no-deref\nsymref-delete %s\n", head); err != nil {
-				return fmt.Errorf("could not delete HEAD ref for %s: %w", r.Remote.Name, err)
-			}
-		} else {
-			if _, err := fmt.Fprintf(w, "option no-deref\nsymref-update %s %s\n", head, r.Head); err != nil {
-				return fmt.Errorf("could not update HEAD ref for %s: %w", r.Remote.Name, err)
+	var pending []string
+	if err := b.transactRefs(ctx, func(w io.Writer) error {
+		pending = nil
+		for _, r := range remoteCfgs {
+			head := r.Remote.Head()
+			switch {
+			case r.Head == "":
+				if _, err := fmt.Fprintf(w, "option no-deref\nsymref-delete %s\n", head); err != nil {
+					return fmt.Errorf("could not delete HEAD ref for %s: %w", r.Remote.Name, err)
+				}
+			case existing[r.Head]:
+				if _, err := fmt.Fprintf(w, "option no-deref\nsymref-update %s %s\n", head, r.Head); err != nil {
+					return fmt.Errorf("could not update HEAD ref for %s: %w", r.Remote.Name, err)
+				}
+			default:
+				pending = append(pending, r.Remote.Name+" "+r.Head)
 			}
 		}
+		return nil
+	}); err != nil {
+		return err
 	}
-
-	return w.Close()
+	if len(pending) == 0 {
+		return nil
+	}
+	return b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		metadata := cfg.Section(section).Subsection(remotesSubsection)
+		for _, p := range pending {
+			metadata.AddOption(pendingHeadOpt, p)
+		}
+		return true, nil
+	})
 }
 
-func (b *biome) cleanUpRemotes(ctx context.Context, remotesToCleanUp map[string]struct{}) error {
-	if len(remotesToCleanUp) == 0 {
+// cleanUpRefs deletes all references under the given reference namespaces.
+func (b *biome) cleanUpRefs(ctx context.Context, namespaces []string) error {
+	if len(namespaces) == 0 {
 		return nil
 	}
 
-	w, err := b.updateRefs(ctx)
+	refs, err := b.listRefs(ctx, namespaces)
 	if err != nil {
 		return err
 	}
 
-	var buf bytes.Buffer
-	args := []string{
-		"-C",
-		b.path,
-		"for-each-ref",
-		"--format=%(if)%(symref)%(then)option no-deref\nsymref-delete %(refname)%(else)delete %(refname)%(end)",
-	}
-	for remote := range remotesToCleanUp {
-		args = append(args, fmt.Sprintf("refs/remotes/%s", remote))
-	}
-	cmd := exec.CommandContext(ctx, "git", args...)
-	cmd.Stdout = w
-	cmd.Stderr = &buf
-
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("could not %q: %w: %s", cmd.String(), err, buf.String())
-	}
-
-	return w.Close()
+	return b.transactRefs(ctx, func(w io.Writer) error {
+		for _, r := range refs {
+			var err error
+			if r.Symref != "" {
+				_, err = fmt.Fprintf(w, "option no-deref\nsymref-delete %s\n", r.Name)
+			} else {
+				_, err = fmt.Fprintf(w, "delete %s\n", r.Name)
+			}
+			if err != nil {
+				return fmt.Errorf("could not delete %s: %w", r.Name, err)
+			}
+		}
+		return nil
+	})
 }
 
 func (b *biome) updateRefs(ctx context.Context) (io.WriteCloser, error) {
 	return newRefUpdater(ctx, b.path)
 }
 
+// refLockRetry is how reference transactions are retried when a reference is
+// locked by a concurrent writer, ex. a `git fetch` or a maintenance task.
+var refLockRetry = retry.Policy{
+	Attempts: 5,
+	Delay:    100 * time.Millisecond,
+	MaxDelay: 2 * time.Second,
+}
+
+// isRefLockContention reports whether a reference transaction failed because
+// another process holds the lock of a reference, or of the packed-refs file.
+func isRefLockContention(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "cannot lock ref") || strings.Contains(msg, ".lock': File exists")
+}
+
+// transactRefs updates references in a single transaction of the
+// `git update-ref --stdin` commands written by write. The transaction is
+// attempted again, with all of its commands, while it fails because of lock
+// contention, since concurrent writers only hold their locks briefly.
+func (b *biome) transactRefs(ctx context.Context, write func(io.Writer) error) error {
+	var commands bytes.Buffer
+	if err := write(&commands); err != nil {
+		return err
+	}
+	return refLockRetry.Do(ctx, isRefLockContention, func() error {
+		w, err := b.updateRefs(ctx)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(commands.Bytes()); err != nil {
+			return fmt.Errorf("could not write reference updates: %w", err)
+		}
+		return w.Close()
+	})
+}
+
 func (b *biome) editConfig(ctx context.Context, do func(context.Context, *config.Config) (bool, error)) error {
 	return config.NewEditor(b.path, b.editorOptions...).Edit(ctx, do)
 }
 
+// readConfig loads the biome's git config for read-only access. It is much
+// faster than editConfig, so it should be preferred whenever the config does
+// not need to be changed.
+func (b *biome) readConfig(ctx context.Context, do func(context.Context, *config.Config) error) error {
+	cfg, err := config.Read(ctx, b.path)
+	if err != nil {
+		return err
+	}
+	return do(ctx, cfg)
+}
+
 func (b *biome) getConfig(ctx context.Context, key string) (string, error) {
 	cmd := exec.CommandContext(ctx, "git", "-C", b.path, "config", "get", "--local", key)
 	out, err := cmd.Output()
@@ -541,7 +1600,21 @@ func (b *biome) getConfig(ctx context.Context, key string) (string, error) {
 	return string(bytes.TrimSpace(out)), nil
 }
 
-func (b *biome) buildRemoteConfigs(ctx context.Context, owner Owner) ([]remoteConfig, error) {
+// buildRemoteConfigs discovers the repositories of the owner with the
+// owner's host's [Provider], returning the configurations of their remotes.
+func (b *biome) buildRemoteConfigs(ctx context.Context, owner Owner, budget *apiBudget, fields repositoryFields) ([]remoteConfig, error) {
+	repos, err := b.provider(owner.Host(), budget, fields).ListRepositories(ctx, owner)
+	remoteCfgs, invalid := remoteConfigs(owner, repos)
+	return remoteCfgs, errors.Join(err, invalid)
+}
+
+// graphQLProvider lists the repositories of owners with GitHub's GraphQL API.
+type graphQLProvider struct {
+	budget *apiBudget
+	fields repositoryFields
+}
+
+func (p graphQLProvider) ListRepositories(ctx context.Context, owner Owner) ([]Repo, error) {
 	client, err := api.NewGraphQLClient(api.ClientOptions{
 		Host: owner.Host(),
 	})
@@ -563,19 +1636,72 @@ func (b *biome) buildRemoteConfigs(ctx context.Context, owner Owner) ([]remoteCo
 		"owner":     graphql.String(owner.name),
 		"endCursor": (*graphql.String)(nil),
 	}
-	var remoteCfgs []remoteConfig
+	p.fields.addVariables(variables)
+	var repos []Repo
 	for {
+		if err := p.budget.spend(); err != nil {
+			return repos, err
+		}
 		if err := client.QueryWithContext(ctx, "OwnerRepositories", &query, variables); err != nil {
-			return remoteCfgs, fmt.Errorf("could not query repos for %s: %w", owner, err)
+			return repos, fmt.Errorf("could not query repos for %s: %w", owner, err)
 		}
 		for _, repo := range query.RepositoryOwner.Repositories.Nodes {
-			remoteCfgs = append(remoteCfgs, repo.Remote())
+			repos = append(repos, repo.repo())
 		}
 		if !query.RepositoryOwner.Repositories.PageInfo.HasNextPage {
 			break
 		}
 		variables["endCursor"] = graphql.String(query.RepositoryOwner.Repositories.PageInfo.EndCursor)
 	}
+	return repos, nil
+}
+
+// buildViewerRemoteConfigs discovers the repositories accessible to the
+// viewer with GitHub's GraphQL API, returning the configurations of their
+// remotes. Viewers of hosts with a [Provider] are refused, see
+// [UseProvider].
+func (b *biome) buildViewerRemoteConfigs(ctx context.Context, viewer Viewer, budget *apiBudget, fields repositoryFields) ([]remoteConfig, error) {
+	if _, ok := b.providers[viewer.Host()]; ok {
+		return nil, fmt.Errorf("%w: %s", errProviderViewer, viewer.Host())
+	}
+	client, err := api.NewGraphQLClient(api.ClientOptions{
+		Host: viewer.Host(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create API client: %s: %w", viewer.Host(), err)
+	}
+	var query struct {
+		Viewer struct {
+			Repositories struct {
+				Nodes    []repository
+				PageInfo struct {
+					HasNextPage bool
+					EndCursor   string
+				}
+			} `graphql:"repositories(first: 100, after: $endCursor, affiliations: $affiliations)"`
+		}
+	}
+	variables := map[string]interface{}{
+		"affiliations": viewer.Affiliations(),
+		"endCursor":    (*graphql.String)(nil),
+	}
+	fields.addVariables(variables)
+	var remoteCfgs []remoteConfig
+	for {
+		if err := budget.spend(); err != nil {
+			return remoteCfgs, err
+		}
+		if err := client.QueryWithContext(ctx, "ViewerRepositories", &query, variables); err != nil {
+			return remoteCfgs, fmt.Errorf("could not query repos for %s: %w", viewer, err)
+		}
+		for _, repo := range query.Viewer.Repositories.Nodes {
+			remoteCfgs = append(remoteCfgs, repo.Remote())
+		}
+		if !query.Viewer.Repositories.PageInfo.HasNextPage {
+			break
+		}
+		variables["endCursor"] = graphql.String(query.Viewer.Repositories.PageInfo.EndCursor)
+	}
 	slices.SortFunc(remoteCfgs, func(a, b remoteConfig) int {
 		return strings.Compare(a.Remote.Name, b.Remote.Name)
 	})
@@ -584,6 +1710,17 @@ func (b *biome) buildRemoteConfigs(ctx context.Context, owner Owner) ([]remoteCo
 
 type BiomeOption func(*biome)
 
+// FetchParallel records the fetch.parallel setting when a new biome is
+// initialized, controlling how many remotes are fetched in parallel. A value
+// of 0 lets git choose a reasonable default. If this option is not given,
+// fetch.parallel is left unset. The option has no effect when loading a biome
+// or initializing a biome that already exists.
+func FetchParallel(n int) BiomeOption {
+	return func(b *biome) {
+		b.fetchParallel = &n
+	}
+}
+
 // EditorOptions overrides the options to use when provisioning a
 // `git config edit` helper.
 func EditorOptions(opts ...config.EditorOption) BiomeOption {
@@ -603,26 +1740,37 @@ type repository struct {
 	IsLocked         bool
 	URL              string `graphql:"url" json:"url"`
 	DefaultBranchRef *ref
+	Visibility       string            `graphql:"visibility @include(if: $visibility)" json:"visibility"`
+	Parent           *parentRepository `graphql:"parent @include(if: $parent)" json:"parent"`
+	CreatedAt        time.Time         `graphql:"createdAt @include(if: $createdAt)" json:"createdAt"`
 }
 
-func (r repository) Remote() remoteConfig {
-	remoteCfg := remoteConfig{
-		Remote: Remote{
-			Name:     r.URL[8:],
-			Archived: r.IsArchived,
-			Disabled: r.IsDisabled,
-			Locked:   r.IsLocked,
-		},
+type parentRepository struct {
+	URL string `graphql:"url" json:"url"`
+}
+
+// repo converts the GraphQL representation of the repository to the
+// representation that providers list.
+func (r repository) repo() Repo {
+	repo := Repo{
+		URL:       r.URL,
+		Archived:  r.IsArchived,
+		Disabled:  r.IsDisabled,
+		Locked:    r.IsLocked,
+		Internal:  r.Visibility == "INTERNAL",
+		CreatedAt: r.CreatedAt,
+	}
+	if r.Parent != nil {
+		repo.Upstream = r.Parent.URL
 	}
 	if r.DefaultBranchRef != nil {
-		remoteCfg.Head = path.Join(
-			"refs/remotes/",
-			remoteCfg.Remote.Name,
-			strings.TrimPrefix(r.DefaultBranchRef.Prefix, "refs/"),
-			r.DefaultBranchRef.Name,
-		)
-	}
-	return remoteCfg
+		repo.DefaultBranch = r.DefaultBranchRef.Name
+	}
+	return repo
+}
+
+func (r repository) Remote() remoteConfig {
+	return r.repo().remote()
 }
 
 type refUpdater struct {
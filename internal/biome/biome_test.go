@@ -6,11 +6,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/orirawlings/gh-biome/internal/config"
 	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
@@ -118,6 +121,7 @@ var (
 			Name:   "main",
 			Prefix: "refs/heads/",
 		},
+		Visibility: "INTERNAL",
 	}
 
 	repositories = map[string][]repository{
@@ -144,6 +148,21 @@ var (
 		},
 	}
 
+	viewerRepositories = map[string][]repository{
+		github_com_viewer.Host(): {
+			github_com_cli_cli,
+			github_com_git_git,
+		},
+		my_github_biz_viewer.Host(): {
+			my_github_biz_foobar_bazbiz,
+		},
+	}
+
+	viewerAffiliations = map[string]string{
+		github_com_viewer.Host():    `["COLLABORATOR","ORGANIZATION_MEMBER","OWNER"]`,
+		my_github_biz_viewer.Host(): `["COLLABORATOR"]`,
+	}
+
 	repositoriesStubs map[string]*gock.Response
 )
 
@@ -153,7 +172,7 @@ func TestInit(t *testing.T) {
 	path := t.TempDir()
 
 	t.Run("new biome", func(t *testing.T) {
-		b := initBiome(t, ctx, path, true)
+		b := initBiome(t, ctx, path, true, FetchParallel(0), LFS(LFSSkip))
 		if b.Path() != path {
 			t.Fatalf("expected biome path %q, got %q", path, b.Path())
 		}
@@ -165,9 +184,19 @@ func TestInit(t *testing.T) {
 			t.Errorf("expected %q format for references, but was %q", expectedRefFormat, refFormat)
 		}
 		assertGitConfig(t, path, "fetch.parallel", "0")
+		assertGitConfig(t, path, lfsPolicyKey, string(LFSSkip))
 
-		// assert that Init is idempotent
+		// assert that Init is idempotent, and does not override settings
+		initBiome(t, ctx, path, true, FetchParallel(4), LFS(LFSSelected))
+		assertGitConfig(t, path, "fetch.parallel", "0")
+		assertGitConfig(t, path, lfsPolicyKey, string(LFSSkip))
+	})
+
+	t.Run("new biome without fetch.parallel", func(t *testing.T) {
+		path := t.TempDir()
 		initBiome(t, ctx, path, true)
+		assertConfigNotSet(t, path, "fetch.parallel")
+		assertConfigNotSet(t, path, lfsPolicyKey)
 	})
 
 	t.Run("existing repo with bad biome version", func(t *testing.T) {
@@ -185,6 +214,11 @@ func assertGitConfig(t *testing.T, path, key, expected string) {
 	}
 }
 
+func assertConfigNotSet(t *testing.T, path, key string) {
+	t.Helper()
+	expectRemotesForConfigKey(t, path, key, nil)
+}
+
 func getGitConfig(t *testing.T, path, key string) string {
 	return strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "config", "get", "--local", key))
 }
@@ -214,10 +248,48 @@ func TestLoad(t *testing.T) {
 	})
 }
 
-func initBiome(t testing.TB, ctx context.Context, path string, shouldSucceed bool) Biome {
+func TestDiscover(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+	initBiome(t, ctx, path, true)
+
+	t.Run("biome", func(t *testing.T) {
+		b, err := Discover(ctx, path)
+		testutil.Check(t, err)
+		if b.Path() != path {
+			t.Errorf("expected biome path %q, got %q", path, b.Path())
+		}
+	})
+
+	t.Run("subdirectory of biome", func(t *testing.T) {
+		b, err := Discover(ctx, filepath.Join(path, "refs", "heads"))
+		testutil.Check(t, err)
+		if b.Path() != path {
+			t.Errorf("expected biome path %q, got %q", path, b.Path())
+		}
+	})
+
+	t.Run("repo within biome", func(t *testing.T) {
+		repo := filepath.Join(path, "notes")
+		testutil.Execute(t, "git", "init", "--quiet", "--bare", repo)
+		b, err := Discover(ctx, repo)
+		testutil.Check(t, err)
+		if b.Path() != path {
+			t.Errorf("expected biome path %q, got %q", path, b.Path())
+		}
+	})
+
+	t.Run("non-repo", func(t *testing.T) {
+		if _, err := Discover(ctx, t.TempDir()); !errors.Is(err, errNotGitRepo) {
+			t.Errorf("expected %v, got %v", errNotGitRepo, err)
+		}
+	})
+}
+
+func initBiome(t testing.TB, ctx context.Context, path string, shouldSucceed bool, opts ...BiomeOption) Biome {
 	t.Helper()
 	stubGitHub(t)
-	b, err := Init(ctx, path, biomeOptions()...)
+	b, err := Init(ctx, path, append(biomeOptions(), opts...)...)
 	if shouldSucceed {
 		testutil.Check(t, err)
 	} else {
@@ -331,6 +403,209 @@ my.github.biz/foobar
 	testutil.ExpectError(t, err)
 }
 
+func TestBiome_Viewers(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+	b := initBiome(t, ctx, path, true)
+
+	// no viewers
+	expectViewers(t, ctx, b, nil)
+
+	// add one viewer
+	testutil.Check(t, b.AddViewers(ctx, []Viewer{github_com_viewer}))
+	expectViewers(t, ctx, b, []Viewer{
+		github_com_viewer,
+	})
+
+	// readding a viewer replaces its affiliations
+	testutil.Check(t, b.AddViewers(ctx, []Viewer{NewViewer("github.com", OwnerAffiliation), my_github_biz_viewer}))
+	expectViewers(t, ctx, b, []Viewer{
+		NewViewer("github.com", OwnerAffiliation),
+		my_github_biz_viewer,
+	})
+	assertGitConfig(t, path, "biome.viewer.my.github.biz.affiliation", "COLLABORATOR")
+
+	// remove a viewer
+	testutil.Check(t, b.RemoveViewers(ctx, []Viewer{NewViewer("github.com")}))
+	expectViewers(t, ctx, b, []Viewer{
+		my_github_biz_viewer,
+	})
+
+	// removing a viewer should be idempotent
+	testutil.Check(t, b.RemoveViewers(ctx, []Viewer{NewViewer("github.com")}))
+	expectViewers(t, ctx, b, []Viewer{
+		my_github_biz_viewer,
+	})
+}
+
+func TestBiome_UpdateRemotes_viewers(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+	b := initBiome(t, ctx, path, true)
+
+	addOwners(t, ctx, b, github_com_cli)
+	testutil.Check(t, b.AddViewers(ctx, []Viewer{github_com_viewer}))
+	testutil.Check(t, b.UpdateRemotes(ctx))
+	expectGitRemotes(t, ctx, b, []Remote{
+		githubCLICLIRemote,
+		githubGitGitRemote,
+	})
+	expectActive(t, ctx, b, []Remote{
+		githubCLICLIRemote,
+		githubGitGitRemote,
+	})
+	expectGitRemoteGroups(t, path, map[string][]string{
+		github_com_cli.RemoteGroup(): {
+			githubCLICLIRemote.Name,
+		},
+		github_com_viewer.RemoteGroup(): {
+			githubCLICLIRemote.Name,
+			githubGitGitRemote.Name,
+		},
+	})
+
+	// remotes owned by a remaining owner are kept
+	testutil.Check(t, b.RemoveViewers(ctx, []Viewer{github_com_viewer}))
+	testutil.Check(t, b.UpdateRemotes(ctx))
+	expectGitRemotes(t, ctx, b, []Remote{
+		githubCLICLIRemote,
+	})
+	expectGitRemoteGroups(t, path, map[string][]string{
+		github_com_cli.RemoteGroup(): {
+			githubCLICLIRemote.Name,
+		},
+		github_com_viewer.RemoteGroup(): nil,
+	})
+}
+
+func TestBiome_UpdateRemotes_budget(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+	b := initBiome(t, ctx, path, true)
+
+	addOwners(t, ctx, b, github_com_cli, github_com_git)
+	testutil.Check(t, b.SetSetting(ctx, apiBudgetKey, "1"))
+
+	// the budget only allows github.com/cli to be discovered
+	if err := b.UpdateRemotes(ctx); !errors.Is(err, ErrAPIBudgetExhausted) {
+		t.Fatalf("expected API budget to be exhausted, was %v", err)
+	}
+	expectGitRemotes(t, ctx, b, []Remote{
+		githubCLICLIRemote,
+	})
+	assertGitConfig(t, path, "biome.deferred", github_com_git.RemoteGroup())
+
+	// github.com/git is discovered first by the next update, while the
+	// remotes of github.com/cli are kept
+	if err := b.UpdateRemotes(ctx); !errors.Is(err, ErrAPIBudgetExhausted) {
+		t.Fatalf("expected API budget to be exhausted, was %v", err)
+	}
+	expectGitRemotes(t, ctx, b, []Remote{
+		githubCLICLIRemote,
+		githubGitGitRemote,
+	})
+	expectActive(t, ctx, b, []Remote{
+		githubCLICLIRemote,
+		githubGitGitRemote,
+	})
+	expectGitRemoteGroups(t, path, map[string][]string{
+		github_com_cli.RemoteGroup(): {
+			githubCLICLIRemote.Name,
+		},
+		github_com_git.RemoteGroup(): {
+			githubGitGitRemote.Name,
+		},
+	})
+	assertGitConfig(t, path, "biome.deferred", github_com_cli.RemoteGroup())
+
+	// without a budget, everything is discovered
+	testutil.Check(t, b.UnsetSetting(ctx, apiBudgetKey))
+	testutil.Check(t, b.UpdateRemotes(ctx))
+	assertConfigNotSet(t, path, "biome.deferred")
+}
+
+func TestBiome_UpdateRemotes_selected(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+	b := initBiome(t, ctx, path, true)
+
+	addOwners(t, ctx, b, github_com_cli, github_com_git)
+
+	// only the selected owner is discovered
+	testutil.Check(t, b.UpdateRemotes(ctx, github_com_git))
+	expectGitRemotes(t, ctx, b, []Remote{
+		githubGitGitRemote,
+	})
+
+	// the remotes of owners that are not selected are kept
+	testutil.Check(t, b.UpdateRemotes(ctx, github_com_cli))
+	expectGitRemotes(t, ctx, b, []Remote{
+		githubCLICLIRemote,
+		githubGitGitRemote,
+	})
+	expectActive(t, ctx, b, []Remote{
+		githubCLICLIRemote,
+		githubGitGitRemote,
+	})
+	expectGitRemoteGroups(t, path, map[string][]string{
+		github_com_cli.RemoteGroup(): {
+			githubCLICLIRemote.Name,
+		},
+		github_com_git.RemoteGroup(): {
+			githubGitGitRemote.Name,
+		},
+	})
+
+	// owners that were deferred remain deferred while they are not selected
+	testutil.Check(t, b.SetSetting(ctx, apiBudgetKey, "1"))
+	if err := b.UpdateRemotes(ctx); !errors.Is(err, ErrAPIBudgetExhausted) {
+		t.Fatalf("expected API budget to be exhausted, was %v", err)
+	}
+	assertGitConfig(t, path, "biome.deferred", github_com_git.RemoteGroup())
+	testutil.Check(t, b.UpdateRemotes(ctx, github_com_cli))
+	assertGitConfig(t, path, "biome.deferred", github_com_git.RemoteGroup())
+	expectGitRemotes(t, ctx, b, []Remote{
+		githubCLICLIRemote,
+		githubGitGitRemote,
+	})
+}
+
+func TestBiome_UpdateRemotes_paused(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+	b := initBiome(t, ctx, path, true)
+
+	addOwners(t, ctx, b, github_com_cli)
+	testutil.Check(t, b.UpdateRemotes(ctx))
+	testutil.Check(t, b.SetOwnerSetting(ctx, github_com_cli, "paused", "true"))
+	testutil.ExpectError(t, b.SetOwnerSetting(ctx, github_com_cli, "paused", "maybe"))
+
+	// a paused owner keeps its remotes, even when the owner is selected
+	addOwners(t, ctx, b, github_com_git)
+	testutil.Check(t, b.UpdateRemotes(ctx))
+	testutil.Check(t, b.UpdateRemotes(ctx, github_com_cli))
+	expectGitRemotes(t, ctx, b, []Remote{
+		githubCLICLIRemote,
+		githubGitGitRemote,
+	})
+	expectGitRemoteGroups(t, path, map[string][]string{
+		github_com_cli.RemoteGroup(): {
+			githubCLICLIRemote.Name,
+		},
+		github_com_git.RemoteGroup(): {
+			githubGitGitRemote.Name,
+		},
+	})
+
+	// a resumed owner is discovered again
+	testutil.Check(t, b.UnsetOwnerSetting(ctx, github_com_cli, "paused"))
+	updateStubbedGitHubRepositories(t, github_com_cli, nil)
+	testutil.Check(t, b.UpdateRemotes(ctx))
+	expectGitRemotes(t, ctx, b, []Remote{
+		githubGitGitRemote,
+	})
+}
+
 func TestBiome_UpdateRemotes(t *testing.T) {
 	ctx := context.Background()
 	path := t.TempDir()
@@ -597,6 +872,65 @@ func TestBiome_UpdateRemotes(t *testing.T) {
 	expectRefs(t, ctx, path, nil)
 }
 
+func TestBiome_UpdateRemotes_caseChange(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+	b := initBiome(t, ctx, path, true)
+
+	commitID := createCommitFor(t, ctx, path, []string{
+		barRemoteCfg.Head,
+	})
+	addOwners(t, ctx, b, github_com_orirawlings)
+	testutil.Check(t, b.UpdateRemotes(ctx))
+	testutil.Check(t, b.SetSetting(ctx, RemoteSettingKey(barRemote.Name, "upstream"), githubCLICLIRemote.Name))
+
+	// rename github.com/orirawlings/bar to github.com/orirawlings/Bar
+	renamedBar := github_com_orirawlings_bar
+	renamedBar.URL = "https://github.com/orirawlings/Bar"
+	updateStubbedGitHubRepositories(t, github_com_orirawlings, []repository{
+		renamedBar,
+		// the same repository discovered twice should not be duplicated
+		github_com_orirawlings_bar,
+	})
+	testutil.Check(t, b.UpdateRemotes(ctx))
+
+	// per-remote settings follow the remote
+	expectGitRemotes(t, ctx, b, []Remote{
+		{Name: "github.com/orirawlings/Bar", Upstream: githubCLICLIRemote.Name},
+	})
+	assertGitConfig(t, path, "biome.remote.github.com/orirawlings/Bar.upstream", githubCLICLIRemote.Name)
+	expectRefs(t, ctx, path, []string{
+		fmt.Sprintf(`%s commit refs/remotes/github.com/orirawlings/Bar/HEAD refs/remotes/github.com/orirawlings/Bar/heads/main`, commitID),
+		fmt.Sprintf(`%s commit refs/remotes/github.com/orirawlings/Bar/heads/main `, commitID),
+	})
+}
+
+func TestStageCaseMoves(t *testing.T) {
+	first, second := stageCaseMoves(map[string]string{
+		"refs/remotes/github.com/orirawlings/bar/": "refs/remotes/github.com/orirawlings/Bar/",
+		"refs/remotes/github.com/cli/cli/":         "refs/attic/github.com/cli/cli/",
+		"refs/remotes/github.com/git/git/":         "refs/remotes/github.com/GIT/git/",
+	})
+
+	// names that only change in letter case move through a temporary
+	// namespace, others move directly
+	expectedFirst := map[string]string{
+		"refs/remotes/github.com/cli/cli/":         "refs/attic/github.com/cli/cli/",
+		"refs/remotes/github.com/git/git/":         "refs/biome/moving/0/",
+		"refs/remotes/github.com/orirawlings/bar/": "refs/biome/moving/1/",
+	}
+	expectedSecond := map[string]string{
+		"refs/biome/moving/0/": "refs/remotes/github.com/GIT/git/",
+		"refs/biome/moving/1/": "refs/remotes/github.com/orirawlings/Bar/",
+	}
+	if !maps.Equal(first, expectedFirst) {
+		t.Errorf("unexpected first step: wanted %v, was %v", expectedFirst, first)
+	}
+	if !maps.Equal(second, expectedSecond) {
+		t.Errorf("unexpected second step: wanted %v, was %v", expectedSecond, second)
+	}
+}
+
 func TestBiome_Remotes(t *testing.T) {
 	ctx := context.Background()
 	path := t.TempDir()
@@ -670,12 +1004,119 @@ func TestBiome_Remotes(t *testing.T) {
 	expectUnsupported(t, ctx, b, []Remote{
 		dotPrefixRemote,
 	})
+	expectRemotesForConfigKey(t, path, strings.Join([]string{section, remotesSubsection, internalOpt}, "."), []string{
+		myGithubBizFoobarBazbizRemote.Name,
+	})
 
 	// querying without categories should fail
 	_, err = b.Remotes(ctx)
 	testutil.ExpectError(t, err)
 }
 
+func TestBiome_PlanRemoveOwners(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+	b := initBiome(t, ctx, path, true)
+
+	commitID := createCommitFor(t, ctx, path, []string{
+		barRemoteCfg.Head,
+		archivedRemoteCfg.Head,
+	})
+	addOwners(t, ctx, b, github_com_orirawlings, github_com_cli)
+	testutil.Check(t, b.UpdateRemotes(ctx))
+
+	plan, err := b.PlanRemoveOwners(ctx, []Owner{github_com_orirawlings}, nil)
+	testutil.Check(t, err)
+	expected := []RemoteRefs{
+		{Remote: Remote{Name: archivedRemote.Name}, Namespace: archivedRemote.RefNamespace(), Refs: 2},
+		{Remote: Remote{Name: barRemote.Name}, Namespace: barRemote.RefNamespace(), Refs: 2},
+		{Remote: Remote{Name: headlessRemote.Name}, Namespace: headlessRemote.RefNamespace(), Refs: 0},
+	}
+	if !slices.Equal(plan.Remotes, expected) {
+		t.Errorf("unexpected remotes in plan: wanted %v, was %v", expected, plan.Remotes)
+	}
+	if plan.Refs() != 4 {
+		t.Errorf("expected 4 refs in plan, was %d", plan.Refs())
+	}
+	if plan.ReclaimableBytes <= 0 {
+		t.Errorf("expected reclaimable bytes for commit %s, was %d", commitID, plan.ReclaimableBytes)
+	}
+
+	// planning should not change anything
+	expectOwners(t, ctx, b, []Owner{
+		github_com_cli,
+		github_com_orirawlings,
+	})
+
+	// owners without remotes have nothing to remove
+	plan, err = b.PlanRemoveOwners(ctx, []Owner{github_com_git}, nil)
+	testutil.Check(t, err)
+	if len(plan.Remotes) != 0 {
+		t.Errorf("expected no remotes in plan, was %v", plan.Remotes)
+	}
+}
+
+func TestBiome_RenameOwner(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+	b := initBiome(t, ctx, path, true)
+
+	// the reflogs of the references are moved along with them
+	cmd := exec.CommandContext(ctx, "git", "-C", path, "config", "core.logAllRefUpdates", "always")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("could not enable reflogs: %v: %s", err, out)
+	}
+	commitID := createCommitFor(t, ctx, path, []string{
+		barRemoteCfg.Head,
+	})
+	addOwners(t, ctx, b, github_com_orirawlings, github_com_cli)
+	testutil.Check(t, b.UpdateRemotes(ctx))
+	testutil.Check(t, b.SetSetting(ctx, RemoteSettingKey(barRemote.Name, "upstream"), githubCLICLIRemote.Name))
+
+	// renaming an owner that was never added should fail
+	testutil.ExpectError(t, b.RenameOwner(ctx, github_com_git, github_com_kubernetes))
+
+	// renaming to an owner that was already added should fail
+	testutil.ExpectError(t, b.RenameOwner(ctx, github_com_orirawlings, github_com_cli))
+
+	testutil.Check(t, b.RenameOwner(ctx, github_com_orirawlings, github_com_kubernetes))
+	expectOwners(t, ctx, b, []Owner{
+		github_com_cli,
+		github_com_kubernetes,
+	})
+	// per-remote settings follow their remotes
+	renamedBar := Remote{Name: "github.com/kubernetes/bar", Upstream: githubCLICLIRemote.Name}
+	renamedArchived := Remote{Name: "github.com/kubernetes/archived", Archived: true}
+	renamedHeadless := Remote{Name: "github.com/kubernetes/headless"}
+	expectGitRemotes(t, ctx, b, []Remote{
+		githubCLICLIRemote,
+		renamedArchived,
+		renamedBar,
+		renamedHeadless,
+	})
+	expectGitRemoteGroups(t, path, map[string][]string{
+		github_com_kubernetes.RemoteGroup(): {
+			renamedArchived.Name,
+			renamedBar.Name,
+			renamedHeadless.Name,
+		},
+		github_com_orirawlings.RemoteGroup(): nil,
+	})
+	assertGitConfig(t, path, "biome.remote.github.com/kubernetes/bar.upstream", githubCLICLIRemote.Name)
+	assertConfigNotSet(t, path, "biome.remote.github.com/orirawlings/bar.upstream")
+	assertGitConfig(t, path, "remote.github.com/kubernetes/bar.url", "https://github.com/kubernetes/bar.git")
+	assertGitConfig(t, path, "remote.github.com/kubernetes/bar.fetch", "+refs/*:refs/remotes/github.com/kubernetes/bar/*")
+	expectRefs(t, ctx, path, []string{
+		fmt.Sprintf(`%s commit refs/remotes/github.com/kubernetes/bar/HEAD refs/remotes/github.com/kubernetes/bar/heads/main`, commitID),
+		fmt.Sprintf(`%s commit refs/remotes/github.com/kubernetes/bar/heads/main `, commitID),
+	})
+	out, err := exec.CommandContext(ctx, "git", "-C", path, "reflog", "show", "--format=%H", "refs/remotes/github.com/kubernetes/bar/heads/main").Output()
+	testutil.Check(t, err)
+	if entries := strings.Fields(string(out)); len(entries) < 2 {
+		t.Errorf("expected the reflog to be moved, was %q", out)
+	}
+}
+
 func addOwners(t *testing.T, ctx context.Context, b Biome, owners ...Owner) {
 	t.Helper()
 	testutil.Check(t, b.AddOwners(ctx, owners))
@@ -695,6 +1136,17 @@ func expectOwners(t *testing.T, ctx context.Context, b Biome, expected []Owner)
 	}
 }
 
+func expectViewers(t *testing.T, ctx context.Context, b Biome, expected []Viewer) {
+	t.Helper()
+	viewers, err := b.Viewers(ctx)
+	testutil.Check(t, err)
+	if !slices.EqualFunc(viewers, expected, func(a, b Viewer) bool {
+		return a.Host() == b.Host() && slices.Equal(a.Affiliations(), b.Affiliations())
+	}) {
+		t.Errorf("unexpected viewers: wanted %v, was %v", expected, viewers)
+	}
+}
+
 func expectGitRemotes(t *testing.T, ctx context.Context, b Biome, expected []Remote) {
 	t.Helper()
 	slices.SortFunc(expected, func(a, b Remote) int {
@@ -866,12 +1318,39 @@ hosts:
 		repositoriesStubs[o.String()] = gock.New(fmt.Sprintf("https://%s", host)).
 			Post("/graphql").
 			HeaderPresent("Authorization").
-			BodyString(fmt.Sprintf(`{"query":"query OwnerRepositories($endCursor:String$owner:String!){repositoryOwner(login: $owner){repositories(first: 100, after: $endCursor, affiliations: [OWNER]){nodes{isDisabled,isArchived,isLocked,url,defaultBranchRef{name,prefix}},pageInfo{hasNextPage,endCursor}}}}","variables":{"endCursor":null,"owner":%q}}`, o.Name())).
+			BodyString(fmt.Sprintf(`{"query":"query OwnerRepositories($createdAt:Boolean!$endCursor:String$owner:String!$parent:Boolean!$visibility:Boolean!){repositoryOwner(login: $owner){repositories(first: 100, after: $endCursor, affiliations: [OWNER]){nodes{isDisabled,isArchived,isLocked,url,defaultBranchRef{name,prefix},visibility @include(if: $visibility),parent @include(if: $parent){url},createdAt @include(if: $createdAt)},pageInfo{hasNextPage,endCursor}}}}","variables":{"createdAt":false,"endCursor":null,"owner":%q,"parent":true,"visibility":true}}`, o.Name())).
 			Persist().
 			Reply(200)
 
 		updateStubbedGitHubRepositories(t, o, repositories[o.String()])
 	}
+
+	for _, v := range []Viewer{github_com_viewer, my_github_biz_viewer} {
+		host := v.Host()
+		if host == "github.com" {
+			host = "api.github.com"
+		}
+
+		gock.New(fmt.Sprintf("https://%s", host)).
+			Post("/graphql").
+			HeaderPresent("Authorization").
+			BodyString(`{"query":"query Viewer{viewer{login}}"}`).
+			Persist().
+			Reply(200).
+			JSON(`{"data":{"viewer":{"login":"user1"}}}`)
+
+		marshalled, err := json.Marshal(viewerRepositories[v.Host()])
+		if err != nil {
+			t.Fatalf("could not marshal repositories for %s in stubs: %v", v, err)
+		}
+		gock.New(fmt.Sprintf("https://%s", host)).
+			Post("/graphql").
+			HeaderPresent("Authorization").
+			BodyString(fmt.Sprintf(`{"query":"query ViewerRepositories($affiliations:[RepositoryAffiliation!]!$createdAt:Boolean!$endCursor:String$parent:Boolean!$visibility:Boolean!){viewer{repositories(first: 100, after: $endCursor, affiliations: $affiliations){nodes{isDisabled,isArchived,isLocked,url,defaultBranchRef{name,prefix},visibility @include(if: $visibility),parent @include(if: $parent){url},createdAt @include(if: $createdAt)},pageInfo{hasNextPage,endCursor}}}}","variables":{"affiliations":%s,"createdAt":false,"endCursor":null,"parent":true,"visibility":true}}`, viewerAffiliations[v.Host()])).
+			Persist().
+			Reply(200).
+			JSON(fmt.Sprintf(`{"data":{"viewer":{"repositories":{"nodes":%s,"pageInfo":{"hasNextPage":false}}}}}`, marshalled))
+	}
 }
 
 func updateStubbedGitHubRepositories(t testing.TB, owner Owner, repos []repository) {
@@ -925,3 +1404,40 @@ initial commit
 	testutil.Check(t, w.Close())
 	return commitID
 }
+
+func TestBiome_cleanUpRefs_lockContention(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path: path,
+	}
+	createCommitFor(t, ctx, path, []string{
+		barRemoteCfg.Head,
+	})
+
+	// a concurrent writer, ex. a fetch, briefly holds the lock of the
+	// packed-refs file, which deletions must update. git would otherwise wait
+	// for the lock itself.
+	testutil.Execute(t, "git", "-C", path, "config", "core.packedRefsTimeout", "0")
+	lock := filepath.Join(path, "packed-refs.lock")
+	testutil.Check(t, os.WriteFile(lock, nil, 0644))
+	go func() {
+		time.Sleep(150 * time.Millisecond)
+		os.Remove(lock)
+	}()
+
+	testutil.Check(t, b.cleanUpRefs(ctx, []string{barRemote.RefNamespace()}))
+	expectRefs(t, ctx, path, nil)
+}
+
+func TestIsRefLockContention(t *testing.T) {
+	for msg, expected := range map[string]bool{
+		"fatal: cannot lock ref 'refs/remotes/github.com/cli/cli/HEAD': Unable to create '/biome/refs/remotes/github.com/cli/cli/HEAD.lock': File exists.": true,
+		"fatal: Unable to create '/biome/packed-refs.lock': File exists.":                                                                                  true,
+		"fatal: invalid ref format: refs/remotes/github.com/cli/cli/..":                                                                                    false,
+	} {
+		if isRefLockContention(errors.New(msg)) != expected {
+			t.Errorf("expected lock contention of %q to be %v", msg, expected)
+		}
+	}
+}
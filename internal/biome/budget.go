@@ -0,0 +1,58 @@
+package biome
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+)
+
+var (
+	// ErrAPIBudgetExhausted indicates that the biome's API request budget
+	// for a single update of its remotes was spent, so the discovery of some
+	// owners' or viewers' remotes was deferred to the next update.
+	ErrAPIBudgetExhausted = errors.New("API request budget exhausted")
+)
+
+const (
+	// apiBudgetKey is the git config key of the setting that limits how many
+	// API requests a single update of the biome's remotes may make.
+	apiBudgetKey = "biome.api.maxRequests"
+
+	// deferredOpt is a git config option key within the biome section that
+	// lists the remote groups whose discovery was deferred by the last update
+	// of remotes, so that the next update discovers them first.
+	deferredOpt = "deferred"
+)
+
+// apiBudget limits the number of API requests made while discovering
+// remotes. A nil budget, or a budget without a limit, is never exhausted.
+type apiBudget struct {
+	limit int
+
+	mu   sync.Mutex
+	used int
+}
+
+// newAPIBudget returns the API request budget configured for the biome.
+func newAPIBudget(cfg *config.Config) *apiBudget {
+	value, _ := getConfigValue(cfg, apiBudgetKey)
+	limit, _ := strconv.Atoi(value)
+	return &apiBudget{limit: limit}
+}
+
+// spend accounts for one API request, failing with [ErrAPIBudgetExhausted]
+// instead if the budget has been spent.
+func (b *apiBudget) spend() error {
+	if b == nil || b.limit <= 0 {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.used >= b.limit {
+		return ErrAPIBudgetExhausted
+	}
+	b.used++
+	return nil
+}
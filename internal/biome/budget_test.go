@@ -0,0 +1,25 @@
+package biome
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAPIBudget(t *testing.T) {
+	var unlimited *apiBudget
+	for range 3 {
+		if err := unlimited.spend(); err != nil {
+			t.Fatalf("unexpected error spending nil budget: %v", err)
+		}
+	}
+
+	b := &apiBudget{limit: 2}
+	for range 2 {
+		if err := b.spend(); err != nil {
+			t.Fatalf("unexpected error spending budget: %v", err)
+		}
+	}
+	if err := b.spend(); !errors.Is(err, ErrAPIBudgetExhausted) {
+		t.Errorf("expected budget to be exhausted, was %v", err)
+	}
+}
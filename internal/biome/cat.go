@@ -0,0 +1,48 @@
+package biome
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// ErrPathNotFound indicates that a path does not exist in the tree of a
+// remote's reference.
+var ErrPathNotFound = errors.New("path not found for remote")
+
+// Cat writes the content of the file at the given path in the tree at the
+// named remote's HEAD, or at the given reference of the remote, to w. The
+// reference is named relative to the remote's namespace, as with
+// [Biome.Tree].
+func (b *biome) Cat(ctx context.Context, w io.Writer, remote, ref, path string) error {
+	commit, err := b.resolveRemoteRef(ctx, remote, ref)
+	if err != nil {
+		return err
+	}
+	path = strings.TrimPrefix(path, "/")
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "-C", b.path, "rev-parse", "--verify", "--quiet", commit+":"+path)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return fmt.Errorf("%w: %s %s", ErrPathNotFound, remote, path)
+		}
+		return fmt.Errorf("could not %q: %w: %s", cmd, err, stderr.String())
+	}
+
+	stderr.Reset()
+	cmd = exec.CommandContext(ctx, "git", "-C", b.path, "cat-file", "blob", strings.TrimSpace(string(out)))
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("could not %q: %w: %s", cmd, err, stderr.String())
+	}
+	return nil
+}
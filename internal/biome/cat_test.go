@@ -0,0 +1,66 @@
+package biome
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_Cat(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, barRemote.Name)
+		return true, nil
+	}))
+
+	var blobs []string
+	for _, content := range []string{"module bar\n", "module bar/v2\n"} {
+		file := filepath.Join(t.TempDir(), "go.mod")
+		testutil.Check(t, os.WriteFile(file, []byte(content), 0o644))
+		blobs = append(blobs, strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "hash-object", "-w", file)))
+	}
+	// commitTree sets the identity that commit-tree requires
+	commitTree(t, path, "setup")
+	var commits []string
+	for _, blob := range blobs {
+		tree := mktree(t, path, fmt.Sprintf("100644 blob %s\tgo.mod\n", blob))
+		commits = append(commits, strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "commit-tree", "-m", "go.mod", tree)))
+	}
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/remotes/github.com/orirawlings/bar/heads/main", commits[0])
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/remotes/github.com/orirawlings/bar/heads/v2", commits[1])
+	testutil.Execute(t, "git", "-C", path, "symbolic-ref", barRemote.Head(), "refs/remotes/github.com/orirawlings/bar/heads/main")
+
+	for _, tc := range []struct {
+		ref      string
+		expected string
+	}{
+		{expected: "module bar\n"},
+		{ref: "v2", expected: "module bar/v2\n"},
+	} {
+		buf := new(bytes.Buffer)
+		testutil.Check(t, b.Cat(ctx, buf, barRemote.Name, tc.ref, "go.mod"))
+		if buf.String() != tc.expected {
+			t.Errorf("unexpected content at %q: wanted %q, was %q", tc.ref, tc.expected, buf.String())
+		}
+	}
+
+	if err := b.Cat(ctx, new(bytes.Buffer), barRemote.Name, "", "go.sum"); !errors.Is(err, ErrPathNotFound) {
+		t.Errorf("expected error for missing path, was %v", err)
+	}
+	if err := b.Cat(ctx, new(bytes.Buffer), barRemote.Name, "missing", "go.mod"); !errors.Is(err, errRefNotFound) {
+		t.Errorf("expected error for missing reference, was %v", err)
+	}
+}
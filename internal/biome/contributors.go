@@ -0,0 +1,108 @@
+package biome
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+// Contributor is a distinct author, or committer, of commits across the
+// biome's remotes. Contributors are identified by email address, after git's
+// mailmap is applied.
+type Contributor struct {
+
+	// Name of the contributor, as first seen in the contributor's commits.
+	Name string `json:"name"`
+
+	// Email address of the contributor.
+	Email string `json:"email"`
+
+	// Remotes maps the name of each remote whose default branch has commits
+	// by the contributor to the number of those commits. Commits shared by
+	// remotes, ex. forks, count toward each remote.
+	Remotes map[string]int `json:"remotes"`
+}
+
+// Commits returns the number of the contributor's commits across all
+// remotes, counting commits shared by remotes for each remote.
+func (c Contributor) Commits() int {
+	var n int
+	for _, commits := range c.Remotes {
+		n += commits
+	}
+	return n
+}
+
+// Contributors returns the distinct authors of the commits of the default
+// branch of each fetched remote in the given categories, or the distinct
+// committers if committers is true, sorted by their number of commits, most
+// first. If since is given, a git date, ex. "90.days.ago", only commits
+// committed since then are counted.
+func (b *biome) Contributors(ctx context.Context, since string, committers bool, categories ...RemoteCategory) ([]Contributor, error) {
+	remotes, err := b.Remotes(ctx, categories...)
+	if err != nil {
+		return nil, err
+	}
+	heads, err := b.resolveHeads(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	format := "--format=%aN%x00%aE"
+	if committers {
+		format = "--format=%cN%x00%cE"
+	}
+	contributors := make(map[string]*Contributor)
+	for _, r := range remotes {
+		commit := heads[r.Name]
+		if commit == "" {
+			continue
+		}
+		args := []string{"-C", b.path, "log", format}
+		if since != "" {
+			args = append(args, "--since="+since)
+		}
+		args = append(args, commit, "--")
+
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("could not %q: %w: %s", cmd, err, stderr.String())
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(out))
+		for scanner.Scan() {
+			name, email, _ := strings.Cut(scanner.Text(), "\x00")
+			key := strings.ToLower(email)
+			c, ok := contributors[key]
+			if !ok {
+				c = &Contributor{
+					Name:    name,
+					Email:   email,
+					Remotes: make(map[string]int),
+				}
+				contributors[key] = c
+			}
+			c.Remotes[r.Name]++
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	var result []Contributor
+	for _, key := range slices.Sorted(maps.Keys(contributors)) {
+		result = append(result, *contributors[key])
+	}
+	slices.SortStableFunc(result, func(a, b Contributor) int {
+		return cmp.Compare(b.Commits(), a.Commits())
+	})
+	return result, nil
+}
@@ -0,0 +1,82 @@
+package biome
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_Contributors(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		for _, r := range []Remote{barRemote, githubCLICLIRemote, headlessRemote} {
+			cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, r.Name)
+		}
+		return true, nil
+	}))
+
+	// bar is a fork of cli/cli, with a recent commit by another author
+	base := createCommitFor(t, ctx, path, []string{
+		"refs/remotes/github.com/cli/cli/heads/trunk",
+	})
+	commit := func(parent, author, committer string, timestamp int) string {
+		t.Helper()
+		cmd := exec.Command("git", "-C", path, "hash-object", "-t", "commit", "-w", "--stdin")
+		cmd.Stdin = strings.NewReader(fmt.Sprintf("tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\nparent %s\nauthor %s %d +0000\ncommitter %s %d +0000\n\n%s\n", parent, author, timestamp, committer, timestamp, author))
+		out, err := cmd.Output()
+		testutil.Check(t, err)
+		return strings.TrimSpace(string(out))
+	}
+	fork := commit(base, "B <b@example.com>", "B <B@example.com>", 1750000000)
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/remotes/github.com/orirawlings/bar/heads/main", fork)
+	for ref, target := range map[string]string{
+		githubCLICLIRemote.Head(): "refs/remotes/github.com/cli/cli/heads/trunk",
+		barRemote.Head():          "refs/remotes/github.com/orirawlings/bar/heads/main",
+	} {
+		testutil.Execute(t, "git", "-C", path, "symbolic-ref", ref, target)
+	}
+
+	authors, err := b.Contributors(ctx, "", false, Active)
+	testutil.Check(t, err)
+	expected := []Contributor{
+		{Name: "A", Email: "a@example.com", Remotes: map[string]int{barRemote.Name: 1, githubCLICLIRemote.Name: 1}},
+		{Name: "B", Email: "b@example.com", Remotes: map[string]int{barRemote.Name: 1}},
+	}
+	if !reflect.DeepEqual(authors, expected) {
+		t.Errorf("unexpected authors:\nwanted %+v\nwas    %+v", expected, authors)
+	}
+	if n := authors[0].Commits(); n != 2 {
+		t.Errorf("expected 2 commits by A, was %d", n)
+	}
+
+	committers, err := b.Contributors(ctx, "", true, Active)
+	testutil.Check(t, err)
+	expected = []Contributor{
+		{Name: "C", Email: "c@example.com", Remotes: map[string]int{barRemote.Name: 1, githubCLICLIRemote.Name: 1}},
+		{Name: "B", Email: "B@example.com", Remotes: map[string]int{barRemote.Name: 1}},
+	}
+	if !reflect.DeepEqual(committers, expected) {
+		t.Errorf("unexpected committers:\nwanted %+v\nwas    %+v", expected, committers)
+	}
+
+	// only commits since the given date are counted
+	authors, err = b.Contributors(ctx, "2020-01-01", false, Active)
+	testutil.Check(t, err)
+	expected = []Contributor{
+		{Name: "B", Email: "b@example.com", Remotes: map[string]int{barRemote.Name: 1}},
+	}
+	if !reflect.DeepEqual(authors, expected) {
+		t.Errorf("unexpected authors since 2020-01-01:\nwanted %+v\nwas    %+v", expected, authors)
+	}
+}
@@ -0,0 +1,55 @@
+package biome
+
+import (
+	"context"
+	"slices"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+)
+
+// CredentialSource identifies where git finds the credentials it uses to
+// fetch the biome's remotes.
+type CredentialSource string
+
+const (
+	// CredentialsGH authenticates fetches with the tokens stored by gh, ex.
+	// by 'gh auth login', through a credential helper provided by biome. The
+	// user's own git credential helpers are not consulted for the biome's
+	// hosts.
+	CredentialsGH CredentialSource = "gh"
+
+	// CredentialsGit leaves authentication to the user's own git credential
+	// configuration.
+	CredentialsGit CredentialSource = "git"
+)
+
+// credentialsKey is the git config key of the setting that selects the
+// biome's [CredentialSource].
+const credentialsKey = "biome.credentials"
+
+// credentialSources lists the valid values of [CredentialSource].
+var credentialSources = []string{string(CredentialsGH), string(CredentialsGit)}
+
+// Hosts lists the GitHub servers of the biome's owners and viewers, sorted.
+func (b *biome) Hosts(ctx context.Context) ([]string, error) {
+	var hosts []string
+	err := b.readConfig(ctx, func(ctx context.Context, cfg *config.Config) error {
+		owners, err := b.getOwners(cfg)
+		if err != nil {
+			return err
+		}
+		for _, owner := range owners {
+			hosts = append(hosts, owner.Host())
+		}
+		viewers, err := b.getViewers(cfg)
+		if err != nil {
+			return err
+		}
+		for _, viewer := range viewers {
+			hosts = append(hosts, viewer.Host())
+		}
+		return nil
+	})
+	slices.Sort(hosts)
+	return slices.Compact(hosts), err
+}
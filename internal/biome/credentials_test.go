@@ -0,0 +1,34 @@
+package biome
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_Hosts(t *testing.T) {
+	ctx := context.Background()
+	stubGitHub(t)
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+
+	hosts, err := b.Hosts(ctx)
+	testutil.Check(t, err)
+	if len(hosts) != 0 {
+		t.Errorf("expected no hosts, was %v", hosts)
+	}
+
+	testutil.Check(t, b.AddOwners(ctx, []Owner{github_com_cli, github_com_orirawlings}))
+	testutil.Check(t, b.AddViewers(ctx, []Viewer{my_github_biz_viewer}))
+	hosts, err = b.Hosts(ctx)
+	testutil.Check(t, err)
+	if expected := []string{"github.com", "my.github.biz"}; !slices.Equal(hosts, expected) {
+		t.Errorf("unexpected hosts: wanted %v, was %v", expected, hosts)
+	}
+}
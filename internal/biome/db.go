@@ -0,0 +1,451 @@
+package biome
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const (
+	// dbFile is the name of the biome's metadata database, stored next to
+	// the biome's git config file.
+	dbFile = "biome.db"
+
+	// dbSchemaVersion is the version of the metadata database's schema. It
+	// must be incremented whenever the schema changes incompatibly.
+	dbSchemaVersion = 2
+)
+
+// ErrDatabaseNotSynced indicates that the metadata database cannot be
+// queried, because it has never been synced.
+var ErrDatabaseNotSynced = errors.New("metadata database has not been synced")
+
+// dbSchema creates the tables of the metadata database. Every sync writes a
+// new database, so the database always reflects the biome's current state.
+const dbSchema = `
+CREATE TABLE meta (
+	key TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+CREATE TABLE owners (
+	owner TEXT PRIMARY KEY,
+	host TEXT NOT NULL,
+	name TEXT NOT NULL,
+	paused INTEGER NOT NULL
+);
+CREATE TABLE remotes (
+	remote TEXT PRIMARY KEY,
+	host TEXT NOT NULL,
+	owner TEXT NOT NULL,
+	repo TEXT NOT NULL,
+	archived INTEGER NOT NULL,
+	disabled INTEGER NOT NULL,
+	locked INTEGER NOT NULL,
+	internal INTEGER NOT NULL,
+	evicted INTEGER NOT NULL,
+	upstream TEXT,
+	namespace TEXT NOT NULL
+);
+CREATE TABLE refs (
+	ref TEXT PRIMARY KEY,
+	remote TEXT NOT NULL,
+	object TEXT,
+	symref TEXT
+);
+CREATE INDEX refs_remote ON refs (remote);
+CREATE INDEX refs_object ON refs (object);
+CREATE TABLE heads (
+	remote TEXT PRIMARY KEY,
+	ref TEXT NOT NULL,
+	commit_id TEXT,
+	committed_at TEXT
+);
+CREATE TABLE fetches (
+	time TEXT NOT NULL,
+	remote TEXT NOT NULL,
+	duration_ms INTEGER NOT NULL,
+	failed INTEGER NOT NULL
+);
+CREATE INDEX fetches_remote ON fetches (remote, time);
+CREATE TABLE ref_counts (
+	time TEXT NOT NULL,
+	remote TEXT NOT NULL,
+	refs INTEGER NOT NULL
+);
+CREATE INDEX ref_counts_remote ON ref_counts (remote, time);
+CREATE TABLE forced_updates (
+	time TEXT NOT NULL,
+	remote TEXT NOT NULL,
+	branch TEXT NOT NULL,
+	from_commit TEXT NOT NULL,
+	to_commit TEXT NOT NULL
+);
+CREATE INDEX forced_updates_remote ON forced_updates (remote, time);
+`
+
+// dbPath returns the path of the biome's metadata database.
+func (b *biome) dbPath(ctx context.Context) (string, error) {
+	cfgPath, err := config.Path(ctx, b.path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(cfgPath), dbFile), nil
+}
+
+// SyncDatabase materializes the biome's owners, remotes, references, the
+// commits at the remotes' HEADs, and the fetch and reference count history of
+// the journal into a SQLite database inside the biome, so that external tools
+// can query them with SQL. The database is written to a temporary file that
+// then replaces the previous database, so readers never observe a partial
+// sync. It returns the path of the database.
+func (b *biome) SyncDatabase(ctx context.Context) (string, error) {
+	path, err := b.dbPath(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	owners, err := b.Owners(ctx)
+	if err != nil {
+		return "", err
+	}
+	paused := make(map[Owner]bool)
+	if err := b.readConfig(ctx, func(ctx context.Context, cfg *config.Config) error {
+		for _, owner := range owners {
+			paused[owner], _ = ParseBool(ownerSetting(cfg, owner, "paused"))
+		}
+		return nil
+	}); err != nil {
+		return "", err
+	}
+	remotes, err := b.Remotes(ctx, AllRemoteCategories...)
+	if err != nil {
+		return "", err
+	}
+	namespaces := make(map[string]string)
+	var prefixes []string
+	for _, r := range remotes {
+		namespaces[r.RefNamespace()] = r.Name
+		prefixes = append(prefixes, r.RefNamespace())
+	}
+	refs, err := b.listRefs(ctx, prefixes)
+	if err != nil {
+		return "", err
+	}
+	refsByName := make(map[string]storedRef)
+	for _, ref := range refs {
+		refsByName[ref.Name] = ref
+	}
+	heads := make(map[string]storedRef)
+	var commits []string
+	for _, r := range remotes {
+		head, ok := refsByName[r.Head()]
+		if !ok {
+			continue
+		}
+		if head.Symref != "" {
+			head = refsByName[head.Symref]
+		}
+		heads[r.Name] = head
+		if head.ObjectName != "" {
+			commits = append(commits, head.ObjectName)
+		}
+	}
+	commitDates, err := b.commitDates(ctx, commits)
+	if err != nil {
+		return "", err
+	}
+	entries, err := b.Journal(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), dbFile+".*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("could not create metadata database: %w", err)
+	}
+	tmp := f.Name()
+	if err := f.Close(); err != nil {
+		return "", errors.Join(err, os.Remove(tmp))
+	}
+	if err := writeDatabase(ctx, tmp, func(w *dbWriter) {
+		w.insert("meta", "schema_version", strconv.Itoa(dbSchemaVersion))
+		w.insert("meta", "biome", b.path)
+		w.insert("meta", "synced_at", time.Now().UTC().Format(time.RFC3339))
+		for _, owner := range owners {
+			w.insert("owners", owner.String(), owner.Host(), owner.name, paused[owner])
+		}
+		for _, ref := range refs {
+			remote, ok := remoteOfRef(namespaces, ref.Name)
+			if !ok {
+				continue
+			}
+			w.insert("refs", ref.Name, remote, nullable(ref.ObjectName), nullable(ref.Symref))
+		}
+		for _, r := range remotes {
+			host, owner, repo := splitRemoteName(r.Name)
+			w.insert("remotes", r.Name, host, owner, repo, r.Archived, r.Disabled, r.Locked, r.Internal, r.Evicted, nullable(r.Upstream), r.RefNamespace())
+			head, ok := heads[r.Name]
+			if !ok {
+				continue
+			}
+			w.insert("heads", r.Name, r.Head(), nullable(head.ObjectName), nullable(commitDates[head.ObjectName]))
+		}
+		for _, e := range entries {
+			if e.Remote == "" {
+				continue
+			}
+			switch e.Op {
+			case FetchOp:
+				w.insert("fetches", e.Time.UTC().Format(time.RFC3339Nano), e.Remote, e.Duration.Milliseconds(), e.Failed)
+			case RefsOp:
+				w.insert("ref_counts", e.Time.UTC().Format(time.RFC3339Nano), e.Remote, int64(e.Refs))
+			case ForcedUpdateOp:
+				w.insert("forced_updates", e.Time.UTC().Format(time.RFC3339Nano), e.Remote, e.Branch, e.From, e.To)
+			}
+		}
+	}); err != nil {
+		return "", errors.Join(err, os.Remove(tmp))
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return "", errors.Join(fmt.Errorf("could not replace metadata database: %w", err), os.Remove(tmp))
+	}
+	return path, nil
+}
+
+// writeDatabase creates the tables of the metadata database in the empty
+// SQLite database at path, and inserts the rows written by write, in a single
+// transaction.
+func writeDatabase(ctx context.Context, path string, write func(w *dbWriter)) (err error) {
+	db, err := sql.Open("sqlite", dbURI(path, nil))
+	if err != nil {
+		return fmt.Errorf("could not open metadata database: %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, db.Close())
+	}()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not write metadata database: %w", err)
+	}
+	w := &dbWriter{
+		ctx:   ctx,
+		tx:    tx,
+		stmts: make(map[string]*sql.Stmt),
+	}
+	if _, w.err = tx.ExecContext(ctx, dbSchema); w.err == nil {
+		write(w)
+	}
+	if w.err != nil {
+		return errors.Join(fmt.Errorf("could not write metadata database: %w", w.err), tx.Rollback())
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not write metadata database: %w", err)
+	}
+	return nil
+}
+
+// dbWriter inserts rows into the tables of the metadata database, preparing
+// one statement per table. It records the first error, after which it
+// inserts nothing more.
+type dbWriter struct {
+	ctx   context.Context
+	tx    *sql.Tx
+	stmts map[string]*sql.Stmt
+	err   error
+}
+
+// insert inserts a row of values into table.
+func (w *dbWriter) insert(table string, values ...any) {
+	if w.err != nil {
+		return
+	}
+	stmt, ok := w.stmts[table]
+	if !ok {
+		params := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+		if stmt, w.err = w.tx.PrepareContext(w.ctx, fmt.Sprintf("INSERT INTO %s VALUES (%s)", table, params)); w.err != nil {
+			return
+		}
+		w.stmts[table] = stmt
+	}
+	_, w.err = stmt.ExecContext(w.ctx, values...)
+}
+
+// dbURI returns the SQLite URI of the database file at path, with the given
+// query parameters. The path is escaped, so that none of its characters are
+// mistaken for the URI's query.
+func dbURI(path string, params url.Values) string {
+	u := url.URL{
+		Path:     filepath.ToSlash(path),
+		RawQuery: params.Encode(),
+	}
+	return "file:" + u.String()
+}
+
+// QueryResult holds the rows selected by an SQL query of the metadata
+// database.
+type QueryResult struct {
+
+	// Columns are the names of the selected columns, in order. They are
+	// empty if no rows were selected.
+	Columns []string
+
+	// Rows hold the values of each selected row, in the order of Columns.
+	// Values are strings, numbers as [json.Number], or nil for NULL.
+	Rows [][]any
+}
+
+// Query executes read-only SQL against the metadata database, as last
+// synced by [biome.SyncDatabase], and returns the rows selected by its last
+// statement. Statements that would modify the database, or attach any other
+// database, fail.
+func (b *biome) Query(ctx context.Context, query string) (_ QueryResult, err error) {
+	path, err := b.dbPath(ctx)
+	if err != nil {
+		return QueryResult{}, err
+	}
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return QueryResult{}, ErrDatabaseNotSynced
+	} else if err != nil {
+		return QueryResult{}, err
+	}
+
+	db, err := sql.Open("sqlite", dbURI(path, url.Values{"mode": {"ro"}}))
+	if err != nil {
+		return QueryResult{}, fmt.Errorf("could not open metadata database: %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, db.Close())
+	}()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return QueryResult{}, fmt.Errorf("could not open metadata database: %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, conn.Close())
+	}()
+	if _, err := sqlite.Limit(conn, sqlite3.SQLITE_LIMIT_ATTACHED, 0); err != nil {
+		return QueryResult{}, fmt.Errorf("could not open metadata database: %w", err)
+	}
+
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return QueryResult{}, fmt.Errorf("could not query metadata database: %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, rows.Close())
+	}()
+	columns, err := rows.Columns()
+	if err != nil {
+		return QueryResult{}, fmt.Errorf("could not query metadata database: %w", err)
+	}
+	var result QueryResult
+	for rows.Next() {
+		row := make([]any, len(columns))
+		dest := make([]any, len(columns))
+		for i := range row {
+			dest[i] = &row[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return QueryResult{}, fmt.Errorf("could not query metadata database: %w", err)
+		}
+		for i, v := range row {
+			row[i] = queryValue(v)
+		}
+		result.Columns = columns
+		result.Rows = append(result.Rows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return QueryResult{}, fmt.Errorf("could not query metadata database: %w", err)
+	}
+	return result, nil
+}
+
+// queryValue converts a value scanned from the metadata database to a
+// string, a number as [json.Number], or nil for NULL.
+func queryValue(v any) any {
+	switch v := v.(type) {
+	case int64:
+		return json.Number(strconv.FormatInt(v, 10))
+	case float64:
+		return json.Number(strconv.FormatFloat(v, 'g', -1, 64))
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	default:
+		return v
+	}
+}
+
+// commitDates returns the committer dates, in strict ISO 8601 format, of the
+// given commits, by object name. Commits that are missing are omitted.
+func (b *biome) commitDates(ctx context.Context, objects []string) (map[string]string, error) {
+	dates := make(map[string]string)
+	if len(objects) == 0 {
+		return dates, nil
+	}
+	var stdin bytes.Buffer
+	for _, object := range objects {
+		fmt.Fprintln(&stdin, object)
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "-C", b.path, "log", "--no-walk=unsorted", "--ignore-missing", "--stdin", "--format=%H %cI")
+	cmd.Stdin = &stdin
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not %q: %w: %s", cmd, err, stderr.String())
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if commit, date, ok := strings.Cut(line, " "); ok {
+			dates[commit] = date
+		}
+	}
+	return dates, nil
+}
+
+// remoteOfRef returns the name of the remote whose reference namespace holds
+// the named reference.
+func remoteOfRef(namespaces map[string]string, ref string) (string, bool) {
+	for i := range len(ref) {
+		if ref[i] != '/' {
+			continue
+		}
+		if remote, ok := namespaces[ref[:i+1]]; ok {
+			return remote, true
+		}
+	}
+	return "", false
+}
+
+// splitRemoteName splits a remote name into its host, owner, and repository
+// name.
+func splitRemoteName(name string) (host, owner, repo string) {
+	if parts := strings.SplitN(name, "/", 3); len(parts) == 3 {
+		return parts[0], parts[1], parts[2]
+	}
+	return "", "", name
+}
+
+// nullable returns NULL for empty strings, or the string otherwise.
+func nullable(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,148 @@
+package biome
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_SyncDatabase(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		cfg.Section(section).AddOption(ownersOpt, github_com_orirawlings.String())
+		cfg.Section(section).Subsection(ownerSubsectionPrefix+github_com_orirawlings.String()).SetOption("paused", "true")
+		cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, barRemote.Name)
+		cfg.Section(section).Subsection(remotesSubsection).AddOption(archivedOpt, archivedRemote.Name)
+		cfg.Section(section).Subsection(remotesSubsection).AddOption(upstreamOpt, barRemote.Name+" "+githubCLICLIRemote.Name)
+		return true, nil
+	}))
+	commitID := createCommitFor(t, ctx, path, []string{
+		"refs/remotes/github.com/orirawlings/bar/heads/main",
+		"refs/remotes/github.com/orirawlings/bar/tags/v1",
+		"refs/heads/main",
+	})
+	testutil.Execute(t, "git", "-C", path, "symbolic-ref", barRemote.Head(), "refs/remotes/github.com/orirawlings/bar/heads/main")
+	testutil.Check(t, b.Record(ctx,
+		JournalEntry{Time: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), Op: FetchOp, Remote: barRemote.Name, Duration: 1500 * time.Millisecond},
+		JournalEntry{Time: time.Date(2025, 6, 1, 0, 0, 2, 0, time.UTC), Op: RefsOp, Remote: barRemote.Name, Refs: 2},
+		JournalEntry{Time: time.Date(2025, 6, 1, 0, 0, 3, 0, time.UTC), Op: ForcedUpdateOp, Remote: barRemote.Name, Branch: "main", From: "abc", To: "def"},
+	))
+
+	db, err := b.SyncDatabase(ctx)
+	testutil.Check(t, err)
+
+	// syncing again replaces the database, leaving no temporary files behind
+	_, err = b.SyncDatabase(ctx)
+	testutil.Check(t, err)
+	if tmps, err := filepath.Glob(db + ".*"); err != nil || len(tmps) != 0 {
+		t.Errorf("expected no temporary database files, was %v (%v)", tmps, err)
+	}
+
+	for query, expected := range map[string]string{
+		"SELECT value FROM meta WHERE key = 'schema_version'":                                     "2",
+		"SELECT owner, host, name, paused FROM owners":                                            "github.com/orirawlings|github.com|orirawlings|1",
+		"SELECT remote, owner, repo, archived, ifnull(upstream, '') FROM remotes ORDER BY remote": "github.com/orirawlings/archived|orirawlings|archived|1|\ngithub.com/orirawlings/bar|orirawlings|bar|0|github.com/cli/cli",
+		"SELECT ref, remote, ifnull(object, ''), ifnull(symref, '') FROM refs ORDER BY ref":       "refs/remotes/github.com/orirawlings/bar/HEAD|github.com/orirawlings/bar||refs/remotes/github.com/orirawlings/bar/heads/main\nrefs/remotes/github.com/orirawlings/bar/heads/main|github.com/orirawlings/bar|" + commitID + "|\nrefs/remotes/github.com/orirawlings/bar/tags/v1|github.com/orirawlings/bar|" + commitID + "|",
+		"SELECT remote, commit_id, committed_at FROM heads":                                       "github.com/orirawlings/bar|" + commitID + "|1970-01-01T00:00:00+00:00",
+		"SELECT time, remote, duration_ms, failed FROM fetches":                                   "2025-06-01T00:00:00Z|github.com/orirawlings/bar|1500|0",
+		"SELECT time, remote, refs FROM ref_counts":                                               "2025-06-01T00:00:02Z|github.com/orirawlings/bar|2",
+		"SELECT time, remote, branch, from_commit, to_commit FROM forced_updates":                 "2025-06-01T00:00:03Z|github.com/orirawlings/bar|main|abc|def",
+	} {
+		result, err := b.Query(ctx, query)
+		testutil.Check(t, err)
+		var lines []string
+		for _, row := range result.Rows {
+			values := make([]string, 0, len(row))
+			for _, v := range row {
+				values = append(values, fmt.Sprint(v))
+			}
+			lines = append(lines, strings.Join(values, "|"))
+		}
+		if out := strings.Join(lines, "\n"); out != expected {
+			t.Errorf("unexpected result of %q:\nwanted %q\nwas    %q", query, expected, out)
+		}
+	}
+}
+
+func TestBiome_Query(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		cfg.Section(section).AddOption(ownersOpt, github_com_orirawlings.String())
+		cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, barRemote.Name)
+		cfg.Section(section).Subsection(remotesSubsection).AddOption(archivedOpt, archivedRemote.Name)
+		return true, nil
+	}))
+
+	if _, err := b.Query(ctx, "SELECT * FROM remotes"); !errors.Is(err, ErrDatabaseNotSynced) {
+		t.Fatalf("expected %v querying before sync, was %v", ErrDatabaseNotSynced, err)
+	}
+
+	db, err := b.SyncDatabase(ctx)
+	testutil.Check(t, err)
+
+	result, err := b.Query(ctx, "SELECT remote, archived, upstream FROM remotes ORDER BY remote")
+	testutil.Check(t, err)
+	expected := QueryResult{
+		Columns: []string{"remote", "archived", "upstream"},
+		Rows: [][]any{
+			{archivedRemote.Name, json.Number("1"), nil},
+			{barRemote.Name, json.Number("0"), nil},
+		},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("unexpected query result:\nwanted %v\nwas    %v", expected, result)
+	}
+
+	result, err = b.Query(ctx, "SELECT * FROM remotes WHERE archived AND NOT archived")
+	testutil.Check(t, err)
+	if len(result.Columns) != 0 || len(result.Rows) != 0 {
+		t.Errorf("expected empty query result, was %v", result)
+	}
+
+	result, err = b.Query(ctx, "SELECT 1 AS a; SELECT remote FROM remotes WHERE archived")
+	testutil.Check(t, err)
+	expected = QueryResult{
+		Columns: []string{"remote"},
+		Rows:    [][]any{{archivedRemote.Name}},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("unexpected result of last statement:\nwanted %v\nwas    %v", expected, result)
+	}
+
+	for _, query := range []string{
+		"DELETE FROM remotes",
+		"PRAGMA query_only = 0; DELETE FROM remotes",
+		".shell touch pwned",
+		"ATTACH DATABASE 'other.db' AS other",
+		"ATTACH DATABASE '" + db + "' AS other",
+		"VACUUM INTO '" + filepath.Join(t.TempDir(), "copy.db") + "'",
+	} {
+		if _, err := b.Query(ctx, query); err == nil {
+			t.Errorf("expected error executing %q", query)
+		}
+	}
+	result, err = b.Query(ctx, "SELECT count(*) AS n FROM remotes")
+	testutil.Check(t, err)
+	if n := result.Rows[0][0]; n != json.Number("2") {
+		t.Errorf("expected remotes to be unmodified, was %v", n)
+	}
+}
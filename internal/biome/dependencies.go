@@ -0,0 +1,87 @@
+package biome
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// DependencyFiles are the names of the dependency manifests that
+// [biome.DependencyManifests] collects by default.
+var DependencyFiles = []string{"go.mod", "package.json", "requirements.txt", "pom.xml"}
+
+// vendoredDirs are directories of vendored dependencies, whose manifests
+// describe the dependencies, rather than the remote's repository.
+var vendoredDirs = []string{"vendor", "node_modules", "third_party"}
+
+// DependencyManifest is a dependency manifest file, ex. go.mod, found at the
+// HEAD of a remote.
+type DependencyManifest struct {
+
+	// Remote is the name of the remote, ex. github.com/cli/cli.
+	Remote string `json:"remote"`
+
+	// Commit is the object ID of the commit at the remote's HEAD.
+	Commit string `json:"commit"`
+
+	// Path is the path of the file, relative to the root of the repository.
+	Path string `json:"path"`
+
+	// Content is the content of the file.
+	Content string `json:"content"`
+}
+
+// DependencyManifests returns the dependency manifests at the HEAD of each
+// of the biome's fetchable remotes, or only of the remotes of the given
+// owners, sorted by remote. Files are collected anywhere in the remote's
+// tree if their name is one of names, except within directories of vendored
+// dependencies, ex. vendor/ and node_modules/. Remotes whose default branch
+// has not been fetched are skipped.
+func (b *biome) DependencyManifests(ctx context.Context, names []string, owners ...Owner) ([]DependencyManifest, error) {
+	projects, err := b.Manifest(ctx, owners...)
+	if err != nil {
+		return nil, err
+	}
+	var manifests []DependencyManifest
+	var blobs []string
+	for _, p := range projects {
+		if p.Commit == "" {
+			continue
+		}
+		entries, err := b.lsTree(ctx, p.Commit, "", true)
+		if err != nil {
+			return nil, fmt.Errorf("could not list files of %s: %w", p.Remote, err)
+		}
+		for _, e := range entries {
+			if e.Type != "blob" || !slices.Contains(names, path.Base(e.Path)) || vendored(e.Path) {
+				continue
+			}
+			manifests = append(manifests, DependencyManifest{
+				Remote: p.Remote,
+				Commit: p.Commit,
+				Path:   e.Path,
+			})
+			blobs = append(blobs, e.Object)
+		}
+	}
+	if err := b.readBlobs(ctx, blobs, func(i int, content []byte) error {
+		manifests[i].Content = string(content)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return manifests, nil
+}
+
+// vendored reports whether the path is within a directory of vendored
+// dependencies.
+func vendored(p string) bool {
+	for _, dir := range strings.Split(path.Dir(p), "/") {
+		if slices.Contains(vendoredDirs, dir) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package biome
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_DependencyManifests(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		for _, r := range []Remote{barRemote, headlessRemote} {
+			cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, r.Name)
+		}
+		return true, nil
+	}))
+
+	hashObject := func(content string) string {
+		file := filepath.Join(t.TempDir(), "file")
+		testutil.Check(t, os.WriteFile(file, []byte(content), 0o644))
+		return strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "hash-object", "-w", file))
+	}
+	goMod := "module github.com/orirawlings/bar\n"
+	packageJSON := `{"name": "bar"}` + "\n"
+	vendoredGoMod := hashObject("module example.com/dep\n")
+	dep := mktree(t, path, fmt.Sprintf("100644 blob %s\tgo.mod\n", vendoredGoMod))
+	vendor := mktree(t, path, fmt.Sprintf("040000 tree %s\tdep\n", dep))
+	web := mktree(t, path, fmt.Sprintf("100644 blob %s\tpackage.json\n", hashObject(packageJSON)))
+	root := mktree(t, path, fmt.Sprintf("100644 blob %s\tREADME.md\n100644 blob %s\tgo.mod\n040000 tree %s\tvendor\n040000 tree %s\tweb\n",
+		hashObject("# bar\n"), hashObject(goMod), vendor, web))
+	commit := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "-c", "user.name=A", "-c", "user.email=a@example.com", "commit-tree", "-m", "deps", root))
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/remotes/github.com/orirawlings/bar/heads/main", commit)
+	testutil.Execute(t, "git", "-C", path, "symbolic-ref", barRemote.Head(), "refs/remotes/github.com/orirawlings/bar/heads/main")
+
+	for _, tc := range []struct {
+		name     string
+		files    []string
+		owners   []Owner
+		expected []DependencyManifest
+	}{
+		{
+			name:  "default files",
+			files: DependencyFiles,
+			expected: []DependencyManifest{
+				{Remote: barRemote.Name, Commit: commit, Path: "go.mod", Content: goMod},
+				{Remote: barRemote.Name, Commit: commit, Path: "web/package.json", Content: packageJSON},
+			},
+		},
+		{
+			name:   "owner and file",
+			files:  []string{"package.json"},
+			owners: []Owner{github_com_orirawlings},
+			expected: []DependencyManifest{
+				{Remote: barRemote.Name, Commit: commit, Path: "web/package.json", Content: packageJSON},
+			},
+		},
+		{
+			name:   "other owner",
+			files:  DependencyFiles,
+			owners: []Owner{github_com_cli},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			manifests, err := b.DependencyManifests(ctx, tc.files, tc.owners...)
+			testutil.Check(t, err)
+			if !reflect.DeepEqual(manifests, tc.expected) {
+				t.Errorf("unexpected dependency manifests: wanted %+v, was %+v", tc.expected, manifests)
+			}
+		})
+	}
+}
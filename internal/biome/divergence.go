@@ -0,0 +1,86 @@
+package biome
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Divergence compares the default branch of a fork with the default branch
+// of its upstream.
+type Divergence struct {
+
+	// Fork is the name of the forked remote.
+	Fork string `json:"fork"`
+
+	// Upstream is the name of the remote that Fork was forked from.
+	Upstream string `json:"upstream"`
+
+	// Ahead is the number of commits on the fork's default branch that are
+	// not on the upstream's default branch.
+	Ahead int `json:"ahead"`
+
+	// Behind is the number of commits on the upstream's default branch that
+	// are not on the fork's default branch.
+	Behind int `json:"behind"`
+}
+
+// ForkDivergence reports how far the default branch of each fetched fork has
+// diverged from the default branch of its upstream, for forks whose upstream
+// has also been fetched into the biome, sorted by fork name.
+func (b *biome) ForkDivergence(ctx context.Context) ([]Divergence, error) {
+	heads, err := b.resolveHeads(ctx)
+	if err != nil {
+		return nil, err
+	}
+	remotes, err := b.Remotes(ctx, FetchableRemoteCategories...)
+	if err != nil {
+		return nil, err
+	}
+	var result []Divergence
+	for _, r := range remotes {
+		fork, upstream := heads[r.Name], heads[r.Upstream]
+		if r.Upstream == "" || fork == "" || upstream == "" {
+			continue
+		}
+		ahead, behind, err := b.aheadBehind(ctx, fork, upstream)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, Divergence{
+			Fork:     r.Name,
+			Upstream: r.Upstream,
+			Ahead:    ahead,
+			Behind:   behind,
+		})
+	}
+	return result, nil
+}
+
+// aheadBehind counts the commits reachable from commit but not from other,
+// and the commits reachable from other but not from commit.
+func (b *biome) aheadBehind(ctx context.Context, commit, other string) (int, int, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "-C", b.path, "rev-list", "--left-right", "--count", commit+"..."+other)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, 0, fmt.Errorf("could not %q: %w: %s", cmd, err, stderr.String())
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("could not parse output of %q: %q", cmd, out)
+	}
+	ahead, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("could not parse output of %q: %w", cmd, err)
+	}
+	behind, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("could not parse output of %q: %w", cmd, err)
+	}
+	return ahead, behind, nil
+}
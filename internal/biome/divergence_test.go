@@ -0,0 +1,74 @@
+package biome
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_ForkDivergence(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		ss := cfg.Section(section).Subsection(remotesSubsection)
+		for _, r := range []Remote{barRemote, githubCLICLIRemote, headlessRemote} {
+			ss.AddOption(activeOpt, r.Name)
+		}
+		ss.AddOption(upstreamOpt, barRemote.Name+" "+githubCLICLIRemote.Name)
+		// upstreams that are not fetched are skipped
+		ss.AddOption(upstreamOpt, headlessRemote.Name+" "+githubCLICLIRemote.Name)
+		return true, nil
+	}))
+
+	remotes, err := b.Remotes(ctx, Active)
+	testutil.Check(t, err)
+	expectedRemotes := []Remote{
+		{Name: githubCLICLIRemote.Name},
+		{Name: barRemote.Name, Upstream: githubCLICLIRemote.Name},
+		{Name: headlessRemote.Name, Upstream: githubCLICLIRemote.Name},
+	}
+	if !slices.Equal(remotes, expectedRemotes) {
+		t.Errorf("unexpected remotes: wanted %v, was %v", expectedRemotes, remotes)
+	}
+
+	// upstreams recorded explicitly override discovered upstreams
+	testutil.Check(t, b.SetSetting(ctx, RemoteSettingKey(headlessRemote.Name, "upstream"), barRemote.Name))
+	remotes, err = b.Remotes(ctx, Active)
+	testutil.Check(t, err)
+	expectedRemotes[2].Upstream = barRemote.Name
+	if !slices.Equal(remotes, expectedRemotes) {
+		t.Errorf("unexpected remotes: wanted %v, was %v", expectedRemotes, remotes)
+	}
+	settings, err := b.ListSettings(ctx)
+	testutil.Check(t, err)
+	if !slices.ContainsFunc(settings, func(v SettingValue) bool {
+		return v.Key == RemoteSettingKey(headlessRemote.Name, "upstream") && v.Value == barRemote.Name
+	}) {
+		t.Errorf("expected upstream setting to be listed, was %v", settings)
+	}
+
+	// bar has two commits that cli/cli does not, and is missing one
+	base := commitTree(t, path, "base")
+	upstream := commitTree(t, path, "upstream", base)
+	fork := commitTree(t, path, "fork 2", commitTree(t, path, "fork 1", base))
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/remotes/github.com/cli/cli/heads/trunk", upstream)
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/remotes/github.com/orirawlings/bar/heads/main", fork)
+	testutil.Execute(t, "git", "-C", path, "symbolic-ref", githubCLICLIRemote.Head(), "refs/remotes/github.com/cli/cli/heads/trunk")
+	testutil.Execute(t, "git", "-C", path, "symbolic-ref", barRemote.Head(), "refs/remotes/github.com/orirawlings/bar/heads/main")
+
+	report, err := b.ForkDivergence(ctx)
+	testutil.Check(t, err)
+	expected := []Divergence{
+		{Fork: barRemote.Name, Upstream: githubCLICLIRemote.Name, Ahead: 2, Behind: 1},
+	}
+	if !slices.Equal(report, expected) {
+		t.Errorf("unexpected divergence: wanted %v, was %v", expected, report)
+	}
+}
@@ -0,0 +1,260 @@
+package biome
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+)
+
+const (
+	// bundleOpt is a git config option key within a remote's subsection of
+	// the biome section that records the git bundle that an evicted remote's
+	// references were exported to.
+	bundleOpt = "bundle"
+
+	// bundleNamespaceOpt is a git config option key within a remote's
+	// subsection of the biome section that records the reference namespace
+	// of the references in an evicted remote's bundle, so that they can be
+	// restored to the remote's namespace, even if it changed since, ex.
+	// because the remote's owner was renamed.
+	bundleNamespaceOpt = "bundleNamespace"
+
+	// evictedOpt is the key of evicted remotes in the remotes index.
+	evictedOpt = "evicted"
+
+	// bundlesDir is the directory within the git directory where bundles of
+	// evicted remotes are stored, unless another directory is given.
+	bundlesDir = "biome-bundles"
+)
+
+var (
+	// errNotEvicted indicates that a remote has not been evicted, so it
+	// cannot be restored.
+	errNotEvicted = errors.New("remote was not evicted")
+)
+
+// Eviction describes a remote whose references were exported to a git bundle
+// and removed from the biome.
+type Eviction struct {
+
+	// Remote is the name of the evicted remote.
+	Remote string
+
+	// Bundle is the path of the git bundle holding the remote's references.
+	Bundle string
+
+	// Refs is the number of references exported to, or restored from, the
+	// bundle.
+	Refs int
+}
+
+// evictedBundle returns the path of the bundle that the named remote was
+// evicted to, from a loaded config, or an empty string if the remote was not
+// evicted.
+func evictedBundle(cfg *config.Config, remote string) string {
+	if !cfg.Section(section).HasSubsection(remoteSubsectionPrefix + remote) {
+		return ""
+	}
+	return cfg.Section(section).Subsection(remoteSubsectionPrefix + remote).Options.Get(bundleOpt)
+}
+
+// Evict exports the references of each of the named remotes to a git bundle
+// in dir, then removes the remote's references and git remote configuration,
+// so the remote is no longer fetched. The disk used by objects that are only
+// reachable from the evicted remotes is reclaimed by the next garbage
+// collection. If dir is empty, bundles are stored in the biome's git
+// directory. Evicted remotes can be brought back with [biome.Restore].
+func (b *biome) Evict(ctx context.Context, dir string, remotes ...string) ([]Eviction, error) {
+	if dir == "" {
+		gitDir, err := b.gitDir(ctx)
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(gitDir, bundlesDir)
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := make(map[string]string)
+	if err := b.readConfig(ctx, func(ctx context.Context, cfg *config.Config) error {
+		var errs []error
+		for _, remote := range remotes {
+			if !cfg.Section("remote").HasSubsection(remote) {
+				errs = append(errs, fmt.Errorf("remote is not configured: %s", remote))
+				continue
+			}
+			namespace, ok := refNamespaceOf(cfg.Section("remote").Subsection(remote).Options.Get("fetch"))
+			if !ok {
+				errs = append(errs, fmt.Errorf("could not determine reference namespace of remote: %s", remote))
+				continue
+			}
+			namespaces[remote] = namespace
+		}
+		return errors.Join(errs...)
+	}); err != nil {
+		return nil, err
+	}
+
+	var evictions []Eviction
+	for _, remote := range remotes {
+		eviction, err := b.exportBundle(ctx, remote, namespaces[remote], filepath.Join(dir, filepath.FromSlash(remote)+".bundle"))
+		if err != nil {
+			return evictions, err
+		}
+
+		// forget the remote, so it is not fetched, until it is restored
+		if err := b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+			cfg.Section(section).Subsection(remoteSubsectionPrefix+remote).SetOption(bundleOpt, eviction.Bundle)
+			cfg.Section(section).Subsection(remoteSubsectionPrefix+remote).SetOption(bundleNamespaceOpt, namespaces[remote])
+			cfg.Section("remote").RemoveSubsection(remote)
+			groups := cfg.Section("remotes")
+			kept := groups.Options[:0]
+			for _, opt := range groups.Options {
+				if opt.Value != remote {
+					kept = append(kept, opt)
+				}
+			}
+			groups.Options = kept
+			return true, nil
+		}); err != nil {
+			return evictions, fmt.Errorf("could not record eviction of %s: %w", remote, err)
+		}
+		if err := b.cleanUpRefs(ctx, []string{namespaces[remote]}); err != nil {
+			return evictions, fmt.Errorf("could not remove references of %s: %w", remote, err)
+		}
+		evictions = append(evictions, eviction)
+	}
+	return evictions, nil
+}
+
+// exportBundle writes the references under the remote's reference namespace
+// to a git bundle at the given path. Symbolic references, like HEAD, are not
+// exported, since they are set again when remotes are updated.
+func (b *biome) exportBundle(ctx context.Context, remote, namespace, path string) (Eviction, error) {
+	eviction := Eviction{
+		Remote: remote,
+		Bundle: path,
+	}
+	refs, err := b.listRefs(ctx, []string{namespace})
+	if err != nil {
+		return eviction, err
+	}
+	var stdin bytes.Buffer
+	for _, r := range refs {
+		if r.Symref == "" {
+			fmt.Fprintln(&stdin, r.Name)
+			eviction.Refs++
+		}
+	}
+	if eviction.Refs == 0 {
+		return eviction, fmt.Errorf("remote has no references to evict, fetch it first: %s", remote)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return eviction, fmt.Errorf("could not create directory for bundle: %w", err)
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "-C", b.path, "bundle", "create", "--quiet", path, "--stdin")
+	cmd.Stdin = &stdin
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return eviction, fmt.Errorf("could not %q: %w: %s", cmd, err, stderr.String())
+	}
+	return eviction, nil
+}
+
+// Restore imports the references of each of the named evicted remotes from
+// the git bundles they were evicted to, into the remotes' current reference
+// namespaces, and removes the bundles. The remotes are configured as git
+// remotes again by the next [biome.UpdateRemotes].
+func (b *biome) Restore(ctx context.Context, remotes ...string) ([]Eviction, error) {
+	bundles := make(map[string]string)
+	from := make(map[string]string)
+	to := make(map[string]string)
+	if err := b.readConfig(ctx, func(ctx context.Context, cfg *config.Config) error {
+		var errs []error
+		for _, remote := range remotes {
+			if bundles[remote] = evictedBundle(cfg, remote); bundles[remote] == "" {
+				errs = append(errs, fmt.Errorf("%w: %s", errNotEvicted, remote))
+				continue
+			}
+			// bundles of earlier evictions did not record their namespace,
+			// so their references are restored as they are
+			from[remote] = cfg.Section(section).Subsection(remoteSubsectionPrefix + remote).Options.Get(bundleNamespaceOpt)
+			to[remote] = Remote{Name: remote, refTemplate: refTemplate(cfg)}.RefNamespace()
+		}
+		return errors.Join(errs...)
+	}); err != nil {
+		return nil, err
+	}
+
+	var restored []Eviction
+	for _, remote := range remotes {
+		eviction, err := b.importBundle(ctx, remote, bundles[remote], from[remote], to[remote])
+		if err != nil {
+			return restored, err
+		}
+		if err := b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+			cfg.Section(section).Subsection(remoteSubsectionPrefix + remote).
+				RemoveOption(bundleOpt).
+				RemoveOption(bundleNamespaceOpt)
+			return true, nil
+		}); err != nil {
+			return restored, fmt.Errorf("could not record restoration of %s: %w", remote, err)
+		}
+		if err := os.Remove(eviction.Bundle); err != nil && !os.IsNotExist(err) {
+			return restored, fmt.Errorf("could not remove bundle of %s: %w", remote, err)
+		}
+		restored = append(restored, eviction)
+	}
+	return restored, nil
+}
+
+// importBundle stores the objects of a git bundle in the biome, and creates
+// the references recorded in the bundle. References under the from namespace
+// are created under the to namespace instead.
+func (b *biome) importBundle(ctx context.Context, remote, path, from, to string) (Eviction, error) {
+	eviction := Eviction{
+		Remote: remote,
+		Bundle: path,
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "-C", b.path, "bundle", "unbundle", path)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return eviction, fmt.Errorf("could not %q: %w: %s", cmd, err, stderr.String())
+	}
+
+	w, err := b.updateRefs(ctx)
+	if err != nil {
+		return eviction, err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		objectName, name, ok := strings.Cut(scanner.Text(), " ")
+		if !ok {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(name, from); ok && from != "" {
+			name = to + rest
+		}
+		if _, err := fmt.Fprintf(w, "update %s %s\n", name, objectName); err != nil {
+			return eviction, fmt.Errorf("could not restore %s: %w", name, err)
+		}
+		eviction.Refs++
+	}
+	if err := scanner.Err(); err != nil {
+		return eviction, err
+	}
+	return eviction, w.Close()
+}
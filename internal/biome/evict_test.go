@@ -0,0 +1,144 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_Evict(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		for _, r := range []Remote{barRemote, archivedRemote} {
+			refspec, err := r.FetchRefspec()
+			if err != nil {
+				return false, err
+			}
+			cfg.Section("remote").Subsection(r.Name).SetOption("url", r.FetchURL())
+			cfg.Section("remote").Subsection(r.Name).SetOption("fetch", refspec)
+			cfg.Section("remotes").AddOption(github_com_orirawlings.RemoteGroup(), r.Name)
+		}
+		cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, barRemote.Name)
+		cfg.Section(section).Subsection(remotesSubsection).AddOption(archivedOpt, archivedRemote.Name)
+		return true, nil
+	}))
+	commitID := createCommitFor(t, ctx, path, []string{
+		"refs/remotes/github.com/orirawlings/archived/heads/master",
+		"refs/remotes/github.com/orirawlings/archived/tags/v1",
+		"refs/remotes/github.com/orirawlings/bar/heads/main",
+	})
+
+	// remotes without references cannot be evicted
+	_, err := b.Evict(ctx, "", githubCLICLIRemote.Name)
+	testutil.ExpectError(t, err)
+
+	dir := t.TempDir()
+	evictions, err := b.Evict(ctx, dir, archivedRemote.Name)
+	testutil.Check(t, err)
+	bundle := filepath.Join(dir, "github.com", "orirawlings", "archived.bundle")
+	if expected := []Eviction{{Remote: archivedRemote.Name, Bundle: bundle, Refs: 2}}; !slices.Equal(evictions, expected) {
+		t.Errorf("unexpected evictions: wanted %+v, was %+v", expected, evictions)
+	}
+	if _, err := os.Stat(bundle); err != nil {
+		t.Errorf("expected bundle to be written: %v", err)
+	}
+	expectRefs(t, ctx, path, []string{
+		fmt.Sprintf(`%s commit refs/remotes/github.com/orirawlings/bar/heads/main `, commitID),
+	})
+	testutil.Check(t, b.readConfig(ctx, func(ctx context.Context, cfg *config.Config) error {
+		if cfg.Section("remote").HasSubsection(archivedRemote.Name) {
+			t.Errorf("expected git remote %s to be removed", archivedRemote.Name)
+		}
+		if groups := cfg.Section("remotes").OptionAll(github_com_orirawlings.RemoteGroup()); !slices.Equal(groups, []string{barRemote.Name}) {
+			t.Errorf("unexpected members of remote group: %v", groups)
+		}
+		if evictedBundle(cfg, archivedRemote.Name) != bundle {
+			t.Errorf("expected bundle of %s to be recorded", archivedRemote.Name)
+		}
+		return nil
+	}))
+
+	// evicted remotes are only in the evicted category
+	for category, expected := range map[RemoteCategory][]Remote{
+		Archived: nil,
+		Evicted:  {{Name: archivedRemote.Name, Archived: true, Evicted: true}},
+	} {
+		remotes, err := b.Remotes(ctx, category)
+		testutil.Check(t, err)
+		if !slices.Equal(remotes, expected) {
+			t.Errorf("unexpected %s remotes: wanted %+v, was %+v", category, expected, remotes)
+		}
+	}
+
+	// only evicted remotes can be restored
+	_, err = b.Restore(ctx, barRemote.Name)
+	if !errors.Is(err, errNotEvicted) {
+		t.Errorf("expected %v, was %v", errNotEvicted, err)
+	}
+
+	restored, err := b.Restore(ctx, archivedRemote.Name)
+	testutil.Check(t, err)
+	if expected := []Eviction{{Remote: archivedRemote.Name, Bundle: bundle, Refs: 2}}; !slices.Equal(restored, expected) {
+		t.Errorf("unexpected restorations: wanted %+v, was %+v", expected, restored)
+	}
+	if _, err := os.Stat(bundle); !os.IsNotExist(err) {
+		t.Errorf("expected bundle to be removed, was %v", err)
+	}
+	expectRefs(t, ctx, path, []string{
+		fmt.Sprintf(`%s commit refs/remotes/github.com/orirawlings/archived/heads/master `, commitID),
+		fmt.Sprintf(`%s commit refs/remotes/github.com/orirawlings/archived/tags/v1 `, commitID),
+		fmt.Sprintf(`%s commit refs/remotes/github.com/orirawlings/bar/heads/main `, commitID),
+	})
+	remotes, err := b.Remotes(ctx, Archived)
+	testutil.Check(t, err)
+	if expected := []Remote{{Name: archivedRemote.Name, Archived: true}}; !slices.Equal(remotes, expected) {
+		t.Errorf("unexpected archived remotes: wanted %+v, was %+v", expected, remotes)
+	}
+}
+
+func TestBiome_Restore_renamedOwner(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+	b := initBiome(t, ctx, path, true)
+
+	commitID := createCommitFor(t, ctx, path, []string{
+		barRemoteCfg.Head,
+	})
+	addOwners(t, ctx, b, github_com_orirawlings)
+	testutil.Check(t, b.UpdateRemotes(ctx))
+	_, err := b.Evict(ctx, "", barRemote.Name)
+	testutil.Check(t, err)
+
+	// the eviction follows the remote when its owner is renamed
+	testutil.Check(t, b.RenameOwner(ctx, github_com_orirawlings, github_com_kubernetes))
+	renamedBar := Remote{Name: "github.com/kubernetes/bar", Evicted: true}
+	remotes, err := b.Remotes(ctx, Evicted)
+	testutil.Check(t, err)
+	if expected := []Remote{renamedBar}; !slices.Equal(remotes, expected) {
+		t.Errorf("unexpected evicted remotes: wanted %+v, was %+v", expected, remotes)
+	}
+
+	// the references are restored into the renamed remote's namespace
+	restored, err := b.Restore(ctx, renamedBar.Name)
+	testutil.Check(t, err)
+	if len(restored) != 1 || restored[0].Refs != 1 {
+		t.Errorf("unexpected restorations: %+v", restored)
+	}
+	expectRefs(t, ctx, path, []string{
+		fmt.Sprintf(`%s commit refs/remotes/github.com/kubernetes/bar/heads/main `, commitID),
+	})
+	assertConfigNotSet(t, path, "biome.remote.github.com/kubernetes/bar.bundle")
+	assertConfigNotSet(t, path, "biome.remote.github.com/kubernetes/bar.bundleNamespace")
+}
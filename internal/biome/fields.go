@@ -0,0 +1,83 @@
+package biome
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+
+	graphql "github.com/cli/shurcooL-graphql"
+)
+
+// apiFieldsKey is the git config key of the setting that lists the optional
+// repository fields requested from GitHub when discovering remotes.
+const apiFieldsKey = "biome.api.fields"
+
+const (
+	// parentField requests the repository a fork was forked from, recorded
+	// as the remote's upstream.
+	parentField = "parent"
+
+	// visibilityField requests the repository's visibility, recording
+	// whether the remote is internal.
+	visibilityField = "visibility"
+
+	// defaultAPIFields enables all optional repository fields.
+	defaultAPIFields = parentField + "," + visibilityField
+)
+
+// createdAfterOpt is the per-owner setting option that limits the owner's
+// remotes to repositories created on or after a date, ex. 2023-01-01. The
+// repository's creation date is only requested from GitHub for owners with
+// this setting.
+const createdAfterOpt = "createdAfter"
+
+// optionalFields lists the repository fields that are only requested from
+// GitHub when they are enabled by the biome.api.fields setting.
+var optionalFields = []string{parentField, visibilityField}
+
+// repositoryFields selects which optional repository fields are requested
+// from GitHub's GraphQL API, so that users who only need the URLs and
+// default branches of repositories do not pay for the rest.
+type repositoryFields struct {
+	parent     bool
+	visibility bool
+
+	// createdAt requests the repository's creation date. It is not listed
+	// in biome.api.fields, since it is requested for owners with the
+	// createdAfter setting.
+	createdAt bool
+}
+
+// newRepositoryFields returns the optional repository fields enabled for the
+// biome.
+func newRepositoryFields(cfg *config.Config) repositoryFields {
+	value, ok := getConfigValue(cfg, apiFieldsKey)
+	if !ok {
+		value = defaultAPIFields
+	}
+	fields := splitList(value)
+	return repositoryFields{
+		parent:     slices.Contains(fields, parentField),
+		visibility: slices.Contains(fields, visibilityField),
+	}
+}
+
+// addVariables sets the GraphQL variables that include or skip each optional
+// field of [repository] in a query.
+func (f repositoryFields) addVariables(variables map[string]interface{}) {
+	variables["createdAt"] = graphql.Boolean(f.createdAt)
+	variables["parent"] = graphql.Boolean(f.parent)
+	variables["visibility"] = graphql.Boolean(f.visibility)
+}
+
+// validateAPIFields ensures that a value only lists optional repository
+// fields.
+func validateAPIFields(value string) error {
+	for _, field := range splitList(value) {
+		if !slices.Contains(optionalFields, field) {
+			return fmt.Errorf("unknown field %q, must be a comma separated list of %s", field, optionalFields)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package biome
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestNewRepositoryFields(t *testing.T) {
+	for _, tc := range []struct {
+		value    string
+		set      bool
+		expected repositoryFields
+	}{
+		{
+			expected: repositoryFields{parent: true, visibility: true},
+		},
+		{
+			set:      true,
+			expected: repositoryFields{},
+		},
+		{
+			value:    "visibility",
+			set:      true,
+			expected: repositoryFields{visibility: true},
+		},
+	} {
+		cfg := new(config.Config)
+		if tc.set {
+			setConfigValue(cfg, apiFieldsKey, tc.value)
+		}
+		if actual := newRepositoryFields(cfg); actual != tc.expected {
+			t.Errorf("unexpected fields for %q: wanted %+v, was %+v", tc.value, tc.expected, actual)
+		}
+	}
+
+	testutil.Check(t, validateAPIFields("parent, visibility"))
+	testutil.ExpectError(t, validateAPIFields("parent,topics"))
+}
+
+func TestBuildRemoteConfigs_fields(t *testing.T) {
+	ctx := context.Background()
+	stubGitHub(t)
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+
+	// optional fields are skipped, so GitHub does not return them
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(fmt.Sprintf(`{"query":"query OwnerRepositories($createdAt:Boolean!$endCursor:String$owner:String!$parent:Boolean!$visibility:Boolean!){repositoryOwner(login: $owner){repositories(first: 100, after: $endCursor, affiliations: [OWNER]){nodes{isDisabled,isArchived,isLocked,url,defaultBranchRef{name,prefix},visibility @include(if: $visibility),parent @include(if: $parent){url},createdAt @include(if: $createdAt)},pageInfo{hasNextPage,endCursor}}}}","variables":{"createdAt":false,"endCursor":null,"owner":%q,"parent":false,"visibility":false}}`, github_com_orirawlings.Name())).
+		Reply(200).
+		JSON(`{"data":{"repositoryOwner":{"repositories":{"nodes":[{"isDisabled":false,"isArchived":false,"isLocked":false,"url":"https://github.com/orirawlings/bar","defaultBranchRef":{"name":"main","prefix":"refs/heads/"}}],"pageInfo":{"hasNextPage":false}}}}}`)
+
+	b := &biome{}
+	remoteCfgs, err := b.buildRemoteConfigs(ctx, github_com_orirawlings, nil, repositoryFields{})
+	testutil.Check(t, err)
+	expected := []remoteConfig{
+		{
+			Remote: Remote{Name: barRemote.Name},
+			Head:   barRemoteCfg.Head,
+		},
+	}
+	if !slices.Equal(remoteCfgs, expected) {
+		t.Errorf("unexpected remote configs: wanted %v, was %v", expected, remoteCfgs)
+	}
+}
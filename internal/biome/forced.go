@@ -0,0 +1,161 @@
+package biome
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ForcedUpdate is a non-fast-forward update of a remote's branch by a fetch,
+// ex. because history was rewritten and force-pushed. The previous commit of
+// the branch is no longer an ancestor of its new commit.
+type ForcedUpdate struct {
+
+	// Time when the update was fetched. It is the zero time for updates
+	// that have not been recorded in the journal yet.
+	Time time.Time
+
+	// Remote is the name of the remote.
+	Remote string
+
+	// Branch is the name of the branch, ex. main.
+	Branch string
+
+	// From is the object ID of the branch's previous commit.
+	From string
+
+	// To is the object ID of the branch's new commit.
+	To string
+}
+
+// BranchTips returns the object IDs that the branches of the biome's
+// fetchable remotes point to, keyed by reference name, so that
+// [biome.ForcedUpdates] can compare them with the branches after a fetch.
+func (b *biome) BranchTips(ctx context.Context) (map[string]string, error) {
+	remotes, err := b.Remotes(ctx, FetchableRemoteCategories...)
+	if err != nil {
+		return nil, err
+	}
+	var prefixes []string
+	for _, r := range remotes {
+		prefixes = append(prefixes, r.RefNamespace()+"heads/")
+	}
+	refs, err := b.listRefs(ctx, prefixes)
+	if err != nil {
+		return nil, err
+	}
+	tips := make(map[string]string)
+	for _, ref := range refs {
+		if ref.Symref == "" {
+			tips[ref.Name] = ref.ObjectName
+		}
+	}
+	return tips, nil
+}
+
+// ForcedUpdates compares the current branches of the biome's fetchable
+// remotes with their tips before a fetch, taken with [biome.BranchTips], and
+// returns the branches that were updated without fast-forwarding, sorted by
+// remote and branch. Branches that were created or deleted are not forced
+// updates.
+func (b *biome) ForcedUpdates(ctx context.Context, before map[string]string) ([]ForcedUpdate, error) {
+	remotes, err := b.Remotes(ctx, FetchableRemoteCategories...)
+	if err != nil {
+		return nil, err
+	}
+	namespaces := make(map[string]string)
+	for _, r := range remotes {
+		namespaces[r.RefNamespace()] = r.Name
+	}
+	branches := make(map[string]string)
+	for namespace, remote := range namespaces {
+		branches[remote] = namespace + "heads/"
+	}
+	after, err := b.BranchTips(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var updates []ForcedUpdate
+	for ref, to := range after {
+		from, ok := before[ref]
+		if !ok || from == to {
+			continue
+		}
+		fastForward, err := b.isAncestor(ctx, from, to)
+		if err != nil {
+			return nil, err
+		}
+		if fastForward {
+			continue
+		}
+		remote, _ := remoteOfRef(namespaces, ref)
+		updates = append(updates, ForcedUpdate{
+			Remote: remote,
+			Branch: strings.TrimPrefix(ref, branches[remote]),
+			From:   from,
+			To:     to,
+		})
+	}
+	slices.SortFunc(updates, func(a, b ForcedUpdate) int {
+		return cmp.Or(
+			cmp.Compare(a.Remote, b.Remote),
+			cmp.Compare(a.Branch, b.Branch),
+		)
+	})
+	return updates, nil
+}
+
+// isAncestor reports whether the commit is an ancestor of, or the same as,
+// the other commit.
+func (b *biome) isAncestor(ctx context.Context, commit, other string) (bool, error) {
+	_, err := b.git(ctx, "merge-base", "--is-ancestor", commit, other)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ForcedUpdateEntries returns journal entries that record the given forced
+// updates at the given time.
+func ForcedUpdateEntries(updates []ForcedUpdate, t time.Time) []JournalEntry {
+	var entries []JournalEntry
+	for _, u := range updates {
+		entries = append(entries, JournalEntry{
+			Time:   t,
+			Op:     ForcedUpdateOp,
+			Remote: u.Remote,
+			Branch: u.Branch,
+			From:   u.From,
+			To:     u.To,
+		})
+	}
+	return entries
+}
+
+// ForcedUpdatesSince returns the forced updates recorded in the journal at
+// or after since, most recent first.
+func ForcedUpdatesSince(entries []JournalEntry, since time.Time) []ForcedUpdate {
+	var updates []ForcedUpdate
+	for _, e := range entries {
+		if e.Op != ForcedUpdateOp || e.Time.Before(since) {
+			continue
+		}
+		updates = append(updates, ForcedUpdate{
+			Time:   e.Time,
+			Remote: e.Remote,
+			Branch: e.Branch,
+			From:   e.From,
+			To:     e.To,
+		})
+	}
+	slices.SortStableFunc(updates, func(a, b ForcedUpdate) int {
+		return b.Time.Compare(a.Time)
+	})
+	return updates
+}
@@ -0,0 +1,79 @@
+package biome
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_ForcedUpdates(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		for _, r := range []Remote{barRemote, headlessRemote} {
+			cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, r.Name)
+		}
+		return true, nil
+	}))
+	base := commitTree(t, path, "base")
+	next := commitTree(t, path, "next", base)
+	rewritten := commitTree(t, path, "rewritten", base)
+	for ref, commit := range map[string]string{
+		"refs/remotes/github.com/orirawlings/bar/heads/main":         base,
+		"refs/remotes/github.com/orirawlings/bar/heads/feature/x":    next,
+		"refs/remotes/github.com/orirawlings/bar/heads/gone":         base,
+		"refs/remotes/github.com/orirawlings/bar/tags/v1":            base,
+		"refs/remotes/github.com/orirawlings/headless/heads/develop": next,
+	} {
+		testutil.Execute(t, "git", "-C", path, "update-ref", ref, commit)
+	}
+	tips, err := b.BranchTips(ctx)
+	testutil.Check(t, err)
+	if len(tips) != 4 {
+		t.Errorf("expected tips of 4 branches, was %v", tips)
+	}
+
+	// fast-forward main, rewrite feature/x and develop, move the tag,
+	// delete a branch, and create another
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/remotes/github.com/orirawlings/bar/heads/main", next)
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/remotes/github.com/orirawlings/bar/heads/feature/x", rewritten)
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/remotes/github.com/orirawlings/bar/tags/v1", rewritten)
+	testutil.Execute(t, "git", "-C", path, "update-ref", "-d", "refs/remotes/github.com/orirawlings/bar/heads/gone")
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/remotes/github.com/orirawlings/bar/heads/new", rewritten)
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/remotes/github.com/orirawlings/headless/heads/develop", rewritten)
+
+	updates, err := b.ForcedUpdates(ctx, tips)
+	testutil.Check(t, err)
+	expected := []ForcedUpdate{
+		{Remote: barRemote.Name, Branch: "feature/x", From: next, To: rewritten},
+		{Remote: headlessRemote.Name, Branch: "develop", From: next, To: rewritten},
+	}
+	if !reflect.DeepEqual(updates, expected) {
+		t.Errorf("unexpected forced updates: wanted %+v, was %+v", expected, updates)
+	}
+
+	// recorded forced updates are listed, most recent first
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	entries := append(
+		ForcedUpdateEntries(expected[:1], now.Add(-48*time.Hour)),
+		ForcedUpdateEntries(expected[1:], now)...,
+	)
+	entries = append(entries, JournalEntry{Time: now, Op: FetchOp, Remote: barRemote.Name})
+	recorded := ForcedUpdatesSince(entries, now.Add(-72*time.Hour))
+	expected[0].Time = now.Add(-48 * time.Hour)
+	expected[1].Time = now
+	if want := []ForcedUpdate{expected[1], expected[0]}; !reflect.DeepEqual(recorded, want) {
+		t.Errorf("unexpected recorded forced updates: wanted %+v, was %+v", want, recorded)
+	}
+	if recorded := ForcedUpdatesSince(entries, now.Add(-time.Hour)); !reflect.DeepEqual(recorded, expected[1:]) {
+		t.Errorf("unexpected recent forced updates: wanted %+v, was %+v", expected[1:], recorded)
+	}
+}
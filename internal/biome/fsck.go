@@ -0,0 +1,106 @@
+package biome
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// fsckScheduleKey is the git config key of the setting for how often
+	// scheduled integrity checks check the biome's objects, see
+	// [ParseFetchFrequency].
+	fsckScheduleKey = "biome.fsck.schedule"
+
+	// fsckErrorLines is the number of git's last messages that are recorded
+	// in the journal when an integrity check finds corruption.
+	fsckErrorLines = 5
+)
+
+// Fsck checks the connectivity of the biome's objects with git fsck
+// --connectivity-only, which verifies that every object reachable from the
+// biome's references is present, without the cost of checking the contents
+// of every blob. The result is recorded in the biome's journal, and returned
+// as a [FsckOp] entry that is Failed if corruption was found. An error is
+// returned only if the check could not be run, ex. because it was canceled,
+// in which case nothing is recorded.
+func (b *biome) Fsck(ctx context.Context) (JournalEntry, error) {
+	entry := JournalEntry{
+		Time: time.Now(),
+		Op:   FsckOp,
+	}
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "-C", b.path, "fsck", "--connectivity-only", "--no-dangling", "--no-progress")
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	entry.Duration = time.Since(entry.Time)
+	var exitErr *exec.ExitError
+	if err != nil && (ctx.Err() != nil || !errors.As(err, &exitErr)) {
+		return entry, fmt.Errorf("could not %q: %w", cmd, err)
+	}
+	if err != nil {
+		entry.Failed = true
+		entry.Error = lastLines(out.String(), fsckErrorLines)
+		if entry.Error == "" {
+			entry.Error = err.Error()
+		}
+	}
+	if err := b.Record(ctx, entry); err != nil {
+		return entry, fmt.Errorf("could not record integrity check: %w", err)
+	}
+	return entry, nil
+}
+
+// FsckSchedule returns how often scheduled integrity checks check the
+// biome's objects, according to the biome.fsck.schedule setting, or 0 if
+// they are not scheduled.
+func (b *biome) FsckSchedule(ctx context.Context) (time.Duration, error) {
+	setting, err := b.GetSetting(ctx, fsckScheduleKey)
+	if err != nil {
+		return 0, err
+	}
+	return ParseFetchFrequency(setting.Value)
+}
+
+// LastFsck returns the most recent integrity check recorded in the journal,
+// if any.
+func LastFsck(entries []JournalEntry) (JournalEntry, bool) {
+	for _, e := range slices.Backward(entries) {
+		if e.Op == FsckOp {
+			return e, true
+		}
+	}
+	return JournalEntry{}, false
+}
+
+// FsckDue reports whether a scheduled integrity check is due at the given
+// time, because the schedule has elapsed since the last check recorded in the
+// journal, or because the biome has never been checked. No check is due if
+// the schedule is 0.
+func FsckDue(entries []JournalEntry, schedule time.Duration, now time.Time) bool {
+	if schedule <= 0 {
+		return false
+	}
+	last, ok := LastFsck(entries)
+	return !ok || !now.Before(last.Time.Add(schedule))
+}
+
+// lastLines returns up to n of the last non-empty lines of the output.
+func lastLines(output string, n int) string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
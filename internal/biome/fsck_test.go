@@ -0,0 +1,102 @@
+package biome
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_Fsck(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	base := commitTree(t, path, "base")
+	next := commitTree(t, path, "next", base)
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/remotes/github.com/orirawlings/bar/heads/main", next)
+
+	entry, err := b.Fsck(ctx)
+	testutil.Check(t, err)
+	if entry.Op != FsckOp || entry.Failed || entry.Error != "" {
+		t.Errorf("expected a successful integrity check, was %+v", entry)
+	}
+
+	// remove the parent commit, which the reference still reaches
+	object := filepath.Join(path, "objects", base[:2], base[2:])
+	testutil.Check(t, os.Remove(object))
+	entry, err = b.Fsck(ctx)
+	testutil.Check(t, err)
+	if !entry.Failed || !strings.Contains(entry.Error, base) {
+		t.Errorf("expected a failed integrity check mentioning %s, was %+v", base, entry)
+	}
+
+	entries, err := b.Journal(ctx)
+	testutil.Check(t, err)
+	last, ok := LastFsck(entries)
+	if !ok || !last.Failed || last.Error != entry.Error {
+		t.Errorf("expected the failed integrity check to be recorded last, was %+v", entries)
+	}
+
+	// a canceled check is not recorded
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := b.Fsck(canceled); err == nil {
+		t.Errorf("expected error for canceled integrity check")
+	}
+	if recorded, err := b.Journal(ctx); err != nil || len(recorded) != len(entries) {
+		t.Errorf("expected %d journal entries, was %+v: %v", len(entries), recorded, err)
+	}
+}
+
+func TestBiome_FsckSchedule(t *testing.T) {
+	ctx := context.Background()
+	b := &biome{
+		path:          testutil.TempRepo(t),
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	if schedule, err := b.FsckSchedule(ctx); err != nil || schedule != 0 {
+		t.Errorf("expected no schedule, was %s: %v", schedule, err)
+	}
+	testutil.Check(t, b.SetSetting(ctx, fsckScheduleKey, "daily"))
+	if schedule, err := b.FsckSchedule(ctx); err != nil || schedule != 24*time.Hour {
+		t.Errorf("expected daily schedule, was %s: %v", schedule, err)
+	}
+	if err := b.SetSetting(ctx, fsckScheduleKey, "sometimes"); err == nil {
+		t.Errorf("expected error for invalid schedule")
+	}
+}
+
+func TestFsckDue(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	entries := []JournalEntry{
+		{Time: now.Add(-48 * time.Hour), Op: FsckOp, Failed: true},
+		{Time: now.Add(-12 * time.Hour), Op: FsckOp},
+		{Time: now.Add(-time.Hour), Op: FetchOp, Remote: barRemote.Name},
+	}
+	for _, test := range []struct {
+		name     string
+		entries  []JournalEntry
+		schedule time.Duration
+		expected bool
+	}{
+		{"unscheduled", entries, 0, false},
+		{"never checked", entries[2:], 24 * time.Hour, true},
+		{"checked recently", entries, 24 * time.Hour, false},
+		{"schedule elapsed", entries, 12 * time.Hour, true},
+		{"schedule elapsed since failure", entries[:1], 24 * time.Hour, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if due := FsckDue(test.entries, test.schedule, now); due != test.expected {
+				t.Errorf("expected due %t, was %t", test.expected, due)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package biome
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"path"
+	"slices"
+)
+
+const (
+	// OwnerNode is the kind of graph node for a GitHub owner.
+	OwnerNode = "owner"
+
+	// RemoteNode is the kind of graph node for a remote repository.
+	RemoteNode = "remote"
+
+	// OwnsEdge is the kind of graph edge from an owner to each of its
+	// remotes.
+	OwnsEdge = "owns"
+
+	// ForkOfEdge is the kind of graph edge from a fork to its upstream
+	// remote.
+	ForkOfEdge = "forkOf"
+)
+
+// Graph is the topology of the biome: its owners, the remotes they own, and
+// the upstream remote that each fork was forked from.
+type Graph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode is an owner or remote of the biome's topology.
+type GraphNode struct {
+
+	// ID is the name of the owner or remote.
+	ID string `json:"id"`
+
+	// Kind is either [OwnerNode] or [RemoteNode].
+	Kind string `json:"kind"`
+
+	// Categories of the remote. Upstream remotes that are not in the biome
+	// have no categories.
+	Categories []RemoteCategory `json:"categories,omitempty"`
+
+	// Bytes estimates the on-disk size of the objects reachable from the
+	// remote's references, if sizes were measured.
+	Bytes int64 `json:"bytes,omitempty"`
+}
+
+// GraphEdge relates two nodes of the biome's topology.
+type GraphEdge struct {
+
+	// From is the ID of the owner, or of the fork.
+	From string `json:"from"`
+
+	// To is the ID of the owned remote, or of the upstream remote.
+	To string `json:"to"`
+
+	// Kind is either [OwnsEdge] or [ForkOfEdge].
+	Kind string `json:"kind"`
+}
+
+// Graph returns the topology of the biome, relating each owner to its remotes
+// and each fork to its upstream remote. Upstream remotes that are not in the
+// biome, and the owners of remotes discovered through viewers, are included
+// as nodes too. If sizes is true, the disk usage of each remote is measured,
+// which can take a long time for large biomes.
+func (b *biome) Graph(ctx context.Context, sizes bool) (Graph, error) {
+	var g Graph
+	nodes := make(map[string]*GraphNode)
+	node := func(id, kind string) *GraphNode {
+		if _, ok := nodes[id]; !ok {
+			nodes[id] = &GraphNode{ID: id, Kind: kind}
+		}
+		return nodes[id]
+	}
+
+	owners, err := b.Owners(ctx)
+	if err != nil {
+		return g, err
+	}
+	for _, owner := range owners {
+		node(owner.String(), OwnerNode)
+	}
+
+	var remotes []Remote
+	for _, category := range AllRemoteCategories {
+		rs, err := b.Remotes(ctx, category)
+		if err != nil {
+			return g, err
+		}
+		for _, r := range rs {
+			n := node(r.Name, RemoteNode)
+			if len(n.Categories) == 0 {
+				remotes = append(remotes, r)
+			}
+			n.Categories = append(n.Categories, category)
+		}
+	}
+	for _, r := range remotes {
+		node(path.Dir(r.Name), OwnerNode)
+		g.Edges = append(g.Edges, GraphEdge{From: path.Dir(r.Name), To: r.Name, Kind: OwnsEdge})
+		if r.Upstream != "" {
+			node(r.Upstream, RemoteNode)
+			g.Edges = append(g.Edges, GraphEdge{From: r.Name, To: r.Upstream, Kind: ForkOfEdge})
+		}
+		if sizes && !r.Evicted {
+			if nodes[r.Name].Bytes, err = b.diskUsage(ctx, []Remote{r}, false); err != nil {
+				return g, fmt.Errorf("could not measure disk usage of %s: %w", r.Name, err)
+			}
+		}
+	}
+
+	for _, n := range nodes {
+		g.Nodes = append(g.Nodes, *n)
+	}
+	slices.SortFunc(g.Nodes, func(a, b GraphNode) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	slices.SortFunc(g.Edges, func(a, b GraphEdge) int {
+		return cmp.Or(
+			cmp.Compare(a.From, b.From),
+			cmp.Compare(a.To, b.To),
+		)
+	})
+	return g, nil
+}
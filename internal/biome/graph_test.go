@@ -0,0 +1,61 @@
+package biome
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_Graph(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		cfg.Section(section).AddOption(ownersOpt, github_com_orirawlings.String())
+		remotes := cfg.Section(section).Subsection(remotesSubsection)
+		remotes.AddOption(activeOpt, barRemote.Name)
+		remotes.AddOption(archivedOpt, archivedRemote.Name)
+		remotes.AddOption(activeOpt, githubCLICLIRemote.Name)
+		remotes.AddOption(upstreamOpt, barRemote.Name+" "+githubCLICLIRemote.Name)
+		remotes.AddOption(upstreamOpt, archivedRemote.Name+" github.com/other/upstream")
+		return true, nil
+	}))
+	createCommitFor(t, ctx, path, []string{
+		"refs/remotes/github.com/orirawlings/bar/heads/main",
+	})
+
+	g, err := b.Graph(ctx, true)
+	testutil.Check(t, err)
+	for i, n := range g.Nodes {
+		if n.ID == barRemote.Name && n.Bytes <= 0 {
+			t.Errorf("expected size of fetched remote %s, was %d", n.ID, n.Bytes)
+		}
+		g.Nodes[i].Bytes = 0
+	}
+	expected := Graph{
+		Nodes: []GraphNode{
+			{ID: "github.com/cli", Kind: OwnerNode},
+			{ID: githubCLICLIRemote.Name, Kind: RemoteNode, Categories: []RemoteCategory{Active}},
+			{ID: "github.com/orirawlings", Kind: OwnerNode},
+			{ID: archivedRemote.Name, Kind: RemoteNode, Categories: []RemoteCategory{Archived}},
+			{ID: barRemote.Name, Kind: RemoteNode, Categories: []RemoteCategory{Active}},
+			{ID: "github.com/other/upstream", Kind: RemoteNode},
+		},
+		Edges: []GraphEdge{
+			{From: "github.com/cli", To: githubCLICLIRemote.Name, Kind: OwnsEdge},
+			{From: "github.com/orirawlings", To: archivedRemote.Name, Kind: OwnsEdge},
+			{From: "github.com/orirawlings", To: barRemote.Name, Kind: OwnsEdge},
+			{From: archivedRemote.Name, To: "github.com/other/upstream", Kind: ForkOfEdge},
+			{From: barRemote.Name, To: githubCLICLIRemote.Name, Kind: ForkOfEdge},
+		},
+	}
+	if !reflect.DeepEqual(g, expected) {
+		t.Errorf("unexpected graph: wanted %+v, was %+v", expected, g)
+	}
+}
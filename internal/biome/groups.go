@@ -0,0 +1,88 @@
+package biome
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+)
+
+// RemoteGroup is a git remote group of the biome, listing the remotes of one
+// of the biome's owners or viewers. Remote groups are named after a hash of
+// their owner or viewer, see [Owner.RemoteGroup], so that any owner name can
+// be used in git config, ex. `git fetch <group>`.
+type RemoteGroup struct {
+
+	// Name of the git remote group, ex. `g-<sha1>`.
+	Name string
+
+	// Source is the owner, ex. github.com/cli, or viewer, ex.
+	// viewer@github.com, whose remotes are members of the group. It is empty
+	// if the group is not of any of the biome's current owners or viewers.
+	Source string
+
+	// Members lists the names of the remotes in the group, sorted by name.
+	Members []string
+}
+
+// RemoteGroups returns the biome's git remote groups, sorted by their source.
+// Every owner and viewer of the biome has a remote group, even before its
+// remotes are discovered, in which case the group has no members. Groups that
+// are configured but are not of any current owner or viewer are listed last.
+func (b *biome) RemoteGroups(ctx context.Context) ([]RemoteGroup, error) {
+	var groups []RemoteGroup
+	err := b.readConfig(ctx, func(ctx context.Context, cfg *config.Config) error {
+		owners, err := b.getOwners(cfg)
+		if err != nil {
+			return err
+		}
+		viewers, err := b.getViewers(cfg)
+		if err != nil {
+			return err
+		}
+		sources := make(map[string]string)
+		for _, owner := range owners {
+			sources[owner.RemoteGroup()] = owner.String()
+		}
+		for _, viewer := range viewers {
+			sources[viewer.RemoteGroup()] = viewer.String()
+		}
+
+		members := make(map[string][]string)
+		for _, opt := range cfg.Section("remotes").Options {
+			if remoteGroupPattern.MatchString(opt.Key) {
+				members[opt.Key] = append(members[opt.Key], opt.Value)
+			}
+		}
+		for name, source := range sources {
+			groups = append(groups, RemoteGroup{
+				Name:   name,
+				Source: source,
+			})
+		}
+		for name := range members {
+			if _, ok := sources[name]; !ok {
+				groups = append(groups, RemoteGroup{
+					Name: name,
+				})
+			}
+		}
+		for i := range groups {
+			groups[i].Members = slices.Compact(slices.Sorted(slices.Values(members[groups[i].Name])))
+		}
+		return nil
+	})
+	slices.SortFunc(groups, func(a, b RemoteGroup) int {
+		switch {
+		case a.Source == "" && b.Source != "":
+			return 1
+		case a.Source != "" && b.Source == "":
+			return -1
+		case a.Source != b.Source:
+			return strings.Compare(a.Source, b.Source)
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return groups, err
+}
@@ -0,0 +1,31 @@
+package biome
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_RemoteGroups(t *testing.T) {
+	ctx := context.Background()
+	b, _ := absorbable(t, ctx, []Owner{github_com_orirawlings, github_com_cli}, barRemoteCfg)
+	stale := "g-" + strings.Repeat("0", 40)
+	testutil.Execute(t, "git", "-C", b.path, "config", "--add", "remotes."+stale, archivedRemote.Name)
+	testutil.Execute(t, "git", "-C", b.path, "config", "--add", "remotes.other", "origin")
+
+	groups, err := b.RemoteGroups(ctx)
+	testutil.Check(t, err)
+	expected := []RemoteGroup{
+		{Name: github_com_cli.RemoteGroup(), Source: github_com_cli.String()},
+		{Name: github_com_orirawlings.RemoteGroup(), Source: github_com_orirawlings.String(), Members: []string{barRemote.Name}},
+		{Name: stale, Members: []string{archivedRemote.Name}},
+	}
+	if !slices.EqualFunc(groups, expected, func(a, b RemoteGroup) bool {
+		return a.Name == b.Name && a.Source == b.Source && slices.Equal(a.Members, b.Members)
+	}) {
+		t.Errorf("expected remote groups %+v, was %+v", expected, groups)
+	}
+}
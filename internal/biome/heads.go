@@ -0,0 +1,126 @@
+package biome
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+)
+
+// UnresolvedHeads returns the given remotes whose HEAD reference is missing,
+// ex. for empty repositories that have no default branch, or points to a
+// default branch that does not exist, ex. because the remote has not been
+// fetched yet.
+func (b *biome) UnresolvedHeads(ctx context.Context, remotes []Remote) ([]Remote, error) {
+	var heads []string
+	for _, r := range remotes {
+		heads = append(heads, r.Head())
+	}
+	refs, err := b.listRefs(ctx, heads)
+	if err != nil {
+		return nil, err
+	}
+	targets := make(map[string]string)
+	var targetNames []string
+	for _, ref := range refs {
+		if ref.Symref != "" {
+			targets[ref.Name] = ref.Symref
+			targetNames = append(targetNames, ref.Symref)
+		}
+	}
+	refs, err = b.listRefs(ctx, targetNames)
+	if err != nil {
+		return nil, err
+	}
+	resolved := make(map[string]bool)
+	for _, ref := range refs {
+		resolved[ref.Name] = ref.ObjectName != ""
+	}
+
+	var unresolved []Remote
+	for _, r := range remotes {
+		if target, ok := targets[r.Head()]; !ok || !resolved[target] {
+			unresolved = append(unresolved, r)
+		}
+	}
+	return unresolved, nil
+}
+
+// ResolveHeads sets the HEAD reference of each remote whose default branch was
+// recorded as pending by the last update of the biome's remotes, because it
+// was not fetched yet, and has been fetched since. Default branches that are
+// still missing remain pending. The names of the remotes whose HEAD reference
+// was set are returned.
+func (b *biome) ResolveHeads(ctx context.Context) ([]string, error) {
+	var pending []string
+	if err := b.readConfig(ctx, func(ctx context.Context, cfg *config.Config) error {
+		pending = cfg.Section(section).Subsection(remotesSubsection).OptionAll(pendingHeadOpt)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	var targets []string
+	for _, p := range pending {
+		if _, target, ok := strings.Cut(p, " "); ok {
+			targets = append(targets, target)
+		}
+	}
+	refs, err := b.listRefs(ctx, targets)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool)
+	for _, ref := range refs {
+		existing[ref.Name] = ref.ObjectName != ""
+	}
+
+	// pending default branches that now exist, by remote name
+	fetched := make(map[string]string)
+	for _, p := range pending {
+		if name, target, ok := strings.Cut(p, " "); ok && existing[target] {
+			fetched[name] = target
+		}
+	}
+	if len(fetched) == 0 {
+		return nil, nil
+	}
+	remotes, err := b.Remotes(ctx, FetchableRemoteCategories...)
+	if err != nil {
+		return nil, err
+	}
+	var resolved, names []string
+	if err := b.transactRefs(ctx, func(w io.Writer) error {
+		for _, r := range remotes {
+			target, ok := fetched[r.Name]
+			if !ok {
+				continue
+			}
+			resolved = append(resolved, r.Name+" "+target)
+			names = append(names, r.Name)
+			if _, err := fmt.Fprintf(w, "option no-deref\nsymref-update %s %s\n", r.Head(), target); err != nil {
+				return fmt.Errorf("could not update HEAD ref for %s: %w", r.Name, err)
+			}
+		}
+		return nil
+	}); err != nil || len(resolved) == 0 {
+		return nil, err
+	}
+
+	if err := b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		metadata := cfg.Section(section).Subsection(remotesSubsection)
+		remaining := slices.DeleteFunc(metadata.OptionAll(pendingHeadOpt), func(p string) bool {
+			return slices.Contains(resolved, p)
+		})
+		metadata.RemoveOption(pendingHeadOpt)
+		for _, p := range remaining {
+			metadata.AddOption(pendingHeadOpt, p)
+		}
+		return true, nil
+	}); err != nil {
+		return nil, fmt.Errorf("could not record resolved HEAD references: %w", err)
+	}
+	return names, nil
+}
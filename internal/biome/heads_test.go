@@ -0,0 +1,82 @@
+package biome
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"slices"
+	"testing"
+
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_UnresolvedHeads(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path: path,
+	}
+	createCommitFor(t, ctx, path, []string{
+		barRemoteCfg.Head,
+	})
+	testutil.Execute(t, "git", "-C", path, "symbolic-ref", barRemote.Head(), barRemoteCfg.Head)
+	testutil.Execute(t, "git", "-C", path, "symbolic-ref", githubCLICLIRemote.Head(), "refs/remotes/github.com/cli/cli/heads/trunk")
+
+	unresolved, err := b.UnresolvedHeads(ctx, []Remote{barRemote, githubCLICLIRemote, headlessRemote})
+	testutil.Check(t, err)
+	if expected := []Remote{githubCLICLIRemote, headlessRemote}; !slices.Equal(unresolved, expected) {
+		t.Errorf("expected unresolved HEADs of %v, was %v", expected, unresolved)
+	}
+
+	unresolved, err = b.UnresolvedHeads(ctx, nil)
+	testutil.Check(t, err)
+	if len(unresolved) > 0 {
+		t.Errorf("expected no unresolved HEADs without remotes, was %v", unresolved)
+	}
+}
+
+func TestBiome_ResolveHeads(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+	b := initBiome(t, ctx, path, true)
+	addOwners(t, ctx, b, github_com_orirawlings)
+
+	// default branches that were not fetched yet are pending, rather than
+	// dangling HEAD references
+	testutil.Check(t, b.UpdateRemotes(ctx))
+	expectRemotesForConfigKey(t, path, "biome.remotes.pendingHead", []string{
+		barRemote.Name + " " + barRemoteCfg.Head,
+		archivedRemote.Name + " " + archivedRemoteCfg.Head,
+	})
+	unresolved, err := b.UnresolvedHeads(ctx, []Remote{barRemote})
+	testutil.Check(t, err)
+	if len(unresolved) != 1 {
+		t.Errorf("expected HEAD of %s to be unresolved, was %v", barRemote, unresolved)
+	}
+	if _, err := exec.Command("git", "-C", path, "symbolic-ref", "--quiet", barRemote.Head()).Output(); err == nil {
+		t.Errorf("expected no HEAD reference for %s before its default branch is fetched", barRemote)
+	}
+
+	// nothing was fetched
+	resolved, err := b.ResolveHeads(ctx)
+	testutil.Check(t, err)
+	if len(resolved) > 0 {
+		t.Errorf("expected no resolved HEADs, was %q", resolved)
+	}
+
+	commitID := createCommitFor(t, ctx, path, []string{
+		barRemoteCfg.Head,
+	})
+	resolved, err = b.ResolveHeads(ctx)
+	testutil.Check(t, err)
+	if expected := []string{barRemote.Name}; !slices.Equal(resolved, expected) {
+		t.Errorf("expected resolved HEADs of %q, was %q", expected, resolved)
+	}
+	expectRefs(t, ctx, path, []string{
+		fmt.Sprintf(`%s commit %s %s`, commitID, barRemote.Head(), barRemoteCfg.Head),
+		fmt.Sprintf(`%s commit %s `, commitID, barRemoteCfg.Head),
+	})
+	expectRemotesForConfigKey(t, path, "biome.remotes.pendingHead", []string{
+		archivedRemote.Name + " " + archivedRemoteCfg.Head,
+	})
+}
@@ -0,0 +1,290 @@
+package biome
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxHealthScore is the score of a perfectly healthy biome.
+	maxHealthScore = 100
+
+	// fetchStaleAfter is how long after the last fetch the biome is
+	// considered stale.
+	fetchStaleAfter = 7 * 24 * time.Hour
+
+	// maintenanceStaleAfter is how long after the last maintenance, as
+	// indicated by the commit-graph, maintenance is considered overdue.
+	maintenanceStaleAfter = 30 * 24 * time.Hour
+
+	// maxHealthyPacks is the number of packfiles beyond which the object
+	// database is considered fragmented.
+	maxHealthyPacks = 50
+
+	// maxHealthyLooseObjects is the number of loose objects beyond which the
+	// object database is considered fragmented.
+	maxHealthyLooseObjects = 10000
+)
+
+// HealthReport scores the health of a biome and lists the problems that were
+// found.
+type HealthReport struct {
+
+	// Score of the biome's health from 0 to 100, where 100 means no problems
+	// were found.
+	Score int
+
+	// Checks that found problems, ordered by decreasing penalty, so the most
+	// important remediations come first.
+	Checks []HealthCheck
+}
+
+// HealthCheck is the outcome of a health check that found a problem.
+type HealthCheck struct {
+
+	// Name of the health check, ex. `unfetched remotes`.
+	Name string
+
+	// Penalty deducted from the biome's health score.
+	Penalty int
+
+	// Detail describes the problem that was found.
+	Detail string
+
+	// Remediation suggests how to fix the problem.
+	Remediation string
+}
+
+// healthFacts are the observations about a biome that its health is scored
+// from.
+type healthFacts struct {
+
+	// remotes is the number of fetchable remotes.
+	remotes int
+
+	// unfetched lists fetchable remotes without any references.
+	unfetched []string
+
+	// unresolvedHeads lists fetchable remotes whose HEAD reference points to
+	// a reference that does not exist.
+	unresolvedHeads []string
+
+	// lastFetch is when remotes were last fetched, or zero if never.
+	lastFetch time.Time
+
+	// lastMaintenance is when the commit-graph was last written, or zero if
+	// never.
+	lastMaintenance time.Time
+
+	// packs is the number of packfiles in the object database.
+	packs int
+
+	// looseObjects is the number of loose objects in the object database.
+	looseObjects int
+}
+
+// Health scores the biome's health, checking for remotes that failed to
+// fetch, HEAD references that do not resolve, stale fetches, overdue
+// maintenance, and a fragmented object database.
+func (b *biome) Health(ctx context.Context) (HealthReport, error) {
+	facts, err := b.healthFacts(ctx)
+	if err != nil {
+		return HealthReport{}, err
+	}
+	return facts.report(time.Now(), b.path), nil
+}
+
+func (b *biome) healthFacts(ctx context.Context) (healthFacts, error) {
+	var facts healthFacts
+
+	remotes, err := b.Remotes(ctx, FetchableRemoteCategories...)
+	if err != nil {
+		return facts, err
+	}
+	facts.remotes = len(remotes)
+
+	var namespaces []string
+	for _, r := range remotes {
+		namespaces = append(namespaces, r.RefNamespace())
+	}
+	refs, err := b.listRefs(ctx, namespaces)
+	if err != nil {
+		return facts, err
+	}
+	refsByName := make(map[string]storedRef)
+	var names []string
+	for _, ref := range refs {
+		refsByName[ref.Name] = ref
+		names = append(names, ref.Name)
+	}
+	slices.Sort(names)
+	for _, r := range remotes {
+		// the first reference at or after the namespace is within the
+		// namespace, if the namespace has any references
+		if i, _ := slices.BinarySearch(names, r.RefNamespace()); i == len(names) || !strings.HasPrefix(names[i], r.RefNamespace()) {
+			facts.unfetched = append(facts.unfetched, r.Name)
+			continue
+		}
+		if head, ok := refsByName[r.Head()]; ok && head.Symref != "" {
+			if _, ok := refsByName[head.Symref]; !ok {
+				facts.unresolvedHeads = append(facts.unresolvedHeads, r.Name)
+			}
+		}
+	}
+
+	gitDir, err := b.gitDir(ctx)
+	if err != nil {
+		return facts, err
+	}
+	facts.lastFetch = modTime(filepath.Join(gitDir, "FETCH_HEAD"))
+	facts.lastMaintenance = modTime(
+		filepath.Join(gitDir, "objects", "info", "commit-graph"),
+		filepath.Join(gitDir, "objects", "info", "commit-graphs", "commit-graph-chain"),
+	)
+
+	counts, err := b.countObjects(ctx)
+	if err != nil {
+		return facts, err
+	}
+	facts.packs = counts["packs"]
+	facts.looseObjects = counts["count"]
+
+	return facts, nil
+}
+
+// report scores the facts as of the given time. The biome path is used to
+// suggest remediations.
+func (f healthFacts) report(now time.Time, path string) HealthReport {
+	var checks []HealthCheck
+	if f.remotes > 0 && len(f.unfetched) > 0 {
+		checks = append(checks, HealthCheck{
+			Name:        "unfetched remotes",
+			Penalty:     proportionalPenalty(30, len(f.unfetched), f.remotes),
+			Detail:      fmt.Sprintf("%d of %d remotes have no references, usually because fetching them failed: %s", len(f.unfetched), f.remotes, summarizeNames(f.unfetched)),
+			Remediation: "gh biome fetch",
+		})
+	}
+	if f.remotes > 0 && len(f.unresolvedHeads) > 0 {
+		checks = append(checks, HealthCheck{
+			Name:        "unresolved HEADs",
+			Penalty:     proportionalPenalty(20, len(f.unresolvedHeads), f.remotes),
+			Detail:      fmt.Sprintf("%d of %d remotes have a HEAD reference to a missing default branch: %s", len(f.unresolvedHeads), f.remotes, summarizeNames(f.unresolvedHeads)),
+			Remediation: "gh biome fetch",
+		})
+	}
+	if f.remotes > 0 {
+		if f.lastFetch.IsZero() {
+			checks = append(checks, HealthCheck{
+				Name:        "stale fetch",
+				Penalty:     15,
+				Detail:      "remotes have never been fetched",
+				Remediation: "gh biome fetch",
+			})
+		} else if age := now.Sub(f.lastFetch); age > fetchStaleAfter {
+			checks = append(checks, HealthCheck{
+				Name:        "stale fetch",
+				Penalty:     15,
+				Detail:      fmt.Sprintf("remotes were last fetched %s ago", age.Round(time.Hour)),
+				Remediation: "gh biome fetch",
+			})
+		}
+	}
+	if f.remotes > 0 && !f.lastFetch.IsZero() {
+		if f.lastMaintenance.IsZero() {
+			checks = append(checks, HealthCheck{
+				Name:        "overdue maintenance",
+				Penalty:     15,
+				Detail:      "maintenance has never written a commit-graph",
+				Remediation: fmt.Sprintf("git -C %s maintenance run --task=gc --task=commit-graph", path),
+			})
+		} else if age := now.Sub(f.lastMaintenance); age > maintenanceStaleAfter {
+			checks = append(checks, HealthCheck{
+				Name:        "overdue maintenance",
+				Penalty:     15,
+				Detail:      fmt.Sprintf("maintenance last wrote a commit-graph %s ago", age.Round(time.Hour)),
+				Remediation: fmt.Sprintf("git -C %s maintenance run --task=gc --task=commit-graph", path),
+			})
+		}
+	}
+	if f.packs > maxHealthyPacks || f.looseObjects > maxHealthyLooseObjects {
+		checks = append(checks, HealthCheck{
+			Name:        "pack fragmentation",
+			Penalty:     20,
+			Detail:      fmt.Sprintf("object database has %d packfiles and %d loose objects", f.packs, f.looseObjects),
+			Remediation: fmt.Sprintf("git -C %s maintenance run --task=incremental-repack --task=loose-objects", path),
+		})
+	}
+
+	slices.SortStableFunc(checks, func(a, b HealthCheck) int {
+		return b.Penalty - a.Penalty
+	})
+	report := HealthReport{
+		Score:  maxHealthScore,
+		Checks: checks,
+	}
+	for _, c := range checks {
+		report.Score -= c.Penalty
+	}
+	report.Score = max(report.Score, 0)
+	return report
+}
+
+// proportionalPenalty scales the maximum penalty by the fraction of n out of
+// total, deducting at least 1.
+func proportionalPenalty(maxPenalty, n, total int) int {
+	return max(maxPenalty*n/total, 1)
+}
+
+// summarizeNames lists the first few names, noting how many more there are.
+func summarizeNames(names []string) string {
+	const limit = 3
+	if len(names) <= limit {
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s, and %d more", strings.Join(names[:limit], ", "), len(names)-limit)
+}
+
+// modTime returns the latest modification time of the given files, ignoring
+// files that do not exist.
+func modTime(paths ...string) time.Time {
+	var latest time.Time
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest
+}
+
+// countObjects returns the statistics reported by `git count-objects -v`,
+// ex. `count` loose objects and `packs`.
+func (b *biome) countObjects(ctx context.Context) (map[string]int, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "-C", b.path, "count-objects", "-v")
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not %q: %w: %s", cmd, err, stderr.String())
+	}
+	counts := make(map[string]int)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ": ")
+		if !ok {
+			continue
+		}
+		if n, err := strconv.Atoi(value); err == nil {
+			counts[key] = n
+		}
+	}
+	return counts, scanner.Err()
+}
@@ -0,0 +1,67 @@
+package biome
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestHealthFacts_report(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	for _, run := range []struct {
+		name           string
+		facts          healthFacts
+		expectedScore  int
+		expectedChecks []string
+	}{
+		{
+			name:          "empty biome",
+			expectedScore: 100,
+		},
+		{
+			name: "healthy biome",
+			facts: healthFacts{
+				remotes:         10,
+				lastFetch:       now.Add(-time.Hour),
+				lastMaintenance: now.Add(-24 * time.Hour),
+				packs:           3,
+			},
+			expectedScore: 100,
+		},
+		{
+			name: "never fetched",
+			facts: healthFacts{
+				remotes:   2,
+				unfetched: []string{"github.com/cli/cli", "github.com/git/git"},
+			},
+			expectedScore:  55,
+			expectedChecks: []string{"unfetched remotes", "stale fetch"},
+		},
+		{
+			name: "neglected biome",
+			facts: healthFacts{
+				remotes:         10,
+				unfetched:       []string{"github.com/cli/cli"},
+				unresolvedHeads: []string{"github.com/git/git", "github.com/orirawlings/bar"},
+				lastFetch:       now.Add(-30 * 24 * time.Hour),
+				packs:           200,
+			},
+			expectedScore:  100 - 20 - 15 - 15 - 4 - 3,
+			expectedChecks: []string{"pack fragmentation", "stale fetch", "overdue maintenance", "unresolved HEADs", "unfetched remotes"},
+		},
+	} {
+		t.Run(run.name, func(t *testing.T) {
+			report := run.facts.report(now, "/biome")
+			if report.Score != run.expectedScore {
+				t.Errorf("unexpected score: wanted %d, was %d", run.expectedScore, report.Score)
+			}
+			var checks []string
+			for _, c := range report.Checks {
+				checks = append(checks, c.Name)
+			}
+			if !slices.Equal(checks, run.expectedChecks) {
+				t.Errorf("unexpected checks: wanted %v, was %v", run.expectedChecks, checks)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package biome
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+)
+
+const (
+	// preRemoveHookKey is the git config key of the setting that holds a
+	// command run before owners or viewers are removed from the biome.
+	preRemoveHookKey = "biome.hooks.preRemove"
+
+	// preUpdateHookKey is the git config key of the setting that holds a
+	// command run before remote configurations are updated.
+	preUpdateHookKey = "biome.hooks.preUpdate"
+)
+
+var (
+	// ErrHookRejected indicates that a hook vetoed an operation by exiting
+	// with a non-zero status.
+	ErrHookRejected = errors.New("rejected by hook")
+)
+
+// hookChange is a change to the biome's remotes that is described to a hook.
+type hookChange struct {
+
+	// action is `add` or `remove`.
+	action string
+
+	// remote is the name of the remote that is changed.
+	remote string
+}
+
+// runHook runs the hook command stored in the setting with the given key, if
+// any, to decide whether an operation may proceed. The hook is run by the
+// shell, from the biome's directory. Each change is written to the hook's
+// standard input as a line of the form `<action> <remote>`, ex.
+// `remove github.com/cli/cli`. The hook vetoes the operation by exiting with
+// a non-zero status, in which case [ErrHookRejected] is returned along with
+// the hook's output.
+func (b *biome) runHook(ctx context.Context, cfg *config.Config, key string, changes []hookChange) error {
+	command, _ := getConfigValue(cfg, key)
+	if command == "" {
+		return nil
+	}
+
+	var stdin strings.Builder
+	for _, c := range changes {
+		fmt.Fprintf(&stdin, "%s %s\n", c.action, c.remote)
+	}
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = b.path
+	cmd.Env = append(os.Environ(),
+		"BIOME_PATH="+b.path,
+		"BIOME_HOOK="+key,
+	)
+	cmd.Stdin = strings.NewReader(stdin.String())
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("%w %s: %w: %s", ErrHookRejected, key, err, strings.TrimSpace(out.String()))
+		}
+		return fmt.Errorf("could not %q: %w: %s", cmd, err, out.String())
+	}
+	return nil
+}
+
+// removeChanges describes the removal of the named remotes to a hook.
+func removeChanges(remotes []string) []hookChange {
+	var changes []hookChange
+	for _, name := range remotes {
+		changes = append(changes, hookChange{action: "remove", remote: name})
+	}
+	return changes
+}
+
+// updateChanges describes an update of remote configurations to a hook, from
+// the remotes that were configured before the update and the remotes that
+// are configured by it. Remotes whose names changed only in letter case are
+// neither added nor removed.
+func updateChanges(previous map[string]string, added []remoteConfig) []hookChange {
+	previousByLowerName := make(map[string]struct{})
+	for name := range previous {
+		previousByLowerName[strings.ToLower(name)] = struct{}{}
+	}
+	kept := make(map[string]struct{})
+	var changes []hookChange
+	for _, r := range added {
+		kept[strings.ToLower(r.Remote.Name)] = struct{}{}
+		if _, ok := previousByLowerName[strings.ToLower(r.Remote.Name)]; !ok {
+			changes = append(changes, hookChange{action: "add", remote: r.Remote.Name})
+		}
+	}
+	var removed []string
+	for name := range previous {
+		if _, ok := kept[strings.ToLower(name)]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	slices.Sort(removed)
+	return append(changes, removeChanges(removed)...)
+}
@@ -0,0 +1,84 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_hooks(t *testing.T) {
+	ctx := context.Background()
+	stubGitHub(t)
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		cfg.Section(section).AddOption(ownersOpt, github_com_cli.String())
+		cfg.Section(section).AddOption(ownersOpt, github_com_orirawlings.String())
+		cfg.Section("remotes").AddOption(github_com_orirawlings.RemoteGroup(), barRemote.Name)
+		cfg.Section("remotes").AddOption(github_com_orirawlings.RemoteGroup(), githubCLICLIRemote.Name)
+		cfg.Section("remotes").AddOption(github_com_cli.RemoteGroup(), githubCLICLIRemote.Name)
+		return true, nil
+	}))
+
+	t.Run("preRemove", func(t *testing.T) {
+		testutil.Check(t, b.SetSetting(ctx, preRemoveHookKey, `cat > hook-input; echo "$BIOME_HOOK vetoed"; ! grep -q "^remove $BARRED$" hook-input`))
+		t.Setenv("BARRED", barRemote.Name)
+
+		err := b.RemoveOwners(ctx, []Owner{github_com_orirawlings})
+		if !errors.Is(err, ErrHookRejected) {
+			t.Fatalf("expected removal to be rejected by hook, was %v", err)
+		}
+		expectOwners(t, ctx, b, []Owner{github_com_cli, github_com_orirawlings})
+
+		// only remotes that are not kept by another owner are removed
+		input, err := os.ReadFile(filepath.Join(path, "hook-input"))
+		testutil.Check(t, err)
+		if expected := "remove " + barRemote.Name + "\n"; string(input) != expected {
+			t.Errorf("unexpected hook input: wanted %q, was %q", expected, input)
+		}
+
+		testutil.Check(t, b.RemoveOwners(ctx, []Owner{github_com_cli}))
+		expectOwners(t, ctx, b, []Owner{github_com_orirawlings})
+	})
+
+	t.Run("preUpdate", func(t *testing.T) {
+		testutil.Check(t, b.SetSetting(ctx, preUpdateHookKey, "exit 3"))
+
+		err := b.UpdateRemotes(ctx)
+		if !errors.Is(err, ErrHookRejected) {
+			t.Fatalf("expected update to be rejected by hook, was %v", err)
+		}
+		cfg, err := config.Read(ctx, path)
+		testutil.Check(t, err)
+		if cfg.HasSection("remote") {
+			t.Errorf("expected remotes not to be configured when the update is rejected")
+		}
+	})
+}
+
+func TestUpdateChanges(t *testing.T) {
+	previous := map[string]string{
+		"github.com/orirawlings/Bar":     "refs/remotes/github.com/orirawlings/Bar/",
+		"github.com/orirawlings/removed": "refs/remotes/github.com/orirawlings/removed/",
+	}
+	added := []remoteConfig{
+		{Remote: barRemote},
+		{Remote: githubCLICLIRemote},
+	}
+	expected := []hookChange{
+		{action: "add", remote: githubCLICLIRemote.Name},
+		{action: "remove", remote: "github.com/orirawlings/removed"},
+	}
+	if changes := updateChanges(previous, added); !slices.Equal(changes, expected) {
+		t.Errorf("unexpected changes: wanted %v, was %v", expected, changes)
+	}
+}
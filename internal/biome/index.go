@@ -0,0 +1,149 @@
+package biome
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+)
+
+const (
+	// remotesIndexFile is the name of the remotes index file, stored next to
+	// the biome's git config file.
+	remotesIndexFile = "biome-remotes.json"
+
+	// remotesIndexVersion is the format version of the remotes index. Indexes
+	// with any other version are rebuilt.
+	remotesIndexVersion = 6
+)
+
+// remotesIndex is a compact cache of the owner and remote metadata recorded
+// in the biome's git config, so that listing owners and remotes, ex. for the
+// remotes, heads, and stats commands, does not require parsing the entire git
+// config, which can be large for biomes with many remotes. The index records
+// a hash of the contents of the git config file it was built from, and is
+// rebuilt whenever the git config file changes. Hashing the config file is
+// much cheaper than parsing it. The references that
+// heads and stats inspect are not cached, since they change with every fetch,
+// and are read from the biome's reference store instead.
+type remotesIndex struct {
+	Version int `json:"version"`
+
+	// Config is the SHA-256 hash of the git config file contents the index
+	// was built from, see [hashConfig].
+	Config string `json:"config"`
+
+	// RefTemplate is the biome's reference namespace template, see
+	// [refTemplate].
+	RefTemplate string `json:"refTemplate,omitempty"`
+
+	// Attic records whether the references of archived and locked remotes
+	// are kept in the attic, see [atticEnabled].
+	Attic bool `json:"attic,omitempty"`
+
+	// Owners lists the biome's owners, as recorded by the biome.owners
+	// option.
+	Owners []string `json:"owners,omitempty"`
+
+	// Remotes lists the metadata of each remote as pairs of the biome.remotes
+	// option, ex. `active` or `internal`, and the remote name.
+	Remotes [][2]string `json:"remotes"`
+}
+
+// hashConfig returns the hex encoded SHA-256 hash of the contents of the git
+// config file at path. A missing config file has the hash of empty contents.
+func hashConfig(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// remotesIndex returns the metadata of each owner and remote from the remotes
+// index, rebuilding the index if it is missing or stale.
+func (b *biome) remotesIndex(ctx context.Context) (remotesIndex, error) {
+	cfgPath, err := config.Path(ctx, b.path)
+	if err != nil {
+		return remotesIndex{}, err
+	}
+	indexPath := filepath.Join(filepath.Dir(cfgPath), remotesIndexFile)
+
+	// hash the config before reading it, so that the index is considered
+	// stale if the config changes while the index is built
+	hash, err := hashConfig(cfgPath)
+	if err != nil {
+		return remotesIndex{}, err
+	}
+	if data, err := os.ReadFile(indexPath); err == nil {
+		var idx remotesIndex
+		if err := json.Unmarshal(data, &idx); err == nil && idx.Version == remotesIndexVersion && idx.Config == hash {
+			return idx, nil
+		}
+	}
+
+	cfg, err := config.ReadFile(cfgPath)
+	if err != nil {
+		return remotesIndex{}, err
+	}
+	idx := remotesIndex{
+		Version:     remotesIndexVersion,
+		Config:      hash,
+		RefTemplate: refTemplate(cfg),
+		Attic:       atticEnabled(cfg),
+		Owners:      cfg.Section(section).OptionAll(ownersOpt),
+	}
+	for _, opt := range cfg.Section(section).Subsection(remotesSubsection).Options {
+		idx.Remotes = append(idx.Remotes, [2]string{opt.Key, opt.Value})
+	}
+
+	// upstreams recorded explicitly for a remote follow, and so override,
+	// the upstreams discovered from GitHub
+	for _, ss := range cfg.Section(section).Subsections {
+		remote, ok := strings.CutPrefix(ss.Name, remoteSubsectionPrefix)
+		if upstream := ss.Options.Get("upstream"); ok && upstream != "" {
+			idx.Remotes = append(idx.Remotes, [2]string{upstreamOpt, remote + " " + upstream})
+		}
+		if ok && ss.Options.Get(bundleOpt) != "" {
+			idx.Remotes = append(idx.Remotes, [2]string{evictedOpt, remote})
+		}
+	}
+
+	// the index is only a cache, so failing to write it, ex. in a read-only
+	// biome, is not an error
+	_ = writeFileAtomic(indexPath, idx)
+	return idx, nil
+}
+
+// writeFileAtomic encodes v as JSON to path, see [writeAtomic].
+func writeFileAtomic(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return writeAtomic(path, data)
+}
+
+// writeAtomic writes data to a temporary file and renames it to path, so
+// readers never observe a partially written file.
+func writeAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
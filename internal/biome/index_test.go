@@ -0,0 +1,88 @@
+package biome
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_remotesIndex(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	indexPath := filepath.Join(path, remotesIndexFile)
+
+	addRemote := func(category, name string) {
+		t.Helper()
+		testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+			cfg.Section(section).Subsection(remotesSubsection).AddOption(category, name)
+			return true, nil
+		}))
+	}
+	expectRemotes := func(expected []Remote) {
+		t.Helper()
+		remotes, err := b.Remotes(ctx, AllRemoteCategories...)
+		testutil.Check(t, err)
+		if !slices.Equal(remotes, expected) {
+			t.Errorf("unexpected remotes: wanted %v, was %v", expected, remotes)
+		}
+	}
+
+	expectRemotes(nil)
+	if _, err := os.Stat(indexPath); err != nil {
+		t.Errorf("expected remotes index to be written: %v", err)
+	}
+
+	// the index is rebuilt when the config changes
+	addRemote(activeOpt, barRemote.Name)
+	addRemote(archivedOpt, archivedRemote.Name)
+	expectRemotes([]Remote{archivedRemote, barRemote})
+
+	// the index is rebuilt when the config is rewritten with the same size
+	// and modification time
+	cfgPath := filepath.Join(path, "config")
+	info, err := os.Stat(cfgPath)
+	testutil.Check(t, err)
+	data, err := os.ReadFile(cfgPath)
+	testutil.Check(t, err)
+	testutil.Check(t, os.WriteFile(cfgPath, bytes.Replace(data, []byte("active = "), []byte("locked = "), 1), 0o644))
+	testutil.Check(t, os.Chtimes(cfgPath, info.ModTime(), info.ModTime()))
+	expectRemotes([]Remote{archivedRemote, {Name: barRemote.Name, Locked: true}})
+	testutil.Check(t, os.WriteFile(cfgPath, data, 0o644))
+	testutil.Check(t, os.Chtimes(cfgPath, info.ModTime(), info.ModTime()))
+	expectRemotes([]Remote{archivedRemote, barRemote})
+
+	// the index is used while the config is unchanged
+	idx, err := b.remotesIndex(ctx)
+	testutil.Check(t, err)
+	idx.Remotes = idx.Remotes[:1]
+	testutil.Check(t, writeFileAtomic(indexPath, idx))
+	expectRemotes([]Remote{barRemote})
+
+	// owners are listed from the index too
+	idx.Owners = []string{github_com_orirawlings.String()}
+	testutil.Check(t, writeFileAtomic(indexPath, idx))
+	owners, err := b.Owners(ctx)
+	testutil.Check(t, err)
+	if expected := []Owner{github_com_orirawlings}; !slices.Equal(owners, expected) {
+		t.Errorf("unexpected owners: wanted %v, was %v", expected, owners)
+	}
+
+	// a corrupt index is rebuilt
+	testutil.Check(t, os.WriteFile(indexPath, []byte("{"), 0666))
+	expectRemotes([]Remote{archivedRemote, barRemote})
+	owners, err = b.Owners(ctx)
+	testutil.Check(t, err)
+	if len(owners) != 0 {
+		t.Errorf("expected no owners, was %v", owners)
+	}
+}
@@ -0,0 +1,247 @@
+package biome
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+)
+
+const (
+	// journalFile is the name of the biome's journal, stored next to the
+	// biome's git config file.
+	journalFile = "biome-journal.jsonl"
+
+	// fetchEstimateSamples is the number of a remote's most recent
+	// successful fetches that are averaged to estimate its next fetch.
+	fetchEstimateSamples = 5
+)
+
+// JournalOp identifies the kind of operation recorded by a [JournalEntry].
+type JournalOp string
+
+const (
+	// FetchOp records the fetch of a single remote.
+	FetchOp JournalOp = "fetch"
+
+	// RefsOp records the number of references of a single remote, counted
+	// after a fetch, so that the growth of remotes can be monitored.
+	RefsOp JournalOp = "refs"
+
+	// ForcedUpdateOp records a non-fast-forward update of a remote's branch
+	// by a fetch, see [ForcedUpdate].
+	ForcedUpdateOp JournalOp = "forcedUpdate"
+
+	// FsckOp records a connectivity-only integrity check of the biome's
+	// objects, see [biome.Fsck].
+	FsckOp JournalOp = "fsck"
+)
+
+// JournalEntry records an operation performed on the biome. The journal
+// keeps a history of operations, ex. how long each remote took to fetch, so
+// that future operations can be planned from past ones.
+type JournalEntry struct {
+
+	// Time when the operation started.
+	Time time.Time `json:"time"`
+
+	// Op is the kind of operation.
+	Op JournalOp `json:"op"`
+
+	// Remote is the name of the remote the operation applied to, if any.
+	Remote string `json:"remote,omitempty"`
+
+	// Duration of the operation.
+	Duration time.Duration `json:"duration,omitempty"`
+
+	// Failed is true if the operation did not succeed.
+	Failed bool `json:"failed,omitempty"`
+
+	// Error is an excerpt of the error messages of a failed operation, ex.
+	// the last lines git printed to stderr before a fetch failed.
+	Error string `json:"error,omitempty"`
+
+	// ErrorClass is the kind of error that a failed operation ran into, see
+	// [ClassifyFetchError].
+	ErrorClass FetchErrorClass `json:"errorClass,omitempty"`
+
+	// Refs is the number of references counted by a [RefsOp].
+	Refs int `json:"refs,omitempty"`
+
+	// Branch is the name of the branch updated by a [ForcedUpdateOp].
+	Branch string `json:"branch,omitempty"`
+
+	// From is the object ID of the branch's commit before a
+	// [ForcedUpdateOp].
+	From string `json:"from,omitempty"`
+
+	// To is the object ID of the branch's commit after a [ForcedUpdateOp].
+	To string `json:"to,omitempty"`
+}
+
+// FetchErrorClass is the kind of error that a fetch of a remote ran into, so
+// that failed fetches can be triaged together.
+type FetchErrorClass string
+
+const (
+	// AuthError indicates that the fetch was not authorized, ex. because a
+	// token expired or lacks access to the repository.
+	AuthError FetchErrorClass = "auth"
+
+	// NotFoundError indicates that the repository was not found, ex. because
+	// it was deleted or renamed since remotes were last updated.
+	NotFoundError FetchErrorClass = "notFound"
+
+	// TimeoutError indicates that the fetch timed out or stalled.
+	TimeoutError FetchErrorClass = "timeout"
+
+	// PackError indicates that the packfile sent by the server could not be
+	// transferred or unpacked.
+	PackError FetchErrorClass = "pack"
+
+	// OtherError indicates any other error.
+	OtherError FetchErrorClass = "other"
+)
+
+// fetchErrorPatterns are substrings of git's error messages, in lower case,
+// that identify each class of fetch error, checked in order.
+var fetchErrorPatterns = []struct {
+	class    FetchErrorClass
+	patterns []string
+}{
+	{AuthError, []string{"authentication failed", "could not read username", "could not read password", "terminal prompts disabled", "permission denied", "the requested url returned error: 401", "the requested url returned error: 403"}},
+	{NotFoundError, []string{"not found", "does not exist", "the requested url returned error: 404"}},
+	{TimeoutError, []string{"timed out", "timeout", "operation too slow"}},
+	{PackError, []string{"pack", "early eof", "rpc failed", "did not send all necessary objects", "unexpected disconnect"}},
+}
+
+// ClassifyFetchError returns the class of the fetch error described by git's
+// error messages.
+func ClassifyFetchError(stderr string) FetchErrorClass {
+	stderr = strings.ToLower(stderr)
+	for _, p := range fetchErrorPatterns {
+		for _, pattern := range p.patterns {
+			if strings.Contains(stderr, pattern) {
+				return p.class
+			}
+		}
+	}
+	return OtherError
+}
+
+// journalPath returns the path of the biome's journal.
+func (b *biome) journalPath(ctx context.Context) (string, error) {
+	cfgPath, err := config.Path(ctx, b.path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(cfgPath), journalFile), nil
+}
+
+// Record appends the given entries to the biome's journal.
+func (b *biome) Record(ctx context.Context, entries ...JournalEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	path, err := b.journalPath(ctx)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+
+	// entries are appended with a single write, so concurrent writers do not
+	// interleave partial lines
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0666)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Journal returns the entries of the biome's journal, oldest first. Entries
+// that cannot be parsed, ex. a line truncated by a crash, are skipped.
+func (b *biome) Journal(ctx context.Context) ([]JournalEntry, error) {
+	path, err := b.journalPath(ctx)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err == nil {
+			entries = append(entries, e)
+		}
+	}
+	slices.SortStableFunc(entries, func(a, b JournalEntry) int {
+		return a.Time.Compare(b.Time)
+	})
+	return entries, scanner.Err()
+}
+
+// FetchEstimates estimates how long each remote will take to fetch, from the
+// average duration of its most recent successful fetches in the journal.
+// Remotes that have never been fetched successfully are omitted.
+func FetchEstimates(entries []JournalEntry) map[string]time.Duration {
+	samples := make(map[string][]time.Duration)
+	for _, e := range slices.Backward(entries) {
+		if e.Op != FetchOp || e.Failed || e.Remote == "" || len(samples[e.Remote]) == fetchEstimateSamples {
+			continue
+		}
+		samples[e.Remote] = append(samples[e.Remote], e.Duration)
+	}
+	estimates := make(map[string]time.Duration, len(samples))
+	for remote, durations := range samples {
+		var total time.Duration
+		for _, d := range durations {
+			total += d
+		}
+		estimates[remote] = total / time.Duration(len(durations))
+	}
+	return estimates
+}
+
+// FetchFailures returns the latest journal entry of each remote whose most
+// recent fetch failed, sorted by remote name.
+func FetchFailures(entries []JournalEntry) []JournalEntry {
+	latest := make(map[string]JournalEntry)
+	for _, e := range entries {
+		if e.Op == FetchOp && e.Remote != "" {
+			latest[e.Remote] = e
+		}
+	}
+	var failures []JournalEntry
+	for _, remote := range slices.Sorted(maps.Keys(latest)) {
+		if e := latest[remote]; e.Failed {
+			failures = append(failures, e)
+		}
+	}
+	return failures
+}
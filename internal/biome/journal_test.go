@@ -0,0 +1,116 @@
+package biome
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_Journal(t *testing.T) {
+	ctx := context.Background()
+	b := &biome{
+		path: testutil.TempRepo(t),
+	}
+
+	entries, err := b.Journal(ctx)
+	testutil.Check(t, err)
+	if len(entries) != 0 {
+		t.Errorf("expected empty journal, was %v", entries)
+	}
+
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	first := JournalEntry{Time: start, Op: FetchOp, Remote: barRemote.Name, Duration: time.Second}
+	second := JournalEntry{Time: start.Add(time.Minute), Op: FetchOp, Remote: githubCLICLIRemote.Name, Duration: time.Minute, Failed: true}
+	testutil.Check(t, b.Record(ctx, second))
+	testutil.Check(t, b.Record(ctx, first))
+
+	// a truncated entry is skipped
+	f, err := os.OpenFile(filepath.Join(b.path, journalFile), os.O_WRONLY|os.O_APPEND, 0666)
+	testutil.Check(t, err)
+	_, err = f.WriteString(`{"time":`)
+	testutil.Check(t, err)
+	testutil.Check(t, f.Close())
+
+	entries, err = b.Journal(ctx)
+	testutil.Check(t, err)
+	expected := []JournalEntry{first, second}
+	if !slices.EqualFunc(entries, expected, func(a, b JournalEntry) bool {
+		return a.Time.Equal(b.Time) && a.Op == b.Op && a.Remote == b.Remote && a.Duration == b.Duration && a.Failed == b.Failed
+	}) {
+		t.Errorf("unexpected journal:\nwanted %v\nwas    %v", expected, entries)
+	}
+}
+
+func TestFetchEstimates(t *testing.T) {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	var entries []JournalEntry
+	fetched := func(remote string, d time.Duration, failed bool) {
+		entries = append(entries, JournalEntry{
+			Time:     start.Add(time.Duration(len(entries)) * time.Hour),
+			Op:       FetchOp,
+			Remote:   remote,
+			Duration: d,
+			Failed:   failed,
+		})
+	}
+
+	// only the most recent samples are averaged
+	fetched("a", time.Hour, false)
+	for range fetchEstimateSamples {
+		fetched("a", 2*time.Second, false)
+	}
+	// failures are ignored
+	fetched("b", 4*time.Second, false)
+	fetched("b", 6*time.Second, false)
+	fetched("b", time.Hour, true)
+	fetched("c", time.Hour, true)
+
+	expected := map[string]time.Duration{
+		"a": 2 * time.Second,
+		"b": 5 * time.Second,
+	}
+	if estimates := FetchEstimates(entries); !maps.Equal(estimates, expected) {
+		t.Errorf("unexpected estimates: wanted %v, was %v", expected, estimates)
+	}
+}
+
+func TestClassifyFetchError(t *testing.T) {
+	for _, tc := range []struct {
+		stderr   string
+		expected FetchErrorClass
+	}{
+		{"fatal: Authentication failed for 'https://github.com/cli/cli.git/'", AuthError},
+		{"fatal: could not read Username for 'https://github.com': terminal prompts disabled", AuthError},
+		{"fatal: repository 'https://github.com/cli/gone.git/' not found", NotFoundError},
+		{"fatal: unable to access 'https://github.com/cli/cli.git/': Failed to connect to github.com port 443 after 75001 ms: Connection timed out", TimeoutError},
+		{"error: RPC failed; curl 92 HTTP/2 stream 5 was not closed cleanly\nfatal: early EOF\nfatal: fetch-pack: invalid index-pack output", PackError},
+		{"fatal: unable to look up current user in the passwd file", OtherError},
+	} {
+		if class := ClassifyFetchError(tc.stderr); class != tc.expected {
+			t.Errorf("unexpected class of %q: wanted %s, was %s", tc.stderr, tc.expected, class)
+		}
+	}
+}
+
+func TestFetchFailures(t *testing.T) {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	entries := []JournalEntry{
+		{Time: start, Op: FetchOp, Remote: "b", Failed: true, ErrorClass: AuthError},
+		{Time: start, Op: FetchOp, Remote: "a", Failed: true, ErrorClass: NotFoundError},
+		{Time: start.Add(time.Hour), Op: FetchOp, Remote: "b"},
+		{Time: start.Add(time.Hour), Op: FetchOp, Remote: "c", Failed: true, ErrorClass: PackError},
+	}
+	failures := FetchFailures(entries)
+	expected := []JournalEntry{entries[1], entries[3]}
+	if !slices.EqualFunc(failures, expected, func(a, b JournalEntry) bool {
+		return a.Remote == b.Remote && a.ErrorClass == b.ErrorClass
+	}) {
+		t.Errorf("unexpected failures: wanted %v, was %v", expected, failures)
+	}
+}
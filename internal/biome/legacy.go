@@ -0,0 +1,144 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	slicesutil "github.com/orirawlings/gh-biome/internal/util/slices"
+)
+
+// legacyArchivedOpt is the git remote option key that the deprecated
+// `add-remotes` command set on the remotes of archived repositories, ex.
+// `remote.github.com/cli/legacy.archived`.
+const legacyArchivedOpt = "archived"
+
+// errNotLegacy indicates that a repository was not configured by the
+// deprecated `add-remotes` command.
+var errNotLegacy = errors.New("repository was not configured by the legacy add-remotes command")
+
+// NeedsMigrationError indicates that a repository was configured by the
+// deprecated `add-remotes` command, so it must be upgraded with
+// [MigrateLegacy] before it can be loaded as a biome.
+type NeedsMigrationError struct {
+
+	// Path is the filesystem directory path of the repository.
+	Path string
+}
+
+func (e *NeedsMigrationError) Error() string {
+	return fmt.Sprintf("repository was configured by the legacy add-remotes command and needs migration: %s", e.Path)
+}
+
+// Migration describes how a repository configured by the deprecated
+// `add-remotes` command was upgraded to a biome.
+type Migration struct {
+
+	// Owners lists the owners inferred from the names of the legacy remotes.
+	Owners []Owner
+
+	// Remotes lists the names of the legacy remotes that joined the biome.
+	Remotes []string
+
+	// Skipped lists the names of the remotes that are not named
+	// <host>/<owner>/<repo>, so their owner could not be inferred. They are
+	// kept as they are, outside of the biome's remote groups.
+	Skipped []string
+}
+
+// isLegacy reports whether a loaded config is of a repository configured by
+// the deprecated `add-remotes` command: no biome schema version is recorded,
+// and its remotes record whether their repositories are archived.
+func isLegacy(cfg *config.Config) bool {
+	if cfg.Section(section).Options.Get(versionOpt) != "" {
+		return false
+	}
+	for _, ss := range cfg.Section("remote").Subsections {
+		if ss.HasOption(legacyArchivedOpt) {
+			return true
+		}
+	}
+	return false
+}
+
+// legacyOwnerOf infers the owner of a legacy remote from its name, ex.
+// github.com/cli for github.com/cli/cli.
+func legacyOwnerOf(name string) (Owner, bool) {
+	parts := strings.Split(name, "/")
+	if len(parts) != 3 || slices.Contains(parts, "") {
+		return Owner{}, false
+	}
+	owner, err := ParseOwner(parts[0] + "/" + parts[1])
+	return owner, err == nil
+}
+
+// MigrateLegacy upgrades a repository at the given filesystem directory path
+// that was configured by the deprecated `add-remotes` command to the v1 biome
+// schema. Owners are inferred from the names of the repository's remotes, and
+// each remote is recorded as active or archived according to its legacy
+// `archived` option, which is then removed. The migration is
+// non-destructive: remote URLs, fetch refspecs, and references are kept, and
+// the next update of the biome's remotes reconciles them with GitHub.
+func MigrateLegacy(ctx context.Context, path string, opts ...BiomeOption) (Biome, Migration, error) {
+	var migration Migration
+	b := &biome{
+		path: path,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	var needsMigration *NeedsMigrationError
+	switch err := b.validate(ctx); {
+	case err == nil:
+		return nil, migration, fmt.Errorf("%w: %s", errAlreadyBiome, path)
+	case err == errVersionNotSet, errors.As(err, &needsMigration):
+	default:
+		return nil, migration, err
+	}
+
+	err := b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		if !isLegacy(cfg) {
+			return false, fmt.Errorf("%w: %s", errNotLegacy, path)
+		}
+		// the edit is retried if the config changes concurrently
+		migration = Migration{}
+		var ownerRefs []string
+		biomeSection := cfg.Section(section)
+		metadata := biomeSection.Subsection(remotesSubsection)
+		for _, ss := range cfg.Section("remote").Subsections {
+			owner, ok := legacyOwnerOf(ss.Name)
+			if !ok {
+				migration.Skipped = append(migration.Skipped, ss.Name)
+				continue
+			}
+			if !slices.Contains(ownerRefs, owner.String()) {
+				ownerRefs = append(ownerRefs, owner.String())
+				migration.Owners = append(migration.Owners, owner)
+			}
+			migration.Remotes = append(migration.Remotes, ss.Name)
+
+			category := activeOpt
+			if archived, _ := ParseBool(ss.Option(legacyArchivedOpt)); archived {
+				category = archivedOpt
+			}
+			metadata.AddOption(category, ss.Name)
+			ss.RemoveOption(legacyArchivedOpt)
+			cfg.Section("remotes").AddOption(owner.RemoteGroup(), ss.Name)
+		}
+		for _, ownerRef := range slicesutil.SortedUnique(ownerRefs) {
+			biomeSection.AddOption(ownersOpt, ownerRef)
+		}
+		biomeSection.SetOption(versionOpt, v1)
+		return true, nil
+	})
+	if err != nil {
+		return nil, migration, err
+	}
+	slices.SortFunc(migration.Owners, func(a, b Owner) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return b, migration, nil
+}
@@ -0,0 +1,108 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+// tempLegacyRepo creates a repository configured as the deprecated
+// `add-remotes` command did, with remotes of two owners, one of them
+// archived, and a remote that is not named <host>/<owner>/<repo>.
+func tempLegacyRepo(t *testing.T) string {
+	t.Helper()
+	path := testutil.TempRepo(t)
+	for _, r := range []struct {
+		name     string
+		archived string
+	}{
+		{githubCLICLIRemote.Name, "false"},
+		{archivedRemote.Name, "true"},
+		{barRemote.Name, ""},
+		{"origin", ""},
+	} {
+		testutil.Execute(t, "git", "-C", path, "config", "remote."+r.name+".url", "https://"+r.name+".git")
+		testutil.Execute(t, "git", "-C", path, "config", "remote."+r.name+".fetch", "+refs/*:refs/remotes/"+r.name+"/*")
+		if r.archived != "" {
+			testutil.Execute(t, "git", "-C", path, "config", "remote."+r.name+".archived", r.archived)
+		}
+	}
+	return path
+}
+
+func TestMigrateLegacy(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("legacy", func(t *testing.T) {
+		path := tempLegacyRepo(t)
+
+		// legacy repositories are recognized when loaded
+		var needsMigration *NeedsMigrationError
+		if _, err := Load(ctx, path, biomeOptions()...); !errors.As(err, &needsMigration) || needsMigration.Path != path {
+			t.Errorf("expected %T for %s, was %v", needsMigration, path, err)
+		}
+		if _, err := Init(ctx, path, biomeOptions()...); !errors.As(err, &needsMigration) {
+			t.Errorf("expected %T initializing a legacy repository, was %v", needsMigration, err)
+		}
+
+		commit := commitTree(t, path, "initial commit")
+		testutil.Execute(t, "git", "-C", path, "update-ref", "refs/remotes/github.com/cli/cli/heads/trunk", commit)
+
+		b, migration, err := MigrateLegacy(ctx, path, biomeOptions()...)
+		testutil.Check(t, err)
+		if expected := []Owner{github_com_cli, github_com_orirawlings}; !slices.Equal(migration.Owners, expected) {
+			t.Errorf("expected owners %v, was %v", expected, migration.Owners)
+		}
+		if expected := []string{"origin"}; !slices.Equal(migration.Skipped, expected) {
+			t.Errorf("expected skipped remotes %q, was %q", expected, migration.Skipped)
+		}
+		if len(migration.Remotes) != 3 {
+			t.Errorf("expected 3 migrated remotes, was %q", migration.Remotes)
+		}
+
+		owners, err := b.Owners(ctx)
+		testutil.Check(t, err)
+		if !slices.Equal(owners, migration.Owners) {
+			t.Errorf("expected owners %v, was %v", migration.Owners, owners)
+		}
+		for category, expected := range map[RemoteCategory][]Remote{
+			Active:   {githubCLICLIRemote, barRemote},
+			Archived: {archivedRemote},
+		} {
+			remotes, err := b.Remotes(ctx, category)
+			testutil.Check(t, err)
+			if !slices.Equal(remotes, expected) {
+				t.Errorf("expected %s remotes %v, was %v", category, expected, remotes)
+			}
+		}
+		members, err := b.RemoteGroupMembers(ctx, github_com_orirawlings.RemoteGroup())
+		testutil.Check(t, err)
+		if expected := []string{archivedRemote.Name, barRemote.Name}; !slices.Equal(members, expected) {
+			t.Errorf("expected remote group members %q, was %q", expected, members)
+		}
+
+		// remotes and references are kept
+		assertConfigNotSet(t, path, "remote.github.com/orirawlings/archived.archived")
+		assertGitConfig(t, path, "remote.origin.url", "https://origin.git")
+		if trunk := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "rev-parse", "refs/remotes/github.com/cli/cli/heads/trunk")); trunk != commit {
+			t.Errorf("expected reference to be kept at %s, was %s", commit, trunk)
+		}
+		load(t, ctx, path, true)
+
+		if _, _, err := MigrateLegacy(ctx, path, biomeOptions()...); !errors.Is(err, errAlreadyBiome) {
+			t.Errorf("expected %v, was %v", errAlreadyBiome, err)
+		}
+	})
+
+	t.Run("not legacy", func(t *testing.T) {
+		path := testutil.TempRepo(t)
+		if _, _, err := MigrateLegacy(ctx, path, biomeOptions()...); !errors.Is(err, errNotLegacy) {
+			t.Errorf("expected %v, was %v", errNotLegacy, err)
+		}
+		assertConfigNotSet(t, path, versionKey)
+	})
+}
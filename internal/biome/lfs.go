@@ -0,0 +1,102 @@
+package biome
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+)
+
+// LFSPolicy controls how Git LFS objects of the biome's remotes are handled.
+// A naive fetch of repositories that store many large files in Git LFS can
+// either fail or consume far more disk than the git objects themselves.
+type LFSPolicy string
+
+const (
+	// LFSSkip never downloads Git LFS objects. Git LFS is told to skip
+	// smudging for commands run against the biome, so checkouts contain
+	// pointer files instead of downloading objects on demand.
+	LFSSkip LFSPolicy = "skip"
+
+	// LFSPointers fetches git objects only, which include the Git LFS pointer
+	// files. Git LFS objects are downloaded on demand, ex. when a tool checks
+	// out a working tree from the biome.
+	LFSPointers LFSPolicy = "pointers"
+
+	// LFSSelected fetches git objects like [LFSPointers], and also fetches the
+	// Git LFS objects of each remote's default branch for remotes that have
+	// been selected with the per-remote lfs setting.
+	LFSSelected LFSPolicy = "selected"
+)
+
+// lfsPolicyKey is the git config key of the setting that controls the
+// biome's [LFSPolicy].
+const lfsPolicyKey = "biome.lfs.policy"
+
+// lfsOpt is the per-remote setting option that selects a remote for fetching
+// Git LFS objects under the [LFSSelected] policy.
+const lfsOpt = "lfs"
+
+// lfsPolicies lists the valid values of [LFSPolicy].
+var lfsPolicies = []string{string(LFSSkip), string(LFSPointers), string(LFSSelected)}
+
+// ParseLFSPolicy parses the name of an [LFSPolicy].
+func ParseLFSPolicy(value string) (LFSPolicy, error) {
+	if err := validateOneOf(lfsPolicies...)(value); err != nil {
+		return "", fmt.Errorf("invalid lfs policy: %q: %w", value, err)
+	}
+	return LFSPolicy(value), nil
+}
+
+// Env returns the environment variables that apply the policy to git
+// commands, and other tools, run against the biome.
+func (p LFSPolicy) Env() []string {
+	if p == LFSSkip {
+		return []string{"GIT_LFS_SKIP_SMUDGE=1"}
+	}
+	return nil
+}
+
+// LFSRemotes lists the fetchable remotes whose Git LFS objects are fetched
+// under the [LFSSelected] policy, sorted by name.
+func (b *biome) LFSRemotes(ctx context.Context) ([]Remote, error) {
+	remotes, err := b.Remotes(ctx, FetchableRemoteCategories...)
+	if err != nil {
+		return nil, err
+	}
+	var selected []Remote
+	err = b.readConfig(ctx, func(ctx context.Context, cfg *config.Config) error {
+		for _, r := range remotes {
+			if lfs, _ := ParseBool(remoteSetting(cfg, r.Name, lfsOpt)); lfs {
+				selected = append(selected, r)
+			}
+		}
+		return nil
+	})
+	return selected, err
+}
+
+// remoteSetting returns the effective value of the per-remote setting option
+// for the named remote from a loaded config.
+func remoteSetting(cfg *config.Config, remote, option string) string {
+	i := slices.IndexFunc(remoteSettings, func(s Setting) bool {
+		return s.Key == option
+	})
+	if i < 0 {
+		panic(fmt.Errorf("%w: %s", errUnknownSetting, option))
+	}
+	s := remoteSettings[i]
+	s.Key = RemoteSettingKey(remote, s.Key)
+	return settingValue(cfg, s).Value
+}
+
+// LFS records the biome's [LFSPolicy] when a new biome is initialized. If
+// this option is not given, the policy is left unset and defaults to
+// [LFSPointers]. The option has no effect when loading a biome or
+// initializing a biome that already exists.
+func LFS(policy LFSPolicy) BiomeOption {
+	return func(b *biome) {
+		b.lfsPolicy = policy
+	}
+}
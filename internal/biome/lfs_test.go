@@ -0,0 +1,61 @@
+package biome
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestParseLFSPolicy(t *testing.T) {
+	for _, policy := range []LFSPolicy{LFSSkip, LFSPointers, LFSSelected} {
+		parsed, err := ParseLFSPolicy(string(policy))
+		testutil.Check(t, err)
+		if parsed != policy {
+			t.Errorf("expected %q, was %q", policy, parsed)
+		}
+	}
+	_, err := ParseLFSPolicy("all")
+	testutil.ExpectError(t, err)
+
+	if env := LFSSkip.Env(); !slices.Equal(env, []string{"GIT_LFS_SKIP_SMUDGE=1"}) {
+		t.Errorf("unexpected environment for %q: %v", LFSSkip, env)
+	}
+	if env := LFSPointers.Env(); env != nil {
+		t.Errorf("unexpected environment for %q: %v", LFSPointers, env)
+	}
+}
+
+func TestBiome_LFSRemotes(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		ss := cfg.Section(section).Subsection(remotesSubsection)
+		for _, r := range []Remote{barRemote, githubCLICLIRemote, headlessRemote} {
+			ss.AddOption(activeOpt, r.Name)
+		}
+		return true, nil
+	}))
+
+	remotes, err := b.LFSRemotes(ctx)
+	testutil.Check(t, err)
+	if len(remotes) != 0 {
+		t.Errorf("expected no remotes selected for LFS, was %v", remotes)
+	}
+
+	testutil.Check(t, b.SetSetting(ctx, RemoteSettingKey(barRemote.Name, lfsOpt), "true"))
+	testutil.Check(t, b.SetSetting(ctx, RemoteSettingKey(headlessRemote.Name, lfsOpt), "false"))
+	testutil.ExpectError(t, b.SetSetting(ctx, RemoteSettingKey(githubCLICLIRemote.Name, lfsOpt), "sometimes"))
+
+	remotes, err = b.LFSRemotes(ctx)
+	testutil.Check(t, err)
+	if expected := []Remote{{Name: barRemote.Name}}; !slices.Equal(remotes, expected) {
+		t.Errorf("unexpected remotes selected for LFS: wanted %v, was %v", expected, remotes)
+	}
+}
@@ -0,0 +1,79 @@
+package biome
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+)
+
+// MaintenanceMode controls whether a new biome is registered with
+// `git maintenance`, and which maintenance tasks run for it.
+type MaintenanceMode string
+
+const (
+	// MaintenanceOff does not register the biome with git maintenance, ex. for
+	// shared or CI environments that must not run background maintenance.
+	// git's automatic maintenance after some commands still applies.
+	MaintenanceOff MaintenanceMode = "off"
+
+	// MaintenanceMinimal registers the biome with git maintenance, running
+	// only the tasks that keep the biome fast to query: writing the
+	// commit-graph hourly, and packing loose objects daily.
+	MaintenanceMinimal MaintenanceMode = "minimal"
+
+	// MaintenanceFull registers the biome with git maintenance, using git's
+	// incremental strategy, except for the prefetch task. Fetching the
+	// biome's remotes is left to biome, which applies the biome's
+	// credentials, budgets, and pacing.
+	MaintenanceFull MaintenanceMode = "full"
+)
+
+// maintenanceModes lists the valid values of [MaintenanceMode].
+var maintenanceModes = []string{string(MaintenanceOff), string(MaintenanceMinimal), string(MaintenanceFull)}
+
+// ParseMaintenanceMode parses the name of a [MaintenanceMode].
+func ParseMaintenanceMode(value string) (MaintenanceMode, error) {
+	if err := validateOneOf(maintenanceModes...)(value); err != nil {
+		return "", fmt.Errorf("invalid maintenance mode: %q: %w", value, err)
+	}
+	return MaintenanceMode(value), nil
+}
+
+// configure records the git config settings that select the maintenance
+// tasks of the mode.
+func (m MaintenanceMode) configure(c *config.Config) {
+	switch m {
+	case MaintenanceMinimal:
+		c.SetOption("maintenance", "", "strategy", "none")
+		c.SetOption("maintenance", "commit-graph", "enabled", "true")
+		c.SetOption("maintenance", "commit-graph", "schedule", "hourly")
+		c.SetOption("maintenance", "loose-objects", "enabled", "true")
+		c.SetOption("maintenance", "loose-objects", "schedule", "daily")
+	case MaintenanceFull:
+		c.SetOption("maintenance", "", "strategy", "incremental")
+		c.SetOption("maintenance", "prefetch", "enabled", "false")
+	}
+}
+
+// registerMaintenance adds the biome to the repositories maintained by git
+// maintenance in the user's global git config. Maintenance runs on the
+// schedule installed by `git maintenance start`.
+func (b *biome) registerMaintenance(ctx context.Context) error {
+	cmd := exec.CommandContext(ctx, "git", "-C", b.path, "maintenance", "register")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("could not %q: %w\n%s", cmd, err, out)
+	}
+	return nil
+}
+
+// Maintenance registers a new biome with git maintenance in the given
+// [MaintenanceMode] when it is initialized. If this option is not given, or
+// the mode is [MaintenanceOff], the biome is not registered. The option has
+// no effect when loading a biome or initializing a biome that already exists.
+func Maintenance(mode MaintenanceMode) BiomeOption {
+	return func(b *biome) {
+		b.maintenance = mode
+	}
+}
@@ -0,0 +1,86 @@
+package biome
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestParseMaintenanceMode(t *testing.T) {
+	for _, mode := range []MaintenanceMode{MaintenanceOff, MaintenanceMinimal, MaintenanceFull} {
+		parsed, err := ParseMaintenanceMode(string(mode))
+		testutil.Check(t, err)
+		if parsed != mode {
+			t.Errorf("expected %q, was %q", mode, parsed)
+		}
+	}
+	_, err := ParseMaintenanceMode("incremental")
+	testutil.ExpectError(t, err)
+}
+
+func TestMaintenanceMode_configure(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		mode     MaintenanceMode
+		expected map[string]string
+	}{
+		{
+			mode: MaintenanceMinimal,
+			expected: map[string]string{
+				"maintenance.strategy":               "none",
+				"maintenance.commit-graph.enabled":   "true",
+				"maintenance.commit-graph.schedule":  "hourly",
+				"maintenance.loose-objects.enabled":  "true",
+				"maintenance.loose-objects.schedule": "daily",
+			},
+		},
+		{
+			mode: MaintenanceFull,
+			expected: map[string]string{
+				"maintenance.strategy":         "incremental",
+				"maintenance.prefetch.enabled": "false",
+			},
+		},
+	} {
+		t.Run(string(tc.mode), func(t *testing.T) {
+			path := testutil.TempRepo(t)
+			b := &biome{
+				path:          path,
+				editorOptions: []config.EditorOption{config.Direct()},
+			}
+			testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+				tc.mode.configure(cfg)
+				return true, nil
+			}))
+			for key, value := range tc.expected {
+				if v := getGitConfig(t, path, key); v != value {
+					t.Errorf("expected %s=%q, was %q", key, value, v)
+				}
+			}
+		})
+	}
+}
+
+func TestBiome_registerMaintenance(t *testing.T) {
+	ctx := context.Background()
+	global := filepath.Join(t.TempDir(), "gitconfig")
+	t.Setenv("GIT_CONFIG_GLOBAL", global)
+
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path: path,
+	}
+	testutil.Check(t, b.registerMaintenance(ctx))
+
+	repos := strings.Fields(testutil.Execute(t, "git", "config", "--file", global, "--get-all", "maintenance.repo"))
+	if !slices.ContainsFunc(repos, func(repo string) bool {
+		return filepath.Base(repo) == filepath.Base(path)
+	}) {
+		t.Errorf("expected %s to be registered for maintenance, was %q", path, repos)
+	}
+}
@@ -0,0 +1,103 @@
+package biome
+
+import (
+	"cmp"
+	"context"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+)
+
+// includeOpt is a per-owner setting option with glob patterns of repository
+// names, that limits the owner's remotes to the matching repositories, ex.
+// those listed by an imported manifest.
+const includeOpt = "include"
+
+// ManifestProject pins a remote of the biome to the commit at its HEAD, so
+// that the remote's repository can be checked out elsewhere exactly as it
+// is in the biome, ex. by multi-repository tools such as repo or jiri.
+type ManifestProject struct {
+
+	// Remote is the name of the remote, ex. github.com/cli/cli.
+	Remote string
+
+	// URL is the URL that the remote is fetched from.
+	URL string
+
+	// Branch is the remote's default branch, ex. main. It is empty if the
+	// remote's HEAD reference is missing.
+	Branch string
+
+	// Commit is the object ID of the commit at the remote's HEAD. It is
+	// empty if the remote's default branch has not been fetched.
+	Commit string
+}
+
+// Manifest returns a project pinned to the commit at its HEAD for each of
+// the biome's fetchable remotes, or only for the remotes of the given owners,
+// sorted by remote name.
+func (b *biome) Manifest(ctx context.Context, owners ...Owner) ([]ManifestProject, error) {
+	remotes, err := b.Remotes(ctx, FetchableRemoteCategories...)
+	if err != nil {
+		return nil, err
+	}
+	if len(owners) > 0 {
+		remotes = slices.DeleteFunc(remotes, func(r Remote) bool {
+			return !slices.ContainsFunc(owners, func(owner Owner) bool {
+				return owner.String() == path.Dir(r.Name)
+			})
+		})
+	}
+	var heads []string
+	for _, r := range remotes {
+		heads = append(heads, r.Head())
+	}
+	refs, err := b.listRefs(ctx, heads)
+	if err != nil {
+		return nil, err
+	}
+	targets := make(map[string]string)
+	var targetNames []string
+	for _, ref := range refs {
+		if ref.Symref != "" {
+			targets[ref.Name] = ref.Symref
+			targetNames = append(targetNames, ref.Symref)
+		}
+	}
+	refs, err = b.listRefs(ctx, targetNames)
+	if err != nil {
+		return nil, err
+	}
+	commits := make(map[string]string)
+	for _, ref := range refs {
+		commits[ref.Name] = ref.ObjectName
+	}
+
+	// remotes are fetched from their configured URLs, whose scheme depends
+	// on the provider of their host
+	urls := make(map[string]string)
+	if err := b.readConfig(ctx, func(ctx context.Context, cfg *config.Config) error {
+		for _, ss := range cfg.Section("remote").Subsections {
+			urls[ss.Name] = ss.Options.Get("url")
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	var projects []ManifestProject
+	for _, r := range remotes {
+		p := ManifestProject{Remote: r.Name, URL: cmp.Or(urls[r.Name], r.FetchURL())}
+		if target, ok := targets[r.Head()]; ok {
+			p.Branch = strings.TrimPrefix(target, r.RefNamespace()+"heads/")
+			p.Commit = commits[target]
+		}
+		projects = append(projects, p)
+	}
+	slices.SortFunc(projects, func(a, b ManifestProject) int {
+		return strings.Compare(a.Remote, b.Remote)
+	})
+	return projects, nil
+}
@@ -0,0 +1,60 @@
+package biome
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_Manifest(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		for _, r := range []Remote{barRemote, headlessRemote, githubCLICLIRemote} {
+			cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, r.Name)
+		}
+		// remotes are listed with the URL they are fetched from
+		cfg.Section("remote").Subsection(headlessRemote.Name).SetOption("url", "ssh://github.com/orirawlings/headless.git")
+		return true, nil
+	}))
+	commit := createCommitFor(t, ctx, path, []string{
+		"refs/remotes/github.com/orirawlings/bar/heads/main",
+		"refs/remotes/github.com/cli/cli/heads/trunk",
+	})
+	testutil.Execute(t, "git", "-C", path, "symbolic-ref", barRemote.Head(), "refs/remotes/github.com/orirawlings/bar/heads/main")
+	testutil.Execute(t, "git", "-C", path, "symbolic-ref", githubCLICLIRemote.Head(), "refs/remotes/github.com/cli/cli/heads/trunk")
+
+	bar := ManifestProject{Remote: barRemote.Name, URL: "https://github.com/orirawlings/bar.git", Branch: "main", Commit: commit}
+	headless := ManifestProject{Remote: headlessRemote.Name, URL: "ssh://github.com/orirawlings/headless.git"}
+	cli := ManifestProject{Remote: githubCLICLIRemote.Name, URL: "https://github.com/cli/cli.git", Branch: "trunk", Commit: commit}
+	for _, tc := range []struct {
+		name     string
+		owners   []Owner
+		expected []ManifestProject
+	}{
+		{
+			name:     "all",
+			expected: []ManifestProject{cli, bar, headless},
+		},
+		{
+			name:     "owner",
+			owners:   []Owner{github_com_orirawlings},
+			expected: []ManifestProject{bar, headless},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			projects, err := b.Manifest(ctx, tc.owners...)
+			testutil.Check(t, err)
+			if !reflect.DeepEqual(projects, tc.expected) {
+				t.Errorf("unexpected projects: wanted %+v, was %+v", tc.expected, projects)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package biome
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+var (
+	// errRemoteNotFound indicates that a remote is not configured in the
+	// biome.
+	errRemoteNotFound = errors.New("remote not found in biome")
+
+	// errHeadNotFetched indicates that the HEAD reference of a remote does
+	// not resolve to a commit, usually because the remote has not been
+	// fetched.
+	errHeadNotFetched = errors.New("remote HEAD has not been fetched")
+)
+
+// MergeBase is the best common ancestor of the default branches of two
+// remotes.
+type MergeBase struct {
+
+	// Remote is the name of the remote whose default branch is compared.
+	Remote string
+
+	// Commit is the object ID of the best common ancestor.
+	Commit string
+}
+
+// MergeBase returns the best common ancestor of the default branches of the
+// two named remotes, or an empty string if they share no history. Since all
+// remotes share the biome's object database, no objects need to be fetched.
+func (b *biome) MergeBase(ctx context.Context, remote, other string) (string, error) {
+	heads, err := b.resolveHeads(ctx)
+	if err != nil {
+		return "", err
+	}
+	var commits []string
+	for _, name := range []string{remote, other} {
+		commit, err := headCommit(heads, name)
+		if err != nil {
+			return "", err
+		}
+		commits = append(commits, commit)
+	}
+	return b.mergeBase(ctx, commits[0], commits[1])
+}
+
+// ForkMergeBases compares the default branch of the named remote with the
+// default branch of every other fetched remote, returning the best common
+// ancestor for each remote that shares history with it, such as its forks,
+// sorted by remote name.
+func (b *biome) ForkMergeBases(ctx context.Context, remote string) ([]MergeBase, error) {
+	heads, err := b.resolveHeads(ctx)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := headCommit(heads, remote)
+	if err != nil {
+		return nil, err
+	}
+	var result []MergeBase
+	for _, name := range slices.Sorted(maps.Keys(heads)) {
+		other := heads[name]
+		if name == remote || other == "" {
+			continue
+		}
+		base, err := b.mergeBase(ctx, commit, other)
+		if err != nil {
+			return nil, err
+		}
+		if base != "" {
+			result = append(result, MergeBase{Remote: name, Commit: base})
+		}
+	}
+	return result, nil
+}
+
+// resolveHeads maps the name of each fetchable remote to the object ID that
+// its HEAD reference resolves to, or an empty string if it does not resolve.
+func (b *biome) resolveHeads(ctx context.Context) (map[string]string, error) {
+	remotes, err := b.Remotes(ctx, FetchableRemoteCategories...)
+	if err != nil {
+		return nil, err
+	}
+	var namespaces []string
+	for _, r := range remotes {
+		namespaces = append(namespaces, r.RefNamespace())
+	}
+	refs, err := b.listRefs(ctx, namespaces)
+	if err != nil {
+		return nil, err
+	}
+	refsByName := make(map[string]storedRef)
+	for _, ref := range refs {
+		refsByName[ref.Name] = ref
+	}
+	heads := make(map[string]string)
+	for _, r := range remotes {
+		ref := refsByName[r.Head()]
+		if ref.Symref != "" {
+			ref = refsByName[ref.Symref]
+		}
+		heads[r.Name] = ref.ObjectName
+	}
+	return heads, nil
+}
+
+func headCommit(heads map[string]string, remote string) (string, error) {
+	commit, ok := heads[remote]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", errRemoteNotFound, remote)
+	}
+	if commit == "" {
+		return "", fmt.Errorf("%w: %s", errHeadNotFetched, remote)
+	}
+	return commit, nil
+}
+
+// mergeBase returns the best common ancestor of two commits, or an empty
+// string if they share no history.
+func (b *biome) mergeBase(ctx context.Context, commit, other string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "-C", b.path, "merge-base", commit, other)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		// git exits with status 1, without output, if there is no common
+		// ancestor
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && stderr.Len() == 0 {
+			return "", nil
+		}
+		return "", fmt.Errorf("could not %q: %w: %s", cmd, err, stderr.String())
+	}
+	return strings.TrimSpace(string(out)), nil
+}
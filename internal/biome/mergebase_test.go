@@ -0,0 +1,90 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_MergeBase(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		for _, r := range []Remote{barRemote, githubCLICLIRemote, githubGitGitRemote, headlessRemote} {
+			cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, r.Name)
+		}
+		return true, nil
+	}))
+
+	// bar is forked from cli/cli, while git/git has unrelated history
+	base := createCommitFor(t, ctx, path, []string{
+		"refs/remotes/github.com/cli/cli/heads/trunk",
+	})
+	fork := commitTree(t, path, "fork", base)
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/remotes/github.com/orirawlings/bar/heads/main", fork)
+	unrelated := commitTree(t, path, "unrelated")
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/remotes/github.com/git/git/heads/master", unrelated)
+	for ref, target := range map[string]string{
+		githubCLICLIRemote.Head(): "refs/remotes/github.com/cli/cli/heads/trunk",
+		barRemote.Head():          "refs/remotes/github.com/orirawlings/bar/heads/main",
+		githubGitGitRemote.Head(): "refs/remotes/github.com/git/git/heads/master",
+	} {
+		testutil.Execute(t, "git", "-C", path, "symbolic-ref", ref, target)
+	}
+
+	commit, err := b.MergeBase(ctx, barRemote.Name, githubCLICLIRemote.Name)
+	testutil.Check(t, err)
+	if commit != base {
+		t.Errorf("unexpected merge base: wanted %s, was %s", base, commit)
+	}
+
+	commit, err = b.MergeBase(ctx, barRemote.Name, githubGitGitRemote.Name)
+	testutil.Check(t, err)
+	if commit != "" {
+		t.Errorf("expected no merge base for unrelated histories, was %s", commit)
+	}
+
+	if _, err := b.MergeBase(ctx, barRemote.Name, headlessRemote.Name); !errors.Is(err, errHeadNotFetched) {
+		t.Errorf("expected error for unfetched remote, was %v", err)
+	}
+	if _, err := b.MergeBase(ctx, barRemote.Name, "github.com/orirawlings/missing"); !errors.Is(err, errRemoteNotFound) {
+		t.Errorf("expected error for missing remote, was %v", err)
+	}
+
+	bases, err := b.ForkMergeBases(ctx, githubCLICLIRemote.Name)
+	testutil.Check(t, err)
+	expected := []MergeBase{
+		{Remote: barRemote.Name, Commit: base},
+	}
+	if !slices.Equal(bases, expected) {
+		t.Errorf("unexpected fork merge bases: wanted %v, was %v", expected, bases)
+	}
+}
+
+// commitTree creates a commit of the empty tree with the given message and
+// parents, returning its object ID.
+func commitTree(t *testing.T, path, message string, parents ...string) string {
+	t.Helper()
+	for _, name := range []string{"GIT_AUTHOR", "GIT_COMMITTER"} {
+		t.Setenv(name+"_NAME", "A")
+		t.Setenv(name+"_EMAIL", "a@example.com")
+	}
+	args := []string{"git", "-C", path, "commit-tree", "-m", message}
+	for _, parent := range parents {
+		args = append(args, "-p", parent)
+	}
+	// the empty tree, named in the repository's object format
+	emptyTree := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "hash-object", "-t", "tree", "-w", os.DevNull))
+	args = append(args, emptyTree)
+	return strings.TrimSpace(testutil.Execute(t, args...))
+}
@@ -0,0 +1,79 @@
+package biome
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+)
+
+var (
+	// errShallowRepository indicates that an existing repository cannot
+	// become a biome because its history is shallow.
+	errShallowRepository = errors.New("shallow repositories cannot be biomes")
+
+	// errFormatOfExisting indicates that the object format or ref format of
+	// an existing repository cannot be chosen when it becomes a biome.
+	errFormatOfExisting = errors.New("the object format and ref format of an existing repository cannot be changed")
+)
+
+// remoteGroupPattern matches the names of the git remote groups of the
+// biome's owners and viewers, see [Owner.RemoteGroup].
+var remoteGroupPattern = regexp.MustCompile(`^g-[0-9a-f]{40}$`)
+
+// nonBare reports whether the biome's path is an existing non-bare
+// repository, either its working tree or its git directory, ex. a `.git`
+// directory or a separate git directory.
+func (b *biome) nonBare(ctx context.Context) bool {
+	for _, dir := range []string{b.path, filepath.Join(b.path, ".git")} {
+		if !isGitDir(ctx, dir) {
+			continue
+		}
+		out, err := exec.CommandContext(ctx, "git", "--git-dir="+dir, "config", "get", "--type=bool", "core.bare").Output()
+		return err == nil && strings.TrimSpace(string(out)) == "false"
+	}
+	return false
+}
+
+// checkNotShallow ensures that an existing repository that becomes a biome
+// does not have a shallow history, since fetching the biome's remotes into it
+// would give incomplete histories.
+func (b *biome) checkNotShallow(ctx context.Context) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "-C", b.path, "rev-parse", "--is-shallow-repository")
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("could not %q: %w: %s", cmd, err, stderr.String())
+	}
+	if strings.TrimSpace(string(out)) == "true" {
+		return fmt.Errorf("%w: %s", errShallowRepository, b.path)
+	}
+	return nil
+}
+
+// foreignRemotes returns the names of the configured git remotes that are not
+// members of any of the biome's remote groups, ex. the remotes of a non-bare
+// repository that existed before it became a biome. Updates of the biome's
+// remotes keep foreign remotes and their references.
+func foreignRemotes(cfg *config.Config) map[string]struct{} {
+	managed := make(map[string]struct{})
+	for _, opt := range cfg.Section("remotes").Options {
+		if remoteGroupPattern.MatchString(opt.Key) {
+			managed[opt.Value] = struct{}{}
+		}
+	}
+	foreign := make(map[string]struct{})
+	for _, ss := range cfg.Section("remote").Subsections {
+		if _, ok := managed[ss.Name]; !ok {
+			foreign[ss.Name] = struct{}{}
+		}
+	}
+	return foreign
+}
@@ -0,0 +1,101 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+// tempWorkspace creates a non-bare repository with a commit on its main
+// branch, and an origin remote.
+func tempWorkspace(t *testing.T) string {
+	t.Helper()
+	path := t.TempDir()
+	testutil.Execute(t, "git", "init", "--quiet", "--initial-branch=main", path)
+	commit := commitTree(t, path, "initial commit")
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/heads/main", commit)
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/remotes/origin/main", commit)
+	testutil.Execute(t, "git", "-C", path, "remote", "add", "origin", "https://example.com/workspace.git")
+	return path
+}
+
+func TestInit_nonBare(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("working tree", func(t *testing.T) {
+		path := tempWorkspace(t)
+		b := initBiome(t, ctx, path, true)
+		assertGitConfig(t, path, "core.bare", "false")
+
+		addOwners(t, ctx, b, github_com_cli)
+		testutil.Check(t, b.UpdateRemotes(ctx))
+		if remotes := strings.Fields(testutil.Execute(t, "git", "-C", path, "remote")); !slices.Equal(remotes, []string{githubCLICLIRemote.Name, "origin"}) {
+			t.Errorf("expected the workspace's remotes to be kept, was %q", remotes)
+		}
+		testutil.Execute(t, "git", "-C", path, "rev-parse", "--verify", "refs/remotes/origin/main")
+		testutil.Execute(t, "git", "-C", path, "rev-parse", "--verify", "refs/heads/main")
+
+		// the biome is discovered from within the working tree
+		sub := filepath.Join(path, "src")
+		testutil.Execute(t, "mkdir", sub)
+		found, err := Discover(ctx, sub)
+		testutil.Check(t, err)
+		if found.Path() != path {
+			t.Errorf("expected biome path %q, got %q", path, found.Path())
+		}
+	})
+
+	t.Run("git directory", func(t *testing.T) {
+		path := tempWorkspace(t)
+		initBiome(t, ctx, filepath.Join(path, ".git"), true)
+		assertGitConfig(t, path, "core.bare", "false")
+	})
+
+	t.Run("formats of existing repository", func(t *testing.T) {
+		path := tempWorkspace(t)
+		_, err := Init(ctx, path, UseObjectFormat(ObjectFormatSHA256))
+		if !errors.Is(err, errFormatOfExisting) {
+			t.Errorf("expected %v, was %v", errFormatOfExisting, err)
+		}
+	})
+
+	t.Run("shallow repository", func(t *testing.T) {
+		origin := tempWorkspace(t)
+		path := filepath.Join(t.TempDir(), "shallow")
+		testutil.Execute(t, "git", "clone", "--quiet", "--depth=1", "file://"+origin, path)
+		_, err := Init(ctx, path)
+		if !errors.Is(err, errShallowRepository) {
+			t.Errorf("expected %v, was %v", errShallowRepository, err)
+		}
+	})
+}
+
+func TestForeignRemotes(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		for _, name := range []string{"origin", "upstream", barRemote.Name} {
+			cfg.Section("remote").Subsection(name).SetOption("url", "https://example.com/"+name)
+		}
+		cfg.Section("remotes").AddOption(github_com_orirawlings.RemoteGroup(), barRemote.Name)
+		cfg.Section("remotes").AddOption("mine", "upstream")
+		return true, nil
+	}))
+	testutil.Check(t, b.readConfig(ctx, func(ctx context.Context, cfg *config.Config) error {
+		if foreign := slices.Sorted(maps.Keys(foreignRemotes(cfg))); !slices.Equal(foreign, []string{"origin", "upstream"}) {
+			t.Errorf("unexpected foreign remotes: %q", foreign)
+		}
+		return nil
+	}))
+}
@@ -0,0 +1,54 @@
+package biome
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+)
+
+const (
+	// noteOpt is a git config option key, within a remote's subsection,
+	// which holds a freeform note about the remote, ex. the team that owns
+	// it.
+	noteOpt = "note"
+)
+
+// Annotate attaches a freeform note to the named remote, replacing any
+// previous note, so that knowledge about the remote, ex. the team that owns
+// it, travels with the biome. An empty note removes the remote's note. The
+// note is kept when remotes are updated.
+func (b *biome) Annotate(ctx context.Context, remote, note string) error {
+	remotes, err := b.Remotes(ctx, AllRemoteCategories...)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(remotes, func(r Remote) bool {
+		return r.Name == remote
+	}) {
+		return fmt.Errorf("%w: %s", errRemoteNotFound, remote)
+	}
+	key := RemoteSettingKey(remote, noteOpt)
+	if note = strings.TrimSpace(note); note == "" {
+		return b.UnsetSetting(ctx, key)
+	}
+	return b.SetSetting(ctx, key, note)
+}
+
+// RemoteNotes returns the notes attached to remotes with [biome.Annotate],
+// keyed by remote name.
+func (b *biome) RemoteNotes(ctx context.Context) (map[string]string, error) {
+	notes := make(map[string]string)
+	err := b.readConfig(ctx, func(ctx context.Context, cfg *config.Config) error {
+		for _, ss := range cfg.Section(section).Subsections {
+			remote, ok := strings.CutPrefix(ss.Name, remoteSubsectionPrefix)
+			if note := ss.Options.Get(noteOpt); ok && note != "" {
+				notes[remote] = note
+			}
+		}
+		return nil
+	})
+	return notes, err
+}
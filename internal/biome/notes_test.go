@@ -0,0 +1,85 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_Annotate(t *testing.T) {
+	ctx := context.Background()
+	b := &biome{
+		path:          testutil.TempRepo(t),
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		for _, r := range []Remote{barRemote, headlessRemote} {
+			cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, r.Name)
+		}
+		return true, nil
+	}))
+
+	testutil.Check(t, b.Annotate(ctx, barRemote.Name, "owned by platform team"))
+	testutil.Check(t, b.Annotate(ctx, headlessRemote.Name, "  deprecated  "))
+	if err := b.Annotate(ctx, "github.com/orirawlings/missing", "note"); !errors.Is(err, errRemoteNotFound) {
+		t.Errorf("expected %v annotating a missing remote, was %v", errRemoteNotFound, err)
+	}
+	notes, err := b.RemoteNotes(ctx)
+	testutil.Check(t, err)
+	expected := map[string]string{
+		barRemote.Name:      "owned by platform team",
+		headlessRemote.Name: "deprecated",
+	}
+	if !reflect.DeepEqual(notes, expected) {
+		t.Errorf("unexpected notes: wanted %v, was %v", expected, notes)
+	}
+
+	// an empty note removes the remote's note
+	testutil.Check(t, b.Annotate(ctx, headlessRemote.Name, ""))
+	notes, err = b.RemoteNotes(ctx)
+	testutil.Check(t, err)
+	delete(expected, headlessRemote.Name)
+	if !reflect.DeepEqual(notes, expected) {
+		t.Errorf("unexpected notes after removal: wanted %v, was %v", expected, notes)
+	}
+}
+
+func TestBiome_RemoteNotes_rename(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+	b := initBiome(t, ctx, path, true)
+
+	addOwners(t, ctx, b, github_com_orirawlings)
+	testutil.Check(t, b.UpdateRemotes(ctx))
+	testutil.Check(t, b.Annotate(ctx, barRemote.Name, "owned by platform team"))
+	expectNotes := func(expected map[string]string) {
+		t.Helper()
+		notes, err := b.RemoteNotes(ctx)
+		testutil.Check(t, err)
+		if !reflect.DeepEqual(notes, expected) {
+			t.Errorf("unexpected notes: wanted %v, was %v", expected, notes)
+		}
+	}
+
+	// notes follow remotes whose owner is renamed
+	testutil.Check(t, b.RenameOwner(ctx, github_com_orirawlings, github_com_kubernetes))
+	expectNotes(map[string]string{
+		"github.com/kubernetes/bar": "owned by platform team",
+	})
+
+	// notes follow remotes renamed only in letter case
+	testutil.Check(t, b.RenameOwner(ctx, github_com_kubernetes, github_com_orirawlings))
+	renamedBar := github_com_orirawlings_bar
+	renamedBar.URL = "https://github.com/orirawlings/Bar"
+	updateStubbedGitHubRepositories(t, github_com_orirawlings, []repository{
+		renamedBar,
+	})
+	testutil.Check(t, b.UpdateRemotes(ctx))
+	expectNotes(map[string]string{
+		"github.com/orirawlings/Bar": "owned by platform team",
+	})
+}
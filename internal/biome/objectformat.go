@@ -0,0 +1,56 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ObjectFormat is the hash algorithm that names the biome's objects. Object
+// names are 40 hexadecimal characters for [ObjectFormatSHA1], and 64 for
+// [ObjectFormatSHA256].
+type ObjectFormat string
+
+const (
+	// ObjectFormatSHA1 names objects with SHA-1, git's default.
+	ObjectFormatSHA1 ObjectFormat = "sha1"
+
+	// ObjectFormatSHA256 names objects with SHA-256. git does not convert
+	// objects between formats when fetching, so remotes must also use
+	// SHA-256 to be fetched into the biome.
+	ObjectFormatSHA256 ObjectFormat = "sha256"
+)
+
+// objectFormats lists the valid values of [ObjectFormat].
+var objectFormats = []string{string(ObjectFormatSHA1), string(ObjectFormatSHA256)}
+
+// errObjectFormatUnsupported indicates that the installed git cannot create
+// repositories with an object format.
+var errObjectFormatUnsupported = errors.New("object format is not supported by the installed git")
+
+// ParseObjectFormat parses the name of an [ObjectFormat].
+func ParseObjectFormat(value string) (ObjectFormat, error) {
+	if err := validateOneOf(objectFormats...)(value); err != nil {
+		return "", fmt.Errorf("invalid object format: %q: %w", value, err)
+	}
+	return ObjectFormat(value), nil
+}
+
+// checkSupported ensures that the installed git can create repositories with
+// the object format.
+func (f ObjectFormat) checkSupported(ctx context.Context) error {
+	if err := tryInit(ctx, "--object-format="+string(f)); err != nil {
+		return fmt.Errorf("%w: %s: %w", errObjectFormatUnsupported, f, err)
+	}
+	return nil
+}
+
+// UseObjectFormat initializes a new biome with the given [ObjectFormat]. If
+// this option is not given, git's default object format is used. The option
+// has no effect when loading a biome, and initializing a biome that already
+// exists with another object format fails.
+func UseObjectFormat(format ObjectFormat) BiomeOption {
+	return func(b *biome) {
+		b.objectFormat = format
+	}
+}
@@ -0,0 +1,85 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestParseObjectFormat(t *testing.T) {
+	for _, format := range []ObjectFormat{ObjectFormatSHA1, ObjectFormatSHA256} {
+		parsed, err := ParseObjectFormat(string(format))
+		testutil.Check(t, err)
+		if parsed != format {
+			t.Errorf("expected %q, was %q", format, parsed)
+		}
+	}
+	_, err := ParseObjectFormat("md5")
+	testutil.ExpectError(t, err)
+
+	if err := ObjectFormat("md5").checkSupported(context.Background()); !errors.Is(err, errObjectFormatUnsupported) {
+		t.Errorf("expected %v, was %v", errObjectFormatUnsupported, err)
+	}
+}
+
+func TestInit_objectFormat(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+	initBiome(t, ctx, path, true, UseObjectFormat(ObjectFormatSHA256))
+	if format := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "rev-parse", "--show-object-format")); format != string(ObjectFormatSHA256) {
+		t.Errorf("expected %q object format, was %q", ObjectFormatSHA256, format)
+	}
+
+	// an existing biome keeps its object format
+	initBiome(t, ctx, path, false, UseObjectFormat(ObjectFormatSHA1))
+}
+
+// TestBiome_snapshots_sha256 exercises the plumbing that reads and writes
+// object names with the 64 character names of SHA-256 repositories.
+func TestBiome_snapshots_sha256(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+	testutil.Execute(t, "git", "init", "--quiet", "--bare", "--object-format=sha256", path)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		cfg.Section(section).AddOption(ownersOpt, github_com_orirawlings.String())
+		cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, barRemote.Name)
+		return true, nil
+	}))
+	bar := barRemote.RefNamespace()
+	base := commitTree(t, path, "base")
+	if len(base) != 64 {
+		t.Fatalf("expected a SHA-256 object name, was %q", base)
+	}
+	testutil.Execute(t, "git", "-C", path, "update-ref", bar+"heads/main", base)
+	testutil.Execute(t, "git", "-C", path, "symbolic-ref", barRemote.Head(), bar+"heads/main")
+	_, err := b.CreateSnapshot(ctx, "a")
+	testutil.Check(t, err)
+
+	next := commitTree(t, path, "next", base)
+	testutil.Execute(t, "git", "-C", path, "update-ref", bar+"heads/main", next)
+	_, err = b.CreateSnapshot(ctx, "b")
+	testutil.Check(t, err)
+
+	diffs, err := b.DiffSnapshots(ctx, "a", "b")
+	testutil.Check(t, err)
+	if len(diffs) != 1 || diffs[0].RefsUpdated != 1 || diffs[0].OldHead != base || diffs[0].NewHead != next || diffs[0].Ahead != 1 {
+		t.Errorf("unexpected snapshot diff: %+v", diffs)
+	}
+
+	changed, err := b.RestoreSnapshot(ctx, "a")
+	testutil.Check(t, err)
+	if changed != 1 {
+		t.Errorf("expected 1 reference to change, was %d", changed)
+	}
+	if ref := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "rev-parse", bar+"heads/main")); ref != base {
+		t.Errorf("expected %s to be restored, was %s", base, ref)
+	}
+}
@@ -0,0 +1,214 @@
+package biome
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+)
+
+// Orphan is a remote of the biome that is configured as a git remote, or
+// has references, but is not recorded in any remote category, ex. because it
+// was left over by a crash or by manual edits of the git config.
+type Orphan struct {
+
+	// Remote is the name of the orphaned remote.
+	Remote string
+
+	// Configured is true if there is a git remote configuration for the
+	// remote.
+	Configured bool
+
+	// Namespaces lists the reference namespaces that hold references of the
+	// remote, sorted.
+	Namespaces []string
+
+	// References is the number of references in the remote's namespaces.
+	References int
+}
+
+// refTemplatePattern returns a pattern matching the references in the
+// namespace of any remote for a reference namespace template. The first
+// submatch is the namespace, and the named submatches are the remote's name,
+// or its host, owner, and repo.
+func refTemplatePattern(template string) *regexp.Regexp {
+	namespace := regexp.QuoteMeta(strings.TrimSuffix(template, "*"))
+	namespace = strings.NewReplacer(
+		"<name>", `(?P<name>[^/]+/[^/]+/[^/]+)`,
+		"<host>", `(?P<host>[^/]+)`,
+		"<owner>", `(?P<owner>[^/]+)`,
+		"<repo>", `(?P<repo>[^/]+)`,
+	).Replace(namespace)
+	return regexp.MustCompile(`^(` + namespace + `).+$`)
+}
+
+// matchRefTemplate returns the name of the remote, and the remote's
+// namespace, that a reference belongs to according to a pattern from
+// [refTemplatePattern]. If the reference is not in any remote's namespace,
+// false is returned.
+func matchRefTemplate(pattern *regexp.Regexp, ref string) (string, string, bool) {
+	m := pattern.FindStringSubmatch(ref)
+	if m == nil {
+		return "", "", false
+	}
+	group := func(name string) string {
+		return m[pattern.SubexpIndex(name)]
+	}
+	if pattern.SubexpIndex("name") >= 0 {
+		return group("name"), m[1], true
+	}
+	return strings.Join([]string{group("host"), group("owner"), group("repo")}, "/"), m[1], true
+}
+
+// isRemoteName reports whether a git remote name has the form of the names
+// of the biome's remotes, `<host>/<owner>/<repo>`.
+func isRemoteName(name string) bool {
+	return validateRemoteName(name) == nil
+}
+
+// Orphans finds the biome's orphaned remotes, sorted by name: git remotes
+// named like the biome's remotes, `<host>/<owner>/<repo>`, and references in
+// the namespace of such a remote, either per the biome.refspecTemplate
+// setting or in the attic, that are not recorded in any remote category.
+// Snapshots, and the references of other git remotes, ex. `origin` in a
+// non-bare biome, are never orphans.
+func (b *biome) Orphans(ctx context.Context) ([]Orphan, error) {
+	orphans := make(map[string]*Orphan)
+	orphan := func(name string) *Orphan {
+		if _, ok := orphans[name]; !ok {
+			orphans[name] = &Orphan{Remote: name}
+		}
+		return orphans[name]
+	}
+	categorized := make(map[string]bool)
+	others := make(map[string]bool)
+	var template string
+	configured := make(map[string]string)
+	if err := b.readConfig(ctx, func(ctx context.Context, cfg *config.Config) error {
+		template = refTemplate(cfg)
+		metadata := cfg.Section(section).Subsection(remotesSubsection)
+		for _, category := range AllRemoteCategories {
+			for _, name := range metadata.OptionAll(string(category)) {
+				categorized[name] = true
+			}
+		}
+		for _, ss := range cfg.Section("remote").Subsections {
+			switch {
+			case !isRemoteName(ss.Name):
+				others[ss.Name] = true
+			case !categorized[ss.Name]:
+				orphan(ss.Name).Configured = true
+				if namespace, ok := refNamespaceOf(ss.Options.Get("fetch")); ok {
+					configured[namespace] = ss.Name
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if template == "" {
+		template = defaultRefTemplate
+	}
+	prefixes := []string{
+		// templates always have a placeholder, see validateRefTemplate
+		template[:strings.Index(template, "<")],
+		atticRefPrefix,
+	}
+	patterns := []*regexp.Regexp{
+		refTemplatePattern(template),
+		refTemplatePattern(atticRefTemplate),
+	}
+	for namespace := range configured {
+		prefixes = append(prefixes, namespace)
+	}
+	refs, err := b.listRefs(ctx, prefixes)
+	if err != nil {
+		return nil, err
+	}
+	counted := make(map[string]bool)
+	for _, ref := range refs {
+		if counted[ref.Name] || strings.HasPrefix(ref.Name, snapshotRefPrefix) {
+			continue
+		}
+		name, namespace, ok := "", "", false
+		for ns, remote := range configured {
+			if strings.HasPrefix(ref.Name, ns) {
+				name, namespace, ok = remote, ns, true
+			}
+		}
+		for _, pattern := range patterns {
+			if !ok {
+				name, namespace, ok = matchRefTemplate(pattern, ref.Name)
+			}
+		}
+		if !ok || categorized[name] || others[strings.SplitN(name, "/", 2)[0]] {
+			continue
+		}
+		counted[ref.Name] = true
+		o := orphan(name)
+		o.References++
+		if !slices.Contains(o.Namespaces, namespace) {
+			o.Namespaces = append(o.Namespaces, namespace)
+		}
+	}
+
+	var result []Orphan
+	for _, o := range orphans {
+		slices.Sort(o.Namespaces)
+		result = append(result, *o)
+	}
+	slices.SortFunc(result, func(a, b Orphan) int {
+		return strings.Compare(a.Remote, b.Remote)
+	})
+	return result, nil
+}
+
+// RemoveOrphans removes the git remote configurations, remote group
+// memberships, metadata, and references of the given orphaned remotes, see
+// [biome.Orphans]. Per-remote settings are kept, like they are when remotes
+// are updated.
+func (b *biome) RemoveOrphans(ctx context.Context, orphans []Orphan) error {
+	if len(orphans) == 0 {
+		return nil
+	}
+	names := make(map[string]bool)
+	var namespaces []string
+	for _, o := range orphans {
+		names[o.Remote] = true
+		namespaces = append(namespaces, o.Namespaces...)
+	}
+	if err := b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		for name := range names {
+			cfg.Section("remote").RemoveSubsection(name)
+		}
+		groups := cfg.Section("remotes")
+		kept := groups.Options[:0]
+		for _, opt := range groups.Options {
+			if !remoteGroupPattern.MatchString(opt.Key) || !names[opt.Value] {
+				kept = append(kept, opt)
+			}
+		}
+		groups.Options = kept
+		metadata := cfg.Section(section).Subsection(remotesSubsection)
+		kept = metadata.Options[:0]
+		for _, opt := range metadata.Options {
+			if name, _, _ := strings.Cut(opt.Value, " "); !names[name] {
+				kept = append(kept, opt)
+			}
+		}
+		metadata.Options = kept
+		return true, nil
+	}); err != nil {
+		return fmt.Errorf("could not remove orphaned remotes: %w", err)
+	}
+	if err := b.cleanUpRefs(ctx, namespaces); err != nil {
+		return fmt.Errorf("could not remove references of orphaned remotes: %w", err)
+	}
+	_, err := b.remotesIndex(ctx)
+	return err
+}
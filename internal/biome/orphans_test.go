@@ -0,0 +1,120 @@
+package biome
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_Orphans(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	stale := Remote{Name: "github.com/orirawlings/stale"}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		for _, r := range []Remote{barRemote, stale} {
+			refspec, err := r.FetchRefspec()
+			if err != nil {
+				return false, err
+			}
+			cfg.Section("remote").Subsection(r.Name).SetOption("url", r.FetchURL())
+			cfg.Section("remote").Subsection(r.Name).SetOption("fetch", refspec)
+			cfg.Section("remotes").AddOption(github_com_orirawlings.RemoteGroup(), r.Name)
+		}
+		cfg.Section("remote").Subsection("origin").SetOption("url", "https://example.com/workspace.git")
+		cfg.Section("remote").Subsection("origin").SetOption("fetch", "+refs/heads/*:refs/remotes/origin/*")
+		cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, barRemote.Name)
+		cfg.Section(section).Subsection(remotesSubsection).AddOption(upstreamOpt, stale.Name+" "+githubCLICLIRemote.Name)
+		return true, nil
+	}))
+	commitID := createCommitFor(t, ctx, path, []string{
+		"refs/attic/github.com/cli/old/heads/main",
+		"refs/remotes/github.com/orirawlings/bar/heads/main",
+		"refs/remotes/github.com/orirawlings/gone/heads/main",
+		"refs/remotes/github.com/orirawlings/gone/tags/v1",
+		"refs/remotes/github.com/orirawlings/stale/heads/main",
+		"refs/remotes/origin/feature/a/b",
+		"refs/biome/snapshots/before",
+	})
+
+	orphans, err := b.Orphans(ctx)
+	testutil.Check(t, err)
+	expected := []Orphan{
+		{
+			Remote:     "github.com/cli/old",
+			Namespaces: []string{"refs/attic/github.com/cli/old/"},
+			References: 1,
+		},
+		{
+			Remote:     "github.com/orirawlings/gone",
+			Namespaces: []string{"refs/remotes/github.com/orirawlings/gone/"},
+			References: 2,
+		},
+		{
+			Remote:     stale.Name,
+			Configured: true,
+			Namespaces: []string{"refs/remotes/github.com/orirawlings/stale/"},
+			References: 1,
+		},
+	}
+	if !reflect.DeepEqual(orphans, expected) {
+		t.Errorf("unexpected orphans: wanted %+v, was %+v", expected, orphans)
+	}
+
+	testutil.Check(t, b.RemoveOrphans(ctx, orphans))
+	expectRefs(t, ctx, path, []string{
+		fmt.Sprintf(`%s commit refs/biome/snapshots/before `, commitID),
+		fmt.Sprintf(`%s commit refs/remotes/github.com/orirawlings/bar/heads/main `, commitID),
+		fmt.Sprintf(`%s commit refs/remotes/origin/feature/a/b `, commitID),
+	})
+	assertConfigNotSet(t, path, "remote."+stale.Name+".url")
+	assertConfigNotSet(t, path, "biome.remotes.upstream")
+	assertGitConfig(t, path, "remote.origin.url", "https://example.com/workspace.git")
+	assertGitConfig(t, path, "remotes."+github_com_orirawlings.RemoteGroup(), barRemote.Name)
+
+	orphans, err = b.Orphans(ctx)
+	testutil.Check(t, err)
+	if len(orphans) > 0 {
+		t.Errorf("expected no orphans after removing them, was %+v", orphans)
+	}
+}
+
+func TestBiome_Orphans_refspecTemplate(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		setConfigValue(cfg, refspecTemplateKey, "refs/biome/<host>/<owner>/<repo>/*")
+		cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, barRemote.Name)
+		return true, nil
+	}))
+	createCommitFor(t, ctx, path, []string{
+		"refs/biome/github.com/orirawlings/bar/heads/main",
+		"refs/biome/github.com/orirawlings/gone/heads/main",
+		"refs/biome/snapshots/before",
+		"refs/remotes/github.com/orirawlings/other/heads/main",
+	})
+
+	orphans, err := b.Orphans(ctx)
+	testutil.Check(t, err)
+	expected := []Orphan{
+		{
+			Remote:     "github.com/orirawlings/gone",
+			Namespaces: []string{"refs/biome/github.com/orirawlings/gone/"},
+			References: 1,
+		},
+	}
+	if !reflect.DeepEqual(orphans, expected) {
+		t.Errorf("unexpected orphans: wanted %+v, was %+v", expected, orphans)
+	}
+}
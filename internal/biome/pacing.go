@@ -0,0 +1,142 @@
+package biome
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+)
+
+const (
+	// paceOpt is a per-host setting option for the minimum time between
+	// discovering consecutive owners or viewers on the host.
+	paceOpt = "pace"
+
+	// maxConcurrentOpt is a per-host setting option for the maximum number
+	// of the host's owners and viewers discovered, and of the host's remotes
+	// fetched, concurrently.
+	maxConcurrentOpt = "maxConcurrent"
+)
+
+// hostPacer spaces out the discovery of owners and viewers on each GitHub
+// host by the host's pace setting, so that discovering many owners on the
+// same host does not trip GitHub's secondary rate limits.
+type hostPacer struct {
+	cfg *config.Config
+
+	mu   sync.Mutex
+	last map[string]time.Time
+
+	// now and sleep are replaced in tests.
+	now   func() time.Time
+	sleep func(context.Context, time.Duration) error
+}
+
+// newHostPacer returns a pacer for the hosts of a loaded config.
+func newHostPacer(cfg *config.Config) *hostPacer {
+	return &hostPacer{
+		cfg:   cfg,
+		last:  make(map[string]time.Time),
+		now:   time.Now,
+		sleep: sleep,
+	}
+}
+
+// wait blocks until the host's pace has passed since the last discovery on
+// the host finished, or the context is done.
+func (p *hostPacer) wait(ctx context.Context, host string) error {
+	p.mu.Lock()
+	last, ok := p.last[host]
+	p.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	pace, _ := time.ParseDuration(hostSetting(p.cfg, host, paceOpt))
+	if d := last.Add(pace).Sub(p.now()); d > 0 {
+		return p.sleep(ctx, d)
+	}
+	return nil
+}
+
+// done records that a discovery on the host finished.
+func (p *hostPacer) done(host string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.last[host] = p.now()
+}
+
+// hostJob is a discovery of an owner or viewer on a GitHub host.
+type hostJob struct {
+	host string
+	run  func(context.Context) error
+}
+
+// discoveryConcurrency returns how many of the host's owners and viewers may
+// be discovered concurrently, from a loaded config. Unless the host's
+// maxConcurrent setting allows more, they are discovered one at a time.
+func discoveryConcurrency(cfg *config.Config, host string) int {
+	limit, _ := strconv.Atoi(hostSetting(cfg, host, maxConcurrentOpt))
+	return max(limit, 1)
+}
+
+// runByHost runs the jobs of each host concurrently with the jobs of other
+// hosts, but no more of a host's jobs at a time than
+// [discoveryConcurrency] allows, each paced by the host's pace setting. The
+// jobs of a host are started in order. The first job to fail cancels the
+// others, and its error is returned.
+func runByHost(ctx context.Context, cfg *config.Config, jobs []hostJob) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	pacer := newHostPacer(cfg)
+
+	queues := make(map[string]chan hostJob)
+	var hosts []string
+	for _, job := range jobs {
+		if _, ok := queues[job.host]; !ok {
+			queues[job.host] = make(chan hostJob, len(jobs))
+			hosts = append(hosts, job.host)
+		}
+		queues[job.host] <- job
+	}
+	var wg sync.WaitGroup
+	for _, host := range hosts {
+		close(queues[host])
+		for range discoveryConcurrency(cfg, host) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for job := range queues[host] {
+					if ctx.Err() != nil {
+						return
+					}
+					if err := pacer.wait(ctx, host); err != nil {
+						cancel(err)
+						return
+					}
+					err := job.run(ctx)
+					pacer.done(host)
+					if err != nil {
+						cancel(err)
+						return
+					}
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	return context.Cause(ctx)
+}
+
+// sleep blocks for the given duration, or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
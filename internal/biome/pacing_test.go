@@ -0,0 +1,113 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestHostPacer(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{}
+	setConfigValue(cfg, HostSettingKey("github.com", paceOpt), "2s")
+
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	var slept []time.Duration
+	p := newHostPacer(cfg)
+	p.now = func() time.Time { return now }
+	p.sleep = func(ctx context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		now = now.Add(d)
+		return nil
+	}
+
+	// the first discovery on each host is not paced
+	testutil.Check(t, p.wait(ctx, "github.com"))
+	p.done("github.com")
+	testutil.Check(t, p.wait(ctx, "my.github.biz"))
+	p.done("my.github.biz")
+
+	// the next discovery waits out the host's pace, less the time since the
+	// last discovery finished
+	now = now.Add(500 * time.Millisecond)
+	testutil.Check(t, p.wait(ctx, "github.com"))
+	p.done("github.com")
+
+	// hosts without a pace are not paced
+	testutil.Check(t, p.wait(ctx, "my.github.biz"))
+
+	// discoveries that took longer than the pace are not paced
+	now = now.Add(3 * time.Second)
+	testutil.Check(t, p.wait(ctx, "github.com"))
+
+	if len(slept) != 1 || slept[0] != 1500*time.Millisecond {
+		t.Errorf("unexpected waits: %v", slept)
+	}
+}
+
+func TestSleep(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := sleep(ctx, time.Hour); err != context.Canceled {
+		t.Errorf("expected %v, was %v", context.Canceled, err)
+	}
+	testutil.Check(t, sleep(context.Background(), time.Millisecond))
+}
+
+func TestRunByHost(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{}
+	setConfigValue(cfg, HostSettingKey("github.com", maxConcurrentOpt), "2")
+
+	var mu sync.Mutex
+	running := make(map[string]int)
+	peak := make(map[string]int)
+	var started []string
+	var jobs []hostJob
+	for i, host := range []string{"github.com", "my.github.biz", "github.com", "my.github.biz", "github.com", "my.github.biz"} {
+		jobs = append(jobs, hostJob{
+			host: host,
+			run: func(ctx context.Context) error {
+				mu.Lock()
+				running[host]++
+				peak[host] = max(peak[host], running[host])
+				if host == "my.github.biz" {
+					started = append(started, fmt.Sprintf("%s#%d", host, i))
+				}
+				mu.Unlock()
+				time.Sleep(20 * time.Millisecond)
+				mu.Lock()
+				running[host]--
+				mu.Unlock()
+				return nil
+			},
+		})
+	}
+	testutil.Check(t, runByHost(ctx, cfg, jobs))
+	if peak["github.com"] != 2 || peak["my.github.biz"] != 1 {
+		t.Errorf("unexpected peak concurrency per host: %v", peak)
+	}
+
+	// jobs of a host without a limit are run one at a time, in order
+	if expected := []string{"my.github.biz#1", "my.github.biz#3", "my.github.biz#5"}; !slices.Equal(started, expected) {
+		t.Errorf("unexpected order of jobs: wanted %v, was %v", expected, started)
+	}
+
+	// the first failure stops the remaining jobs
+	failure := errors.New("boom")
+	var ran int
+	err := runByHost(ctx, cfg, []hostJob{
+		{host: "my.github.biz", run: func(ctx context.Context) error { return failure }},
+		{host: "my.github.biz", run: func(ctx context.Context) error { ran++; return nil }},
+	})
+	if !errors.Is(err, failure) || ran != 0 {
+		t.Errorf("expected failure to stop remaining jobs, was %v after %d jobs", err, ran)
+	}
+}
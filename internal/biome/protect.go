@@ -0,0 +1,147 @@
+package biome
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+)
+
+const (
+	// protectedRefsKey is the git config key of the setting that lists the
+	// glob patterns of the references that fetches must not delete.
+	protectedRefsKey = "biome.fetch.protectedRefs"
+)
+
+// ProtectedRef is a reference of a remote, matching the
+// biome.fetch.protectedRefs setting, that a fetch deleted because it no
+// longer exists upstream, ex. because of an accidental push --delete.
+type ProtectedRef struct {
+
+	// Remote is the name of the remote.
+	Remote string
+
+	// Ref is the name of the reference, relative to the remote's reference
+	// namespace, ex. heads/main.
+	Ref string
+
+	// Commit is the object ID that the reference pointed to before it was
+	// deleted.
+	Commit string
+}
+
+// ProtectedRefs returns the object IDs that the references of the biome's
+// fetchable remotes matching the biome.fetch.protectedRefs setting point
+// to, keyed by reference name, so that [biome.RestoreProtectedRefs] can
+// restore those that a fetch deletes. It returns nothing if the setting is
+// empty.
+func (b *biome) ProtectedRefs(ctx context.Context) (map[string]string, error) {
+	setting, err := b.GetSetting(ctx, protectedRefsKey)
+	if err != nil {
+		return nil, err
+	}
+	patterns := splitList(setting.Value)
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	namespaces, err := b.fetchableNamespaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := b.listRefs(ctx, slices.Collect(maps.Keys(namespaces)))
+	if err != nil {
+		return nil, err
+	}
+	protected := make(map[string]string)
+	for _, ref := range refs {
+		if ref.Symref != "" {
+			continue
+		}
+		name := relativeRef(namespaces, ref.Name)
+		if slices.ContainsFunc(patterns, func(pattern string) bool {
+			ok, _ := path.Match(pattern, name)
+			return ok
+		}) {
+			protected[ref.Name] = ref.ObjectName
+		}
+	}
+	return protected, nil
+}
+
+// RestoreProtectedRefs compares the current references of the biome's
+// fetchable remotes with the protected references before a fetch, taken
+// with [biome.ProtectedRefs], and recreates those that the fetch deleted,
+// pointing to their previous object IDs. The restored references are
+// returned, sorted by remote and reference.
+func (b *biome) RestoreProtectedRefs(ctx context.Context, before map[string]string) ([]ProtectedRef, error) {
+	if len(before) == 0 {
+		return nil, nil
+	}
+	namespaces, err := b.fetchableNamespaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := b.listRefs(ctx, slices.Collect(maps.Keys(namespaces)))
+	if err != nil {
+		return nil, err
+	}
+	exists := make(map[string]bool)
+	for _, ref := range refs {
+		exists[ref.Name] = true
+	}
+
+	var restored []ProtectedRef
+	for ref, commit := range before {
+		if exists[ref] {
+			continue
+		}
+		// remotes removed from the biome since are not restored
+		remote, ok := remoteOfRef(namespaces, ref)
+		if !ok {
+			continue
+		}
+		if _, err := b.git(ctx, "update-ref", "-m", "biome: restore protected reference", ref, commit, ""); err != nil {
+			return restored, fmt.Errorf("could not restore protected reference %s: %w", ref, err)
+		}
+		restored = append(restored, ProtectedRef{
+			Remote: remote,
+			Ref:    relativeRef(namespaces, ref),
+			Commit: commit,
+		})
+	}
+	slices.SortFunc(restored, func(a, b ProtectedRef) int {
+		return cmp.Or(
+			cmp.Compare(a.Remote, b.Remote),
+			cmp.Compare(a.Ref, b.Ref),
+		)
+	})
+	return restored, nil
+}
+
+// fetchableNamespaces returns the names of the biome's fetchable remotes,
+// keyed by their reference namespaces.
+func (b *biome) fetchableNamespaces(ctx context.Context) (map[string]string, error) {
+	remotes, err := b.Remotes(ctx, FetchableRemoteCategories...)
+	if err != nil {
+		return nil, err
+	}
+	namespaces := make(map[string]string)
+	for _, r := range remotes {
+		namespaces[r.RefNamespace()] = r.Name
+	}
+	return namespaces, nil
+}
+
+// relativeRef returns the name of the reference relative to the reference
+// namespace of its remote, ex. heads/main.
+func relativeRef(namespaces map[string]string, ref string) string {
+	for namespace := range namespaces {
+		if name, ok := strings.CutPrefix(ref, namespace); ok {
+			return name
+		}
+	}
+	return ref
+}
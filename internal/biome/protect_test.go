@@ -0,0 +1,78 @@
+package biome
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_ProtectedRefs(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		for _, r := range []Remote{barRemote, headlessRemote} {
+			cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, r.Name)
+		}
+		return true, nil
+	}))
+	commit := commitTree(t, path, "base")
+	for _, ref := range []string{
+		"refs/remotes/github.com/orirawlings/bar/heads/main",
+		"refs/remotes/github.com/orirawlings/bar/heads/release-1",
+		"refs/remotes/github.com/orirawlings/bar/heads/feature",
+		"refs/remotes/github.com/orirawlings/headless/heads/main",
+	} {
+		testutil.Execute(t, "git", "-C", path, "update-ref", ref, commit)
+	}
+
+	// nothing is protected by default
+	protected, err := b.ProtectedRefs(ctx)
+	testutil.Check(t, err)
+	if len(protected) != 0 {
+		t.Errorf("expected no protected references by default, was %v", protected)
+	}
+
+	testutil.Check(t, b.SetSetting(ctx, protectedRefsKey, "heads/main, heads/release-*"))
+	protected, err = b.ProtectedRefs(ctx)
+	testutil.Check(t, err)
+	expected := map[string]string{
+		"refs/remotes/github.com/orirawlings/bar/heads/main":      commit,
+		"refs/remotes/github.com/orirawlings/bar/heads/release-1": commit,
+		"refs/remotes/github.com/orirawlings/headless/heads/main": commit,
+	}
+	if !reflect.DeepEqual(protected, expected) {
+		t.Errorf("unexpected protected references: wanted %v, was %v", expected, protected)
+	}
+
+	// a pruning fetch deletes protected and unprotected references alike
+	for ref := range expected {
+		testutil.Execute(t, "git", "-C", path, "update-ref", "-d", ref)
+	}
+	testutil.Execute(t, "git", "-C", path, "update-ref", "-d", "refs/remotes/github.com/orirawlings/bar/heads/feature")
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/remotes/github.com/orirawlings/headless/heads/main", commitTree(t, path, "recreated"))
+
+	restored, err := b.RestoreProtectedRefs(ctx, protected)
+	testutil.Check(t, err)
+	if want := []ProtectedRef{
+		{Remote: barRemote.Name, Ref: "heads/main", Commit: commit},
+		{Remote: barRemote.Name, Ref: "heads/release-1", Commit: commit},
+	}; !reflect.DeepEqual(restored, want) {
+		t.Errorf("unexpected restored references: wanted %+v, was %+v", want, restored)
+	}
+	refs := testutil.Execute(t, "git", "-C", path, "for-each-ref", "--format=%(refname) %(objectname)", "refs/remotes/github.com/orirawlings/bar/")
+	if want := "refs/remotes/github.com/orirawlings/bar/heads/main " + commit + "\nrefs/remotes/github.com/orirawlings/bar/heads/release-1 " + commit; strings.TrimSpace(refs) != want {
+		t.Errorf("unexpected references after restoring: wanted %q, was %q", want, refs)
+	}
+
+	if err := b.SetSetting(ctx, protectedRefsKey, "heads/["); err == nil {
+		t.Error("expected error setting an invalid pattern")
+	}
+}
@@ -0,0 +1,168 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Repo is a repository listed by a [Provider], which biome configures as a
+// remote.
+type Repo struct {
+
+	// URL of the repository's home page, ex. https://github.com/cli/cli. The
+	// remote is named after the URL without its scheme, ex.
+	// github.com/cli/cli, and fetched from the URL with a .git suffix, with
+	// the URL's scheme, one of [repoSchemes]. The URL must name a repository
+	// of the owner it is listed for, ex. ssh://git.example.com/platform/api
+	// for the owner git.example.com/platform, or it is not configured.
+	URL string
+
+	// DefaultBranch is the name of the repository's default branch, ex.
+	// main, or empty if the repository has none, ex. because it is empty.
+	DefaultBranch string
+
+	// Archived, Disabled, Locked, and Internal are recorded as the remote's
+	// metadata, see [Remote].
+	Archived bool
+	Disabled bool
+	Locked   bool
+	Internal bool
+
+	// Upstream is the URL of the repository that the repository was forked
+	// from, if it is a fork and the provider knows.
+	Upstream string
+
+	// CreatedAt is when the repository was created, if the provider knows.
+	// Repositories without a creation date are never excluded by the
+	// createdAfter owner setting.
+	CreatedAt time.Time
+}
+
+// Provider discovers the repositories of owners on a host, ex. through a git
+// forge's API, or an in-house inventory of repositories.
+type Provider interface {
+
+	// ListRepositories returns the repositories that the owner owns. If
+	// listing fails part way, the repositories listed so far are returned
+	// along with the error.
+	ListRepositories(ctx context.Context, owner Owner) ([]Repo, error)
+}
+
+// repoSchemes are the URL schemes that the repositories of a [Provider] may
+// be fetched with.
+var repoSchemes = []string{"https", "http", "ssh", "git"}
+
+// errProviderViewer indicates that repositories accessible to an
+// authenticated user were to be discovered on a host with a [Provider],
+// which only lists the repositories of owners.
+var errProviderViewer = errors.New("repositories accessible to an authenticated user cannot be discovered on a host with a provider")
+
+// UseProvider discovers the repositories of owners on the host with the
+// given [Provider], rather than with GitHub's API. Owners of the host are
+// not validated with GitHub when they are added either, so the provider is
+// expected to report unknown owners when listing their repositories.
+// Viewers cannot be added for the host, since providers only list the
+// repositories of owners.
+func UseProvider(host string, p Provider) BiomeOption {
+	return func(b *biome) {
+		if b.providers == nil {
+			b.providers = make(map[string]Provider)
+		}
+		b.providers[host] = p
+	}
+}
+
+// provider returns the [Provider] that discovers the repositories of owners on
+// the host: the provider registered with [UseProvider], or else GitHub's
+// GraphQL API, or its REST API if gh has no token for the host, see
+// [Anonymous]. GitHub's APIs spend the budget for each page of repositories
+// they request, and only request the given optional fields.
+func (b *biome) provider(host string, budget *apiBudget, fields repositoryFields) Provider {
+	if p, ok := b.providers[host]; ok {
+		return p
+	}
+	if Anonymous(host) {
+		return restProvider{budget: budget}
+	}
+	return graphQLProvider{budget: budget, fields: fields}
+}
+
+// validate checks that the repository's URL names a repository of the owner,
+// with one of [repoSchemes], so that a provider cannot configure remotes of
+// other owners, or fetch with an unexpected transport.
+func (r Repo) validate(owner Owner) error {
+	scheme, rest, ok := strings.Cut(r.URL, "://")
+	if !ok || !slices.Contains(repoSchemes, strings.ToLower(scheme)) {
+		return fmt.Errorf("repository URL must have one of the schemes %s: %q", strings.Join(repoSchemes, ", "), r.URL)
+	}
+	prefix := owner.String() + "/"
+	if len(rest) <= len(prefix) || !strings.EqualFold(rest[:len(prefix)], prefix) || strings.Contains(rest[len(prefix):], "/") {
+		return fmt.Errorf("repository URL does not name a repository of %s: %q", owner, r.URL)
+	}
+	return nil
+}
+
+// remote returns the configuration of the remote for the repository.
+func (r Repo) remote() remoteConfig {
+	remoteCfg := remoteConfig{
+		CreatedAt: r.CreatedAt,
+		Remote: Remote{
+			Name:     trimScheme(r.URL),
+			Archived: r.Archived,
+			Disabled: r.Disabled,
+			Locked:   r.Locked,
+			Internal: r.Internal,
+			scheme:   remoteScheme(r.URL),
+		},
+	}
+	if r.Upstream != "" {
+		remoteCfg.Remote.Upstream = trimScheme(r.Upstream)
+	}
+	if r.DefaultBranch != "" {
+		remoteCfg.Head = path.Join(remoteCfg.Remote.RefNamespace(), "heads", r.DefaultBranch)
+	}
+	return remoteCfg
+}
+
+// remoteScheme returns the scheme of the URL that a remote is fetched with,
+// or an empty string for https, the default, see [Remote.FetchURL].
+func remoteScheme(url string) string {
+	scheme, _, ok := strings.Cut(url, "://")
+	if scheme = strings.ToLower(scheme); !ok || scheme == "https" {
+		return ""
+	}
+	return scheme
+}
+
+// trimScheme returns the URL without its scheme, ex. github.com/cli/cli for
+// https://github.com/cli/cli.
+func trimScheme(url string) string {
+	if _, rest, ok := strings.Cut(url, "://"); ok {
+		return rest
+	}
+	return url
+}
+
+// remoteConfigs returns the configurations of the remotes for the owner's
+// repositories, ordered by remote name. Repositories that fail
+// [Repo.validate] are left out, and reported in the returned error.
+func remoteConfigs(owner Owner, repos []Repo) ([]remoteConfig, error) {
+	var remoteCfgs []remoteConfig
+	var errs []error
+	for _, repo := range repos {
+		if err := repo.validate(owner); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		remoteCfgs = append(remoteCfgs, repo.remote())
+	}
+	slices.SortFunc(remoteCfgs, func(a, b remoteConfig) int {
+		return strings.Compare(a.Remote.Name, b.Remote.Name)
+	})
+	return remoteCfgs, errors.Join(errs...)
+}
@@ -0,0 +1,110 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+// stubProvider lists the same repositories for every owner.
+type stubProvider struct {
+	repos []Repo
+	err   error
+}
+
+func (p stubProvider) ListRepositories(ctx context.Context, owner Owner) ([]Repo, error) {
+	return p.repos, p.err
+}
+
+func TestBiome_UseProvider(t *testing.T) {
+	ctx := context.Background()
+	stubGitHub(t)
+	created := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	owner := Owner{host: "git.example.com", name: "platform"}
+	b := &biome{}
+	UseProvider(owner.Host(), stubProvider{
+		repos: []Repo{
+			{
+				URL:           "https://git.example.com/platform/tools",
+				DefaultBranch: "main",
+				Upstream:      "https://git.example.com/infra/tools",
+				CreatedAt:     created,
+			},
+			{
+				URL:      "ssh://git.example.com/platform/api",
+				Archived: true,
+				Internal: true,
+			},
+		},
+	})(b)
+
+	// owners of the provider's host are not validated with GitHub
+	testutil.Check(t, b.validateOwner(ctx, owner))
+	remoteCfgs, err := b.buildRemoteConfigs(ctx, owner, nil, repositoryFields{})
+	testutil.Check(t, err)
+	expected := []remoteConfig{
+		{
+			Remote: Remote{Name: "git.example.com/platform/api", Archived: true, Internal: true, scheme: "ssh"},
+		},
+		{
+			Remote:    Remote{Name: "git.example.com/platform/tools", Upstream: "git.example.com/infra/tools"},
+			Head:      "refs/remotes/git.example.com/platform/tools/heads/main",
+			CreatedAt: created,
+		},
+	}
+	if !slices.Equal(remoteCfgs, expected) {
+		t.Errorf("unexpected remote configs: wanted %v, was %v", expected, remoteCfgs)
+	}
+
+	// remotes are fetched with the scheme of their repository's URL
+	if actual := remoteCfgs[0].Remote.FetchURL(); actual != "ssh://git.example.com/platform/api.git" {
+		t.Errorf("expected the remote to be fetched over ssh, was %q", actual)
+	}
+
+	// repositories of other owners, or with unexpected schemes, are not
+	// configured
+	for _, url := range []string{
+		"https://git.example.com/infra/tools",
+		"https://github.com/platform/tools",
+		"https://git.example.com/platform/tools/extra",
+		"https://git.example.com/platform/",
+		"file://git.example.com/platform/tools",
+		"git.example.com/platform/tools",
+	} {
+		UseProvider(owner.Host(), stubProvider{repos: []Repo{{URL: url}, {URL: "https://git.example.com/platform/api"}}})(b)
+		remoteCfgs, err = b.buildRemoteConfigs(ctx, owner, nil, repositoryFields{})
+		testutil.ExpectError(t, err)
+		if len(remoteCfgs) != 1 || remoteCfgs[0].Remote.Name != "git.example.com/platform/api" {
+			t.Errorf("expected only the owner's repository to be configured for %q, was %v", url, remoteCfgs)
+		}
+	}
+
+	// the repositories listed before a failure are kept
+	listErr := errors.New("pretend the inventory is unavailable")
+	UseProvider(owner.Host(), stubProvider{repos: []Repo{{URL: "https://git.example.com/platform/tools"}}, err: listErr})(b)
+	remoteCfgs, err = b.buildRemoteConfigs(ctx, owner, nil, repositoryFields{})
+	if !errors.Is(err, listErr) {
+		t.Errorf("expected %v, was %v", listErr, err)
+	}
+	if len(remoteCfgs) != 1 {
+		t.Errorf("expected the listed remote to be kept, was %v", remoteCfgs)
+	}
+
+	// repositories accessible to a viewer cannot be discovered with a provider
+	viewer := Viewer{host: owner.Host()}
+	if err := b.validateViewer(ctx, viewer); !errors.Is(err, errProviderViewer) {
+		t.Errorf("expected %v, was %v", errProviderViewer, err)
+	}
+	if _, err := b.buildViewerRemoteConfigs(ctx, viewer, nil, repositoryFields{}); !errors.Is(err, errProviderViewer) {
+		t.Errorf("expected %v, was %v", errProviderViewer, err)
+	}
+
+	// owners of other hosts are still discovered with GitHub
+	if _, ok := b.provider("github.com", nil, repositoryFields{}).(graphQLProvider); !ok {
+		t.Errorf("expected GitHub's GraphQL API to discover owners of github.com")
+	}
+}
@@ -0,0 +1,165 @@
+package biome
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// pruneSafetyWindow is how long ago the expiry of a prune must be at
+	// least. Fetches write objects before they update the references that
+	// make the objects reachable, so recent unreachable objects may still be
+	// needed by a fetch that is in progress.
+	pruneSafetyWindow = 24 * time.Hour
+)
+
+var (
+	// errPruneTooRecent indicates that a prune's expiry is within the safety
+	// window.
+	errPruneTooRecent = fmt.Errorf("expiry must be at least %s ago", pruneSafetyWindow)
+)
+
+// PrunePlan describes the effects that pruning unreachable objects would
+// have, without changing anything.
+type PrunePlan struct {
+
+	// Expire is the time before which unreachable objects are pruned.
+	Expire time.Time
+
+	// Bytes is the on-disk size of the biome's objects.
+	Bytes int64
+
+	// ReclaimableBytes estimates the on-disk size of the objects that are not
+	// reachable from any reference or the index. Objects that are unreachable
+	// for less time than the expiry, or that are still reachable from the
+	// reflog entries that are kept, are not pruned, so this is an upper bound
+	// of the disk that pruning reclaims.
+	ReclaimableBytes int64
+}
+
+// PlanPruneObjects estimates how much disk would be reclaimed by pruning
+// unreachable objects older than expire, a git date, ex. "2.weeks.ago".
+func (b *biome) PlanPruneObjects(ctx context.Context, expire string) (PrunePlan, error) {
+	var plan PrunePlan
+	var err error
+	if plan.Expire, err = b.pruneExpiry(ctx, expire); err != nil {
+		return plan, err
+	}
+	if plan.Bytes, err = b.objectsSize(ctx); err != nil {
+		return plan, err
+	}
+	out, err := b.git(ctx, "rev-list", "--objects", "--disk-usage", "--use-bitmap-index", "--all", "--indexed-objects")
+	if err != nil {
+		return plan, err
+	}
+	reachable, err := strconv.ParseInt(string(bytes.TrimSpace(out)), 10, 64)
+	if err != nil {
+		return plan, fmt.Errorf("could not parse disk usage of reachable objects: %w", err)
+	}
+	plan.ReclaimableBytes = max(plan.Bytes-reachable, 0)
+	return plan, nil
+}
+
+// PruneObjects prunes unreachable objects older than expire, a git date, ex.
+// "2.weeks.ago", returning how much disk was reclaimed. Removed and evicted
+// remotes leave their objects behind until they are pruned. The expiry must
+// be before the safety window, so that objects written by fetches in progress
+// are kept. Reflog and journal entries are expired according to the biome's
+// retention settings.
+func (b *biome) PruneObjects(ctx context.Context, expire string) (int64, error) {
+	if _, err := b.pruneExpiry(ctx, expire); err != nil {
+		return 0, err
+	}
+	before, err := b.objectsSize(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// git gc expires reflog entries according to gc.reflogExpire and the
+	// gc.<pattern>.reflogExpire options set by the reflog retention setting
+	if _, err := b.git(ctx, "gc", "--quiet", "--prune="+expire); err != nil {
+		return 0, err
+	}
+	if err := b.expireJournal(ctx); err != nil {
+		return 0, fmt.Errorf("could not expire journal: %w", err)
+	}
+	after, err := b.objectsSize(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return max(before-after, 0), nil
+}
+
+// pruneExpiry resolves the git date expire, ex. "2.weeks.ago", to a time, and
+// ensures that it is before the safety window. Git resolves dates that it
+// cannot parse to the current time, so they are rejected too.
+func (b *biome) pruneExpiry(ctx context.Context, expire string) (time.Time, error) {
+	t, err := b.resolveDate(ctx, expire)
+	if err != nil {
+		return t, err
+	}
+	if time.Since(t) < pruneSafetyWindow {
+		return t, fmt.Errorf("%w: %s", errPruneTooRecent, expire)
+	}
+	return t, nil
+}
+
+// resolveDate resolves a git date, ex. "2.weeks.ago", to a time, the way git
+// resolves the expiry of reflog entries and unreachable objects. "never"
+// resolves to the Unix epoch.
+func (b *biome) resolveDate(ctx context.Context, date string) (time.Time, error) {
+	out, err := b.git(ctx, "rev-parse", "--since="+date)
+	if err != nil {
+		return time.Time{}, err
+	}
+	value, ok := strings.CutPrefix(strings.TrimSpace(string(out)), "--max-age=")
+	if !ok {
+		return time.Time{}, fmt.Errorf("could not parse date %q: %s", date, out)
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not parse date %q: %w", date, err)
+	}
+	return time.Unix(seconds, 0), nil
+}
+
+// objectsSize returns the on-disk size of the biome's loose and packed
+// objects.
+func (b *biome) objectsSize(ctx context.Context) (int64, error) {
+	out, err := b.git(ctx, "count-objects", "-v")
+	if err != nil {
+		return 0, err
+	}
+	var size int64
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(scanner.Text(), ": ")
+		if key != "size" && key != "size-pack" {
+			continue
+		}
+		kib, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("could not parse %s of objects: %w", key, err)
+		}
+		size += kib * 1024
+	}
+	return size, scanner.Err()
+}
+
+// git runs a git command in the biome, returning its output.
+func (b *biome) git(ctx context.Context, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", b.path}, args...)...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not %q: %w: %s", cmd, err, stderr.String())
+	}
+	return out, nil
+}
@@ -0,0 +1,57 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_PruneObjects(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path: path,
+	}
+	createCommitFor(t, ctx, path, []string{
+		"refs/remotes/github.com/orirawlings/bar/heads/main",
+	})
+
+	// an object left behind by a removed remote a month ago
+	unreachable := commitTree(t, path, "removed")
+	gitDir := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "rev-parse", "--absolute-git-dir"))
+	monthAgo := time.Now().Add(-30 * 24 * time.Hour)
+	testutil.Check(t, os.Chtimes(filepath.Join(gitDir, "objects", unreachable[:2], unreachable[2:]), monthAgo, monthAgo))
+
+	for _, expire := range []string{"now", "1.hour.ago", "not a date"} {
+		if _, err := b.PlanPruneObjects(ctx, expire); !errors.Is(err, errPruneTooRecent) {
+			t.Errorf("expected expiry %q to be rejected, was %v", expire, err)
+		}
+		if _, err := b.PruneObjects(ctx, expire); !errors.Is(err, errPruneTooRecent) {
+			t.Errorf("expected expiry %q to be rejected, was %v", expire, err)
+		}
+	}
+
+	plan, err := b.PlanPruneObjects(ctx, "2.weeks.ago")
+	testutil.Check(t, err)
+	if plan.Bytes <= 0 || plan.ReclaimableBytes <= 0 || plan.ReclaimableBytes >= plan.Bytes {
+		t.Errorf("unexpected prune plan: %+v", plan)
+	}
+	if since := time.Since(plan.Expire); since < 13*24*time.Hour || since > 15*24*time.Hour {
+		t.Errorf("unexpected expiry: %v", plan.Expire)
+	}
+
+	_, err = b.PruneObjects(ctx, "2.weeks.ago")
+	testutil.Check(t, err)
+	if _, err := b.git(ctx, "cat-file", "-e", unreachable); err == nil {
+		t.Errorf("expected unreachable object %s to be pruned", unreachable)
+	}
+	if _, err := b.git(ctx, "cat-file", "-e", "refs/remotes/github.com/orirawlings/bar/heads/main"); err != nil {
+		t.Errorf("expected reachable object to be kept: %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package biome
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// quarantineKey is the git config key of the setting that fetches into
+	// a quarantine, see [Quarantine].
+	quarantineKey = "biome.fetch.quarantine"
+
+	// quarantinePattern is the pattern of the names of quarantine object
+	// directories, created inside the biome's object directory, so that
+	// objects are migrated by renaming them.
+	quarantinePattern = "tmp_objdir-biome-*"
+)
+
+// ErrQuarantineRejected indicates that objects received into a quarantine
+// failed validation, and were discarded rather than migrated into the biome.
+var ErrQuarantineRejected = errors.New("received objects failed validation")
+
+// Quarantine is a temporary object directory that fetches write the objects
+// they receive into, instead of the biome's object directory, so that the
+// received packs can be validated before any of their objects are migrated
+// into the biome. A corrupt or malicious pack from a compromised upstream
+// is discarded without ever entering a long-lived biome.
+//
+// Git commands run with [Quarantine.Env] read the biome's objects as
+// alternates, but write new objects into the quarantine. Fetches into a
+// quarantine must not update references, ex. with git fetch --dry-run,
+// since the objects they would point to may be discarded.
+type Quarantine struct {
+
+	// objects is the biome's object directory.
+	objects string
+
+	// dir is the quarantine object directory.
+	dir string
+}
+
+// NewQuarantine creates an empty quarantine object directory in the biome.
+// It must be migrated or discarded once the fetch into it is done.
+func (b *biome) NewQuarantine(ctx context.Context) (*Quarantine, error) {
+	gitDir, err := b.gitDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	objects := filepath.Join(gitDir, "objects")
+	dir, err := os.MkdirTemp(objects, quarantinePattern)
+	if err != nil {
+		return nil, fmt.Errorf("could not create quarantine: %w", err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "pack"), 0o777); err != nil {
+		return nil, errors.Join(fmt.Errorf("could not create quarantine: %w", err), os.RemoveAll(dir))
+	}
+	return &Quarantine{objects: objects, dir: dir}, nil
+}
+
+// Env returns the environment variables that make git commands write new
+// objects into the quarantine, while reading the biome's objects too.
+func (q *Quarantine) Env() []string {
+	return []string{
+		"GIT_OBJECT_DIRECTORY=" + q.dir,
+		"GIT_ALTERNATE_OBJECT_DIRECTORIES=" + q.objects,
+	}
+}
+
+// ConfigArgs returns the git options that make fetches into the quarantine
+// check every received object with git's fsck checks, and keep received
+// objects in packs, rather than loose objects, so that the packs can be
+// validated as a whole.
+func (q *Quarantine) ConfigArgs() []string {
+	return []string{
+		"-c", "fetch.fsckObjects=true",
+		"-c", "fetch.unpackLimit=1",
+	}
+}
+
+// Validate verifies each pack received into the quarantine with git
+// index-pack --verify --strict, which checks the pack's checksums, and that
+// its objects are well formed and link only to objects that exist in the
+// quarantine or the biome. [ErrQuarantineRejected] is returned if any pack
+// fails validation.
+func (q *Quarantine) Validate(ctx context.Context) error {
+	packs, err := filepath.Glob(filepath.Join(q.dir, "pack", "*.pack"))
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, pack := range packs {
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, "git", "index-pack", "--verify", "--strict", pack)
+		cmd.Env = append(os.Environ(), q.Env()...)
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			errs = append(errs, fmt.Errorf("%w: %s: %s", ErrQuarantineRejected, filepath.Base(pack), strings.TrimSpace(stderr.String())))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Migrate moves the objects of the quarantine into the biome's object
+// directory, and removes the quarantine. The index of each pack is moved
+// after the pack's other files, so that git never finds an index without
+// its pack. Objects that the biome already has are discarded.
+func (q *Quarantine) Migrate() error {
+	var indexes []string
+	err := filepath.WalkDir(q.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == q.dir {
+			return err
+		}
+		rel, err := filepath.Rel(q.dir, path)
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(q.objects, rel)
+		if d.IsDir() {
+			return os.MkdirAll(dest, 0o777)
+		}
+		if filepath.Ext(path) == ".idx" {
+			indexes = append(indexes, rel)
+			return nil
+		}
+		return migrateObjectFile(path, dest)
+	})
+	for _, rel := range indexes {
+		if err != nil {
+			break
+		}
+		err = migrateObjectFile(filepath.Join(q.dir, rel), filepath.Join(q.objects, rel))
+	}
+	if err != nil {
+		return fmt.Errorf("could not migrate quarantined objects: %w", err)
+	}
+	return q.Discard()
+}
+
+// Discard removes the quarantine, and any objects received into it.
+func (q *Quarantine) Discard() error {
+	return os.RemoveAll(q.dir)
+}
+
+// migrateObjectFile moves a file of the quarantine into the biome's object
+// directory, unless the biome already has it. Object files are named by
+// their content, so an existing file is the same object.
+func migrateObjectFile(src, dest string) error {
+	if _, err := os.Stat(dest); err == nil {
+		return nil
+	}
+	return os.Rename(src, dest)
+}
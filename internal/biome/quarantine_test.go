@@ -0,0 +1,71 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestQuarantine(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	upstream := testutil.TempRepo(t)
+	commit := createCommitFor(t, ctx, upstream, []string{"refs/heads/main"})
+
+	newQuarantine := func(t *testing.T) *Quarantine {
+		t.Helper()
+		q, err := b.NewQuarantine(ctx)
+		testutil.Check(t, err)
+		t.Cleanup(func() {
+			testutil.Check(t, q.Discard())
+		})
+		return q
+	}
+
+	t.Run("migrate", func(t *testing.T) {
+		q := newQuarantine(t)
+		args := append([]string{"-C", path}, q.ConfigArgs()...)
+		cmd := exec.Command("git", append(args, "fetch", "--dry-run", upstream, "refs/heads/main:refs/remotes/upstream/main")...)
+		cmd.Env = append(os.Environ(), q.Env()...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("could not fetch into quarantine: %v: %s", err, out)
+		}
+		if err := exec.Command("git", "-C", path, "cat-file", "-e", commit).Run(); err == nil {
+			t.Fatal("expected quarantined commit to be missing from the biome")
+		}
+
+		testutil.Check(t, q.Validate(ctx))
+		testutil.Check(t, q.Migrate())
+		testutil.Execute(t, "git", "-C", path, "cat-file", "-e", commit)
+		if _, err := os.Stat(q.dir); !os.IsNotExist(err) {
+			t.Errorf("expected quarantine to be removed, was %v", err)
+		}
+		if refs := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "for-each-ref", "refs/remotes/")); refs != "" {
+			t.Errorf("expected no references to be updated, was %q", refs)
+		}
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		q := newQuarantine(t)
+		pack := filepath.Join(q.dir, "pack", "pack-0000000000000000000000000000000000000000.pack")
+		testutil.Check(t, os.WriteFile(pack, []byte("PACK\x00\x00\x00\x02\x00\x00\x00\x01garbage"), 0o644))
+		if err := q.Validate(ctx); !errors.Is(err, ErrQuarantineRejected) {
+			t.Errorf("expected corrupt pack to be rejected, was %v", err)
+		}
+		testutil.Check(t, q.Discard())
+		if _, err := os.Stat(q.dir); !os.IsNotExist(err) {
+			t.Errorf("expected quarantine to be discarded, was %v", err)
+		}
+	})
+}
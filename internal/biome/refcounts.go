@@ -0,0 +1,108 @@
+package biome
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+)
+
+// RefGrowth is how much the number of references of a remote grew over a
+// period, ex. because pull request references are piling up.
+type RefGrowth struct {
+
+	// Remote is the name of the remote.
+	Remote string
+
+	// Since is when the remote's references were first counted within the
+	// period. It is the zero time if they were never counted before.
+	Since time.Time
+
+	// From is the number of the remote's references at Since.
+	From int
+
+	// To is the current number of the remote's references.
+	To int
+}
+
+// Growth is the number of references the remote gained since it was first
+// counted within the period. It is negative if references were pruned.
+func (g RefGrowth) Growth() int {
+	return g.To - g.From
+}
+
+// RefCounts returns the number of references that each fetchable remote has
+// fetched into its namespace, keyed by the remote's name, not counting
+// symbolic references, ex. the remote's HEAD.
+func (b *biome) RefCounts(ctx context.Context) (map[string]int, error) {
+	remotes, err := b.Remotes(ctx, FetchableRemoteCategories...)
+	if err != nil {
+		return nil, err
+	}
+	namespaces := make(map[string]string)
+	var prefixes []string
+	counts := make(map[string]int)
+	for _, r := range remotes {
+		namespaces[r.RefNamespace()] = r.Name
+		prefixes = append(prefixes, r.RefNamespace())
+		counts[r.Name] = 0
+	}
+	refs, err := b.listRefs(ctx, prefixes)
+	if err != nil {
+		return nil, err
+	}
+	for _, ref := range refs {
+		if ref.Symref != "" {
+			continue
+		}
+		if remote, ok := remoteOfRef(namespaces, ref.Name); ok {
+			counts[remote]++
+		}
+	}
+	return counts, nil
+}
+
+// RefCountEntries returns journal entries that record the given counts of
+// references of each remote, see [Biome.RefCounts], at the given time, sorted
+// by remote name.
+func RefCountEntries(counts map[string]int, t time.Time) []JournalEntry {
+	var entries []JournalEntry
+	for remote, n := range counts {
+		entries = append(entries, JournalEntry{Time: t, Op: RefsOp, Remote: remote, Refs: n})
+	}
+	slices.SortFunc(entries, func(a, b JournalEntry) int {
+		return cmp.Compare(a.Remote, b.Remote)
+	})
+	return entries
+}
+
+// RefCountGrowth compares the current counts of references of each remote
+// with the earliest counts recorded in the journal at or after since,
+// returning the growth of each remote, fastest growing first. Remotes whose
+// references were not counted since then are compared with their latest
+// count before since, if any, or with no references otherwise.
+func RefCountGrowth(entries []JournalEntry, counts map[string]int, since time.Time) []RefGrowth {
+	baselines := make(map[string]JournalEntry)
+	for _, e := range entries {
+		if e.Op != RefsOp || e.Remote == "" {
+			continue
+		}
+		if b, ok := baselines[e.Remote]; ok && !b.Time.Before(since) {
+			// keep the earliest count within the period
+			continue
+		}
+		baselines[e.Remote] = e
+	}
+	var growth []RefGrowth
+	for remote, n := range counts {
+		b := baselines[remote]
+		growth = append(growth, RefGrowth{Remote: remote, Since: b.Time, From: b.Refs, To: n})
+	}
+	slices.SortFunc(growth, func(a, b RefGrowth) int {
+		return cmp.Or(
+			cmp.Compare(b.Growth(), a.Growth()),
+			cmp.Compare(a.Remote, b.Remote),
+		)
+	})
+	return growth
+}
@@ -0,0 +1,85 @@
+package biome
+
+import (
+	"context"
+	"maps"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_RefCounts(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		for _, r := range []Remote{barRemote, headlessRemote} {
+			cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, r.Name)
+		}
+		return true, nil
+	}))
+	createCommitFor(t, ctx, path, []string{
+		"refs/remotes/github.com/orirawlings/bar/heads/main",
+		"refs/remotes/github.com/orirawlings/bar/pull/1/head",
+		"refs/remotes/github.com/orirawlings/bar/tags/v1",
+		"refs/remotes/github.com/orirawlings/barbaz/heads/main",
+	})
+	testutil.Execute(t, "git", "-C", path, "symbolic-ref", barRemote.Head(), "refs/remotes/github.com/orirawlings/bar/heads/main")
+
+	counts, err := b.RefCounts(ctx)
+	testutil.Check(t, err)
+	expected := map[string]int{
+		barRemote.Name:      3,
+		headlessRemote.Name: 0,
+	}
+	if !maps.Equal(counts, expected) {
+		t.Errorf("unexpected reference counts: wanted %v, was %v", expected, counts)
+	}
+}
+
+func TestRefCountGrowth(t *testing.T) {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	since := start.Add(24 * time.Hour)
+	entries := []JournalEntry{
+		{Time: start, Op: RefsOp, Remote: "github.com/cli/cli", Refs: 100},
+		{Time: start.Add(time.Hour), Op: RefsOp, Remote: "github.com/cli/cli", Refs: 110},
+		{Time: start.Add(time.Hour), Op: RefsOp, Remote: "github.com/cli/go-gh", Refs: 50},
+		{Time: since, Op: FetchOp, Remote: "github.com/cli/cli", Refs: 1},
+		{Time: since.Add(time.Hour), Op: RefsOp, Remote: "github.com/cli/cli", Refs: 120},
+		{Time: since.Add(2 * time.Hour), Op: RefsOp, Remote: "github.com/cli/cli", Refs: 400},
+	}
+	counts := map[string]int{
+		"github.com/cli/cli":   1000,
+		"github.com/cli/go-gh": 40,
+		"github.com/cli/new":   30,
+	}
+	expected := []RefGrowth{
+		{Remote: "github.com/cli/cli", Since: since.Add(time.Hour), From: 120, To: 1000},
+		{Remote: "github.com/cli/new", To: 30},
+		{Remote: "github.com/cli/go-gh", Since: start.Add(time.Hour), From: 50, To: 40},
+	}
+	if growth := RefCountGrowth(entries, counts, since); !reflect.DeepEqual(growth, expected) {
+		t.Errorf("unexpected growth: wanted %+v, was %+v", expected, growth)
+	}
+	if g := expected[2].Growth(); g != -10 {
+		t.Errorf("unexpected growth of pruned remote: wanted -10, was %d", g)
+	}
+}
+
+func TestRefCountEntries(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	entries := RefCountEntries(map[string]int{"b": 2, "a": 1}, now)
+	expected := []JournalEntry{
+		{Time: now, Op: RefsOp, Remote: "a", Refs: 1},
+		{Time: now, Op: RefsOp, Remote: "b", Refs: 2},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("unexpected entries: wanted %+v, was %+v", expected, entries)
+	}
+}
@@ -0,0 +1,79 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+)
+
+// RefFormat is the backend that stores the biome's references.
+type RefFormat string
+
+const (
+	// RefFormatFiles stores references as loose files and a packed-refs
+	// file, git's default. Concurrent fetches of many remotes can update
+	// references at the same time.
+	RefFormatFiles RefFormat = "files"
+
+	// RefFormatReftable stores references in reftable files, which are much
+	// faster to read in bulk. A git process that finds the reftable locked by
+	// a concurrent update fails rather than waiting, so fetches only receive
+	// objects in parallel, and their reference updates are applied one
+	// transaction at a time.
+	RefFormatReftable RefFormat = "reftable"
+)
+
+// refFormatKey is the git config key that records the [RefFormat] chosen
+// when the biome was initialized.
+const refFormatKey = "biome.refFormat"
+
+// refFormats lists the valid values of [RefFormat].
+var refFormats = []string{string(RefFormatFiles), string(RefFormatReftable)}
+
+// errRefFormatUnsupported indicates that the installed git cannot create
+// repositories with a reference backend.
+var errRefFormatUnsupported = errors.New("ref format is not supported by the installed git")
+
+// ParseRefFormat parses the name of a [RefFormat].
+func ParseRefFormat(value string) (RefFormat, error) {
+	if err := validateOneOf(refFormats...)(value); err != nil {
+		return "", fmt.Errorf("invalid ref format: %q: %w", value, err)
+	}
+	return RefFormat(value), nil
+}
+
+// checkSupported ensures that the installed git can create repositories with
+// the reference backend. `git init --ref-format` requires git 2.45 or later.
+func (f RefFormat) checkSupported(ctx context.Context) error {
+	if err := tryInit(ctx, "--ref-format="+string(f)); err != nil {
+		return fmt.Errorf("%w: %s: %w", errRefFormatUnsupported, f, err)
+	}
+	return nil
+}
+
+// UseRefFormat initializes a new biome with the given [RefFormat], and
+// records the choice as the biome.refFormat setting. If this option is not
+// given, git's default reference backend is used. The option has no effect
+// when loading a biome, and initializing a biome that already exists with
+// another reference backend fails.
+func UseRefFormat(format RefFormat) BiomeOption {
+	return func(b *biome) {
+		b.refFormat = format
+	}
+}
+
+// RefFormat returns the reference backend of the biome's repository, as git
+// records it in the extensions.refStorage config, whether or not biome chose
+// it when the biome was initialized.
+func (b *biome) RefFormat(ctx context.Context) (RefFormat, error) {
+	format := RefFormatFiles
+	err := b.readConfig(ctx, func(ctx context.Context, cfg *config.Config) error {
+		if v := cfg.Section("extensions").Option("refStorage"); v != "" {
+			format = RefFormat(v)
+		}
+		return nil
+	})
+	return format, err
+}
@@ -0,0 +1,65 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestParseRefFormat(t *testing.T) {
+	for _, format := range []RefFormat{RefFormatFiles, RefFormatReftable} {
+		parsed, err := ParseRefFormat(string(format))
+		testutil.Check(t, err)
+		if parsed != format {
+			t.Errorf("expected %q, was %q", format, parsed)
+		}
+	}
+	_, err := ParseRefFormat("packed")
+	testutil.ExpectError(t, err)
+
+	if err := RefFormat("packed").checkSupported(context.Background()); !errors.Is(err, errRefFormatUnsupported) {
+		t.Errorf("expected %v, was %v", errRefFormatUnsupported, err)
+	}
+}
+
+func TestInit_refFormat(t *testing.T) {
+	ctx := context.Background()
+	if err := RefFormatReftable.checkSupported(ctx); err != nil {
+		t.Skipf("installed git does not support reftable: %v", err)
+	}
+	path := t.TempDir()
+	b := initBiome(t, ctx, path, true, UseRefFormat(RefFormatReftable))
+	if format := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "rev-parse", "--show-ref-format")); format != string(RefFormatReftable) {
+		t.Errorf("expected %q ref format, was %q", RefFormatReftable, format)
+	}
+	assertGitConfig(t, path, refFormatKey, string(RefFormatReftable))
+	if format, err := b.RefFormat(ctx); err != nil || format != RefFormatReftable {
+		t.Errorf("expected %q ref format, was %q: %v", RefFormatReftable, format, err)
+	}
+
+	// an existing biome keeps its ref format
+	initBiome(t, ctx, path, false, UseRefFormat(RefFormatFiles))
+}
+
+func TestBiome_RefFormat(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{path: path, editorOptions: []config.EditorOption{config.Direct()}}
+	format, err := b.RefFormat(ctx)
+	testutil.Check(t, err)
+	if format != RefFormatFiles {
+		t.Errorf("expected %q ref format, was %q", RefFormatFiles, format)
+	}
+
+	// the backend git records is reported, even if biome did not choose it
+	testutil.Execute(t, "git", "-C", path, "config", "set", "--local", "extensions.refStorage", "reftable")
+	format, err = b.RefFormat(ctx)
+	testutil.Check(t, err)
+	if format != RefFormatReftable {
+		t.Errorf("expected %q ref format, was %q", RefFormatReftable, format)
+	}
+}
@@ -0,0 +1,209 @@
+package biome
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+)
+
+// storedRef is a reference stored in the biome's git repository.
+type storedRef struct {
+
+	// Name of the reference, ex. `refs/remotes/github.com/cli/cli/heads/trunk`.
+	Name string
+
+	// ObjectName is the object ID the reference points to. It is empty for
+	// symbolic references.
+	ObjectName string
+
+	// Symref is the name of the reference that a symbolic reference points
+	// to. It is empty for regular references.
+	Symref string
+}
+
+// listRefs returns all references whose names begin with any of the given
+// prefixes. When the biome uses the "files" reference backend and SHA-1
+// object names, references are read in-process, avoiding the overhead of
+// forking git. Otherwise, the references are listed by `git for-each-ref`.
+func (b *biome) listRefs(ctx context.Context, prefixes []string) ([]storedRef, error) {
+	if len(prefixes) == 0 {
+		return nil, nil
+	}
+	cfg, err := config.Read(ctx, b.path)
+	if err != nil {
+		return nil, err
+	}
+	if refStorage := cfg.Section("extensions").Option("refStorage"); refStorage != "" && refStorage != "files" {
+		return b.forEachRef(ctx, prefixes)
+	}
+	if objectFormat := cfg.Section("extensions").Option("objectFormat"); objectFormat != "" && objectFormat != string(ObjectFormatSHA1) {
+		// the in-process reader only reads SHA-1 object names
+		return b.forEachRef(ctx, prefixes)
+	}
+	return b.iterRefs(ctx, prefixes)
+}
+
+// iterRefs reads references from the "files" reference backend in-process.
+// Like `git for-each-ref`, a prefix matches the references named by it, and
+// those below it, but not references whose last name component merely
+// begins with it. Only the loose references below each prefix are read, in
+// addition to the packed references, so listing a narrow prefix does not
+// read every loose reference of the biome.
+func (b *biome) iterRefs(ctx context.Context, prefixes []string) ([]storedRef, error) {
+	gitDir, err := b.gitDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	patterns := make(map[string]bool)
+	for _, prefix := range prefixes {
+		patterns[strings.TrimSuffix(prefix, "/")] = true
+	}
+
+	refs := make(map[string]storedRef)
+	if err := readPackedRefs(filepath.Join(gitDir, "packed-refs"), patterns, refs); err != nil {
+		return nil, fmt.Errorf("could not read references: %w", err)
+	}
+	for pattern := range patterns {
+		if err := readLooseRefs(ctx, gitDir, pattern, refs); err != nil {
+			return nil, fmt.Errorf("could not read references: %w", err)
+		}
+	}
+	return slices.SortedFunc(maps.Values(refs), func(a, b storedRef) int {
+		return strings.Compare(a.Name, b.Name)
+	}), nil
+}
+
+// matchesRefPattern reports whether the named reference is named by any of
+// the patterns, or is below one of them.
+func matchesRefPattern(name string, patterns map[string]bool) bool {
+	for i := range len(name) {
+		if name[i] == '/' && patterns[name[:i]] {
+			return true
+		}
+	}
+	return patterns[name]
+}
+
+// readPackedRefs adds the packed references that match any of the patterns
+// to refs.
+func readPackedRefs(path string, patterns map[string]bool, refs map[string]storedRef) error {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || line[0] == '#' || line[0] == '^' {
+			continue
+		}
+		objectName, name, ok := strings.Cut(line, " ")
+		if !ok || !plumbing.IsHash(objectName) {
+			return fmt.Errorf("malformed packed reference: %q", line)
+		}
+		if matchesRefPattern(name, patterns) {
+			refs[name] = storedRef{Name: name, ObjectName: objectName}
+		}
+	}
+	return scanner.Err()
+}
+
+// readLooseRefs adds the loose reference named by the pattern, or the loose
+// references below it, to refs, replacing packed references of the same
+// name.
+func readLooseRefs(ctx context.Context, gitDir, pattern string, refs map[string]storedRef) error {
+	root := filepath.Join(gitDir, filepath.FromSlash(pattern))
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasSuffix(path, ".lock") {
+			return nil
+		}
+		rel, err := filepath.Rel(gitDir, path)
+		if err != nil {
+			return err
+		}
+		ref, err := readLooseRef(filepath.ToSlash(rel), path)
+		if err != nil {
+			return err
+		}
+		refs[ref.Name] = ref
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// readLooseRef reads the named loose reference from the file at path.
+func readLooseRef(name, path string) (storedRef, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return storedRef{}, err
+	}
+	content := strings.TrimSpace(string(data))
+	if target, ok := strings.CutPrefix(content, "ref: "); ok {
+		return storedRef{Name: name, Symref: target}, nil
+	}
+	if !plumbing.IsHash(content) {
+		return storedRef{}, fmt.Errorf("malformed reference: %s", name)
+	}
+	return storedRef{Name: name, ObjectName: content}, nil
+}
+
+// forEachRef lists references with `git for-each-ref`, supporting any
+// reference backend.
+func (b *biome) forEachRef(ctx context.Context, prefixes []string) ([]storedRef, error) {
+	args := []string{"-C", b.path, "for-each-ref", "--format=%(refname) %(if)%(symref)%(then)%(else)%(objectname)%(end) %(symref)"}
+	args = append(args, prefixes...)
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not %q: %w: %s", cmd, err, stderr.String())
+	}
+
+	var refs []storedRef
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		name, rest, _ := strings.Cut(scanner.Text(), " ")
+		objectName, symref, _ := strings.Cut(rest, " ")
+		refs = append(refs, storedRef{Name: name, ObjectName: objectName, Symref: symref})
+	}
+	return refs, scanner.Err()
+}
+
+// gitDir returns the absolute path of the biome's git directory.
+func (b *biome) gitDir(ctx context.Context) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "-C", b.path, "rev-parse", "--absolute-git-dir")
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("could not %q: %w: %s", cmd, err, stderr.String())
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,73 @@
+package biome
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_listRefs(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{path: path}
+
+	commitID := createCommitFor(t, ctx, path, []string{
+		"refs/remotes/github.com/orirawlings/bar/heads/main",
+		"refs/remotes/github.com/orirawlings/bar/tags/v1",
+		"refs/remotes/github.com/orirawlings/barbaz/heads/main",
+		"refs/remotes/github.com/cli/cli/heads/trunk",
+	})
+	// references may be packed or loose
+	testutil.Execute(t, "git", "-C", path, "pack-refs", "--all")
+	createCommitFor(t, ctx, path, []string{
+		"refs/remotes/github.com/orirawlings/bar/heads/loose",
+	})
+	testutil.Execute(t, "git", "-C", path, "symbolic-ref", "refs/remotes/github.com/orirawlings/bar/HEAD", "refs/remotes/github.com/orirawlings/bar/heads/main")
+
+	prefixes := []string{
+		"refs/remotes/github.com/orirawlings/bar/",
+		"refs/remotes/github.com/cli/cli/heads/trunk",
+		// prefixes match whole name components only
+		"refs/remotes/github.com/orirawlings/ba",
+	}
+	sortRefs := func(refs []storedRef) []storedRef {
+		slices.SortFunc(refs, func(a, b storedRef) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		return refs
+	}
+
+	// only the loose references below the prefixes are read
+	broken := filepath.Join(path, "refs", "remotes", "github.com", "git", "git", "heads", "broken")
+	testutil.Check(t, os.MkdirAll(filepath.Dir(broken), 0o755))
+	testutil.Check(t, os.WriteFile(broken, []byte("not a reference\n"), 0o644))
+	inProcess, err := b.iterRefs(ctx, prefixes)
+	testutil.Check(t, err)
+	testutil.Check(t, os.Remove(broken))
+	forked, err := b.forEachRef(ctx, prefixes)
+	testutil.Check(t, err)
+	listed, err := b.listRefs(ctx, prefixes)
+	testutil.Check(t, err)
+
+	expected := []storedRef{
+		{Name: "refs/remotes/github.com/cli/cli/heads/trunk", ObjectName: commitID},
+		{Name: "refs/remotes/github.com/orirawlings/bar/HEAD", Symref: "refs/remotes/github.com/orirawlings/bar/heads/main"},
+		{Name: "refs/remotes/github.com/orirawlings/bar/heads/loose", ObjectName: commitID},
+		{Name: "refs/remotes/github.com/orirawlings/bar/heads/main", ObjectName: commitID},
+		{Name: "refs/remotes/github.com/orirawlings/bar/tags/v1", ObjectName: commitID},
+	}
+	for name, refs := range map[string][]storedRef{
+		"in-process": inProcess,
+		"forked":     forked,
+		"listed":     listed,
+	} {
+		if refs = sortRefs(refs); !slices.Equal(refs, expected) {
+			t.Errorf("unexpected %s references: wanted %v, was %v", name, expected, refs)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package biome
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureStatus is the outcome of verifying a GPG or SSH signature with
+// the user's gpg and gpg.ssh configuration.
+type SignatureStatus string
+
+const (
+	// SignatureGood is an intact signature, including signatures by
+	// expired keys or keys of unknown validity.
+	SignatureGood SignatureStatus = "good"
+
+	// SignatureBad is a signature that does not match the signed content,
+	// or a signature by a revoked key.
+	SignatureBad SignatureStatus = "bad"
+
+	// SignatureUnverified is a signature that could not be checked, ex.
+	// because the signing key is not known.
+	SignatureUnverified SignatureStatus = "unverified"
+
+	// SignatureMissing indicates that there is no signature.
+	SignatureMissing SignatureStatus = "unsigned"
+)
+
+// signatureStatus returns the status of a signature reported by git's %G?
+// format placeholder.
+func signatureStatus(code string) SignatureStatus {
+	switch code {
+	case "G", "U", "X", "Y":
+		return SignatureGood
+	case "B", "R":
+		return SignatureBad
+	case "E":
+		return SignatureUnverified
+	default:
+		return SignatureMissing
+	}
+}
+
+// Release is the latest tag of a remote, see [biome.Releases].
+type Release struct {
+
+	// Remote is the name of the remote.
+	Remote string
+
+	// Tag is the name of the tag, ex. v1.0.
+	Tag string
+
+	// Commit is the object ID of the tagged commit.
+	Commit string
+
+	// Date is the committer date of the tagged commit.
+	Date time.Time
+
+	// SignedTag indicates that the tag itself is signed, so Signature and
+	// Signer describe the tag's signature. Otherwise, they describe the
+	// signature of the tagged commit.
+	SignedTag bool
+
+	// Signature is the status of the tag's or commit's signature.
+	Signature SignatureStatus
+
+	// Signer identifies who made the signature, ex. the user ID of a GPG
+	// key, the principal of an SSH key, or the SSH key's fingerprint if no
+	// principal is allowed to sign with it.
+	Signer string
+}
+
+// Releases returns the latest tag of each of the biome's fetchable remotes,
+// or only of the remotes of the given owners, sorted by remote name. The
+// latest tag is the one whose tagged commit was committed most recently.
+// If pattern is not empty, only tags whose names match the glob pattern, ex.
+// v*, are considered. Remotes without any such tag are omitted.
+func (b *biome) Releases(ctx context.Context, pattern string, owners ...Owner) ([]Release, error) {
+	if pattern != "" {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid tag pattern %q: %w", pattern, err)
+		}
+	}
+	remotes, err := b.Remotes(ctx, FetchableRemoteCategories...)
+	if err != nil {
+		return nil, err
+	}
+	if len(owners) > 0 {
+		remotes = slices.DeleteFunc(remotes, func(r Remote) bool {
+			return !slices.ContainsFunc(owners, func(owner Owner) bool {
+				return owner.String() == path.Dir(r.Name)
+			})
+		})
+	}
+	namespaces := make(map[string]string)
+	var prefixes []string
+	for _, r := range remotes {
+		namespaces[r.RefNamespace()] = r.Name
+		prefixes = append(prefixes, r.RefNamespace()+"tags/")
+	}
+
+	// the latest tag of each remote, and whether the tag object is signed
+	latest := make(map[string]Release)
+	var signedTags []string
+	tagObjects := make(map[string]string)
+	for batch := range slices.Chunk(prefixes, forEachRefBatchSize) {
+		args := append([]string{
+			"for-each-ref",
+			"--format=%(refname)%00%(objectname)%00%(*objectname)%00%(committerdate:unix)%00%(*committerdate:unix)%00%(if)%(contents:signature)%(then)signed%(end)",
+		}, batch...)
+		out, err := b.git(ctx, args...)
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(out))
+		for scanner.Scan() {
+			fields := strings.Split(scanner.Text(), "\x00")
+			if len(fields) != 6 {
+				continue
+			}
+			ref, object, peeled, date, peeledDate, signed := fields[0], fields[1], fields[2], fields[3], fields[4], fields[5]
+			remote, ok := remoteOfRef(namespaces, ref)
+			if !ok {
+				continue
+			}
+			tag := strings.TrimPrefix(relativeRef(namespaces, ref), "tags/")
+			if pattern != "" {
+				if ok, _ := path.Match(pattern, tag); !ok {
+					continue
+				}
+			}
+			commit := object
+			if peeled != "" {
+				commit, date = peeled, peeledDate
+			}
+			seconds, err := strconv.ParseInt(date, 10, 64)
+			if err != nil {
+				// tags of trees and blobs are not releases
+				continue
+			}
+			r := Release{
+				Remote:    remote,
+				Tag:       tag,
+				Commit:    commit,
+				Date:      time.Unix(seconds, 0).UTC(),
+				SignedTag: signed != "",
+			}
+			if prev, ok := latest[remote]; ok && !r.Date.After(prev.Date) {
+				continue
+			}
+			latest[remote] = r
+			tagObjects[remote] = object
+		}
+	}
+
+	var releases []Release
+	var commits []string
+	for _, r := range remotes {
+		if release, ok := latest[r.Name]; ok {
+			releases = append(releases, release)
+			commits = append(commits, release.Commit)
+			if release.SignedTag {
+				signedTags = append(signedTags, tagObjects[r.Name])
+			}
+		}
+	}
+	if len(releases) == 0 {
+		return nil, nil
+	}
+
+	// verify the signatures of signed tags, and of the tagged commits of
+	// the other tags
+	args, err := b.verifyArgs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	commitSignatures, err := b.commitSignatures(ctx, args, commits)
+	if err != nil {
+		return nil, err
+	}
+	for i, r := range releases {
+		if r.SignedTag {
+			releases[i].Signature, releases[i].Signer = b.verifyTag(ctx, args, tagObjects[r.Remote])
+			continue
+		}
+		s := commitSignatures[r.Commit]
+		releases[i].Signature, releases[i].Signer = s.status, s.signer
+	}
+	return releases, nil
+}
+
+// verifyArgs returns the git arguments that configure the verification of
+// signatures. Without allowed signers, git cannot verify SSH signatures at
+// all, and reports them as missing. They are verified against no allowed
+// signers instead, so that intact SSH signatures are good, of unknown
+// validity.
+func (b *biome) verifyArgs(ctx context.Context) ([]string, error) {
+	args := []string{"-C", b.path}
+	allowedSigners, err := b.userConfig(ctx, "gpg.ssh.allowedSignersFile")
+	if err != nil {
+		return nil, err
+	}
+	if allowedSigners == "" {
+		args = append(args, "-c", "gpg.ssh.allowedSignersFile="+os.DevNull)
+	}
+	return args, nil
+}
+
+// signature is the verified signature of a commit or tag.
+type signature struct {
+	status SignatureStatus
+	signer string
+}
+
+// commitSignatures verifies the signatures of the given commits, keyed by
+// object ID.
+func (b *biome) commitSignatures(ctx context.Context, args, commits []string) (map[string]signature, error) {
+	args = append(slices.Clone(args), "log", "--no-walk=unsorted", "--format=%H%x00%G?%x00%GS%x00%GK", "--stdin")
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Stdin = strings.NewReader(strings.Join(commits, "\n") + "\n")
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not %q: %w: %s", cmd, err, stderr.String())
+	}
+	signatures := make(map[string]signature)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\x00")
+		if len(fields) != 4 {
+			continue
+		}
+		signatures[fields[0]] = signature{
+			status: signatureStatus(fields[1]),
+			// the key identifies signers that are not known
+			signer: cmp.Or(fields[2], fields[3]),
+		}
+	}
+	return signatures, scanner.Err()
+}
+
+var (
+	// gpgStatus matches the status lines of gpg that report the outcome
+	// of verifying a signature, and the signing key's user ID.
+	gpgStatus = regexp.MustCompile(`(?m)^\[GNUPG:\] (GOODSIG|EXPSIG|EXPKEYSIG|REVKEYSIG|BADSIG) \S+ (.*)$`)
+
+	// sshGoodSignature matches the output of ssh-keygen for an intact
+	// signature, with the signer's principal if it is an allowed signer,
+	// and the signing key's fingerprint.
+	sshGoodSignature = regexp.MustCompile(`Good "git" signature (?:for (.+) )?with \S+ key (\S+)`)
+)
+
+// verifyTag verifies the signature of a signed tag object, returning its
+// status and signer.
+func (b *biome) verifyTag(ctx context.Context, args []string, tag string) (SignatureStatus, string) {
+	args = append(slices.Clone(args), "verify-tag", "--raw", tag)
+	// the outcome is read from gpg's and ssh-keygen's output, since git
+	// fails to verify intact signatures of unknown signers too
+	out, _ := exec.CommandContext(ctx, "git", args...).CombinedOutput()
+	if m := gpgStatus.FindSubmatch(out); m != nil {
+		switch string(m[1]) {
+		case "BADSIG", "REVKEYSIG":
+			return SignatureBad, string(m[2])
+		default:
+			return SignatureGood, string(m[2])
+		}
+	}
+	if m := sshGoodSignature.FindSubmatch(out); m != nil {
+		if len(m[1]) > 0 {
+			return SignatureGood, string(m[1])
+		}
+		return SignatureGood, string(m[2])
+	}
+	if bytes.Contains(out, []byte("Signature verification failed")) || bytes.Contains(out, []byte("Could not verify signature")) {
+		return SignatureBad, ""
+	}
+	return SignatureUnverified, ""
+}
@@ -0,0 +1,91 @@
+package biome
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_Releases(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		for _, r := range []Remote{barRemote, githubCLICLIRemote, githubGitGitRemote, headlessRemote} {
+			cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, r.Name)
+		}
+		return true, nil
+	}))
+
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen is required to sign tags")
+	}
+	key := filepath.Join(t.TempDir(), "key")
+	testutil.Execute(t, "ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", key)
+	fingerprint := strings.Fields(testutil.Execute(t, "ssh-keygen", "-l", "-E", "sha256", "-f", key+".pub"))[1]
+	sign := []string{"-c", "gpg.format=ssh", "-c", "user.signingKey=" + key}
+
+	commitAt := func(message, date string) string {
+		t.Setenv("GIT_COMMITTER_DATE", date)
+		return commitTree(t, path, message)
+	}
+	old := commitAt("old", "2024-01-01T12:00:00Z")
+	recent := commitAt("recent", "2025-01-01T12:00:00Z")
+	t.Setenv("GIT_COMMITTER_DATE", "2025-01-01T12:00:00Z")
+	signedCommit := strings.TrimSpace(testutil.Execute(t, append(append([]string{"git", "-C", path}, sign...), "commit-tree", "-S", "-m", "signed", "4b825dc642cb6eb9a060e54bf8d69288fbee4904")...))
+
+	// a signed tag of cli/cli's old commit, and an unsigned annotated tag of
+	// git/git's signed commit
+	testutil.Execute(t, append(append([]string{"git", "-C", path}, sign...), "tag", "-s", "-m", "v1.0", "signed", old)...)
+	testutil.Execute(t, "git", "-C", path, "tag", "-a", "-m", "v2.0", "annotated", signedCommit)
+	signedTag := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "rev-parse", "refs/tags/signed"))
+	annotatedTag := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "rev-parse", "refs/tags/annotated"))
+	for ref, object := range map[string]string{
+		"refs/remotes/github.com/cli/cli/tags/v1.0":              signedTag,
+		"refs/remotes/github.com/cli/cli/tags/nightly":           old,
+		"refs/remotes/github.com/git/git/tags/v2.0":              annotatedTag,
+		"refs/remotes/github.com/orirawlings/bar/tags/v0.1":      old,
+		"refs/remotes/github.com/orirawlings/bar/tags/v0.2":      recent,
+		"refs/remotes/github.com/orirawlings/bar/heads/main":     recent,
+		"refs/remotes/github.com/orirawlings/headless/tags/test": recent,
+	} {
+		testutil.Execute(t, "git", "-C", path, "update-ref", ref, object)
+	}
+
+	releases, err := b.Releases(ctx, "v*")
+	testutil.Check(t, err)
+	expected := []Release{
+		{Remote: githubCLICLIRemote.Name, Tag: "v1.0", Commit: old, Date: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), SignedTag: true, Signature: SignatureGood, Signer: fingerprint},
+		{Remote: githubGitGitRemote.Name, Tag: "v2.0", Commit: signedCommit, Date: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), Signature: SignatureGood, Signer: fingerprint},
+		{Remote: barRemote.Name, Tag: "v0.2", Commit: recent, Date: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), Signature: SignatureMissing},
+	}
+	if !reflect.DeepEqual(releases, expected) {
+		t.Errorf("unexpected releases:\nwanted %+v\nwas    %+v", expected, releases)
+	}
+
+	// only the remotes of the given owners are listed, considering every
+	// tag without a pattern
+	releases, err = b.Releases(ctx, "", Owner{host: "github.com", name: "orirawlings"})
+	testutil.Check(t, err)
+	expected = []Release{
+		{Remote: barRemote.Name, Tag: "v0.2", Commit: recent, Date: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), Signature: SignatureMissing},
+		{Remote: headlessRemote.Name, Tag: "test", Commit: recent, Date: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), Signature: SignatureMissing},
+	}
+	if !reflect.DeepEqual(releases, expected) {
+		t.Errorf("unexpected releases of owner:\nwanted %+v\nwas    %+v", expected, releases)
+	}
+
+	if _, err := b.Releases(ctx, "["); err == nil {
+		t.Error("expected error for an invalid pattern")
+	}
+}
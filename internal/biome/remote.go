@@ -3,6 +3,9 @@ package biome
 import (
 	"fmt"
 	"os/exec"
+	"path"
+	"strings"
+	"time"
 )
 
 // Remote represents a git Remote in the biome configuration. Typically the
@@ -31,27 +34,84 @@ type Remote struct {
 	// fetched.
 	// https://docs.github.com/en/migrations/overview/about-locked-repositories
 	Locked bool
+
+	// Internal indicates that the remote repository has internal visibility
+	// in GitHub, visible to all members of the owning enterprise but neither
+	// public nor private.
+	// https://docs.github.com/en/repositories/creating-and-managing-repositories/about-repositories#about-internal-repositories
+	Internal bool
+
+	// Evicted indicates that the remote's references were exported to a git
+	// bundle and removed from the biome, see [Biome.Evict].
+	Evicted bool
+
+	// Upstream is the name of the remote that the remote repository was
+	// forked from in GitHub, if it is a fork. The upstream remote is not
+	// necessarily in the biome.
+	Upstream string
+
+	// refTemplate is the biome's template for the reference namespace of
+	// each remote, see [expandRefTemplate]. If empty, [defaultRefTemplate] is
+	// used.
+	refTemplate string
+
+	// scheme is the URL scheme that the remote is fetched with, ex. ssh, as
+	// listed by the [Provider] of its host. If empty, https is used.
+	scheme string
+}
+
+// defaultRefTemplate is the default destination pattern for references fetched
+// from each remote.
+const defaultRefTemplate = "refs/remotes/<name>/*"
+
+// expandRefTemplate expands the placeholders in a reference namespace
+// template for the named remote. `<name>` expands to the remote name, while
+// `<host>`, `<owner>`, and `<repo>` expand to its parts.
+func expandRefTemplate(template, name string) string {
+	if template == "" {
+		template = defaultRefTemplate
+	}
+	var host, owner, repo string
+	if parts := strings.SplitN(name, "/", 3); len(parts) == 3 {
+		host, owner, repo = parts[0], parts[1], parts[2]
+	}
+	return strings.NewReplacer(
+		"<name>", name,
+		"<host>", host,
+		"<owner>", owner,
+		"<repo>", repo,
+	).Replace(template)
 }
 
 func (r Remote) String() string {
 	return r.Name
 }
 
+// WebURL of the remote repository's home page on its GitHub server.
+func (r Remote) WebURL() string {
+	return fmt.Sprintf("https://%s", r.Name)
+}
+
 // FetchURL to retrieve references and objects from.
 func (r Remote) FetchURL() string {
-	return fmt.Sprintf("https://%s.git", r.Name)
+	scheme := r.scheme
+	if scheme == "" {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s.git", scheme, r.Name)
 }
 
 // FetchRefspec returns the refspec that should be used when fetching
 // references from the remote. The refspec will sync all references under
-// `refs/*` from the remote repo to `refs/remotes/<remote name>/*` in the
-// local repo. The destination part of the refspec is checked with
-// `git check-ref-format --refspec-pattern` to ensure it is valid.
+// `refs/*` from the remote repo to the remote's reference namespace in the
+// local repo, `refs/remotes/<remote name>/*` by default. The destination part
+// of the refspec is checked with `git check-ref-format --refspec-pattern` to
+// ensure it is valid.
 //
 // See https://git-scm.com/docs/git-check-ref-format
 func (r Remote) FetchRefspec() (string, error) {
 	src := "refs/*"
-	dst := fmt.Sprintf("refs/remotes/%s/*", r.Name)
+	dst := r.RefNamespace() + "*"
 	c := exec.Command("git", "check-ref-format", "--refspec-pattern", dst)
 	if err := c.Run(); err != nil {
 		// TODO (orirawlings): Instead of failing here, ideally we could
@@ -74,13 +134,43 @@ func (r Remote) Supported() bool {
 	return err == nil
 }
 
+// matchesAny reports whether the remote's repository name, the last path
+// element of the remote name, matches any of the given glob patterns.
+func (r Remote) matchesAny(patterns []string) bool {
+	repo := path.Base(r.Name)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, repo); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// RefNamespace returns the reference prefix under which all references
+// fetched from the remote are stored, ex. `refs/remotes/<remote name>/`.
+func (r Remote) RefNamespace() string {
+	return strings.TrimSuffix(expandRefTemplate(r.refTemplate, r.Name), "*")
+}
+
+// refNamespaceOf returns the reference prefix of a fetch refspec's
+// destination, ex. `refs/remotes/<remote name>/` for
+// `+refs/*:refs/remotes/<remote name>/*`. If the refspec cannot be parsed,
+// false is returned.
+func refNamespaceOf(refspec string) (string, bool) {
+	_, dst, ok := strings.Cut(refspec, ":")
+	if !ok || !strings.HasSuffix(dst, "/*") {
+		return "", false
+	}
+	return strings.TrimSuffix(dst, "*"), true
+}
+
 // Head returns the HEAD reference for the remote repository. This is used to
 // determine the default branch of the remote repository. The HEAD reference
 // is a symbolic reference that points to the default branch of the remote
 // repository, such as `refs/remotes/<remote name>/heads/main` or
 // `refs/remotes/<remote name>/heads/master`.
 func (r Remote) Head() string {
-	return fmt.Sprintf("refs/remotes/%s/HEAD", r.Name)
+	return r.RefNamespace() + "HEAD"
 }
 
 // RemoteCategory represents the category of a remote repository in GitHub.
@@ -120,6 +210,12 @@ const (
 	// by this tool. Unsupported remotes are skipped during remote configuration
 	// setup, but are still recorded in the configuration for reference.
 	Unsupported RemoteCategory = "unsupported"
+
+	// Evicted indicates that the remote's references were exported to a git
+	// bundle and removed from the biome, to reclaim disk. Evicted remotes are
+	// not configured as git remotes until they are restored. Evicted remotes
+	// are not in any other category.
+	Evicted RemoteCategory = "evicted"
 )
 
 var (
@@ -130,6 +226,7 @@ var (
 		Disabled,
 		Locked,
 		Unsupported,
+		Evicted,
 	}
 
 	// FetchableRemoteCategories is a list of remote categories that are
@@ -143,4 +240,20 @@ var (
 type remoteConfig struct {
 	Remote Remote
 	Head   string
+
+	// CreatedAt is when the remote's repository was created, if it was
+	// requested from GitHub.
+	CreatedAt time.Time
+}
+
+// withRefTemplate returns the remote configuration using the given reference
+// namespace template, with the target of its HEAD reference moved into the
+// remote's new namespace.
+func (r remoteConfig) withRefTemplate(template string) remoteConfig {
+	moved := Remote{Name: r.Remote.Name, refTemplate: template}
+	if r.Head != "" {
+		r.Head = moved.RefNamespace() + strings.TrimPrefix(r.Head, r.Remote.RefNamespace())
+	}
+	r.Remote.refTemplate = template
+	return r
 }
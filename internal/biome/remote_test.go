@@ -44,7 +44,8 @@ var (
 	}
 
 	myGithubBizFoobarBazbizRemote = Remote{
-		Name: "my.github.biz/foobar/bazbiz",
+		Name:     "my.github.biz/foobar/bazbiz",
+		Internal: true,
 	}
 )
 
@@ -169,3 +170,108 @@ func TestRemote_Supported(t *testing.T) {
 		})
 	}
 }
+
+func TestRemote_RefNamespace(t *testing.T) {
+	for _, r := range []struct {
+		remote   Remote
+		expected string
+	}{
+		{
+			remote:   barRemote,
+			expected: "refs/remotes/github.com/orirawlings/bar/",
+		},
+		{
+			remote:   myGithubBizFoobarBazbizRemote,
+			expected: "refs/remotes/my.github.biz/foobar/bazbiz/",
+		},
+		{
+			remote:   Remote{Name: barRemote.Name, refTemplate: "refs/biome/<host>/<owner>/<repo>/*"},
+			expected: "refs/biome/github.com/orirawlings/bar/",
+		},
+		{
+			remote:   Remote{Name: barRemote.Name, refTemplate: "refs/mirrors/<name>/*"},
+			expected: "refs/mirrors/github.com/orirawlings/bar/",
+		},
+	} {
+		t.Run(r.remote.RefNamespace(), func(t *testing.T) {
+			if r.remote.RefNamespace() != r.expected {
+				t.Errorf("expected %q, got %q", r.expected, r.remote.RefNamespace())
+			}
+			if r.remote.Head() != r.expected+"HEAD" {
+				t.Errorf("expected %q, got %q", r.expected+"HEAD", r.remote.Head())
+			}
+		})
+	}
+}
+
+func TestRemote_matchesAny(t *testing.T) {
+	for _, run := range []struct {
+		remote   Remote
+		patterns []string
+		expected bool
+	}{
+		{
+			remote: barRemote,
+		},
+		{
+			remote:   barRemote,
+			patterns: []string{"bar"},
+			expected: true,
+		},
+		{
+			remote:   barRemote,
+			patterns: []string{"foo", "b*"},
+			expected: true,
+		},
+		{
+			remote:   barRemote,
+			patterns: []string{"orirawlings/*"},
+		},
+		{
+			remote:   dotPrefixRemote,
+			patterns: []string{".*"},
+			expected: true,
+		},
+	} {
+		t.Run(run.remote.Name, func(t *testing.T) {
+			if actual := run.remote.matchesAny(run.patterns); actual != run.expected {
+				t.Errorf("expected %v for patterns %v, was %v", run.expected, run.patterns, actual)
+			}
+		})
+	}
+}
+
+func TestRefNamespaceOf(t *testing.T) {
+	for _, run := range []struct {
+		refspec  string
+		expected string
+		invalid  bool
+	}{
+		{
+			refspec:  "+refs/*:refs/remotes/github.com/orirawlings/bar/*",
+			expected: "refs/remotes/github.com/orirawlings/bar/",
+		},
+		{
+			refspec:  "+refs/*:refs/biome/github.com/orirawlings/bar/*",
+			expected: "refs/biome/github.com/orirawlings/bar/",
+		},
+		{
+			refspec: "+refs/heads/main",
+			invalid: true,
+		},
+		{
+			refspec: "",
+			invalid: true,
+		},
+	} {
+		t.Run(run.refspec, func(t *testing.T) {
+			namespace, ok := refNamespaceOf(run.refspec)
+			if ok == run.invalid {
+				t.Fatalf("unexpected validity parsing %q: %v", run.refspec, ok)
+			}
+			if namespace != run.expected {
+				t.Errorf("expected %q, got %q", run.expected, namespace)
+			}
+		})
+	}
+}
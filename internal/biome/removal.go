@@ -0,0 +1,179 @@
+package biome
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+)
+
+// RemovalPlan describes the effects that removing owners from the biome would
+// have, without changing anything.
+type RemovalPlan struct {
+
+	// Remotes that would no longer be configured once the owners are removed,
+	// along with statistics about their references.
+	Remotes []RemoteRefs
+
+	// ReclaimableBytes estimates the on-disk size of objects that are only
+	// reachable from the references of the removed remotes. The space is
+	// reclaimed once the references are deleted and the objects are pruned.
+	ReclaimableBytes int64
+}
+
+// Refs returns the total number of references that would be deleted.
+func (p RemovalPlan) Refs() int {
+	var n int
+	for _, r := range p.Remotes {
+		n += r.Refs
+	}
+	return n
+}
+
+// RemoteRefs summarizes the references stored for a remote.
+type RemoteRefs struct {
+
+	// Remote whose references are summarized.
+	Remote Remote
+
+	// Namespace is the reference prefix that holds the remote's references.
+	Namespace string
+
+	// Refs is the number of references stored under Namespace.
+	Refs int
+}
+
+// PlanRemoveOwners determines which remotes and references would be removed
+// if the given owners and viewers were removed from the biome and remotes were
+// updated. Remotes that remain reachable through another owner or viewer are
+// kept.
+func (b *biome) PlanRemoveOwners(ctx context.Context, owners []Owner, viewers []Viewer) (RemovalPlan, error) {
+	var plan RemovalPlan
+
+	removedGroups := make(map[string]struct{})
+	for _, owner := range owners {
+		removedGroups[owner.RemoteGroup()] = struct{}{}
+	}
+	for _, viewer := range viewers {
+		removedGroups[viewer.RemoteGroup()] = struct{}{}
+	}
+
+	var removed []Remote
+	if err := b.readConfig(ctx, func(ctx context.Context, cfg *config.Config) error {
+		for _, name := range remotesOnlyIn(cfg, removedGroups) {
+			removed = append(removed, Remote{Name: name, refTemplate: configuredRefTemplate(cfg, name)})
+		}
+		return nil
+	}); err != nil {
+		return plan, fmt.Errorf("could not determine remotes to remove: %w", err)
+	}
+	if len(removed) == 0 {
+		return plan, nil
+	}
+	slices.SortFunc(removed, func(a, b Remote) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	counts, err := b.countRefs(ctx, removed)
+	if err != nil {
+		return plan, err
+	}
+	for _, r := range removed {
+		plan.Remotes = append(plan.Remotes, RemoteRefs{
+			Remote:    r,
+			Namespace: r.RefNamespace(),
+			Refs:      counts[r.Name],
+		})
+	}
+
+	if plan.Refs() > 0 {
+		plan.ReclaimableBytes, err = b.diskUsage(ctx, removed, true)
+		if err != nil {
+			return plan, err
+		}
+	}
+	return plan, nil
+}
+
+// remotesOnlyIn lists the names of the remotes that are only members of the
+// given git remote groups, so they would no longer be configured if the
+// groups were removed.
+func remotesOnlyIn(cfg *config.Config, groups map[string]struct{}) []string {
+	kept := make(map[string]struct{})
+	candidates := make(map[string]struct{})
+	for _, opt := range cfg.Section("remotes").Options {
+		if _, ok := groups[opt.Key]; ok {
+			candidates[opt.Value] = struct{}{}
+		} else {
+			kept[opt.Value] = struct{}{}
+		}
+	}
+	var names []string
+	for name := range candidates {
+		if _, ok := kept[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// countRefs counts the references stored for each of the remotes, by name.
+func (b *biome) countRefs(ctx context.Context, remotes []Remote) (map[string]int, error) {
+	counts := make(map[string]int)
+	var namespaces []string
+	for _, r := range remotes {
+		namespaces = append(namespaces, r.RefNamespace())
+	}
+	refs, err := b.listRefs(ctx, namespaces)
+	if err != nil {
+		return nil, err
+	}
+	for _, ref := range refs {
+		for _, r := range remotes {
+			if strings.HasPrefix(ref.Name, r.RefNamespace()) {
+				counts[r.Name]++
+				break
+			}
+		}
+	}
+	return counts, nil
+}
+
+// diskUsage estimates the on-disk size of objects that are reachable from the
+// references of the remotes. If exclusive, only objects that are reachable
+// from no other reference are counted. Reachability bitmaps are used when the
+// biome has them, which makes this much faster for large biomes.
+func (b *biome) diskUsage(ctx context.Context, remotes []Remote, exclusive bool) (int64, error) {
+	if len(remotes) == 0 {
+		return 0, nil
+	}
+	args := []string{"-C", b.path, "rev-list", "--objects", "--disk-usage", "--use-bitmap-index"}
+	for _, r := range remotes {
+		args = append(args, "--glob="+r.RefNamespace()+"*")
+	}
+	if exclusive {
+		args = append(args, "--not")
+		for _, r := range remotes {
+			args = append(args, "--exclude="+r.RefNamespace()+"*")
+		}
+		args = append(args, "--all")
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("could not %q: %w: %s", cmd, err, stderr.String())
+	}
+	size, err := strconv.ParseInt(string(bytes.TrimSpace(out)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse disk usage from %q: %w", cmd, err)
+	}
+	return size, nil
+}
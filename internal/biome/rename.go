@@ -0,0 +1,302 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+)
+
+// RenameOwner replaces an owner of the biome with another, typically after
+// the GitHub user or organization was renamed. The git remote configurations
+// of the owner's repositories are rewritten to use the new owner name, and
+// once they are saved, all references under each remote's ref namespace are
+// moved to the renamed remote's namespace in a single ref transaction, so
+// that nothing needs to be fetched again. With the files reference backend,
+// the reflogs of the references are moved along with them. With the reftable
+// backend, the moved references start with fresh reflogs. If the references
+// cannot be moved, the configuration is renamed back.
+func (b *biome) RenameOwner(ctx context.Context, from, to Owner) error {
+	if from == to {
+		return nil
+	}
+	if err := b.validateOwner(ctx, to); err != nil {
+		return fmt.Errorf("could not validate owner: %s: %w", to, err)
+	}
+
+	var moved map[string]string
+	err := b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		var err error
+		moved, err = renameOwnerConfig(cfg, from, to)
+		return err == nil, err
+	})
+	if err != nil {
+		return err
+	}
+	if err := b.moveRefs(ctx, moved); err != nil {
+		err = fmt.Errorf("could not move references for %s: %w", from, err)
+
+		// the references are still under the old namespaces, so the
+		// configuration must name the old owner again, or updating remotes
+		// would drop the references as orphans
+		restoreErr := b.editConfig(context.WithoutCancel(ctx), func(ctx context.Context, cfg *config.Config) (bool, error) {
+			_, err := renameOwnerConfig(cfg, to, from)
+			return err == nil, err
+		})
+		if restoreErr != nil {
+			restoreErr = fmt.Errorf("could not rename owner back to %s: %w", from, restoreErr)
+		}
+		return errors.Join(err, restoreErr)
+	}
+	return nil
+}
+
+// renameOwnerConfig renames the owner, its settings, and its remotes in the
+// configuration. It returns the ref namespaces of the renamed remotes, keyed
+// by their previous namespace.
+func renameOwnerConfig(cfg *config.Config, from, to Owner) (map[string]string, error) {
+	biomeSection := cfg.Section(section)
+	ownerRefs := biomeSection.OptionAll(ownersOpt)
+	if !slices.Contains(ownerRefs, from.String()) {
+		return nil, fmt.Errorf("owner was not added to the biome: %s", from)
+	}
+	if slices.Contains(ownerRefs, to.String()) {
+		return nil, fmt.Errorf("owner was already added to the biome: %s", to)
+	}
+
+	// rename the owner
+	biomeSection.RemoveOption(ownersOpt)
+	for _, ownerRef := range ownerRefs {
+		if ownerRef == from.String() {
+			ownerRef = to.String()
+		}
+		biomeSection.AddOption(ownersOpt, ownerRef)
+	}
+	for _, ss := range biomeSection.Subsections {
+		if ss.Name == ownerSubsectionPrefix+from.String() {
+			ss.Name = ownerSubsectionPrefix + to.String()
+		}
+	}
+
+	// rename the owner's remotes
+	moved := make(map[string]string)
+	for _, ss := range cfg.Section("remote").Subsections {
+		name, ok := renameOwnedRemote(ss.Name, from, to)
+		if !ok {
+			continue
+		}
+		// remotes in the attic stay in the attic
+		remoteTemplate := configuredRefTemplate(cfg, ss.Name)
+		// remotes keep the scheme they are fetched with
+		r := Remote{Name: name, refTemplate: remoteTemplate, scheme: remoteScheme(ss.Options.Get("url"))}
+		refspec, err := r.FetchRefspec()
+		if err != nil {
+			return nil, err
+		}
+		moved[Remote{Name: ss.Name, refTemplate: remoteTemplate}.RefNamespace()] = r.RefNamespace()
+		ss.Name = name
+		ss.SetOption("url", r.FetchURL())
+		ss.SetOption("fetch", refspec)
+	}
+
+	// rename the owner's remote group and its members, as well as
+	// members of any other remote groups
+	for _, opt := range cfg.Section("remotes").Options {
+		if opt.Key == from.RemoteGroup() {
+			opt.Key = to.RemoteGroup()
+		}
+		if name, ok := renameOwnedRemote(opt.Value, from, to); ok {
+			opt.Value = name
+		}
+	}
+
+	// rename the owner's remotes in remote metadata, and their settings
+	for _, opt := range biomeSection.Subsection(remotesSubsection).Options {
+		if name, ok := renameOwnedRemote(opt.Value, from, to); ok {
+			opt.Value = name
+		}
+	}
+	renameRemoteSettings(cfg, func(name string) (string, bool) {
+		return renameOwnedRemote(name, from, to)
+	})
+	return moved, nil
+}
+
+// renameRemoteSettings renames the subsection of per-remote settings, ex.
+// `biome.remote.github.com/cli/cli`, of each remote for which rename returns
+// a new name, so that the settings follow the remote. Settings are not
+// renamed onto a remote that already has settings of its own.
+func renameRemoteSettings(cfg *config.Config, rename func(name string) (string, bool)) {
+	biomeSection := cfg.Section(section)
+	for _, ss := range biomeSection.Subsections {
+		name, ok := strings.CutPrefix(ss.Name, remoteSubsectionPrefix)
+		if !ok {
+			continue
+		}
+		renamed, ok := rename(name)
+		if !ok || renamed == name || biomeSection.HasSubsection(remoteSubsectionPrefix+renamed) {
+			continue
+		}
+		ss.Name = remoteSubsectionPrefix + renamed
+	}
+}
+
+// renameOwnedRemote returns the name of the remote after its owner is renamed.
+// If the remote is not owned by the from owner, false is returned.
+func renameOwnedRemote(name string, from, to Owner) (string, bool) {
+	prefix := from.String() + "/"
+	if len(name) <= len(prefix) || !strings.EqualFold(name[:len(prefix)], prefix) {
+		return name, false
+	}
+	return to.String() + "/" + name[len(prefix):], true
+}
+
+// movingRefPrefix is the prefix of the temporary reference namespaces that
+// references are moved through when their names only change in letter case.
+const movingRefPrefix = "refs/biome/moving/"
+
+// moveRefs moves all references from each old reference namespace to the
+// corresponding new reference namespace. Symbolic references, like HEAD, are
+// retargeted into the new namespace as well. With the files reference
+// backend, the reflogs of the references are moved along with them, since git
+// deletes the reflog of a deleted reference.
+//
+// Namespaces whose names only change in letter case are moved in two steps,
+// through a temporary namespace, since on a case-insensitive filesystem the
+// files backend cannot create a loose reference in the same transaction that
+// deletes a reference of the same name in a different case. All other
+// namespaces are moved in a single ref transaction.
+func (b *biome) moveRefs(ctx context.Context, moved map[string]string) error {
+	first, second := stageCaseMoves(moved)
+	if err := b.moveRefsOnce(ctx, first); err != nil {
+		return err
+	}
+	if err := b.moveRefsOnce(ctx, second); err != nil {
+		return fmt.Errorf("could not move references out of %s: %w", movingRefPrefix, err)
+	}
+	return nil
+}
+
+// stageCaseMoves splits the moves of reference namespaces into two steps.
+// Namespaces whose names only change in letter case are moved to a temporary
+// namespace in the first step, and from there to their new namespace in the
+// second step. All other namespaces are moved in the first step.
+func stageCaseMoves(moved map[string]string) (map[string]string, map[string]string) {
+	first := make(map[string]string)
+	second := make(map[string]string)
+	for _, oldNamespace := range slices.Sorted(maps.Keys(moved)) {
+		newNamespace := moved[oldNamespace]
+		if oldNamespace == newNamespace || !strings.EqualFold(oldNamespace, newNamespace) {
+			first[oldNamespace] = newNamespace
+			continue
+		}
+		tmp := fmt.Sprintf("%s%d/", movingRefPrefix, len(second))
+		first[oldNamespace] = tmp
+		second[tmp] = newNamespace
+	}
+	return first, second
+}
+
+// moveRefsOnce moves all references from each old reference namespace to the
+// corresponding new reference namespace in a single ref transaction, along
+// with their reflogs, see [biome.moveRefs].
+func (b *biome) moveRefsOnce(ctx context.Context, moved map[string]string) error {
+	if len(moved) == 0 {
+		return nil
+	}
+
+	var namespaces []string
+	for oldNamespace := range moved {
+		namespaces = append(namespaces, oldNamespace)
+	}
+	rename := func(refname string) string {
+		for oldNamespace, newNamespace := range moved {
+			if rest, ok := strings.CutPrefix(refname, oldNamespace); ok {
+				return newNamespace + rest
+			}
+		}
+		return refname
+	}
+
+	refs, err := b.listRefs(ctx, namespaces)
+	if err != nil {
+		return err
+	}
+	logsDir, logs, err := b.readReflogs(ctx, refs, rename)
+	if err != nil {
+		return err
+	}
+
+	err = b.transactRefs(ctx, func(w io.Writer) error {
+		for _, r := range refs {
+			var err error
+			if r.Symref != "" {
+				_, err = fmt.Fprintf(w, "option no-deref\nsymref-create %s %s\noption no-deref\nsymref-delete %s\n", rename(r.Name), rename(r.Symref), r.Name)
+			} else {
+				_, err = fmt.Fprintf(w, "create %s %s\ndelete %s %s\n", rename(r.Name), r.ObjectName, r.Name, r.ObjectName)
+			}
+			if err != nil {
+				return fmt.Errorf("could not move %s: %w", r.Name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return writeReflogs(logsDir, logs)
+}
+
+// readReflogs reads the reflogs of the references with the files reference
+// backend, keyed by the name of each reference after it is renamed. With
+// other backends, no reflogs are read.
+func (b *biome) readReflogs(ctx context.Context, refs []storedRef, rename func(string) string) (string, map[string][]byte, error) {
+	format, err := b.RefFormat(ctx)
+	if err != nil || format != RefFormatFiles {
+		return "", nil, err
+	}
+	gitDir, err := b.gitDir(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	logsDir := filepath.Join(gitDir, "logs")
+	logs := make(map[string][]byte)
+	for _, r := range refs {
+		log, err := os.ReadFile(filepath.Join(logsDir, filepath.FromSlash(r.Name)))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("could not read reflog of %s: %w", r.Name, err)
+		}
+		logs[rename(r.Name)] = log
+	}
+	return logsDir, logs, nil
+}
+
+// writeReflogs restores the reflogs read with [biome.readReflogs] for the
+// renamed references, followed by any entries git wrote for the renamed
+// references since.
+func writeReflogs(logsDir string, logs map[string][]byte) error {
+	for name, log := range logs {
+		path := filepath.Join(logsDir, filepath.FromSlash(name))
+		current, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("could not read reflog of %s: %w", name, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			return fmt.Errorf("could not move reflog of %s: %w", name, err)
+		}
+		if err := os.WriteFile(path, append(log, current...), 0666); err != nil {
+			return fmt.Errorf("could not move reflog of %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package biome
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+)
+
+const (
+	// reflogRetentionKey is the git config key of the setting that controls
+	// how long reflog entries of the remotes' references are kept.
+	reflogRetentionKey = "biome.retention.reflog"
+
+	// journalRetentionKey is the git config key of the setting that controls
+	// how long entries of the biome's journal are kept.
+	journalRetentionKey = "biome.retention.journal"
+)
+
+// retentionPattern matches the git dates accepted by the retention settings.
+// Git interprets any date that it cannot parse as the current time, which
+// would expire everything, so only unambiguous dates are accepted.
+var retentionPattern = regexp.MustCompile(`^(never|all|[0-9]+\.(second|minute|hour|day|week|month|year)s?(\.ago)?|[0-9]{4}-[0-9]{2}-[0-9]{2})$`)
+
+func validateRetention(value string) error {
+	if value != "" && !retentionPattern.MatchString(value) {
+		return errors.New("must be a git date, ex. 90.days.ago, 2006-01-02, never, or all")
+	}
+	return nil
+}
+
+// reflogPatterns returns the reference patterns that hold the references of
+// the biome's remotes, given the biome's reference namespace template, ex.
+// refs/remotes/* for refs/remotes/<name>/*.
+func reflogPatterns(template string) []string {
+	if template == "" {
+		template = defaultRefTemplate
+	}
+	prefix, _, _ := strings.Cut(template, "<")
+	prefix = prefix[:strings.LastIndex(prefix, "/")+1]
+	return []string{prefix + "*", atticRefPrefix + "*"}
+}
+
+// applyReflogRetention configures git to expire the reflog entries of the
+// remotes' references according to the reflog retention setting, through
+// gc.<pattern>.reflogExpire and gc.<pattern>.reflogExpireUnreachable, so that
+// git gc and git maintenance enforce it. Without the setting, git's defaults
+// apply.
+func applyReflogRetention(cfg *config.Config) {
+	retention, _ := getConfigValue(cfg, reflogRetentionKey)
+	for _, pattern := range reflogPatterns(refTemplate(cfg)) {
+		for _, opt := range []string{"reflogExpire", "reflogExpireUnreachable"} {
+			key := "gc." + pattern + "." + opt
+			if retention == "" {
+				unsetConfigValue(cfg, key)
+			} else {
+				setConfigValue(cfg, key, retention)
+			}
+		}
+	}
+}
+
+// expireJournal removes the entries of the biome's journal that are older than
+// the journal retention setting. Without the setting, every entry is kept.
+// Entries recorded while the journal is rewritten may be lost.
+func (b *biome) expireJournal(ctx context.Context) error {
+	var retention string
+	if err := b.readConfig(ctx, func(ctx context.Context, cfg *config.Config) error {
+		retention, _ = getConfigValue(cfg, journalRetentionKey)
+		return nil
+	}); err != nil {
+		return err
+	}
+	if retention == "" {
+		return nil
+	}
+	cutoff, err := b.resolveDate(ctx, retention)
+	if err != nil {
+		return err
+	}
+	entries, err := b.Journal(ctx)
+	if err != nil || len(entries) == 0 || !entries[0].Time.Before(cutoff) {
+		return err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range entries {
+		if e.Time.Before(cutoff) {
+			continue
+		}
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	path, err := b.journalPath(ctx)
+	if err != nil {
+		return err
+	}
+	return writeAtomic(path, buf.Bytes())
+}
@@ -0,0 +1,103 @@
+package biome
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestValidateRetention(t *testing.T) {
+	for _, value := range []string{"", "90.days.ago", "1.week", "6.months.ago", "2006-01-02", "never", "all"} {
+		if err := validateRetention(value); err != nil {
+			t.Errorf("expected %q to be valid: %v", value, err)
+		}
+	}
+	for _, value := range []string{"now", "90 days", "90.fortnights.ago", "soon", "2006-01-02T15:04:05"} {
+		if err := validateRetention(value); err == nil {
+			t.Errorf("expected %q to be invalid", value)
+		}
+	}
+}
+
+func TestReflogPatterns(t *testing.T) {
+	for template, expected := range map[string][]string{
+		"":                                     {"refs/remotes/*", "refs/attic/*"},
+		"refs/remotes/<name>/*":                {"refs/remotes/*", "refs/attic/*"},
+		"refs/biome/<host>/<owner>/<repo>/*":   {"refs/biome/*", "refs/attic/*"},
+		"refs/mirrors/<host>-<owner>-<repo>/*": {"refs/mirrors/*", "refs/attic/*"},
+	} {
+		if patterns := reflogPatterns(template); !slices.Equal(patterns, expected) {
+			t.Errorf("unexpected patterns for %q: wanted %q, was %q", template, expected, patterns)
+		}
+	}
+}
+
+func TestApplyReflogRetention(t *testing.T) {
+	ctx := context.Background()
+	b := &biome{
+		path:          testutil.TempRepo(t),
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.SetSetting(ctx, reflogRetentionKey, "30.days.ago"))
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		applyReflogRetention(cfg)
+		return true, nil
+	}))
+	testutil.Check(t, b.readConfig(ctx, func(ctx context.Context, cfg *config.Config) error {
+		for _, key := range []string{
+			"gc.refs/remotes/*.reflogExpire",
+			"gc.refs/remotes/*.reflogExpireUnreachable",
+			"gc.refs/attic/*.reflogExpire",
+			"gc.refs/attic/*.reflogExpireUnreachable",
+		} {
+			if value, _ := getConfigValue(cfg, key); value != "30.days.ago" {
+				t.Errorf("expected %s to be 30.days.ago, was %q", key, value)
+			}
+		}
+		return nil
+	}))
+
+	// without the setting, git's defaults apply again
+	testutil.Check(t, b.UnsetSetting(ctx, reflogRetentionKey))
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		applyReflogRetention(cfg)
+		return true, nil
+	}))
+	testutil.Check(t, b.readConfig(ctx, func(ctx context.Context, cfg *config.Config) error {
+		if value, ok := getConfigValue(cfg, "gc.refs/remotes/*.reflogExpire"); ok {
+			t.Errorf("expected reflog expiry to be unset, was %q", value)
+		}
+		return nil
+	}))
+}
+
+func TestBiome_expireJournal(t *testing.T) {
+	ctx := context.Background()
+	b := &biome{
+		path:          testutil.TempRepo(t),
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	old := JournalEntry{Time: time.Now().Add(-60 * 24 * time.Hour), Op: FetchOp, Remote: barRemote.Name, Duration: time.Second}
+	recent := JournalEntry{Time: time.Now().Add(-time.Hour), Op: FetchOp, Remote: barRemote.Name, Duration: time.Minute}
+	testutil.Check(t, b.Record(ctx, old, recent))
+
+	// without the setting, every entry is kept
+	testutil.Check(t, b.expireJournal(ctx))
+	entries, err := b.Journal(ctx)
+	testutil.Check(t, err)
+	if len(entries) != 2 {
+		t.Errorf("expected every entry to be kept, was %v", entries)
+	}
+
+	testutil.Check(t, b.SetSetting(ctx, journalRetentionKey, "30.days.ago"))
+	testutil.Check(t, b.expireJournal(ctx))
+	entries, err = b.Journal(ctx)
+	testutil.Check(t, err)
+	if len(entries) != 1 || entries[0].Duration != recent.Duration {
+		t.Errorf("expected only the recent entry to be kept, was %v", entries)
+	}
+}
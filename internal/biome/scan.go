@@ -0,0 +1,345 @@
+package biome
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// scanKey is the git config key of the setting that scans newly
+	// fetched blobs for secrets after each fetch.
+	scanKey = "biome.fetch.scan"
+
+	// scanCommandKey is the git config key of the setting that holds an
+	// external secret scanner, run in place of the built-in rules.
+	scanCommandKey = "biome.scan.command"
+
+	// scanMaxBlobSize is the size of the largest blob scanned by the
+	// built-in rules. Larger blobs are rarely source code or configuration.
+	scanMaxBlobSize = 1 << 20
+)
+
+// ScanRule is a built-in rule that finds a kind of secret in the content of
+// blobs.
+type ScanRule struct {
+
+	// Name of the rule, ex. github-token.
+	Name string
+
+	// Pattern matches the secret.
+	Pattern *regexp.Regexp
+}
+
+// ScanRules are the built-in rules that scans find secrets with, unless an
+// external scanner is configured.
+var ScanRules = []ScanRule{
+	{Name: "aws-access-key-id", Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{Name: "github-token", Pattern: regexp.MustCompile(`\bgh[pousr]_[0-9A-Za-z]{36}\b`)},
+	{Name: "github-fine-grained-token", Pattern: regexp.MustCompile(`\bgithub_pat_[0-9A-Za-z_]{82}\b`)},
+	{Name: "google-api-key", Pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{Name: "private-key", Pattern: regexp.MustCompile(`-----BEGIN (?:[A-Z]+ )?PRIVATE KEY-----`)},
+	{Name: "slack-token", Pattern: regexp.MustCompile(`\bxox[abposr]-[0-9A-Za-z-]{10,}\b`)},
+}
+
+// Finding is a possible secret that a scan found in a blob of a remote.
+type Finding struct {
+
+	// Remote is the name of the remote whose references reach the blob.
+	Remote string
+
+	// Blob is the object ID of the blob.
+	Blob string
+
+	// Path is a path that the blob was found at.
+	Path string
+
+	// Line is the line number of the secret within the blob, or 0 if the
+	// external scanner did not report one.
+	Line int
+
+	// Rule describes the kind of secret, ex. the name of a built-in
+	// [ScanRule], or the description given by an external scanner.
+	Rule string
+
+	// Match is the secret, redacted so that findings can be shared without
+	// leaking it further. It is empty for findings of an external scanner.
+	Match string
+}
+
+// ScanBaseline returns the object IDs that the references of the biome's
+// fetchable remotes point to, before a fetch, wherever the reference
+// namespace template puts them. [biome.Scan] skips the blobs reachable from
+// the baseline, since they were already in the biome.
+func (b *biome) ScanBaseline(ctx context.Context) ([]string, error) {
+	namespaces, err := b.fetchableNamespaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := b.listRefs(ctx, slices.Collect(maps.Keys(namespaces)))
+	if err != nil {
+		return nil, err
+	}
+	var baseline []string
+	for _, r := range refs {
+		if r.Symref == "" {
+			baseline = append(baseline, r.ObjectName)
+		}
+	}
+	return baseline, nil
+}
+
+// Scan finds possible secrets in the blobs that the references of the
+// biome's fetchable remotes reach, but that the baseline does not, ex. the
+// blobs fetched since the baseline was taken with [biome.ScanBaseline].
+// Blobs are scanned by the external scanner of the biome.scan.command
+// setting, if any, or by the built-in [ScanRules] otherwise. Findings are
+// grouped by remote, in the order of the remotes.
+func (b *biome) Scan(ctx context.Context, baseline []string) ([]Finding, error) {
+	command, err := b.GetSetting(ctx, scanCommandKey)
+	if err != nil {
+		return nil, err
+	}
+	remotes, err := b.Remotes(ctx, FetchableRemoteCategories...)
+	if err != nil {
+		return nil, err
+	}
+	var namespaces []string
+	for _, r := range remotes {
+		namespaces = append(namespaces, r.RefNamespace())
+	}
+	refs, err := b.listRefs(ctx, namespaces)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool)
+	for _, id := range baseline {
+		known[id] = true
+	}
+
+	var findings []Finding
+	for _, r := range remotes {
+		var tips []string
+		for _, ref := range refs {
+			if ref.Symref == "" && !known[ref.ObjectName] && strings.HasPrefix(ref.Name, r.RefNamespace()) {
+				tips = append(tips, ref.ObjectName)
+			}
+		}
+		if len(tips) == 0 {
+			continue
+		}
+		blobs, err := b.newBlobs(ctx, tips, baseline, command.Value == "")
+		if err != nil {
+			return findings, fmt.Errorf("could not list new blobs of %s: %w", r.Name, err)
+		}
+		if len(blobs) == 0 {
+			continue
+		}
+		var found []Finding
+		if command.Value != "" {
+			found, err = b.scanCommand(ctx, command.Value, r.Name, blobs)
+		} else {
+			found, err = b.scanRules(ctx, r.Name, blobs)
+		}
+		if err != nil {
+			return findings, fmt.Errorf("could not scan %s: %w", r.Name, err)
+		}
+		findings = append(findings, found...)
+	}
+	return findings, nil
+}
+
+// scannedBlob is a blob to scan, and a path it was found at.
+type scannedBlob struct {
+	id   string
+	path string
+}
+
+// newBlobs lists the blobs reachable from the tips, but not from the
+// baseline. If limit is true, blobs larger than scanMaxBlobSize are not
+// listed.
+func (b *biome) newBlobs(ctx context.Context, tips, baseline []string, limit bool) ([]scannedBlob, error) {
+	var stdin strings.Builder
+	for _, id := range tips {
+		fmt.Fprintln(&stdin, id)
+	}
+	for _, id := range baseline {
+		fmt.Fprintln(&stdin, "^"+id)
+	}
+	args := []string{"rev-list", "--objects", "--filter=object:type=blob"}
+	if limit {
+		args = append(args, fmt.Sprintf("--filter=blob:limit=%d", scanMaxBlobSize))
+	}
+	out, err := b.gitStdin(ctx, strings.NewReader(stdin.String()), append(args, "--stdin")...)
+	if err != nil {
+		return nil, err
+	}
+	var blobs []scannedBlob
+	for _, line := range strings.Split(out, "\n") {
+		// commits are listed too, but without a path
+		if id, path, ok := strings.Cut(line, " "); ok {
+			blobs = append(blobs, scannedBlob{id: id, path: path})
+		}
+	}
+	return blobs, nil
+}
+
+// scanRules finds secrets in the blobs with the built-in rules. Binary
+// blobs, which contain a NUL byte, are skipped.
+func (b *biome) scanRules(ctx context.Context, remote string, blobs []scannedBlob) ([]Finding, error) {
+	var ids []string
+	for _, blob := range blobs {
+		ids = append(ids, blob.id)
+	}
+	var findings []Finding
+	err := b.readBlobs(ctx, ids, func(i int, content []byte) error {
+		if bytes.IndexByte(content, 0) >= 0 {
+			return nil
+		}
+		for n, line := range bytes.Split(content, []byte("\n")) {
+			for _, rule := range ScanRules {
+				for _, match := range rule.Pattern.FindAll(line, -1) {
+					findings = append(findings, Finding{
+						Remote: remote,
+						Blob:   blobs[i].id,
+						Path:   blobs[i].path,
+						Line:   n + 1,
+						Rule:   rule.Name,
+						Match:  redact(string(match)),
+					})
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return findings, nil
+}
+
+// readBlobs reads the content of each of the blobs with git cat-file
+// --batch, calling fn with the index and content of each blob in turn.
+func (b *biome) readBlobs(ctx context.Context, ids []string, fn func(i int, content []byte) error) error {
+	var stdin strings.Builder
+	for _, id := range ids {
+		fmt.Fprintln(&stdin, id)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "-C", b.path, "cat-file", "--batch")
+	cmd.Stdin = strings.NewReader(stdin.String())
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("could not %q: %w", cmd, err)
+	}
+
+	r := bufio.NewReader(stdout)
+	for i, id := range ids {
+		content, err := readBatchObject(r)
+		if err == nil {
+			err = fn(i, content)
+		} else {
+			err = fmt.Errorf("could not read blob %s: %w", id, err)
+		}
+		if err != nil {
+			// stop git, rather than waiting for it to write the rest
+			cancel()
+			return errors.Join(err, cmd.Wait())
+		}
+	}
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("could not %q: %w: %s", cmd, err, stderr.String())
+	}
+	return nil
+}
+
+// readBatchObject reads the content of the next object from the output of
+// git cat-file --batch.
+func readBatchObject(r *bufio.Reader) ([]byte, error) {
+	header, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	// <object> SP <type> SP <size> LF <content> LF
+	fields := strings.Fields(header)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("unexpected object header: %q", header)
+	}
+	size, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("unexpected object header: %q", header)
+	}
+	content := make([]byte, size+1)
+	if _, err := io.ReadFull(r, content); err != nil {
+		return nil, err
+	}
+	return content[:size], nil
+}
+
+// scanCommand finds secrets in the blobs with an external scanner. The
+// scanner is run by the shell, from the biome's directory, once for each
+// remote, with BIOME_REMOTE naming the remote. Each blob is written to its
+// standard input as a line of the form `<blob> <path>`, and the scanner can
+// read the blobs' contents with git cat-file. The scanner reports each
+// finding as a line of the form `<blob> <description>` on its standard
+// output. A non-zero exit fails the scan.
+func (b *biome) scanCommand(ctx context.Context, command, remote string, blobs []scannedBlob) ([]Finding, error) {
+	var stdin strings.Builder
+	paths := make(map[string]string)
+	for _, blob := range blobs {
+		fmt.Fprintf(&stdin, "%s %s\n", blob.id, blob.path)
+		paths[blob.id] = blob.path
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = b.path
+	cmd.Env = append(os.Environ(),
+		"BIOME_PATH="+b.path,
+		"BIOME_REMOTE="+remote,
+	)
+	cmd.Stdin = strings.NewReader(stdin.String())
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("could not %q: %w: %s", cmd, err, strings.TrimSpace(stderr.String()))
+	}
+	var findings []Finding
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		blob, rule, _ := strings.Cut(line, " ")
+		findings = append(findings, Finding{
+			Remote: remote,
+			Blob:   blob,
+			Path:   paths[blob],
+			Rule:   strings.TrimSpace(rule),
+		})
+	}
+	return findings, nil
+}
+
+// redact hides all but the first four characters of a secret.
+func redact(secret string) string {
+	const shown = 4
+	if len(secret) <= shown {
+		return strings.Repeat("*", len(secret))
+	}
+	return secret[:shown] + strings.Repeat("*", len(secret)-shown)
+}
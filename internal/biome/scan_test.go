@@ -0,0 +1,112 @@
+package biome
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_Scan(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		for _, r := range []Remote{barRemote, headlessRemote} {
+			cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, r.Name)
+		}
+		return true, nil
+	}))
+
+	// a blob with a secret on its second line, and a binary blob with one
+	hashObject := func(content string) string {
+		file := filepath.Join(t.TempDir(), "file")
+		testutil.Check(t, os.WriteFile(file, []byte(content), 0o644))
+		return strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "hash-object", "-w", file))
+	}
+	token := "ghp_" + strings.Repeat("a1B2", 9)
+	secret := hashObject("[auth]\ntoken = " + token + "\n")
+	binary := hashObject("\x00" + token)
+	tree := mktree(t, path, fmt.Sprintf("100644 blob %s\tconfig.ini\n100644 blob %s\tdata.bin\n", secret, binary))
+	commit := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "-c", "user.name=A", "-c", "user.email=a@example.com", "commit-tree", "-m", "secret", tree))
+	baseline, err := b.ScanBaseline(ctx)
+	testutil.Check(t, err)
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/remotes/github.com/orirawlings/bar/heads/main", commit)
+
+	findings, err := b.Scan(ctx, baseline)
+	testutil.Check(t, err)
+	expected := []Finding{
+		{
+			Remote: barRemote.Name,
+			Blob:   secret,
+			Path:   "config.ini",
+			Line:   2,
+			Rule:   "github-token",
+			Match:  "ghp_" + strings.Repeat("*", len(token)-4),
+		},
+	}
+	if !reflect.DeepEqual(findings, expected) {
+		t.Errorf("unexpected findings: wanted %+v, was %+v", expected, findings)
+	}
+
+	// blobs reachable from the baseline were already scanned
+	baseline, err = b.ScanBaseline(ctx)
+	testutil.Check(t, err)
+	findings, err = b.Scan(ctx, baseline)
+	testutil.Check(t, err)
+	if len(findings) != 0 {
+		t.Errorf("expected no findings for blobs in the baseline, was %+v", findings)
+	}
+
+	// the baseline covers the namespaces of the reference namespace template
+	testutil.Check(t, b.SetSetting(ctx, refspecTemplateKey, "refs/biome/<host>/<owner>/<repo>/*"))
+	other := hashObject("token = " + token + "\n")
+	tree = mktree(t, path, fmt.Sprintf("100644 blob %s\tother.ini\n", other))
+	commit = strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "-c", "user.name=A", "-c", "user.email=a@example.com", "commit-tree", "-m", "other", tree))
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/biome/github.com/orirawlings/bar/heads/main", commit)
+	baseline, err = b.ScanBaseline(ctx)
+	testutil.Check(t, err)
+	findings, err = b.Scan(ctx, baseline)
+	testutil.Check(t, err)
+	if len(findings) != 0 {
+		t.Errorf("expected no findings for blobs in the baseline of the template's namespaces, was %+v", findings)
+	}
+	testutil.Check(t, b.UnsetSetting(ctx, refspecTemplateKey))
+
+	testutil.Check(t, b.SetSetting(ctx, scanCommandKey, `while read blob path; do echo "$blob custom rule for $BIOME_REMOTE"; done`))
+	findings, err = b.Scan(ctx, nil)
+	testutil.Check(t, err)
+	expected = []Finding{
+		{Remote: barRemote.Name, Blob: secret, Path: "config.ini", Rule: "custom rule for " + barRemote.Name},
+		{Remote: barRemote.Name, Blob: binary, Path: "data.bin", Rule: "custom rule for " + barRemote.Name},
+	}
+	if !reflect.DeepEqual(findings, expected) {
+		t.Errorf("unexpected findings of external scanner: wanted %+v, was %+v", expected, findings)
+	}
+
+	testutil.Check(t, b.SetSetting(ctx, scanCommandKey, "exit 1"))
+	if _, err := b.Scan(ctx, nil); err == nil {
+		t.Error("expected error from failed external scanner")
+	}
+}
+
+func TestRedact(t *testing.T) {
+	for secret, expected := range map[string]string{
+		"":           "",
+		"abc":        "***",
+		"AKIA123456": "AKIA******",
+	} {
+		if actual := redact(secret); actual != expected {
+			t.Errorf("redact(%q): expected %q, was %q", secret, expected, actual)
+		}
+	}
+}
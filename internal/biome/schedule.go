@@ -0,0 +1,62 @@
+package biome
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// fetchFrequencyOpt is a per-owner setting option for how often scheduled
+// fetches fetch the owner's remotes, see [ParseFetchFrequency].
+const fetchFrequencyOpt = "fetchFrequency"
+
+// fetchFrequencies are the named fetch frequencies accepted by
+// [ParseFetchFrequency], in addition to durations.
+var fetchFrequencies = map[string]time.Duration{
+	"hourly": time.Hour,
+	"daily":  24 * time.Hour,
+	"weekly": 7 * 24 * time.Hour,
+}
+
+// ParseFetchFrequency parses the value of an owner's fetchFrequency setting,
+// either a duration, ex. 15m, or one of hourly, daily, or weekly. An empty
+// value parses as 0, meaning the owner has no frequency.
+func ParseFetchFrequency(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if d, ok := fetchFrequencies[value]; ok {
+		return d, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("must be a duration, or one of %s: %w", strings.Join(slices.Sorted(maps.Keys(fetchFrequencies)), ", "), err)
+	}
+	if d <= 0 {
+		return 0, errors.New("must be positive")
+	}
+	return d, nil
+}
+
+// OwnerFetchTimes returns when the remotes of each owner, keyed by the
+// owner's name, ex. github.com/cli, were last fetched, according to the fetch
+// entries of the journal. Failed fetches count too, so that an owner with a
+// broken remote is retried at its frequency, rather than by every scheduled
+// fetch.
+func OwnerFetchTimes(entries []JournalEntry) map[string]time.Time {
+	times := make(map[string]time.Time)
+	for _, e := range entries {
+		if e.Op != FetchOp || e.Remote == "" {
+			continue
+		}
+		owner := path.Dir(e.Remote)
+		if e.Time.After(times[owner]) {
+			times[owner] = e.Time
+		}
+	}
+	return times
+}
@@ -0,0 +1,46 @@
+package biome
+
+import (
+	"maps"
+	"testing"
+	"time"
+)
+
+func TestParseFetchFrequency(t *testing.T) {
+	for value, expected := range map[string]time.Duration{
+		"":       0,
+		"15m":    15 * time.Minute,
+		"hourly": time.Hour,
+		"daily":  24 * time.Hour,
+		"weekly": 7 * 24 * time.Hour,
+	} {
+		d, err := ParseFetchFrequency(value)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", value, err)
+		} else if d != expected {
+			t.Errorf("unexpected frequency for %q: wanted %s, was %s", value, expected, d)
+		}
+	}
+	for _, value := range []string{"0s", "-1h", "monthly"} {
+		if _, err := ParseFetchFrequency(value); err == nil {
+			t.Errorf("expected error for %q", value)
+		}
+	}
+}
+
+func TestOwnerFetchTimes(t *testing.T) {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	entries := []JournalEntry{
+		{Time: start.Add(time.Hour), Op: FetchOp, Remote: "github.com/cli/cli"},
+		{Time: start, Op: FetchOp, Remote: "github.com/cli/go-gh"},
+		{Time: start.Add(2 * time.Hour), Op: FetchOp, Remote: "github.com/orirawlings/bar", Failed: true},
+		{Time: start.Add(3 * time.Hour), Op: "other", Remote: "github.com/git/git"},
+	}
+	expected := map[string]time.Time{
+		"github.com/cli":         start.Add(time.Hour),
+		"github.com/orirawlings": start.Add(2 * time.Hour),
+	}
+	if times := OwnerFetchTimes(entries); !maps.Equal(times, expected) {
+		t.Errorf("unexpected fetch times: wanted %v, was %v", expected, times)
+	}
+}
@@ -0,0 +1,66 @@
+package biome
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// OwnerMatch is a GitHub user or organization found by [SearchOwners].
+type OwnerMatch struct {
+
+	// Login of the user or organization.
+	Login string
+
+	// Organization is true if the owner is an organization rather than a
+	// user.
+	Organization bool
+}
+
+// SearchOwners searches the GitHub host for users and organizations whose
+// logins begin with the given prefix, ex. to complete owner arguments on the
+// command line. At most limit matches are returned, best matches first. If
+// gh is not logged in to the host, the search is anonymous.
+func SearchOwners(ctx context.Context, host, prefix string, limit int) ([]OwnerMatch, error) {
+	var client *api.RESTClient
+	var err error
+	if Anonymous(host) {
+		client, err = anonymousRESTClient(host)
+	} else {
+		client, err = api.NewRESTClient(api.ClientOptions{
+			Host: host,
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not create API client: %s: %w", host, err)
+	}
+	var result struct {
+		Items []struct {
+			Login string `json:"login"`
+			Type  string `json:"type"`
+		} `json:"items"`
+	}
+	query := url.Values{
+		"q":        []string{prefix + " in:login"},
+		"per_page": []string{strconv.Itoa(limit)},
+	}
+	if err := client.DoWithContext(ctx, "GET", "search/users?"+query.Encode(), nil, &result); err != nil {
+		return nil, fmt.Errorf("could not search owners: %s: %w", host, err)
+	}
+
+	// the search matches logins that contain the prefix anywhere
+	var matches []OwnerMatch
+	for _, item := range result.Items {
+		if strings.HasPrefix(strings.ToLower(item.Login), strings.ToLower(prefix)) {
+			matches = append(matches, OwnerMatch{
+				Login:        item.Login,
+				Organization: item.Type == "Organization",
+			})
+		}
+	}
+	return matches, nil
+}
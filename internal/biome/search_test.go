@@ -0,0 +1,51 @@
+package biome
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestSearchOwners(t *testing.T) {
+	ctx := context.Background()
+	stubGitHub(t)
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_ENTERPRISE_TOKEN", "")
+	t.Setenv("GITHUB_ENTERPRISE_TOKEN", "")
+
+	gock.New("https://api.github.com").
+		Get("/search/users").
+		MatchParam("q", "^oriraw in:login$").
+		MatchParam("per_page", "^5$").
+		Reply(200).
+		JSON(`{"items":[
+			{"login":"orirawlings","type":"User"},
+			{"login":"not-orirawlings","type":"User"},
+			{"login":"OriRawlingsOrg","type":"Organization"}
+		]}`)
+	gock.New("https://my.github.biz").
+		Get("/api/v3/search/users").
+		MatchParam("q", "^foo in:login$").
+		Reply(200).
+		JSON(`{"items":[{"login":"foobar","type":"Organization"}]}`)
+
+	matches, err := SearchOwners(ctx, "github.com", "oriraw", 5)
+	testutil.Check(t, err)
+	expected := []OwnerMatch{
+		{Login: "orirawlings"},
+		{Login: "OriRawlingsOrg", Organization: true},
+	}
+	if !slices.Equal(matches, expected) {
+		t.Errorf("unexpected matches: wanted %v, was %v", expected, matches)
+	}
+
+	matches, err = SearchOwners(ctx, "my.github.biz", "foo", 5)
+	testutil.Check(t, err)
+	if expected := []OwnerMatch{{Login: "foobar", Organization: true}}; !slices.Equal(matches, expected) {
+		t.Errorf("unexpected matches: wanted %v, was %v", expected, matches)
+	}
+}
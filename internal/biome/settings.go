@@ -0,0 +1,761 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+)
+
+var (
+	// errUnknownSetting indicates that a setting key is not recognized by
+	// the biome.
+	errUnknownSetting = errors.New("unknown biome setting")
+)
+
+// Setting is a biome-scoped configuration setting, stored in the biome's git
+// config. Settings are validated before they are stored, so that the biome's
+// configuration schema cannot be broken by typos or invalid values.
+type Setting struct {
+
+	// Key is the git config key that stores the setting, ex. `fetch.parallel`.
+	Key string
+
+	// Description explains what the setting controls.
+	Description string
+
+	// Default is the effective value of the setting when it is not set.
+	Default string
+
+	// validate ensures a value is acceptable for the setting.
+	validate func(string) error
+}
+
+// Validate ensures that the given value is acceptable for the setting.
+func (s Setting) Validate(value string) error {
+	if s.validate == nil {
+		return nil
+	}
+	if err := s.validate(value); err != nil {
+		return fmt.Errorf("invalid value for %s: %q: %w", s.Key, value, err)
+	}
+	return nil
+}
+
+// SettingValue is the effective value of a setting in a biome.
+type SettingValue struct {
+	Setting
+
+	// Value is the effective value of the setting.
+	Value string
+
+	// IsDefault indicates that the setting is not set in the biome, so Value
+	// is the setting's default.
+	IsDefault bool
+}
+
+// settings lists all settings that can be read and written on a biome.
+var settings = []Setting{
+	{
+		Key:         refspecTemplateKey,
+		Description: "Destination pattern for the references fetched from each remote. <name> expands to the remote name, while <host>, <owner>, and <repo> expand to its parts. Existing references are moved on the next fetch.",
+		Default:     defaultRefTemplate,
+		validate:    validateRefTemplate,
+	},
+	{
+		Key:         atticKey,
+		Description: "Whether the references of archived and locked remotes are kept under refs/attic/<name>/, out of the references of the other remotes, rather than the refspecTemplate's destination. Objects are kept. References are moved on the next fetch.",
+		Default:     "false",
+		validate:    validateBool,
+	},
+	{
+		Key:         synthesizeHeadsKey,
+		Description: "Whether a HEAD reference is synthesized for remotes whose repositories have no default branch, pointing at their main or master branch, or their only branch. Synthesized HEAD references are evaluated again each time remotes are updated or fetched.",
+		Default:     "false",
+		validate:    validateBool,
+	},
+	{
+		Key:         apiBudgetKey,
+		Description: "Maximum number of GitHub API requests made to discover remotes each time they are updated, ex. by fetch. Owners and viewers that are not discovered within the budget keep their remotes, and are discovered first by the next update. A value of 0 means no limit.",
+		Default:     "0",
+		validate:    validateNonNegativeInt,
+	},
+	{
+		Key:         apiFieldsKey,
+		Description: "Comma separated list of optional repository fields requested from GitHub when discovering remotes: parent (the upstreams of forks, used by drift) and visibility (whether repositories are internal). Requesting fewer fields makes discovery cheaper.",
+		Default:     defaultAPIFields,
+		validate:    validateAPIFields,
+	},
+	{
+		Key:         credentialsKey,
+		Description: "Where git finds credentials to fetch remotes: gh (the tokens stored by gh, through a credential helper provided by biome) or git (the user's own git credential configuration).",
+		Default:     string(CredentialsGH),
+		validate:    validateOneOf(credentialSources...),
+	},
+	{
+		Key:         reflogRetentionKey,
+		Description: "How long reflog entries of the remotes' references are kept, as a git date, ex. 90.days.ago, never, or all. Set as gc.<pattern>.reflogExpire and gc.<pattern>.reflogExpireUnreachable for the remotes' reference namespaces the next time remotes are updated, so git gc, git maintenance, and prune-objects enforce it. Empty uses git's gc.reflogExpire defaults.",
+		Default:     "",
+		validate:    validateRetention,
+	},
+	{
+		Key:         journalRetentionKey,
+		Description: "How long entries of the biome's journal, ex. the durations of past fetches, are kept, as a git date, ex. 90.days.ago. Older entries are removed by prune-objects. Empty keeps every entry.",
+		Default:     "",
+		validate:    validateRetention,
+	},
+	{
+		Key:         "fetch.parallel",
+		Description: "Maximum number of remotes fetched in parallel. A value of 0 will give some reasonable default.",
+		Default:     "1",
+		validate:    validateNonNegativeInt,
+	},
+	{
+		Key:         preRemoveHookKey,
+		Description: "Shell command run before owners or viewers are removed. The remotes that would be removed are written to its standard input as `remove <remote>` lines. A non-zero exit aborts the removal.",
+		Default:     "",
+	},
+	{
+		Key:         preUpdateHookKey,
+		Description: "Shell command run before remote configurations are updated, ex. by fetch. The remotes that would be added or removed are written to its standard input as `add <remote>` or `remove <remote>` lines. A non-zero exit aborts the update.",
+		Default:     "",
+	},
+	{
+		Key:         lfsPolicyKey,
+		Description: "How Git LFS objects are handled: skip (never download them, not even on checkout), pointers (fetch only the pointer files), or selected (also fetch the LFS objects of remotes selected with biome.remote.<remote>.lfs).",
+		Default:     string(LFSPointers),
+		validate:    validateOneOf(lfsPolicies...),
+	},
+	{
+		Key:         quarantineKey,
+		Description: "Whether fetches receive objects into a quarantine object directory, and only migrate them into the biome, and update references, once every received pack passes validation, so that corrupt or malicious packs never enter the biome.",
+		Default:     "false",
+		validate:    validateBool,
+	},
+	{
+		Key:         scanKey,
+		Description: "Whether the blobs received by each fetch are scanned for secrets, ex. access tokens and private keys, reporting possible secrets found in each remote.",
+		Default:     "false",
+		validate:    validateBool,
+	},
+	{
+		Key:         scanCommandKey,
+		Description: "Shell command run as the secret scanner in place of the built-in rules, once for each remote with new blobs, named by BIOME_REMOTE. The new blobs are written to its standard input as `<blob> <path>` lines. It reports each possible secret as a `<blob> <description>` line on its standard output. A non-zero exit fails the scan.",
+		Default:     "",
+	},
+	{
+		Key:         protectedRefsKey,
+		Description: "Comma separated glob patterns of references, relative to each remote's reference namespace, ex. heads/main. References matching any pattern that no longer exist upstream are kept when a fetch deletes them, ex. with fetch.prune, unless the fetch is run with --allow-protected-deletions.",
+		Default:     "",
+		validate:    validateGlobs,
+	},
+	{
+		Key:         fsckScheduleKey,
+		Description: "How often the biome's objects are checked for corruption with a connectivity-only git fsck, by fetches with --watch or --scheduled, and by 'biome fsck --scheduled': a duration, ex. 12h, or one of hourly, daily, or weekly. Empty disables scheduled checks.",
+		Default:     "",
+		validate:    validateFetchFrequency,
+	},
+	{
+		Key:         "fetch.prune",
+		Description: "Remove references for each remote that no longer exist on the remote when fetching.",
+		Default:     "false",
+		validate:    validateBool,
+	},
+	{
+		Key:         "http.version",
+		Description: "HTTP protocol version used to fetch remotes: HTTP/2, or HTTP/1.1, ex. for proxies that mishandle HTTP/2. Empty uses curl's default.",
+		Default:     "",
+		validate:    validateOneOf(httpVersions...),
+	},
+	{
+		Key:         "http.postBuffer",
+		Description: "Maximum size in bytes of the buffer used to send requests when fetching, with an optional k, m, or g suffix, ex. 512m. Larger requests, ex. negotiating the history of remotes with many references, are sent with chunked encoding, which some servers and proxies mishandle.",
+		Default:     "1m",
+		validate:    validateSize,
+	},
+	{
+		Key:         "http.lowSpeedLimit",
+		Description: "Transfer speed, in bytes per second, below which a fetch of a remote is aborted once it lasts longer than http.lowSpeedTime, so that stalled connections fail rather than hang. A value of 0 disables the check.",
+		Default:     "0",
+		validate:    validateNonNegativeInt,
+	},
+	{
+		Key:         "http.lowSpeedTime",
+		Description: "Number of seconds that a fetch of a remote may stay below http.lowSpeedLimit before it is aborted.",
+		Default:     "0",
+		validate:    validateNonNegativeInt,
+	},
+	{
+		Key:         "http.keepAliveIdle",
+		Description: "Number of seconds a connection is idle before TCP keep-alive probes are sent, so that connections dropped by flaky links or NAT gateways are noticed during long fetches. Empty uses the operating system's default. Ignored by older versions of git that do not support it.",
+		Default:     "",
+		validate:    validateNonNegativeInt,
+	},
+	{
+		Key:         "http.keepAliveInterval",
+		Description: "Number of seconds between TCP keep-alive probes. Empty uses the operating system's default. Ignored by older versions of git that do not support it.",
+		Default:     "",
+		validate:    validateNonNegativeInt,
+	},
+	{
+		Key:         "http.keepAliveCount",
+		Description: "Number of unanswered TCP keep-alive probes after which a connection is dropped. Empty uses the operating system's default. Ignored by older versions of git that do not support it.",
+		Default:     "",
+		validate:    validateNonNegativeInt,
+	},
+}
+
+// httpVersions lists the values of the http.version git config setting.
+var httpVersions = []string{"HTTP/1.1", "HTTP/2"}
+
+// ownerSettings lists all settings that can be read and written for each
+// owner of a biome. Each setting's Key is the git config option name within
+// the owner's subsection, see [OwnerSettingKey].
+var ownerSettings = []Setting{
+	{
+		Key:         includeOpt,
+		Description: "Comma separated glob patterns of repository names. If set, only the owner's repositories that match any pattern are added as remotes, ex. the repositories imported from a manifest.",
+		Default:     "",
+		validate:    validateGlobs,
+	},
+	{
+		Key:         "exclude",
+		Description: "Comma separated glob patterns of repository names. The owner's repositories that match any pattern are not added as remotes.",
+		Default:     "",
+		validate:    validateGlobs,
+	},
+	{
+		Key:         "tags",
+		Description: "Which tags are fetched from the owner's repositories into the standard refs/tags/ namespace: none, follow (tags pointing into fetched history), or all.",
+		Default:     "none",
+		validate:    validateOneOf(slices.Sorted(maps.Keys(tagOpts))...),
+	},
+	{
+		Key:         createdAfterOpt,
+		Description: "Date, ex. 2023-01-01. Only the owner's repositories created on or after the date are added as remotes.",
+		Default:     "",
+		validate:    validateDate,
+	},
+	{
+		Key:         "paused",
+		Description: "Whether the owner is paused. The remotes of a paused owner stay configured, but are not updated or fetched until the owner is resumed.",
+		Default:     "false",
+		validate:    validateBool,
+	},
+	{
+		Key:         fetchFrequencyOpt,
+		Description: "How often the owner's remotes are fetched by scheduled fetches, ex. 15m, hourly, daily, or weekly. Scheduled fetches skip the owner until the frequency has elapsed since its remotes were last fetched. Owners without a frequency are fetched by every scheduled fetch.",
+		Default:     "",
+		validate:    validateFetchFrequency,
+	},
+}
+
+// remoteSettings lists all settings that can be read and written for each
+// remote of a biome. Each setting's Key is the git config option name within
+// the remote's subsection, see [RemoteSettingKey]. Unlike the metadata that
+// is discovered about remotes, these settings are kept when remotes are
+// updated.
+var remoteSettings = []Setting{
+	{
+		Key:         "upstream",
+		Description: "Name of the remote that the remote's repository was forked from, ex. github.com/cli/cli. Overrides the upstream reported by GitHub.",
+		Default:     "",
+		validate:    validateRemoteName,
+	},
+	{
+		Key:         lfsOpt,
+		Description: "Fetch the Git LFS objects of the remote's default branch when the biome.lfs.policy setting is selected.",
+		Default:     "false",
+		validate:    validateBool,
+	},
+	{
+		Key:         noteOpt,
+		Description: "Freeform note about the remote, ex. the team that owns it, shown by 'remotes --long' and 'remotes --json'. Set it with 'annotate'.",
+		Default:     "",
+	},
+}
+
+// hostSettings lists all settings that can be read and written for each
+// GitHub host of a biome. Each setting's Key is the git config option name
+// within the host's subsection, see [HostSettingKey].
+var hostSettings = []Setting{
+	{
+		Key:         paceOpt,
+		Description: "Minimum time between discovering consecutive owners or viewers on the host, ex. 2s, so that discovering many owners stays under GitHub's secondary rate limits. A value of 0 disables pacing.",
+		Default:     "0s",
+		validate:    validateNonNegativeDuration,
+	},
+	{
+		Key:         maxConcurrentOpt,
+		Description: "Maximum number of the host's owners and viewers discovered concurrently, and of the host's remotes fetched concurrently across all git processes of a fetch, so that the host is not overwhelmed. A value of 0 discovers one owner or viewer at a time, and fetches without a limit beyond fetch.parallel and --jobs.",
+		Default:     "0",
+		validate:    validateNonNegativeInt,
+	},
+}
+
+// refspecTemplateKey is the git config key of the setting that controls the
+// reference namespace of each remote.
+const refspecTemplateKey = "biome.refspecTemplate"
+
+// refTemplate returns the reference namespace template from a loaded config,
+// or an empty string if the default template is in effect.
+func refTemplate(cfg *config.Config) string {
+	template, _ := getConfigValue(cfg, refspecTemplateKey)
+	return template
+}
+
+// tagOpts maps values of the per-owner tags setting to the remote.<name>.tagOpt
+// git config option. An empty tagOpt leaves git's default tag following in
+// effect.
+var tagOpts = map[string]string{
+	"none":   "--no-tags",
+	"follow": "",
+	"all":    "--tags",
+}
+
+// OwnerSettingKey returns the git config key that stores the given per-owner
+// setting option for the owner, ex. `biome.owner.github.com/cli.exclude`.
+func OwnerSettingKey(owner Owner, option string) string {
+	return strings.Join([]string{section, ownerSubsectionPrefix + owner.String(), option}, ".")
+}
+
+// RemoteSettingKey returns the git config key that stores the given
+// per-remote setting option for the named remote, ex.
+// `biome.remote.github.com/cli/cli.upstream`.
+func RemoteSettingKey(remote, option string) string {
+	return strings.Join([]string{section, remoteSubsectionPrefix + remote, option}, ".")
+}
+
+// HostSettingKey returns the git config key that stores the given per-host
+// setting option for the GitHub host, ex. `biome.host.github.com.pace`.
+func HostSettingKey(host, option string) string {
+	return strings.Join([]string{section, hostSubsectionPrefix + host, option}, ".")
+}
+
+// HostSettings lists all settings that can be read and written for each
+// GitHub host of a biome, sorted by key. Keys use `<host>` as a placeholder
+// for the host name.
+func HostSettings() []Setting {
+	var result []Setting
+	for _, s := range hostSettings {
+		s.Key = HostSettingKey("<host>", s.Key)
+		result = append(result, s)
+	}
+	slices.SortFunc(result, func(a, b Setting) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return result
+}
+
+// RemoteSettings lists all settings that can be read and written for each
+// remote of a biome, sorted by key. Keys use `<remote>` as a placeholder for
+// the remote name.
+func RemoteSettings() []Setting {
+	var result []Setting
+	for _, s := range remoteSettings {
+		s.Key = RemoteSettingKey("<remote>", s.Key)
+		result = append(result, s)
+	}
+	slices.SortFunc(result, func(a, b Setting) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return result
+}
+
+// OwnerSettings lists all settings that can be read and written for each
+// owner of a biome, sorted by key. Keys use `<owner>` as a placeholder for the
+// owner reference.
+func OwnerSettings() []Setting {
+	var result []Setting
+	for _, s := range ownerSettings {
+		s.Key = strings.Join([]string{section, ownerSubsectionPrefix + "<owner>", s.Key}, ".")
+		result = append(result, s)
+	}
+	slices.SortFunc(result, func(a, b Setting) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return result
+}
+
+// Settings lists all settings that can be read and written on a biome,
+// sorted by key.
+func Settings() []Setting {
+	result := slices.Clone(settings)
+	slices.SortFunc(result, func(a, b Setting) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return result
+}
+
+// LookupSetting finds the setting for the given git config key. Per-owner and
+// per-remote settings are found by their full key, ex.
+// `biome.owner.github.com/cli.exclude`.
+func LookupSetting(key string) (Setting, error) {
+	for _, s := range settings {
+		if strings.EqualFold(s.Key, key) {
+			return s, nil
+		}
+	}
+	sec, subsec, opt := splitConfigKey(key)
+	if ownerRef, ok := strings.CutPrefix(subsec, ownerSubsectionPrefix); ok && strings.EqualFold(sec, section) {
+		owner, err := ParseOwner(ownerRef)
+		if err != nil {
+			return Setting{}, err
+		}
+		for _, s := range ownerSettings {
+			if strings.EqualFold(s.Key, opt) {
+				s.Key = OwnerSettingKey(owner, s.Key)
+				return s, nil
+			}
+		}
+	}
+	if remote, ok := strings.CutPrefix(subsec, remoteSubsectionPrefix); ok && strings.EqualFold(sec, section) {
+		if err := validateRemoteName(remote); err != nil {
+			return Setting{}, fmt.Errorf("invalid remote name: %q: %w", remote, err)
+		}
+		for _, s := range remoteSettings {
+			if strings.EqualFold(s.Key, opt) {
+				s.Key = RemoteSettingKey(remote, s.Key)
+				return s, nil
+			}
+		}
+	}
+	if host, ok := strings.CutPrefix(subsec, hostSubsectionPrefix); ok && strings.EqualFold(sec, section) {
+		if host == "" || strings.Contains(host, "/") {
+			return Setting{}, fmt.Errorf("invalid host name: %q", host)
+		}
+		for _, s := range hostSettings {
+			if strings.EqualFold(s.Key, opt) {
+				s.Key = HostSettingKey(host, s.Key)
+				return s, nil
+			}
+		}
+	}
+	return Setting{}, fmt.Errorf("%w: %s", errUnknownSetting, key)
+}
+
+// GetSetting returns the effective value of the setting with the given key.
+func (b *biome) GetSetting(ctx context.Context, key string) (SettingValue, error) {
+	s, err := LookupSetting(key)
+	if err != nil {
+		return SettingValue{}, err
+	}
+	var result SettingValue
+	err = b.readConfig(ctx, func(ctx context.Context, cfg *config.Config) error {
+		result = settingValue(cfg, s)
+		return nil
+	})
+	return result, err
+}
+
+// SetSetting validates and stores a value for the setting with the given key.
+func (b *biome) SetSetting(ctx context.Context, key, value string) error {
+	s, err := LookupSetting(key)
+	if err != nil {
+		return err
+	}
+	if err := s.Validate(value); err != nil {
+		return err
+	}
+	return b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		setConfigValue(cfg, s.Key, value)
+		return true, nil
+	})
+}
+
+// UnsetSetting removes the setting with the given key from the biome, so the
+// setting's default is in effect.
+func (b *biome) UnsetSetting(ctx context.Context, key string) error {
+	s, err := LookupSetting(key)
+	if err != nil {
+		return err
+	}
+	return b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		unsetConfigValue(cfg, s.Key)
+		return true, nil
+	})
+}
+
+// ListSettings returns the effective values of all settings, sorted by key.
+// Per-owner settings are only included for owners of the biome where they
+// have been set, and per-remote settings only where they have been set.
+func (b *biome) ListSettings(ctx context.Context) ([]SettingValue, error) {
+	var result []SettingValue
+	err := b.readConfig(ctx, func(ctx context.Context, cfg *config.Config) error {
+		for _, s := range Settings() {
+			result = append(result, settingValue(cfg, s))
+		}
+		owners, err := b.getOwners(cfg)
+		if err != nil {
+			return err
+		}
+		for _, owner := range owners {
+			for _, s := range ownerSettings {
+				s.Key = OwnerSettingKey(owner, s.Key)
+				if v := settingValue(cfg, s); !v.IsDefault {
+					result = append(result, v)
+				}
+			}
+		}
+		for _, ss := range cfg.Section(section).Subsections {
+			host, ok := strings.CutPrefix(ss.Name, hostSubsectionPrefix)
+			if !ok {
+				continue
+			}
+			for _, s := range hostSettings {
+				s.Key = HostSettingKey(host, s.Key)
+				if v := settingValue(cfg, s); !v.IsDefault {
+					result = append(result, v)
+				}
+			}
+		}
+		for _, ss := range cfg.Section(section).Subsections {
+			remote, ok := strings.CutPrefix(ss.Name, remoteSubsectionPrefix)
+			if !ok {
+				continue
+			}
+			for _, s := range remoteSettings {
+				s.Key = RemoteSettingKey(remote, s.Key)
+				if v := settingValue(cfg, s); !v.IsDefault {
+					result = append(result, v)
+				}
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+// GetOwnerSetting returns the effective value of the per-owner setting option
+// for the given owner.
+func (b *biome) GetOwnerSetting(ctx context.Context, owner Owner, option string) (SettingValue, error) {
+	return b.GetSetting(ctx, OwnerSettingKey(owner, option))
+}
+
+// SetOwnerSetting validates and stores a value for the per-owner setting
+// option for the given owner.
+func (b *biome) SetOwnerSetting(ctx context.Context, owner Owner, option, value string) error {
+	return b.SetSetting(ctx, OwnerSettingKey(owner, option), value)
+}
+
+// UnsetOwnerSetting removes the per-owner setting option for the given owner,
+// so the setting's default is in effect.
+func (b *biome) UnsetOwnerSetting(ctx context.Context, owner Owner, option string) error {
+	return b.UnsetSetting(ctx, OwnerSettingKey(owner, option))
+}
+
+// ownerSetting returns the effective value of the per-owner setting option for
+// the given owner from a loaded config.
+func ownerSetting(cfg *config.Config, owner Owner, option string) string {
+	for _, s := range ownerSettings {
+		if s.Key == option {
+			s.Key = OwnerSettingKey(owner, s.Key)
+			return settingValue(cfg, s).Value
+		}
+	}
+	panic(fmt.Errorf("%w: %s", errUnknownSetting, option))
+}
+
+// hostSetting returns the effective value of the per-host setting option for
+// the given GitHub host from a loaded config.
+func hostSetting(cfg *config.Config, host, option string) string {
+	for _, s := range hostSettings {
+		if s.Key == option {
+			s.Key = HostSettingKey(host, s.Key)
+			return settingValue(cfg, s).Value
+		}
+	}
+	panic(fmt.Errorf("%w: %s", errUnknownSetting, option))
+}
+
+func settingValue(cfg *config.Config, s Setting) SettingValue {
+	value, ok := getConfigValue(cfg, s.Key)
+	if !ok {
+		return SettingValue{Setting: s, Value: s.Default, IsDefault: true}
+	}
+	return SettingValue{Setting: s, Value: value}
+}
+
+// splitConfigKey splits a git config key into its section, subsection, and
+// option parts. The subsection is empty if the key does not have one.
+func splitConfigKey(key string) (section, subsection, option string) {
+	i, j := strings.Index(key, "."), strings.LastIndex(key, ".")
+	if i < 0 {
+		return key, "", ""
+	}
+	if i == j {
+		return key[:i], "", key[i+1:]
+	}
+	return key[:i], key[i+1 : j], key[j+1:]
+}
+
+func getConfigValue(cfg *config.Config, key string) (string, bool) {
+	sec, subsec, opt := splitConfigKey(key)
+	if !cfg.HasSection(sec) {
+		return "", false
+	}
+	options := cfg.Section(sec).Options
+	if subsec != "" {
+		if !cfg.Section(sec).HasSubsection(subsec) {
+			return "", false
+		}
+		options = cfg.Section(sec).Subsection(subsec).Options
+	}
+	if !options.Has(opt) {
+		return "", false
+	}
+	return options.Get(opt), true
+}
+
+func setConfigValue(cfg *config.Config, key, value string) {
+	sec, subsec, opt := splitConfigKey(key)
+	if subsec != "" {
+		cfg.Section(sec).Subsection(subsec).SetOption(opt, value)
+	} else {
+		cfg.Section(sec).SetOption(opt, value)
+	}
+}
+
+func unsetConfigValue(cfg *config.Config, key string) {
+	sec, subsec, opt := splitConfigKey(key)
+	if !cfg.HasSection(sec) {
+		return
+	}
+	if subsec != "" {
+		if cfg.Section(sec).HasSubsection(subsec) {
+			cfg.Section(sec).Subsection(subsec).RemoveOption(opt)
+		}
+	} else {
+		cfg.Section(sec).RemoveOption(opt)
+	}
+}
+
+// splitList splits a comma separated setting value into its trimmed, non-empty
+// elements.
+func splitList(value string) []string {
+	var result []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// validateRemoteName ensures that a value is a remote name of the form
+// `<host>/<owner>/<repo>`.
+func validateRemoteName(value string) error {
+	parts := strings.Split(value, "/")
+	if len(parts) != 3 || slices.Contains(parts, "") {
+		return errors.New("must be of the form <host>/<owner>/<repo>")
+	}
+	return nil
+}
+
+func validateGlobs(value string) error {
+	for _, pattern := range splitList(value) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateOneOf returns a validation that accepts only the given values.
+func validateOneOf(values ...string) func(string) error {
+	return func(value string) error {
+		if !slices.Contains(values, value) {
+			return fmt.Errorf("must be one of %s", strings.Join(values, ", "))
+		}
+		return nil
+	}
+}
+
+// validateRefTemplate ensures that a reference namespace template is a valid
+// refspec pattern under `refs/`, that gives each remote its own namespace.
+func validateRefTemplate(value string) error {
+	if !strings.HasPrefix(value, "refs/") || !strings.HasSuffix(value, "/*") || strings.Count(value, "*") != 1 {
+		return errors.New("must begin with refs/ and end with the only wildcard, /*")
+	}
+	if !strings.Contains(value, "<name>") && !(strings.Contains(value, "<host>") && strings.Contains(value, "<owner>") && strings.Contains(value, "<repo>")) {
+		return errors.New("must contain <name>, or each of <host>, <owner>, and <repo>")
+	}
+	if _, err := (Remote{Name: "github.com/owner/repo", refTemplate: value}).FetchRefspec(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ParseBool parses a boolean config value the way git does: true, yes, on,
+// and any non-zero integer are true, while false, no, off, 0, and the empty
+// string are false, ignoring case. See git-config(1).
+func ParseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true", "yes", "on":
+		return true, nil
+	case "false", "no", "off", "":
+		return false, nil
+	}
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return n != 0, nil
+	}
+	return false, fmt.Errorf("invalid boolean: %q", value)
+}
+
+func validateBool(value string) error {
+	_, err := ParseBool(value)
+	return err
+}
+
+func validateNonNegativeInt(value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errors.New("must not be negative")
+	}
+	return nil
+}
+
+// sizePattern matches git's sizes, a number of bytes with an optional k, m,
+// or g suffix, ex. 512m.
+var sizePattern = regexp.MustCompile(`^[0-9]+[kKmMgG]?$`)
+
+func validateSize(value string) error {
+	if !sizePattern.MatchString(value) {
+		return errors.New("must be a number of bytes with an optional k, m, or g suffix, ex. 512m")
+	}
+	return nil
+}
+
+func validateDate(value string) error {
+	if _, err := time.Parse(time.DateOnly, value); err != nil {
+		return errors.New("must be a date of the form YYYY-MM-DD")
+	}
+	return nil
+}
+
+func validateFetchFrequency(value string) error {
+	_, err := ParseFetchFrequency(value)
+	return err
+}
+
+func validateNonNegativeDuration(value string) error {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	if d < 0 {
+		return errors.New("must not be negative")
+	}
+	return nil
+}
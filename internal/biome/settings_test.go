@@ -0,0 +1,361 @@
+package biome
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestSplitConfigKey(t *testing.T) {
+	for _, run := range []struct {
+		key                                       string
+		expectedSection, expectedSub, expectedOpt string
+	}{
+		{
+			key:             "fetch.parallel",
+			expectedSection: "fetch",
+			expectedOpt:     "parallel",
+		},
+		{
+			key:             "biome.remotes.active",
+			expectedSection: "biome",
+			expectedSub:     "remotes",
+			expectedOpt:     "active",
+		},
+		{
+			key:             "biome.owner.github.com/cli.tagOpt",
+			expectedSection: "biome",
+			expectedSub:     "owner.github.com/cli",
+			expectedOpt:     "tagOpt",
+		},
+	} {
+		t.Run(run.key, func(t *testing.T) {
+			section, sub, opt := splitConfigKey(run.key)
+			if section != run.expectedSection || sub != run.expectedSub || opt != run.expectedOpt {
+				t.Errorf("wanted (%q, %q, %q), was (%q, %q, %q)", run.expectedSection, run.expectedSub, run.expectedOpt, section, sub, opt)
+			}
+		})
+	}
+}
+
+func TestLookupSetting(t *testing.T) {
+	s, err := LookupSetting("fetch.parallel")
+	testutil.Check(t, err)
+	testutil.Check(t, s.Validate("8"))
+	testutil.ExpectError(t, s.Validate("-1"))
+	testutil.ExpectError(t, s.Validate("many"))
+
+	s, err = LookupSetting("fetch.prune")
+	testutil.Check(t, err)
+	testutil.Check(t, s.Validate("true"))
+	testutil.ExpectError(t, s.Validate("maybe"))
+
+	s, err = LookupSetting("http.version")
+	testutil.Check(t, err)
+	testutil.Check(t, s.Validate("HTTP/1.1"))
+	testutil.ExpectError(t, s.Validate("HTTP/3"))
+
+	s, err = LookupSetting("http.postBuffer")
+	testutil.Check(t, err)
+	testutil.Check(t, s.Validate("524288000"))
+	testutil.Check(t, s.Validate("500m"))
+	testutil.ExpectError(t, s.Validate("500mb"))
+	testutil.ExpectError(t, s.Validate("-1"))
+
+	s, err = LookupSetting("http.lowSpeedTime")
+	testutil.Check(t, err)
+	testutil.Check(t, s.Validate("60"))
+	testutil.ExpectError(t, s.Validate("1m"))
+
+	s, err = LookupSetting("biome.owner.github.com/cli.exclude")
+	testutil.Check(t, err)
+	if expected := OwnerSettingKey(github_com_cli, "exclude"); s.Key != expected {
+		t.Errorf("expected key %q, was %q", expected, s.Key)
+	}
+	testutil.Check(t, s.Validate("legacy-*, sandbox"))
+	testutil.ExpectError(t, s.Validate("[unterminated"))
+
+	_, err = LookupSetting("biome.owner.github.com/cli.foo")
+	testutil.ExpectError(t, err)
+
+	s, err = LookupSetting("biome.remote.github.com/orirawlings/bar.upstream")
+	testutil.Check(t, err)
+	if expected := RemoteSettingKey(barRemote.Name, "upstream"); s.Key != expected {
+		t.Errorf("expected key %q, was %q", expected, s.Key)
+	}
+	testutil.Check(t, s.Validate("github.com/cli/cli"))
+	testutil.ExpectError(t, s.Validate("cli/cli"))
+
+	_, err = LookupSetting("biome.remote.github.com/bar.upstream")
+	testutil.ExpectError(t, err)
+
+	s, err = LookupSetting("biome.host.github.com.pace")
+	testutil.Check(t, err)
+	if expected := HostSettingKey("github.com", "pace"); s.Key != expected {
+		t.Errorf("expected key %q, was %q", expected, s.Key)
+	}
+	testutil.Check(t, s.Validate("2s"))
+	testutil.ExpectError(t, s.Validate("-2s"))
+	testutil.ExpectError(t, s.Validate("2"))
+
+	s, err = LookupSetting("biome.host.my.github.biz.maxConcurrent")
+	testutil.Check(t, err)
+	if expected := HostSettingKey("my.github.biz", "maxConcurrent"); s.Key != expected {
+		t.Errorf("expected key %q, was %q", expected, s.Key)
+	}
+	testutil.Check(t, s.Validate("4"))
+	testutil.ExpectError(t, s.Validate("-1"))
+
+	_, err = LookupSetting("biome.host.github.com/cli.pace")
+	testutil.ExpectError(t, err)
+
+	_, err = LookupSetting("foo.bar")
+	if !errors.Is(err, errUnknownSetting) {
+		t.Errorf("expected unknown setting error, was %v", err)
+	}
+}
+
+func TestBiome_Settings(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+	b := initBiome(t, ctx, path, true)
+
+	v, err := b.GetSetting(ctx, "fetch.prune")
+	testutil.Check(t, err)
+	if v.Value != "false" || !v.IsDefault {
+		t.Errorf("expected default value for fetch.prune, was %+v", v)
+	}
+
+	testutil.Check(t, b.SetSetting(ctx, "fetch.prune", "true"))
+	assertGitConfig(t, path, "fetch.prune", "true")
+	v, err = b.GetSetting(ctx, "fetch.prune")
+	testutil.Check(t, err)
+	if v.Value != "true" || v.IsDefault {
+		t.Errorf("expected stored value for fetch.prune, was %+v", v)
+	}
+
+	// invalid values and unknown keys are rejected
+	testutil.ExpectError(t, b.SetSetting(ctx, "fetch.prune", "maybe"))
+	testutil.ExpectError(t, b.SetSetting(ctx, "foo.bar", "true"))
+	assertGitConfig(t, path, "fetch.prune", "true")
+
+	values, err := b.ListSettings(ctx)
+	testutil.Check(t, err)
+	if len(values) != len(Settings()) {
+		t.Errorf("expected %d settings, was %d", len(Settings()), len(values))
+	}
+
+	testutil.Check(t, b.UnsetSetting(ctx, "fetch.prune"))
+	v, err = b.GetSetting(ctx, "fetch.prune")
+	testutil.Check(t, err)
+	if !v.IsDefault {
+		t.Errorf("expected default value for fetch.prune after unset, was %+v", v)
+	}
+}
+
+func TestBiome_OwnerSettings(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+	b := initBiome(t, ctx, path, true)
+	addOwners(t, ctx, b, github_com_orirawlings, github_com_cli)
+
+	testutil.Check(t, b.SetOwnerSetting(ctx, github_com_orirawlings, "exclude", "arch*, locked"))
+	assertGitConfig(t, path, "biome.owner.github.com/orirawlings.exclude", "arch*, locked")
+	v, err := b.GetOwnerSetting(ctx, github_com_orirawlings, "exclude")
+	testutil.Check(t, err)
+	if v.Value != "arch*, locked" || v.IsDefault {
+		t.Errorf("unexpected value for exclude setting: %+v", v)
+	}
+	testutil.ExpectError(t, b.SetOwnerSetting(ctx, github_com_orirawlings, "unknown", "value"))
+
+	// excluded repositories are not added as remotes
+	testutil.Check(t, b.UpdateRemotes(ctx))
+	expectBiomeRemotes(t, ctx, b, []Remote{
+		githubCLICLIRemote,
+		barRemote,
+		disabledRemote,
+		headlessRemote,
+		dotPrefixRemote,
+	})
+
+	// per-owner settings that are set are listed
+	values, err := b.ListSettings(ctx)
+	testutil.Check(t, err)
+	if !slices.ContainsFunc(values, func(v SettingValue) bool {
+		return v.Key == OwnerSettingKey(github_com_orirawlings, "exclude")
+	}) {
+		t.Errorf("expected per-owner setting to be listed, was %v", values)
+	}
+
+	// per-owner settings are removed with the owner
+	removeOwners(t, ctx, b, github_com_orirawlings)
+	v, err = b.GetOwnerSetting(ctx, github_com_orirawlings, "exclude")
+	testutil.Check(t, err)
+	if !v.IsDefault {
+		t.Errorf("expected default value for exclude setting of removed owner, was %+v", v)
+	}
+}
+
+func TestBiome_OwnerSettings_tags(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+	b := initBiome(t, ctx, path, true)
+	addOwners(t, ctx, b, github_com_cli)
+
+	tagOptKey := "remote." + githubCLICLIRemote.Name + ".tagOpt"
+
+	testutil.Check(t, b.UpdateRemotes(ctx))
+	assertGitConfig(t, path, tagOptKey, "--no-tags")
+
+	testutil.ExpectError(t, b.SetOwnerSetting(ctx, github_com_cli, "tags", "some"))
+
+	testutil.Check(t, b.SetOwnerSetting(ctx, github_com_cli, "tags", "all"))
+	testutil.Check(t, b.UpdateRemotes(ctx))
+	assertGitConfig(t, path, tagOptKey, "--tags")
+
+	testutil.Check(t, b.SetOwnerSetting(ctx, github_com_cli, "tags", "follow"))
+	testutil.Check(t, b.UpdateRemotes(ctx))
+	assertConfigNotSet(t, path, tagOptKey)
+}
+
+func TestBiome_OwnerSettings_createdAfter(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+	b := initBiome(t, ctx, path, true)
+	addOwners(t, ctx, b, github_com_orirawlings, github_com_cli)
+
+	testutil.ExpectError(t, b.SetOwnerSetting(ctx, github_com_orirawlings, createdAfterOpt, "last year"))
+	testutil.Check(t, b.SetOwnerSetting(ctx, github_com_orirawlings, createdAfterOpt, "2023-01-01"))
+	assertGitConfig(t, path, "biome.owner.github.com/orirawlings.createdAfter", "2023-01-01")
+
+	// the creation date is only requested for the owner with the setting
+	newer, older := github_com_orirawlings_bar, github_com_orirawlings_headless
+	newer.CreatedAt = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	older.CreatedAt = time.Date(2022, 12, 31, 23, 59, 59, 0, time.UTC)
+	marshalled, err := json.Marshal([]repository{newer, older})
+	testutil.Check(t, err)
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(fmt.Sprintf(`{"query":"query OwnerRepositories($createdAt:Boolean!$endCursor:String$owner:String!$parent:Boolean!$visibility:Boolean!){repositoryOwner(login: $owner){repositories(first: 100, after: $endCursor, affiliations: [OWNER]){nodes{isDisabled,isArchived,isLocked,url,defaultBranchRef{name,prefix},visibility @include(if: $visibility),parent @include(if: $parent){url},createdAt @include(if: $createdAt)},pageInfo{hasNextPage,endCursor}}}}","variables":{"createdAt":true,"endCursor":null,"owner":%q,"parent":true,"visibility":true}}`, github_com_orirawlings.Name())).
+		Reply(200).
+		JSON(fmt.Sprintf(`{"data":{"repositoryOwner":{"repositories":{"nodes":%s,"pageInfo":{"hasNextPage":false}}}}}`, marshalled))
+
+	// repositories created before the date are not added as remotes
+	testutil.Check(t, b.UpdateRemotes(ctx))
+	expectBiomeRemotes(t, ctx, b, []Remote{
+		githubCLICLIRemote,
+		barRemote,
+	})
+}
+
+func TestBiome_OwnerSettings_include(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+	b := initBiome(t, ctx, path, true)
+	addOwners(t, ctx, b, github_com_orirawlings, github_com_cli)
+
+	testutil.ExpectError(t, b.SetOwnerSetting(ctx, github_com_orirawlings, includeOpt, "[bar"))
+	testutil.Check(t, b.SetOwnerSetting(ctx, github_com_orirawlings, includeOpt, "bar,head*,archived"))
+	testutil.Check(t, b.SetOwnerSetting(ctx, github_com_orirawlings, "exclude", "archived"))
+
+	// only included repositories that are not excluded are added as remotes
+	testutil.Check(t, b.UpdateRemotes(ctx))
+	expectBiomeRemotes(t, ctx, b, []Remote{
+		githubCLICLIRemote,
+		barRemote,
+		headlessRemote,
+	})
+}
+
+func TestParseBool(t *testing.T) {
+	for value, expected := range map[string]bool{
+		"true":  true,
+		"Yes":   true,
+		"ON":    true,
+		"1":     true,
+		"-2":    true,
+		"false": false,
+		"no":    false,
+		"Off":   false,
+		"0":     false,
+		"":      false,
+	} {
+		b, err := ParseBool(value)
+		if err != nil || b != expected {
+			t.Errorf("expected %q to parse as %t, was %t: %v", value, expected, b, err)
+		}
+	}
+
+	// git rejects the abbreviations that strconv accepts
+	for _, value := range []string{"T", "F", "t", "maybe"} {
+		if _, err := ParseBool(value); err == nil {
+			t.Errorf("expected %q to be invalid", value)
+		}
+	}
+}
+
+func TestValidateRefTemplate(t *testing.T) {
+	for _, run := range []struct {
+		template string
+		invalid  bool
+	}{
+		{template: defaultRefTemplate},
+		{template: "refs/biome/<host>/<owner>/<repo>/*"},
+		{template: "refs/biome/<owner>/<repo>/*", invalid: true},
+		{template: "refs/remotes/*", invalid: true},
+		{template: "remotes/<name>/*", invalid: true},
+		{template: "refs/remotes/<name>", invalid: true},
+		{template: "refs/*/<name>/*", invalid: true},
+		{template: "refs/remotes/<name>..x/*", invalid: true},
+	} {
+		t.Run(run.template, func(t *testing.T) {
+			err := validateRefTemplate(run.template)
+			if run.invalid && err == nil {
+				t.Error("expected validation error, but was nil")
+			}
+			if !run.invalid && err != nil {
+				t.Errorf("unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+func TestBiome_Settings_refspecTemplate(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+	b := initBiome(t, ctx, path, true)
+
+	commitID := createCommitFor(t, ctx, path, []string{
+		barRemoteCfg.Head,
+	})
+	addOwners(t, ctx, b, github_com_orirawlings)
+	testutil.Check(t, b.UpdateRemotes(ctx))
+
+	testutil.ExpectError(t, b.SetSetting(ctx, refspecTemplateKey, "refs/biome/*"))
+	testutil.Check(t, b.SetSetting(ctx, refspecTemplateKey, "refs/biome/<host>/<owner>/<repo>/*"))
+
+	// references are moved to the new namespace when remotes are updated
+	testutil.Check(t, b.UpdateRemotes(ctx))
+	assertGitConfig(t, path, "remote."+barRemote.Name+".fetch", "+refs/*:refs/biome/github.com/orirawlings/bar/*")
+	expectRefs(t, ctx, path, []string{
+		fmt.Sprintf(`%s commit refs/biome/github.com/orirawlings/bar/HEAD refs/biome/github.com/orirawlings/bar/heads/main`, commitID),
+		fmt.Sprintf(`%s commit refs/biome/github.com/orirawlings/bar/heads/main `, commitID),
+	})
+
+	remotes, err := b.Remotes(ctx, Active)
+	testutil.Check(t, err)
+	for _, r := range remotes {
+		if !strings.HasPrefix(r.Head(), "refs/biome/") {
+			t.Errorf("expected HEAD of %s under refs/biome/, was %q", r, r.Head())
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package biome
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cgi"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Share is a temporary bare repository that exposes a single remote of the
+// biome to others, without exposing the rest of the biome. The remote's
+// branches and tags are the share's own branches and tags, and the remote's
+// HEAD is the share's HEAD, so the share can be cloned like the remote's
+// repository on GitHub.
+//
+// The share reads the biome's objects as alternates, rather than copying
+// them. Only objects reachable from the remote's references are ever written
+// to bundles of the share, or sent by [Share.Handler], which refuses to send
+// objects that were not asked for by the name of a shared reference.
+type Share struct {
+
+	// Remote is the name of the shared remote.
+	Remote string
+
+	// Refs is the number of the remote's references that are shared.
+	Refs int
+
+	// dir is the temporary directory that holds the share's repository.
+	dir string
+}
+
+// Share creates a temporary bare repository that shares the references of
+// the named remote. It must be closed once it is no longer needed.
+func (b *biome) Share(ctx context.Context, remote string) (*Share, error) {
+	remotes, err := b.Remotes(ctx, FetchableRemoteCategories...)
+	if err != nil {
+		return nil, err
+	}
+	var namespace, head string
+	for _, r := range remotes {
+		if r.Name == remote {
+			namespace, head = r.RefNamespace(), r.Head()
+		}
+	}
+	if namespace == "" {
+		return nil, fmt.Errorf("%w: %s", errRemoteNotFound, remote)
+	}
+	refs, err := b.listRefs(ctx, []string{namespace})
+	if err != nil {
+		return nil, err
+	}
+	s := &Share{Remote: remote}
+	var updates bytes.Buffer
+	var target string
+	for _, r := range refs {
+		switch {
+		case r.Name == head:
+			target = strings.TrimPrefix(r.Symref, namespace)
+		case r.Symref == "":
+			fmt.Fprintf(&updates, "create refs/%s %s\n", strings.TrimPrefix(r.Name, namespace), r.ObjectName)
+			s.Refs++
+		}
+	}
+	if s.Refs == 0 {
+		return nil, fmt.Errorf("remote has no references to share, fetch it first: %s", remote)
+	}
+
+	gitDir, err := b.gitDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	format, err := b.git(ctx, "rev-parse", "--show-object-format")
+	if err != nil {
+		return nil, err
+	}
+	if s.dir, err = os.MkdirTemp("", "biome-share-*"); err != nil {
+		return nil, fmt.Errorf("could not create share: %w", err)
+	}
+	if err := s.init(ctx, strings.TrimSpace(string(format)), filepath.Join(gitDir, "objects"), &updates, target); err != nil {
+		return nil, errors.Join(fmt.Errorf("could not create share of %s: %w", remote, err), s.Close())
+	}
+	return s, nil
+}
+
+// init initializes the share's repository, borrowing objects from the given
+// object directory, and creates its references from the git update-ref
+// instructions. If target is not empty, HEAD points to the reference named
+// by target, relative to refs/.
+func (s *Share) init(ctx context.Context, format, objects string, updates io.Reader, target string) error {
+	if _, err := s.git(ctx, nil, "init", "--quiet", "--bare", "--object-format="+format, s.Path()); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(s.Path(), "objects", "info", "alternates"), []byte(objects+"\n"), 0o644); err != nil {
+		return err
+	}
+	// the alternates hold every object of the biome, so only the tips of
+	// the shared references may be asked for
+	for _, key := range []string{"uploadpack.allowAnySHA1InWant", "uploadpack.allowReachableSHA1InWant", "uploadpack.allowTipSHA1InWant"} {
+		if _, err := s.git(ctx, nil, "-C", s.Path(), "config", key, "false"); err != nil {
+			return err
+		}
+	}
+	if _, err := s.git(ctx, updates, "-C", s.Path(), "update-ref", "--stdin"); err != nil {
+		return err
+	}
+	if target != "" {
+		if _, err := s.git(ctx, nil, "-C", s.Path(), "symbolic-ref", "HEAD", "refs/"+target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Path returns the path of the share's bare repository, which is named after
+// the remote, ex. `<tmp>/github.com/cli/cli.git`.
+func (s *Share) Path() string {
+	return filepath.Join(s.dir, filepath.FromSlash(s.Remote)+".git")
+}
+
+// WriteBundle writes a git bundle of the share's references, and the objects
+// reachable from them, to w. The bundle can be cloned, ex. `git clone
+// cli.bundle`.
+func (s *Share) WriteBundle(ctx context.Context, w io.Writer) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "-C", s.Path(), "bundle", "create", "--quiet", "-", "--all")
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("could not %q: %w: %s", cmd, err, stderr.String())
+	}
+	return nil
+}
+
+// Handler returns an HTTP handler that serves the share with git's smart
+// HTTP protocol, through git http-backend, so that it can be cloned and
+// fetched from `http://<host>/<remote>.git`. Only fetches are served. Pushes,
+// and git's dumb HTTP protocol, which serves the repository's files as they
+// are, are refused.
+//
+// Fetches are always served with version 0 of git's wire protocol, since
+// version 2 lets clients ask for any object by its ID, which would expose
+// the objects of the biome's other remotes through the share's alternates.
+// With version 0, clients may only ask for the tips of the shared
+// references.
+func (s *Share) Handler() http.Handler {
+	backend := &cgi.Handler{
+		Path: "git",
+		Args: []string{"http-backend"},
+		Env: []string{
+			"GIT_PROJECT_ROOT=" + s.dir,
+			"GIT_HTTP_EXPORT_ALL=1",
+		},
+	}
+	if path, err := exec.LookPath("git"); err == nil {
+		backend.Path = path
+	}
+	repo := "/" + s.Remote + ".git"
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == repo+"/info/refs" && r.URL.Query().Get("service") == "git-upload-pack":
+		case r.Method == http.MethodPost && r.URL.Path == repo+"/git-upload-pack":
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		r.Header.Del("Git-Protocol")
+		backend.ServeHTTP(w, r)
+	})
+}
+
+// Close removes the share's repository.
+func (s *Share) Close() error {
+	return os.RemoveAll(s.dir)
+}
+
+// git runs git with the given stdin, returning its output.
+func (s *Share) git(ctx context.Context, stdin io.Reader, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Stdin = stdin
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not %q: %w: %s", cmd, err, stderr.String())
+	}
+	return out, nil
+}
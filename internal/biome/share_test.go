@@ -0,0 +1,102 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_Share(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		for _, r := range []Remote{barRemote, headlessRemote} {
+			cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, r.Name)
+		}
+		return true, nil
+	}))
+	commit := createCommitFor(t, ctx, path, []string{
+		"refs/remotes/github.com/orirawlings/bar/heads/main",
+		"refs/remotes/github.com/orirawlings/bar/tags/v1",
+	})
+	testutil.Execute(t, "git", "-C", path, "symbolic-ref", barRemote.Head(), "refs/remotes/github.com/orirawlings/bar/heads/main")
+	tree := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "write-tree"))
+	private := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "-c", "user.name=A", "-c", "user.email=a@example.com", "commit-tree", "-m", "private", tree))
+
+	if _, err := b.Share(ctx, "github.com/orirawlings/missing"); !errors.Is(err, errRemoteNotFound) {
+		t.Errorf("expected error for unknown remote, was %v", err)
+	}
+	if _, err := b.Share(ctx, headlessRemote.Name); err == nil {
+		t.Error("expected error for remote without references")
+	}
+
+	s, err := b.Share(ctx, barRemote.Name)
+	testutil.Check(t, err)
+	if s.Refs != 2 {
+		t.Errorf("expected 2 shared references, was %d", s.Refs)
+	}
+	expectClone := func(t *testing.T, source string) {
+		t.Helper()
+		clone := filepath.Join(t.TempDir(), "bar")
+		testutil.Execute(t, "git", "clone", "--quiet", source, clone)
+		if actual := strings.TrimSpace(testutil.Execute(t, "git", "-C", clone, "rev-parse", "HEAD", "v1")); actual != commit+"\n"+commit {
+			t.Errorf("expected clone to have HEAD and tag at %s, was %q", commit, actual)
+		}
+		if actual := strings.TrimSpace(testutil.Execute(t, "git", "-C", clone, "symbolic-ref", "--short", "HEAD")); actual != "main" {
+			t.Errorf("expected clone to check out main, was %q", actual)
+		}
+	}
+
+	t.Run("bundle", func(t *testing.T) {
+		bundle := filepath.Join(t.TempDir(), "bar.bundle")
+		f, err := os.Create(bundle)
+		testutil.Check(t, err)
+		testutil.Check(t, s.WriteBundle(ctx, f))
+		testutil.Check(t, f.Close())
+		expectClone(t, bundle)
+	})
+
+	t.Run("http", func(t *testing.T) {
+		server := httptest.NewServer(s.Handler())
+		t.Cleanup(server.Close)
+		expectClone(t, server.URL+"/github.com/orirawlings/bar.git")
+
+		// files of the repository are not served as they are
+		for _, file := range []string{"HEAD", "objects/info/alternates", "info/refs"} {
+			resp, err := http.Get(server.URL + "/github.com/orirawlings/bar.git/" + file)
+			testutil.Check(t, err)
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusNotFound {
+				t.Errorf("expected %s not to be served, was %s", file, resp.Status)
+			}
+		}
+
+		// objects of the biome that are not shared cannot be asked for,
+		// with any version of git's wire protocol
+		for _, version := range []string{"0", "1", "2"} {
+			clone := testutil.TempRepo(t)
+			cmd := exec.CommandContext(ctx, "git", "-C", clone, "-c", "protocol.version="+version, "fetch", "--quiet", server.URL+"/github.com/orirawlings/bar.git", private)
+			if out, err := cmd.CombinedOutput(); err == nil {
+				t.Errorf("expected fetching an object that is not shared to fail with protocol version %s: %s", version, out)
+			}
+		}
+	})
+
+	testutil.Check(t, s.Close())
+	if _, err := os.Stat(s.Path()); !os.IsNotExist(err) {
+		t.Errorf("expected share to be removed, was %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package biome
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os/exec"
+	"slices"
+)
+
+// SignatureReport counts the commits of a remote's default branch by the
+// status of their GPG or SSH signatures, as verified by git with the user's
+// gpg and gpg.ssh configuration.
+type SignatureReport struct {
+
+	// Remote is the name of the remote whose commits are verified.
+	Remote string
+
+	// Good is the number of commits with a good signature, including
+	// signatures by expired keys or keys of unknown validity.
+	Good int
+
+	// Bad is the number of commits with a bad signature, or a signature by a
+	// revoked key.
+	Bad int
+
+	// Unverified is the number of signed commits whose signature could not be
+	// checked, ex. because the signing key is not known.
+	Unverified int
+
+	// Unsigned is the number of commits without a signature.
+	Unsigned int
+}
+
+// Commits returns the number of commits that were verified.
+func (r SignatureReport) Commits() int {
+	return r.Good + r.Bad + r.Unverified + r.Unsigned
+}
+
+// count records the signature status of a commit, as reported by git's %G?
+// format placeholder.
+func (r *SignatureReport) count(status string) {
+	switch signatureStatus(status) {
+	case SignatureGood:
+		r.Good++
+	case SignatureBad:
+		r.Bad++
+	case SignatureUnverified:
+		r.Unverified++
+	default:
+		r.Unsigned++
+	}
+}
+
+// VerifySignatures verifies the signatures of the commits at the HEAD of each
+// fetched remote in the given categories, sorted by remote name. If since is
+// given, a git date, ex. "1.month.ago", every commit of the default branch
+// committed since then is verified instead. Remotes whose HEAD has not been
+// fetched are omitted.
+func (b *biome) VerifySignatures(ctx context.Context, since string, categories ...RemoteCategory) ([]SignatureReport, error) {
+	remotes, err := b.Remotes(ctx, categories...)
+	if err != nil {
+		return nil, err
+	}
+	heads, err := b.resolveHeads(ctx)
+	if err != nil {
+		return nil, err
+	}
+	selected := make(map[string]string)
+	for _, r := range remotes {
+		if commit := heads[r.Name]; commit != "" {
+			selected[r.Name] = commit
+		}
+	}
+
+	args, err := b.verifyArgs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, "log", "--format=%G?")
+
+	var reports []SignatureReport
+	for _, name := range slices.Sorted(maps.Keys(selected)) {
+		args := slices.Clone(args)
+		if since != "" {
+			args = append(args, "--since="+since)
+		} else {
+			args = append(args, "-1")
+		}
+		args = append(args, selected[name], "--")
+
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("could not %q: %w: %s", cmd, err, stderr.String())
+		}
+		report := SignatureReport{
+			Remote: name,
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(out))
+		for scanner.Scan() {
+			report.count(scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// userConfig returns the value of a git config key from any of the user's
+// git config files, or an empty string if it is unset.
+func (b *biome) userConfig(ctx context.Context, key string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "-C", b.path, "config", "--get", key)
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			// the config key is unset
+			return "", nil
+		}
+		return "", fmt.Errorf("could not %q: %w", cmd.String(), err)
+	}
+	return string(bytes.TrimSpace(out)), nil
+}
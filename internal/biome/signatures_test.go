@@ -0,0 +1,99 @@
+package biome
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestSignatureReport_count(t *testing.T) {
+	var r SignatureReport
+	for _, status := range []string{"G", "U", "X", "Y", "B", "R", "E", "N", "N"} {
+		r.count(status)
+	}
+	if expected := (SignatureReport{Good: 4, Bad: 2, Unverified: 1, Unsigned: 2}); r != expected {
+		t.Errorf("unexpected report: wanted %+v, was %+v", expected, r)
+	}
+	if r.Commits() != 9 {
+		t.Errorf("expected 9 commits, was %d", r.Commits())
+	}
+}
+
+func TestBiome_VerifySignatures(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		for _, r := range []Remote{barRemote, githubCLICLIRemote, githubGitGitRemote, headlessRemote} {
+			cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, r.Name)
+		}
+		cfg.Section(section).Subsection(remotesSubsection).AddOption(archivedOpt, archivedRemote.Name)
+		return true, nil
+	}))
+
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen is required to sign commits")
+	}
+	key := filepath.Join(t.TempDir(), "key")
+	testutil.Execute(t, "ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", key)
+	for _, name := range []string{"GIT_AUTHOR", "GIT_COMMITTER"} {
+		t.Setenv(name+"_NAME", "A")
+		t.Setenv(name+"_EMAIL", "a@example.com")
+	}
+
+	// cli/cli's HEAD is signed, on top of an old unsigned commit shared with
+	// bar, while git/git's HEAD was tampered with after it was signed
+	base := createCommitFor(t, ctx, path, []string{
+		"refs/remotes/github.com/orirawlings/bar/heads/main",
+		"refs/remotes/github.com/orirawlings/archived/heads/main",
+	})
+	signed := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "-c", "gpg.format=ssh", "-c", "user.signingKey="+key, "commit-tree", "-S", "-m", "signed", "-p", base, "4b825dc642cb6eb9a060e54bf8d69288fbee4904"))
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/remotes/github.com/cli/cli/heads/trunk", signed)
+	cmd := exec.Command("git", "-C", path, "hash-object", "-t", "commit", "-w", "--stdin")
+	cmd.Stdin = strings.NewReader(strings.Replace(testutil.Execute(t, "git", "-C", path, "cat-file", "commit", signed), "\nsigned\n", "\ntampered\n", 1))
+	out, err := cmd.Output()
+	testutil.Check(t, err)
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/remotes/github.com/git/git/heads/master", strings.TrimSpace(string(out)))
+	for ref, target := range map[string]string{
+		githubCLICLIRemote.Head(): "refs/remotes/github.com/cli/cli/heads/trunk",
+		barRemote.Head():          "refs/remotes/github.com/orirawlings/bar/heads/main",
+		githubGitGitRemote.Head(): "refs/remotes/github.com/git/git/heads/master",
+		archivedRemote.Head():     "refs/remotes/github.com/orirawlings/archived/heads/main",
+	} {
+		testutil.Execute(t, "git", "-C", path, "symbolic-ref", ref, target)
+	}
+
+	// without allowed signers, intact signatures are good, of unknown
+	// validity
+	reports, err := b.VerifySignatures(ctx, "", Active)
+	testutil.Check(t, err)
+	expected := []SignatureReport{
+		{Remote: githubCLICLIRemote.Name, Good: 1},
+		{Remote: githubGitGitRemote.Name, Bad: 1},
+		{Remote: barRemote.Name, Unsigned: 1},
+	}
+	if !slices.Equal(reports, expected) {
+		t.Errorf("unexpected reports:\nwanted %+v\nwas    %+v", expected, reports)
+	}
+
+	// only commits since the given date are verified
+	reports, err = b.VerifySignatures(ctx, "2020-01-01", Active)
+	testutil.Check(t, err)
+	expected = []SignatureReport{
+		{Remote: githubCLICLIRemote.Name, Good: 1},
+		{Remote: githubGitGitRemote.Name, Bad: 1},
+		{Remote: barRemote.Name},
+	}
+	if !slices.Equal(reports, expected) {
+		t.Errorf("unexpected reports since 2020-01-01:\nwanted %+v\nwas    %+v", expected, reports)
+	}
+}
@@ -0,0 +1,666 @@
+package biome
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// snapshotRefPrefix is the reference prefix of the biome's snapshots.
+	snapshotRefPrefix = "refs/biome/snapshots/"
+
+	// snapshotRefsFile is the file of a snapshot's tree that lists the
+	// references of the snapshot, in the format of 'git ls-remote --symref'.
+	snapshotRefsFile = "refs"
+
+	// snapshotRemotesFile is the file of a snapshot's tree that lists the
+	// reference namespace and name of each of the biome's remotes, separated
+	// by a tab, when the snapshot was created.
+	snapshotRemotesFile = "remotes"
+
+	// snapshotFanOut is the most parents of any commit created for a
+	// snapshot. Snapshots of more commits are pinned through intermediate
+	// commits, so that no commit object grows too large to walk.
+	snapshotFanOut = 256
+
+	// referencesTrailer is the trailer of a snapshot's commit message that
+	// counts the references of the snapshot.
+	referencesTrailer = "References"
+)
+
+var (
+	// errSnapshotExists indicates that a snapshot cannot be created, because
+	// a snapshot of the same name already exists.
+	errSnapshotExists = errors.New("snapshot already exists")
+
+	// errSnapshotNotFound indicates that a named snapshot does not exist.
+	errSnapshotNotFound = errors.New("snapshot not found")
+
+	// errObjectsMissing indicates that a snapshot cannot be restored, because
+	// objects that it recorded are no longer present, ex. because they were
+	// pruned.
+	errObjectsMissing = errors.New("objects missing")
+
+	// errInvalidSnapshotName indicates that a snapshot name is not valid as
+	// part of a git reference name.
+	errInvalidSnapshotName = errors.New("snapshot name must be valid in a git reference name")
+)
+
+// Snapshot is a record of the object name of every reference of the biome's
+// remotes at a point in time, so that analyses can be pinned to an exact
+// state of the biome and run again later.
+//
+// A snapshot is a commit on refs/biome/snapshots/<name>, whose tree has a
+// file, refs, that lists the snapshot's references, and a file, remotes, that
+// lists the biome's remotes and their reference namespaces, and whose parents
+// are, directly or through intermediate commits, the commits of those
+// references. The commits therefore remain reachable, and are not pruned,
+// for as long as the snapshot exists, even after the remotes' references
+// move on.
+type Snapshot struct {
+
+	// Name of the snapshot.
+	Name string
+
+	// Commit is the object name of the snapshot's commit.
+	Commit string
+
+	// Time the snapshot was created.
+	Time time.Time
+
+	// References is the number of references recorded by the snapshot.
+	References int
+}
+
+// Ref returns the reference of the snapshot's commit.
+func (s Snapshot) Ref() string {
+	return snapshotRefPrefix + s.Name
+}
+
+// CreateSnapshot records the current object name of every reference of the
+// biome's remotes, in all categories, as a new snapshot with the given name.
+func (b *biome) CreateSnapshot(ctx context.Context, name string) (Snapshot, error) {
+	if err := b.checkNewSnapshot(ctx, name); err != nil {
+		return Snapshot{}, err
+	}
+	remotes, err := b.Remotes(ctx, AllRemoteCategories...)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	var prefixes []string
+	for _, r := range remotes {
+		prefixes = append(prefixes, r.RefNamespace())
+	}
+	refs, err := b.listRefs(ctx, prefixes)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	return b.writeSnapshot(ctx, name, remotes, refs)
+}
+
+// CreateSnapshotAt records a new snapshot with the given name, in which the
+// default branch of each of the named remotes, keyed by remote name, is at
+// the given commit, ex. the commits pinned by a multi-repository manifest.
+// The snapshot records only these remotes, their HEAD references, and their
+// default branches. Each remote's HEAD reference must point to its default
+// branch, and the commits must have been fetched into the biome.
+func (b *biome) CreateSnapshotAt(ctx context.Context, name string, commits map[string]string) (Snapshot, error) {
+	if err := b.checkNewSnapshot(ctx, name); err != nil {
+		return Snapshot{}, err
+	}
+	all, err := b.Remotes(ctx, AllRemoteCategories...)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	var remotes []Remote
+	var heads []string
+	for _, remote := range slices.Sorted(maps.Keys(commits)) {
+		i := slices.IndexFunc(all, func(r Remote) bool { return r.Name == remote })
+		if i < 0 {
+			return Snapshot{}, fmt.Errorf("%w: %s", errRemoteNotFound, remote)
+		}
+		remotes = append(remotes, all[i])
+		heads = append(heads, all[i].Head())
+	}
+	headRefs, err := b.listRefs(ctx, heads)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	targets := make(map[string]string)
+	for _, ref := range headRefs {
+		targets[ref.Name] = ref.Symref
+	}
+	missing, err := b.missingObjects(ctx, slices.Collect(maps.Values(commits)))
+	if err != nil {
+		return Snapshot{}, err
+	}
+	var refs []storedRef
+	for _, r := range remotes {
+		target := targets[r.Head()]
+		if target == "" {
+			return Snapshot{}, fmt.Errorf("%w: %s", errHeadNotFetched, r.Name)
+		}
+		commit := commits[r.Name]
+		if slices.Contains(missing, commit) {
+			return Snapshot{}, fmt.Errorf("%w: %s %s", errObjectsMissing, r.Name, commit)
+		}
+		refs = append(refs,
+			storedRef{Name: r.Head(), Symref: target},
+			storedRef{Name: target, ObjectName: commit},
+		)
+	}
+	return b.writeSnapshot(ctx, name, remotes, refs)
+}
+
+// checkNewSnapshot ensures that a snapshot with the given name can be
+// created, because the name is valid and no snapshot has it yet.
+func (b *biome) checkNewSnapshot(ctx context.Context, name string) error {
+	ref := snapshotRefPrefix + name
+	if err := exec.CommandContext(ctx, "git", "check-ref-format", ref).Run(); err != nil {
+		return fmt.Errorf("%w: %q", errInvalidSnapshotName, name)
+	}
+	existing, err := b.listRefs(ctx, []string{ref})
+	if err != nil {
+		return err
+	}
+	if slices.ContainsFunc(existing, func(r storedRef) bool { return r.Name == ref }) {
+		return fmt.Errorf("%w: %q", errSnapshotExists, name)
+	}
+	return nil
+}
+
+// writeSnapshot records the given references of the given remotes as a new
+// snapshot with the given name.
+func (b *biome) writeSnapshot(ctx context.Context, name string, remotes []Remote, refs []storedRef) (Snapshot, error) {
+	ref := snapshotRefPrefix + name
+	var remoteList bytes.Buffer
+	for _, r := range remotes {
+		fmt.Fprintf(&remoteList, "%s\t%s\n", r.RefNamespace(), r.Name)
+	}
+	slices.SortFunc(refs, func(a, b storedRef) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	var list bytes.Buffer
+	var objects []string
+	for _, r := range refs {
+		if r.Symref != "" {
+			fmt.Fprintf(&list, "ref: %s\t%s\n", r.Symref, r.Name)
+			continue
+		}
+		fmt.Fprintf(&list, "%s\t%s\n", r.ObjectName, r.Name)
+		objects = append(objects, r.ObjectName)
+	}
+	refsBlob, err := b.gitStdin(ctx, &list, "hash-object", "-w", "--stdin")
+	if err != nil {
+		return Snapshot{}, err
+	}
+	remotesBlob, err := b.gitStdin(ctx, &remoteList, "hash-object", "-w", "--stdin")
+	if err != nil {
+		return Snapshot{}, err
+	}
+	entries := fmt.Sprintf("100644 blob %s\t%s\n100644 blob %s\t%s\n", refsBlob, snapshotRefsFile, remotesBlob, snapshotRemotesFile)
+	tree, err := b.gitStdin(ctx, strings.NewReader(entries), "mktree")
+	if err != nil {
+		return Snapshot{}, err
+	}
+	parents, err := b.peelCommits(ctx, objects)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	if len(parents) > snapshotFanOut {
+		emptyTree, err := b.gitStdin(ctx, strings.NewReader(""), "mktree")
+		if err != nil {
+			return Snapshot{}, err
+		}
+		for len(parents) > snapshotFanOut {
+			var pins []string
+			for batch := range slices.Chunk(parents, snapshotFanOut) {
+				pin, err := b.commitTree(ctx, emptyTree, fmt.Sprintf("Pin commits of snapshot %s", name), batch)
+				if err != nil {
+					return Snapshot{}, err
+				}
+				pins = append(pins, pin)
+			}
+			parents = pins
+		}
+	}
+	message := fmt.Sprintf("Snapshot %s\n\n%s: %d\n", name, referencesTrailer, len(refs))
+	commit, err := b.commitTree(ctx, tree, message, parents)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	// an empty old value ensures that a snapshot created concurrently is
+	// not overwritten
+	if _, err := b.git(ctx, "update-ref", "-m", "biome: create snapshot", ref, commit, ""); err != nil {
+		return Snapshot{}, err
+	}
+	snapshots, err := b.Snapshots(ctx)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	for _, s := range snapshots {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+	return Snapshot{}, fmt.Errorf("%w: %q", errSnapshotNotFound, name)
+}
+
+// Snapshots returns the biome's snapshots, sorted by name.
+func (b *biome) Snapshots(ctx context.Context) ([]Snapshot, error) {
+	out, err := b.git(ctx, "for-each-ref",
+		"--format=%(refname)%00%(objectname)%00%(committerdate:unix)%00%(trailers:key="+referencesTrailer+",valueonly,separator=)",
+		snapshotRefPrefix,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []Snapshot
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\x00")
+		if len(fields) != 4 {
+			continue
+		}
+		unix, _ := strconv.ParseInt(fields[2], 10, 64)
+		references, _ := strconv.Atoi(strings.TrimSpace(fields[3]))
+		snapshots = append(snapshots, Snapshot{
+			Name:       strings.TrimPrefix(fields[0], snapshotRefPrefix),
+			Commit:     fields[1],
+			Time:       time.Unix(unix, 0),
+			References: references,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+// DeleteSnapshot deletes the named snapshot. Commits that were only kept by
+// the snapshot may be pruned afterwards.
+func (b *biome) DeleteSnapshot(ctx context.Context, name string) error {
+	snapshots, err := b.Snapshots(ctx)
+	if err != nil {
+		return err
+	}
+	for _, s := range snapshots {
+		if s.Name == name {
+			_, err := b.git(ctx, "update-ref", "-m", "biome: delete snapshot", "-d", s.Ref(), s.Commit)
+			return err
+		}
+	}
+	return fmt.Errorf("%w: %q", errSnapshotNotFound, name)
+}
+
+// peelCommits returns the distinct commits that the given objects peel to,
+// sorted by object name. Objects that are not commits, and do not peel to a
+// commit, are omitted.
+func (b *biome) peelCommits(ctx context.Context, objects []string) ([]string, error) {
+	if len(objects) == 0 {
+		return nil, nil
+	}
+	var stdin bytes.Buffer
+	for _, object := range objects {
+		fmt.Fprintf(&stdin, "%s^{commit}\n", object)
+	}
+	out, err := b.gitStdin(ctx, &stdin, "cat-file", "--batch-check=%(objectname) %(objecttype)")
+	if err != nil {
+		return nil, err
+	}
+	var commits []string
+	for _, line := range strings.Split(out, "\n") {
+		if object, kind, ok := strings.Cut(line, " "); ok && kind == "commit" {
+			commits = append(commits, object)
+		}
+	}
+	slices.Sort(commits)
+	return slices.Compact(commits), nil
+}
+
+// commitTree creates a commit of the given tree and parents. If the user has
+// not configured an identity, the commit is made by gh-biome.
+func (b *biome) commitTree(ctx context.Context, tree, message string, parents []string) (string, error) {
+	var args []string
+	for _, identity := range [][2]string{
+		{"user.name", "gh-biome"},
+		{"user.email", "gh-biome@localhost"},
+	} {
+		configured, err := b.userConfig(ctx, identity[0])
+		if err != nil {
+			return "", err
+		}
+		if configured == "" {
+			args = append(args, "-c", identity[0]+"="+identity[1])
+		}
+	}
+	args = append(args, "commit-tree", tree)
+	for _, parent := range parents {
+		args = append(args, "-p", parent)
+	}
+	return b.gitStdin(ctx, strings.NewReader(message), args...)
+}
+
+// gitStdin executes a git command in the biome with the given input, and
+// returns its output, without surrounding whitespace.
+func (b *biome) gitStdin(ctx context.Context, stdin io.Reader, args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", b.path}, args...)...)
+	cmd.Stdin = stdin
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("could not %q: %w: %s", cmd, err, stderr.String())
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// SnapshotDiff reports how the references of a remote changed between two
+// snapshots.
+type SnapshotDiff struct {
+
+	// Remote is the name of the remote.
+	Remote string
+
+	// Added is true if the remote was only in the later snapshot.
+	Added bool
+
+	// Removed is true if the remote was only in the earlier snapshot.
+	Removed bool
+
+	// RefsAdded is the number of the remote's references that were only in
+	// the later snapshot.
+	RefsAdded int
+
+	// RefsRemoved is the number of the remote's references that were only in
+	// the earlier snapshot.
+	RefsRemoved int
+
+	// RefsUpdated is the number of the remote's references that point to
+	// different objects in each snapshot.
+	RefsUpdated int
+
+	// OldHead is the commit at the remote's HEAD in the earlier snapshot, if
+	// any.
+	OldHead string
+
+	// NewHead is the commit at the remote's HEAD in the later snapshot, if
+	// any.
+	NewHead string
+
+	// Ahead is the number of commits of the later HEAD that are not in the
+	// earlier HEAD.
+	Ahead int
+
+	// Behind is the number of commits of the earlier HEAD that are not in
+	// the later HEAD. If it is not zero, the remote's default branch was
+	// force-pushed, or changed to another branch.
+	Behind int
+}
+
+// ForcePushed reports whether the remote's HEAD moved to a commit that does
+// not contain the earlier HEAD, ex. because it was force-pushed.
+func (d SnapshotDiff) ForcePushed() bool {
+	return d.Behind > 0
+}
+
+// snapshotState is the content of a snapshot: the values of its references,
+// object names or "ref: <target>" for symbolic references, by name, and the
+// reference namespaces of its remotes, by remote name.
+type snapshotState struct {
+	refs       map[string]string
+	namespaces map[string]string
+}
+
+// head returns the commit at the HEAD of the named remote, if any.
+func (s snapshotState) head(remote string) string {
+	namespace, ok := s.namespaces[remote]
+	if !ok {
+		return ""
+	}
+	value := s.refs[namespace+"HEAD"]
+	if target, ok := strings.CutPrefix(value, "ref: "); ok {
+		value = s.refs[target]
+	}
+	return value
+}
+
+// remoteRefs returns the values of the references of the named remote, by
+// name within the remote's namespace.
+func (s snapshotState) remoteRefs(remote string) map[string]string {
+	refs := make(map[string]string)
+	namespace, ok := s.namespaces[remote]
+	if !ok {
+		return refs
+	}
+	for name, value := range s.refs {
+		if name, ok := strings.CutPrefix(name, namespace); ok {
+			if target, ok := strings.CutPrefix(value, "ref: "); ok {
+				value = "ref: " + strings.TrimPrefix(target, namespace)
+			}
+			refs[name] = value
+		}
+	}
+	return refs
+}
+
+// readSnapshot reads the content of the named snapshot.
+func (b *biome) readSnapshot(ctx context.Context, name string) (snapshotState, error) {
+	snapshots, err := b.Snapshots(ctx)
+	if err != nil {
+		return snapshotState{}, err
+	}
+	i := slices.IndexFunc(snapshots, func(s Snapshot) bool { return s.Name == name })
+	if i < 0 {
+		return snapshotState{}, fmt.Errorf("%w: %q", errSnapshotNotFound, name)
+	}
+	commit := snapshots[i].Commit
+	state := snapshotState{
+		refs:       make(map[string]string),
+		namespaces: make(map[string]string),
+	}
+	for file, entries := range map[string]map[string]string{
+		snapshotRefsFile:    state.refs,
+		snapshotRemotesFile: state.namespaces,
+	} {
+		out, err := b.git(ctx, "cat-file", "blob", commit+":"+file)
+		if err != nil {
+			return snapshotState{}, err
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(out))
+		for scanner.Scan() {
+			if value, key, ok := strings.Cut(scanner.Text(), "\t"); ok {
+				entries[key] = value
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return snapshotState{}, err
+		}
+	}
+	return state, nil
+}
+
+// DiffSnapshots reports which remotes were added or removed, gained, lost,
+// or updated references, or whose HEAD moved, between the earlier snapshot,
+// from, and the later snapshot, to, sorted by remote name. Remotes whose
+// references did not change are omitted. Remotes are matched by name, so the
+// references of remotes that moved to another namespace, ex. into the attic,
+// are compared within each namespace.
+func (b *biome) DiffSnapshots(ctx context.Context, from, to string) ([]SnapshotDiff, error) {
+	before, err := b.readSnapshot(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	after, err := b.readSnapshot(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+
+	remotes := slices.Collect(maps.Keys(before.namespaces))
+	for name := range after.namespaces {
+		if _, ok := before.namespaces[name]; !ok {
+			remotes = append(remotes, name)
+		}
+	}
+	slices.Sort(remotes)
+
+	var diffs []SnapshotDiff
+	for _, remote := range remotes {
+		_, inOld := before.namespaces[remote]
+		_, inNew := after.namespaces[remote]
+		d := SnapshotDiff{
+			Remote:  remote,
+			Added:   !inOld,
+			Removed: !inNew,
+			OldHead: before.head(remote),
+			NewHead: after.head(remote),
+		}
+		oldRefs, newRefs := before.remoteRefs(remote), after.remoteRefs(remote)
+		for name, value := range oldRefs {
+			if newValue, ok := newRefs[name]; !ok {
+				d.RefsRemoved++
+			} else if newValue != value {
+				d.RefsUpdated++
+			}
+		}
+		for name := range newRefs {
+			if _, ok := oldRefs[name]; !ok {
+				d.RefsAdded++
+			}
+		}
+		if d.OldHead != "" && d.NewHead != "" && d.OldHead != d.NewHead {
+			d.Behind, d.Ahead, err = b.aheadBehind(ctx, d.OldHead, d.NewHead)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if d.Added || d.Removed || d.RefsAdded > 0 || d.RefsRemoved > 0 || d.RefsUpdated > 0 || d.OldHead != d.NewHead {
+			diffs = append(diffs, d)
+		}
+	}
+	return diffs, nil
+}
+
+// RestoreSnapshot resets the references of the biome's remotes to the values
+// recorded by the named snapshot, in a single reference transaction, and
+// returns the number of references that were created, updated, or deleted.
+// References of the snapshot's remotes, and of the biome's current remotes,
+// that the snapshot did not record are deleted. Every object recorded by the
+// snapshot must still be present in the biome.
+func (b *biome) RestoreSnapshot(ctx context.Context, name string) (int, error) {
+	state, err := b.readSnapshot(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	remotes, err := b.Remotes(ctx, AllRemoteCategories...)
+	if err != nil {
+		return 0, err
+	}
+	namespaces := slices.Collect(maps.Values(state.namespaces))
+	for _, r := range remotes {
+		namespaces = append(namespaces, r.RefNamespace())
+	}
+	slices.Sort(namespaces)
+	refs, err := b.listRefs(ctx, slices.Compact(namespaces))
+	if err != nil {
+		return 0, err
+	}
+
+	var objects []string
+	for _, value := range state.refs {
+		if !strings.HasPrefix(value, "ref: ") {
+			objects = append(objects, value)
+		}
+	}
+	missing, err := b.missingObjects(ctx, objects)
+	if err != nil {
+		return 0, err
+	}
+	if len(missing) > 0 {
+		return 0, fmt.Errorf("%w: %d objects of snapshot %q, ex. %s", errObjectsMissing, len(missing), name, missing[0])
+	}
+
+	var changed int
+	w, err := b.updateRefs(ctx)
+	if err != nil {
+		return 0, err
+	}
+	current := make(map[string]string)
+	for _, r := range refs {
+		value := r.ObjectName
+		if r.Symref != "" {
+			value = "ref: " + r.Symref
+		}
+		current[r.Name] = value
+		if _, ok := state.refs[r.Name]; ok {
+			continue
+		}
+		changed++
+		if r.Symref != "" {
+			_, err = fmt.Fprintf(w, "option no-deref\nsymref-delete %s\n", r.Name)
+		} else {
+			_, err = fmt.Fprintf(w, "delete %s %s\n", r.Name, r.ObjectName)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("could not delete %s: %w", r.Name, err)
+		}
+	}
+	for _, ref := range slices.Sorted(maps.Keys(state.refs)) {
+		value, old := state.refs[ref], current[ref]
+		if value == old {
+			continue
+		}
+		changed++
+		if target, ok := strings.CutPrefix(value, "ref: "); ok {
+			_, err = fmt.Fprintf(w, "symref-update %s %s\n", ref, target)
+		} else if old == "" {
+			_, err = fmt.Fprintf(w, "create %s %s\n", ref, value)
+		} else if strings.HasPrefix(old, "ref: ") {
+			_, err = fmt.Fprintf(w, "option no-deref\nupdate %s %s\n", ref, value)
+		} else {
+			_, err = fmt.Fprintf(w, "update %s %s %s\n", ref, value, old)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("could not restore %s: %w", ref, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return 0, err
+	}
+	return changed, nil
+}
+
+// missingObjects returns the given objects that are not present in the
+// biome.
+func (b *biome) missingObjects(ctx context.Context, objects []string) ([]string, error) {
+	if len(objects) == 0 {
+		return nil, nil
+	}
+	var stdin bytes.Buffer
+	for _, object := range objects {
+		fmt.Fprintln(&stdin, object)
+	}
+	out, err := b.gitStdin(ctx, &stdin, "cat-file", "--batch-check=%(objectname)")
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	for _, line := range strings.Split(out, "\n") {
+		if object, ok := strings.CutSuffix(line, " missing"); ok {
+			missing = append(missing, object)
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,354 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_Snapshots(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		cfg.Section(section).AddOption(ownersOpt, github_com_orirawlings.String())
+		cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, barRemote.Name)
+		return true, nil
+	}))
+	commitID := createCommitFor(t, ctx, path, []string{
+		"refs/remotes/github.com/orirawlings/bar/heads/main",
+		"refs/heads/main",
+	})
+	testutil.Execute(t, "git", "-C", path, "symbolic-ref", barRemote.Head(), "refs/remotes/github.com/orirawlings/bar/heads/main")
+
+	if _, err := b.CreateSnapshot(ctx, "bad..name"); !errors.Is(err, errInvalidSnapshotName) {
+		t.Errorf("expected %v, was %v", errInvalidSnapshotName, err)
+	}
+
+	s, err := b.CreateSnapshot(ctx, "before")
+	testutil.Check(t, err)
+	if s.Name != "before" || s.References != 2 || s.Time.IsZero() {
+		t.Errorf("unexpected snapshot: %+v", s)
+	}
+	expected := "ref: refs/remotes/github.com/orirawlings/bar/heads/main\trefs/remotes/github.com/orirawlings/bar/HEAD\n" +
+		commitID + "\trefs/remotes/github.com/orirawlings/bar/heads/main"
+	if refs := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "show", s.Ref()+":"+snapshotRefsFile)); refs != expected {
+		t.Errorf("unexpected snapshot references:\nwanted %q\nwas    %q", expected, refs)
+	}
+	if parents := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "rev-parse", s.Ref()+"^@")); parents != commitID {
+		t.Errorf("expected snapshot to pin %s, was %q", commitID, parents)
+	}
+
+	if _, err := b.CreateSnapshot(ctx, "before"); !errors.Is(err, errSnapshotExists) {
+		t.Errorf("expected %v, was %v", errSnapshotExists, err)
+	}
+
+	// move the remote's branch on, the snapshot keeps the old commit
+	testutil.Execute(t, "git", "-C", path, "update-ref", "-d", "refs/remotes/github.com/orirawlings/bar/heads/main")
+	after, err := b.CreateSnapshot(ctx, "after")
+	testutil.Check(t, err)
+	if after.References != 1 {
+		t.Errorf("expected 1 reference in snapshot, was %d", after.References)
+	}
+	if err := exec.Command("git", "-C", path, "merge-base", "--is-ancestor", commitID, s.Ref()).Run(); err != nil {
+		t.Errorf("expected %s to be reachable from snapshot: %v", commitID, err)
+	}
+
+	snapshots, err := b.Snapshots(ctx)
+	testutil.Check(t, err)
+	var names []string
+	for _, s := range snapshots {
+		names = append(names, s.Name)
+	}
+	if got := strings.Join(names, ","); got != "after,before" {
+		t.Errorf("unexpected snapshots: %s", got)
+	}
+
+	testutil.Check(t, b.DeleteSnapshot(ctx, "after"))
+	if err := b.DeleteSnapshot(ctx, "after"); !errors.Is(err, errSnapshotNotFound) {
+		t.Errorf("expected %v, was %v", errSnapshotNotFound, err)
+	}
+	snapshots, err = b.Snapshots(ctx)
+	testutil.Check(t, err)
+	if len(snapshots) != 1 || snapshots[0].Name != "before" {
+		t.Errorf("unexpected snapshots after delete: %+v", snapshots)
+	}
+}
+
+func TestBiome_CreateSnapshot_fanOut(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		cfg.Section(section).AddOption(ownersOpt, github_com_orirawlings.String())
+		cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, barRemote.Name)
+		return true, nil
+	}))
+
+	// distinct commits on more branches than one commit may have parents
+	branches := snapshotFanOut + 10
+	var stream strings.Builder
+	for i := range branches {
+		message := fmt.Sprintf("commit %d", i)
+		fmt.Fprintf(&stream, "commit %sheads/b%d\ncommitter C <c@example.com> 0 +0000\ndata %d\n%s\n", barRemote.RefNamespace(), i, len(message), message)
+	}
+	cmd := exec.Command("git", "-C", path, "fast-import", "--quiet")
+	cmd.Stdin = strings.NewReader(stream.String())
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("could not %q: %v: %s", cmd, err, out)
+	}
+
+	s, err := b.CreateSnapshot(ctx, "big")
+	testutil.Check(t, err)
+	if s.References != branches {
+		t.Errorf("expected %d references in snapshot, was %d", branches, s.References)
+	}
+	if pins := strings.Fields(testutil.Execute(t, "git", "-C", path, "rev-parse", s.Ref()+"^@")); len(pins) != 2 {
+		t.Errorf("expected snapshot to have 2 intermediate parents, was %d", len(pins))
+	}
+	if n := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "rev-list", "--count", "--max-parents=0", s.Ref())); n != fmt.Sprint(branches) {
+		t.Errorf("expected %d commits to be reachable from snapshot, was %s", branches, n)
+	}
+}
+
+func TestBiome_DiffSnapshots(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		cfg.Section(section).AddOption(ownersOpt, github_com_orirawlings.String())
+		cfg.Section(section).AddOption(ownersOpt, github_com_cli.String())
+		cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, barRemote.Name)
+		cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, githubCLICLIRemote.Name)
+		cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, headlessRemote.Name)
+		return true, nil
+	}))
+	root := commitTree(t, path, "root")
+	base := commitTree(t, path, "base", root)
+	setRefs := func(refs map[string]string) {
+		t.Helper()
+		for ref, value := range refs {
+			if target, ok := strings.CutPrefix(value, "ref: "); ok {
+				testutil.Execute(t, "git", "-C", path, "symbolic-ref", ref, target)
+			} else if value == "" {
+				testutil.Execute(t, "git", "-C", path, "update-ref", "-d", ref)
+			} else {
+				testutil.Execute(t, "git", "-C", path, "update-ref", ref, value)
+			}
+		}
+	}
+	bar := barRemote.RefNamespace()
+	cli := githubCLICLIRemote.RefNamespace()
+	setRefs(map[string]string{
+		bar + "heads/main":  base,
+		bar + "heads/stale": base,
+		bar + "HEAD":        "ref: " + bar + "heads/main",
+		cli + "heads/trunk": base,
+		cli + "HEAD":        "ref: " + cli + "heads/trunk",
+		headlessRemote.RefNamespace() + "heads/main": base,
+	})
+	_, err := b.CreateSnapshot(ctx, "a")
+	testutil.Check(t, err)
+
+	// bar moves on, gains a branch, and loses a branch; cli is force-pushed;
+	// headless is unchanged; archived is added
+	next := commitTree(t, path, "next", base)
+	rewritten := commitTree(t, path, "rewritten", root)
+	setRefs(map[string]string{
+		bar + "heads/main":    next,
+		bar + "heads/feature": next,
+		bar + "heads/stale":   "",
+		cli + "heads/trunk":   rewritten,
+	})
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		cfg.Section(section).Subsection(remotesSubsection).AddOption(archivedOpt, archivedRemote.Name)
+		return true, nil
+	}))
+	_, err = b.CreateSnapshot(ctx, "b")
+	testutil.Check(t, err)
+
+	diffs, err := b.DiffSnapshots(ctx, "a", "b")
+	testutil.Check(t, err)
+	expected := []SnapshotDiff{
+		{
+			Remote:      githubCLICLIRemote.Name,
+			RefsUpdated: 1,
+			OldHead:     base,
+			NewHead:     rewritten,
+			Ahead:       1,
+			Behind:      1,
+		},
+		{
+			Remote: archivedRemote.Name,
+			Added:  true,
+		},
+		{
+			Remote:      barRemote.Name,
+			RefsAdded:   1,
+			RefsRemoved: 1,
+			RefsUpdated: 1,
+			OldHead:     base,
+			NewHead:     next,
+			Ahead:       1,
+		},
+	}
+	if !reflect.DeepEqual(diffs, expected) {
+		t.Errorf("unexpected snapshot diff:\nwanted %+v\nwas    %+v", expected, diffs)
+	}
+	if !diffs[0].ForcePushed() || diffs[2].ForcePushed() {
+		t.Errorf("unexpected force-push detection: %+v", diffs)
+	}
+
+	if _, err := b.DiffSnapshots(ctx, "a", "missing"); !errors.Is(err, errSnapshotNotFound) {
+		t.Errorf("expected %v, was %v", errSnapshotNotFound, err)
+	}
+}
+
+func TestBiome_RestoreSnapshot(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		cfg.Section(section).AddOption(ownersOpt, github_com_orirawlings.String())
+		cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, barRemote.Name)
+		return true, nil
+	}))
+	bar := barRemote.RefNamespace()
+	base := commitTree(t, path, "base")
+	testutil.Execute(t, "git", "-C", path, "update-ref", bar+"heads/main", base)
+	testutil.Execute(t, "git", "-C", path, "update-ref", bar+"heads/stale", base)
+	_, err := b.CreateSnapshot(ctx, "a")
+	testutil.Check(t, err)
+	listRefs := func() string {
+		t.Helper()
+		return testutil.Execute(t, "git", "-C", path, "for-each-ref", "--format=%(objectname) %(refname)", "refs/remotes/")
+	}
+	before := listRefs()
+
+	// fetches move the remotes' refs on
+	next := commitTree(t, path, "next", base)
+	testutil.Execute(t, "git", "-C", path, "update-ref", bar+"heads/main", next)
+	testutil.Execute(t, "git", "-C", path, "update-ref", bar+"heads/feature", next)
+	testutil.Execute(t, "git", "-C", path, "update-ref", "-d", bar+"heads/stale")
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, headlessRemote.Name)
+		return true, nil
+	}))
+	testutil.Execute(t, "git", "-C", path, "update-ref", headlessRemote.RefNamespace()+"heads/main", next)
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/heads/main", next)
+
+	changed, err := b.RestoreSnapshot(ctx, "a")
+	testutil.Check(t, err)
+	if changed != 4 {
+		t.Errorf("expected 4 references to change, was %d", changed)
+	}
+	if after := listRefs(); after != before {
+		t.Errorf("unexpected references after restore:\nwanted %s\nwas    %s", before, after)
+	}
+	if ref := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "rev-parse", "refs/heads/main")); ref != next {
+		t.Errorf("expected references outside of remotes to be kept, was %s", ref)
+	}
+
+	// restoring again changes nothing
+	changed, err = b.RestoreSnapshot(ctx, "a")
+	testutil.Check(t, err)
+	if changed != 0 {
+		t.Errorf("expected no references to change, was %d", changed)
+	}
+}
+
+func TestBiome_RestoreSnapshot_objectsMissing(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		cfg.Section(section).AddOption(ownersOpt, github_com_orirawlings.String())
+		cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, barRemote.Name)
+		return true, nil
+	}))
+
+	// a tag of a blob is recorded, but not kept, by a snapshot
+	blob := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "hash-object", "-w", "--stdin"))
+	testutil.Execute(t, "git", "-C", path, "update-ref", barRemote.RefNamespace()+"tags/key", blob)
+	_, err := b.CreateSnapshot(ctx, "a")
+	testutil.Check(t, err)
+	testutil.Execute(t, "git", "-C", path, "update-ref", "-d", barRemote.RefNamespace()+"tags/key")
+	testutil.Execute(t, "git", "-C", path, "prune", "--expire=now")
+
+	if _, err := b.RestoreSnapshot(ctx, "a"); !errors.Is(err, errObjectsMissing) {
+		t.Errorf("expected %v, was %v", errObjectsMissing, err)
+	}
+}
+
+func TestBiome_CreateSnapshotAt(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		cfg.Section(section).AddOption(ownersOpt, github_com_orirawlings.String())
+		for _, r := range []Remote{barRemote, headlessRemote} {
+			cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, r.Name)
+		}
+		return true, nil
+	}))
+	bar := barRemote.RefNamespace()
+	pinned := commitTree(t, path, "pinned")
+	next := commitTree(t, path, "next", pinned)
+	testutil.Execute(t, "git", "-C", path, "update-ref", bar+"heads/main", next)
+	testutil.Execute(t, "git", "-C", path, "update-ref", bar+"heads/feature", next)
+	testutil.Execute(t, "git", "-C", path, "symbolic-ref", barRemote.Head(), bar+"heads/main")
+
+	if _, err := b.CreateSnapshotAt(ctx, "manifest", map[string]string{headlessRemote.Name: pinned}); !errors.Is(err, errHeadNotFetched) {
+		t.Errorf("expected error for remote without HEAD, was %v", err)
+	}
+	if _, err := b.CreateSnapshotAt(ctx, "manifest", map[string]string{"github.com/orirawlings/missing": pinned}); !errors.Is(err, errRemoteNotFound) {
+		t.Errorf("expected error for unknown remote, was %v", err)
+	}
+	missing := strings.Repeat("0", len(pinned))
+	if _, err := b.CreateSnapshotAt(ctx, "manifest", map[string]string{barRemote.Name: missing}); !errors.Is(err, errObjectsMissing) {
+		t.Errorf("expected error for missing commit, was %v", err)
+	}
+
+	s, err := b.CreateSnapshotAt(ctx, "manifest", map[string]string{barRemote.Name: pinned})
+	testutil.Check(t, err)
+	if s.References != 2 {
+		t.Errorf("expected 2 references in snapshot, was %d", s.References)
+	}
+	refs := testutil.Execute(t, "git", "-C", path, "show", s.Ref()+":refs")
+	if expected := fmt.Sprintf("ref: %sheads/main\t%sHEAD\n%s\t%sheads/main\n", bar, bar, pinned, bar); refs != expected {
+		t.Errorf("unexpected references of snapshot:\nwanted %q\nwas    %q", expected, refs)
+	}
+
+	// restoring the snapshot checks the pinned commit out as the default
+	// branch
+	_, err = b.RestoreSnapshot(ctx, "manifest")
+	testutil.Check(t, err)
+	if ref := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "rev-parse", bar+"HEAD")); ref != pinned {
+		t.Errorf("expected HEAD at pinned commit after restore, was %s", ref)
+	}
+}
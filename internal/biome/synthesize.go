@@ -0,0 +1,169 @@
+package biome
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+)
+
+const (
+	// synthesizeHeadsKey is the git config key of the setting that
+	// synthesizes HEAD references for remotes without a default branch.
+	synthesizeHeadsKey = "biome.heads.synthesize"
+
+	// synthesizedOpt is a git config option key which lists GitHub remote
+	// repositories whose HEAD reference was synthesized by the biome, rather
+	// than set from the repository's default branch. This is orthogonal to
+	// the remote categories.
+	synthesizedOpt = "synthesized"
+)
+
+// synthesizeHeadsEnabled reports whether HEAD references are synthesized for
+// remotes without a default branch, from a loaded config.
+func synthesizeHeadsEnabled(cfg *config.Config) bool {
+	value, _ := getConfigValue(cfg, synthesizeHeadsKey)
+	enabled, _ := ParseBool(value)
+	return enabled
+}
+
+// guessHead returns the branch that most likely serves as the default branch
+// of a remote, given the references in its reference namespace: main, then
+// master, then the only branch of the remote. If there is no such branch,
+// false is returned.
+func guessHead(namespace string, refs []storedRef) (string, bool) {
+	var branches []string
+	for _, ref := range refs {
+		if strings.HasPrefix(ref.Name, namespace+"heads/") && ref.Symref == "" {
+			branches = append(branches, ref.Name)
+		}
+	}
+	for _, branch := range []string{"main", "master"} {
+		if slices.Contains(branches, namespace+"heads/"+branch) {
+			return namespace + "heads/" + branch, true
+		}
+	}
+	if len(branches) == 1 {
+		return branches[0], true
+	}
+	return "", false
+}
+
+// SynthesizeHeads sets a HEAD reference for each fetchable remote that has no
+// default branch in GitHub, ex. because the repository's default branch was
+// never set, pointing at the branch that most likely serves as its default
+// branch, see [guessHead]. Synthesized HEAD references are recorded in the
+// biome's remote metadata, so they are evaluated again each time remotes are
+// updated or fetched, and are replaced by the repository's default branch as
+// soon as GitHub reports one. Nothing is done unless the biome.heads.synthesize
+// setting is enabled. The remotes whose HEAD reference was synthesized are
+// returned.
+func (b *biome) SynthesizeHeads(ctx context.Context) ([]Remote, error) {
+	var enabled bool
+	synthesized := make(map[string]bool)
+	pending := make(map[string]bool)
+	if err := b.readConfig(ctx, func(ctx context.Context, cfg *config.Config) error {
+		enabled = synthesizeHeadsEnabled(cfg)
+		metadata := cfg.Section(section).Subsection(remotesSubsection)
+		for _, name := range metadata.OptionAll(synthesizedOpt) {
+			synthesized[name] = true
+		}
+		for _, p := range metadata.OptionAll(pendingHeadOpt) {
+			name, _, _ := strings.Cut(p, " ")
+			pending[name] = true
+		}
+		return nil
+	}); err != nil || !enabled {
+		return nil, err
+	}
+
+	remotes, err := b.Remotes(ctx, FetchableRemoteCategories...)
+	if err != nil {
+		return nil, err
+	}
+	var heads []string
+	for _, r := range remotes {
+		heads = append(heads, r.Head())
+	}
+	refs, err := b.listRefs(ctx, heads)
+	if err != nil {
+		return nil, err
+	}
+	targets := make(map[string]string)
+	for _, ref := range refs {
+		targets[ref.Name] = ref.Symref
+	}
+
+	// remotes without a HEAD reference have no default branch, unless their
+	// default branch was not fetched yet, since HEAD references are set for
+	// every other remote when remotes are updated
+	var candidates []Remote
+	var namespaces []string
+	for _, r := range remotes {
+		if _, ok := targets[r.Head()]; !ok && !pending[r.Name] || synthesized[r.Name] {
+			candidates = append(candidates, r)
+			namespaces = append(namespaces, r.RefNamespace()+"heads/")
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	refs, err = b.listRefs(ctx, namespaces)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Remote
+	if err := b.transactRefs(ctx, func(w io.Writer) error {
+		result = nil
+		for _, r := range candidates {
+			branch, ok := guessHead(r.RefNamespace(), refs)
+			switch {
+			case ok:
+				result = append(result, r)
+				if targets[r.Head()] == branch {
+					continue
+				}
+				if _, err := fmt.Fprintf(w, "option no-deref\nsymref-update %s %s\n", r.Head(), branch); err != nil {
+					return fmt.Errorf("could not synthesize HEAD ref for %s: %w", r.Name, err)
+				}
+			case targets[r.Head()] != "":
+				// the synthesized HEAD's branch is gone
+				if _, err := fmt.Fprintf(w, "option no-deref\nsymref-delete %s\n", r.Head()); err != nil {
+					return fmt.Errorf("could not delete HEAD ref for %s: %w", r.Name, err)
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		metadata := cfg.Section(section).Subsection(remotesSubsection)
+		previous := metadata.OptionAll(synthesizedOpt)
+		var names []string
+		for _, name := range previous {
+			if !slices.ContainsFunc(candidates, func(r Remote) bool { return r.Name == name }) {
+				names = append(names, name)
+			}
+		}
+		for _, r := range result {
+			names = append(names, r.Name)
+		}
+		if slices.Equal(previous, names) {
+			return false, nil
+		}
+		metadata.RemoveOption(synthesizedOpt)
+		for _, name := range names {
+			metadata.AddOption(synthesizedOpt, name)
+		}
+		return true, nil
+	}); err != nil {
+		return nil, fmt.Errorf("could not record synthesized HEAD references: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,107 @@
+package biome
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_SynthesizeHeads(t *testing.T) {
+	ctx := context.Background()
+	headlessCfg := remoteConfig{
+		Remote: headlessRemote,
+		Head:   "refs/remotes/github.com/orirawlings/headless/heads/develop",
+	}
+	b, commits := absorbable(t, ctx, []Owner{github_com_orirawlings}, barRemoteCfg, headlessCfg)
+
+	// the headless remote's repository has no default branch
+	testutil.Execute(t, "git", "-C", b.path, "symbolic-ref", "--delete", headlessRemote.Head())
+
+	// nothing is synthesized unless enabled
+	synthesized, err := b.SynthesizeHeads(ctx)
+	testutil.Check(t, err)
+	if len(synthesized) > 0 {
+		t.Errorf("expected no synthesized HEADs while disabled, was %v", synthesized)
+	}
+	testutil.ExpectError(t, b.SetSetting(ctx, synthesizeHeadsKey, "sometimes"))
+	testutil.Check(t, b.SetSetting(ctx, synthesizeHeadsKey, "true"))
+
+	expectHead := func(t *testing.T, expected string) {
+		t.Helper()
+		head := strings.TrimSpace(testutil.Execute(t, "git", "-C", b.path, "for-each-ref", "--format=%(symref)", headlessRemote.Head()))
+		if head != expected {
+			t.Errorf("expected %s to refer to %q, was %q", headlessRemote.Head(), expected, head)
+		}
+	}
+	synthesize := func(t *testing.T, expected ...Remote) {
+		t.Helper()
+		synthesized, err := b.SynthesizeHeads(ctx)
+		testutil.Check(t, err)
+		if !slices.Equal(synthesized, expected) {
+			t.Errorf("expected synthesized HEADs of %v, was %v", expected, synthesized)
+		}
+	}
+
+	// the only branch
+	synthesize(t, headlessRemote)
+	expectHead(t, headlessCfg.Head)
+	assertGitConfig(t, b.path, "biome.remotes.synthesized", headlessRemote.Name)
+
+	// master, then main, are preferred
+	master := "refs/remotes/github.com/orirawlings/headless/heads/master"
+	main := "refs/remotes/github.com/orirawlings/headless/heads/main"
+	testutil.Execute(t, "git", "-C", b.path, "update-ref", master, commits[headlessRemote.Name])
+	synthesize(t, headlessRemote)
+	expectHead(t, master)
+	testutil.Execute(t, "git", "-C", b.path, "update-ref", main, commits[headlessRemote.Name])
+	synthesize(t, headlessRemote)
+	expectHead(t, main)
+
+	// without a likely default branch, the synthesized HEAD is removed
+	testutil.Execute(t, "git", "-C", b.path, "update-ref", "-d", main)
+	testutil.Execute(t, "git", "-C", b.path, "update-ref", "-d", master)
+	testutil.Execute(t, "git", "-C", b.path, "update-ref", "refs/remotes/github.com/orirawlings/headless/heads/feature", commits[headlessRemote.Name])
+	synthesize(t)
+	expectHead(t, "")
+	assertConfigNotSet(t, b.path, "biome.remotes.synthesized")
+
+	// remotes with a default branch are untouched
+	if head := strings.TrimSpace(testutil.Execute(t, "git", "-C", b.path, "symbolic-ref", barRemote.Head())); head != barRemoteCfg.Head {
+		t.Errorf("expected %s to refer to %s, was %s", barRemote.Head(), barRemoteCfg.Head, head)
+	}
+}
+
+func TestGuessHead(t *testing.T) {
+	namespace := "refs/remotes/github.com/orirawlings/headless/"
+	for _, tc := range []struct {
+		name     string
+		branches []string
+		expected string
+	}{
+		{"none", nil, ""},
+		{"only", []string{"develop"}, "develop"},
+		{"several", []string{"develop", "feature"}, ""},
+		{"master", []string{"develop", "master"}, "master"},
+		{"main", []string{"main", "master"}, "main"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			refs := []storedRef{
+				{Name: namespace + "tags/v1.0.0", ObjectName: "abc"},
+				{Name: "refs/remotes/github.com/orirawlings/bar/heads/main", ObjectName: "abc"},
+			}
+			for _, branch := range tc.branches {
+				refs = append(refs, storedRef{Name: namespace + "heads/" + branch, ObjectName: "abc"})
+			}
+			branch, ok := guessHead(namespace, refs)
+			if expected := tc.expected != ""; ok != expected {
+				t.Fatalf("expected a guess %t, was %t", expected, ok)
+			}
+			if tc.expected != "" && branch != namespace+"heads/"+tc.expected {
+				t.Errorf("expected %s, was %s", namespace+"heads/"+tc.expected, branch)
+			}
+		})
+	}
+}
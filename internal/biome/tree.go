@@ -0,0 +1,135 @@
+package biome
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// errRefNotFound indicates that a reference does not exist in the namespace
+// of a remote.
+var errRefNotFound = errors.New("reference not found for remote")
+
+// TreeEntry is an entry of a git tree, a file, directory, or submodule of a
+// remote's repository.
+type TreeEntry struct {
+
+	// Mode is the entry's octal file mode, ex. 100644 for a regular file.
+	Mode string
+
+	// Type is the type of the entry's object: blob, tree, or commit for
+	// submodules.
+	Type string
+
+	// Object is the object ID of the entry.
+	Object string
+
+	// Path is the path of the entry, relative to the root of the
+	// repository.
+	Path string
+}
+
+// Tree lists the entries of the tree at the named remote's HEAD, or at the
+// given reference of the remote, ex. "main", "heads/main", or "tags/v1.0",
+// without checking it out. As with git ls-tree, a path lists the matching
+// entry, while a path ending with a slash lists the entries of the
+// directory. If recursive is true, the entries of subdirectories are listed
+// in place of the subdirectories.
+func (b *biome) Tree(ctx context.Context, remote, ref, path string, recursive bool) ([]TreeEntry, error) {
+	commit, err := b.resolveRemoteRef(ctx, remote, ref)
+	if err != nil {
+		return nil, err
+	}
+	return b.lsTree(ctx, commit, path, recursive)
+}
+
+// lsTree lists the entries of the tree of the given commit, as [biome.Tree]
+// does.
+func (b *biome) lsTree(ctx context.Context, commit, path string, recursive bool) ([]TreeEntry, error) {
+	args := []string{"-C", b.path, "ls-tree", "-z", "--full-tree"}
+	if recursive {
+		args = append(args, "-r")
+	}
+	args = append(args, commit)
+	if path != "" {
+		args = append(args, "--", path)
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not %q: %w: %s", cmd, err, stderr.String())
+	}
+	var entries []TreeEntry
+	for _, line := range strings.Split(string(out), "\x00") {
+		if line == "" {
+			continue
+		}
+		// <mode> SP <type> SP <object> TAB <path>
+		info, name, ok := strings.Cut(line, "\t")
+		fields := strings.Fields(info)
+		if !ok || len(fields) != 3 {
+			return nil, fmt.Errorf("could not parse tree entry %q", line)
+		}
+		entries = append(entries, TreeEntry{
+			Mode:   fields[0],
+			Type:   fields[1],
+			Object: fields[2],
+			Path:   name,
+		})
+	}
+	return entries, nil
+}
+
+// resolveRemoteRef returns the object ID that the given reference of the
+// named remote resolves to, or its HEAD reference if ref is empty. The
+// reference is looked up in the remote's namespace as given, then as a
+// branch, then as a tag.
+func (b *biome) resolveRemoteRef(ctx context.Context, remote, ref string) (string, error) {
+	if ref == "" {
+		heads, err := b.resolveHeads(ctx)
+		if err != nil {
+			return "", err
+		}
+		return headCommit(heads, remote)
+	}
+	remotes, err := b.Remotes(ctx, FetchableRemoteCategories...)
+	if err != nil {
+		return "", err
+	}
+	var namespace string
+	for _, r := range remotes {
+		if r.Name == remote {
+			namespace = r.RefNamespace()
+		}
+	}
+	if namespace == "" {
+		return "", fmt.Errorf("%w: %s", errRemoteNotFound, remote)
+	}
+	refs, err := b.listRefs(ctx, []string{namespace})
+	if err != nil {
+		return "", err
+	}
+	refsByName := make(map[string]storedRef)
+	for _, r := range refs {
+		refsByName[r.Name] = r
+	}
+	ref = strings.TrimPrefix(ref, "refs/")
+	for _, name := range []string{ref, "heads/" + ref, "tags/" + ref} {
+		r, ok := refsByName[namespace+name]
+		if !ok {
+			continue
+		}
+		if r.Symref != "" {
+			r = refsByName[r.Symref]
+		}
+		if r.ObjectName != "" {
+			return r.ObjectName, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s %s", errRefNotFound, remote, ref)
+}
@@ -0,0 +1,111 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_Tree(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		for _, r := range []Remote{barRemote, headlessRemote} {
+			cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, r.Name)
+		}
+		return true, nil
+	}))
+
+	file := filepath.Join(t.TempDir(), "file")
+	testutil.Check(t, os.WriteFile(file, []byte("hello\n"), 0o644))
+	blob := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "hash-object", "-w", file))
+	docs := mktree(t, path, fmt.Sprintf("100644 blob %s\tguide.md\n", blob))
+	root := mktree(t, path, fmt.Sprintf("100644 blob %s\tREADME.md\n040000 tree %s\tdocs\n", blob, docs))
+	empty := commitTree(t, path, "empty")
+	head := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "commit-tree", "-m", "files", root))
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/remotes/github.com/orirawlings/bar/heads/main", head)
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/remotes/github.com/orirawlings/bar/tags/v0", empty)
+	testutil.Execute(t, "git", "-C", path, "symbolic-ref", barRemote.Head(), "refs/remotes/github.com/orirawlings/bar/heads/main")
+
+	readme := TreeEntry{Mode: "100644", Type: "blob", Object: blob, Path: "README.md"}
+	guide := TreeEntry{Mode: "100644", Type: "blob", Object: blob, Path: "docs/guide.md"}
+	for _, tc := range []struct {
+		name      string
+		ref       string
+		path      string
+		recursive bool
+		expected  []TreeEntry
+	}{
+		{
+			name: "HEAD",
+			expected: []TreeEntry{
+				readme,
+				{Mode: "040000", Type: "tree", Object: docs, Path: "docs"},
+			},
+		},
+		{
+			name:      "recursive",
+			recursive: true,
+			expected:  []TreeEntry{readme, guide},
+		},
+		{
+			name:     "directory",
+			path:     "docs/",
+			expected: []TreeEntry{guide},
+		},
+		{
+			name: "branch",
+			ref:  "main",
+			path: "README.md",
+			expected: []TreeEntry{
+				readme,
+			},
+		},
+		{
+			name: "tag",
+			ref:  "tags/v0",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			entries, err := b.Tree(ctx, barRemote.Name, tc.ref, tc.path, tc.recursive)
+			testutil.Check(t, err)
+			if !reflect.DeepEqual(entries, tc.expected) {
+				t.Errorf("unexpected tree entries: wanted %+v, was %+v", tc.expected, entries)
+			}
+		})
+	}
+
+	if _, err := b.Tree(ctx, barRemote.Name, "missing", "", false); !errors.Is(err, errRefNotFound) {
+		t.Errorf("expected error for missing reference, was %v", err)
+	}
+	if _, err := b.Tree(ctx, headlessRemote.Name, "", "", false); !errors.Is(err, errHeadNotFetched) {
+		t.Errorf("expected error for unfetched remote, was %v", err)
+	}
+	if _, err := b.Tree(ctx, "github.com/orirawlings/missing", "main", "", false); !errors.Is(err, errRemoteNotFound) {
+		t.Errorf("expected error for unknown remote, was %v", err)
+	}
+}
+
+// mktree writes a tree object from entries in the format of git ls-tree,
+// returning its object ID.
+func mktree(t *testing.T, path, entries string) string {
+	t.Helper()
+	cmd := exec.Command("git", "-C", path, "mktree")
+	cmd.Stdin = strings.NewReader(entries)
+	out, err := cmd.Output()
+	testutil.Check(t, err)
+	return strings.TrimSpace(string(out))
+}
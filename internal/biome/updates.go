@@ -0,0 +1,202 @@
+package biome
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	graphql "github.com/cli/shurcooL-graphql"
+)
+
+// UpdateCheck compares the default branch of a remote's GitHub repository
+// with the remote's HEAD reference in the biome.
+type UpdateCheck struct {
+
+	// Remote is the name of the remote.
+	Remote string `json:"remote"`
+
+	// PushedAt is when GitHub last received a push to the repository.
+	PushedAt time.Time `json:"pushedAt"`
+
+	// Upstream is the object ID of the repository's default branch in
+	// GitHub, or empty if the repository has no default branch.
+	Upstream string `json:"upstream"`
+
+	// Local is the object ID that the remote's HEAD reference resolves to in
+	// the biome, or empty if the remote's default branch was not fetched.
+	Local string `json:"local"`
+}
+
+// Behind reports whether GitHub has a default branch for the remote that the
+// biome has not fetched.
+func (c UpdateCheck) Behind() bool {
+	return c.Upstream != "" && c.Upstream != c.Local
+}
+
+// updateCheckRepository is the little of a repository that GitHub is asked
+// for when checking for updates, so that checks stay cheap.
+type updateCheckRepository struct {
+	URL              string `graphql:"url"`
+	PushedAt         time.Time
+	DefaultBranchRef *struct {
+		Target struct {
+			OID string `graphql:"oid"`
+		}
+	}
+}
+
+func (r updateCheckRepository) check() UpdateCheck {
+	c := UpdateCheck{
+		Remote:   r.URL[8:],
+		PushedAt: r.PushedAt,
+	}
+	if r.DefaultBranchRef != nil {
+		c.Upstream = r.DefaultBranchRef.Target.OID
+	}
+	return c
+}
+
+// CheckUpdates asks GitHub for the default branch of the repository of each
+// fetchable remote of the biome's owners and viewers, without fetching
+// anything, and compares it with the remote's HEAD reference, sorted by
+// remote name. If owners are given, only their remotes are checked. Remotes
+// of anonymous hosts, see [Anonymous], are not checked, since the GraphQL API
+// is unavailable without a token.
+func (b *biome) CheckUpdates(ctx context.Context, owners ...Owner) ([]UpdateCheck, error) {
+	var viewers []Viewer
+	if err := b.readConfig(ctx, func(ctx context.Context, cfg *config.Config) error {
+		if len(owners) > 0 {
+			return nil
+		}
+		var err error
+		if owners, err = b.getOwners(cfg); err != nil {
+			return err
+		}
+		viewers, err = b.getViewers(cfg)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	heads, err := b.resolveHeads(ctx)
+	if err != nil {
+		return nil, err
+	}
+	checks := make(map[string]UpdateCheck)
+	add := func(repos []updateCheckRepository) {
+		for _, repo := range repos {
+			c := repo.check()
+			local, ok := heads[c.Remote]
+			if !ok {
+				// not a fetchable remote of the biome
+				continue
+			}
+			c.Local = local
+			checks[c.Remote] = c
+		}
+	}
+	for _, owner := range owners {
+		if Anonymous(owner.Host()) {
+			continue
+		}
+		repos, err := queryOwnerUpdates(ctx, owner)
+		if err != nil {
+			return nil, err
+		}
+		add(repos)
+	}
+	for _, viewer := range viewers {
+		if Anonymous(viewer.Host()) {
+			continue
+		}
+		repos, err := queryViewerUpdates(ctx, viewer)
+		if err != nil {
+			return nil, err
+		}
+		add(repos)
+	}
+
+	var result []UpdateCheck
+	for _, c := range checks {
+		result = append(result, c)
+	}
+	slices.SortFunc(result, func(a, b UpdateCheck) int {
+		return strings.Compare(a.Remote, b.Remote)
+	})
+	return result, nil
+}
+
+func queryOwnerUpdates(ctx context.Context, owner Owner) ([]updateCheckRepository, error) {
+	client, err := api.NewGraphQLClient(api.ClientOptions{
+		Host: owner.Host(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create API client: %s: %w", owner.Host(), err)
+	}
+	var query struct {
+		RepositoryOwner struct {
+			Repositories struct {
+				Nodes    []updateCheckRepository
+				PageInfo struct {
+					HasNextPage bool
+					EndCursor   string
+				}
+			} `graphql:"repositories(first: 100, after: $endCursor, affiliations: [OWNER])"`
+		} `graphql:"repositoryOwner(login: $owner)"`
+	}
+	variables := map[string]interface{}{
+		"owner":     graphql.String(owner.name),
+		"endCursor": (*graphql.String)(nil),
+	}
+	var repos []updateCheckRepository
+	for {
+		if err := client.QueryWithContext(ctx, "OwnerUpdates", &query, variables); err != nil {
+			return nil, fmt.Errorf("could not query updates for %s: %w", owner, err)
+		}
+		repos = append(repos, query.RepositoryOwner.Repositories.Nodes...)
+		if !query.RepositoryOwner.Repositories.PageInfo.HasNextPage {
+			return repos, nil
+		}
+		variables["endCursor"] = graphql.String(query.RepositoryOwner.Repositories.PageInfo.EndCursor)
+	}
+}
+
+func queryViewerUpdates(ctx context.Context, viewer Viewer) ([]updateCheckRepository, error) {
+	client, err := api.NewGraphQLClient(api.ClientOptions{
+		Host: viewer.Host(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create API client: %s: %w", viewer.Host(), err)
+	}
+	var query struct {
+		Viewer struct {
+			Repositories struct {
+				Nodes    []updateCheckRepository
+				PageInfo struct {
+					HasNextPage bool
+					EndCursor   string
+				}
+			} `graphql:"repositories(first: 100, after: $endCursor, affiliations: $affiliations)"`
+		}
+	}
+	variables := map[string]interface{}{
+		"affiliations": viewer.Affiliations(),
+		"endCursor":    (*graphql.String)(nil),
+	}
+	var repos []updateCheckRepository
+	for {
+		if err := client.QueryWithContext(ctx, "ViewerUpdates", &query, variables); err != nil {
+			return nil, fmt.Errorf("could not query updates for %s: %w", viewer, err)
+		}
+		repos = append(repos, query.Viewer.Repositories.Nodes...)
+		if !query.Viewer.Repositories.PageInfo.HasNextPage {
+			return repos, nil
+		}
+		variables["endCursor"] = graphql.String(query.Viewer.Repositories.PageInfo.EndCursor)
+	}
+}
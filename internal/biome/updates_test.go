@@ -0,0 +1,95 @@
+package biome
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestBiome_CheckUpdates(t *testing.T) {
+	ctx := context.Background()
+	stubGitHub(t)
+	headlessCfg := remoteConfig{
+		Remote: headlessRemote,
+		Head:   "refs/remotes/github.com/orirawlings/headless/heads/develop",
+	}
+	b, commits := absorbable(t, ctx, []Owner{github_com_orirawlings}, barRemoteCfg, headlessCfg, archivedRemoteCfg)
+
+	// the archived remote's default branch was not fetched yet
+	testutil.Execute(t, "git", "-C", b.path, "update-ref", "-d", archivedRemoteCfg.Head)
+
+	newer := commitTree(t, b.path, "newer commit of "+headlessRemote.Name)
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`{"query":"query OwnerUpdates($endCursor:String$owner:String!){repositoryOwner(login: $owner){repositories(first: 100, after: $endCursor, affiliations: [OWNER]){nodes{url,pushedAt,defaultBranchRef{target{oid}}},pageInfo{hasNextPage,endCursor}}}}","variables":{"endCursor":null,"owner":"orirawlings"}}`).
+		Reply(200).
+		JSON(fmt.Sprintf(`{"data":{"repositoryOwner":{"repositories":{"nodes":[
+			{"url":"https://github.com/orirawlings/bar","pushedAt":"2024-01-02T03:04:05Z","defaultBranchRef":{"target":{"oid":%q}}},
+			{"url":"https://github.com/orirawlings/headless","pushedAt":"2024-02-03T04:05:06Z","defaultBranchRef":{"target":{"oid":%q}}},
+			{"url":"https://github.com/orirawlings/locked","pushedAt":"2024-02-03T04:05:06Z","defaultBranchRef":{"target":{"oid":%q}}}
+		],"pageInfo":{"hasNextPage":true,"endCursor":"abc"}}}}}`, commits[barRemote.Name], newer, newer))
+	gock.New("https://api.github.com").
+		Post("/graphql").
+		BodyString(`{"query":"query OwnerUpdates($endCursor:String!$owner:String!){repositoryOwner(login: $owner){repositories(first: 100, after: $endCursor, affiliations: [OWNER]){nodes{url,pushedAt,defaultBranchRef{target{oid}}},pageInfo{hasNextPage,endCursor}}}}","variables":{"endCursor":"abc","owner":"orirawlings"}}`).
+		Reply(200).
+		JSON(fmt.Sprintf(`{"data":{"repositoryOwner":{"repositories":{"nodes":[
+			{"url":"https://github.com/orirawlings/archived","pushedAt":"2024-03-04T05:06:07Z","defaultBranchRef":{"target":{"oid":%q}}}
+		],"pageInfo":{"hasNextPage":false,"endCursor":"def"}}}}}`, newer))
+
+	checks, err := b.CheckUpdates(ctx)
+	testutil.Check(t, err)
+	expected := []UpdateCheck{
+		{
+			Remote:   archivedRemote.Name,
+			PushedAt: time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC),
+			Upstream: newer,
+		},
+		{
+			Remote:   barRemote.Name,
+			PushedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			Upstream: commits[barRemote.Name],
+			Local:    commits[barRemote.Name],
+		},
+		{
+			Remote:   headlessRemote.Name,
+			PushedAt: time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC),
+			Upstream: newer,
+			Local:    commits[headlessRemote.Name],
+		},
+	}
+	if !slices.EqualFunc(checks, expected, func(a, b UpdateCheck) bool {
+		return a.Remote == b.Remote && a.PushedAt.Equal(b.PushedAt) && a.Upstream == b.Upstream && a.Local == b.Local
+	}) {
+		t.Errorf("expected %v, was %v", expected, checks)
+	}
+	for _, c := range checks {
+		if behind := c.Remote != barRemote.Name; c.Behind() != behind {
+			t.Errorf("expected %s behind %t, was %t", c.Remote, behind, c.Behind())
+		}
+	}
+}
+
+func TestUpdateCheck_Behind(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		check    UpdateCheck
+		expected bool
+	}{
+		{"up to date", UpdateCheck{Upstream: "abc", Local: "abc"}, false},
+		{"behind", UpdateCheck{Upstream: "def", Local: "abc"}, true},
+		{"not fetched", UpdateCheck{Upstream: "abc"}, true},
+		{"no default branch", UpdateCheck{Local: "abc"}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if behind := tc.check.Behind(); behind != tc.expected {
+				t.Errorf("expected %t, was %t", tc.expected, behind)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package biome
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+)
+
+// OwnerDiskUsage attributes the biome's object storage to one of its owners.
+// Objects are often shared between owners, ex. by forks, so the sizes are
+// approximate, and the sizes of all owners add up to more than the biome's
+// size.
+type OwnerDiskUsage struct {
+
+	// Owner whose remotes' objects are measured.
+	Owner Owner
+
+	// Remotes is the number of the owner's remotes that are configured as
+	// git remotes.
+	Remotes int
+
+	// Bytes estimates the on-disk size of the objects reachable from the
+	// references of the owner's remotes.
+	Bytes int64
+
+	// ExclusiveBytes estimates the on-disk size of the objects that are
+	// reachable from the references of the owner's remotes, but from no
+	// other reference. This is about how much disk would be reclaimed if the
+	// owner were removed from the biome.
+	ExclusiveBytes int64
+}
+
+// DiskUsage attributes the biome's object storage to each of its owners,
+// sorted by the size of the objects reachable from the owner's remotes,
+// largest first.
+func (b *biome) DiskUsage(ctx context.Context) ([]OwnerDiskUsage, error) {
+	owners, err := b.Owners(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var remotes map[Owner][]Remote
+	if err := b.readConfig(ctx, func(ctx context.Context, cfg *config.Config) error {
+		remotes = ownerRemotes(cfg, owners)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	var usage []OwnerDiskUsage
+	for _, owner := range owners {
+		u := OwnerDiskUsage{
+			Owner:   owner,
+			Remotes: len(remotes[owner]),
+		}
+		if u.Bytes, err = b.diskUsage(ctx, remotes[owner], false); err != nil {
+			return nil, fmt.Errorf("could not measure disk usage of %s: %w", owner, err)
+		}
+		if u.ExclusiveBytes, err = b.diskUsage(ctx, remotes[owner], true); err != nil {
+			return nil, fmt.Errorf("could not measure disk usage of %s: %w", owner, err)
+		}
+		usage = append(usage, u)
+	}
+	slices.SortStableFunc(usage, func(a, b OwnerDiskUsage) int {
+		return cmp.Compare(b.Bytes, a.Bytes)
+	})
+	return usage, nil
+}
+
+// ownerRemotes returns the remotes of each of the given owners that are
+// configured as git remotes, from a loaded config.
+func ownerRemotes(cfg *config.Config, owners []Owner) map[Owner][]Remote {
+	remotes := make(map[Owner][]Remote)
+	for _, opt := range cfg.Section("remotes").Options {
+		for _, owner := range owners {
+			if opt.Key == owner.RemoteGroup() && cfg.Section("remote").HasSubsection(opt.Value) {
+				remotes[owner] = append(remotes[owner], Remote{
+					Name:        opt.Value,
+					refTemplate: configuredRefTemplate(cfg, opt.Value),
+				})
+			}
+		}
+	}
+	return remotes
+}
+
+// RemoteDiskUsage attributes the biome's object storage to one of its
+// remotes.
+type RemoteDiskUsage struct {
+
+	// Remote is the name of the remote whose objects are measured.
+	Remote string
+
+	// Bytes estimates the on-disk size of the objects reachable from the
+	// remote's references.
+	Bytes int64
+
+	// ExclusiveBytes estimates the on-disk size of the objects that are
+	// reachable from the remote's references, but from no other reference.
+	ExclusiveBytes int64
+}
+
+// SharedBytes estimates the on-disk size of the remote's objects that are
+// shared with other references of the biome.
+func (u RemoteDiskUsage) SharedBytes() int64 {
+	return u.Bytes - u.ExclusiveBytes
+}
+
+// Deduplication estimates how much storage the biome saves by storing the
+// objects that its remotes share, ex. forks and mirrors, only once, compared
+// to an independent clone of each remote. Independent clones would be packed
+// separately, so this is only an approximation.
+type Deduplication struct {
+
+	// Bytes estimates the on-disk size of the objects reachable from the
+	// references of all of the biome's remotes.
+	Bytes int64
+
+	// IndependentBytes estimates the on-disk size of independent clones of
+	// each of the biome's remotes.
+	IndependentBytes int64
+
+	// Remotes is the disk usage of each of the biome's remotes, sorted by the
+	// size of the objects that they share with no other reference, largest
+	// first. Remotes that share little benefit little from the biome, so they
+	// are the first candidates for exclusion.
+	Remotes []RemoteDiskUsage
+}
+
+// SavedBytes estimates the storage saved by sharing objects between the
+// biome's remotes.
+func (d Deduplication) SavedBytes() int64 {
+	return d.IndependentBytes - d.Bytes
+}
+
+// Deduplication estimates how much storage the biome saves by sharing objects
+// between its remotes.
+func (b *biome) Deduplication(ctx context.Context) (Deduplication, error) {
+	var d Deduplication
+	owners, err := b.Owners(ctx)
+	if err != nil {
+		return d, err
+	}
+	var remotes []Remote
+	if err := b.readConfig(ctx, func(ctx context.Context, cfg *config.Config) error {
+		byOwner := ownerRemotes(cfg, owners)
+		for _, owner := range owners {
+			remotes = append(remotes, byOwner[owner]...)
+		}
+		return nil
+	}); err != nil {
+		return d, err
+	}
+
+	if d.Bytes, err = b.diskUsage(ctx, remotes, false); err != nil {
+		return d, err
+	}
+	for _, r := range remotes {
+		u := RemoteDiskUsage{
+			Remote: r.Name,
+		}
+		if u.Bytes, err = b.diskUsage(ctx, []Remote{r}, false); err != nil {
+			return d, fmt.Errorf("could not measure disk usage of %s: %w", r.Name, err)
+		}
+		if u.ExclusiveBytes, err = b.diskUsage(ctx, []Remote{r}, true); err != nil {
+			return d, fmt.Errorf("could not measure disk usage of %s: %w", r.Name, err)
+		}
+		d.IndependentBytes += u.Bytes
+		d.Remotes = append(d.Remotes, u)
+	}
+	slices.SortStableFunc(d.Remotes, func(a, b RemoteDiskUsage) int {
+		return cmp.Compare(b.ExclusiveBytes, a.ExclusiveBytes)
+	})
+	return d, nil
+}
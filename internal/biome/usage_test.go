@@ -0,0 +1,103 @@
+package biome
+
+import (
+	"context"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_DiskUsage(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		cfg.Section(section).AddOption(ownersOpt, github_com_cli.String())
+		cfg.Section(section).AddOption(ownersOpt, github_com_orirawlings.String())
+		for _, r := range []Remote{barRemote, archivedRemote} {
+			refspec, err := r.FetchRefspec()
+			if err != nil {
+				return false, err
+			}
+			cfg.Section("remote").Subsection(r.Name).SetOption("url", r.FetchURL())
+			cfg.Section("remote").Subsection(r.Name).SetOption("fetch", refspec)
+			cfg.Section("remotes").AddOption(github_com_orirawlings.RemoteGroup(), r.Name)
+		}
+		return true, nil
+	}))
+	createCommitFor(t, ctx, path, []string{
+		"refs/remotes/github.com/orirawlings/archived/heads/master",
+		"refs/remotes/github.com/orirawlings/bar/heads/main",
+	})
+
+	usage, err := b.DiskUsage(ctx)
+	testutil.Check(t, err)
+	if len(usage) != 2 {
+		t.Fatalf("expected disk usage of 2 owners, was %+v", usage)
+	}
+
+	// owners are sorted by size, largest first
+	if u := usage[0]; u.Owner != github_com_orirawlings || u.Remotes != 2 || u.Bytes <= 0 || u.ExclusiveBytes <= 0 {
+		t.Errorf("unexpected disk usage of %s: %+v", github_com_orirawlings, u)
+	}
+	if expected := (OwnerDiskUsage{Owner: github_com_cli}); usage[1] != expected {
+		t.Errorf("unexpected disk usage of %s: wanted %+v, was %+v", github_com_cli, expected, usage[1])
+	}
+}
+
+func TestBiome_Deduplication(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		cfg.Section(section).AddOption(ownersOpt, github_com_cli.String())
+		cfg.Section(section).AddOption(ownersOpt, github_com_orirawlings.String())
+		for r, owner := range map[Remote]Owner{
+			barRemote:          github_com_orirawlings,
+			archivedRemote:     github_com_orirawlings,
+			githubCLICLIRemote: github_com_cli,
+		} {
+			refspec, err := r.FetchRefspec()
+			if err != nil {
+				return false, err
+			}
+			cfg.Section("remote").Subsection(r.Name).SetOption("url", r.FetchURL())
+			cfg.Section("remote").Subsection(r.Name).SetOption("fetch", refspec)
+			cfg.Section("remotes").AddOption(owner.RemoteGroup(), r.Name)
+		}
+		return true, nil
+	}))
+	// bar and archived share a commit, like a fork and its upstream
+	createCommitFor(t, ctx, path, []string{
+		"refs/remotes/github.com/orirawlings/archived/heads/master",
+		"refs/remotes/github.com/orirawlings/bar/heads/main",
+	})
+	unrelated := commitTree(t, path, "unrelated")
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/remotes/github.com/cli/cli/heads/trunk", unrelated)
+
+	d, err := b.Deduplication(ctx)
+	testutil.Check(t, err)
+	if d.Bytes <= 0 || d.SavedBytes() <= 0 || d.IndependentBytes != d.Bytes+d.SavedBytes() {
+		t.Errorf("unexpected deduplication: %+v", d)
+	}
+	if len(d.Remotes) != 3 {
+		t.Fatalf("expected disk usage of 3 remotes, was %+v", d.Remotes)
+	}
+
+	// the remote that shares nothing is the first candidate for exclusion
+	if u := d.Remotes[0]; u.Remote != githubCLICLIRemote.Name || u.SharedBytes() != 0 || u.ExclusiveBytes <= 0 {
+		t.Errorf("unexpected disk usage of %s: %+v", githubCLICLIRemote.Name, u)
+	}
+	for _, u := range d.Remotes[1:] {
+		if u.ExclusiveBytes != 0 || u.SharedBytes() <= 0 {
+			t.Errorf("unexpected disk usage of %s: %+v", u.Remote, u)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package biome
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"slices"
+	"strings"
+
+	slicesutil "github.com/orirawlings/gh-biome/internal/util/slices"
+)
+
+// RepositoryAffiliation describes how the authenticated user is related to a
+// GitHub repository. Values correspond to GitHub's RepositoryAffiliation
+// GraphQL enum.
+//
+// https://docs.github.com/en/graphql/reference/enums#repositoryaffiliation
+type RepositoryAffiliation string
+
+const (
+	// OwnerAffiliation selects repositories that are owned by the
+	// authenticated user.
+	OwnerAffiliation RepositoryAffiliation = "OWNER"
+
+	// CollaboratorAffiliation selects repositories that the authenticated
+	// user has been added to as a collaborator.
+	CollaboratorAffiliation RepositoryAffiliation = "COLLABORATOR"
+
+	// OrganizationMemberAffiliation selects repositories that the
+	// authenticated user can access through membership in an organization.
+	OrganizationMemberAffiliation RepositoryAffiliation = "ORGANIZATION_MEMBER"
+)
+
+var (
+	// AllAffiliations is a list of all repository affiliations.
+	AllAffiliations = []RepositoryAffiliation{
+		OwnerAffiliation,
+		CollaboratorAffiliation,
+		OrganizationMemberAffiliation,
+	}
+)
+
+// ParseAffiliation identifies the repository affiliation given its name,
+// typically typed in as a command line argument. Names are case-insensitive
+// and may use either '_' or '-' as a word separator.
+//
+// Examples:
+//
+//	owner
+//	collaborator
+//	organization_member
+//	organization-member
+func ParseAffiliation(s string) (RepositoryAffiliation, error) {
+	a := RepositoryAffiliation(strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(s), "-", "_")))
+	if !slices.Contains(AllAffiliations, a) {
+		return "", fmt.Errorf("repository affiliation %q invalid, valid values are owner, collaborator, organization_member", s)
+	}
+	return a, nil
+}
+
+// Viewer is the authenticated user of a GitHub server. When a Viewer is added
+// to the biome, every repository the user can access on that server, as
+// limited by the Viewer's affiliations, is aggregated into the biome.
+type Viewer struct {
+
+	// host is the GitHub server name, ex. `github.com`.
+	host string
+
+	// affiliations limit which of the user's accessible repositories are
+	// aggregated.
+	affiliations []RepositoryAffiliation
+}
+
+// NewViewer returns the authenticated user of the given GitHub server. If
+// host is empty, "github.com" is assumed. If no affiliations are given, all
+// affiliations are assumed.
+func NewViewer(host string, affiliations ...RepositoryAffiliation) Viewer {
+	v := Viewer{
+		host:         strings.ToLower(host),
+		affiliations: slicesutil.SortedUnique(affiliations),
+	}
+	if v.host == "" {
+		v.host = defaultOwnerHost
+	}
+	if len(v.affiliations) == 0 {
+		v.affiliations = slicesutil.SortedUnique(AllAffiliations)
+	}
+	return v
+}
+
+// Host is the GitHub server name, ex. `github.com`.
+func (v Viewer) Host() string {
+	return v.host
+}
+
+// Affiliations limit which of the user's accessible repositories are
+// aggregated into the biome.
+func (v Viewer) Affiliations() []RepositoryAffiliation {
+	return slices.Clone(v.affiliations)
+}
+
+func (v Viewer) String() string {
+	return fmt.Sprintf("viewer@%s", v.host)
+}
+
+// RemoteGroup is the git remote group name for all remotes accessible to this
+// viewer.
+func (v Viewer) RemoteGroup() string {
+	h := sha1.New()
+	_, err := h.Write([]byte(v.String()))
+	if err != nil {
+		panic(fmt.Errorf("could not determine git remote group name for %q: %w", v, err))
+	}
+	return fmt.Sprintf("g-%x", h.Sum(nil))
+}
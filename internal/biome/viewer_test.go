@@ -0,0 +1,121 @@
+package biome
+
+import (
+	"slices"
+	"testing"
+)
+
+var (
+	github_com_viewer = NewViewer("github.com")
+
+	my_github_biz_viewer = NewViewer("my.github.biz", CollaboratorAffiliation)
+)
+
+func TestParseAffiliation(t *testing.T) {
+	for _, r := range []struct {
+		s        string
+		expected RepositoryAffiliation
+		invalid  bool
+	}{
+		{
+			s:        "owner",
+			expected: OwnerAffiliation,
+		},
+		{
+			s:        "COLLABORATOR",
+			expected: CollaboratorAffiliation,
+		},
+		{
+			s:        "organization_member",
+			expected: OrganizationMemberAffiliation,
+		},
+		{
+			s:        "organization-member",
+			expected: OrganizationMemberAffiliation,
+		},
+		{
+			s:       "member",
+			invalid: true,
+		},
+		{
+			s:       "",
+			invalid: true,
+		},
+	} {
+		t.Run(r.s, func(t *testing.T) {
+			a, err := ParseAffiliation(r.s)
+			if r.invalid {
+				if err == nil {
+					t.Error("expected parse error, but was nil")
+				}
+			} else {
+				if err != nil {
+					t.Errorf("unexpected parse error: %v", err)
+				}
+				if a != r.expected {
+					t.Errorf("unexpected: wanted %v, was %v", r.expected, a)
+				}
+			}
+		})
+	}
+}
+
+func TestNewViewer(t *testing.T) {
+	for _, r := range []struct {
+		name                 string
+		viewer               Viewer
+		expectedHost         string
+		expectedAffiliations []RepositoryAffiliation
+	}{
+		{
+			name:                 "defaults",
+			viewer:               NewViewer(""),
+			expectedHost:         "github.com",
+			expectedAffiliations: []RepositoryAffiliation{CollaboratorAffiliation, OrganizationMemberAffiliation, OwnerAffiliation},
+		},
+		{
+			name:                 "host is normalized",
+			viewer:               NewViewer("My.GitHub.biz", OwnerAffiliation),
+			expectedHost:         "my.github.biz",
+			expectedAffiliations: []RepositoryAffiliation{OwnerAffiliation},
+		},
+		{
+			name:                 "affiliations are sorted and unique",
+			viewer:               NewViewer("github.com", OwnerAffiliation, CollaboratorAffiliation, OwnerAffiliation),
+			expectedHost:         "github.com",
+			expectedAffiliations: []RepositoryAffiliation{CollaboratorAffiliation, OwnerAffiliation},
+		},
+	} {
+		t.Run(r.name, func(t *testing.T) {
+			if r.viewer.Host() != r.expectedHost {
+				t.Errorf("unexpected host: wanted %q, was %q", r.expectedHost, r.viewer.Host())
+			}
+			if !slices.Equal(r.viewer.Affiliations(), r.expectedAffiliations) {
+				t.Errorf("unexpected affiliations: wanted %v, was %v", r.expectedAffiliations, r.viewer.Affiliations())
+			}
+		})
+	}
+}
+
+func TestViewer_RemoteGroup(t *testing.T) {
+	for _, run := range []struct {
+		viewer   Viewer
+		expected string
+	}{
+		{
+			viewer:   github_com_viewer,
+			expected: "g-43ca865f100ea1b37dc465da7d0fe0efbb705416",
+		},
+		{
+			viewer:   my_github_biz_viewer,
+			expected: "g-30445d9923f8f383a6f878b3cb9648a477c32373",
+		},
+	} {
+		t.Run(run.viewer.String(), func(t *testing.T) {
+			g := run.viewer.RemoteGroup()
+			if g != run.expected {
+				t.Errorf("wanted %q, was %q", run.expected, g)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+// errCommitNotFound indicates that a commit is not reachable from the
+// references of any of the biome's remotes.
+var errCommitNotFound = errors.New("commit not found in any remote")
+
+// WebURL returns the URL of a page of the named remote's repository on its
+// GitHub server. Without a reference or path, it is the repository's home
+// page. With only a reference, it is the page of the commit that the
+// reference resolves to. With a path, it is the page of the file or
+// directory at that path, in the commit that the reference, or the remote's
+// HEAD, resolves to. The reference is named relative to the remote's
+// namespace, as with [Biome.Tree], ex. HEAD, main, or tags/v1.0.
+//
+// Pages of commits and paths name the commit by its object ID, so the URL
+// keeps pointing to the same content as the remote's branches move on.
+func (b *biome) WebURL(ctx context.Context, remote, ref, path string) (string, error) {
+	remotes, err := b.Remotes(ctx, AllRemoteCategories...)
+	if err != nil {
+		return "", err
+	}
+	i := slices.IndexFunc(remotes, func(r Remote) bool {
+		return r.Name == remote
+	})
+	if i < 0 {
+		return "", fmt.Errorf("%w: %s", errRemoteNotFound, remote)
+	}
+	if ref == "" && strings.Trim(path, "/") == "" {
+		return remotes[i].WebURL(), nil
+	}
+
+	commit, err := b.resolveRemoteRef(ctx, remote, ref)
+	if err != nil {
+		return "", err
+	}
+	return b.commitWebURL(ctx, remotes[i], commit, path)
+}
+
+// RefWebURL returns the URL of the page of the commit, or of the file or
+// directory at the given path of the commit, that a reference or commit of
+// the biome resolves to, on the GitHub server of the remote it belongs to.
+// The reference is given by its full name in a remote's namespace, ex.
+// refs/remotes/github.com/cli/cli/heads/main, as printed by git commands
+// such as git grep and git log --source. A commit is given by its object
+// ID, and is attributed to a remote whose references reach it, preferring
+// remotes that are not forks, so that links point to upstream repositories.
+func (b *biome) RefWebURL(ctx context.Context, ref, path string) (string, error) {
+	remotes, err := b.Remotes(ctx, FetchableRemoteCategories...)
+	if err != nil {
+		return "", err
+	}
+	namespaces := make(map[string]string)
+	for _, r := range remotes {
+		namespaces[r.RefNamespace()] = r.Name
+	}
+	if strings.HasPrefix(ref, "refs/") {
+		name, ok := remoteOfRef(namespaces, ref)
+		if !ok {
+			return "", fmt.Errorf("%w: %s", errRemoteNotFound, ref)
+		}
+		return b.WebURL(ctx, name, relativeRef(namespaces, ref), path)
+	}
+
+	out, err := b.git(ctx, "rev-parse", "--verify", "--quiet", "--end-of-options", ref+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", errCommitNotFound, ref)
+	}
+	commit := strings.TrimSpace(string(out))
+	reaching, err := b.commitRemotes(ctx, remotes, commit)
+	if err != nil {
+		return "", err
+	}
+	// remotes are sorted by name, so forks are only picked when no
+	// upstream reaches the commit
+	var candidates []Remote
+	for _, r := range remotes {
+		if slices.ContainsFunc(reaching, func(cr CommitRemote) bool {
+			return cr.Remote == r.Name
+		}) {
+			candidates = append(candidates, r)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("%w: %s", errCommitNotFound, ref)
+	}
+	i := slices.IndexFunc(candidates, func(r Remote) bool {
+		return r.Upstream == ""
+	})
+	return b.commitWebURL(ctx, candidates[max(i, 0)], commit, path)
+}
+
+// commitWebURL returns the URL of the page of the commit on the remote's
+// GitHub server, or of the file or directory at the given path of the
+// commit.
+func (b *biome) commitWebURL(ctx context.Context, r Remote, commit, path string) (string, error) {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return r.WebURL() + "/commit/" + commit, nil
+	}
+	objectType, err := b.git(ctx, "cat-file", "-t", commit+":"+path)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return "", fmt.Errorf("%w: %s %s", ErrPathNotFound, r.Name, path)
+	}
+	if err != nil {
+		return "", err
+	}
+	page := "blob"
+	if strings.TrimSpace(string(objectType)) == "tree" {
+		page = "tree"
+	}
+	return r.WebURL() + "/" + page + "/" + commit + "/" + escapePath(path), nil
+}
+
+// escapePath escapes each element of a slash separated path for use in a
+// URL.
+func escapePath(path string) string {
+	elems := strings.Split(path, "/")
+	for i, elem := range elems {
+		elems[i] = url.PathEscape(elem)
+	}
+	return strings.Join(elems, "/")
+}
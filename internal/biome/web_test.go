@@ -0,0 +1,158 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_WebURL(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, barRemote.Name)
+		cfg.Section(section).Subsection(remotesSubsection).AddOption(archivedOpt, archivedRemote.Name)
+		return true, nil
+	}))
+
+	file := filepath.Join(t.TempDir(), "file")
+	testutil.Check(t, os.WriteFile(file, []byte("hello\n"), 0o644))
+	blob := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "hash-object", "-w", file))
+	docs := mktree(t, path, fmt.Sprintf("100644 blob %s\tgetting started.md\n", blob))
+	root := mktree(t, path, fmt.Sprintf("100644 blob %s\tREADME.md\n040000 tree %s\tdocs\n", blob, docs))
+	tag := commitTree(t, path, "tag")
+	head := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "commit-tree", "-m", "files", root))
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/remotes/github.com/orirawlings/bar/heads/main", head)
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/remotes/github.com/orirawlings/bar/tags/v1", tag)
+	testutil.Execute(t, "git", "-C", path, "symbolic-ref", barRemote.Head(), "refs/remotes/github.com/orirawlings/bar/heads/main")
+
+	for _, tc := range []struct {
+		name     string
+		remote   string
+		ref      string
+		path     string
+		expected string
+	}{
+		{
+			name:     "home page",
+			remote:   barRemote.Name,
+			expected: "https://github.com/orirawlings/bar",
+		},
+		{
+			name:     "home page of archived remote",
+			remote:   archivedRemote.Name,
+			expected: "https://github.com/orirawlings/archived",
+		},
+		{
+			name:     "HEAD commit",
+			remote:   barRemote.Name,
+			ref:      "HEAD",
+			expected: "https://github.com/orirawlings/bar/commit/" + head,
+		},
+		{
+			name:     "tag commit",
+			remote:   barRemote.Name,
+			ref:      "tags/v1",
+			expected: "https://github.com/orirawlings/bar/commit/" + tag,
+		},
+		{
+			name:     "file",
+			remote:   barRemote.Name,
+			path:     "README.md",
+			expected: "https://github.com/orirawlings/bar/blob/" + head + "/README.md",
+		},
+		{
+			name:     "directory",
+			remote:   barRemote.Name,
+			ref:      "main",
+			path:     "/docs/",
+			expected: "https://github.com/orirawlings/bar/tree/" + head + "/docs",
+		},
+		{
+			name:     "escaped file",
+			remote:   barRemote.Name,
+			path:     "docs/getting started.md",
+			expected: "https://github.com/orirawlings/bar/blob/" + head + "/docs/getting%20started.md",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := b.WebURL(ctx, tc.remote, tc.ref, tc.path)
+			testutil.Check(t, err)
+			if u != tc.expected {
+				t.Errorf("unexpected URL: wanted %q, was %q", tc.expected, u)
+			}
+		})
+	}
+
+	if _, err := b.WebURL(ctx, barRemote.Name, "", "missing.md"); !errors.Is(err, ErrPathNotFound) {
+		t.Errorf("expected error for missing path, was %v", err)
+	}
+	if _, err := b.WebURL(ctx, barRemote.Name, "missing", ""); !errors.Is(err, errRefNotFound) {
+		t.Errorf("expected error for missing reference, was %v", err)
+	}
+	if _, err := b.WebURL(ctx, "github.com/orirawlings/missing", "", ""); !errors.Is(err, errRemoteNotFound) {
+		t.Errorf("expected error for unknown remote, was %v", err)
+	}
+
+	// full reference names and commits name both a remote and a commit
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, githubCLICLIRemote.Name)
+		cfg.Section(section).Subsection(remotesSubsection).AddOption(upstreamOpt, barRemote.Name+" "+githubCLICLIRemote.Name)
+		return true, nil
+	}))
+	for _, tc := range []struct {
+		name     string
+		ref      string
+		path     string
+		expected string
+	}{
+		{
+			name:     "directory of branch",
+			ref:      "refs/remotes/github.com/orirawlings/bar/heads/main",
+			path:     "docs",
+			expected: "https://github.com/orirawlings/bar/tree/" + head + "/docs",
+		},
+		{
+			name:     "tag",
+			ref:      "refs/remotes/github.com/orirawlings/bar/tags/v1",
+			expected: "https://github.com/orirawlings/bar/commit/" + tag,
+		},
+		{
+			name:     "commit of fork",
+			ref:      head[:7],
+			path:     "README.md",
+			expected: "https://github.com/orirawlings/bar/blob/" + head + "/README.md",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := b.RefWebURL(ctx, tc.ref, tc.path)
+			testutil.Check(t, err)
+			if u != tc.expected {
+				t.Errorf("unexpected URL: wanted %q, was %q", tc.expected, u)
+			}
+		})
+	}
+
+	// commits reachable from an upstream are attributed to the upstream
+	testutil.Execute(t, "git", "-C", path, "update-ref", "refs/remotes/github.com/cli/cli/heads/trunk", head)
+	if u, err := b.RefWebURL(ctx, head, ""); err != nil || u != "https://github.com/cli/cli/commit/"+head {
+		t.Errorf("expected commit to be attributed to the upstream, was %q, %v", u, err)
+	}
+	if _, err := b.RefWebURL(ctx, commitTree(t, path, "unreachable"), ""); !errors.Is(err, errCommitNotFound) {
+		t.Errorf("expected error for unreachable commit, was %v", err)
+	}
+	if _, err := b.RefWebURL(ctx, "refs/remotes/github.com/orirawlings/missing/heads/main", ""); !errors.Is(err, errRemoteNotFound) {
+		t.Errorf("expected error for reference of unknown remote, was %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package biome
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// forEachRefBatchSize is the number of remotes' reference namespaces given
+// to each git for-each-ref, so that the arguments of a single git process
+// stay bounded in biomes with thousands of remotes.
+const forEachRefBatchSize = 512
+
+// CommitRemote is a remote whose references reach a commit, see
+// [biome.CommitRemotes].
+type CommitRemote struct {
+
+	// Remote is the name of the remote.
+	Remote string
+
+	// Head indicates that the remote's default branch reaches the commit.
+	Head bool
+
+	// Refs are the names of the remote's references that reach the commit,
+	// relative to the remote's namespace, ex. heads/main or tags/v1.0,
+	// sorted by name. HEAD is not included, see Head.
+	Refs []string
+}
+
+// CommitRemotes returns the biome's fetchable remotes whose references
+// reach the given commit, sorted by name, answering where a commit came
+// from, and which other repositories have it. The commit is given by its
+// object ID, or any other name that git resolves to a commit. It is an error
+// if the biome does not have the commit.
+func (b *biome) CommitRemotes(ctx context.Context, commit string) ([]CommitRemote, error) {
+	remotes, err := b.Remotes(ctx, FetchableRemoteCategories...)
+	if err != nil {
+		return nil, err
+	}
+	out, err := b.git(ctx, "rev-parse", "--verify", "--quiet", "--end-of-options", commit+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errCommitNotFound, commit)
+	}
+	return b.commitRemotes(ctx, remotes, strings.TrimSpace(string(out)))
+}
+
+// commitRemotes returns the given remotes whose references reach the commit,
+// sorted by name.
+func (b *biome) commitRemotes(ctx context.Context, remotes []Remote, commit string) ([]CommitRemote, error) {
+	namespaces := make(map[string]string)
+	var prefixes []string
+	for _, r := range remotes {
+		namespaces[r.RefNamespace()] = r.Name
+		prefixes = append(prefixes, r.RefNamespace())
+	}
+	byRemote := make(map[string]*CommitRemote)
+	for batch := range slices.Chunk(prefixes, forEachRefBatchSize) {
+		args := append([]string{"for-each-ref", "--format=%(refname)", "--contains", commit}, batch...)
+		out, err := b.git(ctx, args...)
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(out))
+		for scanner.Scan() {
+			ref := scanner.Text()
+			remote, ok := remoteOfRef(namespaces, ref)
+			if !ok {
+				continue
+			}
+			if byRemote[remote] == nil {
+				byRemote[remote] = &CommitRemote{Remote: remote}
+			}
+			if name := relativeRef(namespaces, ref); name == "HEAD" {
+				byRemote[remote].Head = true
+			} else {
+				byRemote[remote].Refs = append(byRemote[remote].Refs, name)
+			}
+		}
+	}
+	var result []CommitRemote
+	for _, r := range remotes {
+		if cr, ok := byRemote[r.Name]; ok {
+			slices.Sort(cr.Refs)
+			result = append(result, *cr)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,67 @@
+package biome
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/orirawlings/gh-biome/internal/config"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestBiome_CommitRemotes(t *testing.T) {
+	ctx := context.Background()
+	path := testutil.TempRepo(t)
+	b := &biome{
+		path:          path,
+		editorOptions: []config.EditorOption{config.Direct()},
+	}
+	testutil.Check(t, b.editConfig(ctx, func(ctx context.Context, cfg *config.Config) (bool, error) {
+		for _, r := range []Remote{githubCLICLIRemote, barRemote, headlessRemote} {
+			cfg.Section(section).Subsection(remotesSubsection).AddOption(activeOpt, r.Name)
+		}
+		return true, nil
+	}))
+	base := commitTree(t, path, "base")
+	next := commitTree(t, path, "next", base)
+	other := commitTree(t, path, "other")
+	for ref, commit := range map[string]string{
+		"refs/remotes/github.com/cli/cli/heads/trunk":          next,
+		"refs/remotes/github.com/orirawlings/bar/heads/main":   base,
+		"refs/remotes/github.com/orirawlings/bar/tags/v1":      next,
+		"refs/remotes/github.com/orirawlings/headless/heads/x": other,
+	} {
+		testutil.Execute(t, "git", "-C", path, "update-ref", ref, commit)
+	}
+	testutil.Execute(t, "git", "-C", path, "symbolic-ref", barRemote.Head(), "refs/remotes/github.com/orirawlings/bar/heads/main")
+
+	remotes, err := b.CommitRemotes(ctx, base[:7])
+	testutil.Check(t, err)
+	expected := []CommitRemote{
+		{Remote: githubCLICLIRemote.Name, Refs: []string{"heads/trunk"}},
+		{Remote: barRemote.Name, Head: true, Refs: []string{"heads/main", "tags/v1"}},
+	}
+	if !reflect.DeepEqual(remotes, expected) {
+		t.Errorf("unexpected remotes reaching commit: wanted %+v, was %+v", expected, remotes)
+	}
+
+	remotes, err = b.CommitRemotes(ctx, next)
+	testutil.Check(t, err)
+	expected = []CommitRemote{
+		{Remote: githubCLICLIRemote.Name, Refs: []string{"heads/trunk"}},
+		{Remote: barRemote.Name, Refs: []string{"tags/v1"}},
+	}
+	if !reflect.DeepEqual(remotes, expected) {
+		t.Errorf("unexpected remotes reaching commit: wanted %+v, was %+v", expected, remotes)
+	}
+
+	remotes, err = b.CommitRemotes(ctx, commitTree(t, path, "unreachable"))
+	testutil.Check(t, err)
+	if len(remotes) != 0 {
+		t.Errorf("expected no remotes reaching an unreachable commit, was %+v", remotes)
+	}
+	if _, err := b.CommitRemotes(ctx, "0123456789abcdef0123456789abcdef01234567"); !errors.Is(err, errCommitNotFound) {
+		t.Errorf("expected error for unknown commit, was %v", err)
+	}
+}
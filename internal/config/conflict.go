@@ -0,0 +1,167 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/format/config"
+)
+
+const (
+	// maxEditAttempts is how many times an edit's callback is invoked
+	// before the edit gives up on a configuration file that keeps being
+	// changed by other processes.
+	maxEditAttempts = 5
+
+	// conflictBackoff is how long an edit waits before invoking its callback
+	// again after a conflict, multiplied by the number of attempts so far.
+	conflictBackoff = 50 * time.Millisecond
+)
+
+// ErrConflict indicates that the configuration file was changed, or locked,
+// by another process while it was being edited.
+var ErrConflict = errors.New("config file was changed concurrently")
+
+// snapshot identifies the contents of a configuration file when it was
+// loaded, so that changes made by other processes since can be detected
+// before the edited configuration is saved over them.
+type snapshot struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+	sum     [sha256.Size]byte
+}
+
+// loadSnapshot parses the configuration file at path, taking a snapshot of
+// its contents. A missing file is treated as an empty configuration.
+func loadSnapshot(path string) (*Config, snapshot, error) {
+	cfg := config.New()
+	snap, data, err := takeSnapshot(path)
+	if err != nil {
+		return nil, snap, fmt.Errorf("could not load config file: %w", err)
+	}
+	if err := config.NewDecoder(bytes.NewReader(data)).Decode(cfg); err != nil {
+		return nil, snap, fmt.Errorf("could not load config file: %w", err)
+	}
+	return cfg, snap, nil
+}
+
+// takeSnapshot reads the configuration file at path, returning its snapshot
+// and contents.
+func takeSnapshot(path string) (snapshot, []byte, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return snapshot{}, nil, nil
+	}
+	if err != nil {
+		return snapshot{}, nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return snapshot{}, nil, err
+	}
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(f); err != nil {
+		return snapshot{}, nil, err
+	}
+	return snapshot{
+		exists:  true,
+		modTime: info.ModTime(),
+		size:    info.Size(),
+		sum:     sha256.Sum256(buf.Bytes()),
+	}, buf.Bytes(), nil
+}
+
+// changed reports whether the configuration file at path was changed since
+// the snapshot was taken. The contents are compared, rather than only the
+// modification time, since file systems may not record modification times
+// precisely enough to tell quick successive writes apart.
+func (s snapshot) changed(path string) (bool, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s.exists, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if !s.exists || !info.ModTime().Equal(s.modTime) || info.Size() != s.size {
+		return true, nil
+	}
+	current, _, err := takeSnapshot(path)
+	if err != nil {
+		return false, err
+	}
+	return current.sum != s.sum, nil
+}
+
+// editWithRetries loads the configuration file at path, and invokes the
+// callback with it, saving the changes with save if the callback returns
+// true. If save reports [ErrConflict], because another process changed the
+// file since it was loaded, the file is loaded again, and the callback is
+// invoked again with the changed configuration, up to maxEditAttempts times,
+// so that concurrent edits are applied on top of each other rather than
+// clobbering each other. Callbacks must therefore only modify the
+// configuration they are given.
+func editWithRetries(ctx context.Context, path string, do func(context.Context, *Config) (bool, error), save func(*Config, snapshot) error) error {
+	for attempt := 1; ; attempt++ {
+		cfg, snap, err := loadSnapshot(path)
+		if err != nil {
+			return err
+		}
+		ok, err := do(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("editor callback failed: %w", err)
+		}
+		if !ok {
+			return nil
+		}
+		if ctx.Err() != nil {
+			// the edit was canceled, so do not save it
+			return context.Cause(ctx)
+		}
+		err = save(cfg, snap)
+		if !errors.Is(err, ErrConflict) {
+			return err
+		}
+		if attempt == maxEditAttempts {
+			return fmt.Errorf("could not save config file after %d attempts: %w", attempt, err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * conflictBackoff):
+		}
+	}
+}
+
+// commitLocked saves the configuration to the configuration file at path
+// through its lock file, which the caller has exclusively created, unless the
+// file was changed since the snapshot was taken, in which case [ErrConflict]
+// is returned and nothing is written. The lock file is renamed over the
+// configuration file, so readers never see a partially written
+// configuration.
+func commitLocked(lock *os.File, path string, cfg *Config, snap snapshot) error {
+	changed, err := snap.changed(path)
+	if err != nil {
+		return fmt.Errorf("could not check config file for changes: %w", err)
+	}
+	if changed {
+		return ErrConflict
+	}
+	if err := config.NewEncoder(lock).Encode(cfg); err != nil {
+		return fmt.Errorf("could not save config file: %w", err)
+	}
+	if err := lock.Close(); err != nil {
+		return fmt.Errorf("could not save config file: %w", err)
+	}
+	if err := os.Rename(lock.Name(), path); err != nil {
+		return fmt.Errorf("could not save config file: %w", err)
+	}
+	return nil
+}
@@ -3,12 +3,20 @@ package config
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
 	"fmt"
-	"net"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"sync/atomic"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	pb "github.com/orirawlings/gh-biome/internal/config/protobuf"
 
@@ -17,12 +25,43 @@ import (
 
 type Config = config.Config
 
+type Option = config.Option
+
+const (
+	// defaultHelperTimeout is how long 'git config edit' has to run the
+	// helper command, and the helper has to call back to the editor server,
+	// unless overridden with [HelperTimeout].
+	defaultHelperTimeout = 30 * time.Second
+
+	// exitTimeout is how long 'git config edit', and the helper command, have
+	// to exit once the edit is done, before they are killed.
+	exitTimeout = 5 * time.Second
+
+	// HelperTokenKey is the gRPC metadata key of the token that the helper
+	// command authenticates its call to the editor server with.
+	HelperTokenKey = "gh-biome-editor-token"
+)
+
+var (
+	// errHelperTimeout indicates that the helper command did not call back to
+	// the editor server in time.
+	errHelperTimeout = errors.New("config edit helper did not call back in time")
+
+	// errEditorExited indicates that 'git config edit' exited, ex. because it
+	// was killed, before the edit was done.
+	errEditorExited = errors.New("git config edit exited before the edit was done")
+)
+
 // Editor is an interface for editing git configurations.
 type Editor interface {
 	// Edit opens the git configuration and invokes the provided callback function
 	// with the loaded configuration. If the callback returns true, changes
 	// to the config object will be saved back to the configuration file.
 	// If the callback returns false, any changes will not be saved.
+	// If the configuration file is changed by another process before the
+	// changes are saved, the callback is invoked again with the changed
+	// configuration, so concurrent edits do not clobber each other. Callbacks
+	// must therefore only modify the configuration they are given.
 	// If an error occurs during the editing process, it will be returned.
 	Edit(context.Context, func(context.Context, *Config) (bool, error)) error
 }
@@ -30,6 +69,13 @@ type Editor interface {
 type editor struct {
 	repoPath  string
 	helperCmd string
+
+	// direct edits the configuration file directly, without the helper
+	// command.
+	direct bool
+
+	// helperTimeout is how long the helper command has to call back.
+	helperTimeout time.Duration
 }
 
 // NewEditor creates a new Editor instance for the specified git repository path.
@@ -38,8 +84,9 @@ type editor struct {
 // file, and the helper command will be used to communicate with the editor server.
 func NewEditor(repoPath string, opts ...EditorOption) Editor {
 	e := editor{
-		repoPath:  repoPath,
-		helperCmd: fmt.Sprintf("%s config-edit-helper", os.Args[0]),
+		repoPath:      repoPath,
+		helperCmd:     fmt.Sprintf("%s config-edit-helper", filepath.ToSlash(os.Args[0])),
+		helperTimeout: defaultHelperTimeout,
 	}
 	for _, opt := range opts {
 		opt(&e)
@@ -50,124 +97,134 @@ func NewEditor(repoPath string, opts ...EditorOption) Editor {
 // Edit the git configuration at the specified repository path.
 // It starts a gRPC server to handle the editing process and uses the provided
 // callback function to modify the configuration. The callback can return true
-// to save changes or false to discard them. If an error occurs during the
-// editing process, it will be returned.
+// to save changes or false to discard them. The changes are saved through a
+// lock file, only if the configuration file was not changed by another
+// process since it was loaded. Otherwise, the callback is invoked again with
+// the changed configuration. If an error occurs during the editing process,
+// it will be returned.
 func (e *editor) Edit(ctx context.Context, do func(context.Context, *Config) (bool, error)) error {
+	if e.direct {
+		return NewFileEditor(e.repoPath).Edit(ctx, do)
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	lis, err := e.listener(ctx)
+	lis, target, err := listen(ctx)
 	if err != nil {
-		return err
+		// the helper command would not be able to call back, so edit the
+		// configuration file directly instead
+		return NewFileEditor(e.repoPath).Edit(ctx, do)
 	}
 	defer lis.Close()
 
-	// start server
-	s := newEditorServer()
+	// start server. It is stopped without waiting for the helper, so that a
+	// helper that is stuck cannot keep the edit from returning, and a helper
+	// that is still connected notices and exits. Only the helper is given
+	// the token to call it with, so that other local processes cannot.
+	token := rand.Text()
+	s := newEditorServer(token)
 	gs := grpc.NewServer()
 	pb.RegisterEditorServer(gs, s)
-	defer gs.GracefulStop()
+	defer gs.Stop()
 	go gs.Serve(lis)
 
-	// start git config editor
+	// start git config editor. It is killed if the edit is canceled, and
+	// waiting for it does not hang on its output if the helper outlives it.
 	cmd := exec.CommandContext(ctx, "git", "-C", e.repoPath, "config", "edit", "--local")
-	cmd.Env = append(cmd.Env, fmt.Sprintf("GIT_EDITOR=%s %s", e.helperCmd, lis.Addr()))
+	cmd.Env = append(cmd.Env, fmt.Sprintf("GIT_EDITOR=%s %s %s", e.helperCmd, target, token))
+	cmd.WaitDelay = exitTimeout
 	var out bytes.Buffer
 	cmd.Stderr = &out
 	cmd.Stdout = &out
 	if err := cmd.Start(); err != nil {
 		return fmt.Errorf("could not %q: %w", cmd, err)
 	}
-	cmdErr := make(chan error)
+	var waitErr error
+	exited := make(chan struct{})
 	go func() {
-		defer close(cmdErr)
-		if err := cmd.Wait(); err != nil {
-			select {
-			case cmdErr <- fmt.Errorf("could not %q: %w", cmd, err):
-			case <-ctx.Done():
-			}
-		}
+		defer close(exited)
+		waitErr = cmd.Wait()
+	}()
+
+	// never return before git config editor exits, so that no stray process
+	// is left behind
+	defer func() {
+		cancel()
+		<-exited
 	}()
 
 	var path string
+	timer := time.NewTimer(e.helperTimeout)
+	defer timer.Stop()
 	select {
-	case err := <-cmdErr:
-		if err != nil {
-			err = fmt.Errorf("%q ended unexpectedly early: %w\n%s", cmd, err, out.String())
-		} else {
-			err = fmt.Errorf("%q ended unexpectedly early:\n%s", cmd, out.String())
+	case <-exited:
+		if waitErr != nil {
+			return fmt.Errorf("%q ended unexpectedly early: %w\n%s", cmd, waitErr, out.String())
 		}
-		return err
+		return fmt.Errorf("%q ended unexpectedly early:\n%s", cmd, out.String())
 	case path = <-s.Path():
+	case <-timer.C:
+		return fmt.Errorf("%w: %q did not run the helper command %q within %s, or the helper could not connect to %s. Set GH_BIOME_CONFIG_EDITOR=direct to edit the git config file directly instead.", errHelperTimeout, cmd, e.helperCmd, e.helperTimeout, target)
 	case <-ctx.Done():
 		return ctx.Err()
 	}
 
-	// parse the config file
-	cfg, err := e.load(path)
-	if err != nil {
-		err = fmt.Errorf("could not load config file: %w", err)
-		defer s.Done(ctx, err)
-		return err
-	}
-
-	save, err := do(ctx, cfg)
-	if err != nil {
-		err = fmt.Errorf("editor callback failed: %w", err)
-		defer s.Done(ctx, err)
-		return err
-	}
-
-	if save {
-		if err := e.save(path, cfg); err != nil {
-			err = fmt.Errorf("could not save config file: %w", err)
-			defer s.Done(ctx, err)
-			return err
+	// stop editing, without saving, if git config editor exits in the
+	// meantime, ex. because it was killed
+	editCtx, stopEdit := context.WithCancelCause(ctx)
+	defer stopEdit(nil)
+	go func() {
+		select {
+		case <-exited:
+			stopEdit(fmt.Errorf("%w: %q: %v\n%s", errEditorExited, cmd, waitErr, out.String()))
+		case <-editCtx.Done():
 		}
+	}()
+	err = editWithRetries(editCtx, path, do, func(cfg *Config, snap snapshot) error {
+		return saveLocked(path, cfg, snap)
+	})
+	if cause := context.Cause(editCtx); err != nil && cause != nil && ctx.Err() == nil {
+		err = cause
 	}
 
-	defer s.Done(ctx, nil)
-	return nil
-}
-
-func (e *editor) listener(ctx context.Context) (net.Listener, error) {
-	f, err := os.CreateTemp("", "")
-	if err != nil {
-		return nil, fmt.Errorf("could not create temp file")
-	}
-	if err := f.Close(); err != nil {
-		return nil, fmt.Errorf("could not close temp file")
-	}
-	if err := os.Remove(f.Name()); err != nil {
-		return nil, fmt.Errorf("could not remove temp file")
+	// let the helper, and then git config editor, exit, killing them if
+	// they take too long
+	s.Done(ctx, err)
+	select {
+	case <-exited:
+	case <-time.After(exitTimeout):
 	}
-	return (&net.ListenConfig{}).Listen(ctx, "unix", f.Name())
+	return err
 }
 
-func (e *editor) load(path string) (*Config, error) {
-	configFile, err := os.Open(path)
-	if err != nil {
-		return nil, err
+// saveLocked writes the configuration to the configuration file at path, the
+// way git does, through an exclusively created `config.lock` file next to it,
+// unless the file was changed or locked by another process since the snapshot
+// was taken, in which case [ErrConflict] is returned.
+func saveLocked(path string, cfg *Config, snap snapshot) error {
+	lockPath := path + ".lock"
+	lock, err := os.OpenFile(lockPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0666)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("config file is locked by another process: %w", ErrConflict)
 	}
-	cfg := config.New()
-	defer configFile.Close()
-	return cfg, config.NewDecoder(configFile).Decode(cfg)
-}
-
-func (e *editor) save(path string, cfg *Config) error {
-	w, err := os.Create(path)
 	if err != nil {
+		return fmt.Errorf("could not lock config file: %w", err)
+	}
+	if err := commitLocked(lock, path, cfg, snap); err != nil {
+		lock.Close()
+		os.Remove(lockPath)
 		return err
 	}
-	defer w.Close()
-	return config.NewEncoder(w).Encode(cfg)
+	return nil
 }
 
 type EditorOption func(*editor)
 
 // HelperCommand overrides the command forked by 'git config edit' that will
-// callback to the editor server. The command will be passed two arguments, the
-// unix socket to call the server and the temporary config file to edit
+// callback to the editor server. The command will be passed three arguments,
+// the gRPC target to call the server, the token to authenticate the call with,
+// sent as [HelperTokenKey] metadata, and the temporary config file to edit
 // provided by 'git config edit'.
 //
 // Generally, this option will only be used during tests.
@@ -177,20 +234,59 @@ func HelperCommand(cmd string) EditorOption {
 	}
 }
 
+// HelperTimeout overrides how long 'git config edit' has to run the helper
+// command, and the helper has to call back to the editor server, before the
+// edit fails. The default is 30 seconds.
+func HelperTimeout(d time.Duration) EditorOption {
+	return func(e *editor) {
+		e.helperTimeout = d
+	}
+}
+
+// Direct makes the editor edit the configuration file directly, without
+// 'git config edit' and the helper command, see [NewFileEditor]. The editor
+// falls back to this automatically if the editor server cannot listen for
+// callbacks from the helper command.
+func Direct() EditorOption {
+	return func(e *editor) {
+		e.direct = true
+	}
+}
+
 type editorServer struct {
 	pb.UnimplementedEditorServer
 	path  chan string
 	errCh chan error
+
+	// token authenticates the Edit call of the helper.
+	token string
+
+	// called is set by the first authenticated Edit call. Any later call is
+	// refused, since only one edit is served.
+	called atomic.Bool
+
+	// returned is closed when the Edit call of the helper returns, ex.
+	// because the helper went away.
+	returned chan struct{}
 }
 
-func newEditorServer() *editorServer {
+func newEditorServer(token string) *editorServer {
 	return &editorServer{
-		path:  make(chan string),
-		errCh: make(chan error),
+		path:     make(chan string),
+		errCh:    make(chan error),
+		token:    token,
+		returned: make(chan struct{}),
 	}
 }
 
 func (e *editorServer) Edit(ctx context.Context, req *pb.EditRequest) (*pb.Empty, error) {
+	if !e.authenticated(ctx) {
+		return nil, status.Error(codes.Unauthenticated, "missing or invalid editor token")
+	}
+	if !e.called.CompareAndSwap(false, true) {
+		return nil, status.Error(codes.FailedPrecondition, "config is already being edited")
+	}
+	defer close(e.returned)
 	select {
 	case e.path <- req.Path:
 	case <-ctx.Done():
@@ -206,15 +302,29 @@ func (e *editorServer) Edit(ctx context.Context, req *pb.EditRequest) (*pb.Empty
 	}
 }
 
+// authenticated reports whether the call carries the server's token.
+func (e *editorServer) authenticated(ctx context.Context) bool {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, token := range md.Get(HelperTokenKey) {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(e.token)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
 func (e *editorServer) Path() chan string {
 	return e.path
 }
 
+// Done reports the result of the edit to the helper, which exits with an
+// error if the edit failed. It does not wait for a helper that went away.
 func (e *editorServer) Done(ctx context.Context, err error) error {
 	defer close(e.errCh)
 	if err != nil {
 		select {
 		case e.errCh <- err:
+		case <-e.returned:
 		case <-ctx.Done():
 			return ctx.Err()
 		}
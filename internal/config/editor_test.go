@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
@@ -63,6 +64,97 @@ func TestEditor(t *testing.T) {
 		}
 		assertConfigNotSet(ctx, t, path, configKey)
 	})
+
+	t.Run("retry concurrent edits", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 7*time.Second)
+		t.Cleanup(cancel)
+		path := testutil.TempRepo(t)
+		var attempts int
+		err := newEditor(t, path).Edit(ctx, func(ctx context.Context, c *Config) (bool, error) {
+			attempts++
+			if attempts == 1 {
+				// another process changes the config during the edit
+				testutil.Execute(t, "git", "-C", path, "config", "set", "--local", section+".concurrent", "true")
+			}
+			c.Section(section).SetOption(sectionKey, expectedValue)
+			return true, nil
+		})
+		testutil.Check(t, err)
+		if attempts != 2 {
+			t.Errorf("expected the edit to be attempted twice, was %d", attempts)
+		}
+		for key, expected := range map[string]string{
+			configKey:               expectedValue,
+			section + ".concurrent": "true",
+		} {
+			if v := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "config", "get", "--local", key)); v != expected {
+				t.Errorf("expected config %q to have value %q, was %q", key, expected, v)
+			}
+		}
+	})
+
+	t.Run("give up on locked config", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 7*time.Second)
+		t.Cleanup(cancel)
+		path := testutil.TempRepo(t)
+		lockPath := filepath.Join(path, "config.lock")
+		testutil.Check(t, os.WriteFile(lockPath, nil, 0666))
+		var attempts int
+		err := newEditor(t, path).Edit(ctx, func(ctx context.Context, c *Config) (bool, error) {
+			attempts++
+			c.Section(section).SetOption(sectionKey, expectedValue)
+			return true, nil
+		})
+		if !errors.Is(err, ErrConflict) {
+			t.Errorf("expected conflict error, was %v", err)
+		}
+		if attempts != maxEditAttempts {
+			t.Errorf("expected the edit to be attempted %d times, was %d", maxEditAttempts, attempts)
+		}
+		if _, err := os.Stat(lockPath); err != nil {
+			t.Errorf("expected lock held by another process to remain: %v", err)
+		}
+		assertConfigNotSet(ctx, t, path, configKey)
+	})
+
+	t.Run("helper never calls back", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 7*time.Second)
+		t.Cleanup(cancel)
+		path := testutil.TempRepo(t)
+		// the pretend helper does not hold on to git's output, which would
+		// delay the edit until the helper is given up on as well
+		e := NewEditor(path, HelperCommand("exec sleep 5 >/dev/null 2>&1 #"), HelperTimeout(100*time.Millisecond))
+		start := time.Now()
+		err := e.Edit(ctx, func(ctx context.Context, c *Config) (bool, error) {
+			t.Error("expected the callback not to be invoked")
+			return false, nil
+		})
+		if !errors.Is(err, errHelperTimeout) {
+			t.Errorf("expected helper timeout error, was %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 3*time.Second {
+			t.Errorf("expected the edit to give up promptly, took %s", elapsed)
+		}
+	})
+
+	t.Run("canceled edit", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 7*time.Second)
+		t.Cleanup(cancel)
+		path := testutil.TempRepo(t)
+		editCtx, cancelEdit := context.WithCancel(ctx)
+		err := newEditor(t, path).Edit(editCtx, func(ctx context.Context, c *Config) (bool, error) {
+			c.Section(section).SetOption(sectionKey, expectedValue)
+			cancelEdit()
+			return true, nil
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected canceled error, was %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(path, "config.lock")); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected no config lock to be left behind: %v", err)
+		}
+		assertConfigNotSet(ctx, t, path, configKey)
+	})
 }
 
 func newEditor(t *testing.T, repoPath string) Editor {
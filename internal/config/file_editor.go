@@ -0,0 +1,115 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/config"
+)
+
+type fileEditor struct {
+	repoPath string
+}
+
+// NewFileEditor creates a new Editor instance for the specified git repository
+// path that edits the configuration file directly, rather than through
+// 'git config edit' and the helper command. The configuration file is locked
+// the same way git locks it, by exclusively creating a `config.lock` file next
+// to it, so concurrent git processes cannot modify the configuration while it
+// is being edited.
+//
+// This is useful where the helper command cannot be spawned or cannot call
+// back to the editor server, ex. in constrained sandboxes.
+func NewFileEditor(repoPath string) Editor {
+	return &fileEditor{
+		repoPath: repoPath,
+	}
+}
+
+// Edit the git configuration at the specified repository path. The callback
+// can return true to save changes or false to discard them. If the
+// configuration file is changed by another process before the changes are
+// saved, the callback is invoked again with the changed configuration. If an
+// error occurs during the editing process, it will be returned.
+func (e *fileEditor) Edit(ctx context.Context, do func(context.Context, *Config) (bool, error)) error {
+	path, err := Path(ctx, e.repoPath)
+	if err != nil {
+		return err
+	}
+
+	lockPath := path + ".lock"
+	lock, err := os.OpenFile(lockPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0666)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("could not lock config file, another git process may be running: %w", err)
+		}
+		return fmt.Errorf("could not lock config file: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			lock.Close()
+			os.Remove(lockPath)
+		}
+	}()
+
+	// the lock keeps out other git processes, but not processes that write
+	// the config file without locking it, ex. 'git config edit', so the
+	// edit is retried if the file changes anyway
+	return editWithRetries(ctx, path, do, func(cfg *Config, snap snapshot) error {
+		if err := commitLocked(lock, path, cfg, snap); err != nil {
+			return err
+		}
+		committed = true
+		return nil
+	})
+}
+
+// Read parses the local git configuration of the specified repository path,
+// without modifying or locking it. Git replaces configuration files
+// atomically, so the result is always a consistent snapshot. Read is much
+// faster than an Editor for read-only access, since no processes need to be
+// spawned beyond locating the configuration file.
+func Read(ctx context.Context, repoPath string) (*Config, error) {
+	path, err := Path(ctx, repoPath)
+	if err != nil {
+		return nil, err
+	}
+	return ReadFile(path)
+}
+
+// ReadFile parses the git configuration file at path, without modifying or
+// locking it. A missing file is treated as an empty configuration.
+func ReadFile(path string) (*Config, error) {
+	cfg := config.New()
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("could not load config file: %w", err)
+	}
+	defer f.Close()
+	if err := config.NewDecoder(f).Decode(cfg); err != nil {
+		return nil, fmt.Errorf("could not load config file: %w", err)
+	}
+	return cfg, nil
+}
+
+// Path returns the absolute path of the local git configuration file of the
+// specified repository path.
+func Path(ctx context.Context, repoPath string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "-C", repoPath, "rev-parse", "--path-format=absolute", "--git-path", "config")
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("could not %q: %w: %s", cmd, err, stderr.String())
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+)
+
+func TestFileEditor(t *testing.T) {
+	const (
+		section       = "biome-test"
+		sectionKey    = "editor"
+		configKey     = section + "." + sectionKey
+		expectedValue = "foobar"
+	)
+	t.Run("save edits", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 7*time.Second)
+		t.Cleanup(cancel)
+		path := testutil.TempRepo(t)
+		assertConfigNotSet(ctx, t, path, configKey)
+		err := NewFileEditor(path).Edit(ctx, func(ctx context.Context, c *Config) (bool, error) {
+			c.Section(section).SetOption(sectionKey, expectedValue)
+			return true, nil
+		})
+		testutil.Check(t, err)
+		if v := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "config", "get", "--local", configKey)); v != expectedValue {
+			t.Errorf("expected config %q to be updated with value %q, was %q", configKey, expectedValue, v)
+		}
+	})
+
+	t.Run("do not save edits", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 7*time.Second)
+		t.Cleanup(cancel)
+		path := testutil.TempRepo(t)
+		err := NewFileEditor(path).Edit(ctx, func(ctx context.Context, c *Config) (bool, error) {
+			c.Section(section).SetOption(sectionKey, expectedValue)
+			return false, nil
+		})
+		testutil.Check(t, err)
+		assertConfigNotSet(ctx, t, path, configKey)
+	})
+
+	t.Run("editing error", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 7*time.Second)
+		t.Cleanup(cancel)
+		path := testutil.TempRepo(t)
+		editorErr := errors.New("pretend something went wrong")
+		err := NewFileEditor(path).Edit(ctx, func(ctx context.Context, c *Config) (bool, error) {
+			c.Section(section).SetOption(sectionKey, expectedValue)
+			return true, editorErr
+		})
+		testutil.ExpectError(t, err)
+		if !errors.Is(err, editorErr) {
+			t.Error("expected error to wrap the editor's error, but did not")
+		}
+		assertConfigNotSet(ctx, t, path, configKey)
+
+		// the lock is released
+		testutil.Check(t, NewFileEditor(path).Edit(ctx, func(ctx context.Context, c *Config) (bool, error) {
+			return false, nil
+		}))
+	})
+
+	t.Run("config is locked", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 7*time.Second)
+		t.Cleanup(cancel)
+		path := testutil.TempRepo(t)
+		lockPath := filepath.Join(path, "config.lock")
+		testutil.Check(t, os.WriteFile(lockPath, nil, 0666))
+		err := NewFileEditor(path).Edit(ctx, func(ctx context.Context, c *Config) (bool, error) {
+			t.Error("unexpected callback while config is locked")
+			return false, nil
+		})
+		testutil.ExpectError(t, err)
+		if _, err := os.Stat(lockPath); err != nil {
+			t.Errorf("expected lock held by another process to remain: %v", err)
+		}
+	})
+
+	t.Run("retry edits of config changed without locking", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 7*time.Second)
+		t.Cleanup(cancel)
+		path := testutil.TempRepo(t)
+		configPath := filepath.Join(path, "config")
+		var attempts int
+		err := NewFileEditor(path).Edit(ctx, func(ctx context.Context, c *Config) (bool, error) {
+			attempts++
+			if attempts == 1 {
+				// another process changes the config without locking it,
+				// as 'git config edit' does
+				f, err := os.OpenFile(configPath, os.O_WRONLY|os.O_APPEND, 0666)
+				testutil.Check(t, err)
+				_, err = f.WriteString("[" + section + "]\n\tconcurrent = true\n")
+				testutil.Check(t, err)
+				testutil.Check(t, f.Close())
+			}
+			c.Section(section).SetOption(sectionKey, expectedValue)
+			return true, nil
+		})
+		testutil.Check(t, err)
+		if attempts != 2 {
+			t.Errorf("expected the edit to be attempted twice, was %d", attempts)
+		}
+		for key, expected := range map[string]string{
+			configKey:               expectedValue,
+			section + ".concurrent": "true",
+		} {
+			if v := strings.TrimSpace(testutil.Execute(t, "git", "-C", path, "config", "get", "--local", key)); v != expected {
+				t.Errorf("expected config %q to have value %q, was %q", key, expected, v)
+			}
+		}
+	})
+}
+
+func TestRead(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 7*time.Second)
+	t.Cleanup(cancel)
+	path := testutil.TempRepo(t)
+	testutil.Execute(t, "git", "-C", path, "config", "set", "--local", "biome-test.reader", "foobar")
+
+	cfg, err := Read(ctx, path)
+	testutil.Check(t, err)
+	if v := cfg.Section("biome-test").Option("reader"); v != "foobar" {
+		t.Errorf("expected config value %q, was %q", "foobar", v)
+	}
+	if v := cfg.Section("core").Option("bare"); v != "true" {
+		t.Errorf("expected config value %q, was %q", "true", v)
+	}
+
+	_, err = Read(ctx, t.TempDir())
+	testutil.ExpectError(t, err)
+}
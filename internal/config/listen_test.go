@@ -0,0 +1,76 @@
+package config
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/orirawlings/gh-biome/internal/config/protobuf"
+	testutil "github.com/orirawlings/gh-biome/internal/util/testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestListen(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 7*time.Second)
+	t.Cleanup(cancel)
+
+	lis, target, err := listen(ctx)
+	testutil.Check(t, err)
+	t.Cleanup(func() {
+		lis.Close()
+	})
+
+	const token = "secret"
+	s := newEditorServer(token)
+	gs := grpc.NewServer()
+	pb.RegisterEditorServer(gs, s)
+	t.Cleanup(gs.Stop)
+	go gs.Serve(lis)
+
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.Check(t, err)
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	client := pb.NewEditorClient(conn)
+
+	// calls without the token are refused
+	for _, md := range []metadata.MD{nil, metadata.Pairs(HelperTokenKey, "guess")} {
+		_, err := client.Edit(metadata.NewOutgoingContext(ctx, md), &pb.EditRequest{Path: "/tmp/other"})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Errorf("expected call with metadata %v to be refused, was %v", md, err)
+		}
+	}
+
+	const expectedPath = "/tmp/config"
+	authenticated := metadata.AppendToOutgoingContext(ctx, HelperTokenKey, token)
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := client.Edit(authenticated, &pb.EditRequest{
+			Path: expectedPath,
+		})
+		errCh <- err
+	}()
+
+	select {
+	case path := <-s.Path():
+		if path != expectedPath {
+			t.Errorf("expected path %q, got %q", expectedPath, path)
+		}
+	case <-ctx.Done():
+		t.Fatalf("timed out waiting for callback on %q", target)
+	}
+
+	// only one edit is served
+	if _, err := client.Edit(authenticated, &pb.EditRequest{Path: "/tmp/other"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected a second call to be refused, was %v", err)
+	}
+	testutil.Check(t, s.Done(ctx, nil))
+	testutil.Check(t, <-errCh)
+}
@@ -0,0 +1,30 @@
+//go:build !windows
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+)
+
+// listen starts listening for callbacks from the editor helper on a unix
+// socket. The returned target is the gRPC target the helper should call.
+func listen(ctx context.Context) (net.Listener, string, error) {
+	f, err := os.CreateTemp("", "")
+	if err != nil {
+		return nil, "", fmt.Errorf("could not create temp file")
+	}
+	if err := f.Close(); err != nil {
+		return nil, "", fmt.Errorf("could not close temp file")
+	}
+	if err := os.Remove(f.Name()); err != nil {
+		return nil, "", fmt.Errorf("could not remove temp file")
+	}
+	lis, err := (&net.ListenConfig{}).Listen(ctx, "unix", f.Name())
+	if err != nil {
+		return nil, "", err
+	}
+	return lis, "unix:" + f.Name(), nil
+}
@@ -0,0 +1,44 @@
+//go:build windows
+
+package config
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+)
+
+// listen starts listening for callbacks from the editor helper on a unix
+// socket, in a new temporary directory of the user's, which other users
+// cannot access. Windows versions before Windows 10 version 1803 have no unix
+// sockets, so listen falls back to a TCP loopback port there, which any local
+// process can connect to, leaving the helper's token to authenticate its
+// call. The returned target is the gRPC target the helper should call.
+func listen(ctx context.Context) (net.Listener, string, error) {
+	if dir, err := os.MkdirTemp("", "biome-editor-*"); err == nil {
+		path := filepath.Join(dir, "editor.sock")
+		lis, err := (&net.ListenConfig{}).Listen(ctx, "unix", path)
+		if err == nil {
+			return dirListener{Listener: lis, dir: dir}, "unix:" + filepath.ToSlash(path), nil
+		}
+		os.RemoveAll(dir)
+	}
+	lis, err := (&net.ListenConfig{}).Listen(ctx, "tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, "", err
+	}
+	return lis, "passthrough:///" + lis.Addr().String(), nil
+}
+
+// dirListener is a unix socket listener that removes the socket's directory
+// when it is closed.
+type dirListener struct {
+	net.Listener
+	dir string
+}
+
+func (l dirListener) Close() error {
+	return errors.Join(l.Listener.Close(), os.RemoveAll(l.dir))
+}
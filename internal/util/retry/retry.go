@@ -0,0 +1,47 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// Policy controls how often, and how soon, a failing operation is attempted
+// again.
+type Policy struct {
+
+	// Attempts is the maximum number of times the operation is attempted,
+	// including the first attempt.
+	Attempts int
+
+	// Delay is how long to wait before the first retry. The delay doubles
+	// before each further retry.
+	Delay time.Duration
+
+	// MaxDelay limits how long to wait between attempts. If zero, the delay
+	// is not limited.
+	MaxDelay time.Duration
+}
+
+// Do calls f until it succeeds, it fails with an error that is not
+// retryable, the attempts are exhausted, or the context is done. The error of
+// the last attempt is returned.
+func (p Policy) Do(ctx context.Context, retryable func(error) bool, f func() error) error {
+	delay := p.Delay
+	for attempt := 1; ; attempt++ {
+		err := f()
+		if err == nil || attempt >= p.Attempts || !retryable(err) {
+			return err
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		delay *= 2
+		if p.MaxDelay > 0 && delay > p.MaxDelay {
+			delay = p.MaxDelay
+		}
+	}
+}
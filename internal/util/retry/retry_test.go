@@ -0,0 +1,77 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var (
+	errTransient = errors.New("transient")
+	errPermanent = errors.New("permanent")
+)
+
+func isTransient(err error) bool {
+	return errors.Is(err, errTransient)
+}
+
+func TestPolicy_Do(t *testing.T) {
+	policy := Policy{
+		Attempts: 3,
+		Delay:    time.Millisecond,
+		MaxDelay: 2 * time.Millisecond,
+	}
+	for name, run := range map[string]struct {
+		errs             []error
+		expected         error
+		expectedAttempts int
+	}{
+		"success": {
+			errs:             []error{nil},
+			expectedAttempts: 1,
+		},
+		"transient, then success": {
+			errs:             []error{errTransient, errTransient, nil},
+			expectedAttempts: 3,
+		},
+		"permanent": {
+			errs:             []error{errPermanent},
+			expected:         errPermanent,
+			expectedAttempts: 1,
+		},
+		"attempts exhausted": {
+			errs:             []error{errTransient, errTransient, errTransient, nil},
+			expected:         errTransient,
+			expectedAttempts: 3,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var attempts int
+			err := policy.Do(context.Background(), isTransient, func() error {
+				err := run.errs[attempts]
+				attempts++
+				return err
+			})
+			if err != run.expected {
+				t.Errorf("unexpected error, wanted: %v, was: %v", run.expected, err)
+			}
+			if attempts != run.expectedAttempts {
+				t.Errorf("unexpected attempts, wanted: %d, was: %d", run.expectedAttempts, attempts)
+			}
+		})
+	}
+}
+
+func TestPolicy_Do_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var attempts int
+	err := Policy{Attempts: 3, Delay: time.Hour}.Do(ctx, isTransient, func() error {
+		attempts++
+		return errTransient
+	})
+	if err != errTransient || attempts != 1 {
+		t.Errorf("expected one attempt when the context is done, was %d attempts: %v", attempts, err)
+	}
+}